@@ -213,6 +213,18 @@ func (d *Document) DirectiveIsAllowedOnNodeKind(directiveName string, kind NodeK
 	return false
 }
 
+// skipIncludeDirectives returns the @skip and @include directives of the directive refs
+func (d *Document) skipIncludeDirectives(directiveRefs []int) []int {
+	var out []int
+	for _, i := range directiveRefs {
+		name := d.DirectiveNameBytes(i)
+		if bytes.Equal(name, literal.SKIP) || bytes.Equal(name, literal.INCLUDE) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
 func (d *Document) ResolveSkipDirectiveVariable(directiveRefs []int) (variableName string, exists bool) {
 	for _, i := range directiveRefs {
 		if !bytes.Equal(d.DirectiveNameBytes(i), literal.SKIP) {
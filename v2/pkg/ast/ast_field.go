@@ -32,7 +32,7 @@ func (d *Document) CopyField(ref int) int {
 	if d.Fields[ref].HasSelections {
 		selectionSet = d.CopySelectionSet(d.Fields[ref].SelectionSet)
 	}
-	return d.AddField(Field{
+	field := d.AddField(Field{
 		Name:          d.copyByteSliceReference(d.Fields[ref].Name),
 		Alias:         d.CopyAlias(d.Fields[ref].Alias),
 		HasArguments:  d.Fields[ref].HasArguments,
@@ -41,7 +41,9 @@ func (d *Document) CopyField(ref int) int {
 		Directives:    directives,
 		HasSelections: d.Fields[ref].HasSelections,
 		SelectionSet:  selectionSet,
-	}).Ref
+	})
+	d.AddReplacedNode(Node{Kind: NodeKindField, Ref: ref}, field)
+	return field.Ref
 }
 
 func (d *Document) FieldNameBytes(ref int) ByteSlice {
@@ -122,11 +124,19 @@ func (d *Document) FieldHasDirectives(ref int) bool {
 }
 
 func (d *Document) FieldsAreEqualFlat(left, right int) bool {
+	return d.FieldsCanMergeFlat(left, right) &&
+		d.DirectiveSetsAreEqual(d.FieldDirectives(left), d.FieldDirectives(right)) // directives
+}
+
+// FieldsCanMergeFlat - returns true when two leaf fields resolve to the same field of the response.
+// Only the @skip and @include directives have to be equal, other directives are applied to both fields,
+// e.g. "id @tag(name: "a")" and "id @tag(name: "b")" can merge
+func (d *Document) FieldsCanMergeFlat(left, right int) bool {
 	return bytes.Equal(d.FieldNameBytes(left), d.FieldNameBytes(right)) && // name
 		bytes.Equal(d.FieldAliasBytes(left), d.FieldAliasBytes(right)) && // alias
 		!d.FieldHasSelections(left) && !d.FieldHasSelections(right) && // selections
 		d.ArgumentSetsAreEquals(d.FieldArguments(left), d.FieldArguments(right)) && // arguments
-		d.DirectiveSetsAreEqual(d.FieldDirectives(left), d.FieldDirectives(right)) // directives
+		d.DirectiveSetsAreEqual(d.skipIncludeDirectives(d.FieldDirectives(left)), d.skipIncludeDirectives(d.FieldDirectives(right))) // skip and include directives
 }
 
 func (d *Document) FieldSelectionSet(ref int) (selectionSetRef int, ok bool) {
@@ -20,11 +20,13 @@ func (d *Document) CopyFragmentSpread(ref int) int {
 	if d.FragmentSpreads[ref].HasDirectives {
 		directives = d.CopyDirectiveList(d.FragmentSpreads[ref].Directives)
 	}
-	return d.AddFragmentSpread(FragmentSpread{
+	fragmentSpread := d.AddFragmentSpread(FragmentSpread{
 		FragmentName:  d.copyByteSliceReference(d.FragmentSpreads[ref].FragmentName),
 		HasDirectives: d.FragmentSpreads[ref].HasDirectives,
 		Directives:    directives,
 	})
+	d.AddReplacedNode(Node{Kind: NodeKindFragmentSpread, Ref: ref}, Node{Kind: NodeKindFragmentSpread, Ref: fragmentSpread})
+	return fragmentSpread
 }
 
 func (d *Document) AddFragmentSpread(spread FragmentSpread) int {
@@ -32,13 +32,15 @@ func (d *Document) CopyInlineFragment(ref int) int {
 	if d.InlineFragments[ref].HasSelections {
 		selectionSet = d.CopySelectionSet(d.InlineFragments[ref].SelectionSet)
 	}
-	return d.AddInlineFragment(InlineFragment{
+	inlineFragment := d.AddInlineFragment(InlineFragment{
 		TypeCondition: d.InlineFragments[ref].TypeCondition, // Value type; doesn't need to be copied.
 		HasDirectives: d.InlineFragments[ref].HasDirectives,
 		Directives:    directives,
 		SelectionSet:  selectionSet,
 		HasSelections: d.InlineFragments[ref].HasSelections,
 	})
+	d.AddReplacedNode(Node{Kind: NodeKindInlineFragment, Ref: ref}, Node{Kind: NodeKindInlineFragment, Ref: inlineFragment})
+	return inlineFragment
 }
 
 func (d *Document) InlineFragmentTypeConditionName(ref int) ByteSlice {
@@ -51,9 +51,11 @@ func (d *Document) CopySelectionSet(ref int) int {
 	for _, r := range d.SelectionSets[ref].SelectionRefs {
 		refs = append(refs, d.CopySelection(r))
 	}
-	return d.AddSelectionSetToDocument(SelectionSet{
+	selectionSet := d.AddSelectionSetToDocument(SelectionSet{
 		SelectionRefs: refs,
 	})
+	d.AddReplacedNode(Node{Kind: NodeKindSelectionSet, Ref: ref}, Node{Kind: NodeKindSelectionSet, Ref: selectionSet})
+	return selectionSet
 }
 
 func (d *Document) PrintSelections(selections []int) (out string) {
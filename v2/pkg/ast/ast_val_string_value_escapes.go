@@ -0,0 +1,240 @@
+package ast
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var blockStringDelimiter = []byte(`"""`)
+
+// StringValueUnescaped returns the value of a string as defined by the GraphQL spec,
+// which means that escape sequences of strings are resolved and block strings are dedented
+func (d *Document) StringValueUnescaped(ref int) ([]byte, error) {
+	if d.StringValues[ref].BlockString {
+		return BlockStringValue(d.blockStringRawContent(d.StringValues[ref].Content)), nil
+	}
+	return UnescapeStringValue(d.StringValueContentBytes(ref))
+}
+
+// blockStringRawContent returns the content between the block string delimiters
+// the lexer trims leading and trailing whitespace of the content, which is significant for the indentation of the block string
+// content that was not parsed from the input, e.g. imported values, is returned as is
+func (d *Document) blockStringRawContent(content ByteSliceReference) []byte {
+	raw := d.Input.RawBytes
+	start, end := int(content.Start), int(content.End)
+	if end > len(raw) || start > end {
+		return d.Input.ByteSlice(content)
+	}
+
+	rawStart := start
+	for rawStart > 0 && isBlockStringWhitespace(raw[rawStart-1]) {
+		rawStart--
+	}
+	rawEnd := end
+	for rawEnd < len(raw) && isBlockStringWhitespace(raw[rawEnd]) {
+		rawEnd++
+	}
+	if !bytes.HasSuffix(raw[:rawStart], blockStringDelimiter) || !bytes.HasPrefix(raw[rawEnd:], blockStringDelimiter) {
+		return raw[start:end]
+	}
+	return raw[rawStart:rawEnd]
+}
+
+func isBlockStringWhitespace(b byte) bool {
+	switch b {
+	case ' ', '\t', '\n', '\r':
+		return true
+	}
+	return false
+}
+
+// ValidateStringValueEscapes returns an error if the raw content of a (non block) string contains
+// an invalid escape sequence, a lone surrogate or invalid UTF-8
+func ValidateStringValueEscapes(raw []byte) error {
+	_, err := unescapeStringValue(raw, nil)
+	return err
+}
+
+// UnescapeStringValue resolves all escape sequences of the raw content of a (non block) string
+// Supported are \" \\ \/ \b \f \n \r \t, \uXXXX including surrogate pairs and \u{X...}
+func UnescapeStringValue(raw []byte) ([]byte, error) {
+	if bytes.IndexByte(raw, '\\') == -1 {
+		if !utf8.Valid(raw) {
+			return nil, errors.New("string contains invalid UTF-8")
+		}
+		return raw, nil
+	}
+	return unescapeStringValue(raw, make([]byte, 0, len(raw)))
+}
+
+func unescapeStringValue(raw []byte, out []byte) ([]byte, error) {
+	if !utf8.Valid(raw) {
+		return nil, errors.New("string contains invalid UTF-8")
+	}
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' {
+			out = append(out, raw[i])
+			continue
+		}
+		if i+1 >= len(raw) {
+			return nil, errors.New("string ends with an incomplete escape sequence")
+		}
+		i++
+		switch raw[i] {
+		case '"', '\\', '/':
+			out = append(out, raw[i])
+		case 'b':
+			out = append(out, '\b')
+		case 'f':
+			out = append(out, '\f')
+		case 'n':
+			out = append(out, '\n')
+		case 'r':
+			out = append(out, '\r')
+		case 't':
+			out = append(out, '\t')
+		case 'u':
+			r, length, err := readEscapedUnicode(raw[i+1:])
+			if err != nil {
+				return nil, err
+			}
+			i += length
+			if utf16.IsSurrogate(r) {
+				if r >= 0xDC00 {
+					return nil, fmt.Errorf("invalid unicode escape sequence: lone trailing surrogate \\u%X", r)
+				}
+				if i+2 >= len(raw) || raw[i+1] != '\\' || raw[i+2] != 'u' {
+					return nil, fmt.Errorf("invalid unicode escape sequence: lone leading surrogate \\u%X", r)
+				}
+				trailing, trailingLength, err := readEscapedUnicode(raw[i+3:])
+				if err != nil {
+					return nil, err
+				}
+				combined := utf16.DecodeRune(r, trailing)
+				if combined == utf8.RuneError {
+					return nil, fmt.Errorf("invalid unicode escape sequence: \\u%X\\u%X is not a surrogate pair", r, trailing)
+				}
+				i += 2 + trailingLength
+				r = combined
+			}
+			out = utf8.AppendRune(out, r)
+		default:
+			return nil, fmt.Errorf("invalid escape sequence: \\%c", raw[i])
+		}
+	}
+	return out, nil
+}
+
+// readEscapedUnicode reads the code point of a unicode escape sequence following the \u
+func readEscapedUnicode(raw []byte) (r rune, length int, err error) {
+	if len(raw) != 0 && raw[0] == '{' {
+		end := bytes.IndexByte(raw, '}')
+		if end < 2 || end > 9 {
+			return 0, 0, errors.New("invalid unicode escape sequence: expected \\u{X...}")
+		}
+		value, err := strconv.ParseUint(string(raw[1:end]), 16, 32)
+		if err != nil || value > utf8.MaxRune || utf16.IsSurrogate(rune(value)) {
+			return 0, 0, fmt.Errorf("invalid unicode escape sequence: \\u%s", raw[:end+1])
+		}
+		return rune(value), end + 1, nil
+	}
+	if len(raw) < 4 {
+		return 0, 0, errors.New("invalid unicode escape sequence: expected \\uXXXX")
+	}
+	value, err := strconv.ParseUint(string(raw[:4]), 16, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid unicode escape sequence: \\u%s", raw[:4])
+	}
+	return rune(value), 4, nil
+}
+
+// BlockStringValue computes the value of the raw content of a block string as defined by the GraphQL spec
+// the common indentation is removed from all lines but the first, leading and trailing blank lines are removed
+// and escaped triple quotes are resolved
+func BlockStringValue(raw []byte) []byte {
+	raw = bytes.ReplaceAll(raw, []byte(`\"""`), blockStringDelimiter)
+	raw = bytes.ReplaceAll(raw, []byte("\r\n"), []byte("\n"))
+	lines := bytes.Split(bytes.ReplaceAll(raw, []byte("\r"), []byte("\n")), []byte("\n"))
+
+	commonIndent := -1
+	for i := 1; i < len(lines); i++ {
+		indent := blockStringLineIndent(lines[i])
+		if indent == len(lines[i]) {
+			continue
+		}
+		if commonIndent == -1 || indent < commonIndent {
+			commonIndent = indent
+		}
+	}
+	if commonIndent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) < commonIndent {
+				lines[i] = lines[i][:0]
+				continue
+			}
+			lines[i] = lines[i][commonIndent:]
+		}
+	}
+
+	for len(lines) != 0 && blockStringLineIndent(lines[0]) == len(lines[0]) {
+		lines = lines[1:]
+	}
+	for len(lines) != 0 && blockStringLineIndent(lines[len(lines)-1]) == len(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	return bytes.Join(lines, []byte("\n"))
+}
+
+func blockStringLineIndent(line []byte) int {
+	for i := range line {
+		if line[i] != ' ' && line[i] != '\t' {
+			return i
+		}
+	}
+	return len(line)
+}
+
+// writeJSONString writes the value as a json string
+func writeJSONString(buf *bytes.Buffer, value []byte) {
+	const hex = "0123456789abcdef"
+	buf.WriteByte('"')
+	for _, b := range value {
+		switch b {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(b)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if b < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[b>>4])
+				buf.WriteByte(hex[b&0xF])
+				continue
+			}
+			buf.WriteByte(b)
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// stringValueIsJSONCompatible returns true if the raw content of a (non block) string is a valid json string content
+func stringValueIsJSONCompatible(raw []byte) bool {
+	for i := range raw {
+		if raw[i] < 0x20 {
+			return false
+		}
+		if raw[i] == '\\' && i+2 < len(raw) && raw[i+1] == 'u' && raw[i+2] == '{' {
+			return false
+		}
+	}
+	return true
+}
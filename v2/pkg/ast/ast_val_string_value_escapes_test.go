@@ -0,0 +1,55 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnescapeStringValue(t *testing.T) {
+	run := func(raw, expected string) func(t *testing.T) {
+		return func(t *testing.T) {
+			out, err := UnescapeStringValue([]byte(raw))
+			require.NoError(t, err)
+			assert.Equal(t, expected, string(out))
+		}
+	}
+	runErr := func(raw, expectedErr string) func(t *testing.T) {
+		return func(t *testing.T) {
+			_, err := UnescapeStringValue([]byte(raw))
+			assert.EqualError(t, err, expectedErr)
+		}
+	}
+
+	t.Run("no escapes", run(`foo bar`, "foo bar"))
+	t.Run("simple escapes", run(`\" \\ \/ \b \f \n \r \t`, "\" \\ / \b \f \n \r \t"))
+	t.Run("unicode", run(`\u00e9\u00E9`, "éé"))
+	t.Run("surrogate pair", run(`\uD83D\uDE00`, "😀"))
+	t.Run("braced unicode", run(`\u{1F600}\u{e9}`, "😀é"))
+	t.Run("invalid escape", runErr(`\x`, `invalid escape sequence: \x`))
+	t.Run("incomplete escape", runErr(`foo\`, `string ends with an incomplete escape sequence`))
+	t.Run("incomplete unicode", runErr(`\u00`, `invalid unicode escape sequence: expected \uXXXX`))
+	t.Run("lone leading surrogate", runErr(`\uD83Dfoo`, `invalid unicode escape sequence: lone leading surrogate \uD83D`))
+	t.Run("lone trailing surrogate", runErr(`\uDE00`, `invalid unicode escape sequence: lone trailing surrogate \uDE00`))
+	t.Run("no surrogate pair", runErr(`\uD83D\u0041`, `invalid unicode escape sequence: \uD83D\u41 is not a surrogate pair`))
+	t.Run("braced surrogate", runErr(`\u{DE00}`, `invalid unicode escape sequence: \u{DE00}`))
+	t.Run("braced out of range", runErr(`\u{110000}`, `invalid unicode escape sequence: \u{110000}`))
+	t.Run("invalid UTF-8", runErr("\xff", `string contains invalid UTF-8`))
+}
+
+func TestBlockStringValue(t *testing.T) {
+	run := func(raw, expected string) func(t *testing.T) {
+		return func(t *testing.T) {
+			assert.Equal(t, expected, string(BlockStringValue([]byte(raw))))
+		}
+	}
+
+	t.Run("single line", run(`foo`, "foo"))
+	t.Run("removes common indentation", run("\n    Hello,\n      World!\n\n    Yours,\n      GraphQL.\n  ", "Hello,\n  World!\n\nYours,\n  GraphQL."))
+	t.Run("keeps indentation of first line", run("  foo\n    bar\n    baz", "  foo\nbar\nbaz"))
+	t.Run("removes leading and trailing blank lines", run("\n  \n\tfoo\n \n", "foo"))
+	t.Run("escaped triple quotes", run(`foo \""" bar`, `foo """ bar`))
+	t.Run("backslashes are not escapes", run(`C:\path\n`, `C:\path\n`))
+	t.Run("windows line endings", run("\r\n  foo\r\n  bar\r\n", "foo\nbar"))
+}
@@ -144,7 +144,16 @@ func (d *Document) writeJSONValue(buf *bytes.Buffer, value Value) error {
 			buf.Write(literal.TRUE)
 		}
 	case ValueKindString:
-		buf.Write(quotes.WrapBytes(d.StringValueContentBytes(value.Ref)))
+		content := d.StringValueContentBytes(value.Ref)
+		if !d.StringValueIsBlockString(value.Ref) && stringValueIsJSONCompatible(content) {
+			buf.Write(quotes.WrapBytes(content))
+			return nil
+		}
+		unescaped, err := d.StringValueUnescaped(value.Ref)
+		if err != nil {
+			return err
+		}
+		writeJSONString(buf, unescaped)
 	case ValueKindList:
 		buf.WriteByte(literal.LBRACK_BYTE)
 		for ii, ref := range d.ListValues[value.Ref].Refs {
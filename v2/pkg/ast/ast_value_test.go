@@ -92,6 +92,26 @@ func TestDocument_ValueToJSON(t *testing.T) {
 			Ref:  0,
 		}
 	}, `"foo"`))
+	t.Run("ValueKindString - braced unicode escape", run(func(doc *Document) Value {
+		doc.StringValues = append(doc.StringValues, StringValue{
+			Content: doc.Input.AppendInputString(`smile \u{1F600}`),
+		})
+		return Value{
+			Kind: ValueKindString,
+			Ref:  0,
+		}
+	}, `"smile 😀"`))
+	t.Run("ValueKindString - block string", run(func(doc *Document) Value {
+		doc.Input.AppendInputString("\"\"\"\n    foo\n      \\\"\"\" \"bar\"\n\"\"\"")
+		doc.StringValues = append(doc.StringValues, StringValue{
+			BlockString: true,
+			Content:     ByteSliceReference{Start: 8, End: 28},
+		})
+		return Value{
+			Kind: ValueKindString,
+			Ref:  0,
+		}
+	}, `"foo\n  \"\"\" \"bar\""`))
 	t.Run("ValueKindList", run(func(doc *Document) Value {
 		doc.StringValues = append(doc.StringValues, StringValue{
 			Content: doc.Input.AppendInputString("foo"),
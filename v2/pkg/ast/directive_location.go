@@ -3,6 +3,7 @@
 package ast
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafebytes"
@@ -93,6 +94,30 @@ func (d *DirectiveLocations) SetFromRaw(bytes []byte) error {
 	return nil
 }
 
+// MarshalJSON encodes the locations as list of their literals, e.g. ["QUERY","FIELD"]
+func (d DirectiveLocations) MarshalJSON() ([]byte, error) {
+	out := make([]string, 0, len(d.storage))
+	iter := d.Iterable()
+	for iter.Next() {
+		out = append(out, iter.Value().LiteralString())
+	}
+	return json.Marshal(out)
+}
+
+func (d *DirectiveLocations) UnmarshalJSON(data []byte) error {
+	var raw []string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*d = DirectiveLocations{}
+	for i := range raw {
+		if err := d.SetFromRaw([]byte(raw[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type DirectiveLocationIterable struct {
 	locations DirectiveLocations
 	current   DirectiveLocation
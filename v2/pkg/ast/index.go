@@ -26,6 +26,11 @@ type Index struct {
 	ReplacedFragmentSpreads []int
 	// MergedTypeExtensions is a list of Nodes (Node kind + reference) that got merged during type extension merging.
 	MergedTypeExtensions []Node
+	// TrackReplacedNodes enables recording which nodes replaced a node when it got copied or merged, e.g. during normalization.
+	// It is not affected by Reset.
+	TrackReplacedNodes bool
+	// replacedNodes maps a Node to the Nodes which replaced it, see NodeReplacements.
+	replacedNodes map[Node][]Node
 }
 
 // Reset empties the Index
@@ -38,6 +43,9 @@ func (i *Index) Reset() {
 	for j := range i.nodes {
 		delete(i.nodes, j)
 	}
+	for j := range i.replacedNodes {
+		delete(i.replacedNodes, j)
+	}
 }
 
 func (i *Index) AddNodeStr(name string, node Node) {
@@ -2,6 +2,7 @@ package ast
 
 import (
 	"bytes"
+	"encoding/json"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafebytes"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/literal"
@@ -99,6 +100,19 @@ func (b ByteSlice) MarshalJSON() ([]byte, error) {
 	return append(append(literal.QUOTE, b...), literal.QUOTE...), nil
 }
 
+func (b *ByteSlice) UnmarshalJSON(data []byte) error {
+	var value *string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value == nil {
+		*b = nil
+		return nil
+	}
+	*b = ByteSlice(*value)
+	return nil
+}
+
 type ByteSlices []ByteSlice
 
 func (b ByteSlices) String() string {
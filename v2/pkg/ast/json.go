@@ -0,0 +1,79 @@
+package ast
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/literal"
+)
+
+// DocumentJSONVersion is the version of the JSON representation of a Document written by MarshalDocumentJSON.
+// It is incremented on every incompatible change of the Document struct.
+const DocumentJSONVersion = 1
+
+// documentJSON is the versioned envelope of the JSON representation of a Document
+type documentJSON struct {
+	Version  int       `json:"version"`
+	Document *Document `json:"document"`
+}
+
+// MarshalDocumentJSON serializes the document into a lossless JSON representation, e.g. to hand parsed documents to tools written in other languages.
+// The representation mirrors the Document struct: nodes reference each other by their index (ref) in the slices of their kind,
+// names and values are ByteSliceReferences into Input.RawBytes, which is encoded as base64.
+func MarshalDocumentJSON(document *Document) ([]byte, error) {
+	return json.Marshal(documentJSON{
+		Version:  DocumentJSONVersion,
+		Document: document,
+	})
+}
+
+// UnmarshalDocumentJSON deserializes a document written by MarshalDocumentJSON into the document and rebuilds the Index of its root nodes.
+func UnmarshalDocumentJSON(data []byte, document *Document) error {
+	var versioned struct {
+		Version  int             `json:"version"`
+		Document json.RawMessage `json:"document"`
+	}
+	if err := json.Unmarshal(data, &versioned); err != nil {
+		return err
+	}
+	if versioned.Version != DocumentJSONVersion {
+		return fmt.Errorf("unsupported document json version %d, expected %d", versioned.Version, DocumentJSONVersion)
+	}
+
+	document.Reset()
+	if err := json.Unmarshal(versioned.Document, document); err != nil {
+		return err
+	}
+
+	document.indexRootNodes()
+	return nil
+}
+
+// indexRootNodes adds the root nodes to the index the same way the parser does
+func (d *Document) indexRootNodes() {
+	if d.Index.nodes == nil {
+		d.Index.nodes = make(map[uint64][]Node, len(d.RootNodes))
+	}
+
+	for _, node := range d.RootNodes {
+		var name ByteSlice
+		switch node.Kind {
+		case NodeKindSchemaDefinition:
+			name = literal.SCHEMA
+		case NodeKindScalarTypeExtension:
+			name = d.Input.ByteSlice(d.ScalarTypeExtensions[node.Ref].Name)
+		case NodeKindInputObjectTypeExtension:
+			name = d.Input.ByteSlice(d.InputObjectTypeExtensions[node.Ref].Name)
+		case NodeKindObjectTypeDefinition, NodeKindObjectTypeExtension,
+			NodeKindInterfaceTypeDefinition, NodeKindInterfaceTypeExtension,
+			NodeKindUnionTypeDefinition, NodeKindUnionTypeExtension,
+			NodeKindEnumTypeDefinition, NodeKindEnumTypeExtension,
+			NodeKindScalarTypeDefinition, NodeKindInputObjectTypeDefinition,
+			NodeKindDirectiveDefinition:
+			name = d.NodeNameBytes(node)
+		default:
+			continue
+		}
+		d.Index.AddNodeBytes(name, node)
+	}
+}
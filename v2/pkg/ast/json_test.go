@@ -0,0 +1,74 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+)
+
+func TestDocumentJSON(t *testing.T) {
+	roundTrip := func(t *testing.T, input string) (original, restored *ast.Document) {
+		t.Helper()
+		doc := unsafeparser.ParseGraphqlDocumentString(input)
+		data, err := ast.MarshalDocumentJSON(&doc)
+		require.NoError(t, err)
+
+		restored = &ast.Document{}
+		require.NoError(t, ast.UnmarshalDocumentJSON(data, restored))
+
+		expected, err := astprinter.PrintString(&doc, nil)
+		require.NoError(t, err)
+		actual, err := astprinter.PrintString(restored, nil)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+		return &doc, restored
+	}
+
+	t.Run("operation", func(t *testing.T) {
+		original, restored := roundTrip(t, `
+			query Q($id: ID! = "1") @cached {
+				user(id: $id, filter: {names: ["a", "b"], age: 3.5, active: true}) {
+					... on Admin { permissions }
+					...UserFields
+				}
+			}
+			fragment UserFields on User { name @include(if: false) }`)
+		assert.Equal(t, original.Fields, restored.Fields)
+		assert.Equal(t, original.Selections, restored.Selections)
+		assert.Equal(t, original.Input.RawBytes, restored.Input.RawBytes)
+	})
+
+	t.Run("schema with index", func(t *testing.T) {
+		_, restored := roundTrip(t, `
+			schema { query: Query }
+			"the query type"
+			type Query { user: User }
+			type User implements Node { id: ID! }
+			interface Node { id: ID! }
+			extend type User { name: String }
+			scalar Date
+			extend scalar Date @specifiedBy(url: "https://example.com")
+			directive @cached on QUERY`)
+
+		for _, name := range []string{"schema", "Query", "Node", "Date", "cached"} {
+			_, ok := restored.Index.FirstNodeByNameStr(name)
+			assert.True(t, ok, name)
+		}
+		nodes, ok := restored.Index.NodesByNameStr("User")
+		require.True(t, ok)
+		assert.Equal(t, []ast.Node{
+			{Kind: ast.NodeKindObjectTypeDefinition, Ref: 1},
+			{Kind: ast.NodeKindObjectTypeExtension, Ref: 0},
+		}, nodes)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		err := ast.UnmarshalDocumentJSON([]byte(`{"version":0,"document":{}}`), &ast.Document{})
+		assert.EqualError(t, err, "unsupported document json version 0, expected 1")
+	})
+}
@@ -0,0 +1,115 @@
+package ast
+
+import "strings"
+
+// AddFieldToSelectionSet adds a field without alias, arguments and selections to the selection set
+// If the selection set already selects a field with this name and no alias, the existing field is returned and added is false
+// If the name is already used as alias of another field, the field can't be added and InvalidRef is returned
+// Use AddSelectionSetToField to select fields of a composite field
+func (d *Document) AddFieldToSelectionSet(set int, fieldName string) (fieldRef int, added bool) {
+	if exists, existingRef := d.SelectionSetHasFieldSelectionWithNameOrAliasString(set, fieldName); exists {
+		if d.FieldAliasOrNameString(existingRef) != fieldName || d.FieldNameString(existingRef) != fieldName {
+			return InvalidRef, false
+		}
+		return existingRef, false
+	}
+
+	field := d.AddField(Field{
+		Name: d.Input.AppendInputString(fieldName),
+	})
+	d.AddSelection(set, Selection{
+		Kind: SelectionKindField,
+		Ref:  field.Ref,
+	})
+	return field.Ref, true
+}
+
+// AddSelectionSetToField adds an empty selection set to a field without selections and returns the selection set ref
+// If the field already has selections, the existing selection set is returned
+func (d *Document) AddSelectionSetToField(fieldRef int) (set int) {
+	if d.Fields[fieldRef].HasSelections {
+		return d.Fields[fieldRef].SelectionSet
+	}
+	set = d.AddSelectionSet().Ref
+	d.Fields[fieldRef].SelectionSet = set
+	d.Fields[fieldRef].HasSelections = true
+	return set
+}
+
+// RemoveFieldByPath removes the field at the path from the selection set and reports if a field was removed
+// Each path element is the response key (alias or name) of a field.
+// Elements with the InlineFragmentPathPrefix, e.g. $User, select the inline fragments with this type condition.
+// Removing the last selection of a field leaves an empty selection set, which the caller has to handle.
+func (d *Document) RemoveFieldByPath(set int, path ...string) (removed bool) {
+	if len(path) == 0 {
+		return false
+	}
+
+	if typeName, ok := strings.CutPrefix(path[0], InlineFragmentPathPrefix); ok {
+		for _, selectionRef := range d.SelectionSets[set].SelectionRefs {
+			if d.Selections[selectionRef].Kind != SelectionKindInlineFragment {
+				continue
+			}
+			inlineFragmentRef := d.Selections[selectionRef].Ref
+			if d.InlineFragmentTypeConditionNameString(inlineFragmentRef) != typeName {
+				continue
+			}
+			nested, ok := d.InlineFragmentSelectionSet(inlineFragmentRef)
+			if !ok {
+				continue
+			}
+			if d.RemoveFieldByPath(nested, path[1:]...) {
+				removed = true
+			}
+		}
+		return removed
+	}
+
+	for i, selectionRef := range d.SelectionSets[set].SelectionRefs {
+		if d.Selections[selectionRef].Kind != SelectionKindField {
+			continue
+		}
+		fieldRef := d.Selections[selectionRef].Ref
+		if d.FieldAliasOrNameString(fieldRef) != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			d.RemoveFromSelectionSet(set, i)
+			return true
+		}
+		nested, ok := d.FieldSelectionSet(fieldRef)
+		if !ok {
+			return false
+		}
+		return d.RemoveFieldByPath(nested, path[1:]...)
+	}
+
+	return false
+}
+
+// WrapSelectionInInlineFragment replaces the selection in the selection set with an inline fragment
+// on the type which contains the selection, e.g. { name } becomes { ... on User { name } }
+// ok is false if the selection is not part of the selection set
+func (d *Document) WrapSelectionInInlineFragment(set, selectionRef int, typeName string) (inlineFragmentRef int, ok bool) {
+	index, ok := indexOf(d.SelectionSets[set].SelectionRefs, selectionRef)
+	if !ok {
+		return InvalidRef, false
+	}
+
+	wrapped := d.AddSelectionSet().Ref
+	d.AddSelectionRefToSelectionSet(wrapped, selectionRef)
+
+	inlineFragmentRef = d.AddInlineFragment(InlineFragment{
+		TypeCondition: TypeCondition{
+			Type: d.AddNamedType([]byte(typeName)),
+		},
+		SelectionSet:  wrapped,
+		HasSelections: true,
+	})
+
+	d.SelectionSets[set].SelectionRefs[index] = d.AddSelectionToDocument(Selection{
+		Kind: SelectionKindInlineFragment,
+		Ref:  inlineFragmentRef,
+	})
+	return inlineFragmentRef, true
+}
@@ -0,0 +1,82 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+)
+
+func TestDocumentMutation(t *testing.T) {
+	run := func(t *testing.T, operation string, mutate func(doc *ast.Document, set int), expected string) {
+		t.Helper()
+		doc := unsafeparser.ParseGraphqlDocumentString(operation)
+		mutate(&doc, doc.OperationDefinitions[0].SelectionSet)
+		out, err := astprinter.PrintString(&doc, nil)
+		require.NoError(t, err)
+		assert.Equal(t, expected, out)
+	}
+
+	t.Run("add field", func(t *testing.T) {
+		run(t, `{ user { id } }`, func(doc *ast.Document, set int) {
+			userRef, added := doc.AddFieldToSelectionSet(set, "user")
+			assert.False(t, added)
+			_, added = doc.AddFieldToSelectionSet(doc.AddSelectionSetToField(userRef), "name")
+			assert.True(t, added)
+			_, added = doc.AddFieldToSelectionSet(doc.AddSelectionSetToField(userRef), "name")
+			assert.False(t, added)
+		}, `{user {id name}}`)
+	})
+
+	t.Run("add field with selections", func(t *testing.T) {
+		run(t, `{ user { id } }`, func(doc *ast.Document, set int) {
+			postsRef, added := doc.AddFieldToSelectionSet(set, "posts")
+			assert.True(t, added)
+			doc.AddFieldToSelectionSet(doc.AddSelectionSetToField(postsRef), "title")
+		}, `{user {id} posts {title}}`)
+	})
+
+	t.Run("add field with name of an alias", func(t *testing.T) {
+		run(t, `{ name: id id: name }`, func(doc *ast.Document, set int) {
+			fieldRef, added := doc.AddFieldToSelectionSet(set, "name")
+			assert.False(t, added)
+			assert.Equal(t, ast.InvalidRef, fieldRef)
+		}, `{name: id id: name}`)
+	})
+
+	t.Run("remove field by path", func(t *testing.T) {
+		run(t, `{ user { id name } me: user { id } }`, func(doc *ast.Document, set int) {
+			assert.True(t, doc.RemoveFieldByPath(set, "user", "name"))
+			assert.True(t, doc.RemoveFieldByPath(set, "me"))
+			assert.False(t, doc.RemoveFieldByPath(set, "user", "unknown"))
+			assert.False(t, doc.RemoveFieldByPath(set, "user", "id", "unknown"))
+		}, `{user {id}}`)
+	})
+
+	t.Run("remove field in inline fragments", func(t *testing.T) {
+		run(t, `{ node { id ... on User { id name } ... on Post { id } } }`, func(doc *ast.Document, set int) {
+			assert.True(t, doc.RemoveFieldByPath(set, "node", "$User", "name"))
+			assert.False(t, doc.RemoveFieldByPath(set, "node", "$Comment", "id"))
+		}, `{node {id ... on User {id} ... on Post {id}}}`)
+	})
+
+	t.Run("wrap selection in inline fragment", func(t *testing.T) {
+		run(t, `{ node { id name } }`, func(doc *ast.Document, set int) {
+			nodeRef := doc.Selections[doc.SelectionSets[set].SelectionRefs[0]].Ref
+			nodeSet, ok := doc.FieldSelectionSet(nodeRef)
+			require.True(t, ok)
+			nameSelection := doc.SelectionSets[nodeSet].SelectionRefs[1]
+
+			inlineFragmentRef, ok := doc.WrapSelectionInInlineFragment(nodeSet, nameSelection, "User")
+			require.True(t, ok)
+			assert.Equal(t, "User", doc.InlineFragmentTypeConditionNameString(inlineFragmentRef))
+
+			_, ok = doc.WrapSelectionInInlineFragment(nodeSet, nameSelection, "User")
+			assert.False(t, ok)
+		}, `{node {id ... on User {name}}}`)
+	})
+}
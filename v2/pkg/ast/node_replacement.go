@@ -0,0 +1,39 @@
+package ast
+
+// AddReplacedNode records that the node got replaced by the replacement, e.g. because it was copied
+// when inlining a fragment or merged into another node. It is a no-op unless Index.TrackReplacedNodes is enabled.
+func (d *Document) AddReplacedNode(node, replacement Node) {
+	if !d.Index.TrackReplacedNodes || node == replacement {
+		return
+	}
+	if d.Index.replacedNodes == nil {
+		d.Index.replacedNodes = make(map[Node][]Node)
+	}
+	d.Index.replacedNodes[node] = append(d.Index.replacedNodes[node], replacement)
+}
+
+// NodeReplacements returns the nodes which replaced the node after transformations of the document, e.g. normalization.
+// Refs of nodes stay valid during transformations, but a node might get detached from the tree,
+// e.g. when a fragment definition got inlined or two fields got merged.
+// Replacements are followed transitively, e.g. a field of a fragment which got inlined twice returns both copies.
+// If the node was not replaced, the node itself is returned.
+// Replacements are only recorded while Index.TrackReplacedNodes is enabled.
+func (d *Document) NodeReplacements(node Node) []Node {
+	return d.appendNodeReplacements(nil, node, map[Node]struct{}{})
+}
+
+func (d *Document) appendNodeReplacements(out []Node, node Node, seen map[Node]struct{}) []Node {
+	if _, ok := seen[node]; ok {
+		return out
+	}
+	seen[node] = struct{}{}
+
+	replacements, ok := d.Index.replacedNodes[node]
+	if !ok {
+		return append(out, node)
+	}
+	for _, replacement := range replacements {
+		out = d.appendNodeReplacements(out, replacement, seen)
+	}
+	return out
+}
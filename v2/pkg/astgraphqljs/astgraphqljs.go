@@ -0,0 +1,771 @@
+// Package astgraphqljs converts a GraphQL document into the AST shape of graphql-js,
+// so that tools of the JavaScript ecosystem, e.g. eslint plugins or code generators, can consume documents parsed by this library.
+//
+// Nodes are plain JSON objects with a "kind" field, e.g. {"kind":"Field","name":{"kind":"Name","value":"id"}}.
+// Optional children which are not present in the document are omitted like in graphql-js.
+// The "loc" field contains the start and end offset of the node in Input.RawBytes of the document.
+// It is set for names, selection sets, selections, operation and fragment definitions, if their source position is known.
+package astgraphqljs
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/position"
+)
+
+// Node is a graphql-js AST node
+type Node map[string]interface{}
+
+// Location is the source location of a node, start and end are byte offsets
+type Location struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Convert converts the document into a graphql-js Document node
+func Convert(document *ast.Document) (Node, error) {
+	c := &converter{
+		document:    document,
+		lineOffsets: lineOffsets(document.Input.RawBytes),
+	}
+	return c.convertDocument()
+}
+
+// Marshal converts the document into a graphql-js Document node and encodes it as JSON
+func Marshal(document *ast.Document) ([]byte, error) {
+	node, err := Convert(document)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(node)
+}
+
+type converter struct {
+	document    *ast.Document
+	lineOffsets []int
+}
+
+// lineOffsets returns the offsets of the start of each line, lines are 1-based in positions
+func lineOffsets(input []byte) []int {
+	offsets := []int{0}
+	for i := range input {
+		if input[i] == '\n' {
+			offsets = append(offsets, i+1)
+		}
+	}
+	return offsets
+}
+
+func (c *converter) offset(line, char uint32) (int, bool) {
+	if line == 0 || char == 0 || int(line) > len(c.lineOffsets) {
+		return 0, false
+	}
+	return c.lineOffsets[line-1] + int(char) - 1, true
+}
+
+// setLoc sets the location of the node from the start of the start position to the end of the end position
+func (c *converter) setLoc(node Node, start, end position.Position) {
+	startOffset, ok := c.offset(start.LineStart, start.CharStart)
+	if !ok {
+		return
+	}
+	endOffset, ok := c.offset(end.LineEnd, end.CharEnd)
+	if !ok || endOffset < startOffset {
+		return
+	}
+	node["loc"] = Location{Start: startOffset, End: endOffset}
+}
+
+func (c *converter) name(ref ast.ByteSliceReference) Node {
+	node := Node{
+		"kind":  "Name",
+		"value": c.document.Input.ByteSliceString(ref),
+	}
+	if ref.Length() != 0 && int(ref.End) <= len(c.document.Input.RawBytes) {
+		node["loc"] = Location{Start: int(ref.Start), End: int(ref.End)}
+	}
+	return node
+}
+
+func (c *converter) convertDocument() (Node, error) {
+	definitions := make([]Node, 0, len(c.document.RootNodes))
+	for _, root := range c.document.RootNodes {
+		definition, err := c.definition(root)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, definition)
+	}
+	return Node{
+		"kind":        "Document",
+		"definitions": definitions,
+	}, nil
+}
+
+func (c *converter) definition(node ast.Node) (Node, error) {
+	switch node.Kind {
+	case ast.NodeKindOperationDefinition:
+		return c.operationDefinition(node.Ref)
+	case ast.NodeKindFragmentDefinition:
+		return c.fragmentDefinition(node.Ref)
+	case ast.NodeKindSchemaDefinition:
+		return c.schemaDefinition("SchemaDefinition", c.document.SchemaDefinitions[node.Ref])
+	case ast.NodeKindSchemaExtension:
+		return c.schemaDefinition("SchemaExtension", c.document.SchemaExtensions[node.Ref].SchemaDefinition)
+	case ast.NodeKindScalarTypeDefinition:
+		return c.scalarTypeDefinition("ScalarTypeDefinition", c.document.ScalarTypeDefinitions[node.Ref])
+	case ast.NodeKindScalarTypeExtension:
+		return c.scalarTypeDefinition("ScalarTypeExtension", c.document.ScalarTypeExtensions[node.Ref].ScalarTypeDefinition)
+	case ast.NodeKindObjectTypeDefinition:
+		return c.objectTypeDefinition("ObjectTypeDefinition", c.document.ObjectTypeDefinitions[node.Ref])
+	case ast.NodeKindObjectTypeExtension:
+		return c.objectTypeDefinition("ObjectTypeExtension", c.document.ObjectTypeExtensions[node.Ref].ObjectTypeDefinition)
+	case ast.NodeKindInterfaceTypeDefinition:
+		return c.interfaceTypeDefinition("InterfaceTypeDefinition", c.document.InterfaceTypeDefinitions[node.Ref])
+	case ast.NodeKindInterfaceTypeExtension:
+		return c.interfaceTypeDefinition("InterfaceTypeExtension", c.document.InterfaceTypeExtensions[node.Ref].InterfaceTypeDefinition)
+	case ast.NodeKindUnionTypeDefinition:
+		return c.unionTypeDefinition("UnionTypeDefinition", c.document.UnionTypeDefinitions[node.Ref])
+	case ast.NodeKindUnionTypeExtension:
+		return c.unionTypeDefinition("UnionTypeExtension", c.document.UnionTypeExtensions[node.Ref].UnionTypeDefinition)
+	case ast.NodeKindEnumTypeDefinition:
+		return c.enumTypeDefinition("EnumTypeDefinition", c.document.EnumTypeDefinitions[node.Ref])
+	case ast.NodeKindEnumTypeExtension:
+		return c.enumTypeDefinition("EnumTypeExtension", c.document.EnumTypeExtensions[node.Ref].EnumTypeDefinition)
+	case ast.NodeKindInputObjectTypeDefinition:
+		return c.inputObjectTypeDefinition("InputObjectTypeDefinition", c.document.InputObjectTypeDefinitions[node.Ref])
+	case ast.NodeKindInputObjectTypeExtension:
+		return c.inputObjectTypeDefinition("InputObjectTypeExtension", c.document.InputObjectTypeExtensions[node.Ref].InputObjectTypeDefinition)
+	case ast.NodeKindDirectiveDefinition:
+		return c.directiveDefinition(node.Ref)
+	default:
+		return nil, fmt.Errorf("unsupported root node kind: %s", node.Kind)
+	}
+}
+
+func operationTypeName(operationType ast.OperationType) (string, error) {
+	switch operationType {
+	case ast.OperationTypeQuery:
+		return "query", nil
+	case ast.OperationTypeMutation:
+		return "mutation", nil
+	case ast.OperationTypeSubscription:
+		return "subscription", nil
+	default:
+		return "", fmt.Errorf("unsupported operation type: %s", operationType)
+	}
+}
+
+func (c *converter) operationDefinition(ref int) (Node, error) {
+	operation := c.document.OperationDefinitions[ref]
+	operationType, err := operationTypeName(operation.OperationType)
+	if err != nil {
+		return nil, err
+	}
+
+	variableDefinitions := make([]Node, 0, len(operation.VariableDefinitions.Refs))
+	for _, variableDefinition := range operation.VariableDefinitions.Refs {
+		converted, err := c.variableDefinition(variableDefinition)
+		if err != nil {
+			return nil, err
+		}
+		variableDefinitions = append(variableDefinitions, converted)
+	}
+	directives, err := c.directives(operation.Directives.Refs)
+	if err != nil {
+		return nil, err
+	}
+	selectionSet, err := c.selectionSet(operation.SelectionSet)
+	if err != nil {
+		return nil, err
+	}
+
+	node := Node{
+		"kind":                "OperationDefinition",
+		"operation":           operationType,
+		"variableDefinitions": variableDefinitions,
+		"directives":          directives,
+		"selectionSet":        selectionSet,
+	}
+	if operation.Name.Length() != 0 {
+		node["name"] = c.name(operation.Name)
+	}
+	start := operation.OperationTypeLiteral
+	if start.LineStart == 0 {
+		start = c.document.SelectionSets[operation.SelectionSet].LBrace
+	}
+	c.setLoc(node, start, c.document.SelectionSets[operation.SelectionSet].RBrace)
+	return node, nil
+}
+
+func (c *converter) variableDefinition(ref int) (Node, error) {
+	variableDefinition := c.document.VariableDefinitions[ref]
+	variable, err := c.value(variableDefinition.VariableValue)
+	if err != nil {
+		return nil, err
+	}
+	typeNode, err := c.typeNode(variableDefinition.Type)
+	if err != nil {
+		return nil, err
+	}
+	directives, err := c.directives(variableDefinition.Directives.Refs)
+	if err != nil {
+		return nil, err
+	}
+
+	node := Node{
+		"kind":       "VariableDefinition",
+		"variable":   variable,
+		"type":       typeNode,
+		"directives": directives,
+	}
+	if variableDefinition.DefaultValue.IsDefined {
+		if node["defaultValue"], err = c.value(variableDefinition.DefaultValue.Value); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+func (c *converter) fragmentDefinition(ref int) (Node, error) {
+	fragment := c.document.FragmentDefinitions[ref]
+	typeCondition, err := c.typeNode(fragment.TypeCondition.Type)
+	if err != nil {
+		return nil, err
+	}
+	directives, err := c.directives(fragment.Directives.Refs)
+	if err != nil {
+		return nil, err
+	}
+	selectionSet, err := c.selectionSet(fragment.SelectionSet)
+	if err != nil {
+		return nil, err
+	}
+
+	node := Node{
+		"kind":          "FragmentDefinition",
+		"name":          c.name(fragment.Name),
+		"typeCondition": typeCondition,
+		"directives":    directives,
+		"selectionSet":  selectionSet,
+	}
+	c.setLoc(node, fragment.FragmentLiteral, c.document.SelectionSets[fragment.SelectionSet].RBrace)
+	return node, nil
+}
+
+func (c *converter) selectionSet(ref int) (Node, error) {
+	selectionSet := c.document.SelectionSets[ref]
+	selections := make([]Node, 0, len(selectionSet.SelectionRefs))
+	for _, selectionRef := range selectionSet.SelectionRefs {
+		selection, err := c.selection(c.document.Selections[selectionRef])
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+	}
+
+	node := Node{
+		"kind":       "SelectionSet",
+		"selections": selections,
+	}
+	c.setLoc(node, selectionSet.LBrace, selectionSet.RBrace)
+	return node, nil
+}
+
+func (c *converter) selection(selection ast.Selection) (Node, error) {
+	switch selection.Kind {
+	case ast.SelectionKindField:
+		return c.field(selection.Ref)
+	case ast.SelectionKindFragmentSpread:
+		return c.fragmentSpread(selection.Ref)
+	case ast.SelectionKindInlineFragment:
+		return c.inlineFragment(selection.Ref)
+	default:
+		return nil, fmt.Errorf("unsupported selection kind: %s", selection.Kind)
+	}
+}
+
+func (c *converter) field(ref int) (Node, error) {
+	field := c.document.Fields[ref]
+	arguments, err := c.arguments(field.Arguments.Refs)
+	if err != nil {
+		return nil, err
+	}
+	directives, err := c.directives(field.Directives.Refs)
+	if err != nil {
+		return nil, err
+	}
+
+	node := Node{
+		"kind":       "Field",
+		"name":       c.name(field.Name),
+		"arguments":  arguments,
+		"directives": directives,
+	}
+	if field.Alias.IsDefined {
+		node["alias"] = c.name(field.Alias.Name)
+	}
+	if field.HasSelections {
+		if node["selectionSet"], err = c.selectionSet(field.SelectionSet); err != nil {
+			return nil, err
+		}
+		c.setLoc(node, field.Position, c.document.SelectionSets[field.SelectionSet].RBrace)
+	} else if field.HasArguments {
+		c.setLoc(node, field.Position, field.Arguments.RPAREN)
+	} else if !field.Alias.IsDefined {
+		c.setLoc(node, field.Position, field.Position)
+	}
+	return node, nil
+}
+
+func (c *converter) fragmentSpread(ref int) (Node, error) {
+	spread := c.document.FragmentSpreads[ref]
+	directives, err := c.directives(spread.Directives.Refs)
+	if err != nil {
+		return nil, err
+	}
+
+	node := Node{
+		"kind":       "FragmentSpread",
+		"name":       c.name(spread.FragmentName),
+		"directives": directives,
+	}
+	if start, ok := c.offset(spread.Spread.LineStart, spread.Spread.CharStart); ok && int(spread.FragmentName.End) >= start {
+		node["loc"] = Location{Start: start, End: int(spread.FragmentName.End)}
+	}
+	return node, nil
+}
+
+func (c *converter) inlineFragment(ref int) (Node, error) {
+	fragment := c.document.InlineFragments[ref]
+	directives, err := c.directives(fragment.Directives.Refs)
+	if err != nil {
+		return nil, err
+	}
+	selectionSet, err := c.selectionSet(fragment.SelectionSet)
+	if err != nil {
+		return nil, err
+	}
+
+	node := Node{
+		"kind":         "InlineFragment",
+		"directives":   directives,
+		"selectionSet": selectionSet,
+	}
+	if fragment.TypeCondition.Type != ast.InvalidRef {
+		if node["typeCondition"], err = c.typeNode(fragment.TypeCondition.Type); err != nil {
+			return nil, err
+		}
+	}
+	c.setLoc(node, fragment.Spread, c.document.SelectionSets[fragment.SelectionSet].RBrace)
+	return node, nil
+}
+
+func (c *converter) arguments(refs []int) ([]Node, error) {
+	arguments := make([]Node, 0, len(refs))
+	for _, ref := range refs {
+		value, err := c.value(c.document.Arguments[ref].Value)
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, Node{
+			"kind":  "Argument",
+			"name":  c.name(c.document.Arguments[ref].Name),
+			"value": value,
+		})
+	}
+	return arguments, nil
+}
+
+func (c *converter) directives(refs []int) ([]Node, error) {
+	directives := make([]Node, 0, len(refs))
+	for _, ref := range refs {
+		arguments, err := c.arguments(c.document.Directives[ref].Arguments.Refs)
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, Node{
+			"kind":      "Directive",
+			"name":      c.name(c.document.Directives[ref].Name),
+			"arguments": arguments,
+		})
+	}
+	return directives, nil
+}
+
+func (c *converter) value(value ast.Value) (Node, error) {
+	switch value.Kind {
+	case ast.ValueKindString:
+		content, err := c.document.StringValueUnescaped(value.Ref)
+		if err != nil {
+			return nil, err
+		}
+		return Node{
+			"kind":  "StringValue",
+			"value": string(content),
+			"block": c.document.StringValueIsBlockString(value.Ref),
+		}, nil
+	case ast.ValueKindBoolean:
+		return Node{
+			"kind":  "BooleanValue",
+			"value": bool(c.document.BooleanValue(value.Ref)),
+		}, nil
+	case ast.ValueKindInteger:
+		raw := string(c.document.IntValueRaw(value.Ref))
+		if c.document.IntValueIsNegative(value.Ref) {
+			raw = "-" + raw
+		}
+		return Node{
+			"kind":  "IntValue",
+			"value": raw,
+		}, nil
+	case ast.ValueKindFloat:
+		raw := string(c.document.FloatValueRaw(value.Ref))
+		if c.document.FloatValueIsNegative(value.Ref) {
+			raw = "-" + raw
+		}
+		return Node{
+			"kind":  "FloatValue",
+			"value": raw,
+		}, nil
+	case ast.ValueKindVariable:
+		return Node{
+			"kind": "Variable",
+			"name": c.name(c.document.VariableValues[value.Ref].Name),
+		}, nil
+	case ast.ValueKindNull:
+		return Node{
+			"kind": "NullValue",
+		}, nil
+	case ast.ValueKindEnum:
+		return Node{
+			"kind":  "EnumValue",
+			"value": c.document.EnumValueNameString(value.Ref),
+		}, nil
+	case ast.ValueKindList:
+		values := make([]Node, 0, len(c.document.ListValues[value.Ref].Refs))
+		for _, ref := range c.document.ListValues[value.Ref].Refs {
+			converted, err := c.value(c.document.Values[ref])
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, converted)
+		}
+		return Node{
+			"kind":   "ListValue",
+			"values": values,
+		}, nil
+	case ast.ValueKindObject:
+		fields := make([]Node, 0, len(c.document.ObjectValues[value.Ref].Refs))
+		for _, ref := range c.document.ObjectValues[value.Ref].Refs {
+			converted, err := c.value(c.document.ObjectFields[ref].Value)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, Node{
+				"kind":  "ObjectField",
+				"name":  c.name(c.document.ObjectFields[ref].Name),
+				"value": converted,
+			})
+		}
+		return Node{
+			"kind":   "ObjectValue",
+			"fields": fields,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported value kind: %s", value.Kind)
+	}
+}
+
+func (c *converter) typeNode(ref int) (Node, error) {
+	typeNode := c.document.Types[ref]
+	switch typeNode.TypeKind {
+	case ast.TypeKindNamed:
+		return Node{
+			"kind": "NamedType",
+			"name": c.name(typeNode.Name),
+		}, nil
+	case ast.TypeKindList:
+		ofType, err := c.typeNode(typeNode.OfType)
+		if err != nil {
+			return nil, err
+		}
+		return Node{
+			"kind": "ListType",
+			"type": ofType,
+		}, nil
+	case ast.TypeKindNonNull:
+		ofType, err := c.typeNode(typeNode.OfType)
+		if err != nil {
+			return nil, err
+		}
+		return Node{
+			"kind": "NonNullType",
+			"type": ofType,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported type kind: %s", typeNode.TypeKind)
+	}
+}
+
+func (c *converter) namedTypes(refs []int) ([]Node, error) {
+	types := make([]Node, 0, len(refs))
+	for _, ref := range refs {
+		converted, err := c.typeNode(ref)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, converted)
+	}
+	return types, nil
+}
+
+// setDescription sets the description of a type system definition if it is defined
+func (c *converter) setDescription(node Node, description ast.Description) error {
+	if !description.IsDefined {
+		return nil
+	}
+	raw := c.document.Input.ByteSlice(description.Content)
+	value := ast.BlockStringValue(raw)
+	if !description.IsBlockString {
+		var err error
+		if value, err = ast.UnescapeStringValue(raw); err != nil {
+			return err
+		}
+	}
+	node["description"] = Node{
+		"kind":  "StringValue",
+		"value": string(value),
+		"block": description.IsBlockString,
+	}
+	return nil
+}
+
+func (c *converter) schemaDefinition(kind string, schema ast.SchemaDefinition) (Node, error) {
+	directives, err := c.directives(schema.Directives.Refs)
+	if err != nil {
+		return nil, err
+	}
+	operationTypes := make([]Node, 0, len(schema.RootOperationTypeDefinitions.Refs))
+	for _, ref := range schema.RootOperationTypeDefinitions.Refs {
+		rootOperationType := c.document.RootOperationTypeDefinitions[ref]
+		operationType, err := operationTypeName(rootOperationType.OperationType)
+		if err != nil {
+			return nil, err
+		}
+		operationTypes = append(operationTypes, Node{
+			"kind":      "OperationTypeDefinition",
+			"operation": operationType,
+			"type": Node{
+				"kind": "NamedType",
+				"name": c.name(rootOperationType.NamedType.Name),
+			},
+		})
+	}
+
+	node := Node{
+		"kind":           kind,
+		"directives":     directives,
+		"operationTypes": operationTypes,
+	}
+	return node, c.setDescription(node, schema.Description)
+}
+
+func (c *converter) scalarTypeDefinition(kind string, scalar ast.ScalarTypeDefinition) (Node, error) {
+	directives, err := c.directives(scalar.Directives.Refs)
+	if err != nil {
+		return nil, err
+	}
+	node := Node{
+		"kind":       kind,
+		"name":       c.name(scalar.Name),
+		"directives": directives,
+	}
+	return node, c.setDescription(node, scalar.Description)
+}
+
+func (c *converter) objectTypeDefinition(kind string, object ast.ObjectTypeDefinition) (Node, error) {
+	return c.fieldsTypeDefinition(kind, object.Name, object.Description, object.ImplementsInterfaces.Refs, object.Directives.Refs, object.FieldsDefinition.Refs)
+}
+
+func (c *converter) interfaceTypeDefinition(kind string, iface ast.InterfaceTypeDefinition) (Node, error) {
+	return c.fieldsTypeDefinition(kind, iface.Name, iface.Description, iface.ImplementsInterfaces.Refs, iface.Directives.Refs, iface.FieldsDefinition.Refs)
+}
+
+func (c *converter) fieldsTypeDefinition(kind string, name ast.ByteSliceReference, description ast.Description, interfaceRefs, directiveRefs, fieldRefs []int) (Node, error) {
+	interfaces, err := c.namedTypes(interfaceRefs)
+	if err != nil {
+		return nil, err
+	}
+	directives, err := c.directives(directiveRefs)
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]Node, 0, len(fieldRefs))
+	for _, ref := range fieldRefs {
+		field, err := c.fieldDefinition(ref)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+
+	node := Node{
+		"kind":       kind,
+		"name":       c.name(name),
+		"interfaces": interfaces,
+		"directives": directives,
+		"fields":     fields,
+	}
+	return node, c.setDescription(node, description)
+}
+
+func (c *converter) fieldDefinition(ref int) (Node, error) {
+	field := c.document.FieldDefinitions[ref]
+	arguments, err := c.inputValueDefinitions(field.ArgumentsDefinition.Refs)
+	if err != nil {
+		return nil, err
+	}
+	typeNode, err := c.typeNode(field.Type)
+	if err != nil {
+		return nil, err
+	}
+	directives, err := c.directives(field.Directives.Refs)
+	if err != nil {
+		return nil, err
+	}
+
+	node := Node{
+		"kind":       "FieldDefinition",
+		"name":       c.name(field.Name),
+		"arguments":  arguments,
+		"type":       typeNode,
+		"directives": directives,
+	}
+	return node, c.setDescription(node, field.Description)
+}
+
+func (c *converter) inputValueDefinitions(refs []int) ([]Node, error) {
+	inputValues := make([]Node, 0, len(refs))
+	for _, ref := range refs {
+		inputValue := c.document.InputValueDefinitions[ref]
+		typeNode, err := c.typeNode(inputValue.Type)
+		if err != nil {
+			return nil, err
+		}
+		directives, err := c.directives(inputValue.Directives.Refs)
+		if err != nil {
+			return nil, err
+		}
+
+		node := Node{
+			"kind":       "InputValueDefinition",
+			"name":       c.name(inputValue.Name),
+			"type":       typeNode,
+			"directives": directives,
+		}
+		if inputValue.DefaultValue.IsDefined {
+			if node["defaultValue"], err = c.value(inputValue.DefaultValue.Value); err != nil {
+				return nil, err
+			}
+		}
+		if err := c.setDescription(node, inputValue.Description); err != nil {
+			return nil, err
+		}
+		inputValues = append(inputValues, node)
+	}
+	return inputValues, nil
+}
+
+func (c *converter) unionTypeDefinition(kind string, union ast.UnionTypeDefinition) (Node, error) {
+	directives, err := c.directives(union.Directives.Refs)
+	if err != nil {
+		return nil, err
+	}
+	types, err := c.namedTypes(union.UnionMemberTypes.Refs)
+	if err != nil {
+		return nil, err
+	}
+	node := Node{
+		"kind":       kind,
+		"name":       c.name(union.Name),
+		"directives": directives,
+		"types":      types,
+	}
+	return node, c.setDescription(node, union.Description)
+}
+
+func (c *converter) enumTypeDefinition(kind string, enum ast.EnumTypeDefinition) (Node, error) {
+	directives, err := c.directives(enum.Directives.Refs)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]Node, 0, len(enum.EnumValuesDefinition.Refs))
+	for _, ref := range enum.EnumValuesDefinition.Refs {
+		enumValue := c.document.EnumValueDefinitions[ref]
+		valueDirectives, err := c.directives(enumValue.Directives.Refs)
+		if err != nil {
+			return nil, err
+		}
+		value := Node{
+			"kind":       "EnumValueDefinition",
+			"name":       c.name(enumValue.EnumValue),
+			"directives": valueDirectives,
+		}
+		if err := c.setDescription(value, enumValue.Description); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	node := Node{
+		"kind":       kind,
+		"name":       c.name(enum.Name),
+		"directives": directives,
+		"values":     values,
+	}
+	return node, c.setDescription(node, enum.Description)
+}
+
+func (c *converter) inputObjectTypeDefinition(kind string, input ast.InputObjectTypeDefinition) (Node, error) {
+	directives, err := c.directives(input.Directives.Refs)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := c.inputValueDefinitions(input.InputFieldsDefinition.Refs)
+	if err != nil {
+		return nil, err
+	}
+	node := Node{
+		"kind":       kind,
+		"name":       c.name(input.Name),
+		"directives": directives,
+		"fields":     fields,
+	}
+	return node, c.setDescription(node, input.Description)
+}
+
+func (c *converter) directiveDefinition(ref int) (Node, error) {
+	directive := c.document.DirectiveDefinitions[ref]
+	arguments, err := c.inputValueDefinitions(directive.ArgumentsDefinition.Refs)
+	if err != nil {
+		return nil, err
+	}
+	locations := make([]Node, 0, 4)
+	iter := directive.DirectiveLocations.Iterable()
+	for iter.Next() {
+		locations = append(locations, Node{
+			"kind":  "Name",
+			"value": iter.Value().LiteralString(),
+		})
+	}
+	node := Node{
+		"kind":       "DirectiveDefinition",
+		"name":       c.name(directive.Name),
+		"arguments":  arguments,
+		"repeatable": directive.Repeatable.IsRepeatable,
+		"locations":  locations,
+	}
+	return node, c.setDescription(node, directive.Description)
+}
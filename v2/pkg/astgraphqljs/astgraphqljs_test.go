@@ -0,0 +1,133 @@
+package astgraphqljs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+)
+
+func TestMarshal(t *testing.T) {
+	run := func(t *testing.T, input, expected string) {
+		t.Helper()
+		doc := unsafeparser.ParseGraphqlDocumentString(input)
+		out, err := Marshal(&doc)
+		require.NoError(t, err)
+		assert.JSONEq(t, expected, string(out))
+	}
+
+	t.Run("operation", func(t *testing.T) {
+		run(t, `query Q($id: ID! = -1) { a: user(id: $id) @include(if: true) { ...F ... on User { id } } }`, `{
+			"kind": "Document",
+			"definitions": [{
+				"kind": "OperationDefinition",
+				"operation": "query",
+				"loc": {"start": 0, "end": 90},
+				"name": {"kind": "Name", "value": "Q", "loc": {"start": 6, "end": 7}},
+				"variableDefinitions": [{
+					"kind": "VariableDefinition",
+					"variable": {"kind": "Variable", "name": {"kind": "Name", "value": "id", "loc": {"start": 9, "end": 11}}},
+					"type": {"kind": "NonNullType", "type": {"kind": "NamedType", "name": {"kind": "Name", "value": "ID", "loc": {"start": 13, "end": 15}}}},
+					"defaultValue": {"kind": "IntValue", "value": "-1"},
+					"directives": []
+				}],
+				"directives": [],
+				"selectionSet": {
+					"kind": "SelectionSet",
+					"loc": {"start": 23, "end": 90},
+					"selections": [{
+						"kind": "Field",
+						"loc": {"start": 25, "end": 88},
+						"alias": {"kind": "Name", "value": "a", "loc": {"start": 25, "end": 26}},
+						"name": {"kind": "Name", "value": "user", "loc": {"start": 28, "end": 32}},
+						"arguments": [{
+							"kind": "Argument",
+							"name": {"kind": "Name", "value": "id", "loc": {"start": 33, "end": 35}},
+							"value": {"kind": "Variable", "name": {"kind": "Name", "value": "id", "loc": {"start": 38, "end": 40}}}
+						}],
+						"directives": [{
+							"kind": "Directive",
+							"name": {"kind": "Name", "value": "include", "loc": {"start": 43, "end": 50}},
+							"arguments": [{
+								"kind": "Argument",
+								"name": {"kind": "Name", "value": "if", "loc": {"start": 51, "end": 53}},
+								"value": {"kind": "BooleanValue", "value": true}
+							}]
+						}],
+						"selectionSet": {
+							"kind": "SelectionSet",
+							"loc": {"start": 61, "end": 88},
+							"selections": [{
+								"kind": "FragmentSpread",
+								"loc": {"start": 63, "end": 67},
+								"name": {"kind": "Name", "value": "F", "loc": {"start": 66, "end": 67}},
+								"directives": []
+							}, {
+								"kind": "InlineFragment",
+								"loc": {"start": 68, "end": 86},
+								"typeCondition": {"kind": "NamedType", "name": {"kind": "Name", "value": "User", "loc": {"start": 75, "end": 79}}},
+								"directives": [],
+								"selectionSet": {
+									"kind": "SelectionSet",
+									"loc": {"start": 80, "end": 86},
+									"selections": [{
+										"kind": "Field",
+										"loc": {"start": 82, "end": 84},
+										"name": {"kind": "Name", "value": "id", "loc": {"start": 82, "end": 84}},
+										"arguments": [],
+										"directives": []
+									}]
+								}
+							}]
+						}
+					}]
+				}
+			}]
+		}`)
+	})
+	t.Run("schema", func(t *testing.T) {
+		doc := unsafeparser.ParseGraphqlDocumentString(`
+			"""
+			  The query type
+			"""
+			type Query implements Node { search(term: String = "a\\nb", first: Int = 10): [Result!]! @deprecated }
+			union Result = User | Post
+			enum Role { "admin role" ADMIN USER }
+			input Filter { role: Role = ADMIN }
+			extend scalar Date @specifiedBy(url: "https://example.com")
+			directive @cached(ttl: Int) repeatable on FIELD | QUERY
+			schema { query: Query }`)
+		document, err := Convert(&doc)
+		require.NoError(t, err)
+
+		definitions := document["definitions"].([]Node)
+		kinds := make([]string, 0, len(definitions))
+		for _, definition := range definitions {
+			kinds = append(kinds, definition["kind"].(string))
+		}
+		assert.Equal(t, []string{"ObjectTypeDefinition", "UnionTypeDefinition", "EnumTypeDefinition", "InputObjectTypeDefinition", "ScalarTypeExtension", "DirectiveDefinition", "SchemaDefinition"}, kinds)
+
+		query := definitions[0]
+		assert.Equal(t, Node{"kind": "StringValue", "value": "The query type", "block": true}, query["description"])
+		assert.Equal(t, "Node", query["interfaces"].([]Node)[0]["name"].(Node)["value"])
+		search := query["fields"].([]Node)[0]
+		assert.Equal(t, "ListType", search["type"].(Node)["type"].(Node)["kind"])
+		arguments := search["arguments"].([]Node)
+		assert.Equal(t, Node{"kind": "StringValue", "value": `a\nb`, "block": false}, arguments[0]["defaultValue"])
+		assert.Equal(t, Node{"kind": "IntValue", "value": "10"}, arguments[1]["defaultValue"])
+
+		assert.Len(t, definitions[1]["types"], 2)
+		values := definitions[2]["values"].([]Node)
+		assert.Equal(t, "admin role", values[0]["description"].(Node)["value"])
+		assert.Equal(t, Node{"kind": "EnumValue", "value": "ADMIN"}, definitions[3]["fields"].([]Node)[0]["defaultValue"])
+
+		directive := definitions[5]
+		assert.Equal(t, true, directive["repeatable"])
+		assert.Equal(t, []Node{{"kind": "Name", "value": "QUERY"}, {"kind": "Name", "value": "FIELD"}}, directive["locations"])
+
+		operationType := definitions[6]["operationTypes"].([]Node)[0]
+		assert.Equal(t, "query", operationType["operation"])
+	})
+}
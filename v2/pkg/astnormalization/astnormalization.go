@@ -147,9 +147,14 @@ type options struct {
 	removeFragmentDefinitions             bool
 	inlineFragmentSpreads                 bool
 	extractVariables                      bool
+	processVariables                      bool
+	keepSkippedFields                     bool
+	keepInlineFragments                   bool
 	removeUnusedVariables                 bool
 	removeNotMatchingOperationDefinitions bool
 	normalizeDefinition                   bool
+	trackReplacedNodes                    bool
+	maxExpandedSelections                 int
 }
 
 type Option func(options *options)
@@ -160,6 +165,30 @@ func WithExtractVariables() Option {
 	}
 }
 
+// WithProcessVariables injects the default values of variables and input fields and coerces list values of the variables
+// without extracting literal arguments into variables, WithExtractVariables processes the variables as well.
+func WithProcessVariables() Option {
+	return func(options *options) {
+		options.processVariables = true
+	}
+}
+
+// WithKeepSkippedFields keeps the fields and fragments with constant @skip and @include arguments and their directives
+// instead of removing the skipped selections and the directives of the included selections.
+func WithKeepSkippedFields() Option {
+	return func(options *options) {
+		options.keepSkippedFields = true
+	}
+}
+
+// WithKeepInlineFragments keeps inline fragments on the enclosing type or without type condition
+// instead of merging their selections into the parent selection set.
+func WithKeepInlineFragments() Option {
+	return func(options *options) {
+		options.keepInlineFragments = true
+	}
+}
+
 func WithRemoveFragmentDefinitions() Option {
 	return func(options *options) {
 		options.removeFragmentDefinitions = true
@@ -190,29 +219,51 @@ func WithNormalizeDefinition() Option {
 	}
 }
 
+// WithTrackReplacedNodes records which nodes replaced the nodes of the operation when they got copied or merged,
+// e.g. when inlining fragments. Use ast.Document.NodeReplacements to find the nodes of the normalized operation.
+func WithTrackReplacedNodes() Option {
+	return func(options *options) {
+		options.trackReplacedNodes = true
+	}
+}
+
+// WithMaxExpandedSelections limits the number of selections inlined from fragment spreads,
+// normalization stops with an error when the limit is exceeded.
+// The limit protects against fragment bombs, small operations whose fragments expand exponentially.
+func WithMaxExpandedSelections(maxExpandedSelections int) Option {
+	return func(options *options) {
+		options.maxExpandedSelections = maxExpandedSelections
+	}
+}
+
 func (o *OperationNormalizer) setupOperationWalkers() {
 	o.operationWalkers = make([]walkerStage, 0, 6)
 
+	fragmentInline := astvisitor.NewWalker(48)
+	deduplicateFragmentDefinitions(&fragmentInline)
 	if o.options.inlineFragmentSpreads {
-		fragmentInline := astvisitor.NewWalker(48)
-		fragmentSpreadInline(&fragmentInline)
-		o.operationWalkers = append(o.operationWalkers, walkerStage{
-			name:   "fragmentInline",
-			walker: &fragmentInline,
-		})
+		fragmentSpreadInlineWithLimit(&fragmentInline, o.options.maxExpandedSelections)
 	}
+	o.operationWalkers = append(o.operationWalkers, walkerStage{
+		name:   "deduplicateFragmentDefinitions, fragmentInline",
+		walker: &fragmentInline,
+	})
 
-	directivesIncludeSkip := astvisitor.NewWalker(48)
-	directiveIncludeSkip(&directivesIncludeSkip)
+	if !o.options.keepSkippedFields || o.options.removeNotMatchingOperationDefinitions {
+		directivesIncludeSkip := astvisitor.NewWalker(48)
+		if !o.options.keepSkippedFields {
+			directiveIncludeSkip(&directivesIncludeSkip)
+		}
 
-	if o.options.removeNotMatchingOperationDefinitions {
-		o.removeOperationDefinitionsVisitor = removeOperationDefinitions(&directivesIncludeSkip)
-	}
+		if o.options.removeNotMatchingOperationDefinitions {
+			o.removeOperationDefinitionsVisitor = removeOperationDefinitions(&directivesIncludeSkip)
+		}
 
-	o.operationWalkers = append(o.operationWalkers, walkerStage{
-		name:   "directivesIncludeSkip, removeOperationDefinitions",
-		walker: &directivesIncludeSkip,
-	})
+		o.operationWalkers = append(o.operationWalkers, walkerStage{
+			name:   "directivesIncludeSkip, removeOperationDefinitions",
+			walker: &directivesIncludeSkip,
+		})
+	}
 
 	if o.options.extractVariables {
 		extractVariablesWalker := astvisitor.NewWalker(48)
@@ -225,7 +276,9 @@ func (o *OperationNormalizer) setupOperationWalkers() {
 
 	other := astvisitor.NewWalker(48)
 	removeSelfAliasing(&other)
-	inlineSelectionsFromInlineFragments(&other)
+	if !o.options.keepInlineFragments {
+		inlineSelectionsFromInlineFragments(&other)
+	}
 	o.operationWalkers = append(o.operationWalkers, walkerStage{
 		name:   "removeSelfAliasing, inlineSelectionsFromInlineFragments",
 		walker: &other,
@@ -252,7 +305,7 @@ func (o *OperationNormalizer) setupOperationWalkers() {
 		walker: &cleanup,
 	})
 
-	if o.options.extractVariables {
+	if o.options.extractVariables || o.options.processVariables {
 		variablesProcessing := astvisitor.NewWalker(48)
 		inputCoercionForList(&variablesProcessing)
 		o.variablesDefaultValuesExtraction = extractVariablesDefaultValue(&variablesProcessing)
@@ -280,6 +333,10 @@ func (o *OperationNormalizer) NormalizeOperation(operation, definition *ast.Docu
 		}
 	}
 
+	if o.options.trackReplacedNodes {
+		operation.Index.TrackReplacedNodes = true
+	}
+
 	for i := range o.operationWalkers {
 		o.operationWalkers[i].walker.Walk(operation, definition, report)
 		if report.HasErrors() {
@@ -306,6 +363,9 @@ func (o *OperationNormalizer) NormalizeNamedOperation(operation, definition *ast
 	if o.removeOperationDefinitionsVisitor != nil {
 		o.removeOperationDefinitionsVisitor.operationName = operationName
 	}
+	if o.options.trackReplacedNodes {
+		operation.Index.TrackReplacedNodes = true
+	}
 
 	for i := range o.operationWalkers {
 		o.operationWalkers[i].walker.Walk(operation, definition, report)
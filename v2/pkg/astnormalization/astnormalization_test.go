@@ -462,6 +462,74 @@ schema {
 	})
 }
 
+func TestOperationNormalizer_TrackReplacedNodes(t *testing.T) {
+	schema := `
+scalar String
+
+type Query {
+	country: Country!
+}
+
+type Country {
+	name: String!
+	code: String!
+}
+
+schema {
+    query: Query
+}
+`
+	query := `fragment Fields on Country {name} query Q {country {...Fields} country {code name}}`
+
+	definition := unsafeparser.ParseGraphqlDocumentString(schema)
+	operation := unsafeparser.ParseGraphqlDocumentString(query)
+
+	fieldNode := func(name string, nth int) ast.Node {
+		for i := range operation.Fields {
+			if operation.FieldNameString(i) != name {
+				continue
+			}
+			if nth == 0 {
+				return ast.Node{Kind: ast.NodeKindField, Ref: i}
+			}
+			nth--
+		}
+		t.Fatalf("field %s not found", name)
+		return ast.InvalidNode
+	}
+
+	fragmentName := fieldNode("name", 0)
+	secondCountry := fieldNode("country", 1)
+	secondName := fieldNode("name", 1)
+
+	report := operationreport.Report{}
+	normalizer := NewWithOpts(WithInlineFragmentSpreads(), WithRemoveFragmentDefinitions(), WithTrackReplacedNodes())
+	normalizer.NormalizeOperation(&operation, &definition, &report)
+	require.False(t, report.HasErrors(), report.Error())
+	require.Equal(t, `query Q {country {name code}}`, unsafeprinter.Print(&operation, nil))
+
+	countrySelection := operation.SelectionSets[operation.OperationDefinitions[0].SelectionSet].SelectionRefs[0]
+	country := ast.Node{Kind: ast.NodeKindField, Ref: operation.Selections[countrySelection].Ref}
+	countrySet := operation.Fields[country.Ref].SelectionSet
+	name := ast.Node{Kind: ast.NodeKindField, Ref: operation.Selections[operation.SelectionSets[countrySet].SelectionRefs[0]].Ref}
+
+	assert.Equal(t, []ast.Node{country}, operation.NodeReplacements(secondCountry))
+	assert.Equal(t, []ast.Node{country}, operation.NodeReplacements(country))
+	assert.Equal(t, []ast.Node{name}, operation.NodeReplacements(fragmentName))
+	assert.Equal(t, []ast.Node{name}, operation.NodeReplacements(secondName))
+
+	t.Run("should not track replaced nodes by default", func(t *testing.T) {
+		definition := unsafeparser.ParseGraphqlDocumentString(schema)
+		operation := unsafeparser.ParseGraphqlDocumentString(query)
+
+		report := operationreport.Report{}
+		NewNormalizer(true, false).NormalizeOperation(&operation, &definition, &report)
+		require.False(t, report.HasErrors(), report.Error())
+
+		assert.Equal(t, []ast.Node{secondCountry}, operation.NodeReplacements(secondCountry))
+	})
+}
+
 func TestParseMissingBaseSchema(t *testing.T) {
 	const (
 		schema = `type Query {
@@ -482,6 +550,44 @@ func TestParseMissingBaseSchema(t *testing.T) {
 	assert.Regexp(t, regexp.MustCompile("forget.*merge.*base.*schema"), report.Error(), "error should mention the user forgot to merge the base schema")
 }
 
+func TestOperationNormalizer_KeepOptions(t *testing.T) {
+	operation := `
+		query a($unused: String, $cmd: DogCommand = SIT) {
+			dog {
+				... on Dog { name }
+				nickname @skip(if: true)
+				barkVolume @include(if: true)
+				doesKnowCommand(dogCommand: DOWN)
+				isHousetrained: doesKnowCommand(dogCommand: $cmd)
+			}
+		}`
+
+	normalize := func(t *testing.T, options ...Option) (string, string) {
+		t.Helper()
+
+		definition := unsafeparser.ParseGraphqlDocumentStringWithBaseSchema(testDefinition)
+		operationDocument := unsafeparser.ParseGraphqlDocumentString(operation)
+		report := operationreport.Report{}
+
+		NewWithOpts(append([]Option{WithRemoveFragmentDefinitions(), WithInlineFragmentSpreads()}, options...)...).NormalizeOperation(&operationDocument, &definition, &report)
+		require.False(t, report.HasErrors(), report.Error())
+
+		return mustString(astprinter.PrintString(&operationDocument, &definition)), string(operationDocument.Input.Variables)
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		output, variables := normalize(t, WithExtractVariables(), WithRemoveUnusedVariables())
+		assert.Equal(t, `query a($cmd: DogCommand!, $a: DogCommand!){dog {name barkVolume doesKnowCommand(dogCommand: $a) isHousetrained: doesKnowCommand(dogCommand: $cmd)}}`, output)
+		assert.Equal(t, `{"cmd":"SIT","a":"DOWN"}`, variables)
+	})
+
+	t.Run("keep", func(t *testing.T) {
+		output, variables := normalize(t, WithProcessVariables(), WithKeepSkippedFields(), WithKeepInlineFragments())
+		assert.Equal(t, `query a($unused: String, $cmd: DogCommand!){dog {... on Dog {name} nickname @skip(if: true) barkVolume @include(if: true) doesKnowCommand(dogCommand: DOWN) isHousetrained: doesKnowCommand(dogCommand: $cmd)}}`, output)
+		assert.Equal(t, `{"cmd":"SIT"}`, variables)
+	})
+}
+
 func BenchmarkAstNormalization(b *testing.B) {
 
 	definition := unsafeparser.ParseGraphqlDocumentString(testDefinition)
@@ -50,6 +50,7 @@ func (d *deduplicateFieldsVisitor) EnterSelectionSet(ref int) {
 				continue
 			}
 			if d.operation.FieldsAreEqualFlat(left, right) {
+				d.operation.AddReplacedNode(ast.Node{Kind: ast.NodeKindField, Ref: right}, ast.Node{Kind: ast.NodeKindField, Ref: left})
 				d.operation.RemoveFromSelectionSet(ref, b)
 				d.RevisitNode()
 				return
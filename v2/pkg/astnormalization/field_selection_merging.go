@@ -86,6 +86,7 @@ func (f *fieldSelectionMergeVisitor) EnterSelectionSet(ref int) {
 			}
 
 			if f.mergeFields(leftField, rightField) {
+				f.operation.AddReplacedNode(ast.Node{Kind: ast.NodeKindField, Ref: rightField}, ast.Node{Kind: ast.NodeKindField, Ref: leftField})
 				f.operation.RemoveFromSelectionSet(ref, i)
 				f.RevisitNode()
 			}
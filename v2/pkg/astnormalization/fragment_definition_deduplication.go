@@ -0,0 +1,105 @@
+package astnormalization
+
+import (
+	"bytes"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// deduplicateFragmentDefinitions removes fragment definitions which are identical to a previous definition with the same name,
+// e.g. when client bundles stitch the same fragment into an operation more than once.
+// Definitions with the same name but different type conditions, directives or selections are reported as conflict,
+// otherwise the fragment spreads would resolve to one of them.
+func deduplicateFragmentDefinitions(walker *astvisitor.Walker) {
+	visitor := deduplicateFragmentDefinitionsVisitor{
+		Walker: walker,
+	}
+	walker.RegisterEnterDocumentVisitor(&visitor)
+}
+
+type deduplicateFragmentDefinitionsVisitor struct {
+	*astvisitor.Walker
+}
+
+func (d *deduplicateFragmentDefinitionsVisitor) EnterDocument(operation, _ *ast.Document) {
+	// the root nodes of the first definition of each fragment
+	var definitions []int
+	for i := range operation.RootNodes {
+		if operation.RootNodes[i].Kind != ast.NodeKindFragmentDefinition {
+			continue
+		}
+		duplicate := false
+		for _, first := range definitions {
+			left, right := operation.RootNodes[first].Ref, operation.RootNodes[i].Ref
+			if !bytes.Equal(operation.FragmentDefinitionNameBytes(left), operation.FragmentDefinitionNameBytes(right)) {
+				continue
+			}
+			if !fragmentDefinitionsAreEqual(operation, left, right) {
+				d.StopWithExternalErr(operationreport.ErrFragmentDefinitionConflict(
+					operation.FragmentDefinitionNameBytes(right),
+					operation.FragmentDefinitions[left].FragmentLiteral,
+					operation.FragmentDefinitions[right].FragmentLiteral,
+				))
+				return
+			}
+			duplicate = true
+			break
+		}
+		if duplicate {
+			operation.RootNodes[i].Kind = ast.NodeKindUnknown
+			continue
+		}
+		definitions = append(definitions, i)
+	}
+}
+
+func fragmentDefinitionsAreEqual(operation *ast.Document, left, right int) bool {
+	return bytes.Equal(operation.FragmentDefinitionTypeName(left), operation.FragmentDefinitionTypeName(right)) &&
+		operation.DirectiveSetsAreEqual(operation.FragmentDefinitions[left].Directives.Refs, operation.FragmentDefinitions[right].Directives.Refs) &&
+		selectionSetsAreEqual(operation, operation.FragmentDefinitions[left].SelectionSet, operation.FragmentDefinitions[right].SelectionSet)
+}
+
+// selectionSetsAreEqual compares the selections of both selection sets in order, including their nested selections
+func selectionSetsAreEqual(operation *ast.Document, left, right int) bool {
+	leftSelections, rightSelections := operation.SelectionSets[left].SelectionRefs, operation.SelectionSets[right].SelectionRefs
+	if len(leftSelections) != len(rightSelections) {
+		return false
+	}
+	for i := range leftSelections {
+		leftSelection, rightSelection := operation.Selections[leftSelections[i]], operation.Selections[rightSelections[i]]
+		if leftSelection.Kind != rightSelection.Kind {
+			return false
+		}
+		l, r := leftSelection.Ref, rightSelection.Ref
+		switch leftSelection.Kind {
+		case ast.SelectionKindField:
+			equal := bytes.Equal(operation.FieldNameBytes(l), operation.FieldNameBytes(r)) &&
+				bytes.Equal(operation.FieldAliasBytes(l), operation.FieldAliasBytes(r)) &&
+				operation.ArgumentSetsAreEquals(operation.FieldArguments(l), operation.FieldArguments(r)) &&
+				operation.DirectiveSetsAreEqual(operation.FieldDirectives(l), operation.FieldDirectives(r)) &&
+				operation.FieldHasSelections(l) == operation.FieldHasSelections(r)
+			if !equal {
+				return false
+			}
+			if operation.FieldHasSelections(l) && !selectionSetsAreEqual(operation, operation.Fields[l].SelectionSet, operation.Fields[r].SelectionSet) {
+				return false
+			}
+		case ast.SelectionKindFragmentSpread:
+			equal := bytes.Equal(operation.FragmentSpreadNameBytes(l), operation.FragmentSpreadNameBytes(r)) &&
+				operation.DirectiveSetsAreEqual(operation.FragmentSpreads[l].Directives.Refs, operation.FragmentSpreads[r].Directives.Refs)
+			if !equal {
+				return false
+			}
+		case ast.SelectionKindInlineFragment:
+			equal := bytes.Equal(operation.InlineFragmentTypeConditionName(l), operation.InlineFragmentTypeConditionName(r)) &&
+				operation.DirectiveSetsAreEqual(operation.InlineFragments[l].Directives.Refs, operation.InlineFragments[r].Directives.Refs) &&
+				selectionSetsAreEqual(operation, operation.InlineFragments[l].SelectionSet, operation.InlineFragments[r].SelectionSet)
+			if !equal {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package astnormalization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqlerrors"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+func TestDeduplicateFragmentDefinitions(t *testing.T) {
+	t.Run("identical definitions", func(t *testing.T) {
+		runMany(t, testDefinition, `
+			query q {
+				dog { ...dogFields }
+			}
+			fragment dogFields on Dog { name ... on Dog { nickname } owner { name } }
+			fragment dogFields on Dog {
+				name
+				... on Dog { nickname }
+				owner { name }
+			}`, `
+			query q {
+				dog { name ... on Dog { nickname } owner { name } }
+			}
+			fragment dogFields on Dog { name ... on Dog { nickname } owner { name } }`,
+			deduplicateFragmentDefinitions, fragmentSpreadInline)
+	})
+
+	t.Run("different fragments", func(t *testing.T) {
+		runMany(t, testDefinition, `
+			query q {
+				dog { ...dogName ...dogNickname }
+			}
+			fragment dogName on Dog { name }
+			fragment dogNickname on Dog { nickname }`, `
+			query q {
+				dog { ...dogName ...dogNickname }
+			}
+			fragment dogName on Dog { name }
+			fragment dogNickname on Dog { nickname }`,
+			deduplicateFragmentDefinitions)
+	})
+
+	conflict := func(t *testing.T, operation string) operationreport.ExternalError {
+		t.Helper()
+
+		definitionDocument := unsafeparser.ParseGraphqlDocumentStringWithBaseSchema(testDefinition)
+		operationDocument := unsafeparser.ParseGraphqlDocumentString(operation)
+		report := operationreport.Report{}
+		walker := astvisitor.NewWalker(48)
+		deduplicateFragmentDefinitions(&walker)
+		walker.Walk(&operationDocument, &definitionDocument, &report)
+
+		require.Len(t, report.ExternalErrors, 1)
+		return report.ExternalErrors[0]
+	}
+
+	t.Run("conflicting selections", func(t *testing.T) {
+		err := conflict(t, `query q { dog { ...dogFields } }
+fragment dogFields on Dog { name }
+fragment dogFields on Dog { name: nickname }`)
+		assert.Equal(t, "fragment: dogFields is defined more than once with different type conditions, directives or selections", err.Message)
+		assert.Equal(t, []graphqlerrors.Location{{Line: 2, Column: 1}, {Line: 3, Column: 1}}, err.Locations)
+	})
+
+	t.Run("conflicting type conditions", func(t *testing.T) {
+		err := conflict(t, `query q { pet { ...petFields } }
+fragment petFields on Dog { name }
+fragment petFields on Cat { name }`)
+		assert.Equal(t, "fragment: petFields is defined more than once with different type conditions, directives or selections", err.Message)
+	})
+
+	t.Run("conflicting arguments", func(t *testing.T) {
+		conflict(t, `query q { dog { ...dogFields } }
+fragment dogFields on Dog { doesKnowCommand(dogCommand: SIT) }
+fragment dogFields on Dog { doesKnowCommand(dogCommand: DOWN) }`)
+	})
+}
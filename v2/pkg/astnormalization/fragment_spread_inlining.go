@@ -9,8 +9,15 @@ import (
 )
 
 func fragmentSpreadInline(walker *astvisitor.Walker) {
+	fragmentSpreadInlineWithLimit(walker, 0)
+}
+
+// fragmentSpreadInlineWithLimit inlines fragment spreads and stops with an error
+// once more than maxExpandedSelections selections were inlined, 0 disables the limit
+func fragmentSpreadInlineWithLimit(walker *astvisitor.Walker, maxExpandedSelections int) {
 	visitor := fragmentSpreadInlineVisitor{
-		Walker: walker,
+		Walker:                walker,
+		maxExpandedSelections: maxExpandedSelections,
 	}
 	walker.RegisterEnterDocumentVisitor(&visitor)
 	walker.RegisterEnterFragmentSpreadVisitor(&visitor)
@@ -20,6 +27,12 @@ func fragmentSpreadInline(walker *astvisitor.Walker) {
 type fragmentSpreadInlineVisitor struct {
 	*astvisitor.Walker
 	operation, definition *ast.Document
+
+	maxExpandedSelections int
+	expandedSelections    int
+	// fragmentSelections caches the number of selections of a fragment definition,
+	// fragment definitions are skipped, so their selections don't change during the walk
+	fragmentSelections map[int]int
 }
 
 func (f *fragmentSpreadInlineVisitor) EnterFragmentDefinition(ref int) {
@@ -29,6 +42,53 @@ func (f *fragmentSpreadInlineVisitor) EnterFragmentDefinition(ref int) {
 func (f *fragmentSpreadInlineVisitor) EnterDocument(operation, definition *ast.Document) {
 	f.operation = operation
 	f.definition = definition
+	f.expandedSelections = 0
+	if f.fragmentSelections == nil {
+		f.fragmentSelections = make(map[int]int)
+	} else {
+		clear(f.fragmentSelections)
+	}
+}
+
+// expand counts the selections inlined by the fragment definition and reports whether the limit is exceeded
+func (f *fragmentSpreadInlineVisitor) expand(fragmentDefinitionRef int) (ok bool) {
+	if f.maxExpandedSelections <= 0 {
+		return true
+	}
+
+	count, exists := f.fragmentSelections[fragmentDefinitionRef]
+	if !exists {
+		count = f.countSelections(f.operation.FragmentDefinitions[fragmentDefinitionRef].SelectionSet)
+		f.fragmentSelections[fragmentDefinitionRef] = count
+	}
+
+	f.expandedSelections += count
+	if f.expandedSelections > f.maxExpandedSelections {
+		f.StopWithExternalErr(operationreport.ErrExpandedSelectionsLimitExceeded(f.maxExpandedSelections))
+		return false
+	}
+	return true
+}
+
+// countSelections returns the number of selections of a selection set including nested selection sets,
+// nested fragment spreads count as one selection because they are counted when they are inlined
+func (f *fragmentSpreadInlineVisitor) countSelections(selectionSet int) (count int) {
+	for _, selectionRef := range f.operation.SelectionSets[selectionSet].SelectionRefs {
+		count++
+
+		selection := f.operation.Selections[selectionRef]
+		switch selection.Kind {
+		case ast.SelectionKindField:
+			if nested, ok := f.operation.FieldSelectionSet(selection.Ref); ok {
+				count += f.countSelections(nested)
+			}
+		case ast.SelectionKindInlineFragment:
+			if nested, ok := f.operation.InlineFragmentSelectionSet(selection.Ref); ok {
+				count += f.countSelections(nested)
+			}
+		}
+	}
+	return count
 }
 
 func (f *fragmentSpreadInlineVisitor) EnterFragmentSpread(ref int) {
@@ -85,6 +145,10 @@ func (f *fragmentSpreadInlineVisitor) EnterFragmentSpread(ref int) {
 		fragmentTypeIsMemberOfEnclosingUnionType = f.definition.NodeIsUnionMember(fragmentNode, f.EnclosingTypeDefinition)
 	}
 
+	if !f.expand(fragmentDefinitionRef) {
+		return
+	}
+
 	selectionSet := f.Ancestors[len(f.Ancestors)-1].Ref
 	replaceWith := f.operation.FragmentDefinitions[fragmentDefinitionRef].SelectionSet
 	typeCondition := f.operation.FragmentDefinitions[fragmentDefinitionRef].TypeCondition
@@ -1,6 +1,16 @@
 package astnormalization
 
-import "testing"
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
 
 func TestInlineFragments(t *testing.T) {
 	t.Run("simple", func(t *testing.T) {
@@ -578,3 +588,56 @@ func TestInlineFragments(t *testing.T) {
 				}`, true)
 	})
 }
+
+func TestFragmentSpreadInline_MaxExpandedSelections(t *testing.T) {
+	// each level doubles the selections, 10 levels expand to 2^10 name fields
+	var fragmentBomb strings.Builder
+	fragmentBomb.WriteString(`query a { dog { ...f0 } }`)
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(&fragmentBomb, ` fragment f%d on Dog { ...f%d ...f%d }`, i, i+1, i+1)
+	}
+	fragmentBomb.WriteString(` fragment f10 on Dog { name }`)
+
+	normalize := func(t *testing.T, operation string, maxExpandedSelections int) operationreport.Report {
+		t.Helper()
+
+		definition := unsafeparser.ParseGraphqlDocumentStringWithBaseSchema(testDefinition)
+		operationDocument := unsafeparser.ParseGraphqlDocumentString(operation)
+		report := operationreport.Report{}
+
+		NewWithOpts(
+			WithInlineFragmentSpreads(),
+			WithMaxExpandedSelections(maxExpandedSelections),
+		).NormalizeOperation(&operationDocument, &definition, &report)
+		return report
+	}
+
+	t.Run("should stop expanding fragments when the limit is exceeded", func(t *testing.T) {
+		report := normalize(t, fragmentBomb.String(), 1000)
+		require.True(t, report.HasErrors())
+		require.Len(t, report.ExternalErrors, 1)
+		assert.Equal(t, "fragments expand to more than 1000 selections", report.ExternalErrors[0].Message)
+	})
+
+	t.Run("should expand fragments within the limit", func(t *testing.T) {
+		report := normalize(t, fragmentBomb.String(), 5000)
+		assert.False(t, report.HasErrors(), report.Error())
+	})
+
+	t.Run("should not limit expansion by default", func(t *testing.T) {
+		report := normalize(t, fragmentBomb.String(), 0)
+		assert.False(t, report.HasErrors(), report.Error())
+	})
+
+	t.Run("should count nested selections of fragments", func(t *testing.T) {
+		operation := `
+			query a { dog { ...dogFields } }
+			fragment dogFields on Dog { name owner { name } }`
+
+		report := normalize(t, operation, 2)
+		assert.True(t, report.HasErrors())
+
+		report = normalize(t, operation, 3)
+		assert.False(t, report.HasErrors(), report.Error())
+	})
+}
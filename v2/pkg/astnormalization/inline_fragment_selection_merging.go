@@ -18,10 +18,18 @@ func mergeInlineFragmentSelections(walker *astvisitor.Walker) {
 type inlineFragmentSelectionMergeVisitor struct {
 	*astvisitor.Walker
 	operation *ast.Document
+	// fragmentKeys memoizes the structural keys of inline fragments,
+	// so that nested fragments are not processed again for every parent fragment
+	fragmentKeys map[int]string
+	// seenKeys holds the keys of the inline fragments of the current selection set
+	seenKeys map[string]int
+	buf      bytes.Buffer
 }
 
 func (f *inlineFragmentSelectionMergeVisitor) EnterDocument(operation, definition *ast.Document) {
 	f.operation = operation
+	f.fragmentKeys = make(map[int]string)
+	f.seenKeys = make(map[string]int)
 }
 
 func (f *inlineFragmentSelectionMergeVisitor) fragmentsCanBeMerged(left, right int) bool {
@@ -51,14 +59,119 @@ func (f *inlineFragmentSelectionMergeVisitor) mergeInlineFragments(left, right i
 	}
 
 	f.operation.AppendSelectionSet(leftSet, rightSet)
+	// the selections of the left fragment changed
+	delete(f.fragmentKeys, left)
 	return true
 }
 
+// removeDuplicatedInlineFragments removes inline fragments which are structurally equal to a previous inline fragment
+// of the selection set. Generated operations can contain hundreds of identical fragments, removing them upfront
+// avoids to merge their selections and to deduplicate the merged fields afterwards.
+func (f *inlineFragmentSelectionMergeVisitor) removeDuplicatedInlineFragments(ref int) {
+	for key := range f.seenKeys {
+		delete(f.seenKeys, key)
+	}
+
+	selectionRefs := f.operation.SelectionSets[ref].SelectionRefs
+	kept := selectionRefs[:0]
+	for _, selection := range selectionRefs {
+		if !f.operation.SelectionIsInlineFragmentSelection(selection) {
+			kept = append(kept, selection)
+			continue
+		}
+		inlineFragment := f.operation.Selections[selection].Ref
+		key := f.inlineFragmentKey(inlineFragment)
+		if first, ok := f.seenKeys[key]; ok {
+			f.operation.AddReplacedNode(ast.Node{Kind: ast.NodeKindInlineFragment, Ref: inlineFragment}, ast.Node{Kind: ast.NodeKindInlineFragment, Ref: first})
+			continue
+		}
+		f.seenKeys[key] = inlineFragment
+		kept = append(kept, selection)
+	}
+	f.operation.SelectionSets[ref].SelectionRefs = kept
+}
+
+// inlineFragmentKey returns a key which is equal for inline fragments with the same type condition, directives and selections
+func (f *inlineFragmentSelectionMergeVisitor) inlineFragmentKey(ref int) string {
+	if key, ok := f.fragmentKeys[ref]; ok {
+		return key
+	}
+
+	// nested fragments are keyed before the buffer is used for this fragment
+	if selectionSet, ok := f.operation.InlineFragmentSelectionSet(ref); ok {
+		f.keyNestedInlineFragments(selectionSet)
+	}
+
+	f.buf.Reset()
+	f.buf.WriteString("... on ")
+	f.buf.Write(f.operation.InlineFragmentTypeConditionName(ref))
+	f.writeDirectivesKey(f.operation.InlineFragmentDirectives(ref))
+	if selectionSet, ok := f.operation.InlineFragmentSelectionSet(ref); ok {
+		f.writeSelectionSetKey(selectionSet)
+	}
+
+	key := f.buf.String()
+	f.fragmentKeys[ref] = key
+	return key
+}
+
+func (f *inlineFragmentSelectionMergeVisitor) keyNestedInlineFragments(selectionSet int) {
+	for _, selection := range f.operation.SelectionSets[selectionSet].SelectionRefs {
+		switch f.operation.Selections[selection].Kind {
+		case ast.SelectionKindInlineFragment:
+			f.inlineFragmentKey(f.operation.Selections[selection].Ref)
+		case ast.SelectionKindField:
+			field := f.operation.Selections[selection].Ref
+			if f.operation.Fields[field].HasSelections {
+				f.keyNestedInlineFragments(f.operation.Fields[field].SelectionSet)
+			}
+		}
+	}
+}
+
+func (f *inlineFragmentSelectionMergeVisitor) writeSelectionSetKey(selectionSet int) {
+	f.buf.WriteByte('{')
+	for _, selection := range f.operation.SelectionSets[selectionSet].SelectionRefs {
+		ref := f.operation.Selections[selection].Ref
+		switch f.operation.Selections[selection].Kind {
+		case ast.SelectionKindField:
+			f.buf.Write(f.operation.FieldAliasOrNameBytes(ref))
+			f.buf.WriteByte(':')
+			f.buf.Write(f.operation.FieldNameBytes(ref))
+			if f.operation.FieldHasArguments(ref) {
+				_ = f.operation.PrintArguments(f.operation.FieldArguments(ref), &f.buf)
+			}
+			f.writeDirectivesKey(f.operation.FieldDirectives(ref))
+			if f.operation.Fields[ref].HasSelections {
+				f.writeSelectionSetKey(f.operation.Fields[ref].SelectionSet)
+			}
+		case ast.SelectionKindInlineFragment:
+			// nested fragments are keyed in keyNestedInlineFragments
+			f.buf.WriteString(f.fragmentKeys[ref])
+		case ast.SelectionKindFragmentSpread:
+			f.buf.WriteString("...")
+			f.buf.Write(f.operation.FragmentSpreadNameBytes(ref))
+			f.writeDirectivesKey(f.operation.FragmentSpreads[ref].Directives.Refs)
+		}
+		f.buf.WriteByte(' ')
+	}
+	f.buf.WriteByte('}')
+}
+
+func (f *inlineFragmentSelectionMergeVisitor) writeDirectivesKey(directives []int) {
+	for _, directive := range directives {
+		f.buf.WriteByte(' ')
+		_ = f.operation.PrintDirective(directive, &f.buf)
+	}
+}
+
 func (f *inlineFragmentSelectionMergeVisitor) EnterSelectionSet(ref int) {
 	if len(f.operation.SelectionSets[ref].SelectionRefs) < 2 {
 		return
 	}
 
+	f.removeDuplicatedInlineFragments(ref)
+
 	for _, leftSelection := range f.operation.SelectionSets[ref].SelectionRefs {
 		if !f.operation.SelectionIsInlineFragmentSelection(leftSelection) {
 			continue
@@ -76,6 +189,7 @@ func (f *inlineFragmentSelectionMergeVisitor) EnterSelectionSet(ref int) {
 				continue
 			}
 			if f.mergeInlineFragments(leftInlineFragment, rightInlineFragment) {
+				f.operation.AddReplacedNode(ast.Node{Kind: ast.NodeKindInlineFragment, Ref: rightInlineFragment}, ast.Node{Kind: ast.NodeKindInlineFragment, Ref: leftInlineFragment})
 				f.operation.RemoveFromSelectionSet(ref, i)
 				f.RevisitNode()
 			}
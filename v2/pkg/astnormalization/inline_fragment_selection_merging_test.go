@@ -1,6 +1,12 @@
 package astnormalization
 
-import "testing"
+import (
+	"strings"
+	"testing"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
 
 func TestMergeInlineFragmentSelections(t *testing.T) {
 	t.Run("fragments with the same field", func(t *testing.T) {
@@ -22,8 +28,35 @@ func TestMergeInlineFragmentSelections(t *testing.T) {
 						pet {
 							... on Dog {
 								name
-								name
-								name
+							}
+						}
+					}`)
+	})
+	t.Run("identical fragments with nested selections and arguments", func(t *testing.T) {
+		run(t, mergeInlineFragmentSelections, testDefinition, `
+					query a {
+						pet {
+							... on Dog {
+								doesKnowCommand(dogCommand: SIT)
+								owner { ... on Human { name } }
+							}
+							... on Dog {
+								doesKnowCommand(dogCommand: SIT)
+								owner { ... on Human { name } }
+							}
+							... on Dog {
+								doesKnowCommand(dogCommand: DOWN)
+								owner { ... on Human { name } }
+							}
+						}
+					}`, `
+					query a {
+						pet {
+							... on Dog {
+								doesKnowCommand(dogCommand: SIT)
+								owner { ... on Human { name } }
+								doesKnowCommand(dogCommand: DOWN)
+								owner { ... on Human { name } }
 							}
 						}
 					}`)
@@ -122,3 +155,24 @@ func TestMergeInlineFragmentSelections(t *testing.T) {
 					}`)
 	})
 }
+
+func BenchmarkMergeInlineFragmentSelections_IdenticalFragments(b *testing.B) {
+	fragments := strings.Repeat(`... on Dog { name owner { ... on Human { name } } }`, 300)
+	query := `query a { pet { ` + fragments + ` } }`
+
+	definition := unsafeparser.ParseGraphqlDocumentStringWithBaseSchema(testDefinition)
+	normalizer := NewNormalizer(true, true)
+	report := operationreport.Report{}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		operation := unsafeparser.ParseGraphqlDocumentString(query)
+		report.Reset()
+		normalizer.NormalizeOperation(&operation, &definition, &report)
+		if report.HasErrors() {
+			b.Fatal(report.Error())
+		}
+	}
+}
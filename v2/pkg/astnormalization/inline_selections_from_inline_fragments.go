@@ -43,6 +43,7 @@ func (m *inlineSelectionsFromInlineFragmentsVisitor) couldInline(set, inlineFrag
 }
 
 func (m *inlineSelectionsFromInlineFragmentsVisitor) resolveInlineFragment(set, index, inlineFragment int) {
+	m.operation.AddReplacedNode(ast.Node{Kind: ast.NodeKindInlineFragment, Ref: inlineFragment}, ast.Node{Kind: ast.NodeKindSelectionSet, Ref: set})
 	m.operation.ReplaceSelectionOnSelectionSet(set, index, m.operation.InlineFragments[inlineFragment].SelectionSet)
 }
 
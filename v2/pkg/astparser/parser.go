@@ -227,6 +227,26 @@ func (p *Parser) errUnexpectedToken(unexpected token.Token, expectedKeywords ...
 	})
 }
 
+// validateStringEscapes reports invalid escape sequences, lone surrogates and invalid UTF-8 in a string token
+func (p *Parser) validateStringEscapes(tok token.Token) {
+	if p.report.HasErrors() {
+		return
+	}
+	err := ast.ValidateStringValueEscapes(p.document.Input.ByteSlice(tok.Literal))
+	if err == nil {
+		return
+	}
+	p.report.AddExternalError(operationreport.ExternalError{
+		Message: fmt.Sprintf("invalid string value: %s", err),
+		Locations: []graphqlerrors.Location{
+			{
+				Line:   tok.TextPosition.LineStart,
+				Column: tok.TextPosition.CharStart,
+			},
+		},
+	})
+}
+
 func (p *Parser) parseSchemaDefinition(description *ast.Description) {
 	var schemaDefinition ast.SchemaDefinition
 
@@ -647,6 +667,9 @@ func (p *Parser) parseStringValue() (ref int, pos position.Position) {
 		p.errUnexpectedToken(value, keyword.STRING, keyword.BLOCKSTRING)
 		return ast.InvalidRef, position.Position{}
 	}
+	if value.Keyword == keyword.STRING {
+		p.validateStringEscapes(value)
+	}
 	stringValue := ast.StringValue{
 		Content:     value.Literal,
 		BlockString: value.Keyword == keyword.BLOCKSTRING,
@@ -882,6 +905,9 @@ func (p *Parser) ParseType() (ref int) {
 
 func (p *Parser) parseDescription() ast.Description {
 	tok := p.read()
+	if tok.Keyword == keyword.STRING {
+		p.validateStringEscapes(tok)
+	}
 	return ast.Description{
 		IsDefined:     true,
 		Content:       tok.Literal,
@@ -0,0 +1,26 @@
+package astparser
+
+import (
+	"testing"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+)
+
+func FuzzParseGraphqlDocumentBytes(f *testing.F) {
+	f.Add([]byte(`query Q($a: String = "a\nbé😀") { field(arg: """block \""" string""") @dir(if: true) { ... on T { id } } }`))
+	f.Add([]byte(`type Query { "desc" field(a: [Int!]! = [1, 2]): String @deprecated(reason: """  multi
+	    line""") } schema { query: Query }`))
+	f.Add([]byte(`{ a(b: {c: -1.5e10, d: ENUM, e: null}) }`))
+	f.Add([]byte(`"\u{1F600}"`))
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		doc, report := ParseGraphqlDocumentBytes(input)
+		if report.HasErrors() {
+			return
+		}
+		for i := range doc.StringValues {
+			_, _ = doc.StringValueUnescaped(i)
+		}
+		_, _ = astprinter.PrintString(&doc, nil)
+	})
+}
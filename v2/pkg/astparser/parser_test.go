@@ -1650,6 +1650,38 @@ func TestParser_Parse(t *testing.T) {
 				run(`- 13.37`, parseValue, true)
 			})
 		})
+		t.Run("string value", func(t *testing.T) {
+			t.Run("escape sequences", func(t *testing.T) {
+				run(`"\\ \" \/ \b \f \n \r \t \u00e9 \uD83D\uDE00 \u{1F600}"`, parseValue, false,
+					func(doc *ast.Document, extra interface{}) {
+						value := extra.(ast.Value)
+						if value.Kind != ast.ValueKindString {
+							panic("want ValueKindString")
+						}
+					})
+			})
+			t.Run("report invalid escape sequence", func(t *testing.T) {
+				run(`"\x"`, parseValue, true)
+			})
+			t.Run("report incomplete unicode escape sequence", func(t *testing.T) {
+				run(`"\u00"`, parseValue, true)
+			})
+			t.Run("report lone leading surrogate", func(t *testing.T) {
+				run(`"\uD83D"`, parseValue, true)
+			})
+			t.Run("report lone trailing surrogate", func(t *testing.T) {
+				run(`"\uDE00"`, parseValue, true)
+			})
+			t.Run("report surrogate in braced escape", func(t *testing.T) {
+				run(`"\u{D83D}"`, parseValue, true)
+			})
+			t.Run("report invalid UTF-8", func(t *testing.T) {
+				run("\"\xff\"", parseValue, true)
+			})
+			t.Run("block string escapes are not validated", func(t *testing.T) {
+				run(`"""\x"""`, parseValue, false)
+			})
+		})
 		t.Run("null value", func(t *testing.T) {
 			run(`null`, parseValue, false,
 				func(doc *ast.Document, extra interface{}) {
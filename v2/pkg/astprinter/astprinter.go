@@ -34,6 +34,27 @@ func PrintIndentDebug(document, definition *ast.Document, indent []byte, out io.
 	return printer.Print(document, definition, out)
 }
 
+// PrintRedacted is the same as PrintIndent but replaces all string, int, float and boolean literals of arguments
+// and variable default values with a placeholder, e.g. to print operations in logs without leaking user data.
+func PrintRedacted(document, definition *ast.Document, indent []byte, out io.Writer) error {
+	printer := Printer{
+		indent: indent,
+		redact: true,
+	}
+	return printer.Print(document, definition, out)
+}
+
+// PrintShape prints the shape of a document, e.g. to log or aggregate operations without their values.
+// String, int, float and boolean literals are replaced with a placeholder of their type ("", 0, 0.0 and false)
+// and lists with an empty list, so operations which only differ in their literals print the same.
+// Variables, enum values, null and the fields of objects are kept.
+func PrintShape(document, definition *ast.Document, out io.Writer) error {
+	printer := Printer{
+		shape: true,
+	}
+	return printer.Print(document, definition, out)
+}
+
 // PrintString is the same as Print but returns a string instead of writing to an io.Writer
 func PrintString(document, definition *ast.Document) (string, error) {
 	buff := &bytes.Buffer{}
@@ -58,6 +79,22 @@ func PrintStringIndentDebug(document, definition *ast.Document, indent string) (
 	return out, err
 }
 
+// PrintStringRedacted is the same as PrintRedacted but returns a string instead of writing to an io.Writer
+func PrintStringRedacted(document, definition *ast.Document, indent string) (string, error) {
+	buff := &bytes.Buffer{}
+	err := PrintRedacted(document, definition, []byte(indent), buff)
+	out := buff.String()
+	return out, err
+}
+
+// PrintStringShape is the same as PrintShape but returns a string instead of writing to an io.Writer
+func PrintStringShape(document, definition *ast.Document) (string, error) {
+	buff := &bytes.Buffer{}
+	err := PrintShape(document, definition, buff)
+	out := buff.String()
+	return out, err
+}
+
 // Printer walks a GraphQL document and prints it as a string
 type Printer struct {
 	indent     []byte
@@ -65,6 +102,8 @@ type Printer struct {
 	walker     astvisitor.SimpleWalker
 	registered bool
 	debug      bool
+	redact     bool
+	shape      bool
 }
 
 // Print starts the actual AST printing
@@ -72,6 +111,8 @@ type Printer struct {
 func (p *Printer) Print(document, definition *ast.Document, out io.Writer) error {
 	p.visitor.indent = p.indent
 	p.visitor.debug = p.debug
+	p.visitor.redact = p.redact
+	p.visitor.shape = p.shape
 	p.visitor.err = nil
 	p.visitor.document = document
 	p.visitor.out = out
@@ -94,6 +135,8 @@ type printVisitor struct {
 	isFirstDirectiveLocation   bool
 	isDirectiveRepeatable      bool
 	debug                      bool
+	redact                     bool
+	shape                      bool
 }
 
 func (p *printVisitor) write(data []byte) {
@@ -223,7 +266,7 @@ func (p *printVisitor) EnterVariableDefinition(ref int) {
 		p.write(literal.SPACE)
 		p.write(literal.EQUALS)
 		p.write(literal.SPACE)
-		p.must(p.document.PrintValue(p.document.VariableDefinitions[ref].DefaultValue.Value, p.out))
+		p.printValue(p.document.VariableDefinitions[ref].DefaultValue.Value)
 	}
 
 	if p.document.VariableDefinitions[ref].HasDirectives {
@@ -247,6 +290,13 @@ func (p *printVisitor) EnterArgument(ref int) {
 		p.write(literal.COMMA)
 		p.write(literal.SPACE)
 	}
+	if p.redact || p.shape {
+		p.write(p.document.ArgumentNameBytes(ref))
+		p.write(literal.COLON)
+		p.write(literal.SPACE)
+		p.printValue(p.document.ArgumentValue(ref))
+		return
+	}
 	p.must(p.document.PrintArgument(ref, p.out))
 }
 
@@ -1088,3 +1138,71 @@ func (p *printVisitor) writeFieldType(ref int) {
 	p.write(literal.SPACE)
 	p.must(p.document.PrintType(p.document.FieldDefinitionType(ref), p.out))
 }
+
+var (
+	redactedValue = []byte(`"****"`)
+
+	shapeStringValue  = []byte(`""`)
+	shapeIntegerValue = []byte(`0`)
+	shapeFloatValue   = []byte(`0.0`)
+	shapeBooleanValue = []byte(`false`)
+	shapeListValue    = []byte(`[]`)
+)
+
+// printValue prints the value, in redact mode scalar literals are replaced with a placeholder
+// while variables, enum values, null and the structure of lists and objects are kept,
+// in shape mode scalar literals are replaced with a placeholder of their type and lists with an empty list
+func (p *printVisitor) printValue(value ast.Value) {
+	if !p.redact && !p.shape {
+		p.must(p.document.PrintValue(value, p.out))
+		return
+	}
+	switch value.Kind {
+	case ast.ValueKindString, ast.ValueKindInteger, ast.ValueKindFloat, ast.ValueKindBoolean:
+		p.write(p.placeholder(value.Kind))
+	case ast.ValueKindList:
+		if p.shape {
+			p.write(shapeListValue)
+			return
+		}
+		p.write(literal.LBRACK)
+		for i, ref := range p.document.ListValues[value.Ref].Refs {
+			if i != 0 {
+				p.write(literal.COMMA)
+			}
+			p.printValue(p.document.Value(ref))
+		}
+		p.write(literal.RBRACK)
+	case ast.ValueKindObject:
+		p.write(literal.LBRACE)
+		for i, ref := range p.document.ObjectValues[value.Ref].Refs {
+			if i != 0 {
+				p.write(literal.COMMA)
+			}
+			p.write(p.document.ObjectFieldNameBytes(ref))
+			p.write(literal.COLON)
+			p.write(literal.SPACE)
+			p.printValue(p.document.ObjectFieldValue(ref))
+		}
+		p.write(literal.RBRACE)
+	default:
+		p.must(p.document.PrintValue(value, p.out))
+	}
+}
+
+// placeholder returns the placeholder of a scalar literal
+func (p *printVisitor) placeholder(kind ast.ValueKind) []byte {
+	if !p.shape {
+		return redactedValue
+	}
+	switch kind {
+	case ast.ValueKindInteger:
+		return shapeIntegerValue
+	case ast.ValueKindFloat:
+		return shapeFloatValue
+	case ast.ValueKindBoolean:
+		return shapeBooleanValue
+	default:
+		return shapeStringValue
+	}
+}
@@ -677,6 +677,34 @@ func TestPrintArgumentWithBeforeAfterValue(t *testing.T) {
 	assert.Equal(t, "mutation($email: String!){pge_queryRaw(query: \"SELECT id, name, email from \\\"User\\\" where email = $1\", parameters: \"[$email]\")}", string(out))
 }
 
+func TestPrintRedacted(t *testing.T) {
+	doc := unsafeparser.ParseGraphqlDocumentString(`
+		query Search($limit: Int = 10, $episode: Episode = JEDI) {
+			search(name: "Luke", filter: {excludeName: "Leia", stars: [1, 2.5, true, null], episode: NEWHOPE}, id: $id) @include(if: false) {
+				name
+			}
+		}
+	`)
+
+	buff := &bytes.Buffer{}
+	require.NoError(t, PrintRedacted(&doc, nil, nil, buff))
+	assert.Equal(t, `query Search($limit: Int = "****", $episode: Episode = JEDI){search(name: "****", filter: {excludeName: "****",stars: ["****","****","****",null],episode: NEWHOPE}, id: $id)@include(if: "****") {name}}`, buff.String())
+}
+
+func TestPrintShape(t *testing.T) {
+	doc := unsafeparser.ParseGraphqlDocumentString(`
+		query Search($limit: Int = 10, $episode: Episode = JEDI) {
+			search(name: "Luke", filter: {excludeName: "Leia", stars: [1, 2.5, true, null], rating: 4.5, episode: NEWHOPE, ids: null}, id: $id) @include(if: true) {
+				name
+			}
+		}
+	`)
+
+	out, err := PrintStringShape(&doc, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `query Search($limit: Int = 0, $episode: Episode = JEDI){search(name: "", filter: {excludeName: "",stars: [],rating: 0.0,episode: NEWHOPE,ids: null}, id: $id)@include(if: false) {name}}`, out)
+}
+
 func TestPrintSchemaDefinition(t *testing.T) {
 
 	doc := unsafeparser.ParseGraphqlDocumentFile("./testdata/starwars.schema.graphql")
@@ -165,6 +165,11 @@ directive @deprecated(
     """
     reason: String = "No longer supported"
 ) on FIELD_DEFINITION | ENUM_VALUE
+"Exposes a URL that specifies the behavior of this scalar."
+directive @specifiedBy(
+    "The URL that specifies the behavior of this scalar."
+    url: String!
+) on SCALAR
 
 """
 A Directive provides a way to describe alternate runtime execution and type validation behavior in a GraphQL document.
@@ -298,6 +303,7 @@ type __Type {
     enumValues(includeDeprecated: Boolean = false): [__EnumValue!]
     inputFields: [__InputValue!]
     ofType: __Type
+    specifiedByURL: String
 }
 
 "An enum describing what kind of type a given '__Type' is."
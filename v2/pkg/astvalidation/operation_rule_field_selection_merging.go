@@ -152,7 +152,7 @@ func (f *fieldSelectionMergingVisitor) EnterField(ref int) {
 
 	for _, i := range matchedRequirements {
 		if f.potentiallySameObject(f.scalarRequirements[i].enclosingTypeDefinition, f.EnclosingTypeDefinition) {
-			if !f.operation.FieldsAreEqualFlat(f.scalarRequirements[i].fieldRef, ref) {
+			if !f.operation.FieldsCanMergeFlat(f.scalarRequirements[i].fieldRef, ref) {
 				f.StopWithExternalErr(operationreport.ErrDifferingFieldsOnPotentiallySameType(objectName))
 				return
 			}
@@ -6,7 +6,7 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
 )
 
-// RequiredArguments validates if all required arguments are present
+// RequiredArguments validates if all required arguments of fields and directives are present
 func RequiredArguments() Rule {
 	return func(walker *astvisitor.Walker) {
 		visitor := requiredArgumentsVisitor{
@@ -14,6 +14,7 @@ func RequiredArguments() Rule {
 		}
 		walker.RegisterEnterDocumentVisitor(&visitor)
 		walker.RegisterEnterFieldVisitor(&visitor)
+		walker.RegisterEnterDirectiveVisitor(&visitor)
 	}
 }
 
@@ -51,3 +52,26 @@ func (r *requiredArgumentsVisitor) EnterField(ref int) {
 		}
 	}
 }
+
+func (r *requiredArgumentsVisitor) EnterDirective(ref int) {
+	directiveName := r.operation.DirectiveNameBytes(ref)
+	directiveDefinition, exists := r.definition.DirectiveDefinitionByNameBytes(directiveName)
+	if !exists {
+		// unknown directives are reported by DirectivesAreDefined
+		return
+	}
+
+	for _, i := range r.definition.DirectiveDefinitions[directiveDefinition].ArgumentsDefinition.Refs {
+		if r.definition.InputValueDefinitionArgumentIsOptional(i) {
+			continue
+		}
+
+		name := r.definition.InputValueDefinitionNameBytes(i)
+
+		// null values are reported by Values
+		if _, exists := r.operation.DirectiveArgumentValueByName(ref, name); !exists {
+			r.StopWithExternalErr(operationreport.ErrArgumentRequiredOnDirective(name, directiveName, r.operation.Directives[ref].At))
+			return
+		}
+	}
+}
@@ -653,6 +653,24 @@ func TestExecutionValidation(t *testing.T) {
 						}
 					}`, FieldSelectionMerging(), Valid)
 			})
+			t.Run("fields differing in repeatable directives", func(t *testing.T) {
+				run(t, `
+						query {
+							dog {
+								name @tag(name: "a")
+								name @tag(name: "b") @tag(name: "c")
+							}
+						}`, FieldSelectionMerging(), Valid)
+			})
+			t.Run("fields differing in skip directives", func(t *testing.T) {
+				run(t, `
+						query conditionalName($skip: Boolean!) {
+							dog {
+								name @tag(name: "a")
+								name @skip(if: $skip)
+							}
+						}`, FieldSelectionMerging(), Invalid)
+			})
 			t.Run("reference implementation tests", func(t *testing.T) {
 				t.Run("Same aliases allowed on non-overlapping fields", func(t *testing.T) {
 					run(t, `
@@ -2211,6 +2229,22 @@ func TestExecutionValidation(t *testing.T) {
 									}`,
 					KnownArguments(), Invalid, withValidationErrors(`Unknown argument "unless" on directive "@include".`))
 			})
+			t.Run("119 variant with custom directive", func(t *testing.T) {
+				run(t, ` 	{
+										dog {
+											name @tag(name: "a", weight: 1)
+										}
+									}`,
+					KnownArguments(), Invalid, withValidationErrors(`Unknown argument "weight" on directive "@tag".`))
+			})
+			t.Run("119 variant with custom directive argument of wrong type", func(t *testing.T) {
+				run(t, ` 	{
+										dog {
+											name @tag(name: 1)
+										}
+									}`,
+					Values(), Invalid, withValidationErrors(`String cannot represent a non string value: 1`))
+			})
 			t.Run("121 args in reversed order", func(t *testing.T) {
 				run(t, `	fragment multipleArgs on ValidArguments {
 								multipleReqs(x: 1, y: 2)
@@ -2371,6 +2405,38 @@ func TestExecutionValidation(t *testing.T) {
 								}`,
 					RequiredArguments(), Valid)
 			})
+			t.Run("missing required argument of directive", func(t *testing.T) {
+				run(t, `	{
+									dog {
+										name @include
+									}
+								}`,
+					RequiredArguments(), Invalid, withValidationErrors(`argument: if is required on directive: @include but missing`))
+			})
+			t.Run("missing required argument of custom directive", func(t *testing.T) {
+				run(t, `	{
+									dog {
+										name @tag
+									}
+								}`,
+					RequiredArguments(), Invalid, withValidationErrors(`argument: name is required on directive: @tag but missing`))
+			})
+			t.Run("required argument of custom directive", func(t *testing.T) {
+				run(t, `	{
+									dog {
+										name @tag(name: "a")
+									}
+								}`,
+					RequiredArguments(), Valid)
+			})
+			t.Run("unknown directive", func(t *testing.T) {
+				run(t, `	{
+									dog {
+										name @unknown
+									}
+								}`,
+					RequiredArguments(), Valid)
+			})
 		})
 	})
 	t.Run("5.5 Fragments", func(t *testing.T) {
@@ -2409,7 +2475,7 @@ func TestExecutionValidation(t *testing.T) {
     									name
   									}
 								}`,
-						Fragments(), Invalid)
+						Fragments(), Invalid, withDisableNormalization())
 				})
 			})
 			t.Run("5.5.1.2 Fragment Spread Existence", func(t *testing.T) {
@@ -3351,6 +3417,12 @@ func TestExecutionValidation(t *testing.T) {
 								}`,
 					DirectivesAreUniquePerLocation(), Valid)
 			})
+			t.Run("152 variant", func(t *testing.T) {
+				run(t, `query MyQuery {
+									field @tag(name: "a") @tag(name: "b") @tag(name: "a")
+								}`,
+					DirectivesAreUniquePerLocation(), Valid)
+			})
 		})
 	})
 	t.Run("5.8 Variables", func(t *testing.T) {
@@ -4602,6 +4674,7 @@ directive @fragmentDefinition on FRAGMENT_DEFINITION
 directive @onQuery on QUERY
 directive @onMutation on MUTATION
 directive @onSubscription on SUBSCRIPTION
+directive @tag(name: String!) repeatable on FIELD | INLINE_FRAGMENT
 
 "The Int scalar type represents non-fractional signed whole numeric values. Int can represent values between -(2^31) and 2^31 - 1."
 scalar Int
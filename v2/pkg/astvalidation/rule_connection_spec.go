@@ -0,0 +1,219 @@
+package astvalidation
+
+import (
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+const (
+	connectionTypeSuffix = "Connection"
+	pageInfoTypeName     = "PageInfo"
+)
+
+// ConnectionSpec validates the conformance of the schema to the Relay cursor connections spec:
+// https://relay.dev/graphql/connections.htm
+//
+// Object types with a name ending in Connection are connections and need an edges field returning a list of edges
+// and a pageInfo field returning PageInfo!. Edges need a node field and a cursor field returning a scalar.
+// PageInfo needs the fields hasPreviousPage: Boolean!, hasNextPage: Boolean!, startCursor and endCursor.
+// Fields returning a connection need the forward pagination arguments first and after,
+// the backward pagination arguments last and before, or both.
+//
+// The rule is not part of the DefaultDefinitionValidator, as the spec is a convention and not part of GraphQL.
+func ConnectionSpec() Rule {
+	return func(walker *astvisitor.Walker) {
+		visitor := &connectionSpecVisitor{
+			Walker: walker,
+		}
+
+		walker.RegisterEnterDocumentVisitor(visitor)
+		walker.RegisterEnterObjectTypeDefinitionVisitor(visitor)
+	}
+}
+
+type connectionSpecVisitor struct {
+	*astvisitor.Walker
+	definition *ast.Document
+	// validatedEdges avoids reporting the violations of edges shared by multiple connections more than once
+	validatedEdges map[int]struct{}
+}
+
+func (c *connectionSpecVisitor) EnterDocument(operation, _ *ast.Document) {
+	c.definition = operation
+	c.validatedEdges = map[int]struct{}{}
+}
+
+func (c *connectionSpecVisitor) EnterObjectTypeDefinition(ref int) {
+	typeName := c.definition.ObjectTypeDefinitionNameString(ref)
+	switch {
+	case typeName == pageInfoTypeName:
+		c.validatePageInfo(ref)
+	case strings.HasSuffix(typeName, connectionTypeSuffix) && typeName != connectionTypeSuffix:
+		c.validateConnection(ref)
+	}
+
+	for _, fieldRef := range c.definition.ObjectTypeDefinitions[ref].FieldsDefinition.Refs {
+		if c.isConnection(c.definition.FieldDefinitionType(fieldRef)) {
+			c.validateConnectionArguments(typeName, fieldRef)
+		}
+	}
+}
+
+func (c *connectionSpecVisitor) validateConnection(ref int) {
+	typeName := c.definition.ObjectTypeDefinitionNameString(ref)
+
+	edgesRef, ok := c.definition.ObjectTypeDefinitionFieldWithName(ref, []byte("edges"))
+	if !ok {
+		c.report(typeName, "", "a connection must have an edges field")
+	} else {
+		edgesType := c.definition.FieldDefinitionType(edgesRef)
+		edgeNode, isObject := c.objectTypeDefinition(edgesType)
+		switch {
+		case !c.definition.TypeIsList(edgesType):
+			c.report(typeName, "edges", "the edges field must return a list")
+		case !isObject:
+			c.report(typeName, "edges", "the edges field must return a list of an object type")
+		default:
+			c.validateEdge(edgeNode.Ref)
+		}
+	}
+
+	pageInfoRef, ok := c.definition.ObjectTypeDefinitionFieldWithName(ref, []byte("pageInfo"))
+	if !ok {
+		c.report(typeName, "", "a connection must have a pageInfo field")
+		return
+	}
+	pageInfoType := c.definition.FieldDefinitionType(pageInfoRef)
+	if !c.definition.TypeIsNonNull(pageInfoType) || c.definition.TypeIsList(pageInfoType) ||
+		c.definition.ResolveTypeNameString(pageInfoType) != pageInfoTypeName {
+		c.report(typeName, "pageInfo", "the pageInfo field must return PageInfo!")
+	}
+}
+
+func (c *connectionSpecVisitor) validateEdge(ref int) {
+	if _, ok := c.validatedEdges[ref]; ok {
+		return
+	}
+	c.validatedEdges[ref] = struct{}{}
+
+	typeName := c.definition.ObjectTypeDefinitionNameString(ref)
+
+	nodeRef, ok := c.definition.ObjectTypeDefinitionFieldWithName(ref, []byte("node"))
+	if !ok {
+		c.report(typeName, "", "an edge must have a node field")
+	} else if c.definition.TypeIsList(c.definition.FieldDefinitionType(nodeRef)) {
+		c.report(typeName, "node", "the node field must not return a list")
+	}
+
+	cursorRef, ok := c.definition.ObjectTypeDefinitionFieldWithName(ref, []byte("cursor"))
+	if !ok {
+		c.report(typeName, "", "an edge must have a cursor field")
+	} else if !c.isScalar(c.definition.FieldDefinitionType(cursorRef)) {
+		c.report(typeName, "cursor", "the cursor field must return a scalar")
+	}
+}
+
+func (c *connectionSpecVisitor) validatePageInfo(ref int) {
+	for _, fieldName := range []string{"hasPreviousPage", "hasNextPage"} {
+		fieldRef, ok := c.definition.ObjectTypeDefinitionFieldWithName(ref, []byte(fieldName))
+		if !ok {
+			c.report(pageInfoTypeName, "", "PageInfo must have a "+fieldName+" field")
+			continue
+		}
+		fieldType := c.definition.FieldDefinitionType(fieldRef)
+		if !c.definition.TypeIsNonNull(fieldType) || c.definition.TypeIsList(fieldType) ||
+			c.definition.ResolveTypeNameString(fieldType) != "Boolean" {
+			c.report(pageInfoTypeName, fieldName, "the "+fieldName+" field must return Boolean!")
+		}
+	}
+
+	for _, fieldName := range []string{"startCursor", "endCursor"} {
+		fieldRef, ok := c.definition.ObjectTypeDefinitionFieldWithName(ref, []byte(fieldName))
+		if !ok {
+			c.report(pageInfoTypeName, "", "PageInfo must have a "+fieldName+" field")
+			continue
+		}
+		if !c.isScalar(c.definition.FieldDefinitionType(fieldRef)) {
+			c.report(pageInfoTypeName, fieldName, "the "+fieldName+" field must return a scalar")
+		}
+	}
+}
+
+func (c *connectionSpecVisitor) validateConnectionArguments(typeName string, fieldRef int) {
+	fieldName := c.definition.FieldDefinitionNameString(fieldRef)
+
+	forward := c.validatePaginationArguments(typeName, fieldName, fieldRef, "first", "after")
+	backward := c.validatePaginationArguments(typeName, fieldName, fieldRef, "last", "before")
+	if !forward && !backward {
+		c.report(typeName, fieldName, "a field returning a connection must have the arguments first and after, last and before, or both")
+	}
+}
+
+// validatePaginationArguments validates a pair of pagination arguments and returns whether the field has them
+// a field with only one of the arguments is reported
+func (c *connectionSpecVisitor) validatePaginationArguments(typeName, fieldName string, fieldRef int, countArgumentName, cursorArgumentName string) bool {
+	countRef, hasCount := c.fieldArgument(fieldRef, countArgumentName)
+	cursorRef, hasCursor := c.fieldArgument(fieldRef, cursorArgumentName)
+
+	switch {
+	case !hasCount && !hasCursor:
+		return false
+	case !hasCount:
+		c.report(typeName, fieldName, "the argument "+cursorArgumentName+" requires the argument "+countArgumentName)
+	case !hasCursor:
+		c.report(typeName, fieldName, "the argument "+countArgumentName+" requires the argument "+cursorArgumentName)
+	}
+
+	if hasCount {
+		countType := c.definition.InputValueDefinitionType(countRef)
+		if c.definition.TypeIsList(countType) || c.definition.ResolveTypeNameString(countType) != "Int" {
+			c.report(typeName, fieldName, "the argument "+countArgumentName+" must be of type Int")
+		}
+	}
+	if hasCursor && !c.isScalar(c.definition.InputValueDefinitionType(cursorRef)) {
+		c.report(typeName, fieldName, "the argument "+cursorArgumentName+" must be a scalar")
+	}
+	return true
+}
+
+func (c *connectionSpecVisitor) fieldArgument(fieldRef int, argumentName string) (ref int, ok bool) {
+	for _, ref := range c.definition.FieldDefinitionArgumentsDefinitions(fieldRef) {
+		if c.definition.InputValueDefinitionNameString(ref) == argumentName {
+			return ref, true
+		}
+	}
+	return ast.InvalidRef, false
+}
+
+// isConnection returns true if the type is a connection object type, lists of connections are not connection fields
+func (c *connectionSpecVisitor) isConnection(typeRef int) bool {
+	if c.definition.TypeIsList(typeRef) {
+		return false
+	}
+	typeName := c.definition.ResolveTypeNameString(typeRef)
+	if !strings.HasSuffix(typeName, connectionTypeSuffix) || typeName == connectionTypeSuffix {
+		return false
+	}
+	_, isObject := c.objectTypeDefinition(typeRef)
+	return isObject
+}
+
+func (c *connectionSpecVisitor) isScalar(typeRef int) bool {
+	if c.definition.TypeIsList(typeRef) {
+		return false
+	}
+	node, ok := c.definition.Index.FirstNodeByNameStr(c.definition.ResolveTypeNameString(typeRef))
+	return ok && node.Kind == ast.NodeKindScalarTypeDefinition
+}
+
+func (c *connectionSpecVisitor) objectTypeDefinition(typeRef int) (ast.Node, bool) {
+	node, ok := c.definition.Index.FirstNodeByNameStr(c.definition.ResolveTypeNameString(typeRef))
+	return node, ok && node.Kind == ast.NodeKindObjectTypeDefinition
+}
+
+func (c *connectionSpecVisitor) report(typeName, fieldName, reason string) {
+	c.Report.AddExternalError(operationreport.ErrConnectionSpecViolation(typeName, fieldName, reason))
+}
@@ -0,0 +1,176 @@
+package astvalidation
+
+import (
+	"testing"
+)
+
+func TestConnectionSpec(t *testing.T) {
+	const pageInfo = `
+		type PageInfo {
+			hasPreviousPage: Boolean!
+			hasNextPage: Boolean!
+			startCursor: String
+			endCursor: String
+		}
+	`
+
+	const connection = `
+		type UserConnection {
+			edges: [UserEdge]
+			pageInfo: PageInfo!
+		}
+
+		type UserEdge {
+			node: User
+			cursor: String!
+		}
+
+		type User {
+			name: String
+		}
+	`
+
+	t.Run("Definition", func(t *testing.T) {
+		t.Run("Connections with forward and backward pagination are valid", func(t *testing.T) {
+			runDefinitionValidation(t, pageInfo+connection+`
+					type Query {
+						users(first: Int, after: String, last: Int, before: String): UserConnection!
+						usersForward(first: Int!, after: String): UserConnection
+						usersBackward(last: Int, before: String): UserConnection
+					}
+				`, Valid, ConnectionSpec(),
+			)
+		})
+
+		t.Run("Types without connections are valid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					type Query {
+						users: [User]
+					}
+
+					type User {
+						name: String
+					}
+				`, Valid, ConnectionSpec(),
+			)
+		})
+
+		t.Run("Connection without edges is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, pageInfo+`
+					type UserConnection {
+						pageInfo: PageInfo!
+					}
+				`, Invalid, ConnectionSpec(),
+			)
+		})
+
+		t.Run("Connection with edges not returning a list is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, pageInfo+`
+					type UserConnection {
+						edges: UserEdge
+						pageInfo: PageInfo!
+					}
+
+					type UserEdge {
+						node: String
+						cursor: String!
+					}
+				`, Invalid, ConnectionSpec(),
+			)
+		})
+
+		t.Run("Connection with nullable pageInfo is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, pageInfo+`
+					type UserConnection {
+						edges: [UserEdge]
+						pageInfo: PageInfo
+					}
+
+					type UserEdge {
+						node: String
+						cursor: String!
+					}
+				`, Invalid, ConnectionSpec(),
+			)
+		})
+
+		t.Run("Edge without cursor is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, pageInfo+`
+					type UserConnection {
+						edges: [UserEdge]
+						pageInfo: PageInfo!
+					}
+
+					type UserEdge {
+						node: String
+					}
+				`, Invalid, ConnectionSpec(),
+			)
+		})
+
+		t.Run("Edge with list node is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, pageInfo+`
+					type UserConnection {
+						edges: [UserEdge]
+						pageInfo: PageInfo!
+					}
+
+					type UserEdge {
+						node: [String]
+						cursor: String!
+					}
+				`, Invalid, ConnectionSpec(),
+			)
+		})
+
+		t.Run("PageInfo with nullable hasNextPage is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					type PageInfo {
+						hasPreviousPage: Boolean!
+						hasNextPage: Boolean
+						startCursor: String
+						endCursor: String
+					}
+				`, Invalid, ConnectionSpec(),
+			)
+		})
+
+		t.Run("PageInfo without endCursor is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					type PageInfo {
+						hasPreviousPage: Boolean!
+						hasNextPage: Boolean!
+						startCursor: String
+					}
+				`, Invalid, ConnectionSpec(),
+			)
+		})
+
+		t.Run("Connection field without pagination arguments is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, pageInfo+connection+`
+					type Query {
+						users: UserConnection
+					}
+				`, Invalid, ConnectionSpec(),
+			)
+		})
+
+		t.Run("Connection field with first but without after is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, pageInfo+connection+`
+					type Query {
+						users(first: Int): UserConnection
+					}
+				`, Invalid, ConnectionSpec(),
+			)
+		})
+
+		t.Run("Connection field with first of type String is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, pageInfo+connection+`
+					type Query {
+						users(first: String, after: String): UserConnection
+					}
+				`, Invalid, ConnectionSpec(),
+			)
+		})
+	})
+}
@@ -12,6 +12,7 @@ import (
 var (
 	ErrDocumentMustNotBeNil   = fmt.Errorf("document must not be nil")
 	ErrDefinitionMustNotBeNil = fmt.Errorf("definition must not be nil when walking operations")
+	ErrSelectionSetNotFound   = fmt.Errorf("selection set is not part of an operation or fragment definition of the document")
 )
 
 type SkipVisitors []int
@@ -1336,6 +1337,130 @@ func (w *Walker) SetVisitorFilter(filter VisitorFilter) {
 
 // Walk initiates the walker to start walking the AST from the top root Node
 func (w *Walker) Walk(document, definition *ast.Document, report *operationreport.Report) {
+	w.prepare(document, definition, report)
+	w.walk()
+}
+
+// WalkFragmentDefinition walks a single fragment definition of the document instead of the whole document
+// EnterDocument and LeaveDocument are called around the fragment definition
+func (w *Walker) WalkFragmentDefinition(ref int, document, definition *ast.Document, report *operationreport.Report) {
+	w.prepare(document, definition, report)
+	if !w.checkSubTreeDocuments() {
+		return
+	}
+
+	skipFor, ok := w.enterDocument()
+	if !ok {
+		return
+	}
+	w.walkFragmentDefinition(ref, skipFor)
+	if w.stop {
+		return
+	}
+	w.leaveDocument(skipFor)
+}
+
+// WalkSelectionSet walks a single selection set of an operation or fragment definition instead of the whole document
+// Ancestors, Path and EnclosingTypeDefinition are set up as if the walker reached the selection set during a full walk
+// EnterDocument and LeaveDocument are called around the selection set
+func (w *Walker) WalkSelectionSet(ref int, document, definition *ast.Document, report *operationreport.Report) {
+	w.prepare(document, definition, report)
+	if !w.checkSubTreeDocuments() {
+		return
+	}
+
+	ancestors, ok := w.selectionSetAncestors(ref)
+	if !ok {
+		w.Report.AddInternalError(ErrSelectionSetNotFound)
+		return
+	}
+
+	skipFor, ok := w.enterDocument()
+	if !ok {
+		return
+	}
+	for _, ancestor := range ancestors {
+		w.appendAncestor(ancestor.Ref, ancestor.Kind)
+		if w.stop {
+			return
+		}
+	}
+	w.Depth = len(w.Ancestors)
+	w.walkSelectionSet(ref, skipFor)
+	if w.stop {
+		return
+	}
+	w.leaveDocument(skipFor)
+}
+
+func (w *Walker) checkSubTreeDocuments() bool {
+	if w.document == nil {
+		w.Report.AddInternalError(ErrDocumentMustNotBeNil)
+		return false
+	}
+	if w.definition == nil {
+		w.Report.AddInternalError(ErrDefinitionMustNotBeNil)
+		return false
+	}
+	return true
+}
+
+// selectionSetAncestors returns the ancestors of the selection set from the enclosing operation or fragment definition
+func (w *Walker) selectionSetAncestors(ref int) ([]ast.Node, bool) {
+	for _, root := range w.document.RootNodes {
+		var selectionSet int
+		switch root.Kind {
+		case ast.NodeKindOperationDefinition:
+			if !w.document.OperationDefinitions[root.Ref].HasSelections {
+				continue
+			}
+			selectionSet = w.document.OperationDefinitions[root.Ref].SelectionSet
+		case ast.NodeKindFragmentDefinition:
+			selectionSet = w.document.FragmentDefinitions[root.Ref].SelectionSet
+		default:
+			continue
+		}
+		if ancestors, ok := w.findSelectionSet(ref, selectionSet, []ast.Node{root}); ok {
+			return ancestors, true
+		}
+	}
+	return nil, false
+}
+
+func (w *Walker) findSelectionSet(ref, selectionSet int, ancestors []ast.Node) ([]ast.Node, bool) {
+	if selectionSet == ref {
+		return ancestors, true
+	}
+	ancestors = append(ancestors, ast.Node{Kind: ast.NodeKindSelectionSet, Ref: selectionSet})
+	for _, selection := range w.document.SelectionSets[selectionSet].SelectionRefs {
+		node := ast.Node{Ref: w.document.Selections[selection].Ref}
+		var nested int
+		switch w.document.Selections[selection].Kind {
+		case ast.SelectionKindField:
+			if !w.document.Fields[node.Ref].HasSelections {
+				continue
+			}
+			node.Kind = ast.NodeKindField
+			nested = w.document.Fields[node.Ref].SelectionSet
+		case ast.SelectionKindInlineFragment:
+			if !w.document.InlineFragments[node.Ref].HasSelections {
+				continue
+			}
+			node.Kind = ast.NodeKindInlineFragment
+			nested = w.document.InlineFragments[node.Ref].SelectionSet
+		default:
+			continue
+		}
+		// copy the ancestors so that sibling branches don't share the backing array
+		branch := append(append(make([]ast.Node, 0, len(ancestors)+1), ancestors...), node)
+		if found, ok := w.findSelectionSet(ref, nested, branch); ok {
+			return found, true
+		}
+	}
+	return nil, false
+}
+
+func (w *Walker) prepare(document, definition *ast.Document, report *operationreport.Report) {
 	if report == nil {
 		w.Report = &operationreport.Report{}
 	} else {
@@ -1348,7 +1473,6 @@ func (w *Walker) Walk(document, definition *ast.Document, report *operationrepor
 	w.definition = definition
 	w.Depth = 0
 	w.stop = false
-	w.walk()
 }
 
 // DefferOnEnterField runs the provided func() after the current batch of visitors
@@ -1507,27 +1631,9 @@ func (w *Walker) walk() {
 		return
 	}
 
-	skipFor := make(SkipVisitors, 0, 4)
-
-	for i := 0; i < len(w.visitors.enterDocument); {
-		allowedToVisit := w.filter == nil || w.filter.AllowVisitor(EnterDocument, 0, w.visitors.enterDocument[i], skipFor)
-		skipFor = newSkipVisitors(skipFor, w.visitors.enterDocument[i], allowedToVisit)
-
-		if allowedToVisit {
-			w.visitors.enterDocument[i].EnterDocument(w.document, w.definition)
-		}
-		if w.revisit {
-			w.revisit = false
-			continue
-		}
-		if w.stop {
-			return
-		}
-		if w.skip {
-			w.skip = false
-			return
-		}
-		i++
+	skipFor, ok := w.enterDocument()
+	if !ok {
+		return
 	}
 
 	for i := range w.document.RootNodes {
@@ -1585,6 +1691,38 @@ func (w *Walker) walk() {
 		}
 	}
 
+	w.leaveDocument(skipFor)
+}
+
+// enterDocument calls the EnterDocument visitors, walking continues if ok is true
+func (w *Walker) enterDocument() (skipFor SkipVisitors, ok bool) {
+	skipFor = make(SkipVisitors, 0, 4)
+
+	for i := 0; i < len(w.visitors.enterDocument); {
+		allowedToVisit := w.filter == nil || w.filter.AllowVisitor(EnterDocument, 0, w.visitors.enterDocument[i], skipFor)
+		skipFor = newSkipVisitors(skipFor, w.visitors.enterDocument[i], allowedToVisit)
+
+		if allowedToVisit {
+			w.visitors.enterDocument[i].EnterDocument(w.document, w.definition)
+		}
+		if w.revisit {
+			w.revisit = false
+			continue
+		}
+		if w.stop {
+			return skipFor, false
+		}
+		if w.skip {
+			w.skip = false
+			return skipFor, false
+		}
+		i++
+	}
+
+	return skipFor, true
+}
+
+func (w *Walker) leaveDocument(skipFor SkipVisitors) {
 	for i := len(w.visitors.leaveDocument) - 1; i > -1; {
 		ancestorAllowed := skipFor.Allow(w.visitors.leaveDocument[i])
 		allowedToVisit := w.filter == nil || w.filter.AllowVisitor(LeaveDocument, 0, w.visitors.leaveDocument[i], skipFor)
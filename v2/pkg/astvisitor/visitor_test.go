@@ -208,6 +208,115 @@ func (p *pathVisitor) EnterField(ref int) {
 	p.out.Write([]byte(fmt.Sprintf("EnterField: %s, path: %s\n", p.op.FieldNameUnsafeString(ref), p.Path)))
 }
 
+func TestWalker_WalkSubTree(t *testing.T) {
+	definition := unsafeparser.ParseGraphqlDocumentString(testDefinition)
+	operation := unsafeparser.ParseGraphqlDocumentString(`
+		query {
+			posts {
+				id
+				user {
+					id
+					... on User {
+						name
+					}
+				}
+			}
+		}
+
+		fragment UserFields on User {
+			id
+			posts {
+				description
+			}
+		}`)
+
+	walk := func(walkFn func(walker *astvisitor.Walker, report *operationreport.Report)) string {
+		walker := astvisitor.NewWalker(48)
+		buff := &bytes.Buffer{}
+		report := operationreport.Report{}
+		visitor := &subTreeVisitor{
+			Walker: &walker,
+			out:    buff,
+		}
+		walker.RegisterEnterDocumentVisitor(visitor)
+		walker.RegisterEnterFieldVisitor(visitor)
+		walkFn(&walker, &report)
+		if report.HasErrors() {
+			t.Fatal(report.Error())
+		}
+		return buff.String()
+	}
+
+	fieldSelectionSet := func(name string) int {
+		for i := range operation.Fields {
+			if operation.FieldNameString(i) == name {
+				return operation.Fields[i].SelectionSet
+			}
+		}
+		t.Fatalf("field %s not found", name)
+		return -1
+	}
+
+	t.Run("selection set of a field", func(t *testing.T) {
+		out := walk(func(walker *astvisitor.Walker, report *operationreport.Report) {
+			walker.WalkSelectionSet(fieldSelectionSet("user"), &operation, &definition, report)
+		})
+		expected := "EnterField: id, path: [query,posts,user], enclosing: User, ancestors: 6\n" +
+			"EnterField: name, path: [query,posts,user,$User], enclosing: User, ancestors: 8\n"
+		if out != expected {
+			t.Fatalf("want:\n%s\ngot:\n%s", expected, out)
+		}
+	})
+
+	t.Run("selection set of a fragment definition", func(t *testing.T) {
+		out := walk(func(walker *astvisitor.Walker, report *operationreport.Report) {
+			walker.WalkSelectionSet(operation.FragmentDefinitions[0].SelectionSet, &operation, &definition, report)
+		})
+		expected := "EnterField: id, path: [User], enclosing: User, ancestors: 2\n" +
+			"EnterField: posts, path: [User], enclosing: User, ancestors: 2\n" +
+			"EnterField: description, path: [User,posts], enclosing: Post, ancestors: 4\n"
+		if out != expected {
+			t.Fatalf("want:\n%s\ngot:\n%s", expected, out)
+		}
+	})
+
+	t.Run("fragment definition", func(t *testing.T) {
+		out := walk(func(walker *astvisitor.Walker, report *operationreport.Report) {
+			walker.WalkFragmentDefinition(0, &operation, &definition, report)
+		})
+		expected := "EnterField: id, path: [User], enclosing: User, ancestors: 2\n" +
+			"EnterField: posts, path: [User], enclosing: User, ancestors: 2\n" +
+			"EnterField: description, path: [User,posts], enclosing: Post, ancestors: 4\n"
+		if out != expected {
+			t.Fatalf("want:\n%s\ngot:\n%s", expected, out)
+		}
+	})
+
+	t.Run("unknown selection set", func(t *testing.T) {
+		walker := astvisitor.NewWalker(48)
+		report := operationreport.Report{}
+		walker.WalkSelectionSet(len(operation.SelectionSets), &operation, &definition, &report)
+		if !report.HasErrors() {
+			t.Fatal("expected an error for an unknown selection set")
+		}
+	})
+}
+
+type subTreeVisitor struct {
+	*astvisitor.Walker
+	out     *bytes.Buffer
+	op, def *ast.Document
+}
+
+func (s *subTreeVisitor) EnterDocument(operation, definition *ast.Document) {
+	s.op, s.def = operation, definition
+}
+
+func (s *subTreeVisitor) EnterField(ref int) {
+	s.out.Write([]byte(fmt.Sprintf("EnterField: %s, path: %s, enclosing: %s, ancestors: %d\n",
+		s.op.FieldNameUnsafeString(ref), s.Path, s.def.NodeNameUnsafeString(s.EnclosingTypeDefinition), len(s.Ancestors))))
+}
+
 func TestVisitWithSkip(t *testing.T) {
 
 	definition := unsafeparser.ParseGraphqlDocumentString(testDefinition)
@@ -0,0 +1,251 @@
+// Package debug provides a http.Handler serving the runtime state of an ExecutionEngineV2, similar to net/http/pprof.
+// The endpoints expose internals of the engine, so they are only served through an auth middleware supplied by the caller:
+//
+//	handler, err := debug.NewHandler(engine, debug.Options{Auth: requireAdmin})
+//	mux.Handle(debug.DefaultPrefix+"/", handler)
+//
+// All endpoints respond to GET requests with json:
+//
+//	/debug/engine/               all of the state below
+//	/debug/engine/config         the hash of the loaded configuration
+//	/debug/engine/plancache      the size of the plan cache and its hits and misses
+//	/debug/engine/subscriptions  the active subscriptions and triggers
+//	/debug/engine/datasources    the health of the datasources, if Options.DataSourceTelemetry is set
+//	/debug/engine/slowoperations the recent slow operations, see graphql.EngineV2Configuration.SetSlowOperationLog
+//	/debug/engine/goroutines     the tracked goroutines and their leaks, if Options.GoroutineTracker is set
+package debug
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+// DefaultPrefix is the path the endpoints are served under if Options.Prefix is empty
+const DefaultPrefix = "/debug/engine"
+
+// ErrMissingAuth is returned by NewHandler without an auth middleware, the endpoints are never served unprotected
+var ErrMissingAuth = errors.New("debug: an auth middleware is required")
+
+// Options configures the Handler
+type Options struct {
+	// Auth wraps all endpoints, it has to reject the requests of unauthorized callers. Required
+	Auth func(next http.Handler) http.Handler
+	// Prefix is the path of the endpoints, defaults to DefaultPrefix
+	Prefix string
+	// DataSourceTelemetry enables the datasources endpoint,
+	// it has to be the DataSourceMetrics of the engine, see graphql.EngineV2Configuration.SetDataSourceMetrics
+	DataSourceTelemetry *resolve.DataSourceTelemetry
+	// GoroutineTracker enables the goroutines endpoint,
+	// it has to be the tracker of the engine, see graphql.EngineV2Configuration.SetGoroutineTracker
+	GoroutineTracker *resolve.GoroutineTracker
+}
+
+// State is the runtime state of the engine served by the index endpoint
+type State struct {
+	Config        Config                      `json:"config"`
+	PlanCache     PlanCache                   `json:"planCache"`
+	Subscriptions Subscriptions               `json:"subscriptions"`
+	DataSources   map[string]DataSourceHealth `json:"dataSources,omitempty"`
+	// SlowOperations are the operations of the slow operation log of the engine, the most recent operation first
+	SlowOperations []graphql.SlowOperation `json:"slowOperations"`
+	Goroutines     *Goroutines             `json:"goroutines,omitempty"`
+}
+
+// Config identifies the configuration loaded by the engine
+type Config struct {
+	Hash string `json:"hash"`
+}
+
+type PlanCache struct {
+	Size   int   `json:"size"`
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	// HitRate is the share of the lookups served by the cache, 0 without lookups
+	HitRate float64 `json:"hitRate"`
+}
+
+type Subscriptions struct {
+	Active   int64 `json:"active"`
+	Triggers int64 `json:"triggers"`
+}
+
+// Goroutines are the goroutines tracked by the GoroutineTracker of the engine
+type Goroutines struct {
+	Running int `json:"running"`
+	// Leaks are the goroutines still running after their operation or subscription was canceled, the oldest first
+	Leaks []resolve.GoroutineLeak `json:"leaks"`
+}
+
+// DataSourceHealthStatus is derived from the failed fetches of a datasource since the telemetry was created or reset
+type DataSourceHealthStatus string
+
+const (
+	DataSourceHealthy   DataSourceHealthStatus = "healthy"
+	DataSourceDegraded  DataSourceHealthStatus = "degraded"
+	DataSourceUnhealthy DataSourceHealthStatus = "unhealthy"
+)
+
+type DataSourceHealth struct {
+	Status          DataSourceHealthStatus `json:"status"`
+	Requests        uint64                 `json:"requests"`
+	Errors          uint64                 `json:"errors"`
+	ErrorRate       float64                `json:"errorRate"`
+	AverageDuration time.Duration          `json:"averageDurationNanos"`
+}
+
+// Handler serves the runtime state of an engine
+type Handler struct {
+	engine  *graphql.ExecutionEngineV2
+	options Options
+	handler http.Handler
+}
+
+// NewHandler creates a Handler for the engine, it fails with ErrMissingAuth if Options.Auth is not set
+func NewHandler(engine *graphql.ExecutionEngineV2, options Options) (*Handler, error) {
+	if options.Auth == nil {
+		return nil, ErrMissingAuth
+	}
+	options.Prefix = strings.TrimSuffix(options.Prefix, "/")
+	if options.Prefix == "" {
+		options.Prefix = DefaultPrefix
+	}
+
+	h := &Handler{
+		engine:  engine,
+		options: options,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(options.Prefix+"/", h.serveIndex)
+	mux.HandleFunc(options.Prefix+"/config", serveJSON(h.config))
+	mux.HandleFunc(options.Prefix+"/plancache", serveJSON(h.planCache))
+	mux.HandleFunc(options.Prefix+"/subscriptions", serveJSON(h.subscriptions))
+	mux.HandleFunc(options.Prefix+"/slowoperations", serveJSON(h.slowOperations))
+	if options.DataSourceTelemetry != nil {
+		mux.HandleFunc(options.Prefix+"/datasources", serveJSON(h.dataSources))
+	}
+	if options.GoroutineTracker != nil {
+		mux.HandleFunc(options.Prefix+"/goroutines", serveJSON(h.goroutines))
+	}
+	h.handler = options.Auth(mux)
+	return h, nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.handler.ServeHTTP(w, r)
+}
+
+// State returns the runtime state of the engine
+func (h *Handler) State() State {
+	state := State{
+		Config:         h.config(),
+		PlanCache:      h.planCache(),
+		Subscriptions:  h.subscriptions(),
+		SlowOperations: h.slowOperations(),
+	}
+	if h.options.DataSourceTelemetry != nil {
+		state.DataSources = h.dataSources()
+	}
+	if h.options.GoroutineTracker != nil {
+		goroutines := h.goroutines()
+		state.Goroutines = &goroutines
+	}
+	return state
+}
+
+func (h *Handler) serveIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != h.options.Prefix+"/" {
+		http.NotFound(w, r)
+		return
+	}
+	serveJSON(h.State)(w, r)
+}
+
+func (h *Handler) config() Config {
+	return Config{
+		Hash: strconv.FormatUint(h.engine.ConfigHash(), 16),
+	}
+}
+
+func (h *Handler) planCache() PlanCache {
+	stats := h.engine.PlanCacheStats()
+	planCache := PlanCache{
+		Size:   stats.Size,
+		Hits:   stats.Hits,
+		Misses: stats.Misses,
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		planCache.HitRate = float64(stats.Hits) / float64(lookups)
+	}
+	return planCache
+}
+
+func (h *Handler) subscriptions() Subscriptions {
+	stats := h.engine.SubscriptionStats()
+	return Subscriptions{
+		Active:   stats.Subscriptions,
+		Triggers: stats.Triggers,
+	}
+}
+
+func (h *Handler) slowOperations() []graphql.SlowOperation {
+	operations := h.engine.SlowOperations()
+	if operations == nil {
+		return []graphql.SlowOperation{}
+	}
+	return operations
+}
+
+func (h *Handler) goroutines() Goroutines {
+	leaks := h.options.GoroutineTracker.Leaks()
+	if leaks == nil {
+		leaks = []resolve.GoroutineLeak{}
+	}
+	return Goroutines{
+		Running: h.options.GoroutineTracker.Running(),
+		Leaks:   leaks,
+	}
+}
+
+func (h *Handler) dataSources() map[string]DataSourceHealth {
+	snapshot := h.options.DataSourceTelemetry.Snapshot()
+	dataSources := make(map[string]DataSourceHealth, len(snapshot))
+	for id, counters := range snapshot {
+		health := DataSourceHealth{
+			Status:   DataSourceHealthy,
+			Requests: counters.Requests,
+			Errors:   counters.Errors,
+		}
+		if counters.Requests > 0 {
+			health.ErrorRate = float64(counters.Errors) / float64(counters.Requests)
+			health.AverageDuration = counters.Duration / time.Duration(counters.Requests)
+		}
+		switch {
+		case counters.Errors == 0:
+		case counters.Errors == counters.Requests:
+			health.Status = DataSourceUnhealthy
+		default:
+			health.Status = DataSourceDegraded
+		}
+		dataSources[id] = health
+	}
+	return dataSources
+}
+
+func serveJSON[T any](value func() T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(value())
+	}
+}
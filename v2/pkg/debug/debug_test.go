@@ -0,0 +1,177 @@
+package debug
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/staticdatasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+func TestHandler(t *testing.T) {
+	schema, err := graphql.NewSchemaFromString(`type Query { hello: String }`)
+	require.NoError(t, err)
+
+	telemetry := resolve.NewDataSourceTelemetry()
+	tracker := resolve.NewGoroutineTracker(0)
+	engineConfig := graphql.NewEngineV2Configuration(schema)
+	engineConfig.SetGoroutineTracker(tracker)
+	engineConfig.SetDataSourceMetrics(telemetry)
+	engineConfig.SetSlowOperationLog(graphql.NewSlowOperationLog(0, 1))
+	engineConfig.SetDataSources([]plan.DataSourceConfiguration{
+		{
+			ID: "hello",
+			RootNodes: []plan.TypeField{
+				{TypeName: "Query", FieldNames: []string{"hello"}},
+			},
+			Factory: &staticdatasource.Factory{},
+			Custom: staticdatasource.ConfigJSON(staticdatasource.Configuration{
+				Data: `{"hello":"world"}`,
+			}),
+		},
+	})
+	engine, err := graphql.NewExecutionEngineV2(context.Background(), abstractlogger.NoopLogger, engineConfig)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		resultWriter := graphql.NewEngineResultWriter()
+		require.NoError(t, engine.Execute(context.Background(), &graphql.Request{Query: `{ hello }`}, &resultWriter))
+	}
+	telemetry.RecordFetch("users", resolve.FetchStats{Duration: 2 * time.Millisecond, Failed: true})
+	telemetry.RecordFetch("users", resolve.FetchStats{Duration: 4 * time.Millisecond})
+
+	auth := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	handler, err := NewHandler(engine, Options{Auth: auth, DataSourceTelemetry: telemetry, GoroutineTracker: tracker})
+	require.NoError(t, err)
+
+	serve := func(method, path string, authorized bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, nil)
+		if authorized {
+			r.Header.Set("Authorization", "secret")
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	configHash := strconv.FormatUint(engine.ConfigHash(), 16)
+
+	t.Run("should require an auth middleware", func(t *testing.T) {
+		_, err := NewHandler(engine, Options{})
+		assert.ErrorIs(t, err, ErrMissingAuth)
+	})
+
+	t.Run("should reject unauthorized requests", func(t *testing.T) {
+		for _, path := range []string{"/debug/engine/", "/debug/engine/config", "/debug/engine/plancache", "/debug/engine/unknown"} {
+			assert.Equal(t, http.StatusUnauthorized, serve(http.MethodGet, path, false).Code, path)
+		}
+	})
+
+	t.Run("config", func(t *testing.T) {
+		w := serve(http.MethodGet, "/debug/engine/config", true)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"hash":"`+configHash+`"}`, w.Body.String())
+	})
+
+	t.Run("plan cache", func(t *testing.T) {
+		w := serve(http.MethodGet, "/debug/engine/plancache", true)
+		assert.JSONEq(t, `{"size":1,"hits":1,"misses":1,"hitRate":0.5}`, w.Body.String())
+	})
+
+	t.Run("subscriptions", func(t *testing.T) {
+		w := serve(http.MethodGet, "/debug/engine/subscriptions", true)
+		assert.JSONEq(t, `{"active":0,"triggers":0}`, w.Body.String())
+	})
+
+	t.Run("datasources", func(t *testing.T) {
+		w := serve(http.MethodGet, "/debug/engine/datasources", true)
+		assert.JSONEq(t, `{
+			"hello":{"status":"healthy","requests":2,"errors":0,"errorRate":0,"averageDurationNanos":`+strconv.FormatInt(int64(handler.State().DataSources["hello"].AverageDuration), 10)+`},
+			"users":{"status":"degraded","requests":2,"errors":1,"errorRate":0.5,"averageDurationNanos":3000000}
+		}`, w.Body.String())
+	})
+
+	t.Run("slow operations", func(t *testing.T) {
+		w := serve(http.MethodGet, "/debug/engine/slowoperations", true)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"query":"{hello}"`)
+		assert.Contains(t, w.Body.String(), `"DataSourceID":"hello"`)
+	})
+
+	t.Run("goroutines", func(t *testing.T) {
+		// the event loop of the resolver runs until the engine is closed
+		w := serve(http.MethodGet, "/debug/engine/goroutines", true)
+		assert.JSONEq(t, `{"running":1,"leaks":[]}`, w.Body.String())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := tracker.Track(ctx, "leaking")
+		defer done()
+		cancel()
+
+		assert.Eventually(t, func() bool {
+			return len(handler.State().Goroutines.Leaks) == 1
+		}, time.Second, time.Millisecond)
+		w = serve(http.MethodGet, "/debug/engine/goroutines", true)
+		assert.Contains(t, w.Body.String(), `"running":2,"leaks":[{"name":"leaking"`)
+	})
+
+	t.Run("index", func(t *testing.T) {
+		w := serve(http.MethodGet, "/debug/engine/", true)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"config":{"hash":"`+configHash+`"}`)
+		assert.Contains(t, w.Body.String(), `"planCache":{"size":1,"hits":1,"misses":1,"hitRate":0.5}`)
+		assert.Contains(t, w.Body.String(), `"users":{"status":"degraded"`)
+
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/debug/engine/unknown", true).Code)
+	})
+
+	t.Run("should only serve GET requests", func(t *testing.T) {
+		w := serve(http.MethodPost, "/debug/engine/config", true)
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Equal(t, http.MethodGet, w.Header().Get("Allow"))
+	})
+
+	t.Run("datasources endpoint requires telemetry", func(t *testing.T) {
+		withoutTelemetry, err := NewHandler(engine, Options{Auth: auth, Prefix: "/internal/"})
+		require.NoError(t, err)
+		r := httptest.NewRequest(http.MethodGet, "/internal/datasources", nil)
+		r.Header.Set("Authorization", "secret")
+		w := httptest.NewRecorder()
+		withoutTelemetry.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Nil(t, withoutTelemetry.State().DataSources)
+	})
+
+	t.Run("slow operations without a log", func(t *testing.T) {
+		engine, err := graphql.NewExecutionEngineV2(context.Background(), abstractlogger.NoopLogger, graphql.NewEngineV2Configuration(schema))
+		require.NoError(t, err)
+		handler, err := NewHandler(engine, Options{Auth: auth})
+		require.NoError(t, err)
+
+		r := httptest.NewRequest(http.MethodGet, "/debug/engine/slowoperations", nil)
+		r.Header.Set("Authorization", "secret")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		assert.Equal(t, "[]\n", w.Body.String())
+	})
+}
@@ -0,0 +1,333 @@
+package graphql_datasource
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/buger/jsonparser"
+	log "github.com/jensneuse/abstractlogger"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+const (
+	callbackProtocolHeader = "subscription-protocol"
+	callbackProtocol       = "callback/1.0"
+	callbackAcceptHeader   = "application/json;callbackSpec=1.0"
+
+	callbackKindSubscription = "subscription"
+	callbackActionCheck      = "check"
+	callbackActionHeartbeat  = "heartbeat"
+	callbackActionNext       = "next"
+	callbackActionComplete   = "complete"
+
+	heartbeatTimeoutError = `{"errors":[{"message":"subscription callback heartbeat timeout"}]}`
+)
+
+// SubscriptionCallbackHandler implements the subscription callback protocol of Apollo Federation.
+// Instead of holding a connection to the subgraph, a subscription is registered with a callback URL
+// and the subgraph sends the events of the subscription as HTTP requests to the callback URL:
+//
+//	check     the subgraph verifies the callback URL before it accepts the subscription
+//	heartbeat the subgraph signals that the subscription is still alive
+//	next      the subgraph sends the next response of the subscription
+//	complete  the subgraph ends the subscription, optionally with errors
+//
+// The handler has to be served at PublicURL, the callback URL of a subscription is PublicURL followed by the id of the subscription.
+// Subscriptions without a heartbeat within twice the heartbeat interval are terminated.
+// Callbacks of unknown or terminated subscriptions are answered with 404, so the subgraph stops sending events.
+type SubscriptionCallbackHandler struct {
+	publicURL         string
+	heartbeatInterval time.Duration
+
+	mu            sync.Mutex
+	subscriptions map[string]*callbackSubscription
+}
+
+// NewSubscriptionCallbackHandler creates a handler for the callbacks sent to publicURL,
+// a heartbeatInterval of 0 disables heartbeats
+func NewSubscriptionCallbackHandler(publicURL string, heartbeatInterval time.Duration) *SubscriptionCallbackHandler {
+	return &SubscriptionCallbackHandler{
+		publicURL:         strings.TrimSuffix(publicURL, "/"),
+		heartbeatInterval: heartbeatInterval,
+		subscriptions:     map[string]*callbackSubscription{},
+	}
+}
+
+type callbackSubscription struct {
+	id       string
+	verifier string
+	// alive is signaled by every check, heartbeat and next callback
+	alive    chan struct{}
+	messages chan callbackMessage
+	// done is closed when the subscription is unregistered
+	done chan struct{}
+}
+
+type callbackMessage struct {
+	Kind     string          `json:"kind"`
+	Action   string          `json:"action"`
+	ID       string          `json:"id"`
+	Verifier string          `json:"verifier"`
+	Payload  json.RawMessage `json:"payload,omitempty"`
+	Errors   json.RawMessage `json:"errors,omitempty"`
+}
+
+type callbackExtension struct {
+	CallbackURL         string `json:"callbackUrl"`
+	SubscriptionID      string `json:"subscriptionId"`
+	Verifier            string `json:"verifier"`
+	HeartbeatIntervalMs int64  `json:"heartbeatIntervalMs"`
+}
+
+// Subscriptions returns the number of registered subscriptions
+func (h *SubscriptionCallbackHandler) Subscriptions() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscriptions)
+}
+
+func (h *SubscriptionCallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	var message callbackMessage
+	if err := json.NewDecoder(r.Body).Decode(&message); err != nil || message.Kind != callbackKindSubscription {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if message.ID != path.Base(r.URL.Path) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	sub := h.subscriptions[message.ID]
+	h.mu.Unlock()
+	if sub == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if message.Verifier != sub.verifier {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set(callbackProtocolHeader, callbackProtocol)
+	switch message.Action {
+	case callbackActionCheck, callbackActionHeartbeat:
+		sub.signalAlive()
+		w.WriteHeader(http.StatusNoContent)
+	case callbackActionNext:
+		sub.signalAlive()
+		if !sub.deliver(message) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	case callbackActionComplete:
+		if !sub.deliver(message) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (s *callbackSubscription) signalAlive() {
+	select {
+	case s.alive <- struct{}{}:
+	default:
+	}
+}
+
+// deliver hands the message to the subscription, it returns false if the subscription is terminated
+func (s *callbackSubscription) deliver(message callbackMessage) bool {
+	select {
+	case s.messages <- message:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+func (h *SubscriptionCallbackHandler) register() (*callbackSubscription, error) {
+	id, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	sub := &callbackSubscription{
+		id:       id,
+		verifier: verifier,
+		alive:    make(chan struct{}, 1),
+		messages: make(chan callbackMessage),
+		done:     make(chan struct{}),
+	}
+	h.mu.Lock()
+	h.subscriptions[id] = sub
+	h.mu.Unlock()
+	return sub, nil
+}
+
+func (h *SubscriptionCallbackHandler) unregister(sub *callbackSubscription) {
+	h.mu.Lock()
+	delete(h.subscriptions, sub.id)
+	h.mu.Unlock()
+	close(sub.done)
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// StartBlocking sends the subscription request to the subgraph and forwards the callbacks of the subscription to the updater
+// until the subscription is completed, the heartbeat times out or the context of the subscription is done
+func (h *SubscriptionCallbackHandler) StartBlocking(client *http.Client, sub Subscription, l log.Logger) {
+	defer sub.updater.Done()
+
+	callback, err := h.register()
+	if err != nil {
+		l.Error("failed to register subscription callback", log.Error(err))
+		sub.updater.Update([]byte(internalError))
+		return
+	}
+	defer h.unregister(callback)
+
+	subscribed := make(chan []byte, 1)
+	done := resolve.TrackGoroutine(sub.ctx, "graphql_datasource: callback subscription request")
+	go func() {
+		defer done()
+		subscribed <- h.subscribe(client, sub, callback, l)
+	}()
+
+	var (
+		heartbeatTimer   *time.Timer
+		heartbeatTimeout <-chan time.Time
+	)
+	if h.heartbeatInterval > 0 {
+		heartbeatTimer = time.NewTimer(2 * h.heartbeatInterval)
+		defer heartbeatTimer.Stop()
+		heartbeatTimeout = heartbeatTimer.C
+	}
+
+	for {
+		select {
+		case response := <-subscribed:
+			if response != nil {
+				sub.updater.Update(response)
+				return
+			}
+		case <-callback.alive:
+			if heartbeatTimer != nil {
+				heartbeatTimer.Reset(2 * h.heartbeatInterval)
+			}
+		case message := <-callback.messages:
+			switch message.Action {
+			case callbackActionNext:
+				sub.updater.Update(message.Payload)
+			case callbackActionComplete:
+				if len(message.Errors) != 0 && !bytes.Equal(message.Errors, []byte("null")) {
+					response, _ := jsonparser.Set([]byte(`{}`), message.Errors, "errors")
+					sub.updater.Update(response)
+				}
+				return
+			}
+		case <-heartbeatTimeout:
+			l.Error("subscription callback heartbeat timeout", log.String("id", callback.id))
+			sub.updater.Update([]byte(heartbeatTimeoutError))
+			return
+		case <-sub.ctx.Done():
+			return
+		}
+	}
+}
+
+// subscribe sends the subscription request with the callback extension,
+// it returns the response to send to the subscriber if the subgraph didn't accept the subscription
+func (h *SubscriptionCallbackHandler) subscribe(client *http.Client, sub Subscription, callback *callbackSubscription, l log.Logger) []byte {
+	extension, err := json.Marshal(callbackExtension{
+		CallbackURL:         h.publicURL + "/" + callback.id,
+		SubscriptionID:      callback.id,
+		Verifier:            callback.verifier,
+		HeartbeatIntervalMs: h.heartbeatInterval.Milliseconds(),
+	})
+	if err != nil {
+		return []byte(internalError)
+	}
+	body := sub.options.Body
+	extensions := []byte(body.Extensions)
+	if len(extensions) == 0 {
+		extensions = []byte(`{}`)
+	}
+	if body.Extensions, err = jsonparser.Set(extensions, extension, "subscription"); err != nil {
+		return []byte(internalError)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return []byte(internalError)
+	}
+
+	req, err := http.NewRequestWithContext(sub.ctx, http.MethodPost, sub.options.URL, bytes.NewReader(data))
+	if err != nil {
+		return []byte(internalError)
+	}
+	if sub.options.Header != nil {
+		req.Header = sub.options.Header.Clone()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", callbackAcceptHeader)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if sub.ctx.Err() != nil {
+			// the subscription is done, the response is not sent
+			return nil
+		}
+		l.Error("failed to perform subscription callback request", log.Error(err))
+		return []byte(internalError)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	response, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return []byte(internalError)
+	}
+	if resp.StatusCode != http.StatusOK {
+		l.Error("subscription callback request failed", log.Error(fmt.Errorf("unexpected status code: %d", resp.StatusCode)))
+		return []byte(internalError)
+	}
+	errors, dataType, _, err := jsonparser.Get(response, "errors")
+	if err != nil || dataType != jsonparser.Array {
+		return nil
+	}
+	count := 0
+	_, _ = jsonparser.ArrayEach(errors, func(_ []byte, _ jsonparser.ValueType, _ int, _ error) {
+		count++
+	})
+	if count == 0 {
+		return nil
+	}
+	out, _ := jsonparser.Set([]byte(`{}`), errors, "errors")
+	return out
+}
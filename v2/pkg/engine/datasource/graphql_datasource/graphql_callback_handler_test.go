@@ -0,0 +1,203 @@
+package graphql_datasource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/buger/jsonparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+// callbackSubgraph implements the subgraph side of the subscription callback protocol
+type callbackSubgraph struct {
+	t         *testing.T
+	server    *httptest.Server
+	requests  chan callbackSubgraphRequest
+	response  string
+	skipCheck bool
+}
+
+type callbackSubgraphRequest struct {
+	header    http.Header
+	body      []byte
+	extension callbackExtension
+}
+
+func newCallbackSubgraph(t *testing.T, response string) *callbackSubgraph {
+	s := &callbackSubgraph{
+		t:        t,
+		requests: make(chan callbackSubgraphRequest, 1),
+		response: response,
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		request := callbackSubgraphRequest{
+			header: r.Header,
+			body:   body,
+		}
+		extension, _, _, _ := jsonparser.Get(body, "extensions", "subscription")
+		require.NoError(t, json.Unmarshal(extension, &request.extension))
+		if !s.skipCheck {
+			status, header := s.send(request.extension, callbackActionCheck, "")
+			assert.Equal(t, http.StatusNoContent, status)
+			assert.Equal(t, callbackProtocol, header.Get(callbackProtocolHeader))
+		}
+		s.requests <- request
+		_, _ = w.Write([]byte(s.response))
+	}))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *callbackSubgraph) send(extension callbackExtension, action, fields string) (int, http.Header) {
+	message := `{"kind":"subscription","action":"` + action + `","id":"` + extension.SubscriptionID + `","verifier":"` + extension.Verifier + `"` + fields + `}`
+	resp, err := http.Post(extension.CallbackURL, "application/json", bytes.NewReader([]byte(message)))
+	require.NoError(s.t, err)
+	_ = resp.Body.Close()
+	return resp.StatusCode, resp.Header
+}
+
+func TestGraphQLSubscriptionClientSubscribe_Callback(t *testing.T) {
+	newClient := func(t *testing.T, heartbeatInterval time.Duration) (*SubscriptionClient, *SubscriptionCallbackHandler) {
+		mux := http.NewServeMux()
+		router := httptest.NewServer(mux)
+		t.Cleanup(router.Close)
+		handler := NewSubscriptionCallbackHandler(router.URL+"/callback/", heartbeatInterval)
+		mux.Handle("/callback/", handler)
+		client := NewGraphQLSubscriptionClient(http.DefaultClient, http.DefaultClient, context.Background(),
+			WithLogger(logger()),
+			WithSubscriptionCallbackHandler(handler),
+		)
+		return client, handler
+	}
+
+	subscribe := func(t *testing.T, ctx context.Context, client *SubscriptionClient, url string) *testSubscriptionUpdater {
+		t.Helper()
+		updater := &testSubscriptionUpdater{}
+		source := &SubscriptionSource{client: client}
+		input := `{"url":"` + url + `","body":{"query":"subscription {messageAdded(roomName: \"room\"){text}}","extensions":{"persistedQuery":{"version":1}}},"header":{"Authorization":["token"]},"use_callback":true}`
+		require.NoError(t, source.Start(resolve.NewContext(ctx), []byte(input), updater))
+		return updater
+	}
+
+	t.Run("should forward the callbacks until the subscription is completed", func(t *testing.T) {
+		client, handler := newClient(t, time.Second)
+		subgraph := newCallbackSubgraph(t, `{"data":null}`)
+
+		updater := subscribe(t, context.Background(), client, subgraph.server.URL)
+		request := <-subgraph.requests
+
+		assert.Equal(t, callbackAcceptHeader, request.header.Get("Accept"))
+		assert.Equal(t, "token", request.header.Get("Authorization"))
+		persistedQuery, _, _, _ := jsonparser.Get(request.body, "extensions", "persistedQuery")
+		assert.Equal(t, `{"version":1}`, string(persistedQuery))
+		assert.Equal(t, int64(1000), request.extension.HeartbeatIntervalMs)
+		assert.NotEmpty(t, request.extension.Verifier)
+		assert.Contains(t, request.extension.CallbackURL, "/callback/"+request.extension.SubscriptionID)
+
+		status, _ := subgraph.send(request.extension, callbackActionHeartbeat, "")
+		assert.Equal(t, http.StatusNoContent, status)
+		status, _ = subgraph.send(request.extension, callbackActionNext, `,"payload":{"data":{"messageAdded":{"text":"first"}}}`)
+		assert.Equal(t, http.StatusOK, status)
+		status, _ = subgraph.send(request.extension, callbackActionNext, `,"payload":{"data":{"messageAdded":{"text":"second"}}}`)
+		assert.Equal(t, http.StatusOK, status)
+		status, _ = subgraph.send(request.extension, callbackActionComplete, "")
+		assert.Equal(t, http.StatusAccepted, status)
+
+		updater.AwaitDone(t, time.Second)
+		assert.Equal(t, []string{
+			`{"data":{"messageAdded":{"text":"first"}}}`,
+			`{"data":{"messageAdded":{"text":"second"}}}`,
+		}, updater.updates)
+		assert.Equal(t, 0, handler.Subscriptions())
+
+		status, _ = subgraph.send(request.extension, callbackActionHeartbeat, "")
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("should send the errors of the complete callback", func(t *testing.T) {
+		client, _ := newClient(t, 0)
+		subgraph := newCallbackSubgraph(t, `{"data":null}`)
+
+		updater := subscribe(t, context.Background(), client, subgraph.server.URL)
+		request := <-subgraph.requests
+
+		status, _ := subgraph.send(request.extension, callbackActionComplete, `,"errors":[{"message":"room closed"}]`)
+		assert.Equal(t, http.StatusAccepted, status)
+
+		updater.AwaitDone(t, time.Second)
+		assert.Equal(t, []string{`{"errors":[{"message":"room closed"}]}`}, updater.updates)
+	})
+
+	t.Run("should reject callbacks with an invalid verifier or id", func(t *testing.T) {
+		client, _ := newClient(t, 0)
+		subgraph := newCallbackSubgraph(t, `{"data":null}`)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		updater := subscribe(t, ctx, client, subgraph.server.URL)
+		request := <-subgraph.requests
+
+		invalidVerifier := request.extension
+		invalidVerifier.Verifier = "invalid"
+		status, _ := subgraph.send(invalidVerifier, callbackActionNext, `,"payload":{"data":null}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		unknownID := request.extension
+		unknownID.SubscriptionID = "unknown"
+		unknownID.CallbackURL = request.extension.CallbackURL[:len(request.extension.CallbackURL)-len(request.extension.SubscriptionID)] + "unknown"
+		status, _ = subgraph.send(unknownID, callbackActionHeartbeat, "")
+		assert.Equal(t, http.StatusNotFound, status)
+
+		cancel()
+		updater.AwaitDone(t, time.Second)
+		assert.Empty(t, updater.updates)
+	})
+
+	t.Run("should terminate the subscription without heartbeats", func(t *testing.T) {
+		client, handler := newClient(t, 10*time.Millisecond)
+		subgraph := newCallbackSubgraph(t, `{"data":null}`)
+
+		updater := subscribe(t, context.Background(), client, subgraph.server.URL)
+		request := <-subgraph.requests
+
+		updater.AwaitDone(t, time.Second)
+		assert.Equal(t, []string{heartbeatTimeoutError}, updater.updates)
+		assert.Equal(t, 0, handler.Subscriptions())
+
+		status, _ := subgraph.send(request.extension, callbackActionNext, `,"payload":{"data":null}`)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("should send the errors of a rejected subscription", func(t *testing.T) {
+		client, handler := newClient(t, 0)
+		subgraph := newCallbackSubgraph(t, `{"errors":[{"message":"callback protocol not supported"}]}`)
+		subgraph.skipCheck = true
+
+		updater := subscribe(t, context.Background(), client, subgraph.server.URL)
+		<-subgraph.requests
+
+		updater.AwaitDone(t, time.Second)
+		assert.Equal(t, []string{`{"errors":[{"message":"callback protocol not supported"}]}`}, updater.updates)
+		assert.Equal(t, 0, handler.Subscriptions())
+	})
+
+	t.Run("should fail without callback handler", func(t *testing.T) {
+		client := NewGraphQLSubscriptionClient(http.DefaultClient, http.DefaultClient, context.Background())
+		err := client.Subscribe(resolve.NewContext(context.Background()), GraphQLSubscriptionOptions{
+			URL:         "http://localhost",
+			Body:        GraphQLBody{Query: `subscription {messageAdded(roomName: "room"){text}}`},
+			UseCallback: true,
+		}, &testSubscriptionUpdater{})
+		assert.EqualError(t, err, "subscription callback handler is nil")
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"regexp"
 	"slices"
+	"sync"
 
 	"github.com/buger/jsonparser"
 	"github.com/cespare/xxhash/v2"
@@ -61,7 +62,9 @@ type Planner struct {
 	nodes                              []ast.Node
 	variables                          resolve.Variables
 	lastFieldEnclosingTypeName         string
+	factory                            *Factory
 	fetchClient                        *http.Client
+	upstreams                          *httpclient.UpstreamGroup
 	subscriptionClient                 GraphQLSubscriptionClient
 	rootTypeName                       string // rootTypeName - holds name of top level type
 	rootFieldName                      string // rootFieldName - holds name of root type field
@@ -182,6 +185,12 @@ func (p *Planner) addDirectiveToNode(directiveRef int, node ast.Node) {
 	if !p.visitor.Definition.DirectiveIsAllowedOnNodeKind(directiveName, node.Kind, operationType) {
 		return
 	}
+	if p.visitor.Config.ListPostProcessing.IsListPostProcessingDirective(directiveName) {
+		return
+	}
+	if p.visitor.Config.DataSourceDirective.IsDataSourceDirective(directiveName) {
+		return
+	}
 	upstreamDirectiveName := p.dataSourceConfig.Directives.RenameTypeNameOnMatchStr(directiveName)
 	if p.upstreamDefinition != nil && !p.upstreamDefinition.DirectiveIsAllowedOnNodeKind(upstreamDirectiveName, node.Kind, operationType) {
 		return
@@ -306,6 +315,10 @@ type Configuration struct {
 	Federation             FederationConfiguration
 	UpstreamSchema         string
 	CustomScalarTypeFields []SingleTypeField
+	OperationNaming        OperationNamingConfiguration
+	// ProxyURL routes the fetches and subscriptions of the datasource through a HTTP(S) or SOCKS5 proxy
+	// instead of the proxy of the environment, e.g. "socks5://egress:1080"
+	ProxyURL string
 }
 
 type SingleTypeField struct {
@@ -323,6 +336,16 @@ type FederationConfiguration struct {
 	ServiceSDL string
 }
 
+// OperationNamingConfiguration makes the operations sent to the upstream identifiable in its logs and traces
+type OperationNamingConfiguration struct {
+	// Enabled names the upstream operations after the client operation and the planner, e.g. "query MyQuery__1 {...}"
+	// Upstream operations of anonymous client operations stay anonymous.
+	Enabled bool
+	// MetadataComment prepends a comment with the client operation name, the datasource ID and the planner ID, e.g.
+	// "# operation=MyQuery datasource=products planner=1"
+	MetadataComment bool
+}
+
 type SubscriptionConfiguration struct {
 	URL           string
 	UseSSE        bool
@@ -337,18 +360,61 @@ type SubscriptionConfiguration struct {
 	// which connections can be multiplexed together, but the subscription engine does not forward
 	// these headers by itself.
 	ForwardedClientHeaderRegularExpressions []*regexp.Regexp
+	// FetchInitialState sends the subscription operation as a query to the fetch URL for every new subscriber
+	// and sends the result as the first message, before the updates of the subscription.
+	// The Query type of the upstream must have fields with the same names, arguments and types as the subscription.
+	FetchInitialState bool
+	// InstanceURLs are the URLs of the instances of a horizontally scaled upstream.
+	// If set, every subscription is routed to one of them by consistent hashing of the StickyRouting key,
+	// so that the events of subscriptions with the same key are delivered by the same instance in order.
+	// Subscriptions without a key use URL.
+	InstanceURLs  []string
+	StickyRouting StickyRoutingConfiguration
+	// UseCallback subscribes with the subscription callback protocol of Apollo Federation:
+	// the subgraph sends the events of the subscription to the SubscriptionCallbackHandler of the Factory
+	// instead of streaming them over a websocket or SSE connection.
+	UseCallback bool
 }
 
 type FetchConfiguration struct {
 	URL    string
 	Method string
 	Header http.Header
+	// FailoverURLs are additional URLs serving the same upstream as URL, e.g. instances in other regions.
+	// Queries fail over to the next URL if the upstream can't be reached or responds with a 5xx status code.
+	// Mutations only fail over if the connection failed before the request was written, so they are never executed twice.
+	FailoverURLs []string
+	// LoadBalancingStrategy defines the order in which URL and FailoverURLs are used. It defaults to priority.
+	LoadBalancingStrategy httpclient.LoadBalancingStrategy
+	// PersistedQueries sends the sha256 hash of the operation instead of the operation (automatic persisted queries).
+	// The operation is only sent if the upstream responds with PersistedQueryNotFound.
+	PersistedQueries bool
+	// UseGETForQueries sends query operations as GET requests with the operation in the URL, so that HTTP caches can cache them.
+	// Requests with a URL longer than MaxGETURLLength are sent as POST requests.
+	// Combined with PersistedQueries, the URL only contains the hash of the operation until the upstream asks for the operation.
+	UseGETForQueries bool
+	// MaxGETURLLength defaults to 2048
+	MaxGETURLLength int
+}
+
+// upstreamGroup returns the group of URL and FailoverURLs or nil if no failover is configured
+func (c *FetchConfiguration) upstreamGroup() *httpclient.UpstreamGroup {
+	if len(c.FailoverURLs) == 0 {
+		return nil
+	}
+	urls := make([]string, 0, len(c.FailoverURLs)+1)
+	urls = append(urls, c.URL)
+	urls = append(urls, c.FailoverURLs...)
+	return httpclient.NewUpstreamGroup(urls, c.LoadBalancingStrategy)
 }
 
 func (c *Configuration) ApplyDefaults() {
 	if c.Fetch.Method == "" {
 		c.Fetch.Method = "POST"
 	}
+	if c.Fetch.UseGETForQueries && c.Fetch.MaxGETURLLength == 0 {
+		c.Fetch.MaxGETURLLength = 2048
+	}
 }
 
 func (p *Planner) Register(visitor *plan.Visitor, configuration plan.DataSourceConfiguration, dataSourcePlannerConfiguration plan.DataSourcePlannerConfiguration) error {
@@ -370,6 +436,14 @@ func (p *Planner) Register(visitor *plan.Visitor, configuration plan.DataSourceC
 	}
 
 	p.config.ApplyDefaults()
+	p.upstreams = p.config.Fetch.upstreamGroup()
+
+	if p.factory != nil && (p.config.ProxyURL != "" || p.factory.EgressPolicy != nil) {
+		p.fetchClient, err = p.factory.fetchClient(p.config.ProxyURL)
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -377,7 +451,8 @@ func (p *Planner) Register(visitor *plan.Visitor, configuration plan.DataSourceC
 func (p *Planner) ConfigureFetch() resolve.FetchConfiguration {
 	var input []byte
 	input = httpclient.SetInputBodyWithPath(input, p.upstreamVariables, "variables")
-	input = httpclient.SetInputBodyWithPath(input, p.printOperation(), "query")
+	input = httpclient.SetInputBodyWithPath(input, p.addMetadataComment(escapeOperation(p.printOperation())), "query")
+	input = p.setPersistedQueryExtension(input)
 
 	if p.unnulVariables {
 		input = httpclient.SetInputFlag(input, httpclient.UNNULL_VARIABLES)
@@ -403,7 +478,10 @@ func (p *Planner) ConfigureFetch() resolve.FetchConfiguration {
 	return resolve.FetchConfiguration{
 		Input: string(input),
 		DataSource: &Source{
-			httpClient: p.fetchClient,
+			httpClient:      p.fetchClient,
+			upstreams:       p.upstreams,
+			maxGETURLLength: p.maxGETURLLength(p.upstreamOperation.OperationDefinitions[0].OperationType),
+			mutation:        p.upstreamOperation.OperationDefinitions[0].OperationType == ast.OperationTypeMutation,
 		},
 		Variables:                             p.variables,
 		RequiresEntityFetch:                   p.requiresEntityFetch(),
@@ -413,6 +491,14 @@ func (p *Planner) ConfigureFetch() resolve.FetchConfiguration {
 	}
 }
 
+// maxGETURLLength returns the maximum URL length of GET requests for query operations, 0 if the operation is sent as POST request
+func (p *Planner) maxGETURLLength(operationType ast.OperationType) int {
+	if !p.config.Fetch.UseGETForQueries || operationType != ast.OperationTypeQuery {
+		return 0
+	}
+	return p.config.Fetch.MaxGETURLLength
+}
+
 func (p *Planner) shouldSelectSingleEntity() bool {
 	return p.dataSourcePlannerConfig.HasRequiredFields() &&
 		p.dataSourcePlannerConfig.PathType == plan.PlannerPathObject
@@ -427,9 +513,16 @@ func (p *Planner) requiresEntityBatchFetch() bool {
 }
 
 func (p *Planner) ConfigureSubscription() plan.SubscriptionConfiguration {
+	operation := p.printOperation()
 	input := httpclient.SetInputBodyWithPath(nil, p.upstreamVariables, "variables")
-	input = httpclient.SetInputBodyWithPath(input, p.printOperation(), "query")
+	input = httpclient.SetInputBodyWithPath(input, p.addMetadataComment(escapeOperation(operation)), "query")
 	input = httpclient.SetInputURL(input, []byte(p.config.Subscription.URL))
+	if p.config.ProxyURL != "" {
+		input, _ = sjson.SetBytes(input, "proxy_url", p.config.ProxyURL)
+	}
+	if p.config.Subscription.UseCallback {
+		input, _ = sjson.SetBytes(input, "use_callback", true)
+	}
 	if p.config.Subscription.UseSSE {
 		input = httpclient.SetInputFlag(input, httpclient.USE_SSE)
 		if p.config.Subscription.SSEMethodPost {
@@ -466,9 +559,39 @@ func (p *Planner) ConfigureSubscription() plan.SubscriptionConfiguration {
 		Input: string(input),
 		DataSource: &SubscriptionSource{
 			client: p.subscriptionClient,
+			router: p.stickyRouter(),
 		},
 		Variables:      p.variables,
 		PostProcessing: DefaultPostProcessingConfiguration,
+		InitialFetch:   p.configureSubscriptionInitialFetch(operation),
+	}
+}
+
+func (p *Planner) configureSubscriptionInitialFetch(operation []byte) *plan.SubscriptionInitialFetchConfiguration {
+	if !p.config.Subscription.FetchInitialState || !bytes.HasPrefix(operation, literal.SUBSCRIPTION) {
+		return nil
+	}
+	query := append(append([]byte{}, literal.QUERY...), operation[len(literal.SUBSCRIPTION):]...)
+
+	input := httpclient.SetInputBodyWithPath(nil, p.upstreamVariables, "variables")
+	input = httpclient.SetInputBodyWithPath(input, p.addMetadataComment(escapeOperation(query)), "query")
+	input = p.setPersistedQueryExtension(input)
+
+	header, err := json.Marshal(p.config.Fetch.Header)
+	if err == nil && len(header) != 0 && !bytes.Equal(header, literal.NULL) {
+		input = httpclient.SetInputHeader(input, header)
+	}
+
+	input = httpclient.SetInputURL(input, []byte(p.config.Fetch.URL))
+	input = httpclient.SetInputMethod(input, []byte(p.config.Fetch.Method))
+
+	return &plan.SubscriptionInitialFetchConfiguration{
+		Input: string(input),
+		DataSource: &Source{
+			httpClient:      p.fetchClient,
+			upstreams:       p.upstreams,
+			maxGETURLLength: p.maxGETURLLength(ast.OperationTypeQuery),
+		},
 	}
 }
 
@@ -483,12 +606,56 @@ func (p *Planner) EnterOperationDefinition(ref int) {
 	if p.dataSourcePlannerConfig.IsNested {
 		operationType = ast.OperationTypeQuery
 	}
-	definition := p.upstreamOperation.AddOperationDefinitionToRootNodes(ast.OperationDefinition{
+	operationDefinition := ast.OperationDefinition{
 		OperationType: operationType,
-	})
+	}
+	if name := p.upstreamOperationName(); name != "" {
+		operationDefinition.Name = p.upstreamOperation.Input.AppendInputString(name)
+	}
+	definition := p.upstreamOperation.AddOperationDefinitionToRootNodes(operationDefinition)
 	p.nodes = append(p.nodes, definition)
 }
 
+// upstreamOperationName returns the name of the upstream operation, e.g. "MyQuery__1" for the planner 1 of the client operation MyQuery
+func (p *Planner) upstreamOperationName() string {
+	if !p.config.OperationNaming.Enabled || p.visitor.OperationName == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s__%d", p.visitor.OperationName, p.id)
+}
+
+// addMetadataComment prepends the metadata comment to the printed upstream operation
+func (p *Planner) addMetadataComment(operation []byte) []byte {
+	if !p.config.OperationNaming.MetadataComment || len(operation) == 0 {
+		return operation
+	}
+	comment, err := json.Marshal(fmt.Sprintf("# operation=%s datasource=%s planner=%d", p.visitor.OperationName, p.dataSourceConfig.ID, p.id))
+	if err != nil {
+		return operation
+	}
+	// the operation is set into the input without escaping, so the comment is escaped and terminated by an escaped line break
+	out := make([]byte, 0, len(comment)+len(operation))
+	out = append(out, comment[1:len(comment)-1]...)
+	out = append(out, `\n`...)
+	return append(out, operation...)
+}
+
+// escapeOperation escapes the quotes of string literals in the operation, the operation is set into the input without escaping.
+// Operations without literal strings, e.g. with extracted variables, are returned as is.
+func escapeOperation(operation []byte) []byte {
+	if !bytes.ContainsAny(operation, `"\`) {
+		return operation
+	}
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(string(operation)); err != nil {
+		return operation
+	}
+	escaped := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	return escaped[1 : len(escaped)-1]
+}
+
 func (p *Planner) LeaveOperationDefinition(_ int) {
 	p.nodes = p.nodes[:len(p.nodes)-1]
 }
@@ -1077,6 +1244,7 @@ func (p *Planner) configureArgument(upstreamFieldRef, downstreamFieldRef int, fi
 func (p *Planner) configureFieldArgumentSource(upstreamFieldRef, downstreamFieldRef int, argumentConfiguration plan.ArgumentConfiguration) {
 	fieldArgument, ok := p.visitor.Operation.FieldArgument(downstreamFieldRef, []byte(argumentConfiguration.Name))
 	if !ok {
+		p.configureArgumentDefaultValue(upstreamFieldRef, argumentConfiguration)
 		return
 	}
 	value := p.visitor.Operation.ArgumentValue(fieldArgument)
@@ -1136,6 +1304,43 @@ func (p *Planner) configureFieldArgumentSource(upstreamFieldRef, downstreamField
 	p.upstreamVariables, _ = sjson.SetRawBytes(p.upstreamVariables, variableNameStr, []byte(contextVariableName))
 }
 
+// configureArgumentDefaultValue - adds an argument which was omitted by the client to the upstream field
+// when the argument configuration has a gateway level default value
+func (p *Planner) configureArgumentDefaultValue(upstreamFieldRef int, argumentConfiguration plan.ArgumentConfiguration) {
+	if len(argumentConfiguration.DefaultValue) == 0 {
+		return
+	}
+
+	// the argument might not exist in the downstream schema, so we have to look it up in the upstream schema
+	definition := p.upstreamDefinition
+	if definition == nil {
+		definition = p.visitor.Definition
+	}
+
+	enclosingTypeName := p.visitor.Walker.EnclosingTypeDefinition.NameBytes(p.visitor.Definition)
+	enclosingTypeName = p.visitor.Config.Types.RenameTypeNameOnMatchBytes(enclosingTypeName)
+	enclosingTypeNode, exists := definition.Index.FirstNodeByNameBytes(enclosingTypeName)
+	if !exists {
+		return
+	}
+
+	fieldName := p.upstreamOperation.FieldNameBytes(upstreamFieldRef)
+	argumentDefinition := definition.NodeFieldDefinitionArgumentDefinitionByName(enclosingTypeNode, fieldName, []byte(argumentConfiguration.Name))
+	if argumentDefinition == -1 {
+		return
+	}
+
+	argumentType := definition.InputValueDefinitionType(argumentDefinition)
+	variableName := p.upstreamOperation.GenerateUnusedVariableDefinitionName(p.nodes[0].Ref)
+	variableValue, argument := p.upstreamOperation.AddVariableValueArgument([]byte(argumentConfiguration.Name), variableName)
+	p.upstreamOperation.AddArgumentToField(upstreamFieldRef, argument)
+
+	importedType := p.visitor.Importer.ImportType(argumentType, definition, p.upstreamOperation)
+	p.upstreamOperation.AddVariableDefinitionToOperationDefinition(p.nodes[0].Ref, variableValue, importedType)
+
+	p.upstreamVariables, _ = sjson.SetRawBytes(p.upstreamVariables, string(variableName), argumentConfiguration.DefaultValue)
+}
+
 // applyInlineFieldArgument - configures arguments for a complex argument of a list or input object type
 func (p *Planner) applyInlineFieldArgument(upstreamField, downstreamField int, argumentName string, sourcePath []string) {
 	fieldArgument, ok := p.visitor.Operation.FieldArgument(downstreamField, []byte(argumentName))
@@ -1346,7 +1551,15 @@ func (p *Planner) printQueryPlan(operation *ast.Document) {
 		return
 	}
 
-	printedOperation, err := astprinter.PrintStringIndent(operation, nil, "  ")
+	var (
+		printedOperation string
+		err              error
+	)
+	if p.dataSourcePlannerConfig.RedactDebugValues {
+		printedOperation, err = astprinter.PrintStringRedacted(operation, nil, "  ")
+	} else {
+		printedOperation, err = astprinter.PrintStringIndent(operation, nil, "  ")
+	}
 	if err != nil {
 		return
 	}
@@ -1653,10 +1866,36 @@ type Factory struct {
 	StreamingClient            *http.Client
 	OnWsConnectionInitCallback *OnWsConnectionInitCallback
 	SubscriptionClient         *SubscriptionClient
-	Logger                     abstractlogger.Logger
-}
+	// SubscriptionCallbackHandler accepts the callbacks of subscriptions with SubscriptionConfiguration.UseCallback,
+	// it has to be served by the application at the public URL of the handler
+	SubscriptionCallbackHandler *SubscriptionCallbackHandler
+	Logger                      abstractlogger.Logger
+	// RequestSigner signs the fetches of the datasource, e.g. httpclient.SigV4Signer for AppSync or Lambda function URLs
+	// or httpclient.OAuth2ClientCredentials to authorize the fetches with the token of the OAuth2 client credentials flow
+	RequestSigner httpclient.RequestSigner
+	// EgressPolicy restricts the upstreams the fetches and the subscriptions of the datasource can connect to,
+	// e.g. to the allowed hosts if the URL is templated from user input.
+	// The policy doesn't apply to a SubscriptionClient which is set on the factory
+	EgressPolicy *httpclient.EgressPolicy
+
+	// clients are created on first use, see egressClients
+	clients *factoryClients
+}
+
+// factoryClients are the clients of a Factory which apply its egress policy
+type factoryClients struct {
+	httpClient      *http.Client
+	streamingClient *http.Client
+	proxyClients    *httpclient.ProxyClients
+	err             error
+}
+
+// factoryClientsMu guards the creation of the clients of the factories,
+// the planners of a factory might be created concurrently by the engines sharing it
+var factoryClientsMu sync.Mutex
 
 func (f *Factory) Planner(ctx context.Context) plan.DataSourcePlanner {
+	clients := f.egressClients()
 	if f.SubscriptionClient == nil {
 		opts := make([]Options, 0)
 		if f.OnWsConnectionInitCallback != nil {
@@ -1665,19 +1904,87 @@ func (f *Factory) Planner(ctx context.Context) plan.DataSourcePlanner {
 		if f.Logger != nil {
 			opts = append(opts, WithLogger(f.Logger))
 		}
+		if f.SubscriptionCallbackHandler != nil {
+			opts = append(opts, WithSubscriptionCallbackHandler(f.SubscriptionCallbackHandler))
+		}
 
-		f.SubscriptionClient = NewGraphQLSubscriptionClient(f.HTTPClient, f.StreamingClient, ctx, opts...)
+		f.SubscriptionClient = NewGraphQLSubscriptionClient(clients.httpClient, clients.streamingClient, ctx, opts...)
 	} else if f.SubscriptionClient.engineCtx == nil {
 		f.SubscriptionClient.engineCtx = ctx
 	}
+	fetchClient, _ := f.fetchClient("")
 	return &Planner{
-		fetchClient:        f.HTTPClient,
+		factory:            f,
+		fetchClient:        fetchClient,
 		subscriptionClient: f.SubscriptionClient,
 	}
 }
 
+// WithClients returns a copy of the factory which uses the clients instead of its own clients,
+// e.g. to share the connections to the upstreams between the factories of several engines
+func (f *Factory) WithClients(httpClient, streamingClient *http.Client, subscriptionClient *SubscriptionClient) *Factory {
+	factory := *f
+	factory.HTTPClient = httpClient
+	factory.StreamingClient = streamingClient
+	factory.SubscriptionClient = subscriptionClient
+	factory.clients = nil
+	return &factory
+}
+
+// egressClients returns the clients of the factory which apply the egress policy, the clients are created once
+func (f *Factory) egressClients() *factoryClients {
+	factoryClientsMu.Lock()
+	defer factoryClientsMu.Unlock()
+
+	if f.clients != nil {
+		return f.clients
+	}
+	clients := &factoryClients{
+		httpClient:      f.HTTPClient,
+		streamingClient: f.StreamingClient,
+	}
+	if f.EgressPolicy != nil {
+		if clients.httpClient != nil {
+			clients.httpClient, clients.err = httpclient.NewEgressClient(clients.httpClient, f.EgressPolicy)
+		}
+		if clients.err == nil && clients.streamingClient != nil {
+			clients.streamingClient, clients.err = httpclient.NewEgressClient(clients.streamingClient, f.EgressPolicy)
+		}
+		if clients.err != nil {
+			// the planners fail to register, so the clients without the egress policy are never used
+			clients.httpClient, clients.streamingClient = f.HTTPClient, f.StreamingClient
+		}
+	}
+	clients.proxyClients = httpclient.NewProxyClients(clients.httpClient)
+	f.clients = clients
+	return clients
+}
+
+// fetchClient returns the client of the fetches, which applies the egress policy,
+// sends the requests through the proxy and signs them if configured
+func (f *Factory) fetchClient(proxyURL string) (*http.Client, error) {
+	clients := f.egressClients()
+	if clients.err != nil {
+		return nil, clients.err
+	}
+	client, err := clients.proxyClients.Client(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	if f.RequestSigner != nil {
+		// the signing client shares the transport, so the connections are reused
+		client = httpclient.NewSigningClient(client, f.RequestSigner)
+	}
+	return client, nil
+}
+
 type Source struct {
 	httpClient *http.Client
+	upstreams  *httpclient.UpstreamGroup
+	// maxGETURLLength is set for query operations which are sent as GET requests
+	maxGETURLLength int
+	// mutation requests aren't idempotent, they are only sent to the failover URLs if they didn't reach the upstream
+	mutation bool
 }
 
 func (s *Source) compactAndUnNullVariables(input []byte) []byte {
@@ -1764,9 +2071,43 @@ func (s *Source) replaceEmptyObject(variables []byte) ([]byte, bool) {
 
 func (s *Source) Load(ctx context.Context, input []byte, writer io.Writer) (err error) {
 	input = s.compactAndUnNullVariables(input)
+	if isPersistedQueryInput(input) {
+		return s.loadPersistedQuery(ctx, input, writer)
+	}
+	return s.do(ctx, input, writer)
+}
+
+func (s *Source) do(ctx context.Context, input []byte, writer io.Writer) error {
+	input = s.setGETFlag(input)
+	if s.upstreams != nil {
+		if s.mutation {
+			return s.upstreams.DoNonIdempotent(s.httpClient, ctx, input, writer)
+		}
+		return s.upstreams.Do(s.httpClient, ctx, input, writer)
+	}
 	return httpclient.Do(s.httpClient, ctx, input, writer)
 }
 
+// setGETFlag sends the request as GET request if the URL with the operation doesn't exceed the maximum length
+func (s *Source) setGETFlag(input []byte) []byte {
+	if s.maxGETURLLength == 0 {
+		return input
+	}
+	url, _, _, err := jsonparser.Get(input, httpclient.URL)
+	if err != nil {
+		return input
+	}
+	body, _, _, err := jsonparser.Get(input, httpclient.BODY)
+	if err != nil {
+		return input
+	}
+	getURL, err := httpclient.GraphQLGetURL(url, body)
+	if err != nil || len(getURL) > s.maxGETURLLength {
+		return input
+	}
+	return httpclient.SetInputFlag(input, httpclient.GRAPHQL_GET)
+}
+
 type GraphQLSubscriptionClient interface {
 	Subscribe(ctx *resolve.Context, options GraphQLSubscriptionOptions, updater resolve.SubscriptionUpdater) error
 	UniqueRequestID(ctx *resolve.Context, options GraphQLSubscriptionOptions, hash *xxhash.Digest) (err error)
@@ -1779,8 +2120,10 @@ type GraphQLSubscriptionOptions struct {
 	Header                                  http.Header      `json:"header"`
 	UseSSE                                  bool             `json:"use_sse"`
 	SSEMethodPost                           bool             `json:"sse_method_post"`
+	UseCallback                             bool             `json:"use_callback"`
 	ForwardedClientHeaderNames              []string         `json:"forwarded_client_header_names"`
 	ForwardedClientHeaderRegularExpressions []*regexp.Regexp `json:"forwarded_client_header_regular_expressions"`
+	ProxyURL                                string           `json:"proxy_url"`
 }
 
 type GraphQLBody struct {
@@ -1792,11 +2135,22 @@ type GraphQLBody struct {
 
 type SubscriptionSource struct {
 	client GraphQLSubscriptionClient
+	router *stickyRouter
+}
+
+func (s *SubscriptionSource) options(ctx *resolve.Context, input []byte) (options GraphQLSubscriptionOptions, err error) {
+	err = json.Unmarshal(input, &options)
+	if err != nil {
+		return options, err
+	}
+	if s.router != nil {
+		options.URL = s.router.url(ctx, options)
+	}
+	return options, nil
 }
 
 func (s *SubscriptionSource) Start(ctx *resolve.Context, input []byte, updater resolve.SubscriptionUpdater) error {
-	var options GraphQLSubscriptionOptions
-	err := json.Unmarshal(input, &options)
+	options, err := s.options(ctx, input)
 	if err != nil {
 		return err
 	}
@@ -1815,8 +2169,7 @@ func (s *SubscriptionSource) UniqueRequestID(ctx *resolve.Context, input []byte,
 	if err != nil {
 		return err
 	}
-	var options GraphQLSubscriptionOptions
-	err = json.Unmarshal(input, &options)
+	options, err := s.options(ctx, input)
 	if err != nil {
 		return err
 	}
@@ -629,6 +629,12 @@ func TestGraphQLDataSourceFederation(t *testing.T) {
 																},
 															},
 															OperationType: ast.OperationTypeQuery,
+															EntityKeys: []resolve.EntityKey{
+																{
+																	TypeName:     "Account",
+																	SelectionSet: "id info {a b}",
+																},
+															},
 														},
 														DataSourceIdentifier: []byte("graphql_datasource.Source"),
 														FetchConfiguration: resolve.FetchConfiguration{
@@ -1324,7 +1330,7 @@ func TestGraphQLDataSourceFederation(t *testing.T) {
 																				DependsOnFetchIDs:    []int{0, 3},
 																				DataSourceIdentifier: []byte("graphql_datasource.Source"),
 																				FetchConfiguration: resolve.FetchConfiguration{
-																					Input:               `{"method":"POST","url":"http://address.service","body":{"query":"query($representations: [_Any!]!){_entities(representations: $representations){__typename ... on Address {line3(test: "BOOM") zip}}}","variables":{"representations":[$$0$$]}}}`,
+																					Input:               `{"method":"POST","url":"http://address.service","body":{"query":"query($representations: [_Any!]!){_entities(representations: $representations){__typename ... on Address {line3(test: \"BOOM\") zip}}}","variables":{"representations":[$$0$$]}}}`,
 																					DataSource:          &Source{},
 																					PostProcessing:      SingleEntityPostProcessingConfiguration,
 																					RequiresEntityFetch: true,
@@ -4503,6 +4509,259 @@ func TestGraphQLDataSourceFederation(t *testing.T) {
 		})
 	})
 
+	t.Run("key fallback", func(t *testing.T) {
+		definition := `
+			type User {
+				id: ID!
+				uuid: ID!
+				email: String!
+				reviews: [String!]!
+			}
+
+			type Query {
+				user: User
+			}
+		`
+
+		usersSubgraphSDL := `
+			type Query {
+				user: User
+			}
+
+			type User @key(fields: "id") {
+				id: ID!
+				email: String!
+			}
+		`
+
+		usersDatasourceConfiguration := plan.DataSourceConfiguration{
+			ID: "users.service",
+			RootNodes: []plan.TypeField{
+				{
+					TypeName:   "Query",
+					FieldNames: []string{"user"},
+				},
+				{
+					TypeName:   "User",
+					FieldNames: []string{"id", "email"},
+				},
+			},
+			Custom: ConfigJson(Configuration{
+				Fetch: FetchConfiguration{
+					URL: "http://users.service",
+				},
+				Federation: FederationConfiguration{
+					Enabled:    true,
+					ServiceSDL: usersSubgraphSDL,
+				},
+			}),
+			Factory: federationFactory,
+			FederationMetaData: plan.FederationMetaData{
+				Keys: plan.FederationFieldConfigurations{
+					{
+						TypeName:     "User",
+						SelectionSet: "id",
+					},
+				},
+			},
+		}
+
+		reviewsSubgraphSDL := `
+			type User @key(fields: "uuid") @key(fields: "email") {
+				uuid: ID!
+				email: String!
+				reviews: [String!]!
+			}
+		`
+
+		reviewsDatasourceConfiguration := plan.DataSourceConfiguration{
+			ID: "reviews.service",
+			RootNodes: []plan.TypeField{
+				{
+					TypeName:   "User",
+					FieldNames: []string{"uuid", "email", "reviews"},
+				},
+			},
+			Custom: ConfigJson(Configuration{
+				Fetch: FetchConfiguration{
+					URL: "http://reviews.service",
+				},
+				Federation: FederationConfiguration{
+					Enabled:    true,
+					ServiceSDL: reviewsSubgraphSDL,
+				},
+			}),
+			Factory: federationFactory,
+			FederationMetaData: plan.FederationMetaData{
+				Keys: plan.FederationFieldConfigurations{
+					{
+						TypeName:     "User",
+						SelectionSet: "uuid",
+					},
+					{
+						TypeName:     "User",
+						SelectionSet: "email",
+					},
+				},
+			},
+		}
+
+		planConfiguration := plan.Configuration{
+			DataSources: []plan.DataSourceConfiguration{
+				usersDatasourceConfiguration,
+				reviewsDatasourceConfiguration,
+			},
+			DisableResolveFieldPositions: true,
+			IncludeInfo:                  true,
+		}
+
+		t.Run("should fall back to the key resolvable by the parent", func(t *testing.T) {
+			RunWithPermutations(
+				t,
+				definition,
+				`
+					query Query {
+						user {
+							id
+							reviews
+						}
+					}
+				`,
+				"Query",
+				&plan.SynchronousResponsePlan{
+					Response: &resolve.GraphQLResponse{
+						Info: &resolve.GraphQLResponseInfo{
+							OperationType: ast.OperationTypeQuery,
+						},
+						Data: &resolve.Object{
+							Fetch: &resolve.SingleFetch{
+								FetchConfiguration: resolve.FetchConfiguration{
+									Input:          `{"method":"POST","url":"http://users.service","body":{"query":"{user {id __typename email}}"}}`,
+									PostProcessing: DefaultPostProcessingConfiguration,
+									DataSource:     &Source{},
+								},
+								DataSourceIdentifier: []byte("graphql_datasource.Source"),
+								Info: &resolve.FetchInfo{
+									DataSourceID:  "users.service",
+									OperationType: ast.OperationTypeQuery,
+									RootFields: []resolve.GraphCoordinate{
+										{
+											TypeName:  "Query",
+											FieldName: "user",
+										},
+									},
+								},
+							},
+							Fields: []*resolve.Field{
+								{
+									Name: []byte("user"),
+									Info: &resolve.FieldInfo{
+										Name:                "user",
+										ExactParentTypeName: "Query",
+										ParentTypeNames:     []string{"Query"},
+										NamedType:           "User",
+										Source: resolve.TypeFieldSource{
+											IDs: []string{"users.service"},
+										},
+									},
+									Value: &resolve.Object{
+										Path:     []string{"user"},
+										Nullable: true,
+										Fields: []*resolve.Field{
+											{
+												Name: []byte("id"),
+												Info: &resolve.FieldInfo{
+													Name:                "id",
+													ExactParentTypeName: "User",
+													ParentTypeNames:     []string{"User"},
+													NamedType:           "ID",
+													Source: resolve.TypeFieldSource{
+														IDs: []string{"users.service"},
+													},
+												},
+												Value: &resolve.Scalar{
+													Path: []string{"id"},
+												},
+											},
+											{
+												Name: []byte("reviews"),
+												Info: &resolve.FieldInfo{
+													Name:                "reviews",
+													ExactParentTypeName: "User",
+													ParentTypeNames:     []string{"User"},
+													NamedType:           "String",
+													Source: resolve.TypeFieldSource{
+														IDs: []string{"reviews.service"},
+													},
+												},
+												Value: &resolve.Array{
+													Path: []string{"reviews"},
+													Item: &resolve.String{},
+												},
+											},
+										},
+										Fetch: &resolve.SingleFetch{
+											FetchID:           1,
+											DependsOnFetchIDs: []int{0},
+											FetchConfiguration: resolve.FetchConfiguration{
+												RequiresEntityFetch:                   true,
+												Input:                                 `{"method":"POST","url":"http://reviews.service","body":{"query":"query($representations: [_Any!]!){_entities(representations: $representations){__typename ... on User {reviews}}}","variables":{"representations":[$$0$$]}}}`,
+												DataSource:                            &Source{},
+												SetTemplateOutputToNullOnVariableNull: true,
+												Variables: []resolve.Variable{
+													&resolve.ResolvableObjectVariable{
+														Renderer: resolve.NewGraphQLVariableResolveRenderer(&resolve.Object{
+															Nullable: true,
+															Fields: []*resolve.Field{
+																{
+																	Name: []byte("__typename"),
+																	Value: &resolve.String{
+																		Path: []string{"__typename"},
+																	},
+																	OnTypeNames: [][]byte{[]byte("User")},
+																},
+																{
+																	Name: []byte("email"),
+																	Value: &resolve.String{
+																		Path: []string{"email"},
+																	},
+																	OnTypeNames: [][]byte{[]byte("User")},
+																},
+															},
+														}),
+													},
+												},
+												PostProcessing: SingleEntityPostProcessingConfiguration,
+											},
+											DataSourceIdentifier: []byte("graphql_datasource.Source"),
+											Info: &resolve.FetchInfo{
+												DataSourceID:  "reviews.service",
+												OperationType: ast.OperationTypeQuery,
+												RootFields: []resolve.GraphCoordinate{
+													{
+														TypeName:  "User",
+														FieldName: "reviews",
+													},
+												},
+												EntityKeys: []resolve.EntityKey{
+													{
+														TypeName:     "User",
+														SelectionSet: "email",
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				planConfiguration,
+			)
+		})
+	})
+
 	t.Run("key resolvable false", func(t *testing.T) {
 		t.Run("example 1", func(t *testing.T) {
 			definition := `
@@ -18,6 +18,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/httpclient"
 	. "github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasourcetesting"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
@@ -2840,6 +2841,412 @@ func TestGraphQLDataSource(t *testing.T) {
 		},
 	))
 
+	t.Run("argument default value defined only at the gateway", RunTest(`
+		type Query {
+			friends(name: String): [Friend!]!
+		}
+		type Friend {
+			id: ID!
+			name: String!
+		}
+	`,
+		`query Friends($name: String){ friends(name: $name){ id name } }`,
+		"Friends",
+		&plan.SynchronousResponsePlan{
+			Response: &resolve.GraphQLResponse{
+				Data: &resolve.Object{
+					Fetch: &resolve.SingleFetch{
+						FetchConfiguration: resolve.FetchConfiguration{
+							Input:      `{"method":"POST","url":"https://service.one","body":{"query":"query($name: String, $a: Int!){friends(name: $name, limit: $a){id name}}","variables":{"a":100,"name":$$0$$}}}`,
+							DataSource: &Source{},
+							Variables: resolve.NewVariables(
+								&resolve.ContextVariable{
+									Path:     []string{"name"},
+									Renderer: resolve.NewJSONVariableRendererWithValidation(`{"type":["string","null"]}`),
+								},
+							),
+							PostProcessing: DefaultPostProcessingConfiguration,
+						},
+						DataSourceIdentifier: []byte("graphql_datasource.Source"),
+					},
+					Fields: []*resolve.Field{
+						{
+							Name: []byte("friends"),
+							Value: &resolve.Array{
+								Path: []string{"friends"},
+								Item: &resolve.Object{
+									Fields: []*resolve.Field{
+										{
+											Name: []byte("id"),
+											Value: &resolve.String{
+												Path: []string{"id"},
+											},
+										},
+										{
+											Name: []byte("name"),
+											Value: &resolve.String{
+												Path: []string{"name"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		plan.Configuration{
+			DataSources: []plan.DataSourceConfiguration{
+				{
+					RootNodes: []plan.TypeField{
+						{
+							TypeName:   "Query",
+							FieldNames: []string{"friends"},
+						},
+					},
+					ChildNodes: []plan.TypeField{
+						{
+							TypeName:   "Friend",
+							FieldNames: []string{"id", "name"},
+						},
+					},
+					Custom: ConfigJson(Configuration{
+						Fetch: FetchConfiguration{
+							URL: "https://service.one",
+						},
+						UpstreamSchema: `
+							type Query {
+								friends(name: String, limit: Int!): [Friend!]!
+							}
+							type Friend {
+								id: ID!
+								name: String!
+							}
+						`,
+					}),
+					Factory: &Factory{},
+				},
+			},
+			Fields: []plan.FieldConfiguration{
+				{
+					TypeName:  "Query",
+					FieldName: "friends",
+					Arguments: []plan.ArgumentConfiguration{
+						{
+							Name:       "name",
+							SourceType: plan.FieldArgumentSource,
+						},
+						{
+							Name:         "limit",
+							SourceType:   plan.FieldArgumentSource,
+							DefaultValue: []byte(`100`),
+						},
+					},
+				},
+			},
+			DisableResolveFieldPositions: true,
+		},
+	))
+
+	t.Run("list post processing directives are executed by the gateway", RunTest(`
+		directive @filter(if: String!) on FIELD
+		directive @sort(by: String!, desc: Boolean) on FIELD
+		type Query {
+			friends: [Friend!]!
+		}
+		type Friend {
+			id: ID!
+			name: String!
+		}
+	`,
+		`query Friends($desc: Boolean){ friends @filter(if: "name != \"a\"") @sort(by: "name", desc: $desc) { id name } }`,
+		"Friends",
+		&plan.SynchronousResponsePlan{
+			Response: &resolve.GraphQLResponse{
+				Data: &resolve.Object{
+					Fetch: &resolve.SingleFetch{
+						FetchConfiguration: resolve.FetchConfiguration{
+							Input:          `{"method":"POST","url":"https://service.one","body":{"query":"{friends {id name}}"}}`,
+							DataSource:     &Source{},
+							PostProcessing: DefaultPostProcessingConfiguration,
+						},
+						DataSourceIdentifier: []byte("graphql_datasource.Source"),
+					},
+					Fields: []*resolve.Field{
+						{
+							Name: []byte("friends"),
+							Value: &resolve.Array{
+								Path: []string{"friends"},
+								PostProcessing: &resolve.ListPostProcessing{
+									Filter: &resolve.ListFilter{
+										Expression: `name != "a"`,
+									},
+									Sort: &resolve.ListSort{
+										By:                     "name",
+										DescendingVariableName: "desc",
+									},
+									MaxItems: 1000,
+								},
+								Item: &resolve.Object{
+									Fields: []*resolve.Field{
+										{
+											Name: []byte("id"),
+											Value: &resolve.String{
+												Path: []string{"id"},
+											},
+										},
+										{
+											Name: []byte("name"),
+											Value: &resolve.String{
+												Path: []string{"name"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		plan.Configuration{
+			DataSources: []plan.DataSourceConfiguration{
+				{
+					RootNodes: []plan.TypeField{
+						{
+							TypeName:   "Query",
+							FieldNames: []string{"friends"},
+						},
+					},
+					ChildNodes: []plan.TypeField{
+						{
+							TypeName:   "Friend",
+							FieldNames: []string{"id", "name"},
+						},
+					},
+					Custom: ConfigJson(Configuration{
+						Fetch: FetchConfiguration{
+							URL: "https://service.one",
+						},
+						UpstreamSchema: `
+							directive @filter(if: String!) on FIELD
+							directive @sort(by: String!, desc: Boolean) on FIELD
+							type Query {
+								friends: [Friend!]!
+							}
+							type Friend {
+								id: ID!
+								name: String!
+							}
+						`,
+					}),
+					Factory: &Factory{},
+				},
+			},
+			ListPostProcessing: plan.ListPostProcessingConfiguration{
+				Enabled:  true,
+				MaxItems: 1000,
+			},
+			DisableResolveFieldPositions: true,
+		},
+	))
+
+	t.Run("operation naming", RunTest(`
+		type Query {
+			user: User
+		}
+		type User {
+			name: String!
+		}
+	`,
+		`query User { user { name } }`,
+		"User",
+		&plan.SynchronousResponsePlan{
+			Response: &resolve.GraphQLResponse{
+				Data: &resolve.Object{
+					Fetch: &resolve.SingleFetch{
+						FetchConfiguration: resolve.FetchConfiguration{
+							Input:          `{"method":"POST","url":"https://service.one","body":{"query":"# operation=User datasource=users planner=1\nquery User__1 {user {name}}"}}`,
+							DataSource:     &Source{},
+							PostProcessing: DefaultPostProcessingConfiguration,
+						},
+						DataSourceIdentifier: []byte("graphql_datasource.Source"),
+					},
+					Fields: []*resolve.Field{
+						{
+							Name: []byte("user"),
+							Value: &resolve.Object{
+								Path:     []string{"user"},
+								Nullable: true,
+								Fields: []*resolve.Field{
+									{
+										Name: []byte("name"),
+										Value: &resolve.String{
+											Path: []string{"name"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		plan.Configuration{
+			DataSources: []plan.DataSourceConfiguration{
+				{
+					ID: "users",
+					RootNodes: []plan.TypeField{
+						{
+							TypeName:   "Query",
+							FieldNames: []string{"user"},
+						},
+					},
+					ChildNodes: []plan.TypeField{
+						{
+							TypeName:   "User",
+							FieldNames: []string{"name"},
+						},
+					},
+					Custom: ConfigJson(Configuration{
+						Fetch: FetchConfiguration{
+							URL: "https://service.one",
+						},
+						UpstreamSchema: `
+							type Query {
+								user: User
+							}
+							type User {
+								name: String!
+							}
+						`,
+						OperationNaming: OperationNamingConfiguration{
+							Enabled:         true,
+							MetadataComment: true,
+						},
+					}),
+					Factory: &Factory{},
+				},
+			},
+			DisableResolveFieldPositions: true,
+		},
+	))
+
+	t.Run("scalar transformers of a datasource", RunTest(`
+		type Query {
+			user: User
+		}
+		type User {
+			createdAt: String!
+			friends: [User!]!
+		}
+	`,
+		`query User { user { createdAt friends { createdAt } } }`,
+		"User",
+		&plan.SynchronousResponsePlan{
+			Response: &resolve.GraphQLResponse{
+				Data: &resolve.Object{
+					Fetch: &resolve.SingleFetch{
+						FetchConfiguration: resolve.FetchConfiguration{
+							Input:      `{"method":"POST","url":"https://service.one","body":{"query":"{user {createdAt friends {createdAt}}}"}}`,
+							DataSource: &Source{},
+							PostProcessing: resolve.PostProcessingConfiguration{
+								SelectResponseDataPath:   []string{"data"},
+								SelectResponseErrorsPath: []string{"errors"},
+								ScalarTransformations: []resolve.ScalarTransformation{
+									{
+										Path:        []string{"user", "createdAt"},
+										Transformer: resolve.EpochToISO8601Transformer{},
+									},
+									{
+										Path:        []string{"user", "friends", "createdAt"},
+										Transformer: resolve.EpochToISO8601Transformer{},
+									},
+								},
+							},
+						},
+						DataSourceIdentifier: []byte("graphql_datasource.Source"),
+					},
+					Fields: []*resolve.Field{
+						{
+							Name: []byte("user"),
+							Value: &resolve.Object{
+								Path:     []string{"user"},
+								Nullable: true,
+								Fields: []*resolve.Field{
+									{
+										Name: []byte("createdAt"),
+										Value: &resolve.String{
+											Path: []string{"createdAt"},
+										},
+									},
+									{
+										Name: []byte("friends"),
+										Value: &resolve.Array{
+											Path: []string{"friends"},
+											Item: &resolve.Object{
+												Fields: []*resolve.Field{
+													{
+														Name: []byte("createdAt"),
+														Value: &resolve.String{
+															Path: []string{"createdAt"},
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		plan.Configuration{
+			DataSources: []plan.DataSourceConfiguration{
+				{
+					RootNodes: []plan.TypeField{
+						{
+							TypeName:   "Query",
+							FieldNames: []string{"user"},
+						},
+					},
+					ChildNodes: []plan.TypeField{
+						{
+							TypeName:   "User",
+							FieldNames: []string{"createdAt", "friends"},
+						},
+					},
+					Custom: ConfigJson(Configuration{
+						Fetch: FetchConfiguration{
+							URL: "https://service.one",
+						},
+						UpstreamSchema: `
+							type Query {
+								user: User
+							}
+							type User {
+								createdAt: Int!
+								friends: [User!]!
+							}
+						`,
+					}),
+					Factory: &Factory{},
+					ScalarTransformers: plan.ScalarTransformerConfigurations{
+						{
+							TypeName:    "User",
+							FieldName:   "createdAt",
+							Transformer: resolve.EpochToISO8601Transformer{},
+						},
+					},
+				},
+			},
+			DisableResolveFieldPositions: true,
+		},
+	))
+
 	t.Run("nested resolvers of same upstream", RunTest(`
 		type Query {
 			foo(bar: String):Baz
@@ -3565,6 +3972,170 @@ func TestGraphQLDataSource(t *testing.T) {
 		WithMultiFetchPostProcessor(),
 	))
 
+	serialMutationsFactory := &Factory{}
+	t.Run("top level mutations of different services are executed serially", RunTest(`
+		type Query {
+			hello: String
+		}
+		type Mutation {
+			createUser(name: String!): String!
+			sendMail(to: String!): Boolean!
+			deleteUser(name: String!): Boolean!
+		}
+	`, `
+		mutation Serial {
+			createUser(name: "a")
+			sendMail(to: "a")
+			deleteUser(name: "a")
+		}
+	`, "Serial",
+		&plan.SynchronousResponsePlan{
+			Response: &resolve.GraphQLResponse{
+				Data: &resolve.Object{
+					Fetch: &resolve.SerialFetch{
+						Fetches: []resolve.Fetch{
+							&resolve.SingleFetch{
+								FetchID: 0,
+								FetchConfiguration: resolve.FetchConfiguration{
+									Input:      `{"method":"POST","url":"https://users.service","body":{"query":"mutation($a: String!){createUser(name: $a)}","variables":{"a":$$0$$}}}`,
+									DataSource: &Source{},
+									Variables: resolve.NewVariables(
+										&resolve.ContextVariable{
+											Path:     []string{"a"},
+											Renderer: resolve.NewJSONVariableRendererWithValidation(`{"type":["string"]}`),
+										},
+									),
+									PostProcessing: DefaultPostProcessingConfiguration,
+								},
+								DataSourceIdentifier: []byte("graphql_datasource.Source"),
+							},
+							&resolve.SingleFetch{
+								FetchID:           1,
+								DependsOnFetchIDs: []int{0},
+								FetchConfiguration: resolve.FetchConfiguration{
+									Input:      `{"method":"POST","url":"https://mail.service","body":{"query":"mutation($a: String!){sendMail(to: $a)}","variables":{"a":$$0$$}}}`,
+									DataSource: &Source{},
+									Variables: resolve.NewVariables(
+										&resolve.ContextVariable{
+											Path:     []string{"a"},
+											Renderer: resolve.NewJSONVariableRendererWithValidation(`{"type":["string"]}`),
+										},
+									),
+									PostProcessing: DefaultPostProcessingConfiguration,
+								},
+								DataSourceIdentifier: []byte("graphql_datasource.Source"),
+							},
+							&resolve.SingleFetch{
+								FetchID:           2,
+								DependsOnFetchIDs: []int{1},
+								FetchConfiguration: resolve.FetchConfiguration{
+									Input:      `{"method":"POST","url":"https://users.service","body":{"query":"mutation($a: String!){deleteUser(name: $a)}","variables":{"a":$$0$$}}}`,
+									DataSource: &Source{},
+									Variables: resolve.NewVariables(
+										&resolve.ContextVariable{
+											Path:     []string{"a"},
+											Renderer: resolve.NewJSONVariableRendererWithValidation(`{"type":["string"]}`),
+										},
+									),
+									PostProcessing: DefaultPostProcessingConfiguration,
+								},
+								DataSourceIdentifier: []byte("graphql_datasource.Source"),
+							},
+						},
+					},
+					Fields: []*resolve.Field{
+						{
+							Name: []byte("createUser"),
+							Value: &resolve.String{
+								Path: []string{"createUser"},
+							},
+						},
+						{
+							Name: []byte("sendMail"),
+							Value: &resolve.Boolean{
+								Path: []string{"sendMail"},
+							},
+						},
+						{
+							Name: []byte("deleteUser"),
+							Value: &resolve.Boolean{
+								Path: []string{"deleteUser"},
+							},
+						},
+					},
+				},
+			},
+		},
+		plan.Configuration{
+			DataSources: []plan.DataSourceConfiguration{
+				{
+					RootNodes: []plan.TypeField{
+						{
+							TypeName:   "Mutation",
+							FieldNames: []string{"createUser", "deleteUser"},
+						},
+					},
+					Custom: ConfigJson(Configuration{
+						Fetch: FetchConfiguration{
+							URL:    "https://users.service",
+							Method: "POST",
+						},
+					}),
+					Factory: serialMutationsFactory,
+				},
+				{
+					RootNodes: []plan.TypeField{
+						{
+							TypeName:   "Mutation",
+							FieldNames: []string{"sendMail"},
+						},
+					},
+					Custom: ConfigJson(Configuration{
+						Fetch: FetchConfiguration{
+							URL:    "https://mail.service",
+							Method: "POST",
+						},
+					}),
+					Factory: serialMutationsFactory,
+				},
+			},
+			Fields: []plan.FieldConfiguration{
+				{
+					TypeName:  "Mutation",
+					FieldName: "createUser",
+					Arguments: []plan.ArgumentConfiguration{
+						{
+							Name:       "name",
+							SourceType: plan.FieldArgumentSource,
+						},
+					},
+				},
+				{
+					TypeName:  "Mutation",
+					FieldName: "sendMail",
+					Arguments: []plan.ArgumentConfiguration{
+						{
+							Name:       "to",
+							SourceType: plan.FieldArgumentSource,
+						},
+					},
+				},
+				{
+					TypeName:  "Mutation",
+					FieldName: "deleteUser",
+					Arguments: []plan.ArgumentConfiguration{
+						{
+							Name:       "name",
+							SourceType: plan.FieldArgumentSource,
+						},
+					},
+				},
+			},
+			DisableResolveFieldPositions: true,
+		},
+		WithMultiFetchPostProcessor(),
+	))
+
 	t.Run("mutation with variables in array object argument", RunTest(
 		todoSchema,
 		`mutation AddTask($title: String!, $completed: Boolean!, $name: String! @fromClaim(name: "sub")) {
@@ -4110,7 +4681,7 @@ func TestGraphQLDataSource(t *testing.T) {
 			Trigger: resolve.GraphQLSubscriptionTrigger{
 				Input: []byte(`{"url":"wss://swapi.com/graphql","body":{"query":"subscription{remainingJedis}"}}`),
 				Source: &SubscriptionSource{
-					NewGraphQLSubscriptionClient(http.DefaultClient, http.DefaultClient, ctx),
+					client: NewGraphQLSubscriptionClient(http.DefaultClient, http.DefaultClient, ctx),
 				},
 				PostProcessing: DefaultPostProcessingConfiguration,
 			},
@@ -4199,6 +4770,95 @@ func TestGraphQLDataSource(t *testing.T) {
 		DisableResolveFieldPositions: true,
 	}))
 
+	t.Run("Subscription with initial state", RunTest(`
+		type Query {
+			foo(bar: String): Int!
+		}
+		type Subscription {
+			foo(bar: String): Int!
+ 		}
+`, `
+		subscription SubscriptionWithInitialState {
+			foo(bar: "baz")
+		}
+	`, "SubscriptionWithInitialState", &plan.SubscriptionResponsePlan{
+		Response: &resolve.GraphQLSubscription{
+			Trigger: resolve.GraphQLSubscriptionTrigger{
+				Input: []byte(`{"url":"wss://swapi.com/graphql","body":{"query":"subscription($a: String){foo(bar: $a)}","variables":{"a":$$0$$}}}`),
+				Variables: resolve.NewVariables(
+					&resolve.ContextVariable{
+						Path:     []string{"a"},
+						Renderer: resolve.NewJSONVariableRendererWithValidation(`{"type":["string","null"]}`),
+					},
+				),
+				Source: &SubscriptionSource{
+					client: NewGraphQLSubscriptionClient(http.DefaultClient, http.DefaultClient, ctx),
+				},
+				PostProcessing: DefaultPostProcessingConfiguration,
+				InitialFetch: &resolve.SubscriptionInitialFetch{
+					Input: []byte(`{"method":"POST","url":"https://swapi.com/graphql","body":{"query":"query($a: String){foo(bar: $a)}","variables":{"a":$$0$$}}}`),
+					Variables: resolve.NewVariables(
+						&resolve.ContextVariable{
+							Path:     []string{"a"},
+							Renderer: resolve.NewJSONVariableRendererWithValidation(`{"type":["string","null"]}`),
+						},
+					),
+					DataSource: &Source{
+						httpClient: http.DefaultClient,
+					},
+				},
+			},
+			Response: &resolve.GraphQLResponse{
+				Data: &resolve.Object{
+					Fields: []*resolve.Field{
+						{
+							Name: []byte("foo"),
+							Value: &resolve.Integer{
+								Path:     []string{"foo"},
+								Nullable: false,
+							},
+						},
+					},
+				},
+			},
+		},
+	}, plan.Configuration{
+		DataSources: []plan.DataSourceConfiguration{
+			{
+				RootNodes: []plan.TypeField{
+					{
+						TypeName:   "Subscription",
+						FieldNames: []string{"foo"},
+					},
+				},
+				Custom: ConfigJson(Configuration{
+					Fetch: FetchConfiguration{
+						URL:    "https://swapi.com/graphql",
+						Method: "POST",
+					},
+					Subscription: SubscriptionConfiguration{
+						URL:               "wss://swapi.com/graphql",
+						FetchInitialState: true,
+					},
+				}),
+				Factory: factory,
+			},
+		},
+		Fields: []plan.FieldConfiguration{
+			{
+				TypeName:  "Subscription",
+				FieldName: "foo",
+				Arguments: []plan.ArgumentConfiguration{
+					{
+						Name:       "bar",
+						SourceType: plan.FieldArgumentSource,
+					},
+				},
+			},
+		},
+		DisableResolveFieldPositions: true,
+	}))
+
 	federationFactory := &Factory{}
 	t.Run("federation", RunTest(federationTestSchema,
 		`	query MyReviews {
@@ -8774,6 +9434,158 @@ func TestSource_Load(t *testing.T) {
 			assert.Equal(t, `{"variables":{"b":null}}`, buf.String())
 		})
 	})
+	t.Run("failover", func(t *testing.T) {
+		unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer unavailable.Close()
+
+		fetch := FetchConfiguration{
+			URL:          unavailable.URL,
+			FailoverURLs: []string{ts.URL},
+		}
+		src := &Source{httpClient: &http.Client{}, upstreams: fetch.upstreamGroup()}
+
+		var input []byte
+		input = httpclient.SetInputBodyWithPath(input, []byte(`{"a":"a"}`), "variables")
+		input = httpclient.SetInputURL(input, []byte(unavailable.URL))
+		buf := bytes.NewBuffer(nil)
+
+		require.NoError(t, src.Load(context.Background(), input, buf))
+		assert.Equal(t, `{"variables":{"a":"a"}}`, buf.String())
+
+		t.Run("mutations are not sent twice", func(t *testing.T) {
+			src := &Source{httpClient: &http.Client{}, upstreams: fetch.upstreamGroup(), mutation: true}
+			buf := bytes.NewBuffer(nil)
+			require.NoError(t, src.Load(context.Background(), input, buf))
+			assert.Equal(t, ``, buf.String())
+		})
+	})
+	t.Run("request signing", func(t *testing.T) {
+		signed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprintf(w, `{"signed":%t}`, r.Header.Get("X-Signature") != "")
+		}))
+		defer signed.Close()
+
+		factory := &Factory{HTTPClient: &http.Client{}, RequestSigner: &httpclient.HMACSigner{Secret: []byte("secret")}}
+		planner := factory.Planner(context.Background()).(*Planner)
+		src := &Source{httpClient: planner.fetchClient}
+
+		var input []byte
+		input = httpclient.SetInputBodyWithPath(input, []byte(`{"a":"a"}`), "variables")
+		input = httpclient.SetInputURL(input, []byte(signed.URL))
+		buf := bytes.NewBuffer(nil)
+
+		require.NoError(t, src.Load(context.Background(), input, buf))
+		assert.Equal(t, `{"signed":true}`, buf.String())
+	})
+	t.Run("proxy", func(t *testing.T) {
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprintf(w, `{"proxied":%q}`, r.URL.Host)
+		}))
+		defer proxy.Close()
+
+		factory := &Factory{HTTPClient: &http.Client{}}
+		planner := factory.Planner(context.Background()).(*Planner)
+		walker := astvisitor.NewWalker(8)
+		require.NoError(t, planner.Register(&plan.Visitor{Walker: &walker}, plan.DataSourceConfiguration{
+			Custom: ConfigJson(Configuration{ProxyURL: proxy.URL}),
+		}, plan.DataSourcePlannerConfiguration{}))
+		src := &Source{httpClient: planner.fetchClient}
+
+		var input []byte
+		input = httpclient.SetInputBodyWithPath(input, []byte(`{"a":"a"}`), "variables")
+		input = httpclient.SetInputURL(input, []byte("http://upstream.internal/graphql"))
+		buf := bytes.NewBuffer(nil)
+
+		require.NoError(t, src.Load(context.Background(), input, buf))
+		assert.Equal(t, `{"proxied":"upstream.internal"}`, buf.String())
+	})
+	t.Run("egress policy", func(t *testing.T) {
+		factory := &Factory{
+			HTTPClient:   &http.Client{},
+			EgressPolicy: &httpclient.EgressPolicy{AllowedHosts: []string{"*.example.com"}},
+		}
+		planner := factory.Planner(context.Background()).(*Planner)
+		walker := astvisitor.NewWalker(8)
+		require.NoError(t, planner.Register(&plan.Visitor{Walker: &walker}, plan.DataSourceConfiguration{
+			Custom: ConfigJson(Configuration{}),
+		}, plan.DataSourcePlannerConfiguration{}))
+		src := &Source{httpClient: planner.fetchClient}
+
+		var input []byte
+		input = httpclient.SetInputBodyWithPath(input, []byte(`{"a":"a"}`), "variables")
+		input = httpclient.SetInputURL(input, []byte("http://169.254.169.254/latest/meta-data"))
+		buf := bytes.NewBuffer(nil)
+
+		err := src.Load(context.Background(), input, buf)
+		var denied *httpclient.EgressDeniedError
+		require.ErrorAs(t, err, &denied)
+		assert.Equal(t, "host is not allowed", denied.Reason)
+	})
+	t.Run("egress policy of subscriptions", func(t *testing.T) {
+		factory := &Factory{
+			HTTPClient:      &http.Client{},
+			StreamingClient: &http.Client{},
+			EgressPolicy:    &httpclient.EgressPolicy{AllowedHosts: []string{"*.example.com"}},
+		}
+		// the planners of the engines sharing the factory are created concurrently
+		wg := sync.WaitGroup{}
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := factory.fetchClient("")
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+		factory.Planner(context.Background())
+
+		for _, client := range []*http.Client{factory.SubscriptionClient.httpClient, factory.SubscriptionClient.streamingClient} {
+			_, err := client.Get("http://169.254.169.254/latest/meta-data")
+			var denied *httpclient.EgressDeniedError
+			require.ErrorAs(t, err, &denied)
+			assert.Equal(t, "host is not allowed", denied.Reason)
+		}
+	})
+	t.Run("get for queries", func(t *testing.T) {
+		get := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprintf(w, `{"method":%q,"query":%q}`, r.Method, r.URL.RawQuery)
+		}))
+		defer get.Close()
+
+		var input []byte
+		input = httpclient.SetInputBodyWithPath(input, []byte(`{"id":"1"}`), "variables")
+		input = httpclient.SetInputBodyWithPath(input, []byte(`query($id: ID!){user(id: $id){name}}`), "query")
+		input = httpclient.SetInputURL(input, []byte(get.URL))
+		input = httpclient.SetInputMethod(input, []byte(http.MethodPost))
+
+		t.Run("should send short queries as GET request", func(t *testing.T) {
+			src := &Source{httpClient: &http.Client{}, maxGETURLLength: 2048}
+			buf := bytes.NewBuffer(nil)
+
+			require.NoError(t, src.Load(context.Background(), input, buf))
+			assert.Equal(t, `{"method":"GET","query":"query=query%28%24id%3A+ID%21%29%7Buser%28id%3A+%24id%29%7Bname%7D%7D&variables=%7B%22id%22%3A%221%22%7D"}`, buf.String())
+		})
+
+		t.Run("should send queries exceeding the maximum URL length as POST request", func(t *testing.T) {
+			src := &Source{httpClient: &http.Client{}, maxGETURLLength: len(get.URL) + 10}
+			buf := bytes.NewBuffer(nil)
+
+			require.NoError(t, src.Load(context.Background(), input, buf))
+			assert.Equal(t, `{"method":"POST","query":""}`, buf.String())
+		})
+
+		t.Run("should send mutations as POST request", func(t *testing.T) {
+			config := Configuration{Fetch: FetchConfiguration{UseGETForQueries: true}}
+			config.ApplyDefaults()
+			planner := &Planner{config: config}
+
+			assert.Equal(t, 2048, planner.maxGETURLLength(ast.OperationTypeQuery))
+			assert.Equal(t, 0, planner.maxGETURLLength(ast.OperationTypeMutation))
+		})
+	})
 }
 
 func TestUnNullVariables(t *testing.T) {
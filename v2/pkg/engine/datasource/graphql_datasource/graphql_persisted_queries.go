@@ -0,0 +1,67 @@
+package graphql_datasource
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"github.com/buger/jsonparser"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/httpclient"
+)
+
+const (
+	persistedQueryNotFoundMessage = "PersistedQueryNotFound"
+	persistedQueryNotFoundCode    = "PERSISTED_QUERY_NOT_FOUND"
+)
+
+// setPersistedQueryExtension adds the sha256 hash of the query to the extensions of the body,
+// so that the Source sends the query only if the upstream doesn't know the hash yet
+func (p *Planner) setPersistedQueryExtension(input []byte) []byte {
+	if !p.config.Fetch.PersistedQueries {
+		return input
+	}
+	query, err := jsonparser.GetString(input, "body", "query")
+	if err != nil {
+		return input
+	}
+	extensions := fmt.Sprintf(`{"persistedQuery":{"version":1,"sha256Hash":"%x"}}`, sha256.Sum256([]byte(query)))
+	return httpclient.SetInputBodyWithPath(input, []byte(extensions), "extensions")
+}
+
+func isPersistedQueryInput(input []byte) bool {
+	_, _, _, err := jsonparser.Get(input, "body", "extensions", "persistedQuery")
+	return err == nil
+}
+
+// loadPersistedQuery sends the hash of the query without the query
+// and falls back to the full query if the upstream responds with PersistedQueryNotFound
+func (s *Source) loadPersistedQuery(ctx context.Context, input []byte, writer io.Writer) error {
+	hashOnlyInput := jsonparser.Delete(append([]byte(nil), input...), "body", "query")
+
+	buf := &bytes.Buffer{}
+	if err := s.do(ctx, hashOnlyInput, buf); err != nil {
+		return err
+	}
+	if !isPersistedQueryNotFound(buf.Bytes()) {
+		_, err := writer.Write(buf.Bytes())
+		return err
+	}
+
+	// the upstream stores the query with the hash of the extensions, so following requests can omit the query
+	return s.do(ctx, input, writer)
+}
+
+func isPersistedQueryNotFound(response []byte) (notFound bool) {
+	_, _ = jsonparser.ArrayEach(response, func(value []byte, _ jsonparser.ValueType, _ int, _ error) {
+		if message, _ := jsonparser.GetString(value, "message"); message == persistedQueryNotFoundMessage {
+			notFound = true
+		}
+		if code, _ := jsonparser.GetString(value, "extensions", "code"); code == persistedQueryNotFoundCode {
+			notFound = true
+		}
+	}, "errors")
+	return notFound
+}
@@ -0,0 +1,91 @@
+package graphql_datasource
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/httpclient"
+)
+
+func TestSource_LoadPersistedQuery(t *testing.T) {
+	const query = `{user {name}}`
+	queryHash := fmt.Sprintf("%x", sha256.Sum256([]byte(query)))
+
+	var (
+		persistedQueries = map[string]string{}
+		requestsWithHash []string
+		requestsWithBody int
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query      string `json:"query"`
+			Extensions struct {
+				PersistedQuery struct {
+					Sha256Hash string `json:"sha256Hash"`
+				} `json:"persistedQuery"`
+			} `json:"extensions"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		hash := body.Extensions.PersistedQuery.Sha256Hash
+		requestsWithHash = append(requestsWithHash, hash)
+		if body.Query != "" {
+			requestsWithBody++
+			persistedQueries[hash] = body.Query
+		}
+		if _, ok := persistedQueries[hash]; !ok {
+			_, _ = fmt.Fprint(w, `{"errors":[{"message":"PersistedQueryNotFound"}]}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"data":{"user":{"name":"Jens"}}}`)
+	}))
+	defer ts.Close()
+
+	planner := &Planner{config: Configuration{Fetch: FetchConfiguration{PersistedQueries: true}}}
+
+	var input []byte
+	input = httpclient.SetInputBodyWithPath(input, []byte(query), "query")
+	input = planner.setPersistedQueryExtension(input)
+	input = httpclient.SetInputURL(input, []byte(ts.URL))
+	assert.JSONEq(t, fmt.Sprintf(`{"body":{"query":"{user {name}}","extensions":{"persistedQuery":{"version":1,"sha256Hash":"%s"}}},"url":"%s"}`, queryHash, ts.URL), string(input))
+
+	src := &Source{httpClient: &http.Client{}}
+
+	t.Run("should send the query if the upstream doesn't know the hash", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, src.Load(context.Background(), input, buf))
+		assert.Equal(t, `{"data":{"user":{"name":"Jens"}}}`, buf.String())
+		assert.Equal(t, []string{queryHash, queryHash}, requestsWithHash)
+		assert.Equal(t, 1, requestsWithBody)
+	})
+
+	t.Run("should send only the hash if the upstream knows the hash", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, src.Load(context.Background(), input, buf))
+		assert.Equal(t, `{"data":{"user":{"name":"Jens"}}}`, buf.String())
+		assert.Equal(t, []string{queryHash, queryHash, queryHash}, requestsWithHash)
+		assert.Equal(t, 1, requestsWithBody)
+	})
+
+	t.Run("should not add the hash if persisted queries are disabled", func(t *testing.T) {
+		planner := &Planner{}
+		input := httpclient.SetInputBodyWithPath(nil, []byte(query), "query")
+		assert.Equal(t, `{"body":{"query":"{user {name}}"}}`, string(planner.setPersistedQueryExtension(input)))
+	})
+}
+
+func TestIsPersistedQueryNotFound(t *testing.T) {
+	assert.True(t, isPersistedQueryNotFound([]byte(`{"errors":[{"message":"PersistedQueryNotFound"}]}`)))
+	assert.True(t, isPersistedQueryNotFound([]byte(`{"errors":[{"message":"not found","extensions":{"code":"PERSISTED_QUERY_NOT_FOUND"}}]}`)))
+	assert.False(t, isPersistedQueryNotFound([]byte(`{"errors":[{"message":"Cannot query field"}]}`)))
+	assert.False(t, isPersistedQueryNotFound([]byte(`{"data":{"user":null}}`)))
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/buger/jsonparser"
 	"github.com/cespare/xxhash/v2"
 	"github.com/jensneuse/abstractlogger"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/httpclient"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
 	"nhooyr.io/websocket"
 )
@@ -24,6 +25,8 @@ const ackWaitTimeout = 30 * time.Second
 type SubscriptionClient struct {
 	streamingClient            *http.Client
 	httpClient                 *http.Client
+	streamingProxyClients      *httpclient.ProxyClients
+	httpProxyClients           *httpclient.ProxyClients
 	engineCtx                  context.Context
 	log                        abstractlogger.Logger
 	hashPool                   sync.Pool
@@ -31,6 +34,8 @@ type SubscriptionClient struct {
 	handlersMu                 sync.Mutex
 	wsSubProtocol              string
 	onWsConnectionInitCallback *OnWsConnectionInitCallback
+	resubscribe                *ResubscribeOptions
+	callbackHandler            *SubscriptionCallbackHandler
 
 	readTimeout time.Duration
 }
@@ -61,11 +66,20 @@ func WithOnWsConnectionInitCallback(callback *OnWsConnectionInitCallback) Option
 	}
 }
 
+// WithSubscriptionCallbackHandler enables subscriptions with the callback protocol, see SubscriptionConfiguration.UseCallback
+func WithSubscriptionCallbackHandler(handler *SubscriptionCallbackHandler) Options {
+	return func(options *opts) {
+		options.callbackHandler = handler
+	}
+}
+
 type opts struct {
 	readTimeout                time.Duration
 	log                        abstractlogger.Logger
 	wsSubProtocol              string
 	onWsConnectionInitCallback *OnWsConnectionInitCallback
+	resubscribe                *ResubscribeOptions
+	callbackHandler            *SubscriptionCallbackHandler
 }
 
 // GraphQLSubscriptionClientFactory abstracts the way of creating a new GraphQLSubscriptionClient.
@@ -89,12 +103,14 @@ func NewGraphQLSubscriptionClient(httpClient, streamingClient *http.Client, engi
 		option(op)
 	}
 	return &SubscriptionClient{
-		httpClient:      httpClient,
-		streamingClient: streamingClient,
-		engineCtx:       engineCtx,
-		handlers:        make(map[uint64]ConnectionHandler),
-		log:             op.log,
-		readTimeout:     op.readTimeout,
+		httpClient:            httpClient,
+		streamingClient:       streamingClient,
+		httpProxyClients:      httpclient.NewProxyClients(httpClient),
+		streamingProxyClients: httpclient.NewProxyClients(streamingClient),
+		engineCtx:             engineCtx,
+		handlers:              make(map[uint64]ConnectionHandler),
+		log:                   op.log,
+		readTimeout:           op.readTimeout,
 		hashPool: sync.Pool{
 			New: func() interface{} {
 				return xxhash.New()
@@ -102,14 +118,21 @@ func NewGraphQLSubscriptionClient(httpClient, streamingClient *http.Client, engi
 		},
 		wsSubProtocol:              op.wsSubProtocol,
 		onWsConnectionInitCallback: op.onWsConnectionInitCallback,
+		resubscribe:                op.resubscribe,
+		callbackHandler:            op.callbackHandler,
 	}
 }
 
 // Subscribe initiates a new GraphQL Subscription with the origin
 // If an existing WS connection with the same ID (Hash) exists, it is being re-used
 // If connection protocol is SSE, a new connection is always created
+// If the callback protocol is used, the subscription is registered with the callback handler of the client
 // If no connection exists, the client initiates a new one
 func (c *SubscriptionClient) Subscribe(reqCtx *resolve.Context, options GraphQLSubscriptionOptions, updater resolve.SubscriptionUpdater) error {
+	if options.UseCallback {
+		return c.subscribeCallback(reqCtx, options, updater)
+	}
+
 	if options.UseSSE {
 		return c.subscribeSSE(reqCtx, options, updater)
 	}
@@ -120,6 +143,7 @@ func (c *SubscriptionClient) Subscribe(reqCtx *resolve.Context, options GraphQLS
 var (
 	withSSE           = []byte(`sse:true`)
 	withSSEMethodPost = []byte(`sse_method_post:true`)
+	withCallback      = []byte(`callback:true`)
 )
 
 func (c *SubscriptionClient) UniqueRequestID(ctx *resolve.Context, options GraphQLSubscriptionOptions, hash *xxhash.Digest) (err error) {
@@ -135,6 +159,12 @@ func (c *SubscriptionClient) UniqueRequestID(ctx *resolve.Context, options Graph
 			return err
 		}
 	}
+	if options.UseCallback {
+		_, err = hash.Write(withCallback)
+		if err != nil {
+			return err
+		}
+	}
 	return c.requestHash(ctx, options, hash)
 }
 
@@ -149,18 +179,29 @@ func (c *SubscriptionClient) subscribeSSE(reqCtx *resolve.Context, options Graph
 		updater: updater,
 	}
 
-	handler := newSSEConnectionHandler(reqCtx, c.streamingClient, options, c.log)
+	streamingClient := c.streamingClient
+	if options.ProxyURL != "" {
+		var err error
+		streamingClient, err = c.streamingProxyClients.Client(options.ProxyURL)
+		if err != nil {
+			return err
+		}
+	}
+
+	handler := newSSEConnectionHandler(reqCtx, streamingClient, options, c.log)
 
+	done := resolve.TrackGoroutine(sub.ctx, "graphql_datasource: sse subscription")
 	go func() {
+		defer done()
 		handler.StartBlocking(sub)
 	}()
 
 	return nil
 }
 
-func (c *SubscriptionClient) subscribeWS(reqCtx *resolve.Context, options GraphQLSubscriptionOptions, updater resolve.SubscriptionUpdater) error {
-	if c.httpClient == nil {
-		return fmt.Errorf("http client is nil")
+func (c *SubscriptionClient) subscribeCallback(reqCtx *resolve.Context, options GraphQLSubscriptionOptions, updater resolve.SubscriptionUpdater) error {
+	if c.callbackHandler == nil {
+		return fmt.Errorf("subscription callback handler is nil")
 	}
 
 	sub := Subscription{
@@ -169,36 +210,78 @@ func (c *SubscriptionClient) subscribeWS(reqCtx *resolve.Context, options GraphQ
 		updater: updater,
 	}
 
+	httpClient := c.httpClient
+	if options.ProxyURL != "" {
+		var err error
+		httpClient, err = c.httpProxyClients.Client(options.ProxyURL)
+		if err != nil {
+			return err
+		}
+	}
+
+	done := resolve.TrackGoroutine(sub.ctx, "graphql_datasource: callback subscription")
+	go func() {
+		defer done()
+		c.callbackHandler.StartBlocking(httpClient, sub, c.log)
+	}()
+
+	return nil
+}
+
+func (c *SubscriptionClient) subscribeWS(reqCtx *resolve.Context, options GraphQLSubscriptionOptions, updater resolve.SubscriptionUpdater) error {
+	if c.httpClient == nil {
+		return fmt.Errorf("http client is nil")
+	}
+
 	// each WS connection to an origin is uniquely identified by the Hash(URL,Headers,Body)
 	handlerID, err := c.generateHandlerIDHash(reqCtx, options)
 	if err != nil {
 		return err
 	}
 
+	return c.startWS(Subscription{
+		ctx:       reqCtx.Context(),
+		options:   options,
+		updater:   updater,
+		handlerID: handlerID,
+	})
+}
+
+// startWS adds the subscription to the connection handler of its handlerID or starts a new one
+func (c *SubscriptionClient) startWS(sub Subscription) error {
 	c.handlersMu.Lock()
 	defer c.handlersMu.Unlock()
-	handler, exists := c.handlers[handlerID]
+	handler, exists := c.handlers[sub.handlerID]
 	if exists {
 		select {
 		case handler.SubscribeCH() <- sub:
-		case <-reqCtx.Context().Done():
+		case <-sub.ctx.Done():
 		}
 		return nil
 	}
 
-	handler, err = c.newWSConnectionHandler(reqCtx.Context(), options)
+	handler, err := c.newWSConnectionHandler(sub.ctx, sub.options)
 	if err != nil {
 		return err
 	}
 
-	c.handlers[handlerID] = handler
+	c.handlers[sub.handlerID] = handler
 
+	done := resolve.TrackGoroutine(sub.ctx, "graphql_datasource: websocket connection")
 	go func(handlerID uint64) {
+		defer done()
 		handler.StartBlocking(sub)
 		c.handlersMu.Lock()
 		delete(c.handlers, handlerID)
 		c.handlersMu.Unlock()
-	}(handlerID)
+
+		// resubscribe after the handler was removed, so that a new connection is created
+		if resumable, ok := handler.(resumableConnectionHandler); ok {
+			for _, resumableSub := range resumable.ResumableSubscriptions() {
+				go c.resubscribeWS(resumableSub)
+			}
+		}
+	}(sub.handlerID)
 
 	return nil
 }
@@ -286,8 +369,17 @@ func (c *SubscriptionClient) newWSConnectionHandler(reqCtx context.Context, opti
 		subProtocols = []string{c.wsSubProtocol}
 	}
 
+	httpClient := c.httpClient
+	if options.ProxyURL != "" {
+		var err error
+		httpClient, err = c.httpProxyClients.Client(options.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	conn, upgradeResponse, err := websocket.Dial(reqCtx, options.URL, &websocket.DialOptions{
-		HTTPClient:      c.httpClient,
+		HTTPClient:      httpClient,
 		HTTPHeader:      options.Header,
 		CompressionMode: websocket.CompressionDisabled,
 		Subprotocols:    subProtocols,
@@ -337,9 +429,13 @@ func (c *SubscriptionClient) newWSConnectionHandler(reqCtx context.Context, opti
 
 	switch c.wsSubProtocol {
 	case ProtocolGraphQLWS:
-		return newGQLWSConnectionHandler(c.engineCtx, conn, c.readTimeout, c.log), nil
+		handler := newGQLWSConnectionHandler(c.engineCtx, conn, c.readTimeout, c.log)
+		handler.shouldResume = c.shouldResume
+		return handler, nil
 	case ProtocolGraphQLTWS:
-		return newGQLTWSConnectionHandler(c.engineCtx, conn, c.readTimeout, c.log), nil
+		handler := newGQLTWSConnectionHandler(c.engineCtx, conn, c.readTimeout, c.log)
+		handler.shouldResume = c.shouldResume
+		return handler, nil
 	default:
 		return nil, fmt.Errorf("unknown protocol %s", conn.Subprotocol())
 	}
@@ -378,6 +474,10 @@ type Subscription struct {
 	ctx     context.Context
 	options GraphQLSubscriptionOptions
 	updater resolve.SubscriptionUpdater
+	// handlerID identifies the websocket connection handler of the subscription
+	handlerID uint64
+	// resumedNotice is sent to the subscriber once the subscription was resubscribed
+	resumedNotice []byte
 }
 
 func waitForAck(ctx context.Context, conn *websocket.Conn) error {
@@ -295,3 +295,88 @@ func TestWebsocketSubscriptionClientWithServerDisconnect(t *testing.T) {
 		return len(client.handlers) == 0
 	}, time.Second, time.Millisecond, "client handlers not 0")
 }
+
+func TestWebsocketSubscriptionClientResubscribeOnRetryableClose(t *testing.T) {
+	connections := atomic.NewInt32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+			Subprotocols: []string{ProtocolGraphQLTWS},
+		})
+		assert.NoError(t, err)
+		ctx := context.Background()
+		_, data, err := conn.Read(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"type":"connection_init"}`, string(data))
+		err = conn.Write(ctx, websocket.MessageText, []byte(`{"type":"connection_ack"}`))
+		assert.NoError(t, err)
+		_, data, err = conn.Read(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"id":"1","type":"subscribe","payload":{"query":"subscription {messageAdded(roomName: \"room\"){text}}"}}`, string(data))
+
+		if connections.Inc() == 1 {
+			err = conn.Write(ctx, websocket.MessageText, []byte(`{"type":"next","id":"1","payload":{"data":{"messageAdded":{"text":"before restart"}}}}`))
+			assert.NoError(t, err)
+			_ = conn.Close(websocket.StatusServiceRestart, "redeploy")
+			return
+		}
+
+		err = conn.Write(ctx, websocket.MessageText, []byte(`{"type":"next","id":"1","payload":{"data":{"messageAdded":{"text":"after restart"}}}}`))
+		assert.NoError(t, err)
+		_, _, _ = conn.Read(ctx)
+	}))
+	defer server.Close()
+
+	ctx, clientCancel := context.WithCancel(context.Background())
+	defer clientCancel()
+	serverCtx, serverCancel := context.WithCancel(context.Background())
+	defer serverCancel()
+
+	client := NewGraphQLSubscriptionClient(http.DefaultClient, http.DefaultClient, serverCtx,
+		WithReadTimeout(time.Millisecond),
+		WithLogger(logger()),
+		WithWSSubProtocol(ProtocolGraphQLTWS),
+		WithResubscribeOnRetryableClose(ResubscribeOptions{
+			Backoff:       time.Millisecond,
+			ResumedNotice: []byte(`{"extensions":{"streamResumed":true}}`),
+		}),
+	)
+	updater := &testSubscriptionUpdater{}
+	err := client.Subscribe(resolve.NewContext(ctx), GraphQLSubscriptionOptions{
+		URL: server.URL,
+		Body: GraphQLBody{
+			Query: `subscription {messageAdded(roomName: "room"){text}}`,
+		},
+	}, updater)
+	assert.NoError(t, err)
+	updater.AwaitUpdates(t, time.Second, 3)
+
+	updater.mux.Lock()
+	defer updater.mux.Unlock()
+	assert.Equal(t, []string{
+		`{"data":{"messageAdded":{"text":"before restart"}}}`,
+		`{"extensions":{"streamResumed":true}}`,
+		`{"data":{"messageAdded":{"text":"after restart"}}}`,
+	}, updater.updates)
+	assert.False(t, updater.done)
+	assert.Equal(t, int32(2), connections.Load())
+}
+
+func TestWebsocketSubscriptionClientProxy(t *testing.T) {
+	proxiedHosts := make(chan string, 1)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHosts <- r.URL.Host
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer proxy.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := NewGraphQLSubscriptionClient(http.DefaultClient, http.DefaultClient, ctx)
+	_, err := client.newWSConnectionHandler(ctx, GraphQLSubscriptionOptions{
+		URL:      "ws://upstream.internal/graphql",
+		ProxyURL: proxy.URL,
+	})
+	assert.Error(t, err)
+	assert.Equal(t, "upstream.internal", <-proxiedHosts)
+}
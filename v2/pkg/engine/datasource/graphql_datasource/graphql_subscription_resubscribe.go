@@ -0,0 +1,97 @@
+package graphql_datasource
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jensneuse/abstractlogger"
+	"nhooyr.io/websocket"
+)
+
+// DefaultRetryableCloseCodes are the close codes an origin sends when it goes away temporarily, e.g. during a deployment.
+var DefaultRetryableCloseCodes = []websocket.StatusCode{
+	websocket.StatusGoingAway,
+	websocket.StatusServiceRestart,
+	websocket.StatusTryAgainLater,
+}
+
+// ResubscribeOptions configures resubscribing to the origin when it closes a websocket connection with a retryable close code.
+// The subscriptions of the connection are started again with the same operation and keep forwarding events to their subscribers.
+type ResubscribeOptions struct {
+	// MaxAttempts is the number of attempts to resubscribe before the subscription fails. It defaults to 3.
+	MaxAttempts int
+	// Backoff is the delay before the first attempt, it grows linearly with every attempt. It defaults to 1s.
+	Backoff time.Duration
+	// RetryableCloseCodes defaults to DefaultRetryableCloseCodes.
+	RetryableCloseCodes []websocket.StatusCode
+	// ResumedNotice is sent as update to the subscribers after resubscribing, e.g. a response with extensions.
+	// No notice is sent if it is empty.
+	ResumedNotice []byte
+}
+
+func WithResubscribeOnRetryableClose(resubscribeOptions ResubscribeOptions) Options {
+	return func(options *opts) {
+		if resubscribeOptions.MaxAttempts == 0 {
+			resubscribeOptions.MaxAttempts = 3
+		}
+		if resubscribeOptions.Backoff == 0 {
+			resubscribeOptions.Backoff = time.Second
+		}
+		if resubscribeOptions.RetryableCloseCodes == nil {
+			resubscribeOptions.RetryableCloseCodes = DefaultRetryableCloseCodes
+		}
+		options.resubscribe = &resubscribeOptions
+	}
+}
+
+// resumableConnectionHandler is implemented by connection handlers which hand over their subscriptions
+// when the origin closed the connection with a retryable close code
+type resumableConnectionHandler interface {
+	ResumableSubscriptions() []Subscription
+}
+
+// shouldResume returns true if the connection was closed by the origin with a retryable close code
+func (c *SubscriptionClient) shouldResume(err error) bool {
+	if c.resubscribe == nil {
+		return false
+	}
+	var closeErr websocket.CloseError
+	if !errors.As(err, &closeErr) {
+		return false
+	}
+	for _, code := range c.resubscribe.RetryableCloseCodes {
+		if closeErr.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// resubscribeWS starts the subscription on a new connection to the origin
+// if all attempts fail, the subscriber receives an error and the subscription completes
+func (c *SubscriptionClient) resubscribeWS(sub Subscription) {
+	sub.resumedNotice = c.resubscribe.ResumedNotice
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-sub.ctx.Done():
+			sub.updater.Done()
+			return
+		case <-time.After(c.resubscribe.Backoff * time.Duration(attempt)):
+		}
+
+		err := c.startWS(sub)
+		if err == nil {
+			return
+		}
+		c.log.Error("SubscriptionClient.resubscribeWS",
+			abstractlogger.Error(err),
+			abstractlogger.Int("attempt", attempt),
+		)
+		if attempt >= c.resubscribe.MaxAttempts {
+			sub.updater.Update([]byte(fmt.Sprintf(errorMessageTemplate, err)))
+			sub.updater.Done()
+			return
+		}
+	}
+}
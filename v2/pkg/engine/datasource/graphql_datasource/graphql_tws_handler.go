@@ -23,6 +23,9 @@ type gqlTWSConnectionHandler struct {
 	nextSubscriptionID int
 	subscriptions      map[string]Subscription
 	readTimeout        time.Duration
+	// shouldResume returns true if the subscriptions should be resubscribed after the connection closed with err
+	shouldResume func(err error) bool
+	resumable    []Subscription
 }
 
 func newGQLTWSConnectionHandler(ctx context.Context, conn *websocket.Conn, rt time.Duration, l log.Logger) *gqlTWSConnectionHandler {
@@ -64,6 +67,10 @@ func (h *gqlTWSConnectionHandler) StartBlocking(sub Subscription) {
 		case sub = <-h.subscribeCh:
 			h.subscribe(sub)
 		case err := <-errCh:
+			if h.resume(err) {
+				h.log.Debug("gqlTWSConnectionHandler.StartBlocking: resubscribing after retryable close", log.Error(err))
+				return
+			}
 			h.log.Error("gqlWSConnectionHandler.StartBlocking", log.Error(err))
 			h.broadcastErrorMessage(err)
 			return
@@ -133,6 +140,25 @@ func (h *gqlTWSConnectionHandler) subscribe(sub Subscription) {
 	}
 
 	h.subscriptions[subscriptionID] = sub
+	if len(sub.resumedNotice) != 0 {
+		sub.updater.Update(sub.resumedNotice)
+	}
+}
+
+// resume moves the subscriptions to the resumable subscriptions if the connection was closed with a retryable close code
+func (h *gqlTWSConnectionHandler) resume(err error) bool {
+	if h.shouldResume == nil || !h.shouldResume(err) {
+		return false
+	}
+	for id, sub := range h.subscriptions {
+		h.resumable = append(h.resumable, sub)
+		delete(h.subscriptions, id)
+	}
+	return true
+}
+
+func (h *gqlTWSConnectionHandler) ResumableSubscriptions() []Subscription {
+	return h.resumable
 }
 
 func (h *gqlTWSConnectionHandler) broadcastErrorMessage(err error) {
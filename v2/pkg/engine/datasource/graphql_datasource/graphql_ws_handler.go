@@ -24,6 +24,9 @@ type gqlWSConnectionHandler struct {
 	nextSubscriptionID int
 	subscriptions      map[string]Subscription
 	readTimeout        time.Duration
+	// shouldResume returns true if the subscriptions should be resubscribed after the connection closed with err
+	shouldResume func(err error) bool
+	resumable    []Subscription
 }
 
 func newGQLWSConnectionHandler(ctx context.Context, conn *websocket.Conn, readTimeout time.Duration, log abstractlogger.Logger) *gqlWSConnectionHandler {
@@ -73,6 +76,10 @@ func (h *gqlWSConnectionHandler) StartBlocking(sub Subscription) {
 		case sub = <-h.subscribeCh:
 			h.subscribe(sub)
 		case err = <-errCh:
+			if h.resume(err) {
+				h.log.Debug("gqlWSConnectionHandler.StartBlocking: resubscribing after retryable close", abstractlogger.Error(err))
+				return
+			}
 			if !errors.Is(err, context.Canceled) {
 				h.log.Error("gqlWSConnectionHandler.StartBlocking", abstractlogger.Error(err))
 			}
@@ -151,6 +158,25 @@ func (h *gqlWSConnectionHandler) subscribe(sub Subscription) {
 	}
 
 	h.subscriptions[subscriptionID] = sub
+	if len(sub.resumedNotice) != 0 {
+		sub.updater.Update(sub.resumedNotice)
+	}
+}
+
+// resume moves the subscriptions to the resumable subscriptions if the connection was closed with a retryable close code
+func (h *gqlWSConnectionHandler) resume(err error) bool {
+	if h.shouldResume == nil || !h.shouldResume(err) {
+		return false
+	}
+	for id, sub := range h.subscriptions {
+		h.resumable = append(h.resumable, sub)
+		delete(h.subscriptions, id)
+	}
+	return true
+}
+
+func (h *gqlWSConnectionHandler) ResumableSubscriptions() []Subscription {
+	return h.resumable
 }
 
 func (h *gqlWSConnectionHandler) handleMessageTypeData(data []byte) {
@@ -0,0 +1,107 @@
+package graphql_datasource
+
+import (
+	"bytes"
+
+	"github.com/buger/jsonparser"
+	"github.com/cespare/xxhash/v2"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+// StickyRoutingConfiguration defines the key used to choose the upstream instance of a subscription.
+// Subscriptions with the same key are always routed to the same instance, as long as the instances don't change.
+type StickyRoutingConfiguration struct {
+	// ArgumentNames are names of arguments of the root subscription field, e.g. the id of the subscribed entity.
+	ArgumentNames []string
+	// ClientHeaderNames are names of client request headers identifying the subscriber, e.g. Authorization.
+	ClientHeaderNames []string
+}
+
+// stickyRouter chooses one of the instance urls by rendezvous hashing of the routing key.
+// Compared to hashing modulo the number of instances, only the subscriptions of a removed instance move on changes.
+type stickyRouter struct {
+	urls              []string
+	variableNames     []string
+	clientHeaderNames []string
+}
+
+// stickyRouter returns the router for the subscription or nil if no instance urls are configured.
+// The configured argument names are resolved to the names of the variables holding their values.
+func (p *Planner) stickyRouter() *stickyRouter {
+	if len(p.config.Subscription.InstanceURLs) == 0 {
+		return nil
+	}
+
+	router := &stickyRouter{
+		urls:              p.config.Subscription.InstanceURLs,
+		clientHeaderNames: p.config.Subscription.StickyRouting.ClientHeaderNames,
+	}
+
+	if p.rootFieldRef == -1 {
+		return router
+	}
+
+	for _, argumentName := range p.config.Subscription.StickyRouting.ArgumentNames {
+		for _, argumentRef := range p.visitor.Operation.FieldArguments(p.rootFieldRef) {
+			if p.visitor.Operation.ArgumentNameString(argumentRef) != argumentName {
+				continue
+			}
+			value := p.visitor.Operation.ArgumentValue(argumentRef)
+			if value.Kind != ast.ValueKindVariable {
+				continue
+			}
+			router.variableNames = append(router.variableNames, p.visitor.Operation.VariableValueNameString(value.Ref))
+		}
+	}
+
+	return router
+}
+
+// url returns the instance url for the subscription.
+// If the subscription has no routing key, the url of the options is used.
+func (r *stickyRouter) url(ctx *resolve.Context, options GraphQLSubscriptionOptions) string {
+	key := r.key(ctx, options)
+	if len(key) == 0 {
+		return options.URL
+	}
+
+	var (
+		url       string
+		bestScore uint64
+		digest    = xxhash.New()
+	)
+	for i := range r.urls {
+		digest.Reset()
+		_, _ = digest.Write(key)
+		_, _ = digest.WriteString(r.urls[i])
+		if score := digest.Sum64(); i == 0 || score > bestScore {
+			url, bestScore = r.urls[i], score
+		}
+	}
+	return url
+}
+
+func (r *stickyRouter) key(ctx *resolve.Context, options GraphQLSubscriptionOptions) []byte {
+	key := &bytes.Buffer{}
+	for _, variableName := range r.variableNames {
+		value, _, _, err := jsonparser.Get(options.Body.Variables, variableName)
+		if err != nil {
+			continue
+		}
+		key.WriteString(variableName)
+		key.WriteByte('=')
+		key.Write(value)
+		key.WriteByte(0)
+	}
+	for _, headerName := range r.clientHeaderNames {
+		for _, value := range ctx.Request.Header.Values(headerName) {
+			key.WriteString(headerName)
+			key.WriteByte('=')
+			key.WriteString(value)
+			key.WriteByte(0)
+		}
+	}
+	return key.Bytes()
+}
@@ -0,0 +1,141 @@
+package graphql_datasource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astnormalization"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+func TestPlanner_StickyRouter(t *testing.T) {
+	definition := unsafeparser.ParseGraphqlDocumentString(`
+		type Subscription {
+			foo(bar: String): Int!
+		}
+	`)
+	require.NoError(t, asttransform.MergeDefinitionWithBaseSchema(&definition))
+	operation := unsafeparser.ParseGraphqlDocumentString(`subscription { foo(bar: "baz") }`)
+
+	report := &operationreport.Report{}
+	astnormalization.NewNormalizer(true, true).NormalizeOperation(&operation, &definition, report)
+	require.False(t, report.HasErrors(), report.Error())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	planner := plan.NewPlanner(ctx, plan.Configuration{
+		DataSources: []plan.DataSourceConfiguration{
+			{
+				RootNodes: []plan.TypeField{
+					{TypeName: "Subscription", FieldNames: []string{"foo"}},
+				},
+				Custom: ConfigJson(Configuration{
+					Subscription: SubscriptionConfiguration{
+						URL:          "wss://swapi.com/graphql",
+						InstanceURLs: []string{"wss://eu.swapi.com/graphql", "wss://us.swapi.com/graphql"},
+						StickyRouting: StickyRoutingConfiguration{
+							ArgumentNames:     []string{"bar"},
+							ClientHeaderNames: []string{"Authorization"},
+						},
+					},
+				}),
+				Factory: &Factory{HTTPClient: http.DefaultClient},
+			},
+		},
+		Fields: []plan.FieldConfiguration{
+			{
+				TypeName:  "Subscription",
+				FieldName: "foo",
+				Arguments: []plan.ArgumentConfiguration{
+					{Name: "bar", SourceType: plan.FieldArgumentSource},
+				},
+			},
+		},
+		DisableResolveFieldPositions: true,
+	})
+	actualPlan := planner.Plan(&operation, &definition, "", report)
+	require.False(t, report.HasErrors(), report.Error())
+
+	subscriptionPlan, ok := actualPlan.(*plan.SubscriptionResponsePlan)
+	require.True(t, ok)
+	source, ok := subscriptionPlan.Response.Trigger.Source.(*SubscriptionSource)
+	require.True(t, ok)
+	assert.Equal(t, &stickyRouter{
+		urls:              []string{"wss://eu.swapi.com/graphql", "wss://us.swapi.com/graphql"},
+		variableNames:     []string{"a"},
+		clientHeaderNames: []string{"Authorization"},
+	}, source.router)
+}
+
+func TestStickyRouter_URL(t *testing.T) {
+	router := &stickyRouter{
+		urls:              []string{"http://instance-a", "http://instance-b", "http://instance-c"},
+		variableNames:     []string{"id"},
+		clientHeaderNames: []string{"Authorization"},
+	}
+
+	options := func(variables string) GraphQLSubscriptionOptions {
+		return GraphQLSubscriptionOptions{
+			URL: "http://default",
+			Body: GraphQLBody{
+				Variables: []byte(variables),
+			},
+		}
+	}
+
+	ctx := resolve.NewContext(context.Background())
+
+	t.Run("should use the url of the options without routing key", func(t *testing.T) {
+		assert.Equal(t, "http://default", router.url(ctx, options(`{"other":1}`)))
+	})
+
+	t.Run("should route the same key to the same instance", func(t *testing.T) {
+		url := router.url(ctx, options(`{"id":"1"}`))
+		assert.Contains(t, router.urls, url)
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, url, router.url(ctx, options(`{"id":"1"}`)))
+		}
+	})
+
+	t.Run("should distribute keys across instances", func(t *testing.T) {
+		used := map[string]struct{}{}
+		for i := 0; i < 100; i++ {
+			used[router.url(ctx, options(fmt.Sprintf(`{"id":"%d"}`, i)))] = struct{}{}
+		}
+		assert.Len(t, used, len(router.urls))
+	})
+
+	t.Run("should include client headers in the key", func(t *testing.T) {
+		used := map[string]struct{}{}
+		for i := 0; i < 100; i++ {
+			headerCtx := resolve.NewContext(context.Background())
+			headerCtx.Request.Header = http.Header{"Authorization": []string{fmt.Sprintf("Bearer %d", i)}}
+			used[router.url(headerCtx, options(`{}`))] = struct{}{}
+		}
+		assert.Len(t, used, len(router.urls))
+	})
+
+	t.Run("should only move keys of a removed instance", func(t *testing.T) {
+		reduced := &stickyRouter{
+			urls:          []string{"http://instance-a", "http://instance-b"},
+			variableNames: []string{"id"},
+		}
+		for i := 0; i < 100; i++ {
+			opts := options(fmt.Sprintf(`{"id":"%d"}`, i))
+			url := router.url(ctx, opts)
+			if url == "http://instance-c" {
+				continue
+			}
+			assert.Equal(t, url, reduced.url(ctx, opts))
+		}
+	})
+}
@@ -0,0 +1,209 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// Resolver resolves the host names of the upstreams, net.DefaultResolver implements it
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// EgressPolicy restricts the addresses a client can connect to,
+// which prevents SSRF when the URLs of a datasource are partially templated from user input
+// Link-local addresses, which include the cloud metadata endpoints, are always blocked unless they are in AllowedNetworks
+// The policy is enforced when the connections are dialed, so the checked addresses are the connected ones.
+// If the client sends a request through a proxy, the addresses of the host of the request are checked before the request is sent,
+// and the policy applies to the connections to the proxy as well
+type EgressPolicy struct {
+	// AllowedHosts are the allowed host names, e.g. "api.example.com", or "*.example.com" for all subdomains
+	// If AllowedHosts and AllowedNetworks are empty, all hosts are allowed
+	AllowedHosts []string
+	// AllowedNetworks are the allowed networks of the resolved addresses, e.g. 10.0.0.0/8
+	// The networks are allowed even if they are blocked otherwise
+	AllowedNetworks []netip.Prefix
+	// BlockPrivateNetworks additionally blocks loopback, private and unspecified addresses
+	BlockPrivateNetworks bool
+	// Resolver resolves the host names, defaults to net.DefaultResolver
+	Resolver Resolver
+}
+
+// metadataNetworks are the metadata endpoints of cloud providers outside the link-local ranges
+var metadataNetworks = []netip.Prefix{
+	netip.MustParsePrefix("fd00:ec2::254/128"),
+	netip.MustParsePrefix("100.100.100.200/32"),
+}
+
+// EgressDeniedError is returned for connections the EgressPolicy denies
+type EgressDeniedError struct {
+	Host   string
+	Reason string
+}
+
+func (e *EgressDeniedError) Error() string {
+	return fmt.Sprintf("egress to %s denied: %s", e.Host, e.Reason)
+}
+
+// NewEgressClient returns a copy of the client which only connects to the addresses the policy allows
+// The transport of the client has to be nil or an *http.Transport.
+// The proxy of the environment is not used, because the policy can't check the connections of the proxy to the upstreams,
+// proxies are configured explicitly with the transport of the client or with NewProxyClient
+func NewEgressClient(client *http.Client, policy *EgressPolicy) (*http.Client, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	httpTransport, ok := transport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("unable to configure the egress policy on a transport of type %T", transport)
+	}
+
+	egressTransport := httpTransport.Clone()
+	if isProxyFromEnvironment(egressTransport.Proxy) {
+		egressTransport.Proxy = nil
+	}
+	dial := egressTransport.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	egressTransport.DialContext = policy.dialContext(dial)
+	egressClient := *client
+	egressClient.Transport = &egressRoundTripper{policy: policy, transport: egressTransport}
+	return &egressClient, nil
+}
+
+func isProxyFromEnvironment(proxy func(*http.Request) (*url.URL, error)) bool {
+	return proxy != nil && reflect.ValueOf(proxy).Pointer() == reflect.ValueOf(http.ProxyFromEnvironment).Pointer()
+}
+
+// egressRoundTripper checks the host of the requests sent through a proxy,
+// the connections to all other hosts are checked when they are dialed
+type egressRoundTripper struct {
+	policy    *EgressPolicy
+	transport *http.Transport
+}
+
+func (e *egressRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if e.transport.Proxy != nil {
+		proxyURL, err := e.transport.Proxy(req)
+		if err != nil {
+			return nil, err
+		}
+		if proxyURL != nil {
+			if err := e.policy.checkHost(req.Context(), req.URL.Hostname()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return e.transport.RoundTrip(req)
+}
+
+func (p *EgressPolicy) dialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		addrs, err := p.resolve(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		// each address is checked and dialed directly, so a second lookup can't return a different address
+		var dialErr error
+		for _, ip := range addrs {
+			if err := p.check(host, ip); err != nil {
+				dialErr = err
+				continue
+			}
+			conn, err := dial(ctx, network, net.JoinHostPort(ip.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			dialErr = err
+		}
+		if dialErr == nil {
+			dialErr = &EgressDeniedError{Host: host, Reason: "no addresses found"}
+		}
+		return nil, dialErr
+	}
+}
+
+// checkHost checks all addresses of the host, because the proxy might connect to any of them
+func (p *EgressPolicy) checkHost(ctx context.Context, host string) error {
+	addrs, err := p.resolve(ctx, host)
+	if err != nil {
+		return err
+	}
+	if len(addrs) == 0 {
+		return &EgressDeniedError{Host: host, Reason: "no addresses found"}
+	}
+	for _, ip := range addrs {
+		if err := p.check(host, ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *EgressPolicy) resolve(ctx context.Context, host string) ([]netip.Addr, error) {
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return []netip.Addr{ip}, nil
+	}
+	var resolver Resolver = net.DefaultResolver
+	if p.Resolver != nil {
+		resolver = p.Resolver
+	}
+	return resolver.LookupNetIP(ctx, "ip", host)
+}
+
+func (p *EgressPolicy) check(host string, ip netip.Addr) error {
+	ip = ip.Unmap()
+	for _, network := range p.AllowedNetworks {
+		if network.Contains(ip) {
+			return nil
+		}
+	}
+	if (len(p.AllowedHosts) > 0 || len(p.AllowedNetworks) > 0) && !p.hostAllowed(host) {
+		return &EgressDeniedError{Host: host, Reason: "host is not allowed"}
+	}
+	if ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+		return &EgressDeniedError{Host: host, Reason: fmt.Sprintf("link-local address %s is blocked", ip)}
+	}
+	for _, network := range metadataNetworks {
+		if network.Contains(ip) {
+			return &EgressDeniedError{Host: host, Reason: fmt.Sprintf("metadata address %s is blocked", ip)}
+		}
+	}
+	if p.BlockPrivateNetworks && (ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified()) {
+		return &EgressDeniedError{Host: host, Reason: fmt.Sprintf("private address %s is blocked", ip)}
+	}
+	return nil
+}
+
+func (p *EgressPolicy) hostAllowed(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, allowed := range p.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if suffix, ok := strings.CutPrefix(allowed, "*"); ok {
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+			continue
+		}
+		if host == allowed {
+			return true
+		}
+	}
+	return false
+}
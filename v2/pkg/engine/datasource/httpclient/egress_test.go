@@ -0,0 +1,152 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticResolver map[string][]netip.Addr
+
+func (s staticResolver) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	addrs, ok := s[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addrs, nil
+}
+
+func TestNewEgressClient(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer upstream.Close()
+
+	upstreamURL, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+	port := upstreamURL.Port()
+
+	resolver := staticResolver{
+		"upstream.internal":     {netip.MustParseAddr("127.0.0.1")},
+		"api.upstream.internal": {netip.MustParseAddr("127.0.0.1")},
+		"other.internal":        {netip.MustParseAddr("127.0.0.1")},
+		"metadata.internal":     {netip.MustParseAddr("169.254.169.254")},
+	}
+
+	do := func(t *testing.T, policy *EgressPolicy, host string) (string, error) {
+		policy.Resolver = resolver
+		client, err := NewEgressClient(&http.Client{}, policy)
+		require.NoError(t, err)
+
+		var input []byte
+		input = SetInputMethod(input, []byte("GET"))
+		input = SetInputURL(input, []byte("http://"+net.JoinHostPort(host, port)))
+
+		out := &bytes.Buffer{}
+		err = Do(client, context.Background(), input, out)
+		return out.String(), err
+	}
+
+	assertDenied := func(t *testing.T, err error, expected string) {
+		var denied *EgressDeniedError
+		require.True(t, errors.As(err, &denied), "expected an egress error, got: %v", err)
+		assert.Equal(t, expected, denied.Error())
+	}
+
+	t.Run("should allow the allowed hosts", func(t *testing.T) {
+		policy := &EgressPolicy{AllowedHosts: []string{"upstream.internal", "*.upstream.internal"}}
+
+		out, err := do(t, policy, "upstream.internal")
+		require.NoError(t, err)
+		assert.Equal(t, "ok", out)
+
+		out, err = do(t, policy, "api.upstream.internal")
+		require.NoError(t, err)
+		assert.Equal(t, "ok", out)
+
+		_, err = do(t, policy, "other.internal")
+		assertDenied(t, err, "egress to other.internal denied: host is not allowed")
+	})
+
+	t.Run("should block metadata addresses", func(t *testing.T) {
+		_, err := do(t, &EgressPolicy{}, "metadata.internal")
+		assertDenied(t, err, "egress to metadata.internal denied: link-local address 169.254.169.254 is blocked")
+
+		_, err = do(t, &EgressPolicy{}, "100.100.100.200")
+		assertDenied(t, err, "egress to 100.100.100.200 denied: metadata address 100.100.100.200 is blocked")
+
+		_, err = do(t, &EgressPolicy{AllowedHosts: []string{"metadata.internal"}}, "metadata.internal")
+		assertDenied(t, err, "egress to metadata.internal denied: link-local address 169.254.169.254 is blocked")
+	})
+
+	t.Run("should block private addresses", func(t *testing.T) {
+		_, err := do(t, &EgressPolicy{BlockPrivateNetworks: true}, "upstream.internal")
+		assertDenied(t, err, "egress to upstream.internal denied: private address 127.0.0.1 is blocked")
+
+		out, err := do(t, &EgressPolicy{
+			BlockPrivateNetworks: true,
+			AllowedNetworks:      []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")},
+		}, "upstream.internal")
+		require.NoError(t, err)
+		assert.Equal(t, "ok", out)
+	})
+
+	t.Run("should not use the proxy of the environment", func(t *testing.T) {
+		client, err := NewEgressClient(&http.Client{}, &EgressPolicy{})
+		require.NoError(t, err)
+		assert.Nil(t, client.Transport.(*egressRoundTripper).transport.Proxy)
+
+		proxy := http.ProxyURL(&url.URL{Scheme: "http", Host: "proxy.internal:3128"})
+		client, err = NewEgressClient(&http.Client{Transport: &http.Transport{Proxy: proxy}}, &EgressPolicy{})
+		require.NoError(t, err)
+		assert.NotNil(t, client.Transport.(*egressRoundTripper).transport.Proxy)
+	})
+
+	t.Run("should check the hosts of the requests sent through a proxy", func(t *testing.T) {
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("proxied " + r.URL.String()))
+		}))
+		defer proxy.Close()
+
+		policy := &EgressPolicy{
+			AllowedHosts:    []string{"upstream.internal"},
+			AllowedNetworks: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")},
+			Resolver:        resolver,
+		}
+		egressClient, err := NewEgressClient(&http.Client{}, policy)
+		require.NoError(t, err)
+		client, err := NewProxyClient(egressClient, proxy.URL)
+		require.NoError(t, err)
+
+		doProxied := func(host string) (string, error) {
+			var input []byte
+			input = SetInputMethod(input, []byte("GET"))
+			input = SetInputURL(input, []byte("http://"+host+"/graphql"))
+
+			out := &bytes.Buffer{}
+			err := Do(client, context.Background(), input, out)
+			return out.String(), err
+		}
+
+		out, err := doProxied("upstream.internal")
+		require.NoError(t, err)
+		assert.Equal(t, "proxied http://upstream.internal/graphql", out)
+
+		_, err = doProxied("metadata.internal")
+		assertDenied(t, err, "egress to metadata.internal denied: host is not allowed")
+	})
+
+	t.Run("should reject unsupported transports", func(t *testing.T) {
+		_, err := NewEgressClient(&http.Client{Transport: NewSigningClient(nil, &HMACSigner{}).Transport}, &EgressPolicy{})
+		assert.EqualError(t, err, "unable to configure the egress policy on a transport of type *httpclient.signingTransport")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"net/url"
 
 	"github.com/buger/jsonparser"
 	bytetemplate "github.com/jensneuse/byte-template"
@@ -33,6 +34,8 @@ const (
 	FORWARDED_CLIENT_HEADER_NAMES               = "forwarded_client_header_names"
 	FORWARDED_CLIENT_HEADER_REGULAR_EXPRESSIONS = "forwarded_client_header_regular_expressions"
 	TRACE                                       = "__trace__"
+	// GRAPHQL_GET sends the GraphQL body as query parameters of a GET request
+	GRAPHQL_GET = "graphql_get"
 )
 
 var (
@@ -43,6 +46,7 @@ var (
 		{HEADER},
 		{QUERYPARAMS},
 		{TRACE},
+		{GRAPHQL_GET},
 	}
 	subscriptionInputPaths = [][]string{
 		{URL},
@@ -210,7 +214,7 @@ func SetInputPath(input, path []byte) []byte {
 	return out
 }
 
-func requestInputParams(input []byte) (url, method, body, headers, queryParams []byte, trace bool) {
+func requestInputParams(input []byte) (url, method, body, headers, queryParams []byte, trace, graphQLGet bool) {
 	jsonparser.EachKey(input, func(i int, bytes []byte, valueType jsonparser.ValueType, err error) {
 		switch i {
 		case 0:
@@ -225,11 +229,42 @@ func requestInputParams(input []byte) (url, method, body, headers, queryParams [
 			queryParams = bytes
 		case 5:
 			trace = bytes[0] == 't'
+		case 6:
+			graphQLGet = bytes[0] == 't'
 		}
 	}, inputPaths...)
 	return
 }
 
+// GraphQLGetURL returns the URL of a GET request with the fields of the GraphQL body,
+// e.g. query, variables and extensions, as query parameters
+func GraphQLGetURL(rawURL, body []byte) (string, error) {
+	u, err := url.Parse(string(rawURL))
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	err = jsonparser.ObjectEach(body, func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) error {
+		switch dataType {
+		case jsonparser.Null:
+		case jsonparser.String:
+			str, err := jsonparser.ParseString(value)
+			if err != nil {
+				return err
+			}
+			query.Set(string(key), str)
+		default:
+			query.Set(string(key), string(value))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
 func GetSubscriptionInput(input []byte) (url, header, body []byte) {
 	jsonparser.EachKey(input, func(i int, bytes []byte, valueType jsonparser.ValueType, err error) {
 		switch i {
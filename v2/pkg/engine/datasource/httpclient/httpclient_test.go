@@ -175,6 +175,62 @@ func TestHttpClientDo(t *testing.T) {
 		t.Run("net", runTest(background, input, `ok`))
 	})
 
+	t.Run("headers of the context replace the headers of the input", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, []string{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}, r.Header.Values("Traceparent"))
+			assert.Equal(t, "token", r.Header.Get("Authorization"))
+			_, err := w.Write([]byte("ok"))
+			assert.NoError(t, err)
+		}))
+		defer server.Close()
+		var input []byte
+		input = SetInputMethod(input, []byte("GET"))
+		input = SetInputURL(input, []byte(server.URL))
+		input = SetInputHeader(input, []byte(`{"Authorization":["token"],"Traceparent":["00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"]}`))
+		ctx := WithRequestHeaders(background, http.Header{
+			"Traceparent": []string{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		})
+		t.Run("net", runTest(ctx, input, `ok`))
+	})
+
+	t.Run("response header collector", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Set-Cookie", "session=1")
+			w.Header().Add("Set-Cookie", "region=eu")
+			_, err := w.Write([]byte("ok"))
+			assert.NoError(t, err)
+		}))
+		defer server.Close()
+		var input []byte
+		input = SetInputMethod(input, []byte("GET"))
+		input = SetInputURL(input, []byte(server.URL))
+		collector := &responseHeaderCollector{}
+		t.Run("net", runTest(WithResponseHeaderCollector(background, collector), input, `ok`))
+		assert.Equal(t, []string{"session=1", "region=eu"}, collector.header.Values("Set-Cookie"))
+	})
+
+	t.Run("graphql get", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "1", r.URL.Query().Get("api"))
+			assert.Equal(t, "query($id: ID!){user(id: $id){name}}", r.URL.Query().Get("query"))
+			assert.Equal(t, `{"id":"1"}`, r.URL.Query().Get("variables"))
+			assert.False(t, r.URL.Query().Has("operationName"))
+			actualBody, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Empty(t, actualBody)
+			_, err = w.Write([]byte("ok"))
+			assert.NoError(t, err)
+		}))
+		defer server.Close()
+		var input []byte
+		input = SetInputMethod(input, []byte("POST"))
+		input = SetInputBody(input, []byte(`{"query":"query($id: ID!){user(id: $id){name}}","variables":{"id":"1"},"operationName":null}`))
+		input = SetInputURL(input, []byte(server.URL+"?api=1"))
+		input = SetInputFlag(input, GRAPHQL_GET)
+		t.Run("net", runTest(background, input, `ok`))
+	})
+
 	t.Run("gzip", func(t *testing.T) {
 		body := []byte(`{"foo":"bar"}`)
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -217,3 +273,11 @@ func TestHttpClientDo(t *testing.T) {
 		assert.Contains(t, out.String(), `"Authorization":["****"]`)
 	})
 }
+
+type responseHeaderCollector struct {
+	header http.Header
+}
+
+func (c *responseHeaderCollector) CollectResponseHeaders(header http.Header) {
+	c.header = header.Clone()
+}
@@ -78,11 +78,60 @@ func setResponseStatusCode(ctx context.Context, statusCode int) {
 	}
 }
 
+type requestHeadersKey struct{}
+
+// WithRequestHeaders adds the headers to the requests sent with the context,
+// they replace the headers of the request input with the same name, e.g. to propagate the trace context of a fetch
+func WithRequestHeaders(ctx context.Context, headers http.Header) context.Context {
+	return context.WithValue(ctx, requestHeadersKey{}, headers)
+}
+
+func setContextRequestHeaders(ctx context.Context, request *http.Request) {
+	headers, ok := ctx.Value(requestHeadersKey{}).(http.Header)
+	if !ok {
+		return
+	}
+	for key, values := range headers {
+		request.Header.Del(key)
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+}
+
+// ResponseHeaderCollector collects the headers of the responses of upstream requests, see resolve.ResponseHeaders
+type ResponseHeaderCollector interface {
+	CollectResponseHeaders(header http.Header)
+}
+
+type responseHeaderCollectorKey struct{}
+
+// WithResponseHeaderCollector passes the headers of the responses of the requests sent with the context to the collector,
+// e.g. to forward the Set-Cookie headers of the upstream to the client
+func WithResponseHeaderCollector(ctx context.Context, collector ResponseHeaderCollector) context.Context {
+	return context.WithValue(ctx, responseHeaderCollectorKey{}, collector)
+}
+
+func collectResponseHeaders(ctx context.Context, header http.Header) {
+	if collector, ok := ctx.Value(responseHeaderCollectorKey{}).(ResponseHeaderCollector); ok {
+		collector.CollectResponseHeaders(header)
+	}
+}
+
 func Do(client *http.Client, ctx context.Context, requestInput []byte, out io.Writer) (err error) {
 
-	url, method, body, headers, queryParams, enableTrace := requestInputParams(requestInput)
+	url, method, body, headers, queryParams, enableTrace, graphQLGet := requestInputParams(requestInput)
 
-	request, err := http.NewRequestWithContext(ctx, string(method), string(url), bytes.NewReader(body))
+	var bodyReader io.Reader = bytes.NewReader(body)
+	if graphQLGet {
+		getURL, err := GraphQLGetURL(url, body)
+		if err != nil {
+			return err
+		}
+		url, method, bodyReader = []byte(getURL), []byte(http.MethodGet), http.NoBody
+	}
+
+	request, err := http.NewRequestWithContext(ctx, string(method), string(url), bodyReader)
 	if err != nil {
 		return err
 	}
@@ -135,6 +184,8 @@ func Do(client *http.Client, ctx context.Context, requestInput []byte, out io.Wr
 		request.URL.RawQuery = query.Encode()
 	}
 
+	setContextRequestHeaders(ctx, request)
+
 	request.Header.Add(AcceptHeader, ContentTypeJSON)
 	request.Header.Add(ContentTypeHeader, ContentTypeJSON)
 	request.Header.Set(AcceptEncodingHeader, EncodingGzip)
@@ -147,6 +198,7 @@ func Do(client *http.Client, ctx context.Context, requestInput []byte, out io.Wr
 	defer response.Body.Close()
 
 	setResponseStatusCode(ctx, response.StatusCode)
+	collectResponseHeaders(ctx, response.Header)
 
 	respReader, err := respBodyReader(response)
 	if err != nil {
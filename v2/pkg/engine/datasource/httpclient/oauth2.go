@@ -0,0 +1,133 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OAuth2ClientCredentials is a RequestSigner which adds the bearer token of the OAuth2 client credentials flow to the requests
+// The token is cached and requested again before it expires
+type OAuth2ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// EndpointParams are additional parameters of the token request, e.g. audience
+	EndpointParams url.Values
+	// CredentialsInBody sends the client credentials as parameters of the token request
+	// instead of the basic authorization header, which some providers require
+	CredentialsInBody bool
+	// RefreshBefore is the time before the expiry of the token in which a new token is requested, defaults to 1 minute
+	RefreshBefore time.Duration
+	// HTTPClient requests the tokens, defaults to http.DefaultClient
+	HTTPClient *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+	now    func() time.Time
+}
+
+type oauth2TokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (c *OAuth2ClientCredentials) SignRequest(request *http.Request, _ []byte) error {
+	token, err := c.Token(request.Context())
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Token returns the cached token or requests a new token if the cached token expires within RefreshBefore
+func (c *OAuth2ClientCredentials) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	refreshBefore := c.RefreshBefore
+	if refreshBefore == 0 {
+		refreshBefore = time.Minute
+	}
+	if c.token != "" && (c.expiry.IsZero() || now().Before(c.expiry.Add(-refreshBefore))) {
+		return c.token, nil
+	}
+
+	response, err := c.requestToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.token = response.AccessToken
+	c.expiry = time.Time{}
+	if response.ExpiresIn > 0 {
+		c.expiry = now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	}
+	return c.token, nil
+}
+
+func (c *OAuth2ClientCredentials) requestToken(ctx context.Context) (*oauth2TokenResponse, error) {
+	params := url.Values{}
+	for key, values := range c.EndpointParams {
+		params[key] = values
+	}
+	params.Set("grant_type", "client_credentials")
+	if len(c.Scopes) > 0 {
+		params.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	if c.CredentialsInBody {
+		params.Set("client_id", c.ClientID)
+		params.Set("client_secret", c.ClientSecret)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set(ContentTypeHeader, "application/x-www-form-urlencoded")
+	request.Header.Set(AcceptHeader, ContentTypeJSON)
+	if !c.CredentialsInBody {
+		request.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	var token oauth2TokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("oauth2: unable to parse token response with status %d: %w", response.StatusCode, err)
+	}
+	if token.Error != "" {
+		return nil, fmt.Errorf("oauth2: token request failed with status %d: %s %s", response.StatusCode, token.Error, token.ErrorDescription)
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 || token.AccessToken == "" {
+		return nil, fmt.Errorf("oauth2: token request failed with status %d", response.StatusCode)
+	}
+	return &token, nil
+}
@@ -0,0 +1,92 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	tokenRequests := 0
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok {
+			clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+		}
+		if clientID != "gateway" || clientSecret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = fmt.Fprint(w, `{"error":"invalid_client","error_description":"unknown client"}`)
+			return
+		}
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "products:read reviews:read", r.PostForm.Get("scope"))
+		assert.Equal(t, "https://api.example.com", r.PostForm.Get("audience"))
+
+		tokenRequests++
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, tokenRequests)
+	}))
+	defer tokenServer.Close()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	newCredentials := func() *OAuth2ClientCredentials {
+		return &OAuth2ClientCredentials{
+			TokenURL:       tokenServer.URL,
+			ClientID:       "gateway",
+			ClientSecret:   "secret",
+			Scopes:         []string{"products:read", "reviews:read"},
+			EndpointParams: map[string][]string{"audience": {"https://api.example.com"}},
+			now: func() time.Time {
+				return now
+			},
+		}
+	}
+
+	t.Run("should cache the token until shortly before the expiry", func(t *testing.T) {
+		tokenRequests = 0
+		credentials := newCredentials()
+
+		request, err := http.NewRequest(http.MethodPost, "https://example.com/graphql", nil)
+		require.NoError(t, err)
+		require.NoError(t, credentials.SignRequest(request, nil))
+		assert.Equal(t, "Bearer token-1", request.Header.Get("Authorization"))
+
+		token, err := credentials.Token(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "token-1", token)
+
+		now = now.Add(58 * time.Minute)
+		token, err = credentials.Token(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "token-1", token)
+
+		now = now.Add(time.Minute)
+		token, err = credentials.Token(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "token-2", token)
+	})
+
+	t.Run("should send the credentials in the body", func(t *testing.T) {
+		tokenRequests = 0
+		credentials := newCredentials()
+		credentials.CredentialsInBody = true
+
+		token, err := credentials.Token(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "token-1", token)
+	})
+
+	t.Run("should return the error of the token endpoint", func(t *testing.T) {
+		credentials := newCredentials()
+		credentials.ClientSecret = "wrong"
+
+		_, err := credentials.Token(context.Background())
+		assert.EqualError(t, err, "oauth2: token request failed with status 401: invalid_client unknown client")
+	})
+}
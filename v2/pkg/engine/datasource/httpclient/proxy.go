@@ -0,0 +1,81 @@
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// NewProxyClient returns a copy of the client which sends its requests through the proxy instead of the proxy of the environment,
+// e.g. "http://egress:3128", "https://egress:3128" or "socks5://egress:1080"
+// The transport of the client has to be nil, an *http.Transport or the transport of a client returned by NewEgressClient
+func NewProxyClient(client *http.Client, proxyURL string) (*http.Client, error) {
+	proxy, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+	switch proxy.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid proxy url: unsupported scheme %q", proxy.Scheme)
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	proxyClient := *client
+	switch t := transport.(type) {
+	case *http.Transport:
+		proxyTransport := t.Clone()
+		proxyTransport.Proxy = http.ProxyURL(proxy)
+		proxyClient.Transport = proxyTransport
+	case *egressRoundTripper:
+		// the egress policy keeps checking the hosts of the requests sent through the proxy
+		proxyTransport := t.transport.Clone()
+		proxyTransport.Proxy = http.ProxyURL(proxy)
+		proxyClient.Transport = &egressRoundTripper{policy: t.policy, transport: proxyTransport}
+	default:
+		return nil, fmt.Errorf("unable to configure the proxy on a transport of type %T", transport)
+	}
+	return &proxyClient, nil
+}
+
+// ProxyClients creates copies of a client which send their requests through proxies, see NewProxyClient
+// The copies are cached per proxy, so that the connections to the proxies are reused
+type ProxyClients struct {
+	client  *http.Client
+	mu      sync.Mutex
+	clients map[string]*http.Client
+}
+
+func NewProxyClients(client *http.Client) *ProxyClients {
+	return &ProxyClients{
+		client:  client,
+		clients: make(map[string]*http.Client),
+	}
+}
+
+// Client returns the copy of the client for the proxy or the client itself if proxyURL is empty
+func (p *ProxyClients) Client(proxyURL string) (*http.Client, error) {
+	if proxyURL == "" {
+		return p.client, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if client, ok := p.clients[proxyURL]; ok {
+		return client, nil
+	}
+	client, err := NewProxyClient(p.client, proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	p.clients[proxyURL] = client
+	return client, nil
+}
@@ -0,0 +1,62 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProxyClient(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a forward proxy receives the absolute URL of the upstream
+		_, _ = w.Write([]byte("proxied " + r.URL.String()))
+	}))
+	defer proxy.Close()
+
+	t.Run("should send the requests through the proxy", func(t *testing.T) {
+		client, err := NewProxyClient(&http.Client{}, proxy.URL)
+		require.NoError(t, err)
+
+		var input []byte
+		input = SetInputMethod(input, []byte("GET"))
+		input = SetInputURL(input, []byte("http://upstream.internal/graphql"))
+
+		out := &bytes.Buffer{}
+		require.NoError(t, Do(client, context.Background(), input, out))
+		assert.Equal(t, "proxied http://upstream.internal/graphql", out.String())
+	})
+
+	t.Run("should reject unsupported proxies", func(t *testing.T) {
+		_, err := NewProxyClient(&http.Client{}, "ftp://proxy.internal")
+		assert.EqualError(t, err, `invalid proxy url: unsupported scheme "ftp"`)
+
+		_, err = NewProxyClient(&http.Client{Transport: NewSigningClient(nil, &HMACSigner{}).Transport}, "socks5://proxy.internal:1080")
+		assert.EqualError(t, err, "unable to configure the proxy on a transport of type *httpclient.signingTransport")
+	})
+}
+
+func TestProxyClients(t *testing.T) {
+	client := &http.Client{}
+	clients := NewProxyClients(client)
+
+	noProxy, err := clients.Client("")
+	require.NoError(t, err)
+	assert.Same(t, client, noProxy)
+
+	proxied, err := clients.Client("http://egress-a.internal:3128")
+	require.NoError(t, err)
+	assert.NotSame(t, client, proxied)
+
+	cached, err := clients.Client("http://egress-a.internal:3128")
+	require.NoError(t, err)
+	assert.Same(t, proxied, cached)
+
+	other, err := clients.Client("http://egress-b.internal:3128")
+	require.NoError(t, err)
+	assert.NotSame(t, proxied, other)
+}
@@ -0,0 +1,236 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RequestSigner signs requests to an upstream right before they are sent, e.g. to authenticate against upstreams behind an API gateway
+type RequestSigner interface {
+	// SignRequest adds the signature to the headers of the request, body is the body of the request or nil
+	SignRequest(request *http.Request, body []byte) error
+}
+
+// NewSigningClient returns a copy of the client which signs every request with the signer
+func NewSigningClient(client *http.Client, signer RequestSigner) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	signingClient := *client
+	signingClient.Transport = &signingTransport{
+		transport: transport,
+		signer:    signer,
+	}
+	return &signingClient
+}
+
+type signingTransport struct {
+	transport http.RoundTripper
+	signer    RequestSigner
+}
+
+func (t *signingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	body, err := requestBody(request)
+	if err != nil {
+		return nil, err
+	}
+	// a RoundTripper must not modify the request, so the headers are added to a clone
+	signedRequest := request.Clone(request.Context())
+	if body != nil {
+		signedRequest.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	if err := t.signer.SignRequest(signedRequest, body); err != nil {
+		return nil, err
+	}
+	return t.transport.RoundTrip(signedRequest)
+}
+
+func requestBody(request *http.Request) ([]byte, error) {
+	if request.Body == nil || request.Body == http.NoBody {
+		return nil, nil
+	}
+	if request.GetBody == nil {
+		defer request.Body.Close()
+		return io.ReadAll(request.Body)
+	}
+	body, err := request.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// SigV4Signer signs requests with AWS Signature Version 4, e.g. for AppSync or Lambda function URLs with IAM authorization
+type SigV4Signer struct {
+	// Region of the upstream, e.g. "eu-central-1"
+	Region string
+	// Service of the upstream, e.g. "appsync" or "lambda"
+	Service         string
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is required for temporary credentials
+	SessionToken string
+
+	now func() time.Time
+}
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat = "20060102T150405Z"
+	sigV4DateFormat = "20060102"
+)
+
+func (s *SigV4Signer) SignRequest(request *http.Request, body []byte) error {
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	signingTime := now().UTC()
+	date := signingTime.Format(sigV4DateFormat)
+
+	request.Header.Set("X-Amz-Date", signingTime.Format(sigV4TimeFormat))
+	if s.SessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	host := request.Host
+	if host == "" {
+		host = request.URL.Host
+	}
+	headers := [][2]string{
+		{"host", host},
+		{"x-amz-date", request.Header.Get("X-Amz-Date")},
+	}
+	if s.SessionToken != "" {
+		headers = append(headers, [2]string{"x-amz-security-token", s.SessionToken})
+	}
+
+	canonicalHeaders := &strings.Builder{}
+	signedHeaders := make([]string, 0, len(headers))
+	for _, header := range headers {
+		canonicalHeaders.WriteString(header[0] + ":" + strings.TrimSpace(header[1]) + "\n")
+		signedHeaders = append(signedHeaders, header[0])
+	}
+
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		sigV4CanonicalURI(request.URL),
+		sigV4CanonicalQuery(request.URL),
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.Region, s.Service, "aws4_request"}, "/")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		signingTime.Format(sigV4TimeFormat),
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, s.Region)
+	signingKey = hmacSHA256(signingKey, s.Service)
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+
+	request.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, s.AccessKeyID, scope, strings.Join(signedHeaders, ";"), hex.EncodeToString(hmacSHA256(signingKey, stringToSign))))
+	return nil
+}
+
+// sigV4CanonicalURI encodes every segment of the escaped path again, as required for all services except S3
+func sigV4CanonicalURI(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i := range segments {
+		segments[i] = sigV4Escape(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
+
+func sigV4CanonicalQuery(u *url.URL) string {
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	params := make([]string, 0, len(query))
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			params = append(params, sigV4Escape(key)+"="+sigV4Escape(value))
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// sigV4Escape escapes all characters except the unreserved characters of RFC 3986
+func sigV4Escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// HMACSigner signs requests with a HMAC-SHA256 of the timestamp, the method, the request URI and the body, separated by line breaks
+// The signature is hex encoded, the timestamp is in unix seconds
+// The upstream should reject requests with outdated timestamps to prevent replays
+type HMACSigner struct {
+	Secret []byte
+	// SignatureHeader defaults to "X-Signature"
+	SignatureHeader string
+	// TimestampHeader defaults to "X-Signature-Timestamp"
+	TimestampHeader string
+
+	now func() time.Time
+}
+
+func (s *HMACSigner) SignRequest(request *http.Request, body []byte) error {
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	signatureHeader, timestampHeader := s.SignatureHeader, s.TimestampHeader
+	if signatureHeader == "" {
+		signatureHeader = "X-Signature"
+	}
+	if timestampHeader == "" {
+		timestampHeader = "X-Signature-Timestamp"
+	}
+
+	timestamp := strconv.FormatInt(now().Unix(), 10)
+	mac := hmac.New(sha256.New, s.Secret)
+	_, _ = io.WriteString(mac, timestamp+"\n"+request.Method+"\n"+request.URL.RequestURI()+"\n")
+	_, _ = mac.Write(body)
+
+	request.Header.Set(timestampHeader, timestamp)
+	request.Header.Set(signatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	return nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = io.WriteString(mac, data)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,104 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigV4Signer(t *testing.T) {
+	signer := &SigV4Signer{
+		Region:          "us-east-1",
+		Service:         "service",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		now: func() time.Time {
+			return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+		},
+	}
+
+	t.Run("get-vanilla of the AWS test suite", func(t *testing.T) {
+		request, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+		require.NoError(t, err)
+
+		require.NoError(t, signer.SignRequest(request, nil))
+		assert.Equal(t, "20150830T123600Z", request.Header.Get("X-Amz-Date"))
+		assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31", request.Header.Get("Authorization"))
+	})
+
+	t.Run("get-vanilla-query-order-key-case of the AWS test suite", func(t *testing.T) {
+		request, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/?Param2=value2&Param1=value1", nil)
+		require.NoError(t, err)
+
+		require.NoError(t, signer.SignRequest(request, nil))
+		assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500", request.Header.Get("Authorization"))
+	})
+
+	t.Run("session token", func(t *testing.T) {
+		signer := *signer
+		signer.SessionToken = "token"
+		request, err := http.NewRequest(http.MethodPost, "https://example.amazonaws.com/graphql", bytes.NewReader([]byte(`{"query":"{me}"}`)))
+		require.NoError(t, err)
+
+		require.NoError(t, signer.SignRequest(request, []byte(`{"query":"{me}"}`)))
+		assert.Equal(t, "token", request.Header.Get("X-Amz-Security-Token"))
+		assert.Contains(t, request.Header.Get("Authorization"), "SignedHeaders=host;x-amz-date;x-amz-security-token, ")
+	})
+}
+
+func TestHMACSigner(t *testing.T) {
+	signer := &HMACSigner{
+		Secret: []byte("secret"),
+		now: func() time.Time {
+			return time.Unix(1700000000, 0)
+		},
+	}
+
+	request, err := http.NewRequest(http.MethodPost, "https://example.com/graphql?api=1", nil)
+	require.NoError(t, err)
+	require.NoError(t, signer.SignRequest(request, []byte(`{"query":"{me}"}`)))
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	_, _ = mac.Write([]byte("1700000000\nPOST\n/graphql?api=1\n{\"query\":\"{me}\"}"))
+	assert.Equal(t, "1700000000", request.Header.Get("X-Signature-Timestamp"))
+	assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), request.Header.Get("X-Signature"))
+}
+
+func TestSigningClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		_, _ = w.Write([]byte(r.Header.Get("X-Signature") + " " + string(body)))
+	}))
+	defer server.Close()
+
+	client := NewSigningClient(http.DefaultClient, signerFunc(func(request *http.Request, body []byte) error {
+		request.Header.Set("X-Signature", "signed "+string(body))
+		return nil
+	}))
+
+	var input []byte
+	input = SetInputMethod(input, []byte("POST"))
+	input = SetInputBody(input, []byte(`{"foo":"bar"}`))
+	input = SetInputURL(input, []byte(server.URL))
+
+	out := &bytes.Buffer{}
+	require.NoError(t, Do(client, context.Background(), input, out))
+	assert.Equal(t, `signed {"foo":"bar"} {"foo":"bar"}`, out.String())
+}
+
+type signerFunc func(request *http.Request, body []byte) error
+
+func (f signerFunc) SignRequest(request *http.Request, body []byte) error {
+	return f(request, body)
+}
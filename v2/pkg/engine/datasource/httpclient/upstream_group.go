@@ -0,0 +1,132 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+)
+
+// LoadBalancingStrategy defines in which order the URLs of an UpstreamGroup are tried.
+type LoadBalancingStrategy string
+
+const (
+	// LoadBalancingStrategyPriority always tries the URLs in the configured order.
+	// The following URLs are only used if the previous ones are unavailable.
+	LoadBalancingStrategyPriority LoadBalancingStrategy = "priority"
+	// LoadBalancingStrategyRoundRobin distributes the requests across all URLs.
+	// If a URL is unavailable, the request fails over to the next one.
+	LoadBalancingStrategyRoundRobin LoadBalancingStrategy = "round_robin"
+)
+
+// UpstreamGroup is a group of URLs serving the same upstream, e.g. instances of a subgraph in multiple regions.
+// An idempotent request, e.g. of a query, fails over to the next URL if the upstream can't be reached or responds with a 5xx status code.
+// A non-idempotent request, e.g. of a mutation, only fails over if the connection failed before the request was written,
+// so the upstream can't have executed it, see DoNonIdempotent.
+type UpstreamGroup struct {
+	urls     []string
+	strategy LoadBalancingStrategy
+	next     uint64
+}
+
+// NewUpstreamGroup creates a group of the provided urls. An empty strategy defaults to LoadBalancingStrategyPriority.
+func NewUpstreamGroup(urls []string, strategy LoadBalancingStrategy) *UpstreamGroup {
+	if strategy == "" {
+		strategy = LoadBalancingStrategyPriority
+	}
+	return &UpstreamGroup{
+		urls:     urls,
+		strategy: strategy,
+	}
+}
+
+// Do sends the idempotent request of requestInput to the URLs of the group in the order of the strategy, replacing the url of the input.
+// The response of the first available upstream is written to out. If all upstreams are unavailable,
+// the response or error of the last attempt is returned.
+func (g *UpstreamGroup) Do(client *http.Client, ctx context.Context, requestInput []byte, out io.Writer) error {
+	return g.do(client, ctx, requestInput, out, true)
+}
+
+// DoNonIdempotent sends the request of requestInput like Do, but only fails over to the next URL
+// if the request wasn't written to the upstream, e.g. because the connection was refused.
+// Requests which might have reached the upstream aren't sent twice, e.g. mutations.
+func (g *UpstreamGroup) DoNonIdempotent(client *http.Client, ctx context.Context, requestInput []byte, out io.Writer) error {
+	return g.do(client, ctx, requestInput, out, false)
+}
+
+func (g *UpstreamGroup) do(client *http.Client, ctx context.Context, requestInput []byte, out io.Writer, idempotent bool) error {
+	if len(g.urls) == 0 {
+		return Do(client, ctx, requestInput, out)
+	}
+
+	buf := &bytes.Buffer{}
+	urls := g.order()
+	var err error
+	for i, url := range urls {
+		buf.Reset()
+		attemptCtx, responseContext := InjectResponseContext(ctx)
+		// only the headers of the response written to out are collected
+		attemptHeaders := &attemptResponseHeaders{}
+		attemptCtx = WithResponseHeaderCollector(attemptCtx, attemptHeaders)
+		var written atomic.Bool
+		if !idempotent {
+			attemptCtx = httptrace.WithClientTrace(attemptCtx, &httptrace.ClientTrace{
+				WroteHeaders: func() {
+					written.Store(true)
+				},
+			})
+		}
+		err = Do(client, attemptCtx, SetInputURL(requestInput, []byte(url)), buf)
+		setResponseStatusCode(ctx, responseContext.StatusCode)
+
+		isLastAttempt := i == len(urls)-1
+		if !isLastAttempt && g.shouldFailover(ctx, err, responseContext.StatusCode, idempotent || !written.Load()) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		collectResponseHeaders(ctx, attemptHeaders.header)
+		_, err = out.Write(buf.Bytes())
+		return err
+	}
+	return err
+}
+
+// attemptResponseHeaders keeps the headers of the response of an attempt of an UpstreamGroup
+type attemptResponseHeaders struct {
+	header http.Header
+}
+
+func (a *attemptResponseHeaders) CollectResponseHeaders(header http.Header) {
+	a.header = header
+}
+
+// order returns the URLs in the order they should be tried for the next request
+func (g *UpstreamGroup) order() []string {
+	if g.strategy != LoadBalancingStrategyRoundRobin {
+		return g.urls
+	}
+	start := int((atomic.AddUint64(&g.next, 1) - 1) % uint64(len(g.urls)))
+	urls := make([]string, 0, len(g.urls))
+	urls = append(urls, g.urls[start:]...)
+	return append(urls, g.urls[:start]...)
+}
+
+// shouldFailover reports if the request is sent to the next URL, retryable is false if the upstream might have executed the request
+func (g *UpstreamGroup) shouldFailover(ctx context.Context, err error, statusCode int, retryable bool) bool {
+	if ctx.Err() != nil {
+		// the request was canceled by the client, not by the upstream
+		return false
+	}
+	if !retryable {
+		return false
+	}
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return statusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,179 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpstreamGroup_Do(t *testing.T) {
+	upstream := func(t *testing.T, statusCode int, response string) (*httptest.Server, *atomic.Int32) {
+		calls := &atomic.Int32{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(statusCode)
+			_, _ = w.Write([]byte(response))
+		}))
+		t.Cleanup(server.Close)
+		return server, calls
+	}
+
+	unavailableURL := func(t *testing.T) string {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+		return server.URL
+	}
+
+	// closedAfterRequest is an upstream closing the connection after it read the request, without responding
+	closedAfterRequest := func(t *testing.T) (*httptest.Server, *atomic.Int32) {
+		calls := &atomic.Int32{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			_ = conn.Close()
+		}))
+		t.Cleanup(server.Close)
+		return server, calls
+	}
+
+	input := SetInputMethod(nil, []byte("POST"))
+	input = SetInputBody(input, []byte(`{"query":"{ hello }"}`))
+
+	t.Run("priority should use the first available upstream", func(t *testing.T) {
+		primary, primaryCalls := upstream(t, http.StatusBadGateway, `bad gateway`)
+		secondary, secondaryCalls := upstream(t, http.StatusOK, `{"data":{"hello":"secondary"}}`)
+		group := NewUpstreamGroup([]string{unavailableURL(t), primary.URL, secondary.URL}, LoadBalancingStrategyPriority)
+
+		ctx, responseContext := InjectResponseContext(context.Background())
+		out := &bytes.Buffer{}
+		require.NoError(t, group.Do(http.DefaultClient, ctx, input, out))
+		assert.Equal(t, `{"data":{"hello":"secondary"}}`, out.String())
+		assert.Equal(t, http.StatusOK, responseContext.StatusCode)
+		assert.Equal(t, int32(1), primaryCalls.Load())
+		assert.Equal(t, int32(1), secondaryCalls.Load())
+	})
+
+	t.Run("should only collect the response headers of the used upstream", func(t *testing.T) {
+		unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Set-Cookie", "upstream=unavailable")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		t.Cleanup(unavailable.Close)
+		available := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Set-Cookie", "upstream=available")
+			_, _ = w.Write([]byte(`{"data":{"hello":"available"}}`))
+		}))
+		t.Cleanup(available.Close)
+		group := NewUpstreamGroup([]string{unavailable.URL, available.URL}, LoadBalancingStrategyPriority)
+
+		collector := &responseHeaderCollector{}
+		ctx := WithResponseHeaderCollector(context.Background(), collector)
+		require.NoError(t, group.Do(http.DefaultClient, ctx, input, &bytes.Buffer{}))
+		assert.Equal(t, []string{"upstream=available"}, collector.header.Values("Set-Cookie"))
+	})
+
+	t.Run("should not fail over on client errors", func(t *testing.T) {
+		primary, _ := upstream(t, http.StatusBadRequest, `{"errors":[{"message":"bad request"}]}`)
+		secondary, secondaryCalls := upstream(t, http.StatusOK, `{"data":{"hello":"secondary"}}`)
+		group := NewUpstreamGroup([]string{primary.URL, secondary.URL}, LoadBalancingStrategyPriority)
+
+		out := &bytes.Buffer{}
+		require.NoError(t, group.Do(http.DefaultClient, context.Background(), input, out))
+		assert.Equal(t, `{"errors":[{"message":"bad request"}]}`, out.String())
+		assert.Equal(t, int32(0), secondaryCalls.Load())
+	})
+
+	t.Run("should return the response of the last upstream if all are unavailable", func(t *testing.T) {
+		primary, _ := upstream(t, http.StatusServiceUnavailable, `primary unavailable`)
+		secondary, _ := upstream(t, http.StatusServiceUnavailable, `secondary unavailable`)
+		group := NewUpstreamGroup([]string{primary.URL, secondary.URL}, LoadBalancingStrategyPriority)
+
+		out := &bytes.Buffer{}
+		require.NoError(t, group.Do(http.DefaultClient, context.Background(), input, out))
+		assert.Equal(t, `secondary unavailable`, out.String())
+	})
+
+	t.Run("should return the error of the last upstream if all are unreachable", func(t *testing.T) {
+		group := NewUpstreamGroup([]string{unavailableURL(t), unavailableURL(t)}, LoadBalancingStrategyPriority)
+		assert.Error(t, group.Do(http.DefaultClient, context.Background(), input, &bytes.Buffer{}))
+	})
+
+	t.Run("round robin should distribute requests across upstreams", func(t *testing.T) {
+		first, firstCalls := upstream(t, http.StatusOK, `{"data":{"hello":"first"}}`)
+		second, secondCalls := upstream(t, http.StatusOK, `{"data":{"hello":"second"}}`)
+		group := NewUpstreamGroup([]string{first.URL, second.URL}, LoadBalancingStrategyRoundRobin)
+
+		for i := 0; i < 4; i++ {
+			require.NoError(t, group.Do(http.DefaultClient, context.Background(), input, &bytes.Buffer{}))
+		}
+		assert.Equal(t, int32(2), firstCalls.Load())
+		assert.Equal(t, int32(2), secondCalls.Load())
+	})
+
+	t.Run("round robin should fail over to the next upstream", func(t *testing.T) {
+		available, availableCalls := upstream(t, http.StatusOK, `{"data":{"hello":"available"}}`)
+		group := NewUpstreamGroup([]string{unavailableURL(t), available.URL}, LoadBalancingStrategyRoundRobin)
+
+		for i := 0; i < 2; i++ {
+			out := &bytes.Buffer{}
+			require.NoError(t, group.Do(http.DefaultClient, context.Background(), input, out))
+			assert.Equal(t, `{"data":{"hello":"available"}}`, out.String())
+		}
+		assert.Equal(t, int32(2), availableCalls.Load())
+	})
+
+	t.Run("should fail over a query if the connection failed after the request was written", func(t *testing.T) {
+		primary, primaryCalls := closedAfterRequest(t)
+		secondary, secondaryCalls := upstream(t, http.StatusOK, `{"data":{"hello":"secondary"}}`)
+		group := NewUpstreamGroup([]string{primary.URL, secondary.URL}, LoadBalancingStrategyPriority)
+
+		out := &bytes.Buffer{}
+		require.NoError(t, group.Do(http.DefaultClient, context.Background(), input, out))
+		assert.Equal(t, `{"data":{"hello":"secondary"}}`, out.String())
+		assert.Equal(t, int32(1), primaryCalls.Load())
+		assert.Equal(t, int32(1), secondaryCalls.Load())
+	})
+
+	t.Run("non idempotent", func(t *testing.T) {
+		mutation := SetInputMethod(nil, []byte("POST"))
+		mutation = SetInputBody(mutation, []byte(`{"query":"mutation { createUser }"}`))
+
+		t.Run("should fail over if the request wasn't written", func(t *testing.T) {
+			available, availableCalls := upstream(t, http.StatusOK, `{"data":{"createUser":"1"}}`)
+			group := NewUpstreamGroup([]string{unavailableURL(t), available.URL}, LoadBalancingStrategyPriority)
+
+			out := &bytes.Buffer{}
+			require.NoError(t, group.DoNonIdempotent(http.DefaultClient, context.Background(), mutation, out))
+			assert.Equal(t, `{"data":{"createUser":"1"}}`, out.String())
+			assert.Equal(t, int32(1), availableCalls.Load())
+		})
+
+		t.Run("should not fail over if the connection failed after the request was written", func(t *testing.T) {
+			primary, primaryCalls := closedAfterRequest(t)
+			secondary, secondaryCalls := upstream(t, http.StatusOK, `{"data":{"createUser":"1"}}`)
+			group := NewUpstreamGroup([]string{primary.URL, secondary.URL}, LoadBalancingStrategyPriority)
+
+			assert.Error(t, group.DoNonIdempotent(http.DefaultClient, context.Background(), mutation, &bytes.Buffer{}))
+			assert.Equal(t, int32(1), primaryCalls.Load())
+			assert.Equal(t, int32(0), secondaryCalls.Load())
+		})
+
+		t.Run("should not fail over on server errors", func(t *testing.T) {
+			primary, _ := upstream(t, http.StatusBadGateway, `bad gateway`)
+			secondary, secondaryCalls := upstream(t, http.StatusOK, `{"data":{"createUser":"1"}}`)
+			group := NewUpstreamGroup([]string{primary.URL, secondary.URL}, LoadBalancingStrategyPriority)
+
+			out := &bytes.Buffer{}
+			require.NoError(t, group.DoNonIdempotent(http.DefaultClient, context.Background(), mutation, out))
+			assert.Equal(t, `bad gateway`, out.String())
+			assert.Equal(t, int32(0), secondaryCalls.Load())
+		})
+	})
+}
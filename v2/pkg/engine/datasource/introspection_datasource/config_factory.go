@@ -1,6 +1,8 @@
 package introspection_datasource
 
 import (
+	lru "github.com/hashicorp/golang-lru"
+
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/introspection"
@@ -9,6 +11,7 @@ import (
 
 type IntrospectionConfigFactory struct {
 	introspectionData *introspection.Data
+	cache             *lru.Cache
 }
 
 func NewIntrospectionConfigFactory(schema *ast.Document) (*IntrospectionConfigFactory, error) {
@@ -25,6 +28,24 @@ func NewIntrospectionConfigFactory(schema *ast.Document) (*IntrospectionConfigFa
 	return &IntrospectionConfigFactory{introspectionData: &data}, nil
 }
 
+// ApplyFilter applies the filter to the introspection data before the data source configurations are built,
+// e.g. to hide internal types from introspection
+func (f *IntrospectionConfigFactory) ApplyFilter(filter func(data *introspection.Data)) {
+	filter(f.introspectionData)
+}
+
+// EnableCache caches the rendered results of the introspection data sources built afterwards in a LRU cache of the given size,
+// only the data of the schema is cached, so every request is still planned and resolved like any other operation
+func (f *IntrospectionConfigFactory) EnableCache(size int) (err error) {
+	f.cache, err = lru.New(size)
+	return err
+}
+
+// IntrospectionData returns the introspection data of the schema with the applied filters
+func (f *IntrospectionConfigFactory) IntrospectionData() *introspection.Data {
+	return f.introspectionData
+}
+
 func (f *IntrospectionConfigFactory) BuildFieldConfigurations() (planFields plan.FieldConfigurations) {
 	return plan.FieldConfigurations{
 		{
@@ -87,7 +108,7 @@ func (f *IntrospectionConfigFactory) buildRootDataSourceConfiguration() plan.Dat
 			},
 			{
 				TypeName:   "__Type",
-				FieldNames: []string{"kind", "name", "description", "interfaces", "possibleTypes", "inputFields", "ofType"},
+				FieldNames: []string{"kind", "name", "description", "interfaces", "possibleTypes", "inputFields", "ofType", "specifiedByURL"},
 			},
 			{
 				TypeName:   "__Field",
@@ -102,7 +123,7 @@ func (f *IntrospectionConfigFactory) buildRootDataSourceConfiguration() plan.Dat
 				FieldNames: []string{"name", "description", "locations", "args", "isRepeatable"},
 			},
 		},
-		Factory: NewFactory(f.introspectionData),
+		Factory: &Factory{introspectionData: f.introspectionData, cache: f.cache},
 		Custom:  []byte("Introspection: __schema __type"),
 	}
 }
@@ -118,7 +139,7 @@ func (f *IntrospectionConfigFactory) buildFieldsConfiguration() plan.DataSourceC
 		ChildNodes: []plan.TypeField{
 			{
 				TypeName:   "__Type",
-				FieldNames: []string{"kind", "name", "description", "interfaces", "possibleTypes", "inputFields", "ofType"},
+				FieldNames: []string{"kind", "name", "description", "interfaces", "possibleTypes", "inputFields", "ofType", "specifiedByURL"},
 			},
 			{
 				TypeName:   "__Field",
@@ -129,7 +150,7 @@ func (f *IntrospectionConfigFactory) buildFieldsConfiguration() plan.DataSourceC
 				FieldNames: []string{"name", "description", "type", "defaultValue"},
 			},
 		},
-		Factory: NewFactory(f.introspectionData),
+		Factory: &Factory{introspectionData: f.introspectionData, cache: f.cache},
 		Custom:  []byte("Introspection: __Type.fields"),
 	}
 }
@@ -148,7 +169,7 @@ func (f *IntrospectionConfigFactory) buildEnumsConfiguration() plan.DataSourceCo
 				FieldNames: []string{"name", "description", "isDeprecated", "deprecationReason"},
 			},
 		},
-		Factory: NewFactory(f.introspectionData),
+		Factory: &Factory{introspectionData: f.introspectionData, cache: f.cache},
 		Custom:  []byte("Introspection: __Type.enumValues"),
 	}
 }
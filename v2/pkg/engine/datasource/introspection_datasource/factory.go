@@ -3,12 +3,15 @@ package introspection_datasource
 import (
 	"context"
 
+	lru "github.com/hashicorp/golang-lru"
+
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/introspection"
 )
 
 type Factory struct {
 	introspectionData *introspection.Data
+	cache             *lru.Cache
 }
 
 func NewFactory(introspectionData *introspection.Data) *Factory {
@@ -16,5 +19,5 @@ func NewFactory(introspectionData *introspection.Data) *Factory {
 }
 
 func (f *Factory) Planner(_ context.Context) plan.DataSourcePlanner {
-	return &Planner{introspectionData: f.introspectionData}
+	return &Planner{introspectionData: f.introspectionData, cache: f.cache}
 }
@@ -3,6 +3,8 @@ package introspection_datasource
 import (
 	"errors"
 
+	lru "github.com/hashicorp/golang-lru"
+
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
@@ -11,6 +13,7 @@ import (
 
 type Planner struct {
 	introspectionData       *introspection.Data
+	cache                   *lru.Cache
 	v                       *plan.Visitor
 	rootField               int
 	rootFieldName           string
@@ -79,6 +82,7 @@ func (p *Planner) ConfigureFetch() resolve.FetchConfiguration {
 		RequiresParallelListItemFetch: requiresParallelListItemFetch,
 		DataSource: &Source{
 			introspectionData: p.introspectionData,
+			cache:             p.cache,
 		},
 		PostProcessing: postProcessing,
 	}
@@ -1,9 +1,14 @@
 package introspection_datasource
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
+	"strings"
+
+	lru "github.com/hashicorp/golang-lru"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/introspection"
 )
@@ -14,6 +19,41 @@ var (
 
 type Source struct {
 	introspectionData *introspection.Data
+	// cache holds the rendered results of previous loads, nil if caching is disabled
+	cache *lru.Cache
+}
+
+// loadCacheKey identifies a rendered result by the introspection data, the page of the types and the input of the fetch
+type loadCacheKey struct {
+	introspectionData *introspection.Data
+	page              TypesPage
+	input             string
+}
+
+type introspectionDataContextKey struct{}
+
+// WithIntrospectionData returns a context in which introspection queries are resolved with the data
+// instead of the data of the schema the operation was planned with, e.g. with the data of a variant of the schema
+func WithIntrospectionData(ctx context.Context, data *introspection.Data) context.Context {
+	return context.WithValue(ctx, introspectionDataContextKey{}, data)
+}
+
+// TypesPage pages and filters the types of the schema returned by __schema,
+// so clients of schemas with many types can fetch the types in multiple requests
+type TypesPage struct {
+	// First is the maximum number of types, 0 returns all types
+	First int
+	// After is the name of the last type of the previous page, the types are paged in the order of the schema
+	After string
+	// NamePrefix only returns the types with names starting with the prefix
+	NamePrefix string
+}
+
+type typesPageContextKey struct{}
+
+// WithTypesPage returns a context in which the types of the schema are paged and filtered
+func WithTypesPage(ctx context.Context, page TypesPage) context.Context {
+	return context.WithValue(ctx, typesPageContextKey{}, page)
 }
 
 func (s *Source) Load(ctx context.Context, input []byte, w io.Writer) (err error) {
@@ -22,6 +62,31 @@ func (s *Source) Load(ctx context.Context, input []byte, w io.Writer) (err error
 		return err
 	}
 
+	if data, ok := ctx.Value(introspectionDataContextKey{}).(*introspection.Data); ok && data != nil {
+		s = &Source{introspectionData: data, cache: s.cache}
+	}
+
+	page, _ := ctx.Value(typesPageContextKey{}).(TypesPage)
+	if s.cache == nil {
+		return s.load(w, req, page)
+	}
+
+	key := loadCacheKey{introspectionData: s.introspectionData, page: page, input: string(input)}
+	if cached, ok := s.cache.Get(key); ok {
+		_, err = w.Write(cached.([]byte))
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	if err = s.load(buf, req, page); err != nil {
+		return err
+	}
+	s.cache.Add(key, buf.Bytes())
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func (s *Source) load(w io.Writer, req introspectionInput, page TypesPage) error {
 	switch req.RequestType {
 	case TypeRequestType:
 		return s.singleType(w, req.TypeName)
@@ -31,14 +96,34 @@ func (s *Source) Load(ctx context.Context, input []byte, w io.Writer) (err error
 		return s.fieldsForType(w, req.OnTypeName, req.IncludeDeprecated)
 	}
 
-	return json.NewEncoder(w).Encode(s.schemaWithoutTypeInfo())
+	return s.schema(w, page)
+}
+
+func (s *Source) schema(w io.Writer, page TypesPage) error {
+	if page.First < 0 {
+		return errors.New("introspection: first must not be negative")
+	}
+	return json.NewEncoder(w).Encode(s.schemaWithoutTypeInfo(page))
 }
 
-func (s *Source) schemaWithoutTypeInfo() introspection.Schema {
+// schemaWithoutTypeInfo returns the schema with the types filtered by name prefix and paged
+func (s *Source) schemaWithoutTypeInfo(page TypesPage) introspection.Schema {
 	types := make([]introspection.FullType, 0, len(s.introspectionData.Schema.Types))
 
+	afterFound := page.After == ""
 	for i := range s.introspectionData.Schema.Types {
-		types = append(types, s.typeWithoutFieldAndEnumValues(&s.introspectionData.Schema.Types[i]))
+		fullType := &s.introspectionData.Schema.Types[i]
+		if !strings.HasPrefix(fullType.Name, page.NamePrefix) {
+			continue
+		}
+		if !afterFound {
+			afterFound = fullType.Name == page.After
+			continue
+		}
+		if page.First > 0 && len(types) == page.First {
+			break
+		}
+		types = append(types, s.typeWithoutFieldAndEnumValues(fullType))
 	}
 
 	return introspection.Schema{
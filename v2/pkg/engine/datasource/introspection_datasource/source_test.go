@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
@@ -15,7 +16,7 @@ import (
 )
 
 func TestSource_Load(t *testing.T) {
-	run := func(schema string, input string, fixtureName string) func(t *testing.T) {
+	runWithTypesPage := func(schema string, input string, page TypesPage, fixtureName string) func(t *testing.T) {
 		t.Helper()
 		return func(t *testing.T) {
 			def, report := astparser.ParseGraphqlDocumentString(schema)
@@ -29,7 +30,7 @@ func TestSource_Load(t *testing.T) {
 
 			buf := &bytes.Buffer{}
 			source := &Source{introspectionData: &data}
-			require.NoError(t, source.Load(context.Background(), []byte(input), buf))
+			require.NoError(t, source.Load(WithTypesPage(context.Background(), page), []byte(input), buf))
 
 			actualResponse := &bytes.Buffer{}
 			require.NoError(t, json.Indent(actualResponse, buf.Bytes(), "", "  "))
@@ -37,8 +38,15 @@ func TestSource_Load(t *testing.T) {
 		}
 	}
 
+	run := func(schema string, input string, fixtureName string) func(t *testing.T) {
+		t.Helper()
+		return runWithTypesPage(schema, input, TypesPage{}, fixtureName)
+	}
+
 	t.Run("schema introspection", run(testSchema, `{"request_type":1}`, `schema_introspection`))
 	t.Run("schema introspection with custom root operation types", run(testSchemaWithCustomRootOperationTypes, `{"request_type":1}`, `schema_introspection_with_custom_root_operation_types`))
+	t.Run("schema introspection with types page", runWithTypesPage(testSchema, `{"request_type":1}`, TypesPage{First: 2, After: "Query"}, `schema_introspection_types_page`))
+	t.Run("schema introspection with types prefix", runWithTypesPage(testSchema, `{"request_type":1}`, TypesPage{NamePrefix: "Dro"}, `schema_introspection_types_prefix`))
 	t.Run("type introspection", run(testSchema, `{"request_type":2,"type_name":"Query"}`, `type_introspection`))
 	t.Run("type introspection of not existing type", run(testSchema, `{"request_type":2,"type_name":"NotExisting"}`, `not_existing_type`))
 
@@ -59,6 +67,45 @@ func TestSource_Load(t *testing.T) {
 	})
 }
 
+func TestSource_LoadWithCache(t *testing.T) {
+	def, report := astparser.ParseGraphqlDocumentString(testSchema)
+	require.False(t, report.HasErrors())
+	require.NoError(t, asttransform.MergeDefinitionWithBaseSchema(&def))
+
+	configFactory, err := NewIntrospectionConfigFactory(&def)
+	require.NoError(t, err)
+	require.NoError(t, configFactory.EnableCache(8))
+	source := &Source{introspectionData: configFactory.IntrospectionData(), cache: configFactory.cache}
+
+	load := func(ctx context.Context, input string) string {
+		buf := &bytes.Buffer{}
+		require.NoError(t, source.Load(ctx, []byte(input), buf))
+		return buf.String()
+	}
+
+	input := `{"request_type":2,"type_name":"Droid"}`
+	expected := load(context.Background(), input)
+	assert.Equal(t, 1, source.cache.Len())
+
+	// the cached result is served even though the data changed
+	source.introspectionData.Schema.Types = nil
+	assert.Equal(t, expected, load(context.Background(), input))
+	assert.Equal(t, 1, source.cache.Len())
+
+	t.Run("results of other inputs, pages and data are cached separately", func(t *testing.T) {
+		assert.Equal(t, "null", load(context.Background(), `{"request_type":2,"type_name":"Query"}`))
+		load(WithTypesPage(context.Background(), TypesPage{First: 1}), `{"request_type":1}`)
+		load(WithIntrospectionData(context.Background(), &introspection.Data{}), input)
+		assert.Equal(t, 4, source.cache.Len())
+	})
+
+	t.Run("failed loads are not cached", func(t *testing.T) {
+		err := source.Load(WithTypesPage(context.Background(), TypesPage{First: -1}), []byte(`{"request_type":1}`), &bytes.Buffer{})
+		assert.Error(t, err)
+		assert.Equal(t, 4, source.cache.Len())
+	})
+}
+
 const testSchema = `
 schema {
     query: Query
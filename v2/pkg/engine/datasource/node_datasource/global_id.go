@@ -0,0 +1,40 @@
+package node_datasource
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var errInvalidGlobalID = errors.New("invalid global id")
+
+// EncodeGlobalID encodes the type name and the key of an entity into an opaque global id
+// the key is the json object of the key fields of the entity, e.g. {"id":"1"}
+func EncodeGlobalID(typeName string, key json.RawMessage) (string, error) {
+	compacted := &bytes.Buffer{}
+	if err := json.Compact(compacted, key); err != nil {
+		return "", err
+	}
+	if compacted.Len() == 0 || compacted.Bytes()[0] != '{' {
+		return "", errors.New("the key of a global id has to be a json object")
+	}
+	return base64.RawURLEncoding.EncodeToString(append([]byte(typeName+":"), compacted.Bytes()...)), nil
+}
+
+// DecodeGlobalID decodes a global id created by EncodeGlobalID into the type name and the key of the entity
+func DecodeGlobalID(id string) (typeName string, key json.RawMessage, err error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil {
+		return "", nil, errInvalidGlobalID
+	}
+	typeNameEnd := bytes.IndexByte(decoded, ':')
+	if typeNameEnd < 1 {
+		return "", nil, errInvalidGlobalID
+	}
+	key = decoded[typeNameEnd+1:]
+	if len(key) == 0 || key[0] != '{' || !json.Valid(key) {
+		return "", nil, errInvalidGlobalID
+	}
+	return string(decoded[:typeNameEnd]), key, nil
+}
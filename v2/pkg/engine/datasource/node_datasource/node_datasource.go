@@ -0,0 +1,321 @@
+// Package node_datasource implements the node field of the Relay global object identification spec at the gateway.
+//
+// The node field decodes the global id into the type name and the key of the entity
+// and resolves the key fields of the entity. All other fields of the entity are loaded
+// from the datasource owning the entity with the federation entity mechanism,
+// so the datasources don't have to implement the node field themselves:
+//
+//	interface Node { id: ID! }
+//	type Query { node(id: ID!): Node }
+//
+// Global ids are created with EncodeGlobalID, e.g. by a resolve.FieldValueTransformer encoding the id fields of the entities.
+package node_datasource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/buger/jsonparser"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+const (
+	DefaultFieldName         = "node"
+	DefaultInterfaceTypeName = "Node"
+
+	idArgumentName = "id"
+)
+
+var null = []byte("null")
+
+type Configuration struct {
+	// FieldName is the name of the node field of the query type, defaults to DefaultFieldName
+	FieldName string `json:"field_name,omitempty"`
+	// InterfaceTypeName is the name of the interface returned by the node field, defaults to DefaultInterfaceTypeName
+	InterfaceTypeName string `json:"interface_type_name,omitempty"`
+	// Types are the entities which can be loaded by their global id
+	Types []NodeType `json:"types"`
+}
+
+// NodeType is an entity which can be loaded by its global id
+type NodeType struct {
+	TypeName string `json:"type_name"`
+	// KeyFields are the fields of the key the entity is loaded by from its owning datasource
+	// only scalar fields are supported
+	KeyFields []string `json:"key_fields"`
+}
+
+func (c Configuration) fieldName() string {
+	if c.FieldName == "" {
+		return DefaultFieldName
+	}
+	return c.FieldName
+}
+
+func (c Configuration) interfaceTypeName() string {
+	if c.InterfaceTypeName == "" {
+		return DefaultInterfaceTypeName
+	}
+	return c.InterfaceTypeName
+}
+
+func ConfigJSON(config Configuration) json.RawMessage {
+	out, _ := json.Marshal(config)
+	return out
+}
+
+// NewDataSourceConfiguration creates the configuration of the datasource resolving the node field of the query type
+// the keys of the node types are added to the federation metadata, so the planner loads the other fields
+// of the entities from the datasources owning them
+func NewDataSourceConfiguration(id string, queryTypeName string, config Configuration) plan.DataSourceConfiguration {
+	childNodes := make(plan.TypeFields, 0, len(config.Types)+1)
+	// the datasource resolves the __typename of the interface
+	childNodes = append(childNodes, plan.TypeField{
+		TypeName: config.interfaceTypeName(),
+	})
+	keys := make(plan.FederationFieldConfigurations, 0, len(config.Types))
+	for _, nodeType := range config.Types {
+		childNodes = append(childNodes, plan.TypeField{
+			TypeName:   nodeType.TypeName,
+			FieldNames: nodeType.KeyFields,
+		})
+		keys = append(keys, plan.FederationFieldConfiguration{
+			TypeName:     nodeType.TypeName,
+			SelectionSet: strings.Join(nodeType.KeyFields, " "),
+			// the datasource only resolves the node field, entities are loaded from their owning datasources
+			DisableEntityResolver: true,
+		})
+	}
+
+	return plan.DataSourceConfiguration{
+		ID: id,
+		RootNodes: plan.TypeFields{
+			{
+				TypeName:   queryTypeName,
+				FieldNames: []string{config.fieldName()},
+			},
+		},
+		ChildNodes: childNodes,
+		FederationMetaData: plan.FederationMetaData{
+			Keys: keys,
+		},
+		Custom:  ConfigJSON(config),
+		Factory: &Factory{},
+	}
+}
+
+type Factory struct{}
+
+func (f *Factory) Planner(_ context.Context) plan.DataSourcePlanner {
+	return &Planner{}
+}
+
+type Planner struct {
+	v              *plan.Visitor
+	upstreamSchema *ast.Document
+	config         Configuration
+	rootField      int
+	rootFieldPath  string
+	input          string
+	variables      resolve.Variables
+}
+
+// UpstreamSchema returns the schema of the node field, the planner uses it to rewrite selections on the interface
+// into selections on the node types
+func (p *Planner) UpstreamSchema(dataSourceConfig plan.DataSourceConfiguration) *ast.Document {
+	if p.upstreamSchema != nil {
+		return p.upstreamSchema
+	}
+
+	var config Configuration
+	if err := json.Unmarshal(dataSourceConfig.Custom, &config); err != nil {
+		panic(err)
+	}
+
+	definition, report := astparser.ParseGraphqlDocumentString(upstreamSchemaSDL(config))
+	if report.HasErrors() {
+		panic(report.Error())
+	}
+	p.upstreamSchema = &definition
+	return p.upstreamSchema
+}
+
+// upstreamSchemaSDL renders the interface and the node types with their key fields,
+// the types of the key fields don't matter for planning
+func upstreamSchemaSDL(config Configuration) string {
+	sdl := strings.Builder{}
+	sdl.WriteString("interface " + config.interfaceTypeName() + "\n")
+	for _, nodeType := range config.Types {
+		sdl.WriteString("type " + nodeType.TypeName + " implements " + config.interfaceTypeName() + " {")
+		for _, keyField := range nodeType.KeyFields {
+			sdl.WriteString(" " + keyField + ": ID")
+		}
+		sdl.WriteString(" }\n")
+	}
+	return sdl.String()
+}
+
+func (p *Planner) Register(visitor *plan.Visitor, configuration plan.DataSourceConfiguration, _ plan.DataSourcePlannerConfiguration) error {
+	p.v = visitor
+	p.rootField = ast.InvalidRef
+	p.input = ""
+	p.variables = nil
+	visitor.Walker.RegisterEnterFieldVisitor(p)
+	return json.Unmarshal(configuration.Custom, &p.config)
+}
+
+func (p *Planner) DownstreamResponseFieldAlias(_ int) (alias string, exists bool) {
+	// the node DataSourcePlanner doesn't rewrite upstream fields: skip
+	return
+}
+
+func (p *Planner) DataSourcePlanningBehavior() plan.DataSourcePlanningBehavior {
+	return plan.DataSourcePlanningBehavior{
+		MergeAliasedRootNodes:      false,
+		OverrideFieldPathFromAlias: true,
+		IncludeTypeNameFields:      true,
+	}
+}
+
+func (p *Planner) EnterField(ref int) {
+	if p.rootField != ast.InvalidRef || p.v.Operation.FieldNameString(ref) != p.config.fieldName() {
+		return
+	}
+	p.rootField = ref
+	p.rootFieldPath = p.v.Operation.FieldAliasOrNameString(ref)
+
+	id, ok := p.idArgumentValue(ref)
+	if !ok {
+		p.v.Walker.StopWithInternalErr(errors.New("node_datasource: id argument of the node field is missing"))
+		return
+	}
+	p.input = `{"id":` + id + `}`
+}
+
+// idArgumentValue renders the value of the id argument as json, variables are rendered at execution time
+func (p *Planner) idArgumentValue(fieldRef int) (string, bool) {
+	argumentRef, ok := p.v.Operation.FieldArgument(fieldRef, []byte(idArgumentName))
+	if !ok {
+		return "", false
+	}
+	value := p.v.Operation.ArgumentValue(argumentRef)
+	if value.Kind != ast.ValueKindVariable {
+		valueJson, err := p.v.Operation.ValueToJSON(value)
+		if err != nil {
+			return "", false
+		}
+		return string(valueJson), true
+	}
+
+	variableName := p.v.Operation.VariableValueNameBytes(value.Ref)
+	variableDefinition, ok := p.v.Operation.VariableDefinitionByNameAndOperation(p.v.Walker.Ancestors[0].Ref, variableName)
+	if !ok {
+		return "", false
+	}
+	renderer, err := resolve.NewJSONVariableRendererWithValidationFromTypeRef(p.v.Operation, p.v.Definition, p.v.Operation.VariableDefinitions[variableDefinition].Type)
+	if err != nil {
+		return "", false
+	}
+	contextVariableName, _ := p.variables.AddVariable(&resolve.ContextVariable{
+		Path:     []string{string(variableName)},
+		Renderer: renderer,
+	})
+	return contextVariableName, true
+}
+
+func (p *Planner) ConfigureFetch() resolve.FetchConfiguration {
+	if p.rootField == ast.InvalidRef {
+		p.v.Walker.StopWithInternalErr(errors.New("node_datasource: node field is not set"))
+	}
+
+	return resolve.FetchConfiguration{
+		Input:     p.input,
+		Variables: p.variables,
+		DataSource: &Source{
+			types: p.config.Types,
+		},
+		PostProcessing: resolve.PostProcessingConfiguration{
+			MergePath: []string{p.rootFieldPath},
+		},
+	}
+}
+
+func (p *Planner) ConfigureSubscription() plan.SubscriptionConfiguration {
+	// the node DataSourcePlanner doesn't have subscriptions
+	return plan.SubscriptionConfiguration{}
+}
+
+// Source resolves the type name and the key fields of the entity identified by the global id of the input
+// ids of types which are not configured or which miss key fields resolve to null
+type Source struct {
+	types []NodeType
+}
+
+func (s *Source) Load(_ context.Context, input []byte, w io.Writer) (err error) {
+	id, err := jsonparser.GetString(input, idArgumentName)
+	if err != nil {
+		return err
+	}
+	typeName, key, err := DecodeGlobalID(id)
+	if err != nil {
+		return err
+	}
+
+	nodeType, ok := s.nodeType(typeName)
+	if !ok {
+		_, err = w.Write(null)
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(`{"__typename":`)
+	typeNameJson, _ := json.Marshal(typeName)
+	buf.Write(typeNameJson)
+	for _, keyField := range nodeType.KeyFields {
+		value, dataType, _, err := jsonparser.Get(key, keyField)
+		if err != nil || dataType == jsonparser.Null || dataType == jsonparser.Object || dataType == jsonparser.Array {
+			_, err = w.Write(null)
+			return err
+		}
+		buf.WriteByte(',')
+		keyFieldJson, _ := json.Marshal(keyField)
+		buf.Write(keyFieldJson)
+		buf.WriteByte(':')
+		if dataType == jsonparser.String {
+			// jsonparser returns strings without quotes, the escaping of the key is kept
+			buf.WriteByte('"')
+			buf.Write(value)
+			buf.WriteByte('"')
+			continue
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func (s *Source) nodeType(typeName string) (NodeType, bool) {
+	for i := range s.types {
+		if s.types[i].TypeName == typeName {
+			return s.types[i], true
+		}
+	}
+	return NodeType{}, false
+}
+
+// Interface Guards
+var (
+	_ plan.PlannerFactory    = (*Factory)(nil)
+	_ plan.DataSourcePlanner = (*Planner)(nil)
+	_ resolve.DataSource     = (*Source)(nil)
+)
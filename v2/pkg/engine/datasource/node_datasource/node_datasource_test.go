@@ -0,0 +1,83 @@
+package node_datasource
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlobalID(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		id, err := EncodeGlobalID("User", []byte(`{ "id": "1" }`))
+		require.NoError(t, err)
+
+		typeName, key, err := DecodeGlobalID(id)
+		require.NoError(t, err)
+		assert.Equal(t, "User", typeName)
+		assert.Equal(t, `{"id":"1"}`, string(key))
+	})
+
+	t.Run("key has to be an object", func(t *testing.T) {
+		_, err := EncodeGlobalID("User", []byte(`"1"`))
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid ids", func(t *testing.T) {
+		for _, id := range []string{"", "not base64!", "VXNlcg", "OnsiaWQiOiIxIn0"} {
+			_, _, err := DecodeGlobalID(id)
+			assert.ErrorIs(t, err, errInvalidGlobalID, id)
+		}
+	})
+}
+
+func TestSource_Load(t *testing.T) {
+	source := &Source{
+		types: []NodeType{
+			{TypeName: "User", KeyFields: []string{"id"}},
+			{TypeName: "Product", KeyFields: []string{"upc", "sku"}},
+		},
+	}
+
+	load := func(t *testing.T, typeName, key string) (string, error) {
+		t.Helper()
+
+		id, err := EncodeGlobalID(typeName, []byte(key))
+		require.NoError(t, err)
+
+		buf := &bytes.Buffer{}
+		err = source.Load(context.Background(), []byte(`{"id":"`+id+`"}`), buf)
+		return buf.String(), err
+	}
+
+	t.Run("resolves the key fields", func(t *testing.T) {
+		out, err := load(t, "User", `{"id":"1","name":"ignored"}`)
+		require.NoError(t, err)
+		assert.Equal(t, `{"__typename":"User","id":"1"}`, out)
+	})
+
+	t.Run("resolves compound keys", func(t *testing.T) {
+		out, err := load(t, "Product", `{"sku":1,"upc":"top-1"}`)
+		require.NoError(t, err)
+		assert.Equal(t, `{"__typename":"Product","upc":"top-1","sku":1}`, out)
+	})
+
+	t.Run("unknown types resolve to null", func(t *testing.T) {
+		out, err := load(t, "Review", `{"id":"1"}`)
+		require.NoError(t, err)
+		assert.Equal(t, `null`, out)
+	})
+
+	t.Run("missing key fields resolve to null", func(t *testing.T) {
+		out, err := load(t, "Product", `{"upc":"top-1"}`)
+		require.NoError(t, err)
+		assert.Equal(t, `null`, out)
+	})
+
+	t.Run("invalid ids", func(t *testing.T) {
+		err := source.Load(context.Background(), []byte(`{"id":"invalid"}`), &bytes.Buffer{})
+		assert.ErrorIs(t, err, errInvalidGlobalID)
+	})
+}
@@ -0,0 +1,350 @@
+package postgres_datasource
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasourcetesting"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+const schema = `
+	type Query {
+		user(id: ID!): User
+		users(limit: Int): [User!]!
+	}
+
+	type User {
+		id: ID!
+		name: String!
+		orders: [Order!]!
+	}
+
+	type Order {
+		id: ID!
+		total: Float!
+	}
+`
+
+var postgresConfiguration = Configuration{
+	Fields: []FieldConfiguration{
+		{
+			TypeName:  "Query",
+			FieldName: "user",
+			Query:     "SELECT id, full_name FROM users WHERE id = {{ .arguments.id }}",
+		},
+		{
+			TypeName:  "Query",
+			FieldName: "users",
+			Query:     "SELECT id, full_name FROM users LIMIT {{ .arguments.limit }}",
+		},
+		{
+			TypeName:  "User",
+			FieldName: "orders",
+			Query:     "SELECT id, total FROM orders WHERE user_id = {{.object.id}}",
+		},
+	},
+}
+
+func dataSourceConfiguration(factory *Factory) plan.DataSourceConfiguration {
+	return plan.DataSourceConfiguration{
+		ID: "postgres",
+		RootNodes: []plan.TypeField{
+			{TypeName: "Query", FieldNames: []string{"user", "users"}},
+			{TypeName: "User", FieldNames: []string{"orders"}},
+		},
+		ChildNodes: []plan.TypeField{
+			{TypeName: "User", FieldNames: []string{"id", "name"}},
+			{TypeName: "Order", FieldNames: []string{"id", "total"}},
+		},
+		Custom:  ConfigJSON(postgresConfiguration),
+		Factory: factory,
+	}
+}
+
+var fieldConfigurations = plan.FieldConfigurations{
+	{
+		TypeName:  "Query",
+		FieldName: "user",
+		Arguments: []plan.ArgumentConfiguration{
+			{Name: "id", SourceType: plan.FieldArgumentSource},
+		},
+	},
+	{
+		TypeName:  "Query",
+		FieldName: "users",
+		Arguments: []plan.ArgumentConfiguration{
+			{Name: "limit", SourceType: plan.FieldArgumentSource},
+		},
+	},
+	{
+		TypeName:  "User",
+		FieldName: "name",
+		Path:      []string{"full_name"},
+	},
+}
+
+// testDriver is a database/sql driver which returns the rows of the table whose prefix matches the executed query
+type testDriver struct {
+	mu      sync.Mutex
+	queries []string
+	tables  map[string]testTable
+}
+
+type testTable struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (d *testDriver) Open(_ string) (driver.Conn, error) {
+	return &testConn{driver: d}, nil
+}
+
+func (d *testDriver) executed() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := d.queries
+	d.queries = nil
+	return out
+}
+
+type testConn struct {
+	driver *testDriver
+}
+
+func (c *testConn) Prepare(_ string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *testConn) Close() error {
+	return nil
+}
+
+func (c *testConn) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *testConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	values := make([]any, len(args))
+	for i := range args {
+		values[i] = args[i].Value
+	}
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	c.driver.queries = append(c.driver.queries, fmt.Sprintf("%s %v", query, values))
+	for prefix, table := range c.driver.tables {
+		if strings.HasPrefix(query, prefix) {
+			return &testRows{table: table}, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected query: %s", query)
+}
+
+type testRows struct {
+	table testTable
+	next  int
+}
+
+func (r *testRows) Columns() []string {
+	return r.table.columns
+}
+
+func (r *testRows) Close() error {
+	return nil
+}
+
+func (r *testRows) Next(dest []driver.Value) error {
+	if r.next == len(r.table.rows) {
+		return io.EOF
+	}
+	copy(dest, r.table.rows[r.next])
+	r.next++
+	return nil
+}
+
+var testDB = &testDriver{
+	tables: map[string]testTable{
+		"SELECT id, full_name FROM users WHERE": {
+			columns: []string{"id", "full_name"},
+			rows:    [][]driver.Value{{"1", []byte("Jens")}},
+		},
+		"SELECT id, full_name FROM users LIMIT": {
+			columns: []string{"id", "full_name"},
+			rows:    [][]driver.Value{{"1", "Jens"}, {"2", "Stefan"}},
+		},
+		"SELECT id, total FROM orders": {
+			columns: []string{"id", "total"},
+			rows:    [][]driver.Value{{"10", 9.5}, {"11", int64(20)}},
+		},
+	},
+}
+
+func init() {
+	sql.Register("postgres_datasource_test", testDB)
+}
+
+func TestPostgresDataSourcePlanning(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	factory := &Factory{DriverName: "postgres_datasource_test"}
+	db, err := factory.open(ctx)
+	require.NoError(t, err)
+
+	planConfiguration := plan.Configuration{
+		DataSources:                  []plan.DataSourceConfiguration{dataSourceConfiguration(factory)},
+		Fields:                       fieldConfigurations,
+		DisableResolveFieldPositions: true,
+	}
+
+	t.Run("query with argument bound as parameter", datasourcetesting.RunTest(schema, `
+		query User($id: ID!) { user(id: $id) { id } }`, "User",
+		&plan.SynchronousResponsePlan{
+			Response: &resolve.GraphQLResponse{
+				Data: &resolve.Object{
+					Fetch: &resolve.SingleFetch{
+						FetchConfiguration: resolve.FetchConfiguration{
+							Input: `{"query":"SELECT id, full_name FROM users WHERE id = $1","args":[$$0$$],"list":false}`,
+							Variables: resolve.Variables{
+								&resolve.ContextVariable{
+									Path:     []string{"id"},
+									Renderer: resolve.NewJSONVariableRenderer(),
+								},
+							},
+							DataSource: &Source{db: db},
+							PostProcessing: resolve.PostProcessingConfiguration{
+								MergePath: []string{"user"},
+							},
+						},
+						DataSourceIdentifier: []byte("postgres_datasource.Source"),
+					},
+					Fields: []*resolve.Field{
+						{
+							Name: []byte("user"),
+							Value: &resolve.Object{
+								Path:     []string{"user"},
+								Nullable: true,
+								Fields: []*resolve.Field{
+									{
+										Name: []byte("id"),
+										Value: &resolve.Scalar{
+											Path: []string{"id"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}, planConfiguration))
+
+	t.Run("arguments which are not set are bound as null", datasourcetesting.RunTest(schema, `
+		query Users { users { id } }`, "Users",
+		&plan.SynchronousResponsePlan{
+			Response: &resolve.GraphQLResponse{
+				Data: &resolve.Object{
+					Fetch: &resolve.SingleFetch{
+						FetchConfiguration: resolve.FetchConfiguration{
+							Input:      `{"query":"SELECT id, full_name FROM users LIMIT $1","args":[null],"list":true}`,
+							DataSource: &Source{db: db},
+							PostProcessing: resolve.PostProcessingConfiguration{
+								MergePath: []string{"users"},
+							},
+						},
+						DataSourceIdentifier: []byte("postgres_datasource.Source"),
+					},
+					Fields: []*resolve.Field{
+						{
+							Name: []byte("users"),
+							Value: &resolve.Array{
+								Path: []string{"users"},
+								Item: &resolve.Object{
+									Fields: []*resolve.Field{
+										{
+											Name: []byte("id"),
+											Value: &resolve.Scalar{
+												Path: []string{"id"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}, planConfiguration))
+}
+
+func TestPostgresDataSource(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	factory := &Factory{DriverName: "postgres_datasource_test", MaxOpenConns: 2}
+
+	graphqlSchema, err := graphql.NewSchemaFromString(schema)
+	require.NoError(t, err)
+	engineConfig := graphql.NewEngineV2Configuration(graphqlSchema)
+	engineConfig.SetDataSources([]plan.DataSourceConfiguration{dataSourceConfiguration(factory)})
+	engineConfig.SetFieldConfigurations(fieldConfigurations)
+	engine, err := graphql.NewExecutionEngineV2(ctx, abstractlogger.NoopLogger, engineConfig)
+	require.NoError(t, err)
+
+	execute := func(t *testing.T, request *graphql.Request) string {
+		t.Helper()
+		testDB.executed()
+		resultWriter := graphql.NewEngineResultWriter()
+		require.NoError(t, engine.Execute(ctx, request, &resultWriter))
+		return resultWriter.String()
+	}
+
+	t.Run("object with nested list bound to the parent object", func(t *testing.T) {
+		out := execute(t, &graphql.Request{
+			Query:     `query User($id: ID!) { user(id: $id) { id name orders { id total } } }`,
+			Variables: []byte(`{"id":"1"}`),
+		})
+		assert.Equal(t, `{"data":{"user":{"id":"1","name":"Jens","orders":[{"id":"10","total":9.5},{"id":"11","total":20}]}}}`, out)
+		assert.Equal(t, []string{
+			"SELECT id, full_name FROM users WHERE id = $1 [1]",
+			"SELECT id, total FROM orders WHERE user_id = $1 [1]",
+		}, testDB.executed())
+	})
+
+	t.Run("list with literal argument", func(t *testing.T) {
+		out := execute(t, &graphql.Request{
+			Query: `{ users(limit: 2) { name } }`,
+		})
+		assert.Equal(t, `{"data":{"users":[{"name":"Jens"},{"name":"Stefan"}]}}`, out)
+		assert.Equal(t, []string{"SELECT id, full_name FROM users LIMIT $1 [2]"}, testDB.executed())
+	})
+
+	t.Run("the connection pool is closed with the context of the planner", func(t *testing.T) {
+		factory.mu.Lock()
+		db := factory.db
+		factory.mu.Unlock()
+		require.NotNil(t, db)
+		require.NoError(t, db.Ping())
+
+		cancel()
+		assert.Eventually(t, func() bool {
+			return db.Ping() != nil
+		}, time.Second, 10*time.Millisecond)
+		factory.mu.Lock()
+		defer factory.mu.Unlock()
+		assert.Nil(t, factory.db)
+	})
+}
@@ -0,0 +1,269 @@
+// Package rest_datasource plans and resolves fields from plain REST endpoints.
+//
+// Each field is configured with the method, the url, the headers and the body of its request.
+// The url, the query parameters, the headers and the body are templates, e.g.:
+//
+//	{{ .arguments.id }}                 the value of an argument of the field
+//	{{ .object.id }}                    the value of a field of the parent object
+//	{{ .request.headers.Authorization }} a header of the client request
+//
+// The ResponsePath selects the value of the field from the json response with a JSONPath-style selector,
+// e.g. "$.data.users[0]". The fields of the selected value are mapped with the Path of the plan.FieldConfiguration.
+package rest_datasource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/httpclient"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+// argumentTemplateRegex matches the argument templates of a query parameter, e.g. {{ .arguments.id }}
+var argumentTemplateRegex = regexp.MustCompile(`{{\s*\.arguments\.([a-zA-Z0-9_]+)`)
+
+type Configuration struct {
+	Fields []FieldConfiguration `json:"fields"`
+}
+
+// FieldConfiguration is the request of a field
+type FieldConfiguration struct {
+	TypeName  string `json:"typeName"`
+	FieldName string `json:"fieldName"`
+	// Method of the request, defaults to GET
+	Method string `json:"method,omitempty"`
+	// URL of the request, e.g. "https://api.example.com/users/{{ .arguments.id }}"
+	URL string `json:"url"`
+	// Header of the request, e.g. {"Authorization": ["{{ .request.headers.Authorization }}"]}
+	Header http.Header `json:"header,omitempty"`
+	// Query parameters of the request, parameters with undefined variables are omitted
+	Query []QueryConfiguration `json:"query,omitempty"`
+	// Body of the request, e.g. `{"name":"{{ .arguments.name }}"}`
+	Body string `json:"body,omitempty"`
+	// ResponsePath selects the value of the field from the response, e.g. "$.data.users[0]",
+	// the whole response is the value of the field if it's empty
+	ResponsePath string `json:"responsePath,omitempty"`
+}
+
+type QueryConfiguration struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func ConfigJSON(config Configuration) json.RawMessage {
+	out, _ := json.Marshal(config)
+	return out
+}
+
+// ParseResponsePath converts a JSONPath-style selector into the path of the value in the response.
+// Only child and array index selectors are supported, e.g. "$.data.users[0].name" or "data.users[0]".
+func ParseResponsePath(responsePath string) ([]string, error) {
+	selector := strings.TrimPrefix(strings.TrimPrefix(responsePath, "$"), ".")
+	if selector == "" {
+		return nil, nil
+	}
+	var path []string
+	for _, segment := range strings.Split(selector, ".") {
+		name, indexes, _ := strings.Cut(segment, "[")
+		if name != "" {
+			path = append(path, name)
+		}
+		if indexes == "" {
+			if name == "" {
+				return nil, fmt.Errorf("rest_datasource: invalid response path %q: empty segment", responsePath)
+			}
+			continue
+		}
+		for _, index := range strings.Split(strings.TrimSuffix(indexes, "]"), "][") {
+			if _, err := strconv.ParseUint(index, 10, 32); err != nil {
+				return nil, fmt.Errorf("rest_datasource: invalid response path %q: unsupported array selector [%s]", responsePath, index)
+			}
+			path = append(path, "["+index+"]")
+		}
+	}
+	return path, nil
+}
+
+type Factory struct {
+	Client *http.Client
+}
+
+func (f *Factory) Planner(_ context.Context) plan.DataSourcePlanner {
+	return &Planner{
+		client: f.Client,
+	}
+}
+
+type Planner struct {
+	client       *http.Client
+	v            *plan.Visitor
+	config       Configuration
+	rootFieldRef int
+	// operationDefinition is the operation of the root field, the query parameters are omitted if it doesn't define their variables
+	operationDefinition int
+	current             *FieldConfiguration
+	// parentPath is the path of the parent field of a nested planner, the parent field is resolved by another fetch
+	parentPath string
+}
+
+func (p *Planner) Register(visitor *plan.Visitor, configuration plan.DataSourceConfiguration, plannerConfiguration plan.DataSourcePlannerConfiguration) error {
+	p.v = visitor
+	p.parentPath = plannerConfiguration.ParentPath
+	p.rootFieldRef = ast.InvalidRef
+	p.current = nil
+	visitor.Walker.RegisterEnterFieldVisitor(p)
+	if err := json.Unmarshal(configuration.Custom, &p.config); err != nil {
+		return err
+	}
+	for i := range p.config.Fields {
+		if _, err := ParseResponsePath(p.config.Fields[i].ResponsePath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Planner) UpstreamSchema(_ plan.DataSourceConfiguration) *ast.Document {
+	return nil
+}
+
+func (p *Planner) DownstreamResponseFieldAlias(_ int) (alias string, exists bool) {
+	// the REST DataSourcePlanner doesn't rewrite upstream fields: skip
+	return
+}
+
+func (p *Planner) DataSourcePlanningBehavior() plan.DataSourcePlanningBehavior {
+	return plan.DataSourcePlanningBehavior{
+		MergeAliasedRootNodes:      false,
+		OverrideFieldPathFromAlias: false,
+	}
+}
+
+func (p *Planner) EnterField(ref int) {
+	if p.rootFieldRef != ast.InvalidRef {
+		// nested fields are resolved from the response of the root field
+		return
+	}
+	if p.parentPath != "query" && p.parentPath == p.v.Walker.Path.DotDelimitedString()+"."+p.v.Operation.FieldAliasOrNameString(ref) {
+		return
+	}
+	p.rootFieldRef = ref
+	p.operationDefinition = p.v.Walker.Ancestors[0].Ref
+
+	fieldName := p.v.Operation.FieldNameString(ref)
+	typeName := p.v.Walker.EnclosingTypeDefinition.NameString(p.v.Definition)
+	for i := range p.config.Fields {
+		if p.config.Fields[i].TypeName == typeName && p.config.Fields[i].FieldName == fieldName {
+			p.current = &p.config.Fields[i]
+			return
+		}
+	}
+	p.v.Walker.StopWithInternalErr(fmt.Errorf("rest_datasource: field %s.%s is not configured", typeName, fieldName))
+}
+
+func (p *Planner) ConfigureFetch() resolve.FetchConfiguration {
+	if p.current == nil {
+		p.v.Walker.StopWithInternalErr(errors.New("rest_datasource: root field is not set"))
+		return resolve.FetchConfiguration{}
+	}
+
+	// the response path is validated in Register
+	responsePath, _ := ParseResponsePath(p.current.ResponsePath)
+	return resolve.FetchConfiguration{
+		Input: string(p.configureInput()),
+		DataSource: &Source{
+			client: p.client,
+		},
+		PostProcessing: resolve.PostProcessingConfiguration{
+			SelectResponseDataPath: responsePath,
+			MergePath:              []string{p.current.FieldName},
+		},
+	}
+}
+
+func (p *Planner) ConfigureSubscription() plan.SubscriptionConfiguration {
+	// the REST DataSourcePlanner doesn't have subscriptions
+	return plan.SubscriptionConfiguration{}
+}
+
+// configureInput renders the request of the field, the templates are resolved by the planner
+func (p *Planner) configureInput() []byte {
+	method := p.current.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	input := httpclient.SetInputURL(nil, []byte(p.current.URL))
+	input = httpclient.SetInputMethod(input, []byte(method))
+	input = httpclient.SetInputBody(input, []byte(p.current.Body))
+
+	if len(p.current.Header) != 0 {
+		header, err := json.Marshal(p.current.Header)
+		if err == nil {
+			input = httpclient.SetInputHeader(input, header)
+		}
+	}
+
+	if queryParams := p.definedQueryParams(); len(queryParams) != 0 {
+		query, err := json.Marshal(queryParams)
+		if err == nil {
+			input = httpclient.SetInputQueryParams(input, query)
+		}
+	}
+	return input
+}
+
+// definedQueryParams omits the query parameters with an argument template of an argument which is not set,
+// or which is set to a variable without a definition
+func (p *Planner) definedQueryParams() []QueryConfiguration {
+	out := make([]QueryConfiguration, 0, len(p.current.Query))
+	for _, query := range p.current.Query {
+		if p.hasUndefinedArgument(query.Value) {
+			continue
+		}
+		out = append(out, query)
+	}
+	return out
+}
+
+func (p *Planner) hasUndefinedArgument(template string) bool {
+	for _, match := range argumentTemplateRegex.FindAllStringSubmatch(template, -1) {
+		argumentRef, ok := p.v.Operation.FieldArgument(p.rootFieldRef, []byte(match[1]))
+		if !ok {
+			return true
+		}
+		value := p.v.Operation.ArgumentValue(argumentRef)
+		if value.Kind != ast.ValueKindVariable {
+			continue
+		}
+		variableName := p.v.Operation.VariableValueNameString(value.Ref)
+		if !p.v.Operation.OperationDefinitionHasVariableDefinition(p.operationDefinition, variableName) {
+			return true
+		}
+	}
+	return false
+}
+
+// Source sends the request of the input with the http client
+type Source struct {
+	client *http.Client
+}
+
+func (s *Source) Load(ctx context.Context, input []byte, w io.Writer) (err error) {
+	return httpclient.Do(s.client, ctx, input, w)
+}
+
+// Interface Guards
+var (
+	_ plan.PlannerFactory    = (*Factory)(nil)
+	_ plan.DataSourcePlanner = (*Planner)(nil)
+	_ resolve.DataSource     = (*Source)(nil)
+)
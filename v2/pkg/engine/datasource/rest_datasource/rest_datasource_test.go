@@ -0,0 +1,306 @@
+package rest_datasource
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/httpclient"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasourcetesting"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+const schema = `
+	type Query {
+		user(id: ID!): User
+		users(limit: Int): [User!]!
+	}
+
+	type Mutation {
+		createUser(name: String!): User
+	}
+
+	type User {
+		id: ID!
+		name: String!
+		friends: [User!]!
+	}
+`
+
+var restConfiguration = Configuration{
+	Fields: []FieldConfiguration{
+		{
+			TypeName:  "Query",
+			FieldName: "user",
+			URL:       "https://example.com/users/{{ .arguments.id }}",
+			Header: http.Header{
+				"Authorization": []string{"{{ .request.headers.Authorization }}"},
+			},
+			ResponsePath: "$.data",
+		},
+		{
+			TypeName:  "Query",
+			FieldName: "users",
+			URL:       "https://example.com/users",
+			Query: []QueryConfiguration{
+				{Name: "limit", Value: "{{ .arguments.limit }}"},
+			},
+		},
+		{
+			TypeName:  "Mutation",
+			FieldName: "createUser",
+			Method:    http.MethodPost,
+			URL:       "https://example.com/users",
+			Body:      `{"name":"{{ .arguments.name }}"}`,
+		},
+		{
+			TypeName:     "User",
+			FieldName:    "friends",
+			URL:          "https://example.com/users/{{ .object.id }}/friends",
+			ResponsePath: "$.pages[0].items",
+		},
+	},
+}
+
+func dataSourceConfiguration(client *http.Client) plan.DataSourceConfiguration {
+	return plan.DataSourceConfiguration{
+		ID: "users",
+		RootNodes: []plan.TypeField{
+			{TypeName: "Query", FieldNames: []string{"user", "users"}},
+			{TypeName: "Mutation", FieldNames: []string{"createUser"}},
+			{TypeName: "User", FieldNames: []string{"friends"}},
+		},
+		ChildNodes: []plan.TypeField{
+			{TypeName: "User", FieldNames: []string{"id", "name"}},
+		},
+		Custom:  ConfigJSON(restConfiguration),
+		Factory: &Factory{Client: client},
+	}
+}
+
+var fieldConfigurations = plan.FieldConfigurations{
+	{
+		TypeName:  "Query",
+		FieldName: "user",
+		Arguments: []plan.ArgumentConfiguration{
+			{Name: "id", SourceType: plan.FieldArgumentSource},
+		},
+	},
+	{
+		TypeName:  "Query",
+		FieldName: "users",
+		Arguments: []plan.ArgumentConfiguration{
+			{Name: "limit", SourceType: plan.FieldArgumentSource},
+		},
+	},
+	{
+		TypeName:  "Mutation",
+		FieldName: "createUser",
+		Arguments: []plan.ArgumentConfiguration{
+			{Name: "name", SourceType: plan.FieldArgumentSource},
+		},
+	},
+	{
+		TypeName:  "User",
+		FieldName: "name",
+		Path:      []string{"full_name"},
+	},
+}
+
+func TestParseResponsePath(t *testing.T) {
+	for responsePath, expected := range map[string][]string{
+		"":                  nil,
+		"$":                 nil,
+		"$.data":            {"data"},
+		"data.users":        {"data", "users"},
+		"$.data.users[0]":   {"data", "users", "[0]"},
+		"$.matrix[1][2].id": {"matrix", "[1]", "[2]", "id"},
+		"$[0].id":           {"[0]", "id"},
+	} {
+		path, err := ParseResponsePath(responsePath)
+		require.NoError(t, err, responsePath)
+		assert.Equal(t, expected, path, responsePath)
+	}
+
+	for _, responsePath := range []string{"$..users", "$.users[*]", "$.users[?(@.id)]", "$.users[-1]", "$.data."} {
+		_, err := ParseResponsePath(responsePath)
+		assert.Error(t, err, responsePath)
+	}
+}
+
+func TestRESTDataSourcePlanning(t *testing.T) {
+	planConfiguration := plan.Configuration{
+		DataSources:                  []plan.DataSourceConfiguration{dataSourceConfiguration(httpclient.DefaultNetHttpClient)},
+		Fields:                       fieldConfigurations,
+		DisableResolveFieldPositions: true,
+	}
+
+	t.Run("get request with argument and header", datasourcetesting.RunTest(schema, `
+		query User($id: ID!) { user(id: $id) { id } }`, "User",
+		&plan.SynchronousResponsePlan{
+			Response: &resolve.GraphQLResponse{
+				Data: &resolve.Object{
+					Fetch: &resolve.SingleFetch{
+						FetchConfiguration: resolve.FetchConfiguration{
+							Input: `{"header":{"Authorization":["$$0$$"]},"method":"GET","url":"https://example.com/users/$$1$$"}`,
+							Variables: resolve.Variables{
+								&resolve.HeaderVariable{
+									Path: []string{"Authorization"},
+								},
+								&resolve.ContextVariable{
+									Path:     []string{"id"},
+									Renderer: resolve.NewPlainVariableRendererWithValidation(`{"type":["string","integer"]}`),
+								},
+							},
+							DataSource: &Source{client: httpclient.DefaultNetHttpClient},
+							PostProcessing: resolve.PostProcessingConfiguration{
+								SelectResponseDataPath: []string{"data"},
+								MergePath:              []string{"user"},
+							},
+						},
+						DataSourceIdentifier: []byte("rest_datasource.Source"),
+					},
+					Fields: []*resolve.Field{
+						{
+							Name: []byte("user"),
+							Value: &resolve.Object{
+								Path:     []string{"user"},
+								Nullable: true,
+								Fields: []*resolve.Field{
+									{
+										Name: []byte("id"),
+										Value: &resolve.Scalar{
+											Path: []string{"id"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}, planConfiguration))
+
+	t.Run("query parameters without variables are omitted", datasourcetesting.RunTest(schema, `
+		query Users { users { id } }`, "Users",
+		&plan.SynchronousResponsePlan{
+			Response: &resolve.GraphQLResponse{
+				Data: &resolve.Object{
+					Fetch: &resolve.SingleFetch{
+						FetchConfiguration: resolve.FetchConfiguration{
+							Input:      `{"method":"GET","url":"https://example.com/users"}`,
+							DataSource: &Source{client: httpclient.DefaultNetHttpClient},
+							PostProcessing: resolve.PostProcessingConfiguration{
+								MergePath: []string{"users"},
+							},
+						},
+						DataSourceIdentifier: []byte("rest_datasource.Source"),
+					},
+					Fields: []*resolve.Field{
+						{
+							Name: []byte("users"),
+							Value: &resolve.Array{
+								Path: []string{"users"},
+								Item: &resolve.Object{
+									Fields: []*resolve.Field{
+										{
+											Name: []byte("id"),
+											Value: &resolve.Scalar{
+												Path: []string{"id"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}, planConfiguration))
+}
+
+func TestRESTDataSource(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.String()+" "+r.Header.Get("Authorization")+" "+string(body))
+		switch r.URL.Path {
+		case "/users/1":
+			_, _ = w.Write([]byte(`{"data":{"id":"1","full_name":"Jens"}}`))
+		case "/users/1/friends":
+			_, _ = w.Write([]byte(`{"pages":[{"items":[{"id":"2","full_name":"Stefan"}]}]}`))
+		case "/users":
+			if r.Method == http.MethodPost {
+				_, _ = w.Write([]byte(`{"id":"3","full_name":"Dustin"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`[{"id":"1","full_name":"Jens"},{"id":"2","full_name":"Stefan"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config := restConfiguration
+	config.Fields = append([]FieldConfiguration(nil), restConfiguration.Fields...)
+	for i := range config.Fields {
+		config.Fields[i].URL = server.URL + config.Fields[i].URL[len("https://example.com"):]
+	}
+	dataSource := dataSourceConfiguration(server.Client())
+	dataSource.Custom = ConfigJSON(config)
+
+	graphqlSchema, err := graphql.NewSchemaFromString(schema)
+	require.NoError(t, err)
+	engineConfig := graphql.NewEngineV2Configuration(graphqlSchema)
+	engineConfig.SetDataSources([]plan.DataSourceConfiguration{dataSource})
+	engineConfig.SetFieldConfigurations(fieldConfigurations)
+	engine, err := graphql.NewExecutionEngineV2(context.Background(), abstractlogger.NoopLogger, engineConfig)
+	require.NoError(t, err)
+
+	execute := func(t *testing.T, request *graphql.Request) string {
+		t.Helper()
+		requests = nil
+		resultWriter := graphql.NewEngineResultWriter()
+		err := engine.Execute(context.Background(), request, &resultWriter, graphql.WithAdditionalHttpHeaders(http.Header{"Authorization": []string{"Bearer token"}}))
+		require.NoError(t, err)
+		return resultWriter.String()
+	}
+
+	t.Run("get request with response path and nested fetch", func(t *testing.T) {
+		out := execute(t, &graphql.Request{
+			Query:     `query User($id: ID!) { user(id: $id) { id name friends { id name } } }`,
+			Variables: []byte(`{"id":"1"}`),
+		})
+		assert.Equal(t, `{"data":{"user":{"id":"1","name":"Jens","friends":[{"id":"2","name":"Stefan"}]}}}`, out)
+		assert.Equal(t, []string{
+			"GET /users/1 Bearer token ",
+			"GET /users/1/friends  ",
+		}, requests)
+	})
+
+	t.Run("get request with query parameters", func(t *testing.T) {
+		out := execute(t, &graphql.Request{
+			Query:     `query Users($limit: Int) { users(limit: $limit) { name } }`,
+			Variables: []byte(`{"limit":2}`),
+		})
+		assert.Equal(t, `{"data":{"users":[{"name":"Jens"},{"name":"Stefan"}]}}`, out)
+		assert.Equal(t, []string{"GET /users?limit=2  "}, requests)
+	})
+
+	t.Run("post request with body", func(t *testing.T) {
+		out := execute(t, &graphql.Request{
+			Query:     `mutation CreateUser($name: String!) { createUser(name: $name) { id name } }`,
+			Variables: []byte(`{"name":"Dustin"}`),
+		})
+		assert.Equal(t, `{"data":{"createUser":{"id":"3","name":"Dustin"}}}`, out)
+		assert.Equal(t, []string{`POST /users  {"name":"Dustin"}`}, requests)
+	})
+}
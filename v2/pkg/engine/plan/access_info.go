@@ -0,0 +1,91 @@
+package plan
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+// AccessInfo describes which parts of the schema and which data sources are accessed by a plan,
+// e.g. to log data access for compliance.
+type AccessInfo struct {
+	// OperationType is the type of the planned operation, e.g. query, mutation, subscription
+	OperationType ast.OperationType
+	// SchemaCoordinates are the unique coordinates of the accessed fields in order of their appearance, e.g. Query.user, User.email
+	// A field of an abstract type adds a coordinate for every possible enclosing type
+	SchemaCoordinates []string
+	// DataSourceIDs are the unique IDs of the data sources which can resolve the accessed fields
+	DataSourceIDs []string
+}
+
+// GetAccessInfo collects the access info of the fields of a plan up to maxDepth, root fields have a depth of 1.
+// A maxDepth of 0 collects the fields of all depths.
+// The plan has to be created with IncludeInfo, fields without info are skipped.
+func GetAccessInfo(plan Plan, maxDepth int) AccessInfo {
+	visitor := accessInfoVisitor{
+		maxDepth:          maxDepth,
+		schemaCoordinates: map[string]struct{}{},
+		dataSourceIDs:     map[string]struct{}{},
+	}
+	switch p := plan.(type) {
+	case *SynchronousResponsePlan:
+		if p.Response.Info != nil {
+			visitor.info.OperationType = p.Response.Info.OperationType
+		}
+		visitor.visitNode(p.Response.Data, 1)
+	case *SubscriptionResponsePlan:
+		if p.Response.Response.Info != nil {
+			visitor.info.OperationType = p.Response.Response.Info.OperationType
+		}
+		visitor.visitNode(p.Response.Response.Data, 1)
+	}
+	return visitor.info
+}
+
+type accessInfoVisitor struct {
+	maxDepth          int
+	info              AccessInfo
+	schemaCoordinates map[string]struct{}
+	dataSourceIDs     map[string]struct{}
+}
+
+func (a *accessInfoVisitor) visitNode(node resolve.Node, depth int) {
+	if a.maxDepth > 0 && depth > a.maxDepth {
+		return
+	}
+	switch t := node.(type) {
+	case *resolve.Object:
+		for _, field := range t.Fields {
+			if field.Info == nil {
+				continue
+			}
+			for _, typeName := range field.Info.ParentTypeNames {
+				a.addSchemaCoordinate(typeName + "." + field.Info.Name)
+			}
+			for _, id := range field.Info.Source.IDs {
+				a.addDataSourceID(id)
+			}
+			a.visitNode(field.Value, depth+1)
+		}
+	case *resolve.Array:
+		a.visitNode(t.Item, depth)
+	}
+}
+
+func (a *accessInfoVisitor) addSchemaCoordinate(coordinate string) {
+	if _, ok := a.schemaCoordinates[coordinate]; ok {
+		return
+	}
+	a.schemaCoordinates[coordinate] = struct{}{}
+	a.info.SchemaCoordinates = append(a.info.SchemaCoordinates, coordinate)
+}
+
+func (a *accessInfoVisitor) addDataSourceID(id string) {
+	if id == "" {
+		return
+	}
+	if _, ok := a.dataSourceIDs[id]; ok {
+		return
+	}
+	a.dataSourceIDs[id] = struct{}{}
+	a.info.DataSourceIDs = append(a.info.DataSourceIDs, id)
+}
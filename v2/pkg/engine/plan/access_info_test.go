@@ -0,0 +1,90 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+func TestGetAccessInfo(t *testing.T) {
+	field := func(name string, parentTypeNames []string, sourceIDs []string, value resolve.Node) *resolve.Field {
+		return &resolve.Field{
+			Name:  []byte(name),
+			Value: value,
+			Info: &resolve.FieldInfo{
+				Name:            name,
+				ParentTypeNames: parentTypeNames,
+				Source:          resolve.TypeFieldSource{IDs: sourceIDs},
+			},
+		}
+	}
+
+	plan := &SynchronousResponsePlan{
+		Response: &resolve.GraphQLResponse{
+			Info: &resolve.GraphQLResponseInfo{
+				OperationType: ast.OperationTypeQuery,
+			},
+			Data: &resolve.Object{
+				Fields: []*resolve.Field{
+					field("users", []string{"Query"}, []string{"accounts"}, &resolve.Array{
+						Item: &resolve.Object{
+							Fields: []*resolve.Field{
+								field("name", []string{"User"}, []string{"accounts"}, &resolve.String{}),
+								field("reviews", []string{"User"}, []string{"reviews"}, &resolve.Array{
+									Item: &resolve.Object{
+										Fields: []*resolve.Field{
+											field("body", []string{"Review"}, []string{"reviews"}, &resolve.String{}),
+										},
+									},
+								}),
+								field("node", []string{"Human", "Droid"}, []string{"accounts"}, &resolve.String{}),
+							},
+						},
+					}),
+					field("__typename", []string{"Query"}, nil, &resolve.String{}),
+				},
+			},
+		},
+	}
+
+	t.Run("all depths", func(t *testing.T) {
+		assert.Equal(t, AccessInfo{
+			OperationType:     ast.OperationTypeQuery,
+			SchemaCoordinates: []string{"Query.users", "User.name", "User.reviews", "Review.body", "Human.node", "Droid.node", "Query.__typename"},
+			DataSourceIDs:     []string{"accounts", "reviews"},
+		}, GetAccessInfo(plan, 0))
+	})
+
+	t.Run("limited depth", func(t *testing.T) {
+		assert.Equal(t, AccessInfo{
+			OperationType:     ast.OperationTypeQuery,
+			SchemaCoordinates: []string{"Query.users", "User.name", "User.reviews", "Human.node", "Droid.node", "Query.__typename"},
+			DataSourceIDs:     []string{"accounts", "reviews"},
+		}, GetAccessInfo(plan, 2))
+	})
+
+	t.Run("subscription", func(t *testing.T) {
+		subscriptionPlan := &SubscriptionResponsePlan{
+			Response: &resolve.GraphQLSubscription{
+				Response: &resolve.GraphQLResponse{
+					Info: &resolve.GraphQLResponseInfo{
+						OperationType: ast.OperationTypeSubscription,
+					},
+					Data: &resolve.Object{
+						Fields: []*resolve.Field{
+							field("newReview", []string{"Subscription"}, []string{"reviews"}, &resolve.String{}),
+						},
+					},
+				},
+			},
+		}
+		assert.Equal(t, AccessInfo{
+			OperationType:     ast.OperationTypeSubscription,
+			SchemaCoordinates: []string{"Subscription.newReview"},
+			DataSourceIDs:     []string{"reviews"},
+		}, GetAccessInfo(subscriptionPlan, 1))
+	})
+}
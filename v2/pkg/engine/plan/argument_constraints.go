@@ -0,0 +1,150 @@
+package plan
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/buger/jsonparser"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// ValidateArgumentConstraints checks all field arguments of a normalized operation against the ArgumentConstraints
+// defined in the field configurations. Values of variables are looked up in the variables json.
+// As plans are cached independently of the variables, this has to be done for each request prior to planning.
+func ValidateArgumentConstraints(operation, definition *ast.Document, variables []byte, fields FieldConfigurations, report *operationreport.Report) {
+	if !fields.hasArgumentConstraints() {
+		return
+	}
+
+	walker := astvisitor.NewWalker(48)
+	visitor := &argumentConstraintsVisitor{
+		walker:     &walker,
+		operation:  operation,
+		definition: definition,
+		variables:  variables,
+		fields:     fields,
+	}
+	walker.RegisterEnterFieldVisitor(visitor)
+	walker.Walk(operation, definition, report)
+}
+
+func (f FieldConfigurations) hasArgumentConstraints() bool {
+	for i := range f {
+		for j := range f[i].Arguments {
+			if f[i].Arguments[j].Constraints != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type argumentConstraintsVisitor struct {
+	walker     *astvisitor.Walker
+	operation  *ast.Document
+	definition *ast.Document
+	variables  []byte
+	fields     FieldConfigurations
+}
+
+func (v *argumentConstraintsVisitor) EnterField(ref int) {
+	typeName := v.walker.EnclosingTypeDefinition.NameBytes(v.definition)
+	fieldName := v.operation.FieldNameBytes(ref)
+	fieldConfiguration := v.fields.ForTypeField(string(typeName), string(fieldName))
+	if fieldConfiguration == nil {
+		return
+	}
+
+	for i := range fieldConfiguration.Arguments {
+		constraints := fieldConfiguration.Arguments[i].Constraints
+		if constraints == nil {
+			continue
+		}
+
+		argumentName := []byte(fieldConfiguration.Arguments[i].Name)
+		argumentRef, exists := v.operation.FieldArgument(ref, argumentName)
+		if !exists {
+			continue
+		}
+
+		value, dataType, err := v.argumentValue(argumentRef)
+		if err != nil {
+			v.walker.StopWithInternalErr(err)
+			return
+		}
+
+		reason, ok := constraints.validate(value, dataType)
+		if ok {
+			continue
+		}
+
+		v.walker.StopWithExternalErr(operationreport.ErrArgumentConstraintViolated(argumentName, typeName, fieldName, reason, v.operation.Arguments[argumentRef].Position))
+		return
+	}
+}
+
+// argumentValue returns the json representation of the argument value
+// for variables the value is looked up in the variables json
+func (v *argumentConstraintsVisitor) argumentValue(argumentRef int) ([]byte, jsonparser.ValueType, error) {
+	value := v.operation.ArgumentValue(argumentRef)
+	if value.Kind == ast.ValueKindVariable {
+		variableName := v.operation.VariableValueNameString(value.Ref)
+		data, dataType, _, err := jsonparser.Get(v.variables, variableName)
+		if err == jsonparser.KeyPathNotFoundError {
+			return nil, jsonparser.NotExist, nil
+		}
+		return data, dataType, err
+	}
+
+	data, err := v.operation.ValueToJSON(value)
+	if err != nil {
+		return nil, jsonparser.Unknown, err
+	}
+	data, dataType, _, err := jsonparser.Get(data)
+	return data, dataType, err
+}
+
+// validate returns the reason of the violation and false when the value doesn't satisfy the constraints
+func (c *ArgumentConstraints) validate(value []byte, dataType jsonparser.ValueType) (reason string, ok bool) {
+	switch dataType {
+	case jsonparser.Number:
+		number, err := strconv.ParseFloat(string(value), 64)
+		if err != nil {
+			return fmt.Sprintf("has an invalid numeric value: %s", value), false
+		}
+		if c.Min != nil && number < *c.Min {
+			return fmt.Sprintf("must not be less than %v, found: %s", *c.Min, value), false
+		}
+		if c.Max != nil && number > *c.Max {
+			return fmt.Sprintf("must not be greater than %v, found: %s", *c.Max, value), false
+		}
+	case jsonparser.String:
+		if c.MaxLength == nil {
+			return "", true
+		}
+		str, err := jsonparser.ParseString(value)
+		if err != nil {
+			return "has an invalid string value", false
+		}
+		if length := utf8.RuneCountInString(str); length > *c.MaxLength {
+			return fmt.Sprintf("must not be longer than %d characters, found: %d", *c.MaxLength, length), false
+		}
+	case jsonparser.Array:
+		if c.MaxListSize == nil {
+			return "", true
+		}
+		size := 0
+		_, _ = jsonparser.ArrayEach(value, func(_ []byte, _ jsonparser.ValueType, _ int, _ error) {
+			size++
+		})
+		if size > *c.MaxListSize {
+			return fmt.Sprintf("must not contain more than %d items, found: %d", *c.MaxListSize, size), false
+		}
+	}
+
+	return "", true
+}
@@ -0,0 +1,116 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astnormalization"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+func TestValidateArgumentConstraints(t *testing.T) {
+	const definition = `
+		type Query {
+			users(first: Int, search: String, ids: [ID!]): [User!]!
+		}
+		type User {
+			id: ID!
+			friends(first: Int): [User!]!
+		}`
+
+	minFirst, maxFirst := 1.0, 100.0
+	maxLength, maxListSize := 5, 2
+
+	fields := FieldConfigurations{
+		{
+			TypeName:  "Query",
+			FieldName: "users",
+			Arguments: ArgumentsConfigurations{
+				{
+					Name:        "first",
+					SourceType:  FieldArgumentSource,
+					Constraints: &ArgumentConstraints{Min: &minFirst, Max: &maxFirst},
+				},
+				{
+					Name:        "search",
+					SourceType:  FieldArgumentSource,
+					Constraints: &ArgumentConstraints{MaxLength: &maxLength},
+				},
+				{
+					Name:        "ids",
+					SourceType:  FieldArgumentSource,
+					Constraints: &ArgumentConstraints{MaxListSize: &maxListSize},
+				},
+			},
+		},
+		{
+			TypeName:  "User",
+			FieldName: "friends",
+			Arguments: ArgumentsConfigurations{
+				{
+					Name:        "first",
+					SourceType:  FieldArgumentSource,
+					Constraints: &ArgumentConstraints{Max: &maxFirst},
+				},
+			},
+		},
+	}
+
+	run := func(operation, variables string) *operationreport.Report {
+		def := unsafeparser.ParseGraphqlDocumentString(definition)
+		require.NoError(t, asttransform.MergeDefinitionWithBaseSchema(&def))
+		op := unsafeparser.ParseGraphqlDocumentString(operation)
+		op.Input.Variables = []byte(variables)
+
+		report := &operationreport.Report{}
+		astnormalization.NewNormalizer(true, true).NormalizeOperation(&op, &def, report)
+		require.False(t, report.HasErrors())
+
+		ValidateArgumentConstraints(&op, &def, op.Input.Variables, fields, report)
+		return report
+	}
+
+	t.Run("valid arguments", func(t *testing.T) {
+		report := run(`query Users($first: Int) { users(first: $first, search: "abc", ids: ["1","2"]) { id } }`, `{"first":100}`)
+		assert.False(t, report.HasErrors())
+	})
+
+	t.Run("omitted arguments are not validated", func(t *testing.T) {
+		report := run(`{ users { id } }`, `{}`)
+		assert.False(t, report.HasErrors())
+	})
+
+	t.Run("int greater than max", func(t *testing.T) {
+		report := run(`query Users($first: Int) { users(first: $first) { id } }`, `{"first":1000000}`)
+		require.Len(t, report.ExternalErrors, 1)
+		assert.Equal(t, `Argument "first" on field "Query.users" must not be greater than 100, found: 1000000.`, report.ExternalErrors[0].Message)
+	})
+
+	t.Run("inline int less than min", func(t *testing.T) {
+		report := run(`{ users(first: 0) { id } }`, `{}`)
+		require.Len(t, report.ExternalErrors, 1)
+		assert.Equal(t, `Argument "first" on field "Query.users" must not be less than 1, found: 0.`, report.ExternalErrors[0].Message)
+	})
+
+	t.Run("string longer than max length", func(t *testing.T) {
+		report := run(`{ users(search: "abcdef") { id } }`, `{}`)
+		require.Len(t, report.ExternalErrors, 1)
+		assert.Equal(t, `Argument "search" on field "Query.users" must not be longer than 5 characters, found: 6.`, report.ExternalErrors[0].Message)
+	})
+
+	t.Run("list exceeding max list size", func(t *testing.T) {
+		report := run(`query Users($ids: [ID!]) { users(ids: $ids) { id } }`, `{"ids":["1","2","3"]}`)
+		require.Len(t, report.ExternalErrors, 1)
+		assert.Equal(t, `Argument "ids" on field "Query.users" must not contain more than 2 items, found: 3.`, report.ExternalErrors[0].Message)
+	})
+
+	t.Run("nested field", func(t *testing.T) {
+		report := run(`{ users { friends(first: 101) { id } } }`, `{}`)
+		require.Len(t, report.ExternalErrors, 1)
+		assert.Equal(t, `Argument "first" on field "User.friends" must not be greater than 100, found: 101.`, report.ExternalErrors[0].Message)
+	})
+}
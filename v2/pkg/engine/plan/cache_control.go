@@ -0,0 +1,116 @@
+package plan
+
+import (
+	"math"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+// CacheControlDirectiveName is the name of the directive hinting the cache policy of fields and types
+// The cache policy of query responses is only computed if the schema defines the directive
+//
+//	enum CacheControlScope { PUBLIC PRIVATE }
+//	directive @cacheControl(maxAge: Int, scope: CacheControlScope, inheritMaxAge: Boolean) on FIELD_DEFINITION | OBJECT | INTERFACE | UNION
+const CacheControlDirectiveName = "cacheControl"
+
+const (
+	cacheControlMaxAgeArgumentName        = "maxAge"
+	cacheControlScopeArgumentName         = "scope"
+	cacheControlInheritMaxAgeArgumentName = "inheritMaxAge"
+	cacheControlScopePrivate              = "PRIVATE"
+
+	// unrestrictedMaxAge is the max age of a response before the first field restricts it
+	unrestrictedMaxAge = math.MaxInt32
+)
+
+type cacheControlHint struct {
+	maxAge        int
+	hasMaxAge     bool
+	private       bool
+	inheritMaxAge bool
+}
+
+func (v *Visitor) initCacheControl(response *resolve.GraphQLResponse, operationKind ast.OperationType) {
+	if operationKind != ast.OperationTypeQuery {
+		return
+	}
+	if _, ok := v.Definition.DirectiveDefinitionByName(CacheControlDirectiveName); !ok {
+		return
+	}
+	response.CacheControl = &resolve.CacheControl{
+		MaxAge: unrestrictedMaxAge,
+	}
+}
+
+// collectCacheControl restricts the cache policy of the response by the policy of the field
+// The max age of a field is hinted on the field definition or on its returned type.
+// Without a hint root fields and fields returning composite types have a max age of 0,
+// other fields and fields with inheritMaxAge inherit the max age of their parent.
+func (v *Visitor) collectCacheControl(fieldRef, fieldDefinitionRef int) {
+	response := v.response()
+	if response == nil || response.CacheControl == nil {
+		return
+	}
+
+	hint := v.cacheControlHint(v.Definition.FieldDefinitions[fieldDefinitionRef].Directives.Refs)
+	typeNode := v.Definition.FieldDefinitionTypeNode(fieldDefinitionRef)
+	isComposite := typeNode.Kind == ast.NodeKindObjectTypeDefinition ||
+		typeNode.Kind == ast.NodeKindInterfaceTypeDefinition ||
+		typeNode.Kind == ast.NodeKindUnionTypeDefinition
+	if isComposite && !hint.hasMaxAge && !hint.inheritMaxAge {
+		typeHint := v.cacheControlHint(v.Definition.NodeDirectives(typeNode))
+		hint.maxAge, hint.hasMaxAge = typeHint.maxAge, typeHint.hasMaxAge
+		hint.private = hint.private || typeHint.private
+	}
+
+	maxAge := unrestrictedMaxAge
+	switch {
+	case hint.hasMaxAge:
+		maxAge = hint.maxAge
+	case hint.inheritMaxAge:
+	case isComposite || v.isRootField():
+		maxAge = 0
+	}
+
+	scope := resolve.CacheControlScopePublic
+	if hint.private {
+		scope = resolve.CacheControlScopePrivate
+	}
+	response.CacheControl.Restrict(maxAge, scope)
+}
+
+func (v *Visitor) isRootField() bool {
+	return v.Walker.EnclosingTypeDefinition.Kind == ast.NodeKindObjectTypeDefinition &&
+		v.Definition.Index.IsRootOperationTypeNameString(v.Walker.EnclosingTypeDefinition.NameString(v.Definition))
+}
+
+func (v *Visitor) cacheControlHint(directiveRefs []int) (hint cacheControlHint) {
+	for _, directiveRef := range directiveRefs {
+		if v.Definition.DirectiveNameString(directiveRef) != CacheControlDirectiveName {
+			continue
+		}
+		if value, ok := v.Definition.DirectiveArgumentValueByName(directiveRef, []byte(cacheControlMaxAgeArgumentName)); ok && value.Kind == ast.ValueKindInteger {
+			hint.maxAge = int(v.Definition.IntValueAsInt(value.Ref))
+			hint.hasMaxAge = true
+		}
+		if value, ok := v.Definition.DirectiveArgumentValueByName(directiveRef, []byte(cacheControlScopeArgumentName)); ok && value.Kind == ast.ValueKindEnum {
+			hint.private = v.Definition.EnumValueNameString(value.Ref) == cacheControlScopePrivate
+		}
+		if value, ok := v.Definition.DirectiveArgumentValueByName(directiveRef, []byte(cacheControlInheritMaxAgeArgumentName)); ok && value.Kind == ast.ValueKindBoolean {
+			hint.inheritMaxAge = bool(v.Definition.BooleanValue(value.Ref))
+		}
+	}
+	return hint
+}
+
+// finishCacheControl marks responses without any restricting field as uncacheable
+func (v *Visitor) finishCacheControl() {
+	response := v.response()
+	if response == nil || response.CacheControl == nil {
+		return
+	}
+	if response.CacheControl.MaxAge == unrestrictedMaxAge {
+		response.CacheControl.MaxAge = 0
+	}
+}
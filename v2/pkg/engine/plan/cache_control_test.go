@@ -0,0 +1,125 @@
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astnormalization"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+func TestPlanner_CacheControl(t *testing.T) {
+	const cacheControlDefinition = `
+		enum CacheControlScope { PUBLIC PRIVATE }
+		directive @cacheControl(maxAge: Int, scope: CacheControlScope, inheritMaxAge: Boolean) on FIELD_DEFINITION | OBJECT | INTERFACE | UNION
+	`
+	const types = `
+		type Query {
+			products: [Product] @cacheControl(maxAge: 60)
+			product: Product
+			me: User @cacheControl(maxAge: 30, scope: PRIVATE)
+			version: String
+			cachedVersion: String @cacheControl(maxAge: 300)
+		}
+
+		type Mutation {
+			addProduct: Product @cacheControl(maxAge: 60)
+		}
+
+		type Product @cacheControl(maxAge: 120) {
+			name: String
+			price: Int @cacheControl(maxAge: 10)
+			reviews: [Review]
+			related: [Product] @cacheControl(inheritMaxAge: true)
+		}
+
+		type Review {
+			body: String
+		}
+
+		type User {
+			name: String
+		}
+	`
+
+	plan := func(t *testing.T, schema, query string) resolve.GraphQLResponse {
+		t.Helper()
+		definition := unsafeparser.ParseGraphqlDocumentString(schema)
+		require.NoError(t, asttransform.MergeDefinitionWithBaseSchema(&definition))
+
+		operation := unsafeparser.ParseGraphqlDocumentString(query)
+		report := &operationreport.Report{}
+		astnormalization.NewNormalizer(true, true).NormalizeOperation(&operation, &definition, report)
+		require.False(t, report.HasErrors(), report.Error())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		dataSource := dsb().
+			Schema(schema).
+			RootNode("Query", "products", "product", "me", "version", "cachedVersion").
+			RootNode("Mutation", "addProduct").
+			ChildNode("Product", "name", "price", "reviews", "related").
+			ChildNode("Review", "body").
+			ChildNode("User", "name").
+			DS()
+		dataSource.Factory = &FakeFactory{upstreamSchema: &definition}
+
+		planner := NewPlanner(ctx, Configuration{
+			DataSources:                  []DataSourceConfiguration{dataSource},
+			DisableResolveFieldPositions: true,
+		})
+		actualPlan := planner.Plan(&operation, &definition, "", report)
+		require.False(t, report.HasErrors(), report.Error())
+
+		syncPlan, ok := actualPlan.(*SynchronousResponsePlan)
+		require.True(t, ok)
+		return *syncPlan.Response
+	}
+
+	query := func(t *testing.T, query string) *resolve.CacheControl {
+		return plan(t, cacheControlDefinition+types, query).CacheControl
+	}
+
+	t.Run("minimum max age of all fields", func(t *testing.T) {
+		assert.Equal(t, &resolve.CacheControl{MaxAge: 60}, query(t, `{ products { name } }`))
+		assert.Equal(t, &resolve.CacheControl{MaxAge: 10}, query(t, `{ products { name price } }`))
+		assert.Equal(t, &resolve.CacheControl{MaxAge: 60}, query(t, `{ products { name } cachedVersion }`))
+	})
+
+	t.Run("max age of the returned type", func(t *testing.T) {
+		assert.Equal(t, &resolve.CacheControl{MaxAge: 120}, query(t, `{ product { name } }`))
+	})
+
+	t.Run("root fields and composite fields without hint are not cacheable", func(t *testing.T) {
+		assert.Equal(t, &resolve.CacheControl{MaxAge: 0}, query(t, `{ version }`))
+		assert.Equal(t, &resolve.CacheControl{MaxAge: 0}, query(t, `{ products { reviews { body } } }`))
+	})
+
+	t.Run("inherited max age", func(t *testing.T) {
+		assert.Equal(t, &resolve.CacheControl{MaxAge: 60}, query(t, `{ products { related { name } } }`))
+	})
+
+	t.Run("private scope", func(t *testing.T) {
+		assert.Equal(t, &resolve.CacheControl{MaxAge: 30, Scope: resolve.CacheControlScopePrivate}, query(t, `{ me { name } products { name } }`))
+	})
+
+	t.Run("mutations are not cached", func(t *testing.T) {
+		assert.Nil(t, query(t, `mutation { addProduct { name } }`))
+	})
+
+	t.Run("without directive definition", func(t *testing.T) {
+		schema := `
+			type Query {
+				version: String
+			}
+		`
+		assert.Nil(t, plan(t, schema, `{ version }`).CacheControl)
+	})
+}
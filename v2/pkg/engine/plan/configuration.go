@@ -1,6 +1,8 @@
 package plan
 
 import (
+	"encoding/json"
+
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
 )
 
@@ -21,6 +23,13 @@ type Configuration struct {
 	// e.g. the origin of a field, possible types, etc.
 	// This information is required to compute the schema usage info from a plan
 	IncludeInfo bool
+	// ListPostProcessing enables gateway executed directives to filter and sort list fields
+	ListPostProcessing ListPostProcessingConfiguration
+	// DataSourceDirective enables an executable directive to pin fields to a datasource
+	DataSourceDirective DataSourceDirectiveConfiguration
+	// NodeSuggestionScorer selects the datasource of fields which are resolvable by multiple datasources,
+	// when the datasource can't be derived from the surrounding fields, e.g. to bias planning toward cheaper datasources
+	NodeSuggestionScorer NodeSuggestionScorer
 }
 
 type DebugConfiguration struct {
@@ -29,6 +38,8 @@ type DebugConfiguration struct {
 	PrintPlanningPaths            bool
 	PrintQueryPlans               bool
 	PrintNodeSuggestions          bool
+	// RedactValues replaces the literals of printed operations and query plans with placeholders
+	RedactValues bool
 
 	ConfigurationVisitor bool
 	PlanningVisitor      bool
@@ -95,6 +106,17 @@ type FieldConfiguration struct {
 	UnescapeResponseJson bool
 	// HasAuthorizationRule needs to be set to true if the Authorizer should be called for this field
 	HasAuthorizationRule bool
+	// Pagination enables slicing of a Relay-style connection in the resolver
+	// This is useful when the origin ignores the pagination arguments and always returns all edges
+	Pagination *PaginationConfiguration
+	// PII tags the field as personally identifiable information, alternatively the field definition can use the @pii directive
+	PII bool
+	// EncodeValue encodes the value of the field with the resolve.FieldValueTransformer of the request
+	// e.g. to sign urls or to encrypt ids into opaque cursors
+	EncodeValue bool
+	// Throttle limits the delivery frequency of a subscription root field for every subscriber,
+	// alternatively the field definition can use the @throttle directive
+	Throttle *resolve.SubscriptionThrottle
 }
 
 type ArgumentsConfigurations []ArgumentConfiguration
@@ -132,4 +154,31 @@ type ArgumentConfiguration struct {
 	SourcePath   []string
 	RenderConfig ArgumentRenderConfig
 	RenameTypeTo string
+	// DefaultValue is the JSON encoded value which will be sent to the upstream when the client omits the argument
+	// The argument only needs to exist in the upstream schema, this way the gateway is able to enforce values
+	// which are not part of the downstream schema, e.g. a limit of 100 items
+	DefaultValue json.RawMessage
+	// Constraints limit the values a client is allowed to pass for the argument
+	// Constraints are validated before planning, see ValidateArgumentConstraints
+	Constraints *ArgumentConstraints
+	// PII tags the argument as personally identifiable information, alternatively the argument definition can use the @pii directive
+	// The values of PII arguments are redacted from traces
+	PII bool
+	// DecodeValue decodes the value of the argument with the resolve.FieldValueTransformer of the request,
+	// so clients can pass back values of fields with EncodeValue
+	DecodeValue bool
+}
+
+// ArgumentConstraints defines the rules an argument value has to satisfy
+// All rules are optional, a nil value disables the rule
+// This allows to prevent abusing e.g. pagination arguments to request a million items
+type ArgumentConstraints struct {
+	// Min is the minimum allowed value of an Int or Float argument
+	Min *float64
+	// Max is the maximum allowed value of an Int or Float argument
+	Max *float64
+	// MaxLength is the maximum allowed number of characters of a String argument
+	MaxLength *int
+	// MaxListSize is the maximum allowed number of items of a list argument
+	MaxListSize *int
 }
@@ -38,6 +38,7 @@ type configurationVisitor struct {
 	handledRequires              map[int]struct{}                  // handledRequires is a map[FieldRef] of already processed fields which has @requires directive
 	visitedFields                map[int]struct{}                  // visitedFields is a map[FieldRef] of already processed fields which we check for abstract type, e.g. union or interface
 	fieldDependenciesForPlanners map[int][]int                     // fieldDependenciesForPlanners is a map[FieldRef][]plannerIdx holds dependencies between fields and planners
+	lastMutationRootPlannerIdx   int                               // lastMutationRootPlannerIdx is the planner of the previous top level mutation field, used to execute mutations serially
 
 	secondaryRun        bool // secondaryRun is a flag to indicate that we're running the planner not the first time
 	hasNewFields        bool // hasNewFields is used to determine if we need to run the planner again. It will be true in case required fields were added
@@ -69,17 +70,22 @@ func (p *missingPath) String() string {
 }
 
 type objectFetchConfiguration struct {
-	object             *resolve.Object
-	trigger            *resolve.GraphQLSubscriptionTrigger
-	planner            DataSourcePlanner
-	isSubscription     bool
-	fieldRef           int
-	fieldDefinitionRef int
-	sourceID           string
-	fetchID            int
-	dependsOnFetchIDs  []int
-	rootFields         []resolve.GraphCoordinate
-	operationType      ast.OperationType
+	object                *resolve.Object
+	trigger               *resolve.GraphQLSubscriptionTrigger
+	planner               DataSourcePlanner
+	isSubscription        bool
+	fieldRef              int
+	fieldDefinitionRef    int
+	sourceID              string
+	fetchID               int
+	dependsOnFetchIDs     []int
+	rootFields            []resolve.GraphCoordinate
+	entityKeys            []resolve.EntityKey
+	operationType         ast.OperationType
+	invalidUTF8Policy     resolve.InvalidUTF8Policy
+	extensionsPassthrough *resolve.ExtensionsPassthrough
+
+	scalarTransformations []scalarTransformationField
 }
 
 func (c *configurationVisitor) currentSelectionSet() int {
@@ -236,6 +242,7 @@ func (c *configurationVisitor) debugPrint(args ...any) {
 
 func (c *configurationVisitor) EnterDocument(operation, definition *ast.Document) {
 	c.hasNewFields = false
+	c.lastMutationRootPlannerIdx = -1
 
 	if c.selectionSetRefs == nil {
 		c.selectionSetRefs = make([]int, 0, 8)
@@ -372,6 +379,7 @@ func (c *configurationVisitor) EnterField(ref int) {
 		c.handleRequirements(plannerIdx, parentPath, typeName, fieldName, ref)
 		c.rewriteSelectionSetOfFieldWithInterfaceType(ref, plannerIdx)
 		c.addPlannerDependencies(ref, plannerIdx)
+		c.addMutationRootFieldDependency(plannerIdx, precedingParentPath)
 		c.addRootField(ref, plannerIdx)
 		return
 	}
@@ -379,6 +387,28 @@ func (c *configurationVisitor) EnterField(ref int) {
 	c.handleMissingPath(typeName, fieldName, currentPath)
 }
 
+// isMutationRootField returns true if the field is a top level mutation field, which must be executed serially
+func (c *configurationVisitor) isMutationRootField(precedingParentPath string) bool {
+	return !c.secondaryRun && precedingParentPath == "mutation"
+}
+
+// addMutationRootFieldDependency makes the fetch of a top level mutation field depend on the fetch of the previous one,
+// so that the fetches are executed serially in the order of the fields
+func (c *configurationVisitor) addMutationRootFieldDependency(plannerIdx int, precedingParentPath string) {
+	if !c.isMutationRootField(precedingParentPath) {
+		return
+	}
+	previousPlannerIdx := c.lastMutationRootPlannerIdx
+	c.lastMutationRootPlannerIdx = plannerIdx
+	if previousPlannerIdx == -1 || previousPlannerIdx == plannerIdx {
+		return
+	}
+	fetchConfiguration := &c.planners[plannerIdx].objectFetchConfiguration
+	if !slices.Contains(fetchConfiguration.dependsOnFetchIDs, previousPlannerIdx) {
+		fetchConfiguration.dependsOnFetchIDs = append(fetchConfiguration.dependsOnFetchIDs, previousPlannerIdx)
+	}
+}
+
 func (c *configurationVisitor) addRootField(fieldRef, plannerIdx int) {
 
 	if c.fieldIsChildNode(plannerIdx) {
@@ -522,6 +552,11 @@ func (c *configurationVisitor) planWithExistingPlanners(ref int, typeName, field
 	dsHashes := c.nodeSuggestions.SuggestionsForPath(typeName, fieldName, currentPath)
 
 	for plannerIdx, plannerConfig := range c.planners {
+		if c.isMutationRootField(precedingParentPath) && plannerIdx != c.lastMutationRootPlannerIdx {
+			// a top level mutation field can only be merged into the fetch of the previous top level mutation field
+			// otherwise the mutations would not be executed in the order of the fields
+			continue
+		}
 		planningBehaviour := plannerConfig.planner.DataSourcePlanningBehavior()
 		currentPlannerDSHash := plannerConfig.dataSourceConfiguration.Hash()
 		_, isProvided := plannerConfig.providedFields.HasSuggestionForPath(typeName, fieldName, currentPath)
@@ -776,13 +811,15 @@ func (c *configurationVisitor) addNewPlanner(ref int, typeName, fieldName, curre
 	fetchID := len(c.planners)
 
 	fetchConfiguration := objectFetchConfiguration{
-		planner:            planner,
-		isSubscription:     isSubscription,
-		fieldRef:           ref,
-		fieldDefinitionRef: fieldDefinition,
-		fetchID:            fetchID,
-		sourceID:           config.ID,
-		operationType:      c.resolveRootFieldOperationType(typeName),
+		planner:               planner,
+		isSubscription:        isSubscription,
+		fieldRef:              ref,
+		fieldDefinitionRef:    fieldDefinition,
+		fetchID:               fetchID,
+		sourceID:              config.ID,
+		operationType:         c.resolveRootFieldOperationType(typeName),
+		invalidUTF8Policy:     config.InvalidUTF8Policy,
+		extensionsPassthrough: config.extensionsPassthrough(),
 	}
 
 	plannerConfig := &plannerConfiguration{
@@ -1025,6 +1062,7 @@ func (c *configurationVisitor) handleFieldsRequiredByKey(plannerIdx int, config
 
 		requiredFieldsConfiguration, planned := c.planKeyRequiredFields(plannerIdx, typeName, parentPath, requiredFieldsForType, isInterfaceObject)
 		if planned {
+			c.addEntityKey(plannerIdx, requiredFieldsConfiguration)
 			var added bool
 			config.requiredFields, added = appendRequiredFieldsConfigurationIfNotPresent(config.requiredFields, requiredFieldsConfiguration)
 			if added {
@@ -1034,6 +1072,18 @@ func (c *configurationVisitor) handleFieldsRequiredByKey(plannerIdx int, config
 	}
 }
 
+// addEntityKey - records the key chosen to fetch the entity, so it's exposed in the fetch info
+func (c *configurationVisitor) addEntityKey(plannerIdx int, keyConfiguration FederationFieldConfiguration) {
+	key := resolve.EntityKey{
+		TypeName:     keyConfiguration.TypeName,
+		SelectionSet: keyConfiguration.SelectionSet,
+	}
+	if slices.Contains(c.planners[plannerIdx].objectFetchConfiguration.entityKeys, key) {
+		return
+	}
+	c.planners[plannerIdx].objectFetchConfiguration.entityKeys = append(c.planners[plannerIdx].objectFetchConfiguration.entityKeys, key)
+}
+
 // couldHandleFieldsRequiredByKey - checks wether we could plan the field now according to it's key requirements
 // if no existing planners datasources could provide us with the required fields we should postpone planning of the field
 func (c *configurationVisitor) couldHandleFieldsRequiredByKey(dsConfig DataSourceConfiguration, typeName string, parentPath string) bool {
@@ -1058,7 +1108,26 @@ func (c *configurationVisitor) couldHandleFieldsRequiredByKey(dsConfig DataSourc
 		}
 	}
 
-	return false
+	// fallback to the keys which are not shared, but could be selected from the parent
+	_, _, found := c.findKeyResolvableByParentPlanner(-1, typeName, parentPath, possibleRequiredFields)
+	return found
+}
+
+// findKeyResolvableByParentPlanner - returns the first key which fields could be resolved by a planner on the parent path
+// it's a fallback for the keys which are not declared by the datasource of the parent planner,
+// the key fields are added to the parent fetch, so the entity could be fetched by the alternate key
+func (c *configurationVisitor) findKeyResolvableByParentPlanner(currentPlannerIdx int, typeName string, parentPath string, possibleRequiredFields []FederationFieldConfiguration) (providedByPlannerIdx int, config FederationFieldConfiguration, found bool) {
+	for i := range c.planners {
+		if i == currentPlannerIdx || !c.planners[i].hasPath(parentPath) {
+			continue
+		}
+		for _, possibleRequiredFieldConfig := range possibleRequiredFields {
+			if canResolveSelectionSet(c.definition, &c.planners[i].dataSourceConfiguration, typeName, possibleRequiredFieldConfig.SelectionSet) {
+				return i, possibleRequiredFieldConfig, true
+			}
+		}
+	}
+	return -1, FederationFieldConfiguration{}, false
 }
 
 func (c *configurationVisitor) planKeyRequiredFields(currentPlannerIdx int, typeName string, parentPath string, possibleRequiredFields []FederationFieldConfiguration, forInterfaceObject bool) (config FederationFieldConfiguration, planned bool) {
@@ -1093,6 +1162,14 @@ func (c *configurationVisitor) planKeyRequiredFields(currentPlannerIdx int, type
 		}
 	}
 
+	// no planner on the parent path shares a key with the current datasource,
+	// so we fall back to a key which fields could be added to the fetch of the parent planner
+	providedByPlannerIdx, config, found := c.findKeyResolvableByParentPlanner(currentPlannerIdx, typeName, parentPath, possibleRequiredFields)
+	if found {
+		c.planAddingRequiredFields(currentPlannerIdx, providedByPlannerIdx, config, false)
+		return config, true
+	}
+
 	return FederationFieldConfiguration{}, false
 }
 
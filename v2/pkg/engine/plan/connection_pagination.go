@@ -0,0 +1,93 @@
+package plan
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+// PaginationConfiguration configures the resolver to slice the edges of a Relay-style connection field
+// All names are optional and default to the names defined by the Relay connection spec
+type PaginationConfiguration struct {
+	// FirstArgumentName is the name of the argument limiting the number of edges, defaults to "first"
+	FirstArgumentName string
+	// AfterArgumentName is the name of the argument containing the cursor to start after, defaults to "after"
+	AfterArgumentName string
+	// DefaultFirst limits the number of edges when the client omits the first argument, 0 means no limit
+	DefaultFirst int
+	// EdgesFieldName defaults to "edges"
+	EdgesFieldName string
+	// CursorFieldName defaults to "cursor"
+	CursorFieldName string
+	// PageInfoFieldName defaults to "pageInfo"
+	PageInfoFieldName string
+}
+
+func (c *PaginationConfiguration) nameOrDefault(name, defaultName string) string {
+	if name != "" {
+		return name
+	}
+	return defaultName
+}
+
+// resolveConnectionPagination maps the pagination configuration of a connection field
+// to the names of the variables and response fields used at runtime
+func (v *Visitor) resolveConnectionPagination(fieldRef int, config *PaginationConfiguration) *resolve.ConnectionPagination {
+	selectionSet, ok := v.Operation.FieldSelectionSet(fieldRef)
+	if !ok {
+		return nil
+	}
+
+	pagination := &resolve.ConnectionPagination{
+		FirstVariableName: v.argumentVariableName(fieldRef, config.nameOrDefault(config.FirstArgumentName, "first")),
+		AfterVariableName: v.argumentVariableName(fieldRef, config.nameOrDefault(config.AfterArgumentName, "after")),
+		DefaultFirst:      config.DefaultFirst,
+	}
+
+	edges, ok := v.selectionFieldByName(selectionSet, config.nameOrDefault(config.EdgesFieldName, "edges"))
+	if ok {
+		pagination.EdgesFieldName = v.Operation.FieldAliasOrNameString(edges)
+		if edgesSelectionSet, ok := v.Operation.FieldSelectionSet(edges); ok {
+			if cursor, ok := v.selectionFieldByName(edgesSelectionSet, config.nameOrDefault(config.CursorFieldName, "cursor")); ok {
+				pagination.CursorFieldName = v.Operation.FieldAliasOrNameString(cursor)
+			}
+		}
+	}
+
+	pageInfo, ok := v.selectionFieldByName(selectionSet, config.nameOrDefault(config.PageInfoFieldName, "pageInfo"))
+	if ok {
+		pagination.PageInfoFieldName = v.Operation.FieldAliasOrNameString(pageInfo)
+		if pageInfoSelectionSet, ok := v.Operation.FieldSelectionSet(pageInfo); ok {
+			pagination.HasNextPageFieldName = v.selectionResponseName(pageInfoSelectionSet, "hasNextPage")
+			pagination.HasPreviousPageFieldName = v.selectionResponseName(pageInfoSelectionSet, "hasPreviousPage")
+			pagination.StartCursorFieldName = v.selectionResponseName(pageInfoSelectionSet, "startCursor")
+			pagination.EndCursorFieldName = v.selectionResponseName(pageInfoSelectionSet, "endCursor")
+		}
+	}
+
+	return pagination
+}
+
+func (v *Visitor) argumentVariableName(fieldRef int, argumentName string) string {
+	argument, ok := v.Operation.FieldArgument(fieldRef, []byte(argumentName))
+	if !ok {
+		return ""
+	}
+	value := v.Operation.ArgumentValue(argument)
+	if value.Kind != ast.ValueKindVariable {
+		return ""
+	}
+	return v.Operation.VariableValueNameString(value.Ref)
+}
+
+func (v *Visitor) selectionFieldByName(selectionSet int, fieldName string) (fieldRef int, ok bool) {
+	ok, fieldRef = v.Operation.SelectionSetHasFieldSelectionWithExactName(selectionSet, []byte(fieldName))
+	return fieldRef, ok
+}
+
+func (v *Visitor) selectionResponseName(selectionSet int, fieldName string) string {
+	fieldRef, ok := v.selectionFieldByName(selectionSet, fieldName)
+	if !ok {
+		return ""
+	}
+	return v.Operation.FieldAliasOrNameString(fieldRef)
+}
@@ -38,10 +38,33 @@ type DataSourceConfiguration struct {
 	Custom     json.RawMessage
 
 	FederationMetaData FederationMetaData
+	// ScalarTransformers transform scalar values of the responses before they are merged
+	ScalarTransformers ScalarTransformerConfigurations
+	// InvalidUTF8Policy defines how responses with invalid UTF-8 or control characters are handled
+	InvalidUTF8Policy resolve.InvalidUTF8Policy
+	// ExtensionsPassthrough selects keys of the extensions of the responses which are passed through to the client
+	// the Namespace defaults to the ID of the DataSource
+	ExtensionsPassthrough *resolve.ExtensionsPassthrough
+	// Priority breaks ties when multiple datasources are able to resolve the same field,
+	// the datasource with the highest priority is selected instead of the first one in the order of the configuration
+	Priority int
 
 	hash DSHash
 }
 
+func (d *DataSourceConfiguration) extensionsPassthrough() *resolve.ExtensionsPassthrough {
+	if d.ExtensionsPassthrough == nil || len(d.ExtensionsPassthrough.Keys) == 0 {
+		return nil
+	}
+	if d.ExtensionsPassthrough.Namespace != "" {
+		return d.ExtensionsPassthrough
+	}
+	return &resolve.ExtensionsPassthrough{
+		Namespace: d.ID,
+		Keys:      d.ExtensionsPassthrough.Keys,
+	}
+}
+
 func (d *DataSourceConfiguration) Hash() DSHash {
 	if d.hash != 0 {
 		return d.hash
@@ -56,6 +79,8 @@ type DataSourcePlannerConfiguration struct {
 	ParentPath     string
 	PathType       PlannerPathType
 	IsNested       bool
+	// RedactDebugValues instructs the planner to replace literals with placeholders when printing query plans
+	RedactDebugValues bool
 }
 
 type PlannerPathType int
@@ -188,4 +213,13 @@ type SubscriptionConfiguration struct {
 	Variables      resolve.Variables
 	DataSource     resolve.SubscriptionDataSource
 	PostProcessing resolve.PostProcessingConfiguration
+	// InitialFetch optionally loads the current state for every new subscriber before the updates are sent
+	InitialFetch *SubscriptionInitialFetchConfiguration
+}
+
+// SubscriptionInitialFetchConfiguration is the fetch of the current state of a subscription
+// The Input can use the same Variables as the subscription input
+type SubscriptionInitialFetchConfiguration struct {
+	Input      string
+	DataSource resolve.DataSource
 }
@@ -0,0 +1,138 @@
+package plan
+
+import (
+	"fmt"
+
+	"github.com/buger/jsonparser"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// DataSourceDirectiveConfiguration enables an executable directive to pin fields to a datasource, e.g. in debug scenarios
+// The directive has to be defined in the schema, with the default name:
+//
+//	directive @datasource(id: String!) on FIELD
+//
+// The id has to be a string literal of the ID of a datasource which is able to resolve the field
+// The directive is never sent to the upstream
+type DataSourceDirectiveConfiguration struct {
+	Enabled bool
+	// DirectiveName defaults to "datasource"
+	DirectiveName string
+}
+
+func (c *DataSourceDirectiveConfiguration) directiveName() string {
+	if c.DirectiveName != "" {
+		return c.DirectiveName
+	}
+	return "datasource"
+}
+
+// IsDataSourceDirective returns true if the directive pins a field to a datasource
+// and must not be sent to the upstream
+func (c *DataSourceDirectiveConfiguration) IsDataSourceDirective(directiveName string) bool {
+	if !c.Enabled {
+		return false
+	}
+	return directiveName == c.directiveName()
+}
+
+const ReasonDataSourceDirective = "pinned by the datasource directive"
+
+// dataSourceDirectiveHints returns node suggestion hints for the fields pinned to a datasource by the datasource directive
+func (p *Planner) dataSourceDirectiveHints(operation, definition *ast.Document, report *operationreport.Report) []NodeSuggestionHint {
+	if !p.config.DataSourceDirective.Enabled {
+		return nil
+	}
+
+	walker := astvisitor.NewWalker(32)
+	visitor := &dataSourceDirectiveVisitor{
+		walker:      &walker,
+		operation:   operation,
+		definition:  definition,
+		config:      &p.config.DataSourceDirective,
+		dataSources: p.config.DataSources,
+	}
+	walker.RegisterEnterFieldVisitor(visitor)
+	walker.Walk(operation, definition, report)
+	if report.HasErrors() {
+		return nil
+	}
+	return visitor.hints
+}
+
+type dataSourceDirectiveVisitor struct {
+	walker                *astvisitor.Walker
+	operation, definition *ast.Document
+	config                *DataSourceDirectiveConfiguration
+	dataSources           []DataSourceConfiguration
+
+	hints []NodeSuggestionHint
+}
+
+func (v *dataSourceDirectiveVisitor) EnterField(ref int) {
+	directiveRef := -1
+	for _, directive := range v.operation.FieldDirectives(ref) {
+		if v.operation.DirectiveNameString(directive) == v.config.directiveName() {
+			directiveRef = directive
+			break
+		}
+	}
+	if directiveRef == -1 {
+		return
+	}
+
+	id, ok := v.dataSourceID(ref, directiveRef)
+	if !ok {
+		return
+	}
+
+	typeName := v.walker.EnclosingTypeDefinition.NameString(v.definition)
+	fieldName := v.operation.FieldNameString(ref)
+
+	for i := range v.dataSources {
+		if v.dataSources[i].ID != id {
+			continue
+		}
+		if !v.dataSources[i].HasRootNode(typeName, fieldName) && !v.dataSources[i].HasChildNode(typeName, fieldName) {
+			v.stopWithError(ref, directiveRef, fmt.Sprintf("the datasource %q can't resolve the field %s.%s", id, typeName, fieldName))
+			return
+		}
+		v.hints = append(v.hints, NodeSuggestionHint{
+			fieldRef:   ref,
+			dsHash:     v.dataSources[i].Hash(),
+			fieldName:  fieldName,
+			parentPath: v.walker.Path.DotDelimitedString(),
+			reason:     ReasonDataSourceDirective,
+		})
+		return
+	}
+	v.stopWithError(ref, directiveRef, fmt.Sprintf("unknown datasource %q", id))
+}
+
+func (v *dataSourceDirectiveVisitor) dataSourceID(fieldRef, directiveRef int) (id string, ok bool) {
+	value, ok := v.operation.DirectiveArgumentValueByName(directiveRef, []byte("id"))
+	if !ok || value.Kind != ast.ValueKindString {
+		// the plan is cached independently of the variables, so the datasource has to be known at planning time
+		v.stopWithError(fieldRef, directiveRef, "the id has to be a string literal")
+		return "", false
+	}
+	valueJson, err := v.operation.ValueToJSON(value)
+	if err != nil {
+		v.walker.StopWithInternalErr(err)
+		return "", false
+	}
+	id, err = jsonparser.ParseString(valueJson[1 : len(valueJson)-1])
+	if err != nil {
+		v.walker.StopWithInternalErr(err)
+		return "", false
+	}
+	return id, true
+}
+
+func (v *dataSourceDirectiveVisitor) stopWithError(fieldRef, directiveRef int, reason string) {
+	v.walker.StopWithExternalErr(operationreport.ErrInvalidDataSourceDirective(
+		v.operation.DirectiveNameBytes(directiveRef), v.operation.FieldAliasOrNameBytes(fieldRef), reason, v.operation.Directives[directiveRef].At))
+}
@@ -0,0 +1,69 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// benchmarkDataSourceSet creates a gateway of subgraphs which all extend the Product entity,
+// every subgraph resolves the shareable field name and one field unique to the subgraph
+func benchmarkDataSourceSet(subgraphs int) (definition, operation string, dataSources []DataSourceConfiguration) {
+	fieldNames := make([]string, subgraphs)
+	for i := range fieldNames {
+		fieldNames[i] = fmt.Sprintf("field%d", i)
+	}
+
+	definition = fmt.Sprintf(`
+		type Query {
+			products: [Product!]!
+		}
+		type Product {
+			upc: ID!
+			name: String!
+			%s: String!
+		}`, strings.Join(fieldNames, ": String!\n"))
+
+	operation = fmt.Sprintf(`query { products { upc name %s } }`, strings.Join(fieldNames, " "))
+
+	dataSources = make([]DataSourceConfiguration, 0, subgraphs)
+	for i := 0; i < subgraphs; i++ {
+		ds := dsb().Hash(DSHash(i+1)).Schema(fmt.Sprintf(`
+			type Product @key(fields: "upc") {
+				upc: ID!
+				name: String! @shareable
+				%s: String!
+			}`, fieldNames[i])).
+			RootNode("Product", "upc", "name", fieldNames[i]).
+			KeysMetadata(FederationFieldConfigurations{{TypeName: "Product", SelectionSet: "upc"}})
+		if i == 0 {
+			ds.RootNode("Query", "products")
+		}
+		dataSources = append(dataSources, ds.DS())
+	}
+	return definition, operation, dataSources
+}
+
+func BenchmarkFindBestDataSourceSet(b *testing.B) {
+	for _, subgraphs := range []int{10, 50, 100} {
+		b.Run(fmt.Sprintf("%d datasources", subgraphs), func(b *testing.B) {
+			rawDefinition, rawOperation, dataSources := benchmarkDataSourceSet(subgraphs)
+			definition := unsafeparser.ParseGraphqlDocumentStringWithBaseSchema(rawDefinition)
+			operation := unsafeparser.ParseGraphqlDocumentString(rawOperation)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				report := operationreport.Report{}
+				dsFilter := NewDataSourceFilter(&operation, &definition, &report)
+				_, _ = dsFilter.findBestDataSourceSet(dataSources, nil)
+				if report.HasErrors() {
+					b.Fatal(report.Error())
+				}
+			}
+		})
+	}
+}
@@ -59,7 +59,8 @@ func (f *collectNodesVisitor) EnterField(ref int) {
 
 	itemIds := make([]int, 0, 1)
 
-	for _, v := range f.dataSources {
+	for i := range f.dataSources {
+		v := &f.dataSources[i]
 		hasRootNode := v.HasRootNode(typeName, fieldName) || (isTypeName && v.HasRootNodeWithTypename(typeName))
 		hasChildNode := v.HasChildNode(typeName, fieldName) || (isTypeName && v.HasChildNodeWithTypename(typeName))
 
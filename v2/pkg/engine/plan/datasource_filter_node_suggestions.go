@@ -54,6 +54,8 @@ type NodeSuggestionHint struct {
 
 	fieldName  string
 	parentPath string
+	// reason of the selection, defaults to ReasonKeyRequirementProvidedByPlanner
+	reason string
 }
 
 type NodeSuggestions struct {
@@ -182,38 +184,45 @@ func (f *NodeSuggestions) duplicatesOf(idx int) (out []int) {
 	return
 }
 
+// childNodesOnSameSource returns the child nodes resolved by the same datasource as the node
+// the data of the tree nodes is filtered in place to avoid collecting the nodes of all datasources first
 func (f *NodeSuggestions) childNodesOnSameSource(idx int) (out []int) {
 	treeNode := f.treeNode(idx)
-	childIndexes := treeNodeChildren(treeNode)
+	dsHash := f.items[idx].DataSourceHash
 
-	out = make([]int, 0, len(childIndexes))
-
-	for _, childIdx := range childIndexes {
-		if f.items[childIdx].DataSourceHash != f.items[idx].DataSourceHash {
-			continue
-		}
-
-		out = append(out, childIdx)
+	for _, child := range treeNode.GetChildren() {
+		out = f.appendNodesOnSource(out, child.GetData(), dsHash)
 	}
 	return
 }
 
+// siblingNodesOnSameSource returns the sibling nodes resolved by the same datasource as the node
 func (f *NodeSuggestions) siblingNodesOnSameSource(idx int) (out []int) {
 	treeNode := f.treeNode(idx)
-	siblingIndexes := treeNodeSiblings(treeNode)
-
-	out = make([]int, 0, len(siblingIndexes))
+	childrenOfParent := treeNode.GetParent().GetChildren()
+	if len(childrenOfParent) < 2 {
+		return nil
+	}
+	dsHash := f.items[idx].DataSourceHash
 
-	for _, siblingIndex := range siblingIndexes {
-		if f.items[siblingIndex].DataSourceHash != f.items[idx].DataSourceHash {
+	for _, child := range childrenOfParent {
+		if child.GetID() == treeNode.GetID() {
 			continue
 		}
-
-		out = append(out, siblingIndex)
+		out = f.appendNodesOnSource(out, child.GetData(), dsHash)
 	}
 	return
 }
 
+func (f *NodeSuggestions) appendNodesOnSource(out []int, indexes []int, dsHash DSHash) []int {
+	for _, i := range indexes {
+		if f.items[i].DataSourceHash == dsHash {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
 func (f *NodeSuggestions) isLeaf(idx int) bool {
 	treeNode := f.treeNode(idx)
 
@@ -266,39 +275,3 @@ func isTreeNodeUniq(node treeNode) bool {
 func isTreeNodeLeaf(node treeNode) bool {
 	return len(node.GetChildren()) == 0
 }
-
-func treeNodeSiblings(node treeNode) []int {
-	childrenOfParent := node.GetParent().GetChildren()
-
-	if len(childrenOfParent) < 2 {
-		return nil
-	}
-
-	out := make([]int, 0, len(childrenOfParent))
-
-	for _, child := range childrenOfParent {
-		if child.GetID() == node.GetID() {
-			continue
-		}
-
-		out = append(out, child.GetData()...)
-	}
-
-	return out
-}
-
-func treeNodeChildren(node treeNode) []int {
-	children := node.GetChildren()
-
-	if len(children) == 0 {
-		return nil
-	}
-
-	out := make([]int, 0, len(children))
-
-	for _, child := range children {
-		out = append(out, child.GetData()...)
-	}
-
-	return out
-}
@@ -0,0 +1,81 @@
+package plan
+
+// NodeSuggestionScorer scores the datasources which are able to resolve a field,
+// when the datasource can't be derived from the selected parent, child or sibling fields
+// The datasource with the highest score is selected, on equal scores the priority and then the order of the datasources decides
+type NodeSuggestionScorer interface {
+	ScoreNodeSuggestion(candidate NodeSuggestionCandidate) float64
+}
+
+// NodeSuggestionCandidate is a datasource which is able to resolve a field of the operation
+type NodeSuggestionCandidate struct {
+	TypeName  string
+	FieldName string
+	Path      string
+	// IsRootNode is true if the field is a root node of the datasource, so the datasource owns the field
+	// otherwise the field is a child node which is only resolvable through its parent
+	IsRootNode bool
+	// DataSourceInUse is true if the datasource already resolves other fields of the operation
+	DataSourceInUse bool
+	DataSource      *DataSourceConfiguration
+}
+
+// WeightedNodeSuggestionScorer sums up the weights of the heuristics matching a candidate
+type WeightedNodeSuggestionScorer struct {
+	// DataSourceInUseWeight is added if the datasource already resolves other fields, which prefers plans with fewer fetches
+	DataSourceInUseWeight float64
+	// RootNodeWeight is added if the datasource owns the field
+	RootNodeWeight float64
+	// DataSourceWeights are added per ID of the datasource, e.g. negative weights bias planning away from expensive datasources
+	DataSourceWeights map[string]float64
+}
+
+func (s *WeightedNodeSuggestionScorer) ScoreNodeSuggestion(candidate NodeSuggestionCandidate) (score float64) {
+	if candidate.DataSourceInUse {
+		score += s.DataSourceInUseWeight
+	}
+	if candidate.IsRootNode {
+		score += s.RootNodeWeight
+	}
+	if candidate.DataSource != nil {
+		score += s.DataSourceWeights[candidate.DataSource.ID]
+	}
+	return score
+}
+
+const ReasonStage3SelectHighestScoredNode = "stage3: select node with the highest score"
+
+// selectHighestScoredNode selects the node or one of its duplicates with the highest score
+func (f *DataSourceFilter) selectHighestScoredNode(i int, duplicates []int) {
+	inUse := make(map[DSHash]struct{})
+	for _, item := range f.nodes.items {
+		if item.Selected {
+			inUse[item.DataSourceHash] = struct{}{}
+		}
+	}
+
+	best, bestScore, bestPriority := -1, 0.0, 0
+	for _, candidate := range append([]int{i}, duplicates...) {
+		node := f.nodes.items[candidate]
+		if node.LessPreferable {
+			continue
+		}
+		_, dataSourceInUse := inUse[node.DataSourceHash]
+		score := f.scorer.ScoreNodeSuggestion(NodeSuggestionCandidate{
+			TypeName:        node.TypeName,
+			FieldName:       node.FieldName,
+			Path:            node.Path,
+			IsRootNode:      node.IsRootNode,
+			DataSourceInUse: dataSourceInUse,
+			DataSource:      f.dataSources[node.DataSourceHash],
+		})
+		priority := f.nodePriority(candidate)
+		if best == -1 || score > bestScore || score == bestScore && priority > bestPriority {
+			best, bestScore, bestPriority = candidate, score, priority
+		}
+	}
+	if best == -1 {
+		return
+	}
+	f.nodes.items[best].selectWithReason(ReasonStage3SelectHighestScoredNode, f.enableSelectionReasons)
+}
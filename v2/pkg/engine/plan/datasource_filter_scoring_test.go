@@ -0,0 +1,91 @@
+package plan
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvalidation"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+func TestWeightedNodeSuggestionScorer(t *testing.T) {
+	scorer := &WeightedNodeSuggestionScorer{
+		DataSourceInUseWeight: 10,
+		RootNodeWeight:        5,
+		DataSourceWeights:     map[string]float64{"expensive": -20},
+	}
+
+	assert.Equal(t, 0.0, scorer.ScoreNodeSuggestion(NodeSuggestionCandidate{}))
+	assert.Equal(t, 15.0, scorer.ScoreNodeSuggestion(NodeSuggestionCandidate{DataSourceInUse: true, IsRootNode: true, DataSource: &DataSourceConfiguration{ID: "cheap"}}))
+	assert.Equal(t, -5.0, scorer.ScoreNodeSuggestion(NodeSuggestionCandidate{DataSourceInUse: true, IsRootNode: true, DataSource: &DataSourceConfiguration{ID: "expensive"}}))
+}
+
+func TestFindBestDataSourceSetWithScorer(t *testing.T) {
+	withID := func(ds DataSourceConfiguration, id string) DataSourceConfiguration {
+		ds.ID = id
+		return ds
+	}
+	dataSources := []DataSourceConfiguration{
+		withID(shareableDS1, "first"),
+		withID(shareableDS2, "second"),
+		withID(shareableDS3, "third"),
+	}
+
+	run := func(t *testing.T, dataSources []DataSourceConfiguration, scorer NodeSuggestionScorer) []*NodeSuggestion {
+		t.Helper()
+
+		definition := unsafeparser.ParseGraphqlDocumentStringWithBaseSchema(shareableDefinition)
+		operation := unsafeparser.ParseGraphqlDocumentString(`query { me { details { forename } } }`)
+		report := operationreport.Report{}
+
+		astvalidation.DefaultOperationValidator().Validate(&operation, &definition, &report)
+		if report.HasErrors() {
+			t.Fatal(report.Error())
+		}
+
+		dsFilter := NewDataSourceFilter(&operation, &definition, &report)
+		dsFilter.EnableSelectionReasons()
+		dsFilter.SetNodeSuggestionScorer(scorer)
+
+		planned, _ := dsFilter.findBestDataSourceSet(dataSources, nil)
+		if report.HasErrors() {
+			t.Fatal(report.Error())
+		}
+
+		for i := range planned.items {
+			planned.items[i].fieldRef = 0
+		}
+		return slices.DeleteFunc(planned.items, func(n *NodeSuggestion) bool {
+			return !n.Selected
+		})
+	}
+
+	expected := newNodeSuggestions([]NodeSuggestion{
+		{TypeName: "Query", FieldName: "me", DataSourceHash: 22, Path: "query.me", ParentPath: "query", IsRootNode: true, Selected: true, SelectionReasons: []string{ReasonStage3SelectHighestScoredNode}},
+		{TypeName: "User", FieldName: "details", DataSourceHash: 22, Path: "query.me.details", ParentPath: "query.me", IsRootNode: true, Selected: true, SelectionReasons: []string{ReasonStage2SameSourceNodeOfSelectedParent}},
+		{TypeName: "Details", FieldName: "forename", DataSourceHash: 22, Path: "query.me.details.forename", ParentPath: "query.me.details", IsRootNode: false, Selected: true, SelectionReasons: []string{ReasonStage2SameSourceNodeOfSelectedParent}},
+	}).items
+
+	t.Run("prefer datasource with higher weight regardless of the order", func(t *testing.T) {
+		scorer := &WeightedNodeSuggestionScorer{DataSourceWeights: map[string]float64{"second": 1}}
+
+		assert.Equal(t, expected, run(t, orderDS(dataSources, []int{0, 1, 2}), scorer))
+		assert.Equal(t, expected, run(t, orderDS(dataSources, []int{2, 0, 1}), scorer))
+	})
+
+	t.Run("prefer datasource with higher priority on equal scores", func(t *testing.T) {
+		prioritized := slices.Clone(dataSources)
+		prioritized[1].Priority = 1
+
+		assert.Equal(t, expected, run(t, prioritized, &WeightedNodeSuggestionScorer{}))
+	})
+
+	t.Run("avoid expensive datasource", func(t *testing.T) {
+		scorer := &WeightedNodeSuggestionScorer{DataSourceWeights: map[string]float64{"first": -1}}
+
+		assert.Equal(t, expected, run(t, orderDS(dataSources, []int{0, 1, 2}), scorer))
+	})
+}
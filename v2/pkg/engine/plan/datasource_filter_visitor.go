@@ -16,6 +16,8 @@ type DataSourceFilter struct {
 	nodes *NodeSuggestions
 
 	enableSelectionReasons bool
+	scorer                 NodeSuggestionScorer
+	dataSources            map[DSHash]*DataSourceConfiguration
 }
 
 func NewDataSourceFilter(operation, definition *ast.Document, report *operationreport.Report) *DataSourceFilter {
@@ -30,6 +32,12 @@ func (f *DataSourceFilter) EnableSelectionReasons() {
 	f.enableSelectionReasons = true
 }
 
+// SetNodeSuggestionScorer sets the scorer which selects the datasource of fields resolvable by multiple datasources,
+// without a scorer the first available datasource is selected
+func (f *DataSourceFilter) SetNodeSuggestionScorer(scorer NodeSuggestionScorer) {
+	f.scorer = scorer
+}
+
 func (f *DataSourceFilter) FilterDataSources(dataSources []DataSourceConfiguration, existingNodes *NodeSuggestions, hints ...NodeSuggestionHint) (used []DataSourceConfiguration, suggestions *NodeSuggestions) {
 	var dsInUse map[DSHash]struct{}
 
@@ -55,6 +63,11 @@ func (f *DataSourceFilter) findBestDataSourceSet(dataSources []DataSourceConfigu
 		return nil, nil
 	}
 
+	f.dataSources = make(map[DSHash]*DataSourceConfiguration, len(dataSources))
+	for i := range dataSources {
+		f.dataSources[dataSources[i].Hash()] = &dataSources[i]
+	}
+
 	// f.nodes.printNodes("initial nodes")
 
 	f.applySuggestionHints(hints)
@@ -119,7 +132,8 @@ const (
 	ReasonStage2SameSourceNodeOfSelectedChild   = "stage2: node on the same source as selected child"
 	ReasonStage2SameSourceNodeOfSelectedSibling = "stage2: node on the same source as selected sibling"
 
-	ReasonStage3SelectAvailableNode = "stage3: select first available node"
+	ReasonStage3SelectAvailableNode       = "stage3: select first available node"
+	ReasonStage3SelectHighestPriorityNode = "stage3: select node on the datasource with the highest priority"
 
 	ReasonKeyRequirementProvidedByPlanner = "provided by planner as required by @key"
 )
@@ -130,6 +144,10 @@ func (f *DataSourceFilter) applySuggestionHints(hints []NodeSuggestionHint) {
 	}
 
 	for _, hint := range hints {
+		reason := ReasonKeyRequirementProvidedByPlanner
+		if hint.reason != "" {
+			reason = hint.reason
+		}
 		treeNodeID := TreeNodeID(hint.fieldRef)
 		treeNode, ok := f.nodes.responseTree.Find(treeNodeID)
 		if !ok {
@@ -146,7 +164,7 @@ func (f *DataSourceFilter) applySuggestionHints(hints []NodeSuggestionHint) {
 					f.nodes.items[itemIdx].SelectionReasons = nil
 				}
 			} else {
-				f.nodes.items[itemIdx].selectWithReason(ReasonKeyRequirementProvidedByPlanner, f.enableSelectionReasons)
+				f.nodes.items[itemIdx].selectWithReason(reason, f.enableSelectionReasons)
 			}
 		}
 	}
@@ -273,11 +291,45 @@ func (f *DataSourceFilter) selectDuplicateNodes(secondRun bool) {
 			if f.nodes.items[i].LessPreferable {
 				continue
 			}
+			if f.scorer != nil {
+				f.selectHighestScoredNode(i, nodeDuplicates)
+				continue
+			}
+			if f.selectHighestPriorityNode(i, nodeDuplicates) {
+				continue
+			}
 			f.nodes.items[i].selectWithReason(ReasonStage3SelectAvailableNode, f.enableSelectionReasons)
 		}
 	}
 }
 
+// selectHighestPriorityNode selects the duplicate of the node with the highest priority of its datasource,
+// it returns false when no duplicate has a higher priority than the node itself
+func (f *DataSourceFilter) selectHighestPriorityNode(i int, duplicates []int) (nodeIsSelected bool) {
+	best, bestPriority := i, f.nodePriority(i)
+	for _, duplicate := range duplicates {
+		if f.nodes.items[duplicate].LessPreferable {
+			continue
+		}
+		if priority := f.nodePriority(duplicate); priority > bestPriority {
+			best, bestPriority = duplicate, priority
+		}
+	}
+	if best == i {
+		return false
+	}
+	f.nodes.items[best].selectWithReason(ReasonStage3SelectHighestPriorityNode, f.enableSelectionReasons)
+	return true
+}
+
+func (f *DataSourceFilter) nodePriority(i int) int {
+	dataSource, ok := f.dataSources[f.nodes.items[i].DataSourceHash]
+	if !ok {
+		return 0
+	}
+	return dataSource.Priority
+}
+
 func (f *DataSourceFilter) checkNodeDuplicates(duplicates []int, callback func(nodeIdx int) (nodeIsSelected bool)) (nodeIsSelected bool) {
 	for _, duplicate := range duplicates {
 		if callback(duplicate) {
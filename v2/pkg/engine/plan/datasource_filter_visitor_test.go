@@ -41,6 +41,11 @@ func (b *dsBuilder) KeysMetadata(keys FederationFieldConfigurations) *dsBuilder
 	return b
 }
 
+func (b *dsBuilder) Priority(priority int) *dsBuilder {
+	b.ds.Priority = priority
+	return b
+}
+
 func (b *dsBuilder) Hash(hash DSHash) *dsBuilder {
 	b.ds.hash = hash
 	return b
@@ -561,6 +566,46 @@ func TestFindBestDataSourceSet(t *testing.T) {
 				},
 			},
 		},
+		{
+			Description: "Shareable: 2 ds are equal so choose the one with the highest priority",
+			Definition:  shareableDefinition,
+			Query: `
+				query {
+					me {
+						details {
+							forename
+						}
+					}
+				}
+			`,
+			DataSources: []DataSourceConfiguration{
+				shareableDS1,
+				dsb().Hash(22).Priority(1).Schema(shareableDS2Schema).
+					RootNode("Query", "me").
+					RootNode("User", "id", "details").
+					ChildNode("Details", "forename", "surname").
+					DS(),
+				shareableDS3,
+			},
+			ExpectedVariants: []Variant{
+				{
+					dsOrder: []int{0, 1, 2},
+					suggestions: newNodeSuggestions([]NodeSuggestion{
+						{TypeName: "Query", FieldName: "me", DataSourceHash: 22, Path: "query.me", ParentPath: "query", IsRootNode: true, Selected: true, SelectionReasons: []string{"stage3: select node on the datasource with the highest priority"}},
+						{TypeName: "User", FieldName: "details", DataSourceHash: 22, Path: "query.me.details", ParentPath: "query.me", IsRootNode: true, Selected: true, SelectionReasons: []string{"stage2: node on the same source as selected parent"}},
+						{TypeName: "Details", FieldName: "forename", DataSourceHash: 22, Path: "query.me.details.forename", ParentPath: "query.me.details", IsRootNode: false, Selected: true, SelectionReasons: []string{"stage2: node on the same source as selected parent"}},
+					}),
+				},
+				{
+					dsOrder: []int{2, 1, 0},
+					suggestions: newNodeSuggestions([]NodeSuggestion{
+						{TypeName: "Query", FieldName: "me", DataSourceHash: 22, Path: "query.me", ParentPath: "query", IsRootNode: true, Selected: true, SelectionReasons: []string{"stage3: select first available node"}},
+						{TypeName: "User", FieldName: "details", DataSourceHash: 22, Path: "query.me.details", ParentPath: "query.me", IsRootNode: true, Selected: true, SelectionReasons: []string{"stage2: node on the same source as selected parent"}},
+						{TypeName: "Details", FieldName: "forename", DataSourceHash: 22, Path: "query.me.details.forename", ParentPath: "query.me.details", IsRootNode: false, Selected: true, SelectionReasons: []string{"stage2: node on the same source as selected parent"}},
+					}),
+				},
+			},
+		},
 		{
 			Description: "Shareable: choose second it provides more fields",
 			Definition:  shareableDefinition,
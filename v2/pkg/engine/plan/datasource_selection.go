@@ -0,0 +1,79 @@
+package plan
+
+import (
+	"strconv"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// FieldDataSourceSelection explains which datasources are able to resolve a field of an operation and which one was selected
+type FieldDataSourceSelection struct {
+	TypeName   string
+	FieldName  string
+	Path       string
+	Candidates []DataSourceCandidate
+}
+
+// DataSourceCandidate is a datasource which is able to resolve a field
+type DataSourceCandidate struct {
+	// DataSourceID is the ID of the datasource, datasources without an ID are identified by their index
+	DataSourceID string
+	// IsRootNode is true if the field is a root node of the datasource, otherwise it is a child node
+	IsRootNode bool
+	Selected   bool
+	// SelectionReasons are the reasons of the stages which selected the datasource, e.g. ReasonStage1Unique
+	// or ReasonKeyRequirementProvidedByPlanner for key fields which are required by another datasource
+	SelectionReasons []string
+}
+
+// ExplainDataSourceSelection plans the operation and returns the candidate datasources of each field in the order of the operation
+// The fields added by the planner, e.g. key fields required by other datasources, are included after the fields of the operation
+// It's meant for debugging and tooling, e.g. to show why a field is resolved by a datasource
+func (p *Planner) ExplainDataSourceSelection(operation, definition *ast.Document, operationName string, report *operationreport.Report) []FieldDataSourceSelection {
+	p.explainDataSourceSelection = true
+	defer func() {
+		p.explainDataSourceSelection = false
+	}()
+
+	p.Plan(operation, definition, operationName, report)
+	if report.HasErrors() {
+		return nil
+	}
+
+	dataSourceIDs := make(map[DSHash]string, len(p.config.DataSources))
+	for i := range p.config.DataSources {
+		id := p.config.DataSources[i].ID
+		if id == "" {
+			id = "#" + strconv.Itoa(i)
+		}
+		dataSourceIDs[p.config.DataSources[i].Hash()] = id
+	}
+
+	type fieldKey struct {
+		typeName, fieldName, path string
+	}
+	fields := make(map[fieldKey]int)
+
+	var selections []FieldDataSourceSelection
+	for _, item := range p.configurationVisitor.nodeSuggestions.items {
+		key := fieldKey{typeName: item.TypeName, fieldName: item.FieldName, path: item.Path}
+		i, ok := fields[key]
+		if !ok {
+			i = len(selections)
+			fields[key] = i
+			selections = append(selections, FieldDataSourceSelection{
+				TypeName:  item.TypeName,
+				FieldName: item.FieldName,
+				Path:      item.Path,
+			})
+		}
+		selections[i].Candidates = append(selections[i].Candidates, DataSourceCandidate{
+			DataSourceID:     dataSourceIDs[item.DataSourceHash],
+			IsRootNode:       item.IsRootNode,
+			Selected:         item.Selected,
+			SelectionReasons: item.SelectionReasons,
+		})
+	}
+	return selections
+}
@@ -6,6 +6,9 @@ type FederationMetaData struct {
 	Provides         FederationFieldConfigurations
 	EntityInterfaces []EntityInterfaceConfiguration
 	InterfaceObjects []EntityInterfaceConfiguration
+	// Shareable are the fields annotated with @shareable, which other datasources are allowed to resolve as well
+	// Key fields are always shareable
+	Shareable TypeFields
 }
 
 type EntityInterfaceConfiguration struct {
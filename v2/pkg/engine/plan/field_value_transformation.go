@@ -0,0 +1,67 @@
+package plan
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+// encodeCoordinate returns the coordinate of the field if its value is encoded, nil otherwise
+func (v *Visitor) encodeCoordinate(fieldRef int) *resolve.GraphCoordinate {
+	typeName := v.Walker.EnclosingTypeDefinition.NameString(v.Definition)
+	fieldName := v.Operation.FieldNameString(fieldRef)
+	fieldConfig := v.Config.Fields.ForTypeField(typeName, fieldName)
+	if fieldConfig == nil || !fieldConfig.EncodeValue {
+		return nil
+	}
+	return &resolve.GraphCoordinate{
+		TypeName:  typeName,
+		FieldName: fieldName,
+	}
+}
+
+// collectDecodeVariables adds the variables passed to arguments of the field with decoded values to the response
+// the normalization extracts all argument values into variables, so literal values don't have to be considered
+func (v *Visitor) collectDecodeVariables(fieldRef int) {
+	response := v.response()
+	if response == nil {
+		return
+	}
+	typeName := v.Walker.EnclosingTypeDefinition.NameString(v.Definition)
+	fieldName := v.Operation.FieldNameString(fieldRef)
+	fieldConfig := v.Config.Fields.ForTypeField(typeName, fieldName)
+	if fieldConfig == nil {
+		return
+	}
+	for _, argumentRef := range v.Operation.FieldArguments(fieldRef) {
+		value := v.Operation.ArgumentValue(argumentRef)
+		if value.Kind != ast.ValueKindVariable {
+			continue
+		}
+		argumentName := v.Operation.ArgumentNameString(argumentRef)
+		argumentConfig := fieldConfig.Arguments.ForName(argumentName)
+		if argumentConfig == nil || !argumentConfig.DecodeValue {
+			continue
+		}
+		variableName := v.Operation.VariableValueNameString(value.Ref)
+		if containsDecodeVariable(response.DecodeVariables, variableName) {
+			continue
+		}
+		response.DecodeVariables = append(response.DecodeVariables, resolve.DecodeVariable{
+			VariableName: variableName,
+			Coordinate: resolve.ArgumentCoordinate{
+				TypeName:     typeName,
+				FieldName:    fieldName,
+				ArgumentName: argumentName,
+			},
+		})
+	}
+}
+
+func containsDecodeVariable(variables []resolve.DecodeVariable, name string) bool {
+	for i := range variables {
+		if variables[i].VariableName == name {
+			return true
+		}
+	}
+	return false
+}
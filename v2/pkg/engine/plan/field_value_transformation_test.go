@@ -0,0 +1,83 @@
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astnormalization"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+func TestPlanner_FieldValueTransformation(t *testing.T) {
+	schema := `
+		type Query {
+			user(id: ID, name: String): User
+		}
+
+		type User {
+			id: ID
+			name: String
+		}
+	`
+	definition := unsafeparser.ParseGraphqlDocumentString(schema)
+	require.NoError(t, asttransform.MergeDefinitionWithBaseSchema(&definition))
+
+	operation := unsafeparser.ParseGraphqlDocumentString(`
+		query User($id: ID) {
+			user(id: $id, name: "Jens") { id name }
+		}
+	`)
+	report := &operationreport.Report{}
+	astnormalization.NewNormalizer(true, true).NormalizeOperation(&operation, &definition, report)
+	require.False(t, report.HasErrors(), report.Error())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dataSource := dsb().
+		Schema(schema).
+		RootNode("Query", "user").
+		ChildNode("User", "id", "name").
+		DS()
+	dataSource.Factory = &FakeFactory{upstreamSchema: &definition}
+
+	planner := NewPlanner(ctx, Configuration{
+		DataSources: []DataSourceConfiguration{dataSource},
+		Fields: FieldConfigurations{
+			{
+				TypeName:  "Query",
+				FieldName: "user",
+				Arguments: ArgumentsConfigurations{
+					{Name: "id", SourceType: FieldArgumentSource, DecodeValue: true},
+					{Name: "name", SourceType: FieldArgumentSource},
+				},
+			},
+			{
+				TypeName:    "User",
+				FieldName:   "id",
+				EncodeValue: true,
+			},
+		},
+		DisableResolveFieldPositions: true,
+	})
+	actualPlan := planner.Plan(&operation, &definition, "User", report)
+	require.False(t, report.HasErrors(), report.Error())
+
+	syncPlan, ok := actualPlan.(*SynchronousResponsePlan)
+	require.True(t, ok)
+	assert.Equal(t, []resolve.DecodeVariable{
+		{VariableName: "id", Coordinate: resolve.ArgumentCoordinate{TypeName: "Query", FieldName: "user", ArgumentName: "id"}},
+	}, syncPlan.Response.DecodeVariables)
+
+	user, ok := syncPlan.Response.Data.Fields[0].Value.(*resolve.Object)
+	require.True(t, ok)
+	require.Len(t, user.Fields, 2)
+	assert.Equal(t, &resolve.GraphCoordinate{TypeName: "User", FieldName: "id"}, user.Fields[0].EncodeCoordinate)
+	assert.Nil(t, user.Fields[1].EncodeCoordinate)
+}
@@ -0,0 +1,43 @@
+package plan
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+// canResolveSelectionSet - checks whether the datasource has root or child nodes for all fields of the selection set,
+// e.g. whether the fields of a key could be selected from the datasource even when it doesn't declare the key
+func canResolveSelectionSet(definition *ast.Document, dsConfig *DataSourceConfiguration, typeName, selectionSet string) bool {
+	key, report := RequiredFieldsFragment(typeName, selectionSet, false)
+	if report.HasErrors() || len(key.FragmentDefinitions) == 0 {
+		return false
+	}
+	return canResolveFields(definition, key, dsConfig, typeName, key.FragmentDefinitions[0].SelectionSet)
+}
+
+func canResolveFields(definition, key *ast.Document, dsConfig *DataSourceConfiguration, typeName string, selectionSetRef int) bool {
+	typeNode, ok := definition.Index.FirstNodeByNameStr(typeName)
+	if !ok {
+		return false
+	}
+
+	for _, selectionRef := range key.SelectionSetFieldSelections(selectionSetRef) {
+		fieldRef := key.Selections[selectionRef].Ref
+		fieldName := key.FieldNameString(fieldRef)
+		if !dsConfig.HasRootNode(typeName, fieldName) && !dsConfig.HasChildNode(typeName, fieldName) {
+			return false
+		}
+
+		fieldSelectionSet, ok := key.FieldSelectionSet(fieldRef)
+		if !ok {
+			continue
+		}
+		fieldDefinition, ok := definition.NodeFieldDefinitionByName(typeNode, key.FieldNameBytes(fieldRef))
+		if !ok {
+			return false
+		}
+		if !canResolveFields(definition, key, dsConfig, definition.FieldDefinitionTypeNameString(fieldDefinition), fieldSelectionSet) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+package plan
+
+import (
+	"github.com/buger/jsonparser"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// ListPostProcessingConfiguration enables executable directives to filter and sort list fields in the gateway
+// The directives have to be defined in the schema, with the default names:
+//
+//	directive @filter(if: String!) on FIELD
+//	directive @sort(by: String!, desc: Boolean) on FIELD
+//
+// The directives are never sent to the upstream, see resolve.ListPostProcessing for the supported expressions
+type ListPostProcessingConfiguration struct {
+	Enabled bool
+	// FilterDirectiveName defaults to "filter"
+	FilterDirectiveName string
+	// SortDirectiveName defaults to "sort"
+	SortDirectiveName string
+	// MaxItems rejects lists with more items than the limit, 0 means no limit
+	MaxItems int
+}
+
+func (c *ListPostProcessingConfiguration) filterDirectiveName() string {
+	if c.FilterDirectiveName != "" {
+		return c.FilterDirectiveName
+	}
+	return "filter"
+}
+
+func (c *ListPostProcessingConfiguration) sortDirectiveName() string {
+	if c.SortDirectiveName != "" {
+		return c.SortDirectiveName
+	}
+	return "sort"
+}
+
+// IsListPostProcessingDirective returns true if the directive is executed by the gateway
+// and must not be sent to the upstream
+func (c *ListPostProcessingConfiguration) IsListPostProcessingDirective(directiveName string) bool {
+	if !c.Enabled {
+		return false
+	}
+	return directiveName == c.filterDirectiveName() || directiveName == c.sortDirectiveName()
+}
+
+// resolveListPostProcessing reads the filter and sort directives of a list field
+// literal arguments are validated during planning, variables are validated by the resolver
+func (v *Visitor) resolveListPostProcessing(fieldRef int) *resolve.ListPostProcessing {
+	config := &v.Config.ListPostProcessing
+	if !config.Enabled {
+		return nil
+	}
+
+	var processing *resolve.ListPostProcessing
+	for _, directiveRef := range v.Operation.FieldDirectives(fieldRef) {
+		switch v.Operation.DirectiveNameString(directiveRef) {
+		case config.filterDirectiveName():
+			expression, variableName, ok := v.listPostProcessingStringArgument(fieldRef, directiveRef, "if", resolve.ValidateListFilterExpression)
+			if !ok {
+				return nil
+			}
+			if processing == nil {
+				processing = &resolve.ListPostProcessing{MaxItems: config.MaxItems}
+			}
+			processing.Filter = &resolve.ListFilter{
+				Expression:             expression,
+				ExpressionVariableName: variableName,
+			}
+		case config.sortDirectiveName():
+			by, byVariableName, ok := v.listPostProcessingStringArgument(fieldRef, directiveRef, "by", resolve.ValidateListSortPath)
+			if !ok {
+				return nil
+			}
+			if processing == nil {
+				processing = &resolve.ListPostProcessing{MaxItems: config.MaxItems}
+			}
+			processing.Sort = &resolve.ListSort{
+				By:             by,
+				ByVariableName: byVariableName,
+			}
+			if value, ok := v.Operation.DirectiveArgumentValueByName(directiveRef, []byte("desc")); ok {
+				switch value.Kind {
+				case ast.ValueKindBoolean:
+					processing.Sort.Descending = bool(v.Operation.BooleanValue(value.Ref))
+				case ast.ValueKindVariable:
+					processing.Sort.DescendingVariableName = v.Operation.VariableValueNameString(value.Ref)
+				}
+			}
+		}
+	}
+	return processing
+}
+
+func (v *Visitor) listPostProcessingStringArgument(fieldRef, directiveRef int, argumentName string, validate func(string) error) (value, variableName string, ok bool) {
+	argument, ok := v.Operation.DirectiveArgumentValueByName(directiveRef, []byte(argumentName))
+	if !ok {
+		return "", "", true
+	}
+	switch argument.Kind {
+	case ast.ValueKindVariable:
+		return "", v.Operation.VariableValueNameString(argument.Ref), true
+	case ast.ValueKindString:
+		valueJson, err := v.Operation.ValueToJSON(argument)
+		if err != nil {
+			v.Walker.StopWithInternalErr(err)
+			return "", "", false
+		}
+		value, err = jsonparser.ParseString(valueJson[1 : len(valueJson)-1])
+		if err != nil {
+			v.Walker.StopWithInternalErr(err)
+			return "", "", false
+		}
+		if err := validate(value); err != nil {
+			v.Walker.StopWithExternalErr(operationreport.ErrInvalidListPostProcessingArgument(
+				v.Operation.DirectiveNameBytes(directiveRef), v.Operation.FieldAliasOrNameBytes(fieldRef), err.Error(), v.Operation.Directives[directiveRef].At))
+			return "", "", false
+		}
+		return value, "", true
+	}
+	return "", "", true
+}
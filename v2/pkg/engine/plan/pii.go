@@ -0,0 +1,69 @@
+package plan
+
+import (
+	"slices"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+// PIIDirectiveName is the name of the directive tagging field and argument definitions as personally identifiable information
+const PIIDirectiveName = "pii"
+
+func (v *Visitor) isPIIField(fieldRef, fieldDefinitionRef int) bool {
+	if v.Definition.FieldDefinitionHasNamedDirective(fieldDefinitionRef, PIIDirectiveName) {
+		return true
+	}
+	typeName := v.Walker.EnclosingTypeDefinition.NameString(v.Definition)
+	fieldConfig := v.Config.Fields.ForTypeField(typeName, v.Operation.FieldNameString(fieldRef))
+	return fieldConfig != nil && fieldConfig.PII
+}
+
+func (v *Visitor) isPIIArgument(fieldRef, argumentRef int) bool {
+	fieldName := v.Operation.FieldNameBytes(fieldRef)
+	argumentName := v.Operation.ArgumentNameBytes(argumentRef)
+	argumentDefinitionRef := v.Definition.NodeFieldDefinitionArgumentDefinitionByName(v.Walker.EnclosingTypeDefinition, fieldName, argumentName)
+	if argumentDefinitionRef != -1 && v.Definition.InputValueDefinitionHasDirective(argumentDefinitionRef, []byte(PIIDirectiveName)) {
+		return true
+	}
+	typeName := v.Walker.EnclosingTypeDefinition.NameString(v.Definition)
+	fieldConfig := v.Config.Fields.ForTypeField(typeName, string(fieldName))
+	if fieldConfig == nil {
+		return false
+	}
+	argumentConfig := fieldConfig.Arguments.ForName(string(argumentName))
+	return argumentConfig != nil && argumentConfig.PII
+}
+
+// collectPIIVariables adds the variables passed to PII arguments of the field to the response
+// the normalization extracts all argument values into variables, so literal values don't have to be considered
+func (v *Visitor) collectPIIVariables(fieldRef int) {
+	response := v.response()
+	if response == nil {
+		return
+	}
+	for _, argumentRef := range v.Operation.FieldArguments(fieldRef) {
+		value := v.Operation.ArgumentValue(argumentRef)
+		if value.Kind != ast.ValueKindVariable {
+			continue
+		}
+		if !v.isPIIArgument(fieldRef, argumentRef) {
+			continue
+		}
+		variableName := v.Operation.VariableValueNameString(value.Ref)
+		if !slices.Contains(response.PIIVariables, variableName) {
+			response.PIIVariables = append(response.PIIVariables, variableName)
+		}
+	}
+}
+
+func (v *Visitor) response() *resolve.GraphQLResponse {
+	switch p := v.plan.(type) {
+	case *SynchronousResponsePlan:
+		return p.Response
+	case *SubscriptionResponsePlan:
+		return p.Response.Response
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,88 @@
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astnormalization"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+func TestPlanner_PII(t *testing.T) {
+	schema := `
+		directive @pii on FIELD_DEFINITION | ARGUMENT_DEFINITION
+
+		type Query {
+			user(email: String @pii, id: ID): User
+			userBySSN(ssn: String, name: String): User
+		}
+
+		type User {
+			name: String
+			email: String @pii
+			phone: String
+		}
+	`
+	definition := unsafeparser.ParseGraphqlDocumentString(schema)
+	require.NoError(t, asttransform.MergeDefinitionWithBaseSchema(&definition))
+
+	operation := unsafeparser.ParseGraphqlDocumentString(`
+		query Users($email: String, $ssn: String) {
+			user(email: $email, id: "1") { name email phone }
+			userBySSN(ssn: $ssn, name: "Jens") { name }
+		}
+	`)
+	report := &operationreport.Report{}
+	astnormalization.NewNormalizer(true, true).NormalizeOperation(&operation, &definition, report)
+	require.False(t, report.HasErrors(), report.Error())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dataSource := dsb().
+		Schema(schema).
+		RootNode("Query", "user", "userBySSN").
+		ChildNode("User", "name", "email", "phone").
+		DS()
+	dataSource.Factory = &FakeFactory{upstreamSchema: &definition}
+
+	planner := NewPlanner(ctx, Configuration{
+		DataSources: []DataSourceConfiguration{dataSource},
+		Fields: FieldConfigurations{
+			{
+				TypeName:  "Query",
+				FieldName: "userBySSN",
+				Arguments: ArgumentsConfigurations{
+					{Name: "ssn", SourceType: FieldArgumentSource, PII: true},
+					{Name: "name", SourceType: FieldArgumentSource},
+				},
+			},
+			{
+				TypeName:  "User",
+				FieldName: "phone",
+				PII:       true,
+			},
+		},
+		DisableResolveFieldPositions: true,
+	})
+	actualPlan := planner.Plan(&operation, &definition, "Users", report)
+	require.False(t, report.HasErrors(), report.Error())
+
+	syncPlan, ok := actualPlan.(*SynchronousResponsePlan)
+	require.True(t, ok)
+	assert.Equal(t, []string{"email", "ssn"}, syncPlan.Response.PIIVariables)
+
+	user, ok := syncPlan.Response.Data.Fields[0].Value.(*resolve.Object)
+	require.True(t, ok)
+	piiFields := map[string]bool{}
+	for _, field := range user.Fields {
+		piiFields[string(field.Name)] = field.PII
+	}
+	assert.Equal(t, map[string]bool{"name": false, "email": true, "phone": true}, piiFields)
+}
@@ -20,6 +20,8 @@ type Planner struct {
 	planningVisitor      *Visitor
 
 	prepareOperationWalker *astvisitor.Walker
+
+	explainDataSourceSelection bool
 }
 
 // NewPlanner creates a new Planner from the Configuration and a ctx object
@@ -131,6 +133,8 @@ func (p *Planner) Plan(operation, definition *ast.Document, operationName string
 			ParentPath:     p.planningVisitor.planners[key].parentPath,
 			PathType:       p.planningVisitor.planners[key].parentPathType,
 			IsNested:       p.planningVisitor.planners[key].isNestedPlanner(),
+
+			RedactDebugValues: p.config.Debug.RedactValues,
 		}
 
 		if plannerWithId, ok := p.planningVisitor.planners[key].planner.(astvisitor.VisitorIdentifier); ok {
@@ -165,6 +169,10 @@ func (p *Planner) Plan(operation, definition *ast.Document, operationName string
 
 func (p *Planner) findPlanningPaths(operation, definition *ast.Document, report *operationreport.Report) {
 	dsFilter := NewDataSourceFilter(operation, definition, report)
+	dsFilter.SetNodeSuggestionScorer(p.config.NodeSuggestionScorer)
+	if p.explainDataSourceSelection {
+		dsFilter.EnableSelectionReasons()
+	}
 
 	if p.config.Debug.PrintOperationTransformations {
 		p.debugMessage("Initial operation:")
@@ -173,9 +181,15 @@ func (p *Planner) findPlanningPaths(operation, definition *ast.Document, report
 
 	p.configurationVisitor.debug = p.config.Debug.ConfigurationVisitor
 
+	// fields pinned to a datasource by the datasource directive are selected like fields with a hint
+	pinnedFieldHints := p.dataSourceDirectiveHints(operation, definition, report)
+	if report.HasErrors() {
+		return
+	}
+
 	// set initial suggestions and used data sources
 	p.configurationVisitor.dataSources, p.configurationVisitor.nodeSuggestions =
-		dsFilter.FilterDataSources(p.config.DataSources, nil)
+		dsFilter.FilterDataSources(p.config.DataSources, nil, pinnedFieldHints...)
 	if report.HasErrors() {
 		return
 	}
@@ -207,7 +221,7 @@ func (p *Planner) findPlanningPaths(operation, definition *ast.Document, report
 		if p.configurationVisitor.hasNewFields {
 			// update suggestions for the new required fields
 			p.configurationVisitor.dataSources, p.configurationVisitor.nodeSuggestions =
-				dsFilter.FilterDataSources(p.config.DataSources, p.configurationVisitor.nodeSuggestions, p.configurationVisitor.nodeSuggestionHints...)
+				dsFilter.FilterDataSources(p.config.DataSources, p.configurationVisitor.nodeSuggestions, append(pinnedFieldHints, p.configurationVisitor.nodeSuggestionHints...)...)
 			if report.HasErrors() {
 				return
 			}
@@ -300,9 +314,12 @@ func (p *Planner) prepareOperation(operation, definition *ast.Document, report *
 func (p *Planner) printOperation(operation *ast.Document) {
 	var pp string
 
-	if p.config.Debug.PrintOperationEnableASTRefs {
+	switch {
+	case p.config.Debug.RedactValues:
+		pp, _ = astprinter.PrintStringRedacted(operation, nil, "  ")
+	case p.config.Debug.PrintOperationEnableASTRefs:
 		pp, _ = astprinter.PrintStringIndentDebug(operation, nil, "  ")
-	} else {
+	default:
 		pp, _ = astprinter.PrintStringIndent(operation, nil, "  ")
 	}
 
@@ -0,0 +1,288 @@
+package plan
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RequiresCycleField is a field of a datasource on a RequiresCycle
+type RequiresCycleField struct {
+	DataSourceID string
+	TypeName     string
+	FieldName    string
+}
+
+func (f RequiresCycleField) String() string {
+	return fmt.Sprintf("%s.%s (%s)", f.TypeName, f.FieldName, f.DataSourceID)
+}
+
+// RequiresCycle is a chain of fields which depend on each other through @requires and @key directives,
+// the last field depends on the first one, so none of the fields could be planned
+type RequiresCycle []RequiresCycleField
+
+func (c RequiresCycle) Error() string {
+	fields := make([]string, 0, len(c)+1)
+	for i := range c {
+		fields = append(fields, c[i].String())
+	}
+	fields = append(fields, c[0].String())
+	return "dependency cycle between datasources: " + strings.Join(fields, " -> ")
+}
+
+type RequiresCycles []RequiresCycle
+
+func (c RequiresCycles) Error() string {
+	messages := make([]string, len(c))
+	for i := range c {
+		messages[i] = c[i].Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+type requiresField struct {
+	typeName, fieldName string
+}
+
+type requiresNode struct {
+	dataSource          int
+	typeName, fieldName string
+}
+
+type requiresGraph struct {
+	dataSources []DataSourceConfiguration
+	// providers are the datasources resolving a field, identified by type and field name
+	providers map[requiresField][]requiresNode
+	// requires are the fields selected by the @requires directive of a node
+	requires map[requiresNode][]requiresField
+	// keys are the fields of the keys used to fetch a node, only one of the keys has to be resolvable
+	keys      map[requiresNode][][]requiresField
+	available map[requiresNode]bool
+}
+
+// FindRequiresCycles returns the cycles of fields which depend on each other through @requires directives
+// and the @key directives of the entity fetches which resolve them.
+// A field with @requires is resolvable when all required fields and the fields of one of its keys are resolvable by any datasource,
+// fields in a cycle are never resolvable, so the planner is not able to plan them.
+// Datasources without an ID are identified by their index
+func FindRequiresCycles(dataSources []DataSourceConfiguration) RequiresCycles {
+	graph := newRequiresGraph(dataSources)
+	if len(graph.requires) == 0 {
+		return nil
+	}
+	graph.resolveAvailability()
+
+	var (
+		cycles  RequiresCycles
+		seen    = map[string]struct{}{}
+		visited = map[requiresNode]bool{}
+	)
+	for _, node := range graph.sortedUnavailableNodes() {
+		if visited[node] {
+			continue
+		}
+		graph.findCycles(node, nil, visited, func(cycle RequiresCycle) {
+			key := cycle.Error()
+			if _, ok := seen[key]; ok {
+				return
+			}
+			seen[key] = struct{}{}
+			cycles = append(cycles, cycle)
+		})
+	}
+	return cycles
+}
+
+func newRequiresGraph(dataSources []DataSourceConfiguration) *requiresGraph {
+	graph := &requiresGraph{
+		dataSources: dataSources,
+		providers:   map[requiresField][]requiresNode{},
+		requires:    map[requiresNode][]requiresField{},
+		keys:        map[requiresNode][][]requiresField{},
+		available:   map[requiresNode]bool{},
+	}
+
+	for i := range dataSources {
+		for _, node := range dataSources[i].RootNodes {
+			for _, fieldName := range node.FieldNames {
+				field := requiresField{typeName: node.TypeName, fieldName: fieldName}
+				graph.providers[field] = append(graph.providers[field], requiresNode{dataSource: i, typeName: node.TypeName, fieldName: fieldName})
+			}
+		}
+
+		for _, requires := range dataSources[i].FederationMetaData.Requires {
+			node := requiresNode{dataSource: i, typeName: requires.TypeName, fieldName: requires.FieldName}
+			graph.requires[node] = append(graph.requires[node], fieldsOfType(requires.TypeName, requires.SelectionSet)...)
+
+			// a field with @requires is resolved by an entity fetch, which needs one of the keys of the datasource
+			if _, ok := graph.keys[node]; ok {
+				continue
+			}
+			for _, key := range dataSources[i].RequiredFieldsByKey(requires.TypeName) {
+				graph.keys[node] = append(graph.keys[node], fieldsOfType(requires.TypeName, key.SelectionSet))
+			}
+		}
+	}
+	return graph
+}
+
+// fieldsOfType returns the top level fields of the selection set, the arguments of the fields are skipped
+func fieldsOfType(typeName, selectionSet string) []requiresField {
+	fieldNames := topLevelFieldNames(withoutArguments(selectionSet))
+	fields := make([]requiresField, 0, len(fieldNames))
+	for _, fieldName := range fieldNames {
+		fields = append(fields, requiresField{typeName: typeName, fieldName: fieldName})
+	}
+	return fields
+}
+
+// resolveAvailability marks the nodes which are resolvable, until no more nodes could be marked
+func (g *requiresGraph) resolveAvailability() {
+	for changed := true; changed; {
+		changed = false
+		for _, nodes := range g.providers {
+			for _, node := range nodes {
+				if g.available[node] || !g.isResolvable(node) {
+					continue
+				}
+				g.available[node] = true
+				changed = true
+			}
+		}
+	}
+}
+
+func (g *requiresGraph) isResolvable(node requiresNode) bool {
+	requires, hasRequires := g.requires[node]
+	if !hasRequires {
+		return true
+	}
+	for _, field := range requires {
+		if !g.isFieldAvailable(field) {
+			return false
+		}
+	}
+	keys := g.keys[node]
+	if len(keys) == 0 {
+		return true
+	}
+	for _, key := range keys {
+		if g.areFieldsAvailable(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *requiresGraph) areFieldsAvailable(fields []requiresField) bool {
+	for _, field := range fields {
+		if !g.isFieldAvailable(field) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *requiresGraph) isFieldAvailable(field requiresField) bool {
+	for _, provider := range g.providers[field] {
+		if g.available[provider] {
+			return true
+		}
+	}
+	return false
+}
+
+// dependencies returns the unavailable nodes which the node depends on
+func (g *requiresGraph) dependencies(node requiresNode) (out []requiresNode) {
+	fields := slices.Clone(g.requires[node])
+	for _, key := range g.keys[node] {
+		fields = append(fields, key...)
+	}
+	for _, field := range fields {
+		if g.isFieldAvailable(field) {
+			continue
+		}
+		for _, provider := range g.providers[field] {
+			if !slices.Contains(out, provider) {
+				out = append(out, provider)
+			}
+		}
+	}
+	return out
+}
+
+func (g *requiresGraph) findCycles(node requiresNode, path []requiresNode, visited map[requiresNode]bool, onCycle func(cycle RequiresCycle)) {
+	if idx := slices.Index(path, node); idx != -1 {
+		onCycle(g.cycle(path[idx:]))
+		return
+	}
+	if visited[node] {
+		return
+	}
+	path = append(path, node)
+	for _, dependency := range g.dependencies(node) {
+		g.findCycles(dependency, path, visited, onCycle)
+	}
+	visited[node] = true
+}
+
+// cycle starts the cycle with the smallest field, so the same cycle is reported once
+func (g *requiresGraph) cycle(nodes []requiresNode) RequiresCycle {
+	cycle := make(RequiresCycle, len(nodes))
+	start := 0
+	for i := range nodes {
+		cycle[i] = RequiresCycleField{
+			DataSourceID: g.dataSourceID(nodes[i].dataSource),
+			TypeName:     nodes[i].typeName,
+			FieldName:    nodes[i].fieldName,
+		}
+		if cycle[i].String() < cycle[start].String() {
+			start = i
+		}
+	}
+	return append(slices.Clone(cycle[start:]), cycle[:start]...)
+}
+
+func withoutArguments(selectionSet string) string {
+	var (
+		out   strings.Builder
+		depth int
+	)
+	for _, r := range selectionSet {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case depth == 0:
+			out.WriteRune(r)
+		}
+	}
+	return out.String()
+}
+
+func (g *requiresGraph) dataSourceID(i int) string {
+	if g.dataSources[i].ID == "" {
+		return "#" + strconv.Itoa(i)
+	}
+	return g.dataSources[i].ID
+}
+
+func (g *requiresGraph) sortedUnavailableNodes() (out []requiresNode) {
+	for node := range g.requires {
+		if !g.available[node] {
+			out = append(out, node)
+		}
+	}
+	slices.SortFunc(out, func(a, b requiresNode) int {
+		if a.dataSource != b.dataSource {
+			return a.dataSource - b.dataSource
+		}
+		if c := strings.Compare(a.typeName, b.typeName); c != 0 {
+			return c
+		}
+		return strings.Compare(a.fieldName, b.fieldName)
+	})
+	return out
+}
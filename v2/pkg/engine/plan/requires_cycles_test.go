@@ -0,0 +1,111 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRequiresCycles(t *testing.T) {
+	productKey := func(selectionSet string) FederationFieldConfigurations {
+		return FederationFieldConfigurations{{TypeName: "Product", SelectionSet: selectionSet}}
+	}
+	requires := func(fieldName, selectionSet string) FederationFieldConfigurations {
+		return FederationFieldConfigurations{{TypeName: "Product", FieldName: fieldName, SelectionSet: selectionSet}}
+	}
+
+	products := DataSourceConfiguration{
+		ID:                 "products",
+		RootNodes:          TypeFields{{TypeName: "Product", FieldNames: []string{"upc", "name", "weight"}}},
+		FederationMetaData: FederationMetaData{Keys: productKey("upc")},
+	}
+
+	t.Run("required fields of another datasource", func(t *testing.T) {
+		cycles := FindRequiresCycles([]DataSourceConfiguration{
+			products,
+			{
+				ID:        "inventory",
+				RootNodes: TypeFields{{TypeName: "Product", FieldNames: []string{"upc", "shippingEstimate"}}},
+				FederationMetaData: FederationMetaData{
+					Keys:     productKey("upc"),
+					Requires: requires("shippingEstimate", "weight name"),
+				},
+			},
+		})
+		assert.Empty(t, cycles)
+	})
+
+	t.Run("fields requiring each other", func(t *testing.T) {
+		cycles := FindRequiresCycles([]DataSourceConfiguration{
+			{
+				ID:        "products",
+				RootNodes: TypeFields{{TypeName: "Product", FieldNames: []string{"upc", "weight"}}},
+				FederationMetaData: FederationMetaData{
+					Keys:     productKey("upc"),
+					Requires: requires("weight", "shippingEstimate"),
+				},
+			},
+			{
+				ID:        "inventory",
+				RootNodes: TypeFields{{TypeName: "Product", FieldNames: []string{"upc", "shippingEstimate"}}},
+				FederationMetaData: FederationMetaData{
+					Keys:     productKey("upc"),
+					Requires: requires("shippingEstimate", `weight(unit: "kg")`),
+				},
+			},
+		})
+		require.Len(t, cycles, 1)
+		assert.Equal(t, RequiresCycle{
+			{DataSourceID: "inventory", TypeName: "Product", FieldName: "shippingEstimate"},
+			{DataSourceID: "products", TypeName: "Product", FieldName: "weight"},
+		}, cycles[0])
+		assert.EqualError(t, cycles, "dependency cycle between datasources: Product.shippingEstimate (inventory) -> Product.weight (products) -> Product.shippingEstimate (inventory)")
+	})
+
+	t.Run("another datasource resolves a field of the cycle", func(t *testing.T) {
+		cycles := FindRequiresCycles([]DataSourceConfiguration{
+			products,
+			{
+				ID:        "shipping",
+				RootNodes: TypeFields{{TypeName: "Product", FieldNames: []string{"upc", "weight"}}},
+				FederationMetaData: FederationMetaData{
+					Keys:     productKey("upc"),
+					Requires: requires("weight", "shippingEstimate"),
+				},
+			},
+			{
+				ID:        "inventory",
+				RootNodes: TypeFields{{TypeName: "Product", FieldNames: []string{"upc", "shippingEstimate"}}},
+				FederationMetaData: FederationMetaData{
+					Keys:     productKey("upc"),
+					Requires: requires("shippingEstimate", "weight"),
+				},
+			},
+		})
+		assert.Empty(t, cycles)
+	})
+
+	t.Run("key field requiring the field fetched by the key", func(t *testing.T) {
+		cycles := FindRequiresCycles([]DataSourceConfiguration{
+			products,
+			{
+				// the key field sku is external, so it's not resolved by the datasource
+				RootNodes: TypeFields{{TypeName: "Product", FieldNames: []string{"rating"}}},
+				FederationMetaData: FederationMetaData{
+					Keys:     productKey("sku"),
+					Requires: requires("rating", "name"),
+				},
+			},
+			{
+				ID:        "inventory",
+				RootNodes: TypeFields{{TypeName: "Product", FieldNames: []string{"upc", "sku"}}},
+				FederationMetaData: FederationMetaData{
+					Keys:     productKey("upc"),
+					Requires: requires("sku", "rating"),
+				},
+			},
+		})
+		assert.EqualError(t, cycles, "dependency cycle between datasources: Product.rating (#1) -> Product.sku (inventory) -> Product.rating (#1)")
+	})
+}
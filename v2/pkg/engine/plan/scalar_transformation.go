@@ -0,0 +1,85 @@
+package plan
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+// ScalarTransformerConfiguration transforms the values of a scalar field in the responses of a datasource,
+// e.g. to convert epoch timestamps of an upstream into the ISO8601 strings promised by the schema
+type ScalarTransformerConfiguration struct {
+	TypeName    string
+	FieldName   string
+	Transformer resolve.ScalarTransformer
+}
+
+type ScalarTransformerConfigurations []ScalarTransformerConfiguration
+
+func (s ScalarTransformerConfigurations) ForTypeField(typeName, fieldName string) resolve.ScalarTransformer {
+	for i := range s {
+		if s[i].TypeName == typeName && s[i].FieldName == fieldName {
+			return s[i].Transformer
+		}
+	}
+	return nil
+}
+
+type scalarTransformationField struct {
+	field       *resolve.Field
+	transformer resolve.ScalarTransformer
+}
+
+// collectScalarTransformations remembers the current field for each planner
+// whose datasource has a transformer configured for it
+func (v *Visitor) collectScalarTransformations(fieldRef int) {
+	typeName := v.Walker.EnclosingTypeDefinition.NameString(v.Definition)
+	fieldName := v.Operation.FieldNameString(fieldRef)
+	currentPath := v.currentFullPath(false)
+
+	for i := range v.planners {
+		transformer := v.planners[i].dataSourceConfiguration.ScalarTransformers.ForTypeField(typeName, fieldName)
+		if transformer == nil || !v.planners[i].hasPath(currentPath) {
+			continue
+		}
+		v.planners[i].objectFetchConfiguration.scalarTransformations = append(v.planners[i].objectFetchConfiguration.scalarTransformations, scalarTransformationField{
+			field:       v.currentField,
+			transformer: transformer,
+		})
+	}
+}
+
+// resolveScalarTransformations computes the paths of the collected fields relative to the object the fetch is attached to
+func (v *Visitor) resolveScalarTransformations(config objectFetchConfiguration) []resolve.ScalarTransformation {
+	if len(config.scalarTransformations) == 0 {
+		return nil
+	}
+	transformations := make([]resolve.ScalarTransformation, 0, len(config.scalarTransformations))
+	for _, transformation := range config.scalarTransformations {
+		path, ok := scalarTransformationPath(config.object, transformation.field, nil)
+		if !ok {
+			continue
+		}
+		transformations = append(transformations, resolve.ScalarTransformation{
+			Path:        path,
+			Transformer: transformation.transformer,
+		})
+	}
+	return transformations
+}
+
+func scalarTransformationPath(node resolve.Node, field *resolve.Field, parentPath []string) ([]string, bool) {
+	switch n := node.(type) {
+	case *resolve.Object:
+		for _, f := range n.Fields {
+			path := append(parentPath[:len(parentPath):len(parentPath)], f.Value.NodePath()...)
+			if f == field {
+				return path, true
+			}
+			if fieldPath, ok := scalarTransformationPath(f.Value, field, path); ok {
+				return fieldPath, true
+			}
+		}
+	case *resolve.Array:
+		return scalarTransformationPath(n.Item, field, append(parentPath[:len(parentPath):len(parentPath)], n.Item.NodePath()...))
+	}
+	return nil, false
+}
@@ -0,0 +1,112 @@
+package plan
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ShareableConflict is a field which is a root node of multiple datasources without being shareable in all of them
+// The planner resolves such a field from one of the datasources, so the plan depends on the order of the datasources
+type ShareableConflict struct {
+	TypeName      string
+	FieldName     string
+	DataSourceIDs []string
+}
+
+func (c ShareableConflict) Error() string {
+	return fmt.Sprintf("field %s.%s is resolved by the datasources %s, but it is not shareable", c.TypeName, c.FieldName, strings.Join(c.DataSourceIDs, ", "))
+}
+
+type ShareableConflicts []ShareableConflict
+
+func (c ShareableConflicts) Error() string {
+	messages := make([]string, len(c))
+	for i := range c {
+		messages[i] = c[i].Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+type shareableField struct {
+	typeName, fieldName string
+}
+
+// FindShareableConflicts returns the fields which are root nodes of multiple datasources,
+// but are neither key fields nor configured as Shareable in all of these datasources
+// Datasources without an ID are identified by their index
+func FindShareableConflicts(dataSources []DataSourceConfiguration) ShareableConflicts {
+	var (
+		fields       []shareableField
+		claims       = map[shareableField][]string{}
+		notShareable = map[shareableField]bool{}
+	)
+
+	for i := range dataSources {
+		id := dataSources[i].ID
+		if id == "" {
+			id = "#" + strconv.Itoa(i)
+		}
+		for _, node := range dataSources[i].RootNodes {
+			for _, fieldName := range node.FieldNames {
+				field := shareableField{typeName: node.TypeName, fieldName: fieldName}
+				if _, ok := claims[field]; !ok {
+					fields = append(fields, field)
+				}
+				if slices.Contains(claims[field], id) {
+					continue
+				}
+				claims[field] = append(claims[field], id)
+				if !dataSources[i].isShareable(node.TypeName, fieldName) {
+					notShareable[field] = true
+				}
+			}
+		}
+	}
+
+	var conflicts ShareableConflicts
+	for _, field := range fields {
+		if len(claims[field]) < 2 || !notShareable[field] {
+			continue
+		}
+		conflicts = append(conflicts, ShareableConflict{
+			TypeName:      field.typeName,
+			FieldName:     field.fieldName,
+			DataSourceIDs: claims[field],
+		})
+	}
+	return conflicts
+}
+
+// isShareable returns true if the field is configured as Shareable or is a top level field of a key of the type
+func (d *DataSourceConfiguration) isShareable(typeName, fieldName string) bool {
+	if d.FederationMetaData.Shareable.HasNode(typeName, fieldName) {
+		return true
+	}
+	for _, key := range d.FederationMetaData.Keys.FilterByTypeAndResolvability(typeName, false) {
+		if slices.Contains(topLevelFieldNames(key.SelectionSet), fieldName) {
+			return true
+		}
+	}
+	return false
+}
+
+// topLevelFieldNames returns the names of the fields of a selection set without the fields of nested selections,
+// e.g. "id info { sku }" results in id and info
+func topLevelFieldNames(selectionSet string) (names []string) {
+	depth := 0
+	for _, token := range strings.Fields(strings.NewReplacer("{", " { ", "}", " } ").Replace(selectionSet)) {
+		switch token {
+		case "{":
+			depth++
+		case "}":
+			depth--
+		default:
+			if depth == 0 {
+				names = append(names, token)
+			}
+		}
+	}
+	return names
+}
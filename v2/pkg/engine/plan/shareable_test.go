@@ -0,0 +1,56 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindShareableConflicts(t *testing.T) {
+	productKey := FederationMetaData{
+		Keys: FederationFieldConfigurations{{TypeName: "Product", SelectionSet: "upc info { sku }"}},
+	}
+
+	t.Run("fields of a single datasource", func(t *testing.T) {
+		conflicts := FindShareableConflicts([]DataSourceConfiguration{
+			{ID: "products", RootNodes: TypeFields{{TypeName: "Query", FieldNames: []string{"topProducts"}}}},
+			{ID: "reviews", RootNodes: TypeFields{{TypeName: "Query", FieldNames: []string{"topReviews"}}}},
+		})
+		assert.Empty(t, conflicts)
+	})
+
+	t.Run("field of multiple datasources", func(t *testing.T) {
+		conflicts := FindShareableConflicts([]DataSourceConfiguration{
+			{ID: "accounts", RootNodes: TypeFields{{TypeName: "Query", FieldNames: []string{"me", "user"}}}},
+			{RootNodes: TypeFields{{TypeName: "Query", FieldNames: []string{"me"}}}},
+			{ID: "inventory", RootNodes: TypeFields{{TypeName: "Query", FieldNames: []string{"me"}}}},
+		})
+		assert.Equal(t, ShareableConflicts{
+			{TypeName: "Query", FieldName: "me", DataSourceIDs: []string{"accounts", "#1", "inventory"}},
+		}, conflicts)
+		assert.EqualError(t, conflicts, "field Query.me is resolved by the datasources accounts, #1, inventory, but it is not shareable")
+	})
+
+	t.Run("key fields are shareable", func(t *testing.T) {
+		conflicts := FindShareableConflicts([]DataSourceConfiguration{
+			{ID: "products", RootNodes: TypeFields{{TypeName: "Product", FieldNames: []string{"upc", "info", "name"}}}, FederationMetaData: productKey},
+			{ID: "reviews", RootNodes: TypeFields{{TypeName: "Product", FieldNames: []string{"upc", "info", "reviews"}}}, FederationMetaData: productKey},
+		})
+		assert.Empty(t, conflicts)
+	})
+
+	t.Run("fields need to be shareable in all datasources", func(t *testing.T) {
+		shareable := func(fieldNames ...string) FederationMetaData {
+			metaData := productKey
+			metaData.Shareable = TypeFields{{TypeName: "Product", FieldNames: fieldNames}}
+			return metaData
+		}
+		conflicts := FindShareableConflicts([]DataSourceConfiguration{
+			{ID: "products", RootNodes: TypeFields{{TypeName: "Product", FieldNames: []string{"upc", "name", "price"}}}, FederationMetaData: shareable("name", "price")},
+			{ID: "search", RootNodes: TypeFields{{TypeName: "Product", FieldNames: []string{"upc", "name", "price"}}}, FederationMetaData: shareable("name")},
+		})
+		assert.Equal(t, ShareableConflicts{
+			{TypeName: "Product", FieldName: "price", DataSourceIDs: []string{"products", "search"}},
+		}, conflicts)
+	})
+}
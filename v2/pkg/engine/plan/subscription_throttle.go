@@ -0,0 +1,60 @@
+package plan
+
+import (
+	"time"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+// ThrottleDirectiveName is the name of the directive limiting the delivery frequency of subscription fields
+//
+//	enum ThrottlePolicy { DROP CONFLATE }
+//	directive @throttle(ms: Int!, policy: ThrottlePolicy = DROP) on FIELD_DEFINITION
+const ThrottleDirectiveName = "throttle"
+
+const (
+	throttleDirectiveIntervalArgumentName = "ms"
+	throttleDirectivePolicyArgumentName   = "policy"
+	throttlePolicyConflate                = "CONFLATE"
+)
+
+// resolveSubscriptionThrottle sets the throttle of the root field of a subscription
+// the field configuration takes precedence over the @throttle directive of the field definition
+func (v *Visitor) resolveSubscriptionThrottle(fieldRef, fieldDefinitionRef int) {
+	subscriptionPlan, ok := v.plan.(*SubscriptionResponsePlan)
+	if !ok {
+		return
+	}
+	typeName := v.Walker.EnclosingTypeDefinition.NameString(v.Definition)
+	if typeName != v.Definition.Index.SubscriptionTypeName.String() {
+		return
+	}
+	fieldConfig := v.Config.Fields.ForTypeField(typeName, v.Operation.FieldNameString(fieldRef))
+	if fieldConfig != nil && fieldConfig.Throttle != nil {
+		throttle := *fieldConfig.Throttle
+		subscriptionPlan.Response.Throttle = &throttle
+		return
+	}
+	subscriptionPlan.Response.Throttle = v.throttleDirective(fieldDefinitionRef)
+}
+
+func (v *Visitor) throttleDirective(fieldDefinitionRef int) *resolve.SubscriptionThrottle {
+	directiveRef, ok := v.Definition.FieldDefinitionDirectiveByName(fieldDefinitionRef, []byte(ThrottleDirectiveName))
+	if !ok {
+		return nil
+	}
+	interval, ok := v.Definition.DirectiveArgumentValueByName(directiveRef, []byte(throttleDirectiveIntervalArgumentName))
+	if !ok || interval.Kind != ast.ValueKindInteger {
+		return nil
+	}
+	throttle := &resolve.SubscriptionThrottle{
+		Interval: time.Duration(v.Definition.IntValueAsInt(interval.Ref)) * time.Millisecond,
+		Policy:   resolve.SubscriptionThrottlePolicyDrop,
+	}
+	policy, ok := v.Definition.DirectiveArgumentValueByName(directiveRef, []byte(throttleDirectivePolicyArgumentName))
+	if ok && policy.Kind == ast.ValueKindEnum && v.Definition.EnumValueNameString(policy.Ref) == throttlePolicyConflate {
+		throttle.Policy = resolve.SubscriptionThrottlePolicyConflate
+	}
+	return throttle
+}
@@ -0,0 +1,102 @@
+package plan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astnormalization"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+func TestPlanner_SubscriptionThrottle(t *testing.T) {
+	schema := `
+		enum ThrottlePolicy { DROP CONFLATE }
+		directive @throttle(ms: Int!, policy: ThrottlePolicy = DROP) on FIELD_DEFINITION
+
+		type Query {
+			hello: String
+		}
+
+		type Subscription {
+			price: Int @throttle(ms: 100, policy: CONFLATE)
+			trades: Int @throttle(ms: 250)
+			volume: Int @throttle(ms: 100)
+			counter: Int
+		}
+	`
+
+	plan := func(t *testing.T, query string, fields FieldConfigurations) *SubscriptionResponsePlan {
+		t.Helper()
+		definition := unsafeparser.ParseGraphqlDocumentString(schema)
+		require.NoError(t, asttransform.MergeDefinitionWithBaseSchema(&definition))
+
+		operation := unsafeparser.ParseGraphqlDocumentString(query)
+		report := &operationreport.Report{}
+		astnormalization.NewNormalizer(true, true).NormalizeOperation(&operation, &definition, report)
+		require.False(t, report.HasErrors(), report.Error())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		dataSource := dsb().
+			Schema(schema).
+			RootNode("Query", "hello").
+			RootNode("Subscription", "price", "trades", "volume", "counter").
+			DS()
+		dataSource.Factory = &FakeFactory{upstreamSchema: &definition}
+
+		planner := NewPlanner(ctx, Configuration{
+			DataSources:                  []DataSourceConfiguration{dataSource},
+			Fields:                       fields,
+			DisableResolveFieldPositions: true,
+		})
+		actualPlan := planner.Plan(&operation, &definition, "", report)
+		require.False(t, report.HasErrors(), report.Error())
+
+		subscriptionPlan, ok := actualPlan.(*SubscriptionResponsePlan)
+		require.True(t, ok)
+		return subscriptionPlan
+	}
+
+	t.Run("directive", func(t *testing.T) {
+		assert.Equal(t, &resolve.SubscriptionThrottle{
+			Interval: 100 * time.Millisecond,
+			Policy:   resolve.SubscriptionThrottlePolicyConflate,
+		}, plan(t, `subscription { price }`, nil).Response.Throttle)
+	})
+
+	t.Run("directive with default policy", func(t *testing.T) {
+		assert.Equal(t, &resolve.SubscriptionThrottle{
+			Interval: 250 * time.Millisecond,
+			Policy:   resolve.SubscriptionThrottlePolicyDrop,
+		}, plan(t, `subscription { trades }`, nil).Response.Throttle)
+	})
+
+	t.Run("field configuration takes precedence", func(t *testing.T) {
+		actual := plan(t, `subscription { volume }`, FieldConfigurations{
+			{
+				TypeName:  "Subscription",
+				FieldName: "volume",
+				Throttle: &resolve.SubscriptionThrottle{
+					Interval: time.Second,
+					Policy:   resolve.SubscriptionThrottlePolicyConflate,
+				},
+			},
+		})
+		assert.Equal(t, &resolve.SubscriptionThrottle{
+			Interval: time.Second,
+			Policy:   resolve.SubscriptionThrottlePolicyConflate,
+		}, actual.Response.Throttle)
+	})
+
+	t.Run("without throttle", func(t *testing.T) {
+		assert.Nil(t, plan(t, `subscription { counter }`, nil).Response.Throttle)
+	})
+}
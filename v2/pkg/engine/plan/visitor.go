@@ -272,12 +272,19 @@ func (v *Visitor) EnterField(ref int) {
 
 	fullFieldPathWithoutFragments := v.currentFullPath(true)
 
+	skipIncludeInfo := v.resolveSkipIncludeForField(ref)
+
 	// if we already have a field with the same path we merge existing field with the current one
 	if v.allowFieldMerge && v.handleExistingField(ref, fieldDefinitionTypeRef, fullFieldPathWithoutFragments) {
 		return
 	}
 
-	skipIncludeInfo := v.resolveSkipIncludeForField(ref)
+	// leaf fields with the same skip and include conditions are merged even with conditional fields in the operation,
+	// e.g. "id @tag(name: "a") id @tag(name: "b")" which differ only in repeatable directives
+	if !v.allowFieldMerge && v.canMergeLeafField(ref, skipIncludeInfo, fullFieldPathWithoutFragments) &&
+		v.handleExistingField(ref, fieldDefinitionTypeRef, fullFieldPathWithoutFragments) {
+		return
+	}
 
 	onTypeNames := v.resolveOnTypeNames(ref)
 
@@ -309,6 +316,8 @@ func (v *Visitor) EnterField(ref int) {
 			IncludeDirectiveDefined: skipIncludeInfo.include,
 			IncludeVariableName:     skipIncludeInfo.includeVariableName,
 			Info:                    v.resolveFieldInfo(ref, fieldDefinitionTypeRef, onTypeNames),
+			PII:                     v.isPIIField(ref, fieldDefinition),
+			EncodeCoordinate:        v.encodeCoordinate(ref),
 		}
 	}
 
@@ -319,6 +328,11 @@ func (v *Visitor) EnterField(ref int) {
 	v.fieldByPaths[fullFieldPathWithoutFragments] = v.currentField
 
 	v.mapFieldConfig(ref)
+	v.collectScalarTransformations(ref)
+	v.collectPIIVariables(ref)
+	v.collectDecodeVariables(ref)
+	v.resolveSubscriptionThrottle(ref, fieldDefinition)
+	v.collectCacheControl(ref, fieldDefinition)
 }
 
 func (v *Visitor) handleExistingField(currentFieldRef int, fieldDefinitionTypeRef int, fullFieldPathWithoutFragments string) (exists bool) {
@@ -376,6 +390,23 @@ func (v *Visitor) handleExistingField(currentFieldRef int, fieldDefinitionTypeRe
 	return true
 }
 
+func (v *Visitor) canMergeLeafField(currentFieldRef int, info skipIncludeInfo, fullFieldPathWithoutFragments string) bool {
+	if v.Operation.FieldHasSelections(currentFieldRef) {
+		return false
+	}
+	resolveField := v.fieldByPaths[fullFieldPathWithoutFragments]
+	if resolveField == nil {
+		return false
+	}
+	if !slices.EqualFunc(resolveField.OnTypeNames, v.resolveOnTypeNames(currentFieldRef), bytes.Equal) {
+		return false
+	}
+	return resolveField.SkipDirectiveDefined == info.skip &&
+		resolveField.SkipVariableName == info.skipVariableName &&
+		resolveField.IncludeDirectiveDefined == info.include &&
+		resolveField.IncludeVariableName == info.includeVariableName
+}
+
 func (v *Visitor) mapFieldConfig(ref int) {
 	typeName := v.Walker.EnclosingTypeDefinition.NameString(v.Definition)
 	fieldNameStr := v.Operation.FieldNameString(ref)
@@ -611,11 +642,15 @@ func (v *Visitor) resolveFieldValue(fieldRef, typeRef int, nullable bool, path [
 		return v.resolveFieldValue(fieldRef, ofType, false, path)
 	case ast.TypeKindList:
 		listItem := v.resolveFieldValue(fieldRef, ofType, true, nil)
-		return &resolve.Array{
+		array := &resolve.Array{
 			Nullable: nullable,
 			Path:     path,
 			Item:     listItem,
 		}
+		if path != nil {
+			array.PostProcessing = v.resolveListPostProcessing(fieldRef)
+		}
+		return array
 	case ast.TypeKindNamed:
 		typeName := v.Definition.ResolveTypeNameString(typeRef)
 		typeDefinitionNode, ok := v.Definition.Index.FirstNodeByNameStr(typeName)
@@ -687,6 +722,9 @@ func (v *Visitor) resolveFieldValue(fieldRef, typeRef int, nullable bool, path [
 				Fields:               []*resolve.Field{},
 				UnescapeResponseJson: unescapeResponseJson,
 			}
+			if fieldConfig != nil && fieldConfig.Pagination != nil {
+				object.Pagination = v.resolveConnectionPagination(fieldRef, fieldConfig.Pagination)
+			}
 			v.objects = append(v.objects, object)
 			v.Walker.DefferOnEnterField(func() {
 				v.currentFields = append(v.currentFields, objectFields{
@@ -814,6 +852,8 @@ func (v *Visitor) EnterOperationDefinition(ref int) {
 		}
 	}
 
+	v.initCacheControl(graphQLResponse, operationKind)
+
 	if operationKind == ast.OperationTypeSubscription {
 		v.plan = &SubscriptionResponsePlan{
 			FlushInterval: v.Config.DefaultFlushIntervalMillis,
@@ -883,6 +923,7 @@ func (v *Visitor) LeaveDocument(_, _ *ast.Document) {
 			v.configureObjectFetch(v.planners[i].objectFetchConfiguration)
 		}
 	}
+	v.finishCacheControl()
 }
 
 var (
@@ -1107,6 +1148,15 @@ func (v *Visitor) configureSubscription(config objectFetchConfiguration) {
 	config.trigger.PostProcessing = subscription.PostProcessing
 	v.resolveInputTemplates(config, &subscription.Input, &config.trigger.Variables)
 	config.trigger.Input = []byte(subscription.Input)
+	if subscription.InitialFetch != nil {
+		initialFetch := &resolve.SubscriptionInitialFetch{
+			DataSource: subscription.InitialFetch.DataSource,
+			Variables:  append(resolve.Variables(nil), subscription.Variables...),
+		}
+		v.resolveInputTemplates(config, &subscription.InitialFetch.Input, &initialFetch.Variables)
+		initialFetch.Input = []byte(subscription.InitialFetch.Input)
+		config.trigger.InitialFetch = initialFetch
+	}
 }
 
 func (v *Visitor) configureObjectFetch(config objectFetchConfiguration) {
@@ -1115,6 +1165,9 @@ func (v *Visitor) configureObjectFetch(config objectFetchConfiguration) {
 		return
 	}
 	fetchConfig := config.planner.ConfigureFetch()
+	fetchConfig.PostProcessing.ScalarTransformations = v.resolveScalarTransformations(config)
+	fetchConfig.PostProcessing.InvalidUTF8Policy = config.invalidUTF8Policy
+	fetchConfig.PostProcessing.ExtensionsPassthrough = config.extensionsPassthrough
 	fetch := v.configureFetch(config, fetchConfig)
 	v.resolveInputTemplates(config, &fetch.Input, &fetch.Variables)
 
@@ -1151,6 +1204,7 @@ func (v *Visitor) configureFetch(internal objectFetchConfiguration, external res
 			DataSourceID:  internal.sourceID,
 			RootFields:    internal.rootFields,
 			OperationType: internal.operationType,
+			EntityKeys:    internal.entityKeys,
 		}
 	}
 
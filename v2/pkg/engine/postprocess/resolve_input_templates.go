@@ -56,6 +56,11 @@ func (d *ResolveInputTemplates) traverseTrigger(trigger *resolve.GraphQLSubscrip
 	d.resolveInputTemplate(trigger.Variables, string(trigger.Input), &trigger.InputTemplate)
 	trigger.Input = nil
 	trigger.Variables = nil
+	if trigger.InitialFetch != nil {
+		d.resolveInputTemplate(trigger.InitialFetch.Variables, string(trigger.InitialFetch.Input), &trigger.InitialFetch.InputTemplate)
+		trigger.InitialFetch.Input = nil
+		trigger.InitialFetch.Variables = nil
+	}
 }
 
 func (d *ResolveInputTemplates) traverseSingleFetch(fetch *resolve.SingleFetch) {
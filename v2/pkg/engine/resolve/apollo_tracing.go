@@ -0,0 +1,193 @@
+package resolve
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+// ApolloTracingFormat is the format of the Apollo compatible tracing data in the response extensions
+type ApolloTracingFormat int
+
+const (
+	ApolloTracingFormatNone ApolloTracingFormat = iota
+	// ApolloTracingFormatLegacy renders the "tracing" extension of the Apollo Tracing format in version 1
+	ApolloTracingFormatLegacy
+	// ApolloTracingFormatFTV1 renders the "ftv1" extension, the base64 encoded federated trace protobuf message
+	// which is collected from the subgraphs by Apollo gateways, it requires a FederatedTraceEncoder, see Context.SetFederatedTraceEncoder
+	ApolloTracingFormatFTV1
+)
+
+// FederatedTraceEncoder encodes the trace of an operation as the Trace message of the Apollo usage reporting protocol,
+// it keeps the resolver free of a protobuf dependency, see package ftv1 of the otel module for an implementation
+type FederatedTraceEncoder interface {
+	EncodeFederatedTrace(trace ApolloTrace) ([]byte, error)
+}
+
+// ApolloTrace are the timings of the root fields of the fetches of an operation
+type ApolloTrace struct {
+	Start     time.Time
+	End       time.Time
+	Resolvers []ApolloTraceResolver
+}
+
+// ApolloTraceResolver are the timings of a root field of a fetch,
+// the path is the response path of the field without the list items, so the fetches of the items of a list are attributed to the list field
+type ApolloTraceResolver struct {
+	Path        []string
+	ParentType  string
+	FieldName   string
+	StartOffset time.Duration
+	Duration    time.Duration
+}
+
+const apolloTracingVersion = 1
+
+var (
+	literalApolloTracing = []byte("tracing")
+	literalFTV1          = []byte("ftv1")
+)
+
+// SetApolloTracing renders the timings of the fetches of the operation in the response extensions in the format,
+// start is the start of the execution of the operation which the offsets of the fetches refer to.
+// The timings of the fetches are collected, if they are not collected yet, see SetFetchTimings.
+// Subscriptions don't render tracing data.
+func (c *Context) SetApolloTracing(format ApolloTracingFormat, start time.Time) {
+	c.apolloTracing = format
+	c.apolloTracingStart = start
+	if format != ApolloTracingFormatNone && c.fetchTimings == nil {
+		c.fetchTimings = &FetchTimings{}
+	}
+}
+
+// SetFederatedTraceEncoder sets the encoder of the traces rendered in the ApolloTracingFormatFTV1,
+// without an encoder no tracing data is rendered in this format
+func (c *Context) SetFederatedTraceEncoder(encoder FederatedTraceEncoder) {
+	c.federatedTraceEncoder = encoder
+}
+
+func (r *Resolvable) hasApolloTracing() bool {
+	switch r.ctx.apolloTracing {
+	case ApolloTracingFormatNone:
+		return false
+	case ApolloTracingFormatFTV1:
+		if r.ctx.federatedTraceEncoder == nil {
+			return false
+		}
+	}
+	return r.ctx.fetchTimings != nil && r.operationType != ast.OperationTypeSubscription
+}
+
+func (r *Resolvable) printApolloTracingExtension() error {
+	end := time.Now()
+	timings := r.ctx.fetchTimings.Timings()
+	sort.SliceStable(timings, func(i, j int) bool {
+		return timings[i].Start.Before(timings[j].Start)
+	})
+
+	trace := newApolloTrace(r.ctx.apolloTracingStart, end, timings)
+
+	var (
+		key  []byte
+		data []byte
+		err  error
+	)
+	switch r.ctx.apolloTracing {
+	case ApolloTracingFormatFTV1:
+		key = literalFTV1
+		var encoded []byte
+		if encoded, err = r.ctx.federatedTraceEncoder.EncodeFederatedTrace(trace); err == nil {
+			data, err = json.Marshal(base64.StdEncoding.EncodeToString(encoded))
+		}
+	default:
+		key = literalApolloTracing
+		data, err = json.Marshal(newApolloTracing(trace))
+	}
+	if err != nil {
+		return err
+	}
+	r.printBytes(quote)
+	r.printBytes(key)
+	r.printBytes(quote)
+	r.printBytes(colon)
+	r.printBytes(data)
+	return nil
+}
+
+// apolloTracing is the "tracing" extension of the Apollo Tracing format
+type apolloTracing struct {
+	Version   int                    `json:"version"`
+	StartTime string                 `json:"startTime"`
+	EndTime   string                 `json:"endTime"`
+	Duration  int64                  `json:"duration"`
+	Execution apolloTracingExecution `json:"execution"`
+}
+
+type apolloTracingExecution struct {
+	Resolvers []apolloTracingResolver `json:"resolvers"`
+}
+
+// apolloTracingResolver are the timings of a root field of a fetch, the offset and the duration are in nanoseconds
+type apolloTracingResolver struct {
+	Path        []string `json:"path"`
+	ParentType  string   `json:"parentType"`
+	FieldName   string   `json:"fieldName"`
+	StartOffset int64    `json:"startOffset"`
+	Duration    int64    `json:"duration"`
+}
+
+func newApolloTrace(start, end time.Time, timings []FetchTiming) ApolloTrace {
+	trace := ApolloTrace{
+		Start: start,
+		End:   end,
+	}
+	for _, timing := range timings {
+		for _, field := range timing.RootFields {
+			trace.Resolvers = append(trace.Resolvers, ApolloTraceResolver{
+				Path:        append(apolloTracingPath(timing.Path), field.FieldName),
+				ParentType:  field.TypeName,
+				FieldName:   field.FieldName,
+				StartOffset: timing.Start.Sub(start),
+				Duration:    timing.Duration,
+			})
+		}
+	}
+	return trace
+}
+
+func newApolloTracing(trace ApolloTrace) apolloTracing {
+	tracing := apolloTracing{
+		Version:   apolloTracingVersion,
+		StartTime: trace.Start.UTC().Format(time.RFC3339Nano),
+		EndTime:   trace.End.UTC().Format(time.RFC3339Nano),
+		Duration:  trace.End.Sub(trace.Start).Nanoseconds(),
+		Execution: apolloTracingExecution{
+			Resolvers: make([]apolloTracingResolver, 0, len(trace.Resolvers)),
+		},
+	}
+	for _, resolver := range trace.Resolvers {
+		tracing.Execution.Resolvers = append(tracing.Execution.Resolvers, apolloTracingResolver{
+			Path:        resolver.Path,
+			ParentType:  resolver.ParentType,
+			FieldName:   resolver.FieldName,
+			StartOffset: resolver.StartOffset.Nanoseconds(),
+			Duration:    resolver.Duration.Nanoseconds(),
+		})
+	}
+	return tracing
+}
+
+// apolloTracingPath returns the response path of a fetch without the list items,
+// so the fetches of the items of a list are attributed to the list field
+func apolloTracingPath(path []string) []string {
+	out := make([]string, 0, len(path)+1)
+	for _, element := range path {
+		if element != "@" {
+			out = append(out, element)
+		}
+	}
+	return out
+}
@@ -0,0 +1,95 @@
+package resolve
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+func TestApolloTracing(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(10 * time.Millisecond)
+	timings := []FetchTiming{
+		{
+			DataSourceID: "users",
+			RootFields:   []GraphCoordinate{{TypeName: "Query", FieldName: "users"}},
+			Start:        start.Add(time.Millisecond),
+			FetchStats:   FetchStats{Duration: 2 * time.Millisecond},
+		},
+		{
+			DataSourceID: "reviews",
+			RootFields:   []GraphCoordinate{{TypeName: "User", FieldName: "reviews"}},
+			Path:         []string{"users", "@"},
+			Start:        start.Add(4 * time.Millisecond),
+			FetchStats:   FetchStats{Duration: 3 * time.Millisecond},
+		},
+	}
+
+	t.Run("legacy format", func(t *testing.T) {
+		out, err := json.Marshal(newApolloTracing(newApolloTrace(start, end, timings)))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"version": 1,
+			"startTime": "2024-01-01T12:00:00Z",
+			"endTime": "2024-01-01T12:00:00.01Z",
+			"duration": 10000000,
+			"execution": {"resolvers": [
+				{"path": ["users"], "parentType": "Query", "fieldName": "users", "startOffset": 1000000, "duration": 2000000},
+				{"path": ["users", "reviews"], "parentType": "User", "fieldName": "reviews", "startOffset": 4000000, "duration": 3000000}
+			]}
+		}`, string(out))
+	})
+
+	t.Run("trace of the federated trace encoder", func(t *testing.T) {
+		assert.Equal(t, ApolloTrace{
+			Start: start,
+			End:   end,
+			Resolvers: []ApolloTraceResolver{
+				{Path: []string{"users"}, ParentType: "Query", FieldName: "users", StartOffset: time.Millisecond, Duration: 2 * time.Millisecond},
+				{Path: []string{"users", "reviews"}, ParentType: "User", FieldName: "reviews", StartOffset: 4 * time.Millisecond, Duration: 3 * time.Millisecond},
+			},
+		}, newApolloTrace(start, end, timings))
+	})
+
+	t.Run("ftv1 format", func(t *testing.T) {
+		newResolvable := func(encoder FederatedTraceEncoder) *Resolvable {
+			ctx := NewContext(context.Background())
+			ctx.SetApolloTracing(ApolloTracingFormatFTV1, start)
+			ctx.SetFederatedTraceEncoder(encoder)
+			ctx.fetchTimings.add(timings[0])
+			return &Resolvable{ctx: ctx, out: &bytes.Buffer{}, operationType: ast.OperationTypeQuery}
+		}
+
+		t.Run("renders the encoded trace", func(t *testing.T) {
+			encoder := &fakeFederatedTraceEncoder{}
+			resolvable := newResolvable(encoder)
+			require.True(t, resolvable.hasApolloTracing())
+			require.NoError(t, resolvable.printApolloTracingExtension())
+			assert.Equal(t, `"ftv1":"dHJhY2U="`, resolvable.out.(*bytes.Buffer).String())
+			require.Len(t, encoder.traces, 1)
+			assert.Equal(t, []ApolloTraceResolver{
+				{Path: []string{"users"}, ParentType: "Query", FieldName: "users", StartOffset: time.Millisecond, Duration: 2 * time.Millisecond},
+			}, encoder.traces[0].Resolvers)
+		})
+
+		t.Run("renders no trace without an encoder", func(t *testing.T) {
+			assert.False(t, newResolvable(nil).hasApolloTracing())
+		})
+	})
+}
+
+type fakeFederatedTraceEncoder struct {
+	traces []ApolloTrace
+}
+
+func (f *fakeFederatedTraceEncoder) EncodeFederatedTrace(trace ApolloTrace) ([]byte, error) {
+	f.traces = append(f.traces, trace)
+	return []byte("trace"), nil
+}
@@ -0,0 +1,55 @@
+package resolve
+
+import (
+	"strconv"
+)
+
+const cacheControlHeader = "Cache-Control"
+
+type CacheControlScope int
+
+const (
+	CacheControlScopePublic CacheControlScope = iota
+	CacheControlScopePrivate
+)
+
+// CacheControl is the cache policy of a response computed by the planner from the @cacheControl hints of the fields
+type CacheControl struct {
+	// MaxAge in seconds is the minimum max age of all fields of the operation, 0 means the response must not be cached
+	MaxAge int
+	// Scope is private if any field of the operation has a private scope
+	Scope CacheControlScope
+}
+
+// Restrict merges a cache policy of a field into the policy of the response
+func (c *CacheControl) Restrict(maxAge int, scope CacheControlScope) {
+	if maxAge < c.MaxAge {
+		c.MaxAge = maxAge
+	}
+	if scope == CacheControlScopePrivate {
+		c.Scope = CacheControlScopePrivate
+	}
+}
+
+// HeaderValue renders the policy as value of the Cache-Control header
+func (c *CacheControl) HeaderValue() string {
+	if c.MaxAge <= 0 {
+		return "no-store"
+	}
+	if c.Scope == CacheControlScopePrivate {
+		return "max-age=" + strconv.Itoa(c.MaxAge) + ", private"
+	}
+	return "max-age=" + strconv.Itoa(c.MaxAge) + ", public"
+}
+
+// contributeCacheControl sets the Cache-Control response header, responses with errors must not be cached
+func (r *Resolver) contributeCacheControl(ctx *Context, response *GraphQLResponse, resolvable *Resolvable) {
+	if response.CacheControl == nil || ctx.responseHeaders == nil {
+		return
+	}
+	if resolvable.hasErrors() {
+		ctx.responseHeaders.Add(cacheControlHeader, "no-store")
+		return
+	}
+	ctx.responseHeaders.Add(cacheControlHeader, response.CacheControl.HeaderValue())
+}
@@ -0,0 +1,73 @@
+package resolve
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheControl_HeaderValue(t *testing.T) {
+	assert.Equal(t, "max-age=60, public", (&CacheControl{MaxAge: 60}).HeaderValue())
+	assert.Equal(t, "max-age=60, private", (&CacheControl{MaxAge: 60, Scope: CacheControlScopePrivate}).HeaderValue())
+	assert.Equal(t, "no-store", (&CacheControl{}).HeaderValue())
+}
+
+func TestResolver_CacheControl(t *testing.T) {
+	response := func(ctrl *gomock.Controller, data string) *GraphQLResponse {
+		mockDataSource := NewMockDataSource(ctrl)
+		mockDataSource.EXPECT().
+			Load(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&bytes.Buffer{})).
+			DoAndReturn(func(ctx context.Context, input []byte, w io.Writer) (err error) {
+				_, err = w.Write([]byte(data))
+				return
+			})
+		return &GraphQLResponse{
+			Data: &Object{
+				Fetch: &SingleFetch{
+					FetchConfiguration: FetchConfiguration{
+						DataSource: mockDataSource,
+						PostProcessing: PostProcessingConfiguration{
+							SelectResponseDataPath:   []string{"data"},
+							SelectResponseErrorsPath: []string{"errors"},
+						},
+					},
+				},
+				Fields: []*Field{
+					{
+						Name: []byte("name"),
+						Value: &String{
+							Path:     []string{"name"},
+							Nullable: true,
+						},
+					},
+				},
+			},
+			CacheControl: &CacheControl{MaxAge: 60},
+		}
+	}
+
+	t.Run("contributes the Cache-Control header", testFnWithPostEvaluation(func(t *testing.T, ctrl *gomock.Controller) (node *GraphQLResponse, ctx Context, expectedOutput string, postEvaluation func(t *testing.T)) {
+		headers := NewResponseHeaders(ResponseHeaderOptions{})
+		ctx = Context{ctx: context.Background()}
+		ctx.SetResponseHeaders(headers)
+
+		return response(ctrl, `{"data":{"name":"Jens"}}`), ctx, `{"data":{"name":"Jens"}}`, func(t *testing.T) {
+			assert.Equal(t, http.Header{"Cache-Control": {"max-age=60, public"}}, headers.Header())
+		}
+	}))
+
+	t.Run("responses with errors are not cached", testFnWithPostEvaluation(func(t *testing.T, ctrl *gomock.Controller) (node *GraphQLResponse, ctx Context, expectedOutput string, postEvaluation func(t *testing.T)) {
+		headers := NewResponseHeaders(ResponseHeaderOptions{})
+		ctx = Context{ctx: context.Background()}
+		ctx.SetResponseHeaders(headers)
+
+		return response(ctrl, `{"errors":[{"message":"failed"}],"data":{"name":null}}`), ctx, `{"errors":[{"message":"Failed to fetch from Subgraph at path 'query'.","extensions":{"errors":[{"message":"failed"}]}}],"data":{"name":null}}`, func(t *testing.T) {
+			assert.Equal(t, http.Header{"Cache-Control": {"no-store"}}, headers.Header())
+		}
+	}))
+}
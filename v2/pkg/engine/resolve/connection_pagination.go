@@ -0,0 +1,182 @@
+package resolve
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strconv"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astjson"
+)
+
+const (
+	connectionCursorPrefix = "connection:"
+)
+
+// ConnectionPagination slices the edges of a Relay-style connection in the resolver
+// It's used for origins which ignore pagination arguments and always return the full list of edges
+// Cursors are generated from the offset of an edge in the full list,
+// pageInfo is computed from the offsets of the returned slice
+// All field names are the names of the fields in the response, which means that aliases are already applied
+type ConnectionPagination struct {
+	// FirstVariableName is the name of the variable containing the "first" argument
+	FirstVariableName string
+	// AfterVariableName is the name of the variable containing the "after" argument
+	AfterVariableName string
+	// DefaultFirst is applied when the client omits the "first" argument, 0 means no limit
+	// A "first" argument of 0 returns an empty page, a negative "first" argument is an error
+	DefaultFirst int
+	// EdgesFieldName is the name of the list of edges in the connection object
+	EdgesFieldName string
+	// CursorFieldName is the name of the cursor field in an edge object
+	CursorFieldName string
+	// PageInfoFieldName is the name of the pageInfo object in the connection object
+	PageInfoFieldName        string
+	HasNextPageFieldName     string
+	HasPreviousPageFieldName string
+	StartCursorFieldName     string
+	EndCursorFieldName       string
+}
+
+// EncodeConnectionCursor returns the opaque cursor for the edge at the given offset
+func EncodeConnectionCursor(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(connectionCursorPrefix + strconv.Itoa(offset)))
+}
+
+// DecodeConnectionCursor returns the offset of a cursor created by EncodeConnectionCursor
+func DecodeConnectionCursor(cursor string) (offset int, ok bool) {
+	decoded, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, false
+	}
+	if !bytes.HasPrefix(decoded, []byte(connectionCursorPrefix)) {
+		return 0, false
+	}
+	offset, err = strconv.Atoi(string(decoded[len(connectionCursorPrefix):]))
+	if err != nil || offset < 0 {
+		return 0, false
+	}
+	return offset, true
+}
+
+// applyConnectionPagination slices the edges of the connection object,
+// it returns false and adds an error if the pagination arguments are invalid
+func (r *Resolvable) applyConnectionPagination(obj *Object, ref int) bool {
+	pagination := obj.Pagination
+
+	edgesField := obj.fieldByName(pagination.EdgesFieldName)
+	if edgesField == nil {
+		return true
+	}
+	edges := r.storage.Get(ref, edgesField.Value.NodePath())
+	if !r.storage.NodeIsDefined(edges) || r.storage.Nodes[edges].Kind != astjson.NodeKindArray {
+		return true
+	}
+
+	first := pagination.DefaultFirst
+	limited := first > 0
+	if value, ok := r.variableInt(pagination.FirstVariableName); ok {
+		first, limited = value, true
+	}
+	if first < 0 {
+		r.addError("Argument 'first' must not be negative.", nil)
+		return false
+	}
+
+	total := len(r.storage.Nodes[edges].ArrayValues)
+	start := 0
+	if after, ok := r.variableString(pagination.AfterVariableName); ok {
+		if offset, ok := DecodeConnectionCursor(after); ok {
+			// offset is compared before adding 1, so cursors with huge offsets can't overflow
+			start = total
+			if offset < total {
+				start = offset + 1
+			}
+		}
+	}
+	end := total
+	if limited && first < total-start {
+		end = start + first
+	}
+
+	r.storage.Nodes[edges].ArrayValues = r.storage.Nodes[edges].ArrayValues[start:end]
+
+	r.setConnectionEdgeCursors(pagination, edgesField, edges, start)
+	r.setConnectionPageInfo(obj, ref, start, end, total)
+	return true
+}
+
+func (r *Resolvable) setConnectionEdgeCursors(pagination *ConnectionPagination, edgesField *Field, edges, start int) {
+	array, ok := edgesField.Value.(*Array)
+	if !ok {
+		return
+	}
+	edge, ok := array.Item.(*Object)
+	if !ok {
+		return
+	}
+	cursorField := edge.fieldByName(pagination.CursorFieldName)
+	if cursorField == nil || len(cursorField.Value.NodePath()) != 1 {
+		return
+	}
+	key := cursorField.Value.NodePath()[0]
+	for i, edgeRef := range r.storage.Nodes[edges].ArrayValues {
+		if r.storage.Nodes[edgeRef].Kind != astjson.NodeKindObject {
+			continue
+		}
+		cursor := r.storage.AppendStringBytes([]byte(EncodeConnectionCursor(start + i)))
+		r.storage.SetObjectField(edgeRef, cursor, key)
+	}
+}
+
+func (r *Resolvable) setConnectionPageInfo(obj *Object, ref, start, end, total int) {
+	pagination := obj.Pagination
+	pageInfoField := obj.fieldByName(pagination.PageInfoFieldName)
+	if pageInfoField == nil || len(pageInfoField.Value.NodePath()) != 1 {
+		return
+	}
+	pageInfoObject, ok := pageInfoField.Value.(*Object)
+	if !ok {
+		return
+	}
+	pageInfo := r.storage.Get(ref, pageInfoField.Value.NodePath())
+	if !r.storage.NodeIsDefined(pageInfo) || r.storage.Nodes[pageInfo].Kind != astjson.NodeKindObject {
+		var err error
+		pageInfo, err = r.storage.AppendObject(emptyObject)
+		if err != nil {
+			return
+		}
+		r.storage.SetObjectField(ref, pageInfo, pageInfoField.Value.NodePath()[0])
+	}
+
+	r.setConnectionPageInfoField(pageInfoObject, pageInfo, pagination.HasNextPageFieldName, r.connectionBoolean(end < total))
+	r.setConnectionPageInfoField(pageInfoObject, pageInfo, pagination.HasPreviousPageFieldName, r.connectionBoolean(start > 0))
+	startCursor, endCursor := r.connectionNull(), r.connectionNull()
+	if end > start {
+		startCursor = r.storage.AppendStringBytes([]byte(EncodeConnectionCursor(start)))
+		endCursor = r.storage.AppendStringBytes([]byte(EncodeConnectionCursor(end - 1)))
+	}
+	r.setConnectionPageInfoField(pageInfoObject, pageInfo, pagination.StartCursorFieldName, startCursor)
+	r.setConnectionPageInfoField(pageInfoObject, pageInfo, pagination.EndCursorFieldName, endCursor)
+}
+
+func (r *Resolvable) setConnectionPageInfoField(pageInfoObject *Object, pageInfo int, fieldName string, value int) {
+	field := pageInfoObject.fieldByName(fieldName)
+	if field == nil || len(field.Value.NodePath()) != 1 {
+		return
+	}
+	r.storage.SetObjectField(pageInfo, value, field.Value.NodePath()[0])
+}
+
+func (r *Resolvable) connectionBoolean(value bool) int {
+	if value {
+		ref, _ := r.storage.AppendAnyJSONBytes(literalTrue)
+		return ref
+	}
+	ref, _ := r.storage.AppendAnyJSONBytes(literalFalse)
+	return ref
+}
+
+func (r *Resolvable) connectionNull() int {
+	ref, _ := r.storage.AppendAnyJSONBytes(null)
+	return ref
+}
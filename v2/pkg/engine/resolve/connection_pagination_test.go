@@ -0,0 +1,161 @@
+package resolve
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+func TestConnectionCursor(t *testing.T) {
+	offset, ok := DecodeConnectionCursor(EncodeConnectionCursor(42))
+	assert.True(t, ok)
+	assert.Equal(t, 42, offset)
+
+	_, ok = DecodeConnectionCursor("invalid")
+	assert.False(t, ok)
+}
+
+func TestResolvable_ConnectionPagination(t *testing.T) {
+	data := `{"users":{"edges":[{"node":{"id":"1"}},{"node":{"id":"2"}},{"node":{"id":"3"}},{"node":{"id":"4"}}]}}`
+
+	connection := func(pagination *ConnectionPagination) *Object {
+		return &Object{
+			Fields: []*Field{
+				{
+					Name: []byte("users"),
+					Value: &Object{
+						Path:       []string{"users"},
+						Pagination: pagination,
+						Fields: []*Field{
+							{
+								Name: []byte("edges"),
+								Value: &Array{
+									Path: []string{"edges"},
+									Item: &Object{
+										Fields: []*Field{
+											{
+												Name:  []byte("cursor"),
+												Value: &String{Path: []string{"cursor"}},
+											},
+											{
+												Name: []byte("node"),
+												Value: &Object{
+													Path: []string{"node"},
+													Fields: []*Field{
+														{
+															Name:  []byte("id"),
+															Value: &String{Path: []string{"id"}},
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+							{
+								Name: []byte("pageInfo"),
+								Value: &Object{
+									Path: []string{"pageInfo"},
+									Fields: []*Field{
+										{
+											Name:  []byte("hasNextPage"),
+											Value: &Boolean{Path: []string{"hasNextPage"}},
+										},
+										{
+											Name:  []byte("hasPreviousPage"),
+											Value: &Boolean{Path: []string{"hasPreviousPage"}},
+										},
+										{
+											Name:  []byte("endCursor"),
+											Value: &String{Path: []string{"endCursor"}, Nullable: true},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	pagination := func() *ConnectionPagination {
+		return &ConnectionPagination{
+			FirstVariableName:        "first",
+			AfterVariableName:        "after",
+			EdgesFieldName:           "edges",
+			CursorFieldName:          "cursor",
+			PageInfoFieldName:        "pageInfo",
+			HasNextPageFieldName:     "hasNextPage",
+			HasPreviousPageFieldName: "hasPreviousPage",
+			EndCursorFieldName:       "endCursor",
+		}
+	}
+
+	resolve := func(t *testing.T, variables string, root *Object) string {
+		res := NewResolvable()
+		ctx := &Context{
+			Variables: []byte(variables),
+		}
+		err := res.Init(ctx, []byte(data), ast.OperationTypeQuery)
+		require.NoError(t, err)
+		out := &bytes.Buffer{}
+		err = res.Resolve(context.Background(), root, out)
+		require.NoError(t, err)
+		return out.String()
+	}
+
+	t.Run("first page", func(t *testing.T) {
+		out := resolve(t, `{"first":2}`, connection(pagination()))
+		expected := fmt.Sprintf(`{"data":{"users":{"edges":[{"cursor":"%s","node":{"id":"1"}},{"cursor":"%s","node":{"id":"2"}}],"pageInfo":{"hasNextPage":true,"hasPreviousPage":false,"endCursor":"%s"}}}}`,
+			EncodeConnectionCursor(0), EncodeConnectionCursor(1), EncodeConnectionCursor(1))
+		assert.Equal(t, expected, out)
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		out := resolve(t, fmt.Sprintf(`{"first":2,"after":"%s"}`, EncodeConnectionCursor(1)), connection(pagination()))
+		expected := fmt.Sprintf(`{"data":{"users":{"edges":[{"cursor":"%s","node":{"id":"3"}},{"cursor":"%s","node":{"id":"4"}}],"pageInfo":{"hasNextPage":false,"hasPreviousPage":true,"endCursor":"%s"}}}}`,
+			EncodeConnectionCursor(2), EncodeConnectionCursor(3), EncodeConnectionCursor(3))
+		assert.Equal(t, expected, out)
+	})
+
+	t.Run("after last edge", func(t *testing.T) {
+		out := resolve(t, fmt.Sprintf(`{"after":"%s"}`, EncodeConnectionCursor(3)), connection(pagination()))
+		assert.Equal(t, `{"data":{"users":{"edges":[],"pageInfo":{"hasNextPage":false,"hasPreviousPage":true,"endCursor":null}}}}`, out)
+	})
+
+	t.Run("default first", func(t *testing.T) {
+		p := pagination()
+		p.DefaultFirst = 3
+		out := resolve(t, `{}`, connection(p))
+		expected := fmt.Sprintf(`{"data":{"users":{"edges":[{"cursor":"%s","node":{"id":"1"}},{"cursor":"%s","node":{"id":"2"}},{"cursor":"%s","node":{"id":"3"}}],"pageInfo":{"hasNextPage":true,"hasPreviousPage":false,"endCursor":"%s"}}}}`,
+			EncodeConnectionCursor(0), EncodeConnectionCursor(1), EncodeConnectionCursor(2), EncodeConnectionCursor(2))
+		assert.Equal(t, expected, out)
+	})
+
+	t.Run("first of zero", func(t *testing.T) {
+		p := pagination()
+		p.DefaultFirst = 3
+		out := resolve(t, `{"first":0}`, connection(p))
+		assert.Equal(t, `{"data":{"users":{"edges":[],"pageInfo":{"hasNextPage":true,"hasPreviousPage":false,"endCursor":null}}}}`, out)
+	})
+
+	t.Run("negative first", func(t *testing.T) {
+		out := resolve(t, `{"first":-1}`, connection(pagination()))
+		assert.Equal(t, `{"errors":[{"message":"Argument 'first' must not be negative.","path":["users"]}],"data":null}`, out)
+	})
+
+	t.Run("after cursor with overflowing offset", func(t *testing.T) {
+		after := base64.StdEncoding.EncodeToString([]byte("connection:9223372036854775807"))
+		out := resolve(t, fmt.Sprintf(`{"first":2,"after":"%s"}`, after), connection(pagination()))
+		assert.Equal(t, `{"data":{"users":{"edges":[],"pageInfo":{"hasNextPage":false,"hasPreviousPage":true,"endCursor":null}}}}`, out)
+	})
+}
@@ -22,6 +22,7 @@ var (
 	literalTrace         = []byte("trace")
 	literalRateLimit     = []byte("rateLimit")
 	literalAuthorization = []byte("authorization")
+	literalSubgraphs     = []byte("subgraphs")
 
 	emptyArray  = []byte("[]")
 	emptyObject = []byte("{}")
@@ -22,8 +22,20 @@ type Context struct {
 	Extensions       []byte
 	Stats            Stats
 
-	authorizer  Authorizer
-	rateLimiter RateLimiter
+	authorizer            Authorizer
+	rateLimiter           RateLimiter
+	mutationRollbackHook  MutationRollbackHook
+	maskPII               bool
+	fieldValueTransformer FieldValueTransformer
+	// subscriptionUpdateTimeout overrides ResolverOptions.SubscriptionUpdateTimeout for the subscription
+	subscriptionUpdateTimeout time.Duration
+	subscriptionCoalescing    SubscriptionCoalescing
+	responseHeaders           *ResponseHeaders
+	fetchTimings              *FetchTimings
+	profilerLabels            bool
+	apolloTracing             ApolloTracingFormat
+	apolloTracingStart        time.Time
+	federatedTraceEncoder     FederatedTraceEncoder
 
 	subgraphErrors error
 }
@@ -55,6 +67,11 @@ func (c *Context) SetAuthorizer(authorizer Authorizer) {
 	c.authorizer = authorizer
 }
 
+// SetMutationRollbackHook sets the hook which is called with the successful top level mutations of the operation if one of them failed
+func (c *Context) SetMutationRollbackHook(hook MutationRollbackHook) {
+	c.mutationRollbackHook = hook
+}
+
 type RateLimitOptions struct {
 	// Enable switches rate limiting on or off
 	Enable bool
@@ -77,6 +94,12 @@ type RateLimiter interface {
 	RenderResponseExtension(ctx *Context, out io.Writer) error
 }
 
+// SetSubscriptionUpdateTimeout sets the deadline for resolving a single event of the subscription,
+// it overrides ResolverOptions.SubscriptionUpdateTimeout
+func (c *Context) SetSubscriptionUpdateTimeout(timeout time.Duration) {
+	c.subscriptionUpdateTimeout = timeout
+}
+
 func (c *Context) SetRateLimiter(limiter RateLimiter) {
 	c.rateLimiter = limiter
 }
@@ -151,6 +174,17 @@ func (c *Context) Free() {
 	c.Stats.Reset()
 	c.subgraphErrors = nil
 	c.authorizer = nil
+	c.mutationRollbackHook = nil
+	c.maskPII = false
+	c.fieldValueTransformer = nil
+	c.subscriptionUpdateTimeout = 0
+	c.subscriptionCoalescing = SubscriptionCoalescing{}
+	c.responseHeaders = nil
+	c.fetchTimings = nil
+	c.profilerLabels = false
+	c.apolloTracing = ApolloTracingFormatNone
+	c.apolloTracingStart = time.Time{}
+	c.federatedTraceEncoder = nil
 }
 
 type traceStartKey struct{}
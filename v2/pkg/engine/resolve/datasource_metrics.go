@@ -0,0 +1,101 @@
+package resolve
+
+import (
+	"sync"
+	"time"
+)
+
+// DataSourceMetrics receives the stats of each fetch, e.g. to export them to a metrics backend
+// The fetches are attributed to the DataSourceID of their FetchInfo, so the plans have to be created with IncludeInfo
+type DataSourceMetrics interface {
+	// RecordFetch is called concurrently after each fetch
+	RecordFetch(dataSourceID string, stats FetchStats)
+}
+
+// FetchStats are the sizes and the duration of a single fetch
+type FetchStats struct {
+	// RequestBytes is the size of the input sent to the datasource
+	RequestBytes int
+	// ResponseBytes is the size of the response of the datasource
+	ResponseBytes int
+	Duration      time.Duration
+	// Failed is true if the datasource returned an error
+	Failed bool
+}
+
+// DataSourceTelemetry is a DataSourceMetrics which aggregates the stats per datasource in memory,
+// e.g. for the attribution of the traffic to the teams owning the subgraphs
+type DataSourceTelemetry struct {
+	mu          sync.Mutex
+	since       time.Time
+	dataSources map[string]*DataSourceTelemetrySnapshot
+	now         func() time.Time
+}
+
+// DataSourceTelemetrySnapshot are the counters of a datasource within the period of a snapshot
+type DataSourceTelemetrySnapshot struct {
+	Requests      uint64
+	Errors        uint64
+	RequestBytes  uint64
+	ResponseBytes uint64
+	// Duration is the total duration of the requests
+	Duration time.Duration
+	// RequestsPerSecond is the average rate of the requests within the period
+	RequestsPerSecond float64
+}
+
+func NewDataSourceTelemetry() *DataSourceTelemetry {
+	return &DataSourceTelemetry{
+		since:       time.Now(),
+		dataSources: map[string]*DataSourceTelemetrySnapshot{},
+		now:         time.Now,
+	}
+}
+
+func (t *DataSourceTelemetry) RecordFetch(dataSourceID string, stats FetchStats) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	dataSource, ok := t.dataSources[dataSourceID]
+	if !ok {
+		dataSource = &DataSourceTelemetrySnapshot{}
+		t.dataSources[dataSourceID] = dataSource
+	}
+	dataSource.Requests++
+	if stats.Failed {
+		dataSource.Errors++
+	}
+	dataSource.RequestBytes += uint64(stats.RequestBytes)
+	dataSource.ResponseBytes += uint64(stats.ResponseBytes)
+	dataSource.Duration += stats.Duration
+}
+
+// Snapshot returns the counters per datasource id since the creation or the last Reset
+func (t *DataSourceTelemetry) Snapshot() map[string]DataSourceTelemetrySnapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.snapshotLocked()
+}
+
+// Reset returns the snapshot and starts a new period, e.g. to export the counters in intervals
+func (t *DataSourceTelemetry) Reset() map[string]DataSourceTelemetrySnapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	snapshot := t.snapshotLocked()
+	t.since = t.now()
+	t.dataSources = map[string]*DataSourceTelemetrySnapshot{}
+	return snapshot
+}
+
+func (t *DataSourceTelemetry) snapshotLocked() map[string]DataSourceTelemetrySnapshot {
+	period := t.now().Sub(t.since).Seconds()
+	snapshot := make(map[string]DataSourceTelemetrySnapshot, len(t.dataSources))
+	for id, dataSource := range t.dataSources {
+		counters := *dataSource
+		if period > 0 {
+			counters.RequestsPerSecond = float64(counters.Requests) / period
+		}
+		snapshot[id] = counters
+	}
+	return snapshot
+}
@@ -0,0 +1,35 @@
+package resolve
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataSourceTelemetry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	telemetry := NewDataSourceTelemetry()
+	telemetry.since = now
+	telemetry.now = func() time.Time {
+		return now
+	}
+
+	telemetry.RecordFetch("products", FetchStats{RequestBytes: 100, ResponseBytes: 1000, Duration: 10 * time.Millisecond})
+	telemetry.RecordFetch("products", FetchStats{RequestBytes: 50, ResponseBytes: 20, Duration: 5 * time.Millisecond, Failed: true})
+	telemetry.RecordFetch("reviews", FetchStats{RequestBytes: 10, ResponseBytes: 30, Duration: time.Millisecond})
+
+	now = now.Add(10 * time.Second)
+	expected := map[string]DataSourceTelemetrySnapshot{
+		"products": {Requests: 2, Errors: 1, RequestBytes: 150, ResponseBytes: 1020, Duration: 15 * time.Millisecond, RequestsPerSecond: 0.2},
+		"reviews":  {Requests: 1, RequestBytes: 10, ResponseBytes: 30, Duration: time.Millisecond, RequestsPerSecond: 0.1},
+	}
+	assert.Equal(t, expected, telemetry.Snapshot())
+	assert.Equal(t, expected, telemetry.Reset())
+
+	now = now.Add(time.Second)
+	telemetry.RecordFetch("reviews", FetchStats{RequestBytes: 10, ResponseBytes: 30})
+	assert.Equal(t, map[string]DataSourceTelemetrySnapshot{
+		"reviews": {Requests: 1, RequestBytes: 10, ResponseBytes: 30, RequestsPerSecond: 1},
+	}, telemetry.Snapshot())
+}
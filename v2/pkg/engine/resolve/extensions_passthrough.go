@@ -0,0 +1,46 @@
+package resolve
+
+// ExtensionsPassthrough merges selected keys of the extensions of a subgraph response into the extensions of the response
+// The values are rendered namespaced by subgraph, e.g. {"extensions":{"subgraphs":{"accounts":{"cost":3}}}}
+// If a subgraph is fetched multiple times, objects are merged and other values are overwritten by the latest fetch
+type ExtensionsPassthrough struct {
+	// Namespace is the key under which the extensions of the subgraph are rendered, usually the name of the subgraph
+	Namespace string
+	// Keys of the extensions object of the subgraph response which are passed through, missing keys are ignored
+	Keys []string
+}
+
+// mergeExtensions copies the configured keys of the extensions of the subgraph response into the subgraph extensions
+func (l *Loader) mergeExtensions(res *result, response int) {
+	passthrough := res.postProcessing.ExtensionsPassthrough
+	extensions := l.data.GetObjectField(response, "extensions")
+	if !l.data.NodeIsDefined(extensions) {
+		return
+	}
+	for _, key := range passthrough.Keys {
+		value := l.data.GetObjectField(extensions, key)
+		if !l.data.NodeIsDefined(value) {
+			continue
+		}
+		namespace := l.data.GetObjectField(l.subgraphExtensionsRoot, passthrough.Namespace)
+		if namespace == -1 {
+			namespace, _ = l.data.AppendObject(emptyObject)
+			_ = l.data.SetObjectField(l.subgraphExtensionsRoot, namespace, passthrough.Namespace)
+		}
+		existing := l.data.GetObjectField(namespace, key)
+		_ = l.data.SetObjectField(namespace, l.data.MergeNodes(existing, value), key)
+	}
+}
+
+func (r *Resolvable) hasSubgraphExtensions() bool {
+	return r.storage.NodeIsDefined(r.subgraphExtensionsRoot) &&
+		len(r.storage.Nodes[r.subgraphExtensionsRoot].ObjectFields) > 0
+}
+
+func (r *Resolvable) printSubgraphExtensions() {
+	r.printBytes(quote)
+	r.printBytes(literalSubgraphs)
+	r.printBytes(quote)
+	r.printBytes(colon)
+	r.printNode(r.subgraphExtensionsRoot)
+}
@@ -60,6 +60,12 @@ type PostProcessingConfiguration struct {
 	// In this case, the result would be {"a":1,"foo":"bar"}
 	// This is useful if you make multiple fetches, e.g. parallel fetches, that would otherwise overwrite each other
 	MergePath []string
+	// ScalarTransformations are applied to the response data before it's merged
+	ScalarTransformations []ScalarTransformation
+	// InvalidUTF8Policy is applied to the raw response before it's parsed
+	InvalidUTF8Policy InvalidUTF8Policy
+	// ExtensionsPassthrough selects extensions of the response which are passed through to the client
+	ExtensionsPassthrough *ExtensionsPassthrough
 }
 
 func (_ *SingleFetch) FetchKind() FetchKind {
@@ -173,6 +179,14 @@ type FetchInfo struct {
 	DataSourceID  string
 	RootFields    []GraphCoordinate
 	OperationType ast.OperationType
+	// EntityKeys are the keys chosen by the planner to fetch the entities, they are only set for entity fetches
+	EntityKeys []EntityKey
+}
+
+// EntityKey is the selection set of a @key of an entity
+type EntityKey struct {
+	TypeName     string `json:"typeName"`
+	SelectionSet string `json:"selectionSet"`
 }
 
 type GraphCoordinate struct {
@@ -0,0 +1,42 @@
+package resolve
+
+import (
+	"sync"
+	"time"
+)
+
+// FetchTiming are the stats of a fetch of an operation
+type FetchTiming struct {
+	DataSourceID string
+	// RootFields are the fields of the fetch
+	RootFields []GraphCoordinate
+	// Path is the response path of the object the fetch is resolved for, items of lists are "@"
+	Path []string
+	// Start is the time the datasource was called
+	Start time.Time
+	FetchStats
+}
+
+// FetchTimings collects the stats of all fetches of an operation, see Context.SetFetchTimings
+type FetchTimings struct {
+	mu      sync.Mutex
+	timings []FetchTiming
+}
+
+func (t *FetchTimings) add(timing FetchTiming) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.timings = append(t.timings, timing)
+}
+
+// Timings returns the stats of the completed fetches in the order of their completion
+func (t *FetchTimings) Timings() []FetchTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]FetchTiming(nil), t.timings...)
+}
+
+// SetFetchTimings collects the stats of the fetches of the operation, e.g. to break down the duration of slow operations
+func (c *Context) SetFetchTimings(timings *FetchTimings) {
+	c.fetchTimings = timings
+}
@@ -0,0 +1,34 @@
+package resolve
+
+import (
+	"context"
+)
+
+// FetchTracer traces the fetches of the loader, e.g. to record a span per request to a subgraph
+type FetchTracer interface {
+	// StartFetch is called concurrently before each fetch. The returned context is passed to the datasource,
+	// so the datasource is able to propagate the trace to the upstream, see httpclient.WithRequestHeaders.
+	// end is called with the stats of the fetch when the datasource returned
+	StartFetch(ctx context.Context, info FetchTraceInfo) (fetchCtx context.Context, end func(stats FetchStats))
+}
+
+// FetchTraceInfo describes a traced fetch
+type FetchTraceInfo struct {
+	// DataSourceID is empty if the plan doesn't include the info about the datasources of the fetches
+	DataSourceID string
+	// Kind is FetchKindSingle, FetchKindEntity or FetchKindEntityBatch
+	Kind FetchKind
+	// RootFields are the fields of the fetch, they are only set if the plan includes the info about the datasources
+	RootFields []GraphCoordinate
+}
+
+func newFetchTraceInfo(kind FetchKind, info *FetchInfo) FetchTraceInfo {
+	traceInfo := FetchTraceInfo{
+		Kind: kind,
+	}
+	if info != nil {
+		traceInfo.DataSourceID = info.DataSourceID
+		traceInfo.RootFields = info.RootFields
+	}
+	return traceInfo
+}
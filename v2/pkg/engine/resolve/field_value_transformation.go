@@ -0,0 +1,114 @@
+package resolve
+
+import (
+	"fmt"
+
+	"github.com/buger/jsonparser"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astjson"
+)
+
+// FieldValueTransformer encodes the values of fields in the response and decodes the values clients pass back as arguments,
+// e.g. to sign urls or to encrypt ids into opaque cursors.
+// Values are raw json, the returned values have to be valid json.
+type FieldValueTransformer interface {
+	// Encode transforms the resolved value of the field at the coordinate before it's written to the response
+	Encode(ctx *Context, coordinate GraphCoordinate, value []byte) ([]byte, error)
+	// Decode transforms the value passed to the argument at the coordinate before the datasources are called
+	Decode(ctx *Context, coordinate ArgumentCoordinate, value []byte) ([]byte, error)
+}
+
+// ArgumentCoordinate is the schema coordinate of a field argument, e.g. Query.user(id:)
+type ArgumentCoordinate struct {
+	TypeName     string
+	FieldName    string
+	ArgumentName string
+}
+
+// DecodeVariable is a variable of the operation passed to an argument whose value is decoded
+type DecodeVariable struct {
+	VariableName string
+	Coordinate   ArgumentCoordinate
+}
+
+// SetFieldValueTransformer sets the transformer encoding the values of fields with an EncodeCoordinate
+// and decoding the variables passed to arguments with decoded values
+func (c *Context) SetFieldValueTransformer(transformer FieldValueTransformer) {
+	c.fieldValueTransformer = transformer
+}
+
+// decodeVariables decodes the variables of the context passed to arguments with decoded values
+// the variables are replaced before they are rendered into the inputs of the fetches
+func (c *Context) decodeVariables(decodeVariables []DecodeVariable) error {
+	if c.fieldValueTransformer == nil || len(decodeVariables) == 0 || len(c.Variables) == 0 {
+		return nil
+	}
+	for i := range decodeVariables {
+		value, dataType, _, err := jsonparser.Get(c.Variables, decodeVariables[i].VariableName)
+		if err != nil || dataType == jsonparser.Null {
+			continue
+		}
+		if dataType == jsonparser.String {
+			// jsonparser returns strings without quotes
+			value = append(append(append(make([]byte, 0, len(value)+2), quote...), value...), quote...)
+		}
+		decoded, err := c.fieldValueTransformer.Decode(c, decodeVariables[i].Coordinate, value)
+		if err != nil {
+			coordinate := decodeVariables[i].Coordinate
+			return fmt.Errorf("failed to decode argument %s.%s(%s:): %w", coordinate.TypeName, coordinate.FieldName, coordinate.ArgumentName, err)
+		}
+		variables, err := jsonparser.Set(c.Variables, decoded, decodeVariables[i].VariableName)
+		if err != nil {
+			return err
+		}
+		c.Variables = variables
+	}
+	return nil
+}
+
+func (r *Resolvable) encodeField(ref int, field *Field) {
+	if field.EncodeCoordinate == nil || r.ctx.fieldValueTransformer == nil {
+		return
+	}
+	value := r.storage.Get(ref, field.Value.NodePath())
+	if !r.storage.NodeIsDefined(value) || r.storage.Nodes[value].Kind == astjson.NodeKindNull {
+		return
+	}
+
+	raw := r.storage.Nodes[value].ValueBytes(r.storage)
+	if r.storage.Nodes[value].Kind == astjson.NodeKindString {
+		// string values are stored without quotes
+		raw = append(append(append(make([]byte, 0, len(raw)+2), quote...), raw...), quote...)
+	}
+	encoded, err := r.ctx.fieldValueTransformer.Encode(r.ctx, *field.EncodeCoordinate, raw)
+	if err == nil {
+		err = r.replaceNodeValue(value, encoded)
+	}
+	if err != nil {
+		r.addEncodeFieldError(field)
+		r.storage.Nodes[value].Kind = astjson.NodeKindNull
+	}
+}
+
+func (r *Resolvable) replaceNodeValue(node int, value []byte) error {
+	if len(value) >= 2 && value[0] == '"' {
+		ref := r.storage.AppendStringBytes(value[1 : len(value)-1])
+		r.storage.Nodes[node] = r.storage.Nodes[ref]
+		return nil
+	}
+	ref, err := r.storage.AppendAnyJSONBytes(value)
+	if err != nil {
+		return err
+	}
+	r.storage.Nodes[node] = r.storage.Nodes[ref]
+	return nil
+}
+
+func (r *Resolvable) addEncodeFieldError(field *Field) {
+	nodePath := field.Value.NodePath()
+	r.pushNodePathElement(nodePath)
+	message := fmt.Sprintf("Failed to encode value of field '%s'.", r.renderFieldPath())
+	ref := r.storage.AppendErrorWithMessage(message, r.path)
+	r.storage.Nodes[r.errorsRoot].ArrayValues = append(r.storage.Nodes[r.errorsRoot].ArrayValues, ref)
+	r.popNodePathElement(nodePath)
+}
@@ -0,0 +1,118 @@
+package resolve
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+// base64Transformer encodes string values into opaque base64 strings
+type base64Transformer struct{}
+
+func (base64Transformer) Encode(ctx *Context, coordinate GraphCoordinate, value []byte) ([]byte, error) {
+	if coordinate.FieldName == "broken" {
+		return nil, errors.New("broken")
+	}
+	str, err := strconv.Unquote(string(value))
+	if err != nil {
+		return nil, err
+	}
+	return strconv.AppendQuote(nil, base64.StdEncoding.EncodeToString([]byte(coordinate.TypeName+":"+str))), nil
+}
+
+func (base64Transformer) Decode(ctx *Context, coordinate ArgumentCoordinate, value []byte) ([]byte, error) {
+	str, err := strconv.Unquote(string(value))
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(str)
+	if err != nil {
+		return nil, err
+	}
+	return strconv.AppendQuote(nil, string(bytes.TrimPrefix(decoded, []byte("User:")))), nil
+}
+
+func TestResolvable_EncodeField(t *testing.T) {
+	data := `{"user":{"id":"1","name":"Jens","broken":"2"}}`
+	object := &Object{
+		Fields: []*Field{
+			{
+				Name: []byte("user"),
+				Value: &Object{
+					Path:     []string{"user"},
+					Nullable: true,
+					Fields: []*Field{
+						{
+							Name:             []byte("id"),
+							Value:            &String{Path: []string{"id"}},
+							EncodeCoordinate: &GraphCoordinate{TypeName: "User", FieldName: "id"},
+						},
+						{
+							Name:  []byte("name"),
+							Value: &String{Path: []string{"name"}},
+						},
+						{
+							Name:             []byte("broken"),
+							Value:            &String{Path: []string{"broken"}, Nullable: true},
+							EncodeCoordinate: &GraphCoordinate{TypeName: "User", FieldName: "broken"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	resolve := func(t *testing.T, transformer FieldValueTransformer) string {
+		ctx := NewContext(context.Background())
+		ctx.SetFieldValueTransformer(transformer)
+		res := NewResolvable()
+		require.NoError(t, res.Init(ctx, []byte(data), ast.OperationTypeQuery))
+		out := &bytes.Buffer{}
+		require.NoError(t, res.Resolve(context.Background(), object, out))
+		return out.String()
+	}
+
+	t.Run("should not encode without transformer", func(t *testing.T) {
+		assert.Equal(t, `{"data":{"user":{"id":"1","name":"Jens","broken":"2"}}}`, resolve(t, nil))
+	})
+
+	t.Run("should encode fields with coordinate", func(t *testing.T) {
+		assert.Equal(t, `{"errors":[{"message":"Failed to encode value of field 'Query.user.broken'.","path":["user","broken"]}],"data":{"user":{"id":"VXNlcjox","name":"Jens","broken":null}}}`, resolve(t, base64Transformer{}))
+	})
+}
+
+func TestContext_DecodeVariables(t *testing.T) {
+	decodeVariables := []DecodeVariable{
+		{VariableName: "id", Coordinate: ArgumentCoordinate{TypeName: "Query", FieldName: "user", ArgumentName: "id"}},
+		{VariableName: "missing", Coordinate: ArgumentCoordinate{TypeName: "Query", FieldName: "user", ArgumentName: "id"}},
+	}
+
+	t.Run("should decode variables", func(t *testing.T) {
+		variables := []byte(`{"id":"VXNlcjox","name":"Jens"}`)
+		ctx := NewContext(context.Background())
+		ctx.Variables = variables
+		ctx.SetFieldValueTransformer(base64Transformer{})
+
+		require.NoError(t, ctx.decodeVariables(decodeVariables))
+		assert.Equal(t, `{"id":"1","name":"Jens"}`, string(ctx.Variables))
+		assert.Equal(t, `{"id":"VXNlcjox","name":"Jens"}`, string(variables), "the variables of the request are not modified")
+	})
+
+	t.Run("should return decode errors", func(t *testing.T) {
+		ctx := NewContext(context.Background())
+		ctx.Variables = []byte(`{"id":"not base64"}`)
+		ctx.SetFieldValueTransformer(base64Transformer{})
+
+		err := ctx.decodeVariables(decodeVariables)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to decode argument Query.user(id:)")
+	})
+}
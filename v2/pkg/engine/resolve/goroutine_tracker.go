@@ -0,0 +1,126 @@
+package resolve
+
+import (
+	"context"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+)
+
+// GoroutineTracker tracks the goroutines started for operations and subscriptions, see ResolverOptions.GoroutineTracker.
+// Goroutines which are still running after their context is done for longer than the grace period are reported as leaks
+// together with the stack trace of their creation.
+// The stack trace is captured for every goroutine, so the tracker is meant for debugging and not for production traffic.
+type GoroutineTracker struct {
+	gracePeriod time.Duration
+
+	mu         sync.Mutex
+	nextID     uint64
+	goroutines map[uint64]*trackedGoroutine
+}
+
+// GoroutineLeak is a goroutine which is still running after its context is done
+type GoroutineLeak struct {
+	// Name describes the task of the goroutine, e.g. "subscription update"
+	Name string `json:"name"`
+	// StartedAt is the time the goroutine was started
+	StartedAt time.Time `json:"startedAt"`
+	// DoneAt is the time the context of the goroutine was done
+	DoneAt time.Time `json:"doneAt"`
+	// Stack is the stack trace of the goroutine which started the leaked goroutine
+	Stack string `json:"stack"`
+}
+
+type trackedGoroutine struct {
+	name      string
+	startedAt time.Time
+	doneAt    time.Time
+	stack     []byte
+}
+
+// NewGoroutineTracker creates a tracker which reports the goroutines running longer than gracePeriod after their context is done
+func NewGoroutineTracker(gracePeriod time.Duration) *GoroutineTracker {
+	return &GoroutineTracker{
+		gracePeriod: gracePeriod,
+		goroutines:  map[uint64]*trackedGoroutine{},
+	}
+}
+
+// Track registers a goroutine owned by ctx, the returned function has to be called when the goroutine returns.
+// Track has to be called before the goroutine is started, so the stack trace shows where it is started.
+// Track of a nil tracker returns a no-op function.
+func (t *GoroutineTracker) Track(ctx context.Context, name string) (done func()) {
+	if t == nil {
+		return func() {}
+	}
+	goroutine := &trackedGoroutine{
+		name:      name,
+		startedAt: time.Now(),
+		stack:     debug.Stack(),
+	}
+
+	t.mu.Lock()
+	t.nextID++
+	id := t.nextID
+	t.goroutines[id] = goroutine
+	t.mu.Unlock()
+
+	stop := context.AfterFunc(ctx, func() {
+		t.mu.Lock()
+		goroutine.doneAt = time.Now()
+		t.mu.Unlock()
+	})
+	return func() {
+		stop()
+		t.mu.Lock()
+		delete(t.goroutines, id)
+		t.mu.Unlock()
+	}
+}
+
+// Running returns the number of tracked goroutines which did not return yet
+func (t *GoroutineTracker) Running() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.goroutines)
+}
+
+// Leaks returns the goroutines which are running longer than the grace period after their context is done, the oldest first
+func (t *GoroutineTracker) Leaks() []GoroutineLeak {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var leaks []GoroutineLeak
+	for _, goroutine := range t.goroutines {
+		if goroutine.doneAt.IsZero() || time.Since(goroutine.doneAt) < t.gracePeriod {
+			continue
+		}
+		leaks = append(leaks, GoroutineLeak{
+			Name:      goroutine.name,
+			StartedAt: goroutine.startedAt,
+			DoneAt:    goroutine.doneAt,
+			Stack:     string(goroutine.stack),
+		})
+	}
+	slices.SortFunc(leaks, func(a, b GoroutineLeak) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+	return leaks
+}
+
+type goroutineTrackerKey struct{}
+
+// TrackGoroutine registers a goroutine owned by ctx with the tracker of the resolver, see GoroutineTracker.Track.
+// The contexts of subscription triggers carry the tracker, so datasources are able to track the goroutines of their subscriptions.
+// Without a tracker it returns a no-op function.
+func TrackGoroutine(ctx context.Context, name string) (done func()) {
+	tracker, _ := ctx.Value(goroutineTrackerKey{}).(*GoroutineTracker)
+	return tracker.Track(ctx, name)
+}
+
+func withGoroutineTracker(ctx context.Context, tracker *GoroutineTracker) context.Context {
+	if tracker == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, goroutineTrackerKey{}, tracker)
+}
@@ -0,0 +1,66 @@
+package resolve
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoroutineTracker(t *testing.T) {
+	t.Run("goroutines returning after their context is done are no leaks", func(t *testing.T) {
+		tracker := NewGoroutineTracker(0)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := tracker.Track(ctx, "fetch")
+		assert.Equal(t, 1, tracker.Running())
+
+		done()
+		cancel()
+		assert.Equal(t, 0, tracker.Running())
+		assert.Empty(t, tracker.Leaks())
+	})
+
+	t.Run("report goroutines running after their context is done", func(t *testing.T) {
+		tracker := NewGoroutineTracker(0)
+		ctx, cancel := context.WithCancel(context.Background())
+		first := tracker.Track(ctx, "subscription update")
+		defer first()
+		second := tracker.Track(context.Background(), "event loop")
+		defer second()
+		third := tracker.Track(ctx, "subscription initial fetch")
+		defer third()
+		cancel()
+
+		require.Eventually(t, func() bool {
+			return len(tracker.Leaks()) == 2
+		}, time.Second, time.Millisecond)
+		leaks := tracker.Leaks()
+		assert.Equal(t, "subscription update", leaks[0].Name)
+		assert.Equal(t, "subscription initial fetch", leaks[1].Name)
+		assert.Contains(t, leaks[0].Stack, "TestGoroutineTracker")
+		assert.False(t, leaks[0].DoneAt.Before(leaks[0].StartedAt))
+	})
+
+	t.Run("goroutines within the grace period are no leaks", func(t *testing.T) {
+		tracker := NewGoroutineTracker(time.Hour)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := tracker.Track(ctx, "fetch")
+		defer done()
+		cancel()
+
+		assert.Empty(t, tracker.Leaks())
+	})
+
+	t.Run("track goroutines with the tracker of the context", func(t *testing.T) {
+		tracker := NewGoroutineTracker(0)
+		done := TrackGoroutine(withGoroutineTracker(context.Background(), tracker), "subscription")
+		assert.Equal(t, 1, tracker.Running())
+		done()
+		assert.Equal(t, 0, tracker.Running())
+
+		// without a tracker goroutines are not tracked
+		TrackGoroutine(context.Background(), "subscription")()
+	})
+}
@@ -0,0 +1,63 @@
+package resolve
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/google/uuid"
+	"go.uber.org/atomic"
+)
+
+// IDGenerator generates the IDs of the fetches in traces and the connection IDs of subscriptions, see ResolverOptions.IDGenerator
+type IDGenerator interface {
+	// TraceFetchID returns the ID of a fetch in a trace
+	TraceFetchID() string
+	// SubscriptionConnectionID returns the connection ID of a subscription which is not started with an identifier
+	SubscriptionConnectionID() int64
+}
+
+// randomIDGenerator generates random fetch IDs and increments the connection IDs
+type randomIDGenerator struct {
+	connectionIDs atomic.Int64
+}
+
+func (g *randomIDGenerator) TraceFetchID() string {
+	return uuid.New().String()
+}
+
+func (g *randomIDGenerator) SubscriptionConnectionID() int64 {
+	return g.connectionIDs.Inc()
+}
+
+// SeededIDGenerator generates the same sequence of IDs for the same seed,
+// e.g. to compare traces with golden files in tests.
+// The IDs depend on the order of the calls, so fetches resolved in parallel could still swap their IDs.
+type SeededIDGenerator struct {
+	mu            sync.Mutex
+	rand          *rand.Rand
+	connectionIDs int64
+}
+
+func NewSeededIDGenerator(seed int64) *SeededIDGenerator {
+	return &SeededIDGenerator{
+		rand: rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (g *SeededIDGenerator) TraceFetchID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	id, err := uuid.NewRandomFromReader(g.rand)
+	if err != nil {
+		// reading from rand.Rand never fails
+		panic(err)
+	}
+	return id.String()
+}
+
+func (g *SeededIDGenerator) SubscriptionConnectionID() int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.connectionIDs++
+	return g.connectionIDs
+}
@@ -0,0 +1,49 @@
+package resolve
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeededIDGenerator(t *testing.T) {
+	ids := func(generator IDGenerator) []any {
+		return []any{
+			generator.TraceFetchID(),
+			generator.TraceFetchID(),
+			generator.SubscriptionConnectionID(),
+			generator.SubscriptionConnectionID(),
+		}
+	}
+
+	first := ids(NewSeededIDGenerator(42))
+	assert.Equal(t, first, ids(NewSeededIDGenerator(42)))
+	assert.NotEqual(t, first[0], first[1])
+	assert.Equal(t, int64(1), first[2])
+	assert.Equal(t, int64(2), first[3])
+	assert.NotEqual(t, first[0], ids(NewSeededIDGenerator(43))[0])
+}
+
+func TestGetTraceIDGenerator(t *testing.T) {
+	root := &Object{
+		Fetch: &SingleFetch{},
+		Fields: []*Field{
+			{
+				Name: []byte("user"),
+				Value: &Object{
+					Path:  []string{"user"},
+					Fetch: &SingleFetch{},
+				},
+			},
+		},
+	}
+
+	trace := GetTrace(context.Background(), root, GetTraceIDGenerator(NewSeededIDGenerator(1)))
+	// the fields of an object are traced before the fetch of the object
+	expected := NewSeededIDGenerator(1)
+	assert.Equal(t, expected.TraceFetchID(), trace.Fields[0].Value.Fetch.Id)
+	assert.Equal(t, expected.TraceFetchID(), trace.Fetch.Id)
+
+	assert.Equal(t, trace, GetTrace(context.Background(), root, GetTraceIDGenerator(NewSeededIDGenerator(1))))
+}
@@ -0,0 +1,313 @@
+package resolve
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/buger/jsonparser"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astjson"
+)
+
+const (
+	maxListFilterExpressionLength = 256
+	maxListFieldPathDepth         = 8
+)
+
+// ListPostProcessing filters and sorts the items of a list in the resolver after it was fetched
+// It's used for origins which are not able to filter or sort server-side
+// Expressions are not evaluated as code, a filter is limited to a single comparison of an item field with a literal value
+type ListPostProcessing struct {
+	Filter *ListFilter
+	Sort   *ListSort
+	// MaxItems rejects lists with more items than the limit to bound the work done by the resolver, 0 means no limit
+	MaxItems int
+}
+
+// ListFilter keeps all items matching the expression, e.g. `status == "ACTIVE"` or `author.age >= 18`
+// The left side is a path to a field of the item, the right side is a json literal
+// Supported operators are ==, !=, <, <=, > and >=
+type ListFilter struct {
+	Expression string
+	// ExpressionVariableName is set when the expression is passed as a variable
+	ExpressionVariableName string
+}
+
+// ListSort sorts the items by the value at the path, e.g. `name` or `author.age`
+// Items without a value at the path are sorted last
+type ListSort struct {
+	By string
+	// ByVariableName is set when the path is passed as a variable
+	ByVariableName string
+	Descending     bool
+	// DescendingVariableName is set when the direction is passed as a variable
+	DescendingVariableName string
+}
+
+type listFilterOperator string
+
+const (
+	listFilterOperatorEqual          listFilterOperator = "=="
+	listFilterOperatorNotEqual       listFilterOperator = "!="
+	listFilterOperatorLess           listFilterOperator = "<"
+	listFilterOperatorLessOrEqual    listFilterOperator = "<="
+	listFilterOperatorGreater        listFilterOperator = ">"
+	listFilterOperatorGreaterOrEqual listFilterOperator = ">="
+)
+
+type listFilterCondition struct {
+	path     []string
+	operator listFilterOperator
+	kind     astjson.NodeKind
+	value    []byte
+}
+
+// ValidateListFilterExpression returns an error if the expression can't be used for a ListFilter
+func ValidateListFilterExpression(expression string) error {
+	_, err := parseListFilterExpression(expression)
+	return err
+}
+
+// ValidateListSortPath returns an error if the path can't be used for a ListSort
+func ValidateListSortPath(path string) error {
+	_, err := parseListFieldPath(path)
+	return err
+}
+
+func parseListFilterExpression(expression string) (*listFilterCondition, error) {
+	if len(expression) > maxListFilterExpressionLength {
+		return nil, fmt.Errorf("filter expression must not be longer than %d characters", maxListFilterExpressionLength)
+	}
+	operatorStart := strings.IndexAny(expression, "=!<>")
+	if operatorStart == -1 {
+		return nil, fmt.Errorf("filter expression '%s' has no operator", expression)
+	}
+	operatorEnd := operatorStart + 1
+	if operatorEnd < len(expression) && expression[operatorEnd] == '=' {
+		operatorEnd++
+	}
+
+	condition := &listFilterCondition{
+		operator: listFilterOperator(expression[operatorStart:operatorEnd]),
+	}
+	switch condition.operator {
+	case listFilterOperatorEqual, listFilterOperatorNotEqual, listFilterOperatorLess, listFilterOperatorLessOrEqual,
+		listFilterOperatorGreater, listFilterOperatorGreaterOrEqual:
+	default:
+		return nil, fmt.Errorf("filter expression '%s' has an unsupported operator '%s'", expression, condition.operator)
+	}
+
+	path, err := parseListFieldPath(expression[:operatorStart])
+	if err != nil {
+		return nil, err
+	}
+	condition.path = path
+
+	value, dataType, _, err := jsonparser.Get([]byte(strings.TrimSpace(expression[operatorEnd:])))
+	if err != nil {
+		return nil, fmt.Errorf("filter expression '%s' has an invalid value", expression)
+	}
+	switch dataType {
+	case jsonparser.String:
+		condition.kind = astjson.NodeKindString
+	case jsonparser.Number:
+		condition.kind = astjson.NodeKindNumber
+	case jsonparser.Boolean:
+		condition.kind = astjson.NodeKindBoolean
+	case jsonparser.Null:
+		condition.kind = astjson.NodeKindNull
+	default:
+		return nil, fmt.Errorf("filter expression '%s' must compare with a scalar value", expression)
+	}
+	condition.value = value
+	return condition, nil
+}
+
+func parseListFieldPath(path string) ([]string, error) {
+	elements := strings.Split(strings.TrimSpace(path), ".")
+	if len(elements) > maxListFieldPathDepth {
+		return nil, fmt.Errorf("field path '%s' must not be deeper than %d", path, maxListFieldPathDepth)
+	}
+	for _, element := range elements {
+		if !isListFieldName(element) {
+			return nil, fmt.Errorf("field path '%s' is invalid", path)
+		}
+	}
+	return elements, nil
+}
+
+func isListFieldName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9' && i != 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// applyListPostProcessing filters and sorts the items of the array in place
+// it returns an error message when the arguments are invalid or the list is too large
+func (r *Resolvable) applyListPostProcessing(processing *ListPostProcessing, ref int) (message string, ok bool) {
+	if processing.MaxItems > 0 && len(r.storage.Nodes[ref].ArrayValues) > processing.MaxItems {
+		return fmt.Sprintf("List exceeds the maximum of %d items for filtering and sorting.", processing.MaxItems), false
+	}
+
+	if processing.Filter != nil {
+		expression := processing.Filter.Expression
+		if value, ok := r.variableString(processing.Filter.ExpressionVariableName); ok {
+			expression = value
+		}
+		if expression != "" {
+			condition, err := parseListFilterExpression(expression)
+			if err != nil {
+				return fmt.Sprintf("Invalid filter: %s.", err.Error()), false
+			}
+			r.filterListItems(ref, condition)
+		}
+	}
+
+	if processing.Sort != nil {
+		by := processing.Sort.By
+		if value, ok := r.variableString(processing.Sort.ByVariableName); ok {
+			by = value
+		}
+		descending := processing.Sort.Descending
+		if value, ok := r.variableBoolean(processing.Sort.DescendingVariableName); ok {
+			descending = value
+		}
+		if by != "" {
+			path, err := parseListFieldPath(by)
+			if err != nil {
+				return fmt.Sprintf("Invalid sort: %s.", err.Error()), false
+			}
+			r.sortListItems(ref, path, descending)
+		}
+	}
+
+	return "", true
+}
+
+func (r *Resolvable) filterListItems(ref int, condition *listFilterCondition) {
+	items := r.storage.Nodes[ref].ArrayValues
+	filtered := items[:0]
+	for _, item := range items {
+		if r.listItemMatches(item, condition) {
+			filtered = append(filtered, item)
+		}
+	}
+	r.storage.Nodes[ref].ArrayValues = filtered
+}
+
+func (r *Resolvable) listItemMatches(item int, condition *listFilterCondition) bool {
+	value := r.storage.Get(item, condition.path)
+	kind := astjson.NodeKindNull
+	if r.storage.NodeIsDefined(value) {
+		kind = r.storage.Nodes[value].Kind
+	}
+
+	if kind != condition.kind {
+		return condition.operator == listFilterOperatorNotEqual
+	}
+
+	var comparison int
+	switch kind {
+	case astjson.NodeKindNumber:
+		comparison = compareListNumbers(r.storage.Nodes[value].ValueBytes(r.storage), condition.value)
+	case astjson.NodeKindString:
+		comparison = bytes.Compare(r.storage.Nodes[value].ValueBytes(r.storage), condition.value)
+	case astjson.NodeKindBoolean:
+		// booleans and null are only comparable for equality
+		if !bytes.Equal(r.storage.Nodes[value].ValueBytes(r.storage), condition.value) {
+			return condition.operator == listFilterOperatorNotEqual
+		}
+		return condition.operator == listFilterOperatorEqual
+	case astjson.NodeKindNull:
+		return condition.operator == listFilterOperatorEqual
+	default:
+		return condition.operator == listFilterOperatorNotEqual
+	}
+
+	switch condition.operator {
+	case listFilterOperatorEqual:
+		return comparison == 0
+	case listFilterOperatorNotEqual:
+		return comparison != 0
+	case listFilterOperatorLess:
+		return comparison < 0
+	case listFilterOperatorLessOrEqual:
+		return comparison <= 0
+	case listFilterOperatorGreater:
+		return comparison > 0
+	case listFilterOperatorGreaterOrEqual:
+		return comparison >= 0
+	}
+	return false
+}
+
+func (r *Resolvable) sortListItems(ref int, path []string, descending bool) {
+	items := r.storage.Nodes[ref].ArrayValues
+	values := make(map[int]int, len(items))
+	for _, item := range items {
+		values[item] = r.storage.Get(item, path)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		left, right := values[items[i]], values[items[j]]
+		leftDefined, rightDefined := r.listSortValueIsDefined(left), r.listSortValueIsDefined(right)
+		if !leftDefined || !rightDefined {
+			return leftDefined && !rightDefined
+		}
+		comparison := r.compareListSortValues(left, right)
+		if descending {
+			return comparison > 0
+		}
+		return comparison < 0
+	})
+}
+
+func (r *Resolvable) listSortValueIsDefined(ref int) bool {
+	if !r.storage.NodeIsDefined(ref) {
+		return false
+	}
+	switch r.storage.Nodes[ref].Kind {
+	case astjson.NodeKindString, astjson.NodeKindNumber, astjson.NodeKindBoolean:
+		return true
+	}
+	return false
+}
+
+func (r *Resolvable) compareListSortValues(left, right int) int {
+	leftKind, rightKind := r.storage.Nodes[left].Kind, r.storage.Nodes[right].Kind
+	if leftKind != rightKind {
+		return int(leftKind) - int(rightKind)
+	}
+	leftBytes, rightBytes := r.storage.Nodes[left].ValueBytes(r.storage), r.storage.Nodes[right].ValueBytes(r.storage)
+	if leftKind == astjson.NodeKindNumber {
+		return compareListNumbers(leftBytes, rightBytes)
+	}
+	return bytes.Compare(leftBytes, rightBytes)
+}
+
+func compareListNumbers(left, right []byte) int {
+	leftNumber, leftErr := strconv.ParseFloat(string(left), 64)
+	rightNumber, rightErr := strconv.ParseFloat(string(right), 64)
+	if leftErr != nil || rightErr != nil {
+		return bytes.Compare(left, right)
+	}
+	switch {
+	case leftNumber < rightNumber:
+		return -1
+	case leftNumber > rightNumber:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,100 @@
+package resolve
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+func TestValidateListFilterExpression(t *testing.T) {
+	assert.NoError(t, ValidateListFilterExpression(`status == "ACTIVE"`))
+	assert.NoError(t, ValidateListFilterExpression(`author.age>=18`))
+	assert.NoError(t, ValidateListFilterExpression(`deletedAt != null`))
+	assert.EqualError(t, ValidateListFilterExpression(`status`), `filter expression 'status' has no operator`)
+	assert.EqualError(t, ValidateListFilterExpression(`status = "ACTIVE"`), `filter expression 'status = "ACTIVE"' has an unsupported operator '='`)
+	assert.EqualError(t, ValidateListFilterExpression(`1status == 1`), `field path '1status ' is invalid`)
+	assert.EqualError(t, ValidateListFilterExpression(`tags == ["a"]`), `filter expression 'tags == ["a"]' must compare with a scalar value`)
+	assert.EqualError(t, ValidateListFilterExpression(`status == ACTIVE`), `filter expression 'status == ACTIVE' has an invalid value`)
+}
+
+func TestResolvable_ListPostProcessing(t *testing.T) {
+	data := `{"users":[{"name":"c","age":30,"address":{"city":"Berlin"}},{"name":"a","age":17,"address":{"city":"Paris"}},{"name":"b","age":null,"address":{"city":"Berlin"}},{"name":"d","age":42,"address":{"city":"Rome"}}]}`
+
+	users := func(processing *ListPostProcessing) *Object {
+		return &Object{
+			Fields: []*Field{
+				{
+					Name: []byte("users"),
+					Value: &Array{
+						Path:           []string{"users"},
+						PostProcessing: processing,
+						Item: &Object{
+							Fields: []*Field{
+								{
+									Name:  []byte("name"),
+									Value: &String{Path: []string{"name"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	resolve := func(t *testing.T, variables string, root *Object) string {
+		res := NewResolvable()
+		ctx := &Context{
+			Variables: []byte(variables),
+		}
+		err := res.Init(ctx, []byte(data), ast.OperationTypeQuery)
+		require.NoError(t, err)
+		out := &bytes.Buffer{}
+		err = res.Resolve(context.Background(), root, out)
+		require.NoError(t, err)
+		return out.String()
+	}
+
+	t.Run("filter by number", func(t *testing.T) {
+		out := resolve(t, `{}`, users(&ListPostProcessing{Filter: &ListFilter{Expression: `age >= 18`}}))
+		assert.Equal(t, `{"data":{"users":[{"name":"c"},{"name":"d"}]}}`, out)
+	})
+
+	t.Run("filter by nested string", func(t *testing.T) {
+		out := resolve(t, `{}`, users(&ListPostProcessing{Filter: &ListFilter{Expression: `address.city != "Berlin"`}}))
+		assert.Equal(t, `{"data":{"users":[{"name":"a"},{"name":"d"}]}}`, out)
+	})
+
+	t.Run("filter by null", func(t *testing.T) {
+		out := resolve(t, `{}`, users(&ListPostProcessing{Filter: &ListFilter{Expression: `age == null`}}))
+		assert.Equal(t, `{"data":{"users":[{"name":"b"}]}}`, out)
+	})
+
+	t.Run("sort ascending with missing values last", func(t *testing.T) {
+		out := resolve(t, `{}`, users(&ListPostProcessing{Sort: &ListSort{By: `age`}}))
+		assert.Equal(t, `{"data":{"users":[{"name":"a"},{"name":"c"},{"name":"d"},{"name":"b"}]}}`, out)
+	})
+
+	t.Run("filter and sort from variables", func(t *testing.T) {
+		out := resolve(t, `{"filter":"address.city == \"Berlin\"","by":"name","desc":true}`, users(&ListPostProcessing{
+			Filter: &ListFilter{ExpressionVariableName: "filter"},
+			Sort:   &ListSort{ByVariableName: "by", DescendingVariableName: "desc"},
+		}))
+		assert.Equal(t, `{"data":{"users":[{"name":"c"},{"name":"b"}]}}`, out)
+	})
+
+	t.Run("invalid filter from variables", func(t *testing.T) {
+		out := resolve(t, `{"filter":"name"}`, users(&ListPostProcessing{Filter: &ListFilter{ExpressionVariableName: "filter"}}))
+		assert.Equal(t, `{"errors":[{"message":"Invalid filter: filter expression 'name' has no operator.","path":["users"]}],"data":null}`, out)
+	})
+
+	t.Run("too many items", func(t *testing.T) {
+		out := resolve(t, `{}`, users(&ListPostProcessing{MaxItems: 3, Sort: &ListSort{By: `name`}}))
+		assert.Equal(t, `{"errors":[{"message":"List exceeds the maximum of 3 items for filtering and sorting.","path":["users"]}],"data":null}`, out)
+	})
+}
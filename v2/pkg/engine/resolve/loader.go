@@ -21,6 +21,7 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astjson"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/arena"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/pool"
 )
 
@@ -28,29 +29,74 @@ type Loader struct {
 	data       *astjson.JSON
 	dataRoot   int
 	errorsRoot int
-	ctx        *Context
-	path       []string
-	info       *GraphQLResponseInfo
+	// subgraphExtensionsRoot is the object the ExtensionsPassthrough of fetches are merged into
+	subgraphExtensionsRoot int
+	ctx                    *Context
+	path                   []string
+	info                   *GraphQLResponseInfo
+	// piiVariables are the operation variables which are redacted from traces
+	piiVariables []string
 
 	propagateSubgraphErrors      bool
 	propagateSubgraphStatusCodes bool
+	// dataSourceMetrics records the stats of the fetches if set
+	dataSourceMetrics DataSourceMetrics
+	// fetchTracer traces the fetches if set
+	fetchTracer FetchTracer
+	// goroutines tracks the goroutines of parallel fetches if set
+	goroutines *GoroutineTracker
+
+	// results and resultSlices allocate the results of fetches if the arena is enabled, they are reset in Free
+	results      *arena.Arena[result]
+	resultSlices *arena.Arena[*result]
+	// representations are the rendered items of batch entity fetches, they are reset in Free
+	representations renderedRepresentations
 }
 
 func (l *Loader) Free() {
 	l.info = nil
+	l.piiVariables = nil
 	l.ctx = nil
 	l.data = nil
 	l.dataRoot = -1
 	l.errorsRoot = -1
+	l.subgraphExtensionsRoot = -1
 	l.path = l.path[:0]
+	l.representations.reset()
+	if l.results != nil {
+		l.results.Reset()
+		l.resultSlices.Reset()
+	}
+}
+
+// enableArena allocates the results of fetches from arenas which are freed at once in Free
+func (l *Loader) enableArena() {
+	l.results = arena.New[result](0)
+	l.resultSlices = arena.New[*result](0)
+}
+
+func (l *Loader) newResult() *result {
+	if l.results == nil {
+		return &result{}
+	}
+	return l.results.New()
+}
+
+func (l *Loader) newResults(n int) []*result {
+	if l.resultSlices == nil {
+		return make([]*result, n)
+	}
+	return l.resultSlices.Slice(n)
 }
 
 func (l *Loader) LoadGraphQLResponseData(ctx *Context, response *GraphQLResponse, resolvable *Resolvable) (err error) {
 	l.data = resolvable.storage
 	l.dataRoot = resolvable.dataRoot
 	l.errorsRoot = resolvable.errorsRoot
+	l.subgraphExtensionsRoot = resolvable.subgraphExtensionsRoot
 	l.ctx = ctx
 	l.info = response.Info
+	l.piiVariables = response.PIIVariables
 	return l.walkNode(response.Data, []int{resolvable.dataRoot})
 }
 
@@ -181,9 +227,8 @@ func (l *Loader) itemsData(items []int, out io.Writer) error {
 func (l *Loader) resolveAndMergeFetch(fetch Fetch, items []int) error {
 	switch f := fetch.(type) {
 	case *SingleFetch:
-		res := &result{
-			out: pool.BytesBuffer.Get(),
-		}
+		res := l.newResult()
+		res.out = pool.BytesBuffer.Get()
 		err := l.loadSingleFetch(l.ctx.ctx, f, items, res)
 		if err != nil {
 			return err
@@ -195,6 +240,9 @@ func (l *Loader) resolveAndMergeFetch(fetch Fetch, items []int) error {
 				Path: l.renderPath(),
 			}
 		}
+		if l.tracksMutationFetches() {
+			return l.resolveSerialMutationFetches(f, items)
+		}
 		for i := range f.Fetches {
 			err := l.resolveAndMergeFetch(f.Fetches[i], items)
 			if err != nil {
@@ -207,12 +255,14 @@ func (l *Loader) resolveAndMergeFetch(fetch Fetch, items []int) error {
 				Path: l.renderPath(),
 			}
 		}
-		results := make([]*result, len(f.Fetches))
+		results := l.newResults(len(f.Fetches))
 		g, ctx := errgroup.WithContext(l.ctx.ctx)
 		for i := range f.Fetches {
 			i := i
-			results[i] = &result{}
+			results[i] = l.newResult()
+			done := l.goroutines.Track(ctx, "parallel fetch")
 			g.Go(func() error {
+				defer done()
 				return l.loadFetch(ctx, f.Fetches[i], items, results[i])
 			})
 		}
@@ -241,14 +291,15 @@ func (l *Loader) resolveAndMergeFetch(fetch Fetch, items []int) error {
 				Path: l.renderPath(),
 			}
 		}
-		results := make([]*result, len(items))
+		results := l.newResults(len(items))
 		g, ctx := errgroup.WithContext(l.ctx.ctx)
 		for i := range items {
 			i := i
-			results[i] = &result{
-				out: pool.BytesBuffer.Get(),
-			}
+			results[i] = l.newResult()
+			results[i].out = pool.BytesBuffer.Get()
+			done := l.goroutines.Track(ctx, "parallel list item fetch")
 			g.Go(func() error {
+				defer done()
 				return l.loadFetch(ctx, f.Fetch, items[i:i+1], results[i])
 			})
 		}
@@ -263,18 +314,16 @@ func (l *Loader) resolveAndMergeFetch(fetch Fetch, items []int) error {
 			}
 		}
 	case *EntityFetch:
-		res := &result{
-			out: pool.BytesBuffer.Get(),
-		}
+		res := l.newResult()
+		res.out = pool.BytesBuffer.Get()
 		err := l.loadEntityFetch(l.ctx.ctx, f, items, res)
 		if err != nil {
 			return errors.WithStack(err)
 		}
 		return l.mergeResult(res, items)
 	case *BatchEntityFetch:
-		res := &result{
-			out: pool.BytesBuffer.Get(),
-		}
+		res := l.newResult()
+		res.out = pool.BytesBuffer.Get()
 		err := l.loadBatchEntityFetch(l.ctx.ctx, f, items, res)
 		if err != nil {
 			return errors.WithStack(err)
@@ -294,25 +343,27 @@ func (l *Loader) loadFetch(ctx context.Context, fetch Fetch, items []int, res *r
 	case *ParallelFetch:
 		return fmt.Errorf("parallel fetch must not be nested")
 	case *ParallelListItemFetch:
-		results := make([]*result, len(items))
+		results := l.newResults(len(items))
 		if l.ctx.TracingOptions.Enable {
 			f.Traces = make([]*SingleFetch, len(items))
 		}
 		g, ctx := errgroup.WithContext(l.ctx.ctx)
 		for i := range items {
 			i := i
-			results[i] = &result{
-				out: pool.BytesBuffer.Get(),
-			}
+			results[i] = l.newResult()
+			results[i].out = pool.BytesBuffer.Get()
+			done := l.goroutines.Track(ctx, "parallel list item fetch")
 			if l.ctx.TracingOptions.Enable {
 				f.Traces[i] = new(SingleFetch)
 				*f.Traces[i] = *f.Fetch
 				g.Go(func() error {
+					defer done()
 					return l.loadFetch(ctx, f.Traces[i], items[i:i+1], results[i])
 				})
 				continue
 			}
 			g.Go(func() error {
+				defer done()
 				return l.loadFetch(ctx, f.Fetch, items[i:i+1], results[i])
 			})
 		}
@@ -337,6 +388,12 @@ func (l *Loader) mergeResult(res *result, items []int) error {
 	if res.err != nil {
 		return l.renderErrorsFailedToFetch(res, failedToFetchNoReason)
 	}
+	if len(res.invalidRepresentations) != 0 {
+		err := l.renderInvalidRepresentationErrors(res)
+		if err != nil {
+			return err
+		}
+	}
 	if res.authorizationRejected {
 		err := l.renderAuthorizationRejectedErrors(res)
 		if err != nil {
@@ -371,6 +428,16 @@ func (l *Loader) mergeResult(res *result, items []int) error {
 	if res.out.Len() == 0 {
 		return l.renderErrorsFailedToFetch(res, failedToFetchEmptyResponse)
 	}
+	if res.postProcessing.InvalidUTF8Policy != InvalidUTF8PolicyPassThrough && responseNeedsSanitization(res.out.Bytes()) {
+		if res.postProcessing.InvalidUTF8Policy == InvalidUTF8PolicyError {
+			return l.renderErrorsFailedToFetch(res, failedToFetchInvalidUTF8)
+		}
+		sanitized := pool.BytesBuffer.Get()
+		defer pool.BytesBuffer.Put(sanitized)
+		sanitizeResponse(res.out.Bytes(), sanitized)
+		res.out.Reset()
+		_, _ = res.out.Write(sanitized.Bytes())
+	}
 	node, err := l.data.AppendAnyJSONBytes(res.out.Bytes())
 	if err != nil {
 		return l.renderErrorsFailedToFetch(res, failedToFetchInvalidJSON)
@@ -382,6 +449,9 @@ func (l *Loader) mergeResult(res *result, items []int) error {
 			return errors.WithStack(err)
 		}
 	}
+	if res.postProcessing.ExtensionsPassthrough != nil {
+		l.mergeExtensions(res, node)
+	}
 	if res.postProcessing.SelectResponseDataPath != nil {
 		node = l.data.Get(node, res.postProcessing.SelectResponseDataPath)
 		if !l.data.NodeIsDefined(node) {
@@ -390,6 +460,9 @@ func (l *Loader) mergeResult(res *result, items []int) error {
 		}
 	}
 	withPostProcessing := res.postProcessing.ResponseTemplate != nil
+	if !withPostProcessing {
+		l.transformScalars(res, node)
+	}
 	if withPostProcessing && len(items) <= 1 {
 		postProcessed := pool.BytesBuffer.Get()
 		defer pool.BytesBuffer.Put(postProcessed)
@@ -406,6 +479,7 @@ func (l *Loader) mergeResult(res *result, items []int) error {
 		if err != nil {
 			return errors.WithStack(err)
 		}
+		l.transformScalars(res, node)
 	}
 	if len(items) == 0 {
 		l.data.RootNode = node
@@ -454,6 +528,7 @@ func (l *Loader) mergeResult(res *result, items []int) error {
 				if err != nil {
 					return errors.WithStack(err)
 				}
+				l.transformScalars(res, nodeProcessed)
 				l.data.MergeNodesWithPath(items[i], nodeProcessed, res.postProcessing.MergePath)
 			}
 		} else {
@@ -490,6 +565,27 @@ type result struct {
 
 	rateLimitRejected       bool
 	rateLimitRejectedReason string
+
+	// invalidRepresentations are the validation errors of the entity representations which were not fetched
+	invalidRepresentations []*InvalidRepresentationError
+
+	// recordInput keeps a copy of the rendered input in input
+	recordInput bool
+	input       []byte
+}
+
+func (r *result) addInvalidRepresentation(err error) bool {
+	var invalid *InvalidRepresentationError
+	if !errors.As(err, &invalid) {
+		return false
+	}
+	for i := range r.invalidRepresentations {
+		if *r.invalidRepresentations[i] == *invalid {
+			return true
+		}
+	}
+	r.invalidRepresentations = append(r.invalidRepresentations, invalid)
+	return true
 }
 
 func (r *result) init(postProcessing PostProcessingConfiguration, info *FetchInfo) {
@@ -635,6 +731,23 @@ func (l *Loader) renderAuthorizationRejectedErrors(res *result) error {
 	return nil
 }
 
+func (l *Loader) renderInvalidRepresentationErrors(res *result) error {
+	path := l.renderPath()
+	subgraph := " "
+	if res.subgraphName != "" {
+		subgraph = fmt.Sprintf(" '%s' ", res.subgraphName)
+	}
+	for _, invalid := range res.invalidRepresentations {
+		l.ctx.appendSubgraphError(errors.Wrap(invalid, fmt.Sprintf("invalid entity representation for subgraph '%s' at path '%s'", res.subgraphName, path)))
+		errorObject, err := l.data.AppendObject([]byte(fmt.Sprintf(`{"message":"Invalid entity representation for Subgraph%sat path '%s'. Reason: %s."}`, subgraph, path, invalid.Error())))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		l.data.Nodes[l.errorsRoot].ArrayValues = append(l.data.Nodes[l.errorsRoot].ArrayValues, errorObject)
+	}
+	return nil
+}
+
 func (l *Loader) renderRateLimitRejectedErrors(res *result) error {
 	path := l.renderPath()
 	l.ctx.appendSubgraphError(errors.Wrap(res.err, fmt.Sprintf("Rate limit rejected for subgraph '%s' at path '%s'. Reason: %s", res.subgraphName, path, res.rateLimitRejectedReason)))
@@ -762,7 +875,10 @@ func (l *Loader) loadSingleFetch(ctx context.Context, fetch *SingleFetch, items
 	if !allowed {
 		return nil
 	}
-	l.executeSourceLoad(ctx, fetch.DataSource, fetchInput, res, fetch.Trace)
+	if res.recordInput {
+		res.input = append([]byte(nil), fetchInput...)
+	}
+	l.executeSourceLoad(ctx, fetch.DataSource, FetchKindSingle, fetch.Info, fetchInput, res, fetch.Trace)
 	return nil
 }
 
@@ -795,6 +911,19 @@ func (l *Loader) loadEntityFetch(ctx context.Context, fetch *EntityFetch, items
 		return errors.WithStack(err)
 	}
 
+	err = validateRepresentations(fetch.Input.Item, itemData.Bytes())
+	if err != nil {
+		if res.addInvalidRepresentation(err) {
+			// skip fetch, the invalid representation is rendered as error
+			res.fetchSkipped = true
+			if l.ctx.TracingOptions.Enable {
+				fetch.Trace.LoadSkipped = true
+			}
+			return nil
+		}
+		return errors.WithStack(err)
+	}
+
 	err = fetch.Input.Item.Render(l.ctx, itemData.Bytes(), item)
 	if err != nil {
 		if fetch.Input.SkipErrItem {
@@ -842,7 +971,7 @@ func (l *Loader) loadEntityFetch(ctx context.Context, fetch *EntityFetch, items
 	if !allowed {
 		return nil
 	}
-	l.executeSourceLoad(ctx, fetch.DataSource, fetchInput, res, fetch.Trace)
+	l.executeSourceLoad(ctx, fetch.DataSource, FetchKindEntity, fetch.Info, fetchInput, res, fetch.Trace)
 	return nil
 }
 
@@ -893,17 +1022,30 @@ WithNextItem:
 		}
 		for j := range fetch.Input.Items {
 			itemInput.Reset()
-			err = fetch.Input.Items[j].Render(l.ctx, itemData.Bytes(), itemInput)
-			if err != nil {
-				if fetch.Input.SkipErrItems {
-					err = nil // nolint:ineffassign
-					res.batchStats[i] = append(res.batchStats[i], -1)
-					continue
+			if rendered, ok := l.representations.get(&fetch.Input.Items[j], itemData.Bytes()); ok {
+				_, _ = itemInput.Write(rendered)
+			} else {
+				err = validateRepresentations(fetch.Input.Items[j], itemData.Bytes())
+				if err != nil {
+					if res.addInvalidRepresentation(err) {
+						res.batchStats[i] = append(res.batchStats[i], -1)
+						continue
+					}
+					return errors.WithStack(err)
 				}
-				if l.ctx.TracingOptions.Enable {
-					fetch.Trace.LoadSkipped = true
+				err = fetch.Input.Items[j].Render(l.ctx, itemData.Bytes(), itemInput)
+				if err != nil {
+					if fetch.Input.SkipErrItems {
+						err = nil // nolint:ineffassign
+						res.batchStats[i] = append(res.batchStats[i], -1)
+						continue
+					}
+					if l.ctx.TracingOptions.Enable {
+						fetch.Trace.LoadSkipped = true
+					}
+					return errors.WithStack(err)
 				}
-				return errors.WithStack(err)
+				l.representations.add(&fetch.Input.Items[j], itemData.Bytes(), itemInput.Bytes())
 			}
 			if fetch.Input.SkipNullItems && itemInput.Len() == 4 && bytes.Equal(itemInput.Bytes(), null) {
 				res.batchStats[i] = append(res.batchStats[i], -1)
@@ -963,7 +1105,7 @@ WithNextItem:
 	if !allowed {
 		return nil
 	}
-	l.executeSourceLoad(ctx, fetch.DataSource, fetchInput, res, fetch.Trace)
+	l.executeSourceLoad(ctx, fetch.DataSource, FetchKindEntityBatch, fetch.Info, fetchInput, res, fetch.Trace)
 	return nil
 }
 
@@ -1029,7 +1171,7 @@ func setSingleFlightStats(ctx context.Context, stats *SingleFlightStats) context
 	return context.WithValue(ctx, singleFlightStatsKey{}, stats)
 }
 
-func (l *Loader) executeSourceLoad(ctx context.Context, source DataSource, input []byte, res *result, trace *DataSourceLoadTrace) {
+func (l *Loader) executeSourceLoad(ctx context.Context, source DataSource, kind FetchKind, info *FetchInfo, input []byte, res *result, trace *DataSourceLoadTrace) {
 	if l.ctx.Extensions != nil {
 		input, res.err = jsonparser.Set(input, l.ctx.Extensions, "body", "extensions")
 		if res.err != nil {
@@ -1043,6 +1185,10 @@ func (l *Loader) executeSourceLoad(ctx context.Context, source DataSource, input
 		if !l.ctx.TracingOptions.ExcludeInput {
 			trace.Input = make([]byte, len(input))
 			copy(trace.Input, input) // copy input explicitly, omit __trace__ field
+			trace.Input = redactPIIVariables(trace.Input, l.piiVariables)
+			if l.ctx.TracingOptions.RedactValues {
+				trace.Input = redactInputValues(trace.Input)
+			}
 			redactedInput, err := redactHeaders(trace.Input)
 			if err != nil {
 				res.err = errors.WithStack(err)
@@ -1147,8 +1293,43 @@ func (l *Loader) executeSourceLoad(ctx context.Context, source DataSource, input
 	}
 	var responseContext *httpclient.ResponseContext
 	ctx, responseContext = httpclient.InjectResponseContext(ctx)
-	res.err = source.Load(ctx, input, res.out)
+	ctx = withResponseHeaders(ctx, l.ctx.responseHeaders)
+	var endFetch func(stats FetchStats)
+	if l.fetchTracer != nil {
+		ctx, endFetch = l.fetchTracer.StartFetch(ctx, newFetchTraceInfo(kind, info))
+	}
+	measure := l.dataSourceMetrics != nil || l.ctx.fetchTimings != nil || endFetch != nil
+	var loadStart time.Time
+	if measure {
+		loadStart = time.Now()
+	}
+	l.loadWithProfilerLabels(ctx, info, func(ctx context.Context) {
+		res.err = source.Load(ctx, input, res.out)
+	})
 	res.statusCode = responseContext.StatusCode
+	if measure {
+		stats := FetchStats{
+			RequestBytes:  len(input),
+			ResponseBytes: res.out.Len(),
+			Duration:      time.Since(loadStart),
+			Failed:        res.err != nil,
+		}
+		if endFetch != nil {
+			endFetch(stats)
+		}
+		if l.dataSourceMetrics != nil && info != nil {
+			l.dataSourceMetrics.RecordFetch(info.DataSourceID, stats)
+		}
+		if l.ctx.fetchTimings != nil && info != nil {
+			l.ctx.fetchTimings.add(FetchTiming{
+				DataSourceID: info.DataSourceID,
+				RootFields:   info.RootFields,
+				Path:         append([]string(nil), l.path...),
+				Start:        loadStart,
+				FetchStats:   stats,
+			})
+		}
+	}
 	if l.ctx.TracingOptions.Enable {
 		stats := GetSingleFlightStats(ctx)
 		if stats != nil {
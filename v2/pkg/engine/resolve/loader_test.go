@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
+	"runtime/pprof"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astjson"
 )
@@ -987,3 +990,458 @@ func TestLoader_RedactHeaders(t *testing.T) {
 		}
 	}
 }
+
+func TestLoader_ScalarTransformations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usersService := mockedDS(t, ctrl,
+		`{"method":"POST","url":"http://users","body":{"query":"query{users{createdAt posts{publishedAt}}}"}}`,
+		`{"users":[{"createdAt":1700000000,"posts":[{"publishedAt":1700000000000},{"publishedAt":null}]},{"createdAt":"invalid","posts":[]}]}`)
+
+	response := &GraphQLResponse{
+		Data: &Object{
+			Fetch: &SingleFetch{
+				InputTemplate: InputTemplate{
+					Segments: []TemplateSegment{
+						{
+							Data:        []byte(`{"method":"POST","url":"http://users","body":{"query":"query{users{createdAt posts{publishedAt}}}"}}`),
+							SegmentType: StaticSegmentType,
+						},
+					},
+				},
+				FetchConfiguration: FetchConfiguration{
+					DataSource: usersService,
+					PostProcessing: PostProcessingConfiguration{
+						SelectResponseDataPath: []string{"data"},
+						ScalarTransformations: []ScalarTransformation{
+							{
+								Path:        []string{"users", "createdAt"},
+								Transformer: EpochToISO8601Transformer{},
+							},
+							{
+								Path:        []string{"users", "posts", "publishedAt"},
+								Transformer: EpochToISO8601Transformer{Milliseconds: true},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	ctx := &Context{
+		ctx: context.Background(),
+	}
+	resolvable := &Resolvable{
+		storage: &astjson.JSON{},
+	}
+	loader := &Loader{}
+	err := resolvable.Init(ctx, nil, ast.OperationTypeQuery)
+	assert.NoError(t, err)
+	err = loader.LoadGraphQLResponseData(ctx, response, resolvable)
+	assert.NoError(t, err)
+	ctrl.Finish()
+	out := &bytes.Buffer{}
+	err = resolvable.storage.PrintNode(resolvable.storage.Nodes[resolvable.storage.RootNode], out)
+	assert.NoError(t, err)
+	expected := `{"errors":[],"data":{"users":[{"createdAt":"2023-11-14T22:13:20Z","posts":[{"publishedAt":"2023-11-14T22:13:20Z"},{"publishedAt":null}]},{"createdAt":"invalid","posts":[]}]}}`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestISO8601ToEpochTransformer(t *testing.T) {
+	out, err := ISO8601ToEpochTransformer{}.Transform([]byte(`"2023-11-14T22:13:20Z"`))
+	assert.NoError(t, err)
+	assert.Equal(t, `1700000000`, string(out))
+
+	out, err = ISO8601ToEpochTransformer{Milliseconds: true}.Transform([]byte(`"2023-11-14T22:13:20.5Z"`))
+	assert.NoError(t, err)
+	assert.Equal(t, `1700000000500`, string(out))
+
+	_, err = ISO8601ToEpochTransformer{}.Transform([]byte(`1700000000`))
+	assert.Error(t, err)
+}
+
+func TestLoader_InvalidUTF8Policy(t *testing.T) {
+	run := func(t *testing.T, policy InvalidUTF8Policy, expected string) {
+		ctrl := gomock.NewController(t)
+		usersService := mockedDS(t, ctrl,
+			`{"method":"POST","url":"http://users","body":{"query":"query{user{name}}"}}`,
+			"{\"user\":{\"name\":\"a\xffb\tc\"}}")
+
+		response := &GraphQLResponse{
+			Data: &Object{
+				Fetch: &SingleFetch{
+					InputTemplate: InputTemplate{
+						Segments: []TemplateSegment{
+							{
+								Data:        []byte(`{"method":"POST","url":"http://users","body":{"query":"query{user{name}}"}}`),
+								SegmentType: StaticSegmentType,
+							},
+						},
+					},
+					FetchConfiguration: FetchConfiguration{
+						DataSource: usersService,
+						PostProcessing: PostProcessingConfiguration{
+							SelectResponseDataPath: []string{"data"},
+							InvalidUTF8Policy:      policy,
+						},
+					},
+				},
+			},
+		}
+		ctx := &Context{
+			ctx: context.Background(),
+		}
+		resolvable := &Resolvable{
+			storage: &astjson.JSON{},
+		}
+		loader := &Loader{}
+		err := resolvable.Init(ctx, nil, ast.OperationTypeQuery)
+		assert.NoError(t, err)
+		err = loader.LoadGraphQLResponseData(ctx, response, resolvable)
+		assert.NoError(t, err)
+		ctrl.Finish()
+		out := &bytes.Buffer{}
+		err = resolvable.storage.PrintNode(resolvable.storage.Nodes[resolvable.storage.RootNode], out)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, out.String())
+	}
+
+	t.Run("pass through", func(t *testing.T) {
+		run(t, InvalidUTF8PolicyPassThrough, "{\"errors\":[],\"data\":{\"user\":{\"name\":\"a\xffb\tc\"}}}")
+	})
+	t.Run("replace", func(t *testing.T) {
+		run(t, InvalidUTF8PolicyReplace, `{"errors":[],"data":{"user":{"name":"a`+"�"+`b\u0009c"}}}`)
+	})
+	t.Run("error", func(t *testing.T) {
+		run(t, InvalidUTF8PolicyError, `{"errors":[{"message":"Failed to fetch from Subgraph at path '', invalid UTF-8 or control characters in response."}],"data":{}}`)
+	})
+}
+
+func TestLoader_ExtensionsPassthrough(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	usersService := NewMockDataSource(ctrl)
+	usersService.EXPECT().
+		Load(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&bytes.Buffer{})).
+		DoAndReturn(func(ctx context.Context, input []byte, w io.Writer) (err error) {
+			_, err = w.Write([]byte(`{"data":{"user":{"name":"Jens"}},"extensions":{"cost":{"requested":3},"tracing":{"duration":12},"internal":true}}`))
+			return
+		})
+
+	response := &GraphQLResponse{
+		Data: &Object{
+			Fetch: &SingleFetch{
+				InputTemplate: InputTemplate{
+					Segments: []TemplateSegment{
+						{
+							Data:        []byte(`{"method":"POST","url":"http://users","body":{"query":"query{user{name}}"}}`),
+							SegmentType: StaticSegmentType,
+						},
+					},
+				},
+				FetchConfiguration: FetchConfiguration{
+					DataSource: usersService,
+					PostProcessing: PostProcessingConfiguration{
+						SelectResponseDataPath: []string{"data"},
+						ExtensionsPassthrough: &ExtensionsPassthrough{
+							Namespace: "users",
+							Keys:      []string{"cost", "tracing", "missing"},
+						},
+					},
+				},
+			},
+			Fields: []*Field{
+				{
+					Name: []byte("user"),
+					Value: &Object{
+						Path: []string{"user"},
+						Fields: []*Field{
+							{
+								Name:  []byte("name"),
+								Value: &String{Path: []string{"name"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	ctx := NewContext(context.Background())
+	resolvable := NewResolvable()
+	loader := &Loader{}
+	err := resolvable.Init(ctx, nil, ast.OperationTypeQuery)
+	assert.NoError(t, err)
+	err = loader.LoadGraphQLResponseData(ctx, response, resolvable)
+	assert.NoError(t, err)
+	ctrl.Finish()
+	out := &bytes.Buffer{}
+	err = resolvable.Resolve(ctx.ctx, response.Data, out)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"data":{"user":{"name":"Jens"}},"extensions":{"subgraphs":{"users":{"cost":{"requested":3},"tracing":{"duration":12}}}}}`, out.String())
+}
+
+func TestLoader_MutationRollbackHook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mutation := func(input, response string) *SingleFetch {
+		service := NewMockDataSource(ctrl)
+		if response != "" {
+			service.EXPECT().
+				Load(gomock.Any(), []byte(input), gomock.AssignableToTypeOf(&bytes.Buffer{})).
+				DoAndReturn(func(ctx context.Context, input []byte, w io.Writer) (err error) {
+					_, err = w.Write([]byte(response))
+					return
+				})
+		}
+		return &SingleFetch{
+			InputTemplate: InputTemplate{
+				Segments: []TemplateSegment{
+					{
+						Data:        []byte(input),
+						SegmentType: StaticSegmentType,
+					},
+				},
+			},
+			FetchConfiguration: FetchConfiguration{
+				DataSource: service,
+				PostProcessing: PostProcessingConfiguration{
+					SelectResponseDataPath:   []string{"data"},
+					SelectResponseErrorsPath: []string{"errors"},
+				},
+			},
+		}
+	}
+
+	response := &GraphQLResponse{
+		Data: &Object{
+			Fetch: &SerialFetch{
+				Fetches: []Fetch{
+					mutation(`{"url":"http://users","body":{"query":"mutation{createUser}"}}`, `{"data":{"createUser":"1"}}`),
+					mutation(`{"url":"http://mail","body":{"query":"mutation{sendMail}"}}`, `{"errors":[{"message":"mail server unavailable"}]}`),
+					mutation(`{"url":"http://users","body":{"query":"mutation{deleteUser}"}}`, `{"data":{"deleteUser":"2"}}`),
+				},
+			},
+		},
+	}
+
+	var rolledBack [][]CompletedMutationFetch
+	ctx := NewContext(context.Background())
+	ctx.SetMutationRollbackHook(MutationRollbackHookFunc(func(ctx *Context, completed []CompletedMutationFetch) {
+		rolledBack = append(rolledBack, completed)
+	}))
+	resolvable := NewResolvable()
+	loader := &Loader{}
+	err := resolvable.Init(ctx, nil, ast.OperationTypeMutation)
+	assert.NoError(t, err)
+	err = loader.LoadGraphQLResponseData(ctx, response, resolvable)
+	assert.NoError(t, err)
+	ctrl.Finish()
+
+	// the hook only observes the mutations, the mutations after the failed mutation are executed
+	require.Len(t, rolledBack, 1)
+	require.Len(t, rolledBack[0], 2)
+	assert.Equal(t, `{"url":"http://users","body":{"query":"mutation{createUser}"}}`, string(rolledBack[0][0].Input))
+	assert.Equal(t, `{"data":{"createUser":"1"}}`, string(rolledBack[0][0].Response))
+	assert.Equal(t, `{"url":"http://users","body":{"query":"mutation{deleteUser}"}}`, string(rolledBack[0][1].Input))
+
+	out := &bytes.Buffer{}
+	err = resolvable.storage.PrintNode(resolvable.storage.Nodes[resolvable.storage.RootNode], out)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"errors":[{"message":"Failed to fetch from Subgraph at path ''."}],"data":{"createUser":"1","deleteUser":"2"}}`, out.String())
+}
+
+type countingVariableRenderer struct {
+	VariableRenderer
+	renders int
+}
+
+func (r *countingVariableRenderer) RenderVariable(ctx context.Context, data []byte, out io.Writer) error {
+	r.renders++
+	return r.VariableRenderer.RenderVariable(ctx, data, out)
+}
+
+func TestLoader_RenderedRepresentations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	productsService := mockedDS(t, ctrl,
+		`{"method":"POST","url":"http://products","body":{"query":"query{topProducts{__typename upc weight}}"}}`,
+		`{"topProducts":[{"__typename":"Product","upc":"1","weight":2},{"__typename":"Product","upc":"1","weight":2},{"__typename":"Product","upc":"2","weight":3}]}`)
+	shippingService := mockedDS(t, ctrl,
+		`{"method":"POST","url":"http://shipping","body":{"query":"query($representations: [_Any!]!){_entities(representations: $representations){__typename ... on Product {shippingEstimate}}}","variables":{"representations":[{"__typename":"Product","upc":"1","weight":2},{"__typename":"Product","upc":"2","weight":3}]}}}`,
+		`{"_entities":[{"__typename":"Product","shippingEstimate":10},{"__typename":"Product","shippingEstimate":15}]}`)
+
+	renderer := &countingVariableRenderer{
+		VariableRenderer: NewGraphQLVariableResolveRenderer(&Object{
+			Fields: []*Field{
+				{
+					Name:  []byte("__typename"),
+					Value: &String{Path: []string{"__typename"}},
+				},
+				{
+					Name:  []byte("upc"),
+					Value: &String{Path: []string{"upc"}},
+				},
+				{
+					Name:  []byte("weight"),
+					Value: &Integer{Path: []string{"weight"}},
+				},
+			},
+		}),
+	}
+
+	response := &GraphQLResponse{
+		Data: &Object{
+			Fetch: &SingleFetch{
+				InputTemplate: InputTemplate{
+					Segments: []TemplateSegment{
+						{
+							Data:        []byte(`{"method":"POST","url":"http://products","body":{"query":"query{topProducts{__typename upc weight}}"}}`),
+							SegmentType: StaticSegmentType,
+						},
+					},
+				},
+				FetchConfiguration: FetchConfiguration{
+					DataSource: productsService,
+					PostProcessing: PostProcessingConfiguration{
+						SelectResponseDataPath: []string{"data"},
+					},
+				},
+			},
+			Fields: []*Field{
+				{
+					Name: []byte("topProducts"),
+					Value: &Array{
+						Path: []string{"topProducts"},
+						Item: &Object{
+							Fetch: &BatchEntityFetch{
+								Input: BatchInput{
+									Header: InputTemplate{
+										Segments: []TemplateSegment{
+											{
+												Data:        []byte(`{"method":"POST","url":"http://shipping","body":{"query":"query($representations: [_Any!]!){_entities(representations: $representations){__typename ... on Product {shippingEstimate}}}","variables":{"representations":[`),
+												SegmentType: StaticSegmentType,
+											},
+										},
+									},
+									Items: []InputTemplate{
+										{
+											Segments: []TemplateSegment{
+												{
+													SegmentType:  VariableSegmentType,
+													VariableKind: ResolvableObjectVariableKind,
+													Renderer:     renderer,
+												},
+											},
+										},
+									},
+									Separator: InputTemplate{
+										Segments: []TemplateSegment{
+											{
+												Data:        []byte(`,`),
+												SegmentType: StaticSegmentType,
+											},
+										},
+									},
+									Footer: InputTemplate{
+										Segments: []TemplateSegment{
+											{
+												Data:        []byte(`]}}}`),
+												SegmentType: StaticSegmentType,
+											},
+										},
+									},
+								},
+								DataSource: shippingService,
+								PostProcessing: PostProcessingConfiguration{
+									SelectResponseDataPath: []string{"data", "_entities"},
+								},
+							},
+							Fields: []*Field{
+								{
+									Name:  []byte("upc"),
+									Value: &String{Path: []string{"upc"}},
+								},
+								{
+									Name:  []byte("shippingEstimate"),
+									Value: &Integer{Path: []string{"shippingEstimate"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	ctx := NewContext(context.Background())
+	resolvable := NewResolvable()
+	loader := &Loader{}
+	err := resolvable.Init(ctx, nil, ast.OperationTypeQuery)
+	assert.NoError(t, err)
+	err = loader.LoadGraphQLResponseData(ctx, response, resolvable)
+	assert.NoError(t, err)
+	ctrl.Finish()
+
+	// the representation of the second product is served from the cache
+	assert.Equal(t, 2, renderer.renders)
+
+	out := &bytes.Buffer{}
+	err = resolvable.storage.PrintNode(resolvable.storage.Nodes[resolvable.storage.RootNode], out)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"errors":[],"data":{"topProducts":[{"__typename":"Product","upc":"1","weight":2,"shippingEstimate":10},{"__typename":"Product","upc":"1","weight":2,"shippingEstimate":10},{"__typename":"Product","upc":"2","weight":3,"shippingEstimate":15}]}}`, out.String())
+
+	loader.Free()
+	assert.Nil(t, loader.representations.byTemplate)
+}
+
+func TestLoader_ProfilerLabels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	var labels []string
+	service := NewMockDataSource(ctrl)
+	service.EXPECT().
+		Load(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&bytes.Buffer{})).
+		DoAndReturn(func(ctx context.Context, input []byte, w io.Writer) (err error) {
+			operation, _ := pprof.Label(ctx, ProfilerLabelOperationName)
+			dataSource, _ := pprof.Label(ctx, ProfilerLabelDataSourceID)
+			labels = append(labels, operation, dataSource)
+			_, err = w.Write([]byte(`{"data":{"user":{"name":"Bill"}}}`))
+			return
+		}).Times(2)
+
+	response := &GraphQLResponse{
+		Data: &Object{
+			Fetch: &SingleFetch{
+				InputTemplate: InputTemplate{
+					Segments: []TemplateSegment{
+						{
+							Data:        []byte(`{"url":"http://users","body":{"query":"{user{name}}"}}`),
+							SegmentType: StaticSegmentType,
+						},
+					},
+				},
+				FetchConfiguration: FetchConfiguration{
+					DataSource: service,
+					PostProcessing: PostProcessingConfiguration{
+						SelectResponseDataPath: []string{"data"},
+					},
+				},
+				Info: &FetchInfo{
+					DataSourceID: "users",
+				},
+			},
+		},
+	}
+
+	load := func(enabled bool) {
+		ctx := NewContext(pprof.WithLabels(context.Background(), pprof.Labels(ProfilerLabelOperationName, "GetUser")))
+		ctx.SetProfilerLabels(enabled)
+		resolvable := NewResolvable()
+		loader := &Loader{}
+		err := resolvable.Init(ctx, nil, ast.OperationTypeQuery)
+		require.NoError(t, err)
+		err = loader.LoadGraphQLResponseData(ctx, response, resolvable)
+		require.NoError(t, err)
+	}
+
+	load(true)
+	load(false)
+	ctrl.Finish()
+
+	assert.Equal(t, []string{"GetUser", "users", "GetUser", ""}, labels)
+}
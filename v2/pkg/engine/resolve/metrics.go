@@ -0,0 +1,103 @@
+package resolve
+
+import (
+	"strconv"
+)
+
+// Counter is a counter of a metrics backend, e.g. a prometheus.CounterVec
+// The label values are passed in the order of the labels documented at the instrument
+type Counter interface {
+	Add(value float64, labelValues ...string)
+}
+
+// Gauge is a value of a metrics backend which goes up and down, e.g. a prometheus.GaugeVec
+type Gauge interface {
+	Add(value float64, labelValues ...string)
+}
+
+// Histogram records the distribution of values of a metrics backend, e.g. a prometheus.HistogramVec
+type Histogram interface {
+	Observe(value float64, labelValues ...string)
+}
+
+// Metrics are the instruments recording the metrics of the resolver, instruments which are nil aren't recorded
+// The fetches are attributed to the DataSourceID of their FetchInfo, so the plans have to be created with IncludeInfo
+type Metrics struct {
+	// FetchDuration observes the duration of each fetch in seconds, labeled with the datasource id
+	// and whether the fetch failed, "true" or "false"
+	FetchDuration Histogram
+	// ActiveSubscriptions is the number of active subscriptions
+	ActiveSubscriptions Gauge
+	// ActiveTriggers is the number of upstream subscriptions, subscriptions with the same input share a trigger
+	ActiveTriggers Gauge
+	// SubscriptionUpdates counts the messages sent to subscriptions
+	SubscriptionUpdates Counter
+}
+
+// metricsRecorder records the fetches and the subscription reports to the instruments of the Metrics
+// and forwards them to the DataSourceMetrics and the Reporter of the ResolverOptions
+type metricsRecorder struct {
+	metrics           Metrics
+	dataSourceMetrics DataSourceMetrics
+	reporter          Reporter
+}
+
+func (m *metricsRecorder) RecordFetch(dataSourceID string, stats FetchStats) {
+	if m.metrics.FetchDuration != nil {
+		m.metrics.FetchDuration.Observe(stats.Duration.Seconds(), dataSourceID, strconv.FormatBool(stats.Failed))
+	}
+	if m.dataSourceMetrics != nil {
+		m.dataSourceMetrics.RecordFetch(dataSourceID, stats)
+	}
+}
+
+func (m *metricsRecorder) SubscriptionUpdateSent() {
+	if m.metrics.SubscriptionUpdates != nil {
+		m.metrics.SubscriptionUpdates.Add(1)
+	}
+	if m.reporter != nil {
+		m.reporter.SubscriptionUpdateSent()
+	}
+}
+
+func (m *metricsRecorder) SubscriptionCountInc(count int) {
+	if m.metrics.ActiveSubscriptions != nil {
+		m.metrics.ActiveSubscriptions.Add(float64(count))
+	}
+	if m.reporter != nil {
+		m.reporter.SubscriptionCountInc(count)
+	}
+}
+
+func (m *metricsRecorder) SubscriptionCountDec(count int) {
+	if m.metrics.ActiveSubscriptions != nil {
+		m.metrics.ActiveSubscriptions.Add(-float64(count))
+	}
+	if m.reporter != nil {
+		m.reporter.SubscriptionCountDec(count)
+	}
+}
+
+func (m *metricsRecorder) TriggerCountInc(count int) {
+	if m.metrics.ActiveTriggers != nil {
+		m.metrics.ActiveTriggers.Add(float64(count))
+	}
+	if m.reporter != nil {
+		m.reporter.TriggerCountInc(count)
+	}
+}
+
+func (m *metricsRecorder) TriggerCountDec(count int) {
+	if m.metrics.ActiveTriggers != nil {
+		m.metrics.ActiveTriggers.Add(-float64(count))
+	}
+	if m.reporter != nil {
+		m.reporter.TriggerCountDec(count)
+	}
+}
+
+// Interface Guards
+var (
+	_ DataSourceMetrics = (*metricsRecorder)(nil)
+	_ Reporter          = (*metricsRecorder)(nil)
+)
@@ -0,0 +1,60 @@
+package resolve
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testInstrument records the values per joined label values
+type testInstrument map[string]float64
+
+func (i testInstrument) Add(value float64, labelValues ...string) {
+	i[strings.Join(labelValues, ",")] += value
+}
+
+func (i testInstrument) Observe(value float64, labelValues ...string) {
+	i[strings.Join(labelValues, ",")] += value
+}
+
+func TestMetricsRecorder(t *testing.T) {
+	fetchDuration, subscriptions, triggers, updates := testInstrument{}, testInstrument{}, testInstrument{}, testInstrument{}
+	telemetry := NewDataSourceTelemetry()
+	counter := &subscriptionCounter{}
+	recorder := &metricsRecorder{
+		metrics: Metrics{
+			FetchDuration:       fetchDuration,
+			ActiveSubscriptions: subscriptions,
+			ActiveTriggers:      triggers,
+			SubscriptionUpdates: updates,
+		},
+		dataSourceMetrics: telemetry,
+		reporter:          counter,
+	}
+
+	recorder.RecordFetch("products", FetchStats{Duration: 2 * time.Second})
+	recorder.RecordFetch("products", FetchStats{Duration: time.Second, Failed: true})
+	recorder.SubscriptionCountInc(3)
+	recorder.SubscriptionCountDec(1)
+	recorder.TriggerCountInc(1)
+	recorder.SubscriptionUpdateSent()
+
+	assert.Equal(t, testInstrument{"products,false": 2, "products,true": 1}, fetchDuration)
+	assert.Equal(t, testInstrument{"": 2}, subscriptions)
+	assert.Equal(t, testInstrument{"": 1}, triggers)
+	assert.Equal(t, testInstrument{"": 1}, updates)
+
+	// the fetches and reports are forwarded
+	assert.Equal(t, uint64(2), telemetry.Snapshot()["products"].Requests)
+	assert.Equal(t, int64(2), counter.subscriptions.Load())
+	assert.Equal(t, int64(1), counter.triggers.Load())
+
+	t.Run("instruments which are nil are not recorded", func(t *testing.T) {
+		recorder := &metricsRecorder{}
+		recorder.RecordFetch("products", FetchStats{})
+		recorder.SubscriptionCountInc(1)
+		recorder.SubscriptionUpdateSent()
+	})
+}
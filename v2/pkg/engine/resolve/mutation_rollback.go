@@ -0,0 +1,89 @@
+package resolve
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/pool"
+)
+
+// MutationRollbackHook allows to implement compensation logic for top level mutations
+// Top level mutation fields are executed serially. The hook only observes the execution, all mutations are executed.
+// If a mutation failed, the hook is called once after the last mutation with all mutations of the operation which completed successfully.
+// A mutation fails if its fetch adds errors to the response, e.g. because the subgraph returned errors
+type MutationRollbackHook interface {
+	Rollback(ctx *Context, completed []CompletedMutationFetch)
+}
+
+// MutationRollbackHookFunc allows to use a function as a MutationRollbackHook
+type MutationRollbackHookFunc func(ctx *Context, completed []CompletedMutationFetch)
+
+func (f MutationRollbackHookFunc) Rollback(ctx *Context, completed []CompletedMutationFetch) {
+	f(ctx, completed)
+}
+
+// CompletedMutationFetch describes a top level mutation fetch which was executed successfully
+type CompletedMutationFetch struct {
+	// Info is only set if the plan was created with IncludeInfo
+	Info                 *FetchInfo
+	DataSourceIdentifier []byte
+	// Input is the rendered input of the fetch, e.g. containing the variables of the mutation
+	Input []byte
+	// Response is the raw response of the fetch
+	Response []byte
+}
+
+// tracksMutationFetches returns true if the serial fetches of the root object are top level mutations
+// which are passed to the rollback hook on failure
+func (l *Loader) tracksMutationFetches() bool {
+	return l.ctx.mutationRollbackHook != nil && len(l.path) == 0
+}
+
+func (l *Loader) resolveSerialMutationFetches(fetch *SerialFetch, items []int) error {
+	completed := make([]CompletedMutationFetch, 0, len(fetch.Fetches))
+	failed := false
+	for i := range fetch.Fetches {
+		single, ok := fetch.Fetches[i].(*SingleFetch)
+		if !ok {
+			// top level mutations are planned as single fetches, other fetches are not tracked
+			err := l.resolveAndMergeFetch(fetch.Fetches[i], items)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			continue
+		}
+		errorCount := l.errorCount()
+		res := l.newResult()
+		res.out = pool.BytesBuffer.Get()
+		res.recordInput = true
+		err := l.loadSingleFetch(l.ctx.ctx, single, items, res)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		mutation := CompletedMutationFetch{
+			Info:                 single.Info,
+			DataSourceIdentifier: single.DataSourceIdentifier,
+			Input:                res.input,
+			Response:             append([]byte(nil), res.out.Bytes()...),
+		}
+		err = l.mergeResult(res, items)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if l.errorCount() > errorCount {
+			failed = true
+			continue
+		}
+		completed = append(completed, mutation)
+	}
+	if failed && len(completed) != 0 {
+		l.ctx.mutationRollbackHook.Rollback(l.ctx, completed)
+	}
+	return nil
+}
+
+func (l *Loader) errorCount() int {
+	if l.errorsRoot == -1 {
+		return 0
+	}
+	return len(l.data.Nodes[l.errorsRoot].ArrayValues)
+}
@@ -6,6 +6,8 @@ type Array struct {
 	ResolveAsynchronous bool
 	Item                Node
 	Items               []Node
+	// PostProcessing filters and sorts the items in the resolver, see ListPostProcessing
+	PostProcessing *ListPostProcessing
 }
 
 func (a *Array) HasChildFetches() bool {
@@ -10,6 +10,20 @@ type Object struct {
 	Fields               []*Field
 	Fetch                Fetch
 	UnescapeResponseJson bool `json:"unescape_response_json,omitempty"`
+	// Pagination is set when the resolver should slice the edges of a Relay-style connection
+	Pagination *ConnectionPagination `json:"pagination,omitempty"`
+}
+
+func (o *Object) fieldByName(name string) *Field {
+	if name == "" {
+		return nil
+	}
+	for i := range o.Fields {
+		if string(o.Fields[i].Name) == name {
+			return o.Fields[i]
+		}
+	}
+	return nil
 }
 
 func (o *Object) HasChildFetches() bool {
@@ -79,6 +93,11 @@ type Field struct {
 	IncludeDirectiveDefined bool
 	IncludeVariableName     string
 	Info                    *FieldInfo
+	// PII tags the field as personally identifiable information, its value is masked if the Context masks PII
+	PII bool
+	// EncodeCoordinate is the coordinate the value of the field is encoded for with the FieldValueTransformer of the Context
+	// nil disables encoding
+	EncodeCoordinate *GraphCoordinate
 }
 
 type FieldInfo struct {
@@ -0,0 +1,95 @@
+package resolve
+
+import (
+	"encoding/json"
+
+	"github.com/buger/jsonparser"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astjson"
+)
+
+var (
+	piiMask = []byte("****")
+	piiZero = []byte("0")
+)
+
+// SetMaskPII enables masking the values of fields tagged as PII in the response, e.g. for roles without access to PII
+// String and custom scalar values are replaced with ****, the values of other nullable fields are set to null
+// and the values of other non-nullable fields are replaced with the zero value of their type, e.g. false, 0 or an empty list
+func (c *Context) SetMaskPII(mask bool) {
+	c.maskPII = mask
+}
+
+func (r *Resolvable) maskPIIField(ref int, field *Field) {
+	if !field.PII || !r.ctx.maskPII {
+		return
+	}
+	r.maskPIIValue(ref, field.Value)
+}
+
+// maskPIIValue masks the value of the node without turning a valid response into an error,
+// so non-nullable values are never set to null
+func (r *Resolvable) maskPIIValue(ref int, node Node) {
+	value := r.storage.Get(ref, node.NodePath())
+	if !r.storage.NodeIsDefined(value) || r.storage.Nodes[value].Kind == astjson.NodeKindNull {
+		return
+	}
+	switch n := node.(type) {
+	case *String, *Scalar:
+		r.replacePIIValue(value, r.storage.AppendStringBytes(piiMask))
+		return
+	case *Object:
+		if !n.Nullable {
+			// the object is kept and its fields are masked instead
+			for _, field := range n.Fields {
+				r.maskPIIValue(value, field.Value)
+			}
+			return
+		}
+	}
+	if node.NodeNullable() {
+		r.storage.Nodes[value].Kind = astjson.NodeKindNull
+		return
+	}
+	var zero []byte
+	switch node.(type) {
+	case *Boolean:
+		zero = literalFalse
+	case *Integer, *Float, *BigInt:
+		zero = piiZero
+	case *Array:
+		zero = emptyArray
+	default:
+		r.storage.Nodes[value].Kind = astjson.NodeKindNull
+		return
+	}
+	masked, err := r.storage.AppendAnyJSONBytes(zero)
+	if err != nil {
+		r.storage.Nodes[value].Kind = astjson.NodeKindNull
+		return
+	}
+	r.replacePIIValue(value, masked)
+}
+
+func (r *Resolvable) replacePIIValue(value, masked int) {
+	r.storage.Nodes[value] = r.storage.Nodes[masked]
+}
+
+// redactPIIVariables replaces the variables of the upstream request in body.variables
+// which are PII variables of the operation, the planners keep the names of the variables of the operation
+func redactPIIVariables(input json.RawMessage, piiVariables []string) json.RawMessage {
+	if len(piiVariables) == 0 {
+		return input
+	}
+	for _, name := range piiVariables {
+		if _, _, _, err := jsonparser.Get(input, "body", "variables", name); err != nil {
+			continue
+		}
+		output, err := jsonparser.Set(input, []byte(`"`+string(piiMask)+`"`), "body", "variables", name)
+		if err != nil {
+			return input
+		}
+		input = output
+	}
+	return input
+}
@@ -0,0 +1,150 @@
+package resolve
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+func TestResolvable_MaskPII(t *testing.T) {
+	data := `{"user":{"name":"Jens","email":"jens@example.com","age":42,"ssn":"123"}}`
+	object := &Object{
+		Fields: []*Field{
+			{
+				Name: []byte("user"),
+				Value: &Object{
+					Path:     []string{"user"},
+					Nullable: true,
+					Fields: []*Field{
+						{
+							Name:  []byte("name"),
+							Value: &String{Path: []string{"name"}},
+						},
+						{
+							Name:  []byte("email"),
+							Value: &String{Path: []string{"email"}, Nullable: true},
+							PII:   true,
+						},
+						{
+							Name:  []byte("age"),
+							Value: &Integer{Path: []string{"age"}, Nullable: true},
+							PII:   true,
+						},
+						{
+							Name:  []byte("ssn"),
+							Value: &Scalar{Path: []string{"ssn"}, Nullable: true},
+							PII:   true,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	resolve := func(t *testing.T, maskPII bool) string {
+		ctx := NewContext(context.Background())
+		ctx.SetMaskPII(maskPII)
+		res := NewResolvable()
+		require.NoError(t, res.Init(ctx, []byte(data), ast.OperationTypeQuery))
+		out := &bytes.Buffer{}
+		require.NoError(t, res.Resolve(context.Background(), object, out))
+		return out.String()
+	}
+
+	t.Run("should not mask without masking enabled", func(t *testing.T) {
+		assert.Equal(t, `{"data":{"user":{"name":"Jens","email":"jens@example.com","age":42,"ssn":"123"}}}`, resolve(t, false))
+	})
+
+	t.Run("should mask PII fields", func(t *testing.T) {
+		assert.Equal(t, `{"data":{"user":{"name":"Jens","email":"****","age":null,"ssn":"****"}}}`, resolve(t, true))
+	})
+
+	t.Run("should mask non-nullable PII fields with values of their type", func(t *testing.T) {
+		data := `{"user":{"verified":true,"age":42,"score":1.5,"tags":["a"],"address":{"street":"Main","zip":12345,"country":null}}}`
+		object := &Object{
+			Fields: []*Field{
+				{
+					Name: []byte("user"),
+					Value: &Object{
+						Path: []string{"user"},
+						Fields: []*Field{
+							{
+								Name:  []byte("verified"),
+								Value: &Boolean{Path: []string{"verified"}},
+								PII:   true,
+							},
+							{
+								Name:  []byte("age"),
+								Value: &Integer{Path: []string{"age"}},
+								PII:   true,
+							},
+							{
+								Name:  []byte("score"),
+								Value: &Float{Path: []string{"score"}},
+								PII:   true,
+							},
+							{
+								Name:  []byte("tags"),
+								Value: &Array{Path: []string{"tags"}, Item: &String{}},
+								PII:   true,
+							},
+							{
+								Name: []byte("address"),
+								Value: &Object{
+									Path: []string{"address"},
+									Fields: []*Field{
+										{
+											Name:  []byte("street"),
+											Value: &String{Path: []string{"street"}},
+										},
+										{
+											Name:  []byte("zip"),
+											Value: &Integer{Path: []string{"zip"}},
+										},
+										{
+											Name:  []byte("country"),
+											Value: &String{Path: []string{"country"}, Nullable: true},
+										},
+									},
+								},
+								PII: true,
+							},
+						},
+					},
+				},
+			},
+		}
+
+		ctx := NewContext(context.Background())
+		ctx.SetMaskPII(true)
+		res := NewResolvable()
+		require.NoError(t, res.Init(ctx, []byte(data), ast.OperationTypeQuery))
+		out := &bytes.Buffer{}
+		require.NoError(t, res.Resolve(context.Background(), object, out))
+		assert.Equal(t, `{"data":{"user":{"verified":false,"age":0,"score":0,"tags":[],"address":{"street":"****","zip":0,"country":null}}}}`, out.String())
+	})
+}
+
+func TestRedactPIIVariables(t *testing.T) {
+	input := []byte(`{"method":"POST","url":"http://localhost","body":{"query":"query($email: String, $id: ID, $filter: Filter){user(email: $email, id: $id, filter: $filter){name}}","variables":{"email":"jens@example.com","id":"1","filter":{"ssn":"123"}}}}`)
+
+	t.Run("should redact the values of PII variables", func(t *testing.T) {
+		redacted := redactPIIVariables(input, []string{"email", "filter"})
+		assert.Equal(t, `{"method":"POST","url":"http://localhost","body":{"query":"query($email: String, $id: ID, $filter: Filter){user(email: $email, id: $id, filter: $filter){name}}","variables":{"email":"****","id":"1","filter":"****"}}}`, string(redacted))
+	})
+
+	t.Run("should not redact variables with the value of a PII variable", func(t *testing.T) {
+		input := []byte(`{"body":{"variables":{"email":"1","id":"1"}}}`)
+		assert.Equal(t, `{"body":{"variables":{"email":"****","id":"1"}}}`, string(redactPIIVariables(input, []string{"email"})))
+	})
+
+	t.Run("should keep the input without PII variables", func(t *testing.T) {
+		assert.Equal(t, string(input), string(redactPIIVariables(input, nil)))
+		assert.Equal(t, string(input), string(redactPIIVariables(input, []string{"unknown"})))
+	})
+}
@@ -0,0 +1,30 @@
+package resolve
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+const (
+	// ProfilerLabelOperationName is the pprof label of the name of the operation
+	ProfilerLabelOperationName = "graphql_operation"
+	// ProfilerLabelDataSourceID is the pprof label of the datasource of a fetch
+	ProfilerLabelDataSourceID = "graphql_datasource"
+)
+
+// SetProfilerLabels labels the fetches of the operation with the ID of their datasource, see ProfilerLabelDataSourceID,
+// so the CPU samples of the datasources are attributed to the subgraphs.
+// The labels are added to the labels of the context, e.g. ProfilerLabelOperationName.
+// Fetches without FetchInfo are not labeled.
+func (c *Context) SetProfilerLabels(enabled bool) {
+	c.profilerLabels = enabled
+}
+
+// loadWithProfilerLabels calls load with the labels of the datasource, when profiler labels are enabled
+func (l *Loader) loadWithProfilerLabels(ctx context.Context, info *FetchInfo, load func(ctx context.Context)) {
+	if !l.ctx.profilerLabels || info == nil {
+		load(ctx)
+		return
+	}
+	pprof.Do(ctx, pprof.Labels(ProfilerLabelDataSourceID, info.DataSourceID), load)
+}
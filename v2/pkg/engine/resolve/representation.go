@@ -0,0 +1,201 @@
+package resolve
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/buger/jsonparser"
+)
+
+// InvalidRepresentationError is returned when the value of a key field doesn't match the type of the field,
+// so the representation of the entity is not sent to the subgraph.
+type InvalidRepresentationError struct {
+	// TypeName of the entity
+	TypeName string
+	// FieldPath of the key field, e.g. "id" or "owner.ids[1]"
+	FieldPath string
+	// ExpectedType is the type of the key field, e.g. "Int"
+	ExpectedType string
+	// ActualType is the json type of the value, e.g. "string"
+	ActualType string
+}
+
+func (e *InvalidRepresentationError) Error() string {
+	return fmt.Sprintf("key field '%s' of entity '%s' expects %s, got %s", e.FieldPath, e.TypeName, e.ExpectedType, e.ActualType)
+}
+
+// validateRepresentations validates the data of an entity against the representations rendered by the input template
+// of an entity fetch, the representations are the resolvable object variables of the template.
+func validateRepresentations(template InputTemplate, data []byte) error {
+	for i := range template.Segments {
+		if template.Segments[i].VariableKind != ResolvableObjectVariableKind {
+			continue
+		}
+		renderer, ok := template.Segments[i].Renderer.(*GraphQLVariableResolveRenderer)
+		if !ok {
+			continue
+		}
+		representation, ok := renderer.Node.(*Object)
+		if !ok {
+			continue
+		}
+		if err := validateRepresentation(representation, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateRepresentation checks the values of the key fields in data against the nodes of the representation.
+// Missing and null values are not validated, they are handled by the nullability of the nodes while rendering.
+// Fields which are not on the __typename of data belong to another entity and are skipped.
+func validateRepresentation(representation *Object, data []byte) error {
+	if len(representation.Path) != 0 {
+		var dataType jsonparser.ValueType
+		data, dataType, _, _ = jsonparser.Get(data, representation.Path...)
+		if dataType != jsonparser.Object {
+			return nil
+		}
+	}
+	typeName, _, _, _ := jsonparser.Get(data, "__typename")
+	for i := range representation.Fields {
+		field := representation.Fields[i]
+		if field.OnTypeNames != nil && !bytesContains(field.OnTypeNames, typeName) {
+			continue
+		}
+		if err := validateRepresentationValue(field.Value, data, string(field.Name), string(typeName)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateRepresentationValue(node Node, data []byte, fieldPath, typeName string) error {
+	var (
+		path         []string
+		expectedType string
+		valid        func(value []byte, dataType jsonparser.ValueType) bool
+	)
+	switch n := node.(type) {
+	case *String:
+		path, expectedType = n.Path, "String"
+		valid = func(_ []byte, dataType jsonparser.ValueType) bool {
+			return dataType == jsonparser.String
+		}
+	case *Integer:
+		path, expectedType = n.Path, "Int"
+		valid = func(value []byte, dataType jsonparser.ValueType) bool {
+			if dataType != jsonparser.Number {
+				return false
+			}
+			_, err := strconv.ParseInt(string(value), 10, 32)
+			return err == nil
+		}
+	case *Float:
+		path, expectedType = n.Path, "Float"
+		valid = func(_ []byte, dataType jsonparser.ValueType) bool {
+			return dataType == jsonparser.Number
+		}
+	case *Boolean:
+		path, expectedType = n.Path, "Boolean"
+		valid = func(_ []byte, dataType jsonparser.ValueType) bool {
+			return dataType == jsonparser.Boolean
+		}
+	case *Object:
+		path, expectedType = n.Path, "an object"
+		valid = func(_ []byte, dataType jsonparser.ValueType) bool {
+			return dataType == jsonparser.Object
+		}
+	case *Array:
+		path, expectedType = n.Path, "a list"
+		valid = func(_ []byte, dataType jsonparser.ValueType) bool {
+			return dataType == jsonparser.Array
+		}
+	default:
+		// custom scalars accept any value
+		return nil
+	}
+
+	value, dataType, _, err := jsonparser.Get(data, path...)
+	if err != nil || dataType == jsonparser.Null {
+		return nil
+	}
+	if !valid(value, dataType) {
+		return &InvalidRepresentationError{
+			TypeName:     typeName,
+			FieldPath:    fieldPath,
+			ExpectedType: expectedType,
+			ActualType:   dataType.String(),
+		}
+	}
+
+	switch n := node.(type) {
+	case *Object:
+		for i := range n.Fields {
+			err = validateRepresentationValue(n.Fields[i].Value, value, fieldPath+"."+string(n.Fields[i].Name), typeName)
+			if err != nil {
+				return err
+			}
+		}
+	case *Array:
+		index := 0
+		_, _ = jsonparser.ArrayEach(value, func(item []byte, itemType jsonparser.ValueType, _ int, _ error) {
+			if err == nil {
+				if itemType == jsonparser.String {
+					// restore the quotes, so the item is read as string
+					item = []byte(strconv.Quote(string(item)))
+				}
+				err = validateRepresentationValue(n.Item, item, fmt.Sprintf("%s[%d]", fieldPath, index), typeName)
+			}
+			index++
+		})
+		return err
+	}
+	return nil
+}
+
+func bytesContains(values [][]byte, value []byte) bool {
+	for i := range values {
+		if bytes.Equal(values[i], value) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderedRepresentations caches the rendered representations of entities within a request.
+// Sibling entities with the same data, e.g. list items requiring the same fields of the parent through @requires,
+// extract and render the representation of a template once.
+// Fetches of a request run in parallel, so the cache is safe for concurrent use.
+type renderedRepresentations struct {
+	mu         sync.Mutex
+	byTemplate map[*InputTemplate]map[string][]byte
+}
+
+func (r *renderedRepresentations) get(template *InputTemplate, data []byte) ([]byte, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rendered, ok := r.byTemplate[template][string(data)]
+	return rendered, ok
+}
+
+// add caches a copy of the representation rendered by the template from data
+func (r *renderedRepresentations) add(template *InputTemplate, data, rendered []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.byTemplate == nil {
+		r.byTemplate = map[*InputTemplate]map[string][]byte{}
+	}
+	if r.byTemplate[template] == nil {
+		r.byTemplate[template] = map[string][]byte{}
+	}
+	r.byTemplate[template][string(data)] = bytes.Clone(rendered)
+}
+
+func (r *renderedRepresentations) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byTemplate = nil
+}
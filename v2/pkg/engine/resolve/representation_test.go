@@ -0,0 +1,121 @@
+package resolve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRepresentation(t *testing.T) {
+	representation := &Object{
+		Nullable: true,
+		Fields: []*Field{
+			{
+				Name:        []byte("__typename"),
+				Value:       &String{Path: []string{"__typename"}},
+				OnTypeNames: [][]byte{[]byte("User")},
+			},
+			{
+				Name:        []byte("id"),
+				Value:       &Scalar{Path: []string{"id"}},
+				OnTypeNames: [][]byte{[]byte("User")},
+			},
+			{
+				Name:        []byte("number"),
+				Value:       &Integer{Path: []string{"number"}, Nullable: true},
+				OnTypeNames: [][]byte{[]byte("User")},
+			},
+			{
+				Name: []byte("account"),
+				Value: &Object{
+					Path:     []string{"account"},
+					Nullable: true,
+					Fields: []*Field{
+						{
+							Name:  []byte("name"),
+							Value: &String{Path: []string{"name"}},
+						},
+						{
+							Name: []byte("tags"),
+							Value: &Array{
+								Path:     []string{"tags"},
+								Nullable: true,
+								Item:     &String{},
+							},
+						},
+					},
+				},
+				OnTypeNames: [][]byte{[]byte("User")},
+			},
+			{
+				Name:        []byte("upc"),
+				Value:       &String{Path: []string{"upc"}},
+				OnTypeNames: [][]byte{[]byte("Product")},
+			},
+		},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		err := validateRepresentation(representation, []byte(`{"__typename":"User","id":1,"number":2,"account":{"name":"a","tags":["b","c"]}}`))
+		assert.NoError(t, err)
+	})
+
+	t.Run("missing and null values are not validated", func(t *testing.T) {
+		err := validateRepresentation(representation, []byte(`{"__typename":"User","number":null,"account":{"tags":null}}`))
+		assert.NoError(t, err)
+	})
+
+	t.Run("fields of other types are not validated", func(t *testing.T) {
+		err := validateRepresentation(representation, []byte(`{"__typename":"Product","number":"1","upc":"top-1"}`))
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid scalar", func(t *testing.T) {
+		err := validateRepresentation(representation, []byte(`{"__typename":"User","number":"1"}`))
+		require.Error(t, err)
+		assert.Equal(t, &InvalidRepresentationError{
+			TypeName:     "User",
+			FieldPath:    "number",
+			ExpectedType: "Int",
+			ActualType:   "string",
+		}, err)
+		assert.EqualError(t, err, "key field 'number' of entity 'User' expects Int, got string")
+	})
+
+	t.Run("number which is not an Int", func(t *testing.T) {
+		err := validateRepresentation(representation, []byte(`{"__typename":"User","number":1.5}`))
+		assert.EqualError(t, err, "key field 'number' of entity 'User' expects Int, got number")
+	})
+
+	t.Run("invalid object", func(t *testing.T) {
+		err := validateRepresentation(representation, []byte(`{"__typename":"User","account":"a"}`))
+		assert.EqualError(t, err, "key field 'account' of entity 'User' expects an object, got string")
+	})
+
+	t.Run("invalid nested field", func(t *testing.T) {
+		err := validateRepresentation(representation, []byte(`{"__typename":"User","account":{"name":true}}`))
+		assert.EqualError(t, err, "key field 'account.name' of entity 'User' expects String, got boolean")
+	})
+
+	t.Run("invalid list item", func(t *testing.T) {
+		err := validateRepresentation(representation, []byte(`{"__typename":"User","account":{"name":"a","tags":["b",1]}}`))
+		assert.EqualError(t, err, "key field 'account.tags[1]' of entity 'User' expects String, got number")
+	})
+
+	t.Run("representation with path", func(t *testing.T) {
+		withPath := &Object{
+			Path: []string{"address"},
+			Fields: []*Field{
+				{
+					Name:  []byte("id"),
+					Value: &Integer{Path: []string{"id"}},
+				},
+			},
+		}
+
+		assert.NoError(t, validateRepresentation(withPath, []byte(`{"address":null}`)))
+		err := validateRepresentation(withPath, []byte(`{"address":{"__typename":"Address","id":true}}`))
+		assert.EqualError(t, err, "key field 'id' of entity 'Address' expects Int, got boolean")
+	})
+}
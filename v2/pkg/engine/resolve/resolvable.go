@@ -6,7 +6,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strconv"
 
+	"github.com/buger/jsonparser"
 	"github.com/cespare/xxhash/v2"
 	"github.com/tidwall/gjson"
 
@@ -17,28 +19,36 @@ import (
 )
 
 type Resolvable struct {
-	storage            *astjson.JSON
-	dataRoot           int
-	errorsRoot         int
-	variablesRoot      int
-	print              bool
-	out                io.Writer
-	printErr           error
-	path               []astjson.PathElement
-	depth              int
-	operationType      ast.OperationType
-	renameTypeNames    []RenameTypeName
-	ctx                *Context
-	authorizationError error
-	xxh                *xxhash.Digest
-	authorizationAllow map[uint64]struct{}
-	authorizationDeny  map[uint64]string
+	storage       *astjson.JSON
+	dataRoot      int
+	errorsRoot    int
+	variablesRoot int
+	// subgraphExtensionsRoot holds the extensions passed through from subgraph responses
+	subgraphExtensionsRoot int
+	print                  bool
+	out                    io.Writer
+	printErr               error
+	path                   []astjson.PathElement
+	depth                  int
+	operationType          ast.OperationType
+	renameTypeNames        []RenameTypeName
+	ctx                    *Context
+	authorizationError     error
+	xxh                    *xxhash.Digest
+	authorizationAllow     map[uint64]struct{}
+	authorizationDeny      map[uint64]string
 
 	authorizationBuf          *bytes.Buffer
 	authorizationBufObjectRef int
 
 	wroteErrors bool
 	wroteData   bool
+
+	formatting     ResponseFormattingOptions
+	extensionsHook ResponseExtensionsHook
+	hookExtensions []byte
+	// ids generates the IDs of the fetches of the trace extension
+	ids IDGenerator
 }
 
 func NewResolvable() *Resolvable {
@@ -57,6 +67,8 @@ func (r *Resolvable) Reset() {
 	r.dataRoot = -1
 	r.errorsRoot = -1
 	r.variablesRoot = -1
+	r.subgraphExtensionsRoot = -1
+	r.hookExtensions = nil
 	r.depth = 0
 	r.print = false
 	r.out = nil
@@ -83,6 +95,10 @@ func (r *Resolvable) Init(ctx *Context, initialData []byte, operationType ast.Op
 	if err != nil {
 		return
 	}
+	r.subgraphExtensionsRoot, err = r.storage.AppendObject(emptyObject)
+	if err != nil {
+		return
+	}
 	if len(ctx.Variables) != 0 {
 		r.variablesRoot, err = r.storage.AppendAnyJSONBytes(ctx.Variables)
 	}
@@ -103,6 +119,10 @@ func (r *Resolvable) InitSubscription(ctx *Context, initialData []byte, postProc
 	if err != nil {
 		return
 	}
+	r.subgraphExtensionsRoot, err = r.storage.AppendObject(emptyObject)
+	if err != nil {
+		return
+	}
 	raw, err := r.storage.AppendObject(initialData)
 	if err != nil {
 		return err
@@ -119,10 +139,25 @@ func (r *Resolvable) InitSubscription(ctx *Context, initialData []byte, postProc
 }
 
 func (r *Resolvable) Resolve(ctx context.Context, root *Object, out io.Writer) error {
+	if r.formatting.Indent == "" {
+		return r.resolve(ctx, root, out)
+	}
+	buf := pool.BytesBuffer.Get()
+	defer pool.BytesBuffer.Put(buf)
+	if err := r.resolve(ctx, root, buf); err != nil {
+		return err
+	}
+	return r.writeIndented(buf.Bytes(), out)
+}
+
+func (r *Resolvable) resolve(ctx context.Context, root *Object, out io.Writer) error {
 	r.out = out
 	r.print = false
 	r.printErr = nil
 	r.authorizationError = nil
+	if err := r.loadHookExtensions(); err != nil {
+		return err
+	}
 
 	// if we have errors and no data, we only print the errors and set data to null
 	// in this case, we're skipping the walk because it would lead to unnecessary non-null errors
@@ -230,12 +265,39 @@ func (r *Resolvable) printExtensions(ctx context.Context, root *Object) error {
 		if writeComma {
 			r.printBytes(comma)
 		}
+		writeComma = true
 		err := r.printTraceExtension(ctx, root)
 		if err != nil {
 			return err
 		}
 	}
 
+	if r.hasApolloTracing() {
+		if writeComma {
+			r.printBytes(comma)
+		}
+		writeComma = true
+		err := r.printApolloTracingExtension()
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.hasSubgraphExtensions() {
+		if writeComma {
+			r.printBytes(comma)
+		}
+		writeComma = true
+		r.printSubgraphExtensions()
+	}
+
+	if r.hasHookExtensions() {
+		if writeComma {
+			r.printBytes(comma)
+		}
+		r.printBytes(r.hookExtensions)
+	}
+
 	r.printBytes(rBrace)
 	return nil
 }
@@ -261,7 +323,7 @@ func (r *Resolvable) printTraceExtension(ctx context.Context, root *Object) erro
 	if r.ctx.TracingOptions.Debug {
 		trace = GetTrace(ctx, root, GetTraceDebug())
 	} else {
-		trace = GetTrace(ctx, root)
+		trace = GetTrace(ctx, root, GetTraceIDGenerator(r.ids))
 	}
 	traceData, err := json.Marshal(trace)
 	if err != nil {
@@ -285,7 +347,7 @@ func (r *Resolvable) hasExtensions() bool {
 	if r.ctx.TracingOptions.Enable && r.ctx.TracingOptions.IncludeTraceOutputInResponseExtensions {
 		return true
 	}
-	return false
+	return r.hasApolloTracing() || r.hasSubgraphExtensions() || r.hasHookExtensions()
 }
 
 func (r *Resolvable) WroteErrorsWithoutData() bool {
@@ -402,6 +464,11 @@ func (r *Resolvable) walkObject(obj *Object, ref int) bool {
 		r.addError("Object cannot represent non-object value.", obj.Path)
 		return r.err()
 	}
+	if !r.print && obj.Pagination != nil {
+		if !r.applyConnectionPagination(obj, ref) {
+			return r.err()
+		}
+	}
 	if r.print && !isRoot {
 		r.printBytes(lBrace)
 		r.ctx.Stats.ResolvedObjects++
@@ -424,6 +491,8 @@ func (r *Resolvable) walkObject(obj *Object, ref int) bool {
 			}
 		}
 		if !r.print {
+			r.maskPIIField(ref, obj.Fields[i])
+			r.encodeField(ref, obj.Fields[i])
 			skip := r.authorizeField(ref, obj.Fields[i])
 			if skip {
 				if obj.Fields[i].Value.NodeNullable() {
@@ -446,6 +515,9 @@ func (r *Resolvable) walkObject(obj *Object, ref int) bool {
 			}
 		}
 		if r.print {
+			if r.formatting.StripNulls && r.fieldValueIsNull(ref, obj.Fields[i].Value) {
+				continue
+			}
 			if addComma {
 				r.printBytes(comma)
 			}
@@ -619,6 +691,12 @@ func (r *Resolvable) walkArray(arr *Array, ref int) bool {
 		r.addError("Array cannot represent non-array value.", arr.Path)
 		return r.err()
 	}
+	if !r.print && arr.PostProcessing != nil {
+		if message, ok := r.applyListPostProcessing(arr.PostProcessing, ref); !ok {
+			r.addError(message, nil)
+			return r.err()
+		}
+	}
 	if r.print {
 		r.printBytes(lBrack)
 	}
@@ -880,3 +958,42 @@ func (r *Resolvable) addError(message string, fieldPath []string) {
 	r.storage.Nodes[r.errorsRoot].ArrayValues = append(r.storage.Nodes[r.errorsRoot].ArrayValues, ref)
 	r.popNodePathElement(fieldPath)
 }
+
+func (r *Resolvable) variable(name string) int {
+	if name == "" || !r.storage.NodeIsDefined(r.variablesRoot) {
+		return -1
+	}
+	return r.storage.GetObjectField(r.variablesRoot, name)
+}
+
+func (r *Resolvable) variableInt(name string) (int, bool) {
+	ref := r.variable(name)
+	if !r.storage.NodeIsDefined(ref) || r.storage.Nodes[ref].Kind != astjson.NodeKindNumber {
+		return 0, false
+	}
+	value, err := strconv.Atoi(string(r.storage.Nodes[ref].ValueBytes(r.storage)))
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+func (r *Resolvable) variableString(name string) (string, bool) {
+	ref := r.variable(name)
+	if !r.storage.NodeIsDefined(ref) || r.storage.Nodes[ref].Kind != astjson.NodeKindString {
+		return "", false
+	}
+	value, err := jsonparser.ParseString(r.storage.Nodes[ref].ValueBytes(r.storage))
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+func (r *Resolvable) variableBoolean(name string) (bool, bool) {
+	ref := r.variable(name)
+	if !r.storage.NodeIsDefined(ref) || r.storage.Nodes[ref].Kind != astjson.NodeKindBoolean {
+		return false, false
+	}
+	return bytes.Equal(r.storage.Nodes[ref].ValueBytes(r.storage), literalTrue), true
+}
@@ -576,3 +576,130 @@ func TestResolvable_WithTracing(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, `{"data":{"topProducts":[{"name":"Table","stock":8,"reviews":[{"body":"Love Table!","author":{"name":"user-1"}},{"body":"Prefer other Table.","author":{"name":"user-2"}}]},{"name":"Couch","stock":2,"reviews":[{"body":"Couch Too expensive.","author":{"name":"user-1"}}]},{"name":"Chair","stock":5,"reviews":[{"body":"Chair Could be better.","author":{"name":"user-2"}}]}]},"extensions":{"trace":{"info":{"trace_start_time":"","trace_start_unix":0,"parse_stats":{"duration_nanoseconds":5,"duration_pretty":"5ns","duration_since_start_nanoseconds":5,"duration_since_start_pretty":"5ns"},"normalize_stats":{"duration_nanoseconds":5,"duration_pretty":"5ns","duration_since_start_nanoseconds":10,"duration_since_start_pretty":"10ns"},"validate_stats":{"duration_nanoseconds":5,"duration_pretty":"5ns","duration_since_start_nanoseconds":15,"duration_since_start_pretty":"15ns"},"planner_stats":{"duration_nanoseconds":5,"duration_pretty":"5ns","duration_since_start_nanoseconds":20,"duration_since_start_pretty":"20ns"}},"node_type":"object","nullable":true,"fields":[{"name":"topProducts","value":{"node_type":"array","path":["topProducts"],"items":[{"node_type":"object","nullable":true,"fields":[{"name":"name","value":{"node_type":"string","path":["name"]}},{"name":"stock","value":{"node_type":"integer","path":["stock"]}},{"name":"reviews","value":{"node_type":"array","path":["reviews"],"items":[{"node_type":"object","nullable":true,"fields":[{"name":"body","value":{"node_type":"string","path":["body"]}},{"name":"author","value":{"node_type":"object","path":["author"],"fields":[{"name":"name","value":{"node_type":"string","path":["name"]}}]}}]}]}}]}]}}]}}}`, out.String())
 }
+
+func TestResolvable_ResponseFormatting(t *testing.T) {
+	data := `{"user":{"id":"1","nickname":null,"name":"Jens","tags":["a",null]}}`
+	object := &Object{
+		Fields: []*Field{
+			{
+				Name: []byte("user"),
+				Value: &Object{
+					Path: []string{"user"},
+					Fields: []*Field{
+						{
+							Name:  []byte("name"),
+							Value: &String{Path: []string{"name"}},
+						},
+						{
+							Name:  []byte("nickname"),
+							Value: &String{Path: []string{"nickname"}, Nullable: true},
+						},
+						{
+							Name:  []byte("email"),
+							Value: &String{Path: []string{"email"}, Nullable: true},
+						},
+						{
+							Name: []byte("tags"),
+							Value: &Array{
+								Path: []string{"tags"},
+								Item: &String{Nullable: true},
+							},
+						},
+						{
+							Name:  []byte("id"),
+							Value: &String{Path: []string{"id"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	resolve := func(t *testing.T, formatting ResponseFormattingOptions) string {
+		res := NewResolvable()
+		res.formatting = formatting
+		err := res.Init(&Context{}, []byte(data), ast.OperationTypeQuery)
+		assert.NoError(t, err)
+		out := &bytes.Buffer{}
+		err = res.Resolve(context.Background(), object, out)
+		assert.NoError(t, err)
+		return out.String()
+	}
+
+	t.Run("selection order", func(t *testing.T) {
+		out := resolve(t, ResponseFormattingOptions{})
+		assert.Equal(t, `{"data":{"user":{"name":"Jens","nickname":null,"email":null,"tags":["a",null],"id":"1"}}}`, out)
+	})
+	t.Run("strip nulls", func(t *testing.T) {
+		out := resolve(t, ResponseFormattingOptions{StripNulls: true})
+		assert.Equal(t, `{"data":{"user":{"name":"Jens","tags":["a",null],"id":"1"}}}`, out)
+	})
+	t.Run("indent", func(t *testing.T) {
+		out := resolve(t, ResponseFormattingOptions{Indent: "  ", StripNulls: true})
+		expected := `{
+  "data": {
+    "user": {
+      "name": "Jens",
+      "tags": [
+        "a",
+        null
+      ],
+      "id": "1"
+    }
+  }
+}`
+		assert.Equal(t, expected, out)
+	})
+}
+
+func TestResolvable_ResponseExtensionsHook(t *testing.T) {
+	object := &Object{
+		Fields: []*Field{
+			{
+				Name:  []byte("name"),
+				Value: &String{Path: []string{"name"}},
+			},
+		},
+	}
+
+	resolve := func(t *testing.T, data string, hook ResponseExtensionsHookFunc) (string, error) {
+		res := NewResolvable()
+		res.extensionsHook = hook
+		ctx := &Context{
+			Request: Request{ID: "123"},
+		}
+		err := res.Init(ctx, []byte(data), ast.OperationTypeQuery)
+		assert.NoError(t, err)
+		out := &bytes.Buffer{}
+		err = res.Resolve(context.Background(), object, out)
+		return out.String(), err
+	}
+
+	hook := func(ctx *Context) ([]byte, error) {
+		return []byte(`{"cacheStatus":"MISS","requestID":"` + ctx.Request.ID + `"}`), nil
+	}
+
+	t.Run("data", func(t *testing.T) {
+		out, err := resolve(t, `{"name":"Jens"}`, hook)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"data":{"name":"Jens"},"extensions":{"cacheStatus":"MISS","requestID":"123"}}`, out)
+	})
+	t.Run("errors without data", func(t *testing.T) {
+		out, err := resolve(t, `{}`, hook)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"errors":[{"message":"Cannot return null for non-nullable field 'Query.name'.","path":["name"]}],"data":null,"extensions":{"cacheStatus":"MISS","requestID":"123"}}`, out)
+	})
+	t.Run("empty extensions", func(t *testing.T) {
+		out, err := resolve(t, `{"name":"Jens"}`, func(ctx *Context) ([]byte, error) {
+			return []byte(`{}`), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"data":{"name":"Jens"}}`, out)
+	})
+	t.Run("invalid extensions", func(t *testing.T) {
+		_, err := resolve(t, `{"name":"Jens"}`, func(ctx *Context) ([]byte, error) {
+			return []byte(`["a"]`), nil
+		})
+		assert.ErrorIs(t, err, errInvalidResponseExtensions)
+	})
+}
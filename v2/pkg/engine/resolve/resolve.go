@@ -13,7 +13,6 @@ import (
 	"github.com/alitto/pond"
 	"github.com/buger/jsonparser"
 	"github.com/pkg/errors"
-	"go.uber.org/atomic"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/xcontext"
@@ -47,10 +46,14 @@ type Resolver struct {
 	events            chan subscriptionEvent
 	triggerUpdatePool *pond.WorkerPool
 
-	connectionIDs atomic.Int64
+	ids IDGenerator
+	// goroutines tracks the goroutines of operations and subscriptions, if set
+	goroutines *GoroutineTracker
 
 	reporter         Reporter
 	asyncErrorWriter AsyncErrorWriter
+	// subscriptionCounter is the reporter of the resolver, it forwards the reports to the Reporter of the options
+	subscriptionCounter *subscriptionCounter
 
 	propagateSubgraphErrors      bool
 	propagateSubgraphStatusCodes bool
@@ -83,31 +86,92 @@ type ResolverOptions struct {
 
 	PropagateSubgraphErrors      bool
 	PropagateSubgraphStatusCodes bool
+
+	// ResponseFormatting configures indentation and null handling of responses
+	ResponseFormatting ResponseFormattingOptions
+	// ResponseExtensionsHook injects computed extensions into every response and subscription message
+	ResponseExtensionsHook ResponseExtensionsHook
+
+	// SubscriptionUpdateTimeout is the deadline for resolving a single event of a subscription, including the fetches triggered by the event.
+	// Fetches exceeding the deadline fail with an error in the message of the event and the subscription continues with the next event.
+	// If set to 0, events are resolved within the lifetime of the subscription
+	SubscriptionUpdateTimeout time.Duration
+
+	// EnableArena allocates the results of the fetches of a request from an arena which is freed at once at the end of the request
+	// This reduces the allocations and the pressure on the garbage collector for operations with many fetches.
+	// Plans are not allocated from the arena because they are cached across requests
+	EnableArena bool
+
+	// DataSourceMetrics records the sizes and the durations of the fetches per datasource
+	DataSourceMetrics DataSourceMetrics
+
+	// Metrics records the durations of the fetches per datasource and the active subscriptions to a metrics backend
+	Metrics *Metrics
+
+	// FetchTracer traces the fetches, e.g. with a span per fetch, and propagates the trace to the datasources
+	FetchTracer FetchTracer
+
+	// IDGenerator generates the IDs of the fetches in traces and the connection IDs of subscriptions started without an identifier,
+	// e.g. a SeededIDGenerator makes the IDs deterministic for golden file tests of traces.
+	// If not set, the fetch IDs are random UUIDs and the connection IDs are incremented
+	IDGenerator IDGenerator
+
+	// GoroutineTracker tracks the goroutines started by the resolver per operation and subscription trigger,
+	// to find the goroutines which keep running after the operation or subscription is canceled.
+	// The contexts of the triggers carry the tracker, so datasources are able to track their goroutines, see TrackGoroutine
+	GoroutineTracker *GoroutineTracker
 }
 
 // New returns a new Resolver, ctx.Done() is used to cancel all active subscriptions & streams
 func New(ctx context.Context, options ResolverOptions) *Resolver {
 	//options.Debug = true
+	if options.Metrics != nil {
+		recorder := &metricsRecorder{
+			metrics:           *options.Metrics,
+			dataSourceMetrics: options.DataSourceMetrics,
+			reporter:          options.Reporter,
+		}
+		options.DataSourceMetrics = recorder
+		options.Reporter = recorder
+	}
+	counter := &subscriptionCounter{next: options.Reporter}
+	if options.IDGenerator == nil {
+		options.IDGenerator = &randomIDGenerator{}
+	}
 	resolver := &Resolver{
 		ctx:                          ctx,
 		options:                      options,
+		ids:                          options.IDGenerator,
+		goroutines:                   options.GoroutineTracker,
 		propagateSubgraphErrors:      options.PropagateSubgraphErrors,
 		propagateSubgraphStatusCodes: options.PropagateSubgraphStatusCodes,
 		toolPool: sync.Pool{
 			New: func() interface{} {
+				resolvable := NewResolvable()
+				resolvable.formatting = options.ResponseFormatting
+				resolvable.extensionsHook = options.ResponseExtensionsHook
+				resolvable.ids = options.IDGenerator
+				loader := &Loader{
+					propagateSubgraphErrors:      options.PropagateSubgraphErrors,
+					propagateSubgraphStatusCodes: options.PropagateSubgraphStatusCodes,
+					dataSourceMetrics:            options.DataSourceMetrics,
+					fetchTracer:                  options.FetchTracer,
+					goroutines:                   options.GoroutineTracker,
+				}
+				if options.EnableArena {
+					loader.enableArena()
+				}
 				return &tools{
-					resolvable: NewResolvable(),
-					loader: &Loader{
-						propagateSubgraphErrors:      options.PropagateSubgraphErrors,
-						propagateSubgraphStatusCodes: options.PropagateSubgraphStatusCodes,
-					},
+					resolvable: resolvable,
+					loader:     loader,
 				}
 			},
 		},
-		events:           make(chan subscriptionEvent),
-		triggers:         make(map[uint64]*trigger),
-		reporter:         options.Reporter,
-		asyncErrorWriter: options.AsyncErrorWriter,
+		events:              make(chan subscriptionEvent),
+		triggers:            make(map[uint64]*trigger),
+		reporter:            counter,
+		subscriptionCounter: counter,
+		asyncErrorWriter:    options.AsyncErrorWriter,
 	}
 	if options.MaxConcurrency > 0 {
 		semaphore := make(chan struct{}, options.MaxConcurrency)
@@ -128,7 +192,11 @@ func New(ctx context.Context, options ResolverOptions) *Resolver {
 		pond.Strategy(pond.Lazy()),
 		pond.MinWorkers(16),
 	)
-	go resolver.handleEvents()
+	done := resolver.goroutines.Track(ctx, "resolver event loop")
+	go func() {
+		defer done()
+		resolver.handleEvents()
+	}()
 	return resolver
 }
 
@@ -156,6 +224,11 @@ func (r *Resolver) ResolveGraphQLResponse(ctx *Context, response *GraphQLRespons
 		}
 	}
 
+	err = ctx.decodeVariables(response.DecodeVariables)
+	if err != nil {
+		return err
+	}
+
 	t := r.getTools()
 	defer r.putTools(t)
 
@@ -169,7 +242,13 @@ func (r *Resolver) ResolveGraphQLResponse(ctx *Context, response *GraphQLRespons
 		return err
 	}
 
-	return t.resolvable.Resolve(ctx.ctx, response.Data, writer)
+	err = t.resolvable.Resolve(ctx.ctx, response.Data, writer)
+	if err != nil {
+		return err
+	}
+
+	r.contributeCacheControl(ctx, response, t.resolvable)
+	return nil
 }
 
 type trigger struct {
@@ -197,19 +276,44 @@ type sub struct {
 	writer         SubscriptionResponseWriter
 	id             SubscriptionIdentifier
 	pendingUpdates int
+	// initialized is closed once the initial fetch of the subscription is done, nil without initial fetch
+	initialized chan struct{}
+	// coalescer collects the events within the coalescing window, nil without coalescing
+	coalescer *subscriptionCoalescer
+	// throttler limits the delivery frequency of a throttled subscription field, nil without throttle
+	throttler *subscriptionThrottler
+}
+
+func (r *Resolver) subscriptionUpdateTimeout(ctx *Context) time.Duration {
+	if ctx.subscriptionUpdateTimeout > 0 {
+		return ctx.subscriptionUpdateTimeout
+	}
+	return r.options.SubscriptionUpdateTimeout
 }
 
 func (r *Resolver) executeSubscriptionUpdate(ctx *Context, sub *sub, sharedInput []byte) {
+	if sub.initialized != nil {
+		<-sub.initialized
+	}
+	input := make([]byte, len(sharedInput))
+	copy(input, sharedInput)
+	r.resolveSubscriptionUpdate(ctx, sub, input)
+}
+
+func (r *Resolver) resolveSubscriptionUpdate(ctx *Context, sub *sub, input []byte) {
 	sub.mux.Lock()
 	sub.pendingUpdates++
 	sub.mux.Unlock()
 	if r.options.Debug {
 		fmt.Printf("resolver:trigger:subscription:update:%d\n", sub.id.SubscriptionID)
 	}
+	if timeout := r.subscriptionUpdateTimeout(ctx); timeout > 0 {
+		updateCtx, cancel := context.WithTimeout(ctx.ctx, timeout)
+		defer cancel()
+		ctx = ctx.WithContext(updateCtx)
+	}
 	t := r.getTools()
 	defer r.putTools(t)
-	input := make([]byte, len(sharedInput))
-	copy(input, sharedInput)
 	if err := t.resolvable.InitSubscription(ctx, input, sub.resolve.Trigger.PostProcessing); err != nil {
 		buf := pool.BytesBuffer.Get()
 		defer pool.BytesBuffer.Put(buf)
@@ -307,11 +411,21 @@ func (r *Resolver) handleTriggerDone(triggerID uint64) {
 	delete(r.triggers, triggerID)
 	wg := trig.inFlight
 	subscriptionCount := len(trig.subscriptions)
+	done := r.goroutines.Track(r.ctx, "subscription trigger completion")
 	go func() {
+		defer done()
 		if wg != nil {
 			wg.Wait()
 		}
-		for _, s := range trig.subscriptions {
+		for c, s := range trig.subscriptions {
+			if s.throttler != nil {
+				// deliver the conflated event before completing
+				r.flushThrottledSubscriptionUpdate(c, s)
+			}
+			if s.coalescer != nil {
+				// deliver the events of the last window before completing
+				r.flushCoalescedSubscriptionUpdates(c, s)
+			}
 			s.writer.Complete()
 		}
 		if r.reporter != nil {
@@ -333,9 +447,20 @@ func (r *Resolver) handleAddSubscription(triggerID uint64, add *addSubscription)
 		writer:  add.writer,
 		id:      add.id,
 	}
+	if add.ctx.subscriptionCoalescing.Window > 0 {
+		s.coalescer = &subscriptionCoalescer{
+			config: add.ctx.subscriptionCoalescing,
+		}
+	}
+	if add.resolve.Throttle != nil && add.resolve.Throttle.Interval > 0 {
+		s.throttler = &subscriptionThrottler{
+			config: *add.resolve.Throttle,
+		}
+	}
 	trig, ok := r.triggers[triggerID]
 	if ok {
 		trig.subscriptions[add.ctx] = s
+		r.startSubscriptionInitialFetch(add.ctx, s, add.initialFetchInput)
 		if r.reporter != nil {
 			r.reporter.SubscriptionCountInc(1)
 		}
@@ -348,6 +473,7 @@ func (r *Resolver) handleAddSubscription(triggerID uint64, add *addSubscription)
 		fmt.Printf("resolver:create:trigger:%d\n", triggerID)
 	}
 	ctx, cancel := context.WithCancel(xcontext.Detach(add.ctx.Context()))
+	ctx = withGoroutineTracker(ctx, r.goroutines)
 	updater := &subscriptionUpdater{
 		debug:     r.options.Debug,
 		triggerID: triggerID,
@@ -377,6 +503,7 @@ func (r *Resolver) handleAddSubscription(triggerID uint64, add *addSubscription)
 	if r.options.Debug {
 		fmt.Printf("resolver:trigger:started:%d\n", triggerID)
 	}
+	r.startSubscriptionInitialFetch(add.ctx, s, add.initialFetchInput)
 	if r.reporter != nil {
 		r.reporter.SubscriptionCountInc(1)
 		r.reporter.TriggerCountInc(1)
@@ -448,12 +575,27 @@ func (r *Resolver) handleTriggerUpdate(id uint64, data []byte) {
 	if r.options.Debug {
 		fmt.Printf("resolver:trigger:update:%d\n", id)
 	}
-	wg := &sync.WaitGroup{}
+	// the wait group tracks all updates of the trigger,
+	// so the subscriptions are completed after every update was delivered
+	if trig.inFlight == nil {
+		trig.inFlight = &sync.WaitGroup{}
+	}
+	wg := trig.inFlight
 	wg.Add(len(trig.subscriptions))
-	trig.inFlight = wg
 	for c, s := range trig.subscriptions {
 		c, s := c, s
+		if s.throttler != nil && !r.throttleSubscriptionUpdate(c, s, data) {
+			wg.Done()
+			continue
+		}
+		if s.coalescer != nil {
+			r.coalesceSubscriptionUpdate(c, s, data)
+			wg.Done()
+			continue
+		}
+		done := r.goroutines.Track(c.Context(), "subscription update")
 		r.triggerUpdatePool.Submit(func() {
+			defer done()
 			r.executeSubscriptionUpdate(c, s, data)
 			wg.Done()
 		})
@@ -549,8 +691,13 @@ func (r *Resolver) ResolveGraphQLSubscription(ctx *Context, subscription *GraphQ
 		return writeFlushComplete(writer, msg)
 	}
 	uniqueID := xxh.Sum64()
+	initialFetchInput, err := r.subscriptionInitialFetchInput(ctx, subscription)
+	if err != nil {
+		msg := []byte(`{"errors":[{"message":"invalid input"}]}`)
+		return writeFlushComplete(writer, msg)
+	}
 	id := SubscriptionIdentifier{
-		ConnectionID:   r.connectionIDs.Inc(),
+		ConnectionID:   r.ids.SubscriptionConnectionID(),
 		SubscriptionID: 0,
 		internal:       true,
 	}
@@ -564,11 +711,12 @@ func (r *Resolver) ResolveGraphQLSubscription(ctx *Context, subscription *GraphQ
 		triggerID: uniqueID,
 		kind:      subscriptionEventKindAddSubscription,
 		addSubscription: &addSubscription{
-			ctx:     ctx,
-			input:   input,
-			resolve: subscription,
-			writer:  writer,
-			id:      id,
+			ctx:               ctx,
+			input:             input,
+			resolve:           subscription,
+			writer:            writer,
+			id:                id,
+			initialFetchInput: initialFetchInput,
 		},
 	}:
 	}
@@ -609,6 +757,11 @@ func (r *Resolver) AsyncResolveGraphQLSubscription(ctx *Context, subscription *G
 		return writeFlushComplete(writer, msg)
 	}
 	uniqueID := xxh.Sum64()
+	initialFetchInput, err := r.subscriptionInitialFetchInput(ctx, subscription)
+	if err != nil {
+		msg := []byte(`{"errors":[{"message":"invalid input"}]}`)
+		return writeFlushComplete(writer, msg)
+	}
 	select {
 	case <-r.ctx.Done():
 		return ErrResolverClosed
@@ -616,11 +769,12 @@ func (r *Resolver) AsyncResolveGraphQLSubscription(ctx *Context, subscription *G
 		triggerID: uniqueID,
 		kind:      subscriptionEventKindAddSubscription,
 		addSubscription: &addSubscription{
-			ctx:     ctx,
-			input:   input,
-			resolve: subscription,
-			writer:  writer,
-			id:      id,
+			ctx:               ctx,
+			input:             input,
+			resolve:           subscription,
+			writer:            writer,
+			id:                id,
+			initialFetchInput: initialFetchInput,
 		},
 	}:
 	}
@@ -702,12 +856,13 @@ type subscriptionEvent struct {
 }
 
 type addSubscription struct {
-	ctx     *Context
-	input   []byte
-	resolve *GraphQLSubscription
-	writer  SubscriptionResponseWriter
-	id      SubscriptionIdentifier
-	done    func()
+	ctx               *Context
+	input             []byte
+	initialFetchInput []byte
+	resolve           *GraphQLSubscription
+	writer            SubscriptionResponseWriter
+	id                SubscriptionIdentifier
+	done              func()
 }
 
 type subscriptionEventKind int
@@ -4256,6 +4256,240 @@ func TestResolver_ResolveGraphQLSubscription(t *testing.T) {
 		}, recorder.Messages())
 	})
 
+	t.Run("should count active subscriptions and triggers", func(t *testing.T) {
+		c, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		fakeStream := createFakeStream(func(counter int) (message string, done bool) {
+			return fmt.Sprintf(`{"data":{"counter":%d}}`, counter), false
+		}, time.Millisecond*10, nil)
+
+		resolver, plan, recorder, id := setup(c, fakeStream)
+		assert.Equal(t, SubscriptionStats{}, resolver.SubscriptionStats())
+
+		err := resolver.AsyncResolveGraphQLSubscription(&Context{}, plan, recorder, id)
+		assert.NoError(t, err)
+		recorder.AwaitMessages(t, 1, defaultTimeout)
+		assert.Equal(t, SubscriptionStats{Subscriptions: 1, Triggers: 1}, resolver.SubscriptionStats())
+
+		err = resolver.AsyncUnsubscribeSubscription(id)
+		assert.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			return resolver.SubscriptionStats() == SubscriptionStats{}
+		}, defaultTimeout, time.Millisecond*10)
+	})
+
+	t.Run("should resolve every update within the subscription update timeout", func(t *testing.T) {
+		c, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		fakeStream := createFakeStream(func(counter int) (message string, done bool) {
+			return fmt.Sprintf(`{"data":{"counter":%d}}`, counter), counter == 1
+		}, time.Millisecond*100, nil)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		loads := atomic.Int32{}
+		entities := NewMockDataSource(ctrl)
+		entities.EXPECT().
+			Load(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&bytes.Buffer{})).
+			DoAndReturn(func(ctx context.Context, input []byte, w io.Writer) error {
+				if loads.Add(1) == 1 {
+					// the first fetch hangs until the deadline of the update
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				_, err := w.Write([]byte(`{"name":"fast"}`))
+				return err
+			}).
+			Times(2)
+
+		resolver, plan, recorder, id := setup(c, fakeStream)
+		plan.Response.Data.Fetch = &SingleFetch{
+			FetchConfiguration: FetchConfiguration{DataSource: entities},
+		}
+		plan.Response.Data.Fields = append(plan.Response.Data.Fields, &Field{
+			Name: []byte("name"),
+			Value: &String{
+				Path:     []string{"name"},
+				Nullable: true,
+			},
+		})
+
+		ctx := NewContext(c)
+		ctx.SetSubscriptionUpdateTimeout(time.Millisecond * 20)
+
+		err := resolver.AsyncResolveGraphQLSubscription(ctx, plan, recorder, id)
+		assert.NoError(t, err)
+		recorder.AwaitComplete(t, defaultTimeout)
+		messages := recorder.Messages()
+		require.Equal(t, 2, len(messages))
+		assert.Contains(t, messages[0], `"errors":[{"message":"Failed to fetch`)
+		assert.Contains(t, messages[0], `"data":{"counter":0,"name":null}`)
+		assert.Equal(t, `{"data":{"counter":1,"name":"fast"}}`, messages[1])
+	})
+
+	t.Run("should drop the events within the throttle interval", func(t *testing.T) {
+		c, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		fakeStream := createFakeStream(func(counter int) (message string, done bool) {
+			return fmt.Sprintf(`{"data":{"counter":%d}}`, counter), counter == 4
+		}, 0, nil)
+
+		resolver, plan, recorder, id := setup(c, fakeStream)
+		plan.Throttle = &SubscriptionThrottle{
+			Interval: time.Second,
+			Policy:   SubscriptionThrottlePolicyDrop,
+		}
+
+		err := resolver.AsyncResolveGraphQLSubscription(NewContext(c), plan, recorder, id)
+		assert.NoError(t, err)
+		recorder.AwaitComplete(t, defaultTimeout)
+		assert.Equal(t, []string{`{"data":{"counter":0}}`}, recorder.Messages())
+	})
+
+	t.Run("should conflate the events within the throttle interval", func(t *testing.T) {
+		c, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		fakeStream := createFakeStream(func(counter int) (message string, done bool) {
+			return fmt.Sprintf(`{"data":{"counter":%d}}`, counter), counter == 4
+		}, 0, nil)
+
+		resolver, plan, recorder, id := setup(c, fakeStream)
+		plan.Throttle = &SubscriptionThrottle{
+			Interval: time.Second,
+			Policy:   SubscriptionThrottlePolicyConflate,
+		}
+
+		err := resolver.AsyncResolveGraphQLSubscription(NewContext(c), plan, recorder, id)
+		assert.NoError(t, err)
+		recorder.AwaitComplete(t, defaultTimeout)
+		assert.Equal(t, []string{`{"data":{"counter":0}}`, `{"data":{"counter":4}}`}, recorder.Messages())
+	})
+
+	t.Run("should deliver the latest event of a burst with coalescing", func(t *testing.T) {
+		c, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		fakeStream := createFakeStream(func(counter int) (message string, done bool) {
+			return fmt.Sprintf(`{"data":{"counter":%d}}`, counter), counter == 2
+		}, 0, nil)
+
+		resolver, plan, recorder, id := setup(c, fakeStream)
+
+		ctx := NewContext(c)
+		ctx.SetSubscriptionCoalescing(SubscriptionCoalescing{
+			Window: time.Second,
+			Mode:   SubscriptionCoalescingModeLatest,
+		})
+
+		err := resolver.AsyncResolveGraphQLSubscription(ctx, plan, recorder, id)
+		assert.NoError(t, err)
+		recorder.AwaitComplete(t, defaultTimeout)
+		assert.Equal(t, []string{`{"data":{"counter":2}}`}, recorder.Messages())
+	})
+
+	t.Run("should deliver all events of a burst as array with coalescing", func(t *testing.T) {
+		c, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		fakeStream := createFakeStream(func(counter int) (message string, done bool) {
+			return fmt.Sprintf(`{"data":{"counter":%d}}`, counter), counter == 2
+		}, 0, nil)
+
+		resolver, plan, recorder, id := setup(c, fakeStream)
+
+		ctx := NewContext(c)
+		ctx.SetSubscriptionCoalescing(SubscriptionCoalescing{
+			Window: time.Second,
+			Mode:   SubscriptionCoalescingModeArray,
+		})
+
+		err := resolver.AsyncResolveGraphQLSubscription(ctx, plan, recorder, id)
+		assert.NoError(t, err)
+		recorder.AwaitComplete(t, defaultTimeout)
+		assert.Equal(t, []string{`[{"data":{"counter":0}},{"data":{"counter":1}},{"data":{"counter":2}}]`}, recorder.Messages())
+	})
+
+	t.Run("should send the initial state before the updates", func(t *testing.T) {
+		c, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		fakeStream := createFakeStream(func(counter int) (message string, done bool) {
+			return fmt.Sprintf(`{"data":{"counter":%d}}`, counter), counter == 1
+		}, 0, func(input []byte) {
+			assert.Equal(t, `{"method":"POST","url":"http://localhost:4000","body":{"query":"subscription { counter }"}}`, string(input))
+		})
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		initialState := NewMockDataSource(ctrl)
+		initialState.EXPECT().
+			Load(gomock.Any(), []byte(`{"method":"POST","url":"http://localhost:4000","body":{"query":"query { counter }"}}`), gomock.AssignableToTypeOf(&bytes.Buffer{})).
+			DoAndReturn(func(ctx context.Context, input []byte, w io.Writer) error {
+				_, err := w.Write([]byte(`{"data":{"counter":100}}`))
+				return err
+			})
+
+		resolver, plan, recorder, id := setup(c, fakeStream)
+		plan.Trigger.InitialFetch = &SubscriptionInitialFetch{
+			InputTemplate: InputTemplate{
+				Segments: []TemplateSegment{
+					{
+						SegmentType: StaticSegmentType,
+						Data:        []byte(`{"method":"POST","url":"http://localhost:4000","body":{"query":"query { counter }"}}`),
+					},
+				},
+			},
+			DataSource: initialState,
+		}
+
+		ctx := &Context{}
+
+		err := resolver.AsyncResolveGraphQLSubscription(ctx, plan, recorder, id)
+		assert.NoError(t, err)
+		recorder.AwaitComplete(t, defaultTimeout)
+		messages := recorder.Messages()
+		assert.Equal(t, 3, len(messages))
+		assert.Equal(t, `{"data":{"counter":100}}`, messages[0])
+		assert.ElementsMatch(t, []string{
+			`{"data":{"counter":0}}`,
+			`{"data":{"counter":1}}`,
+		}, messages[1:])
+	})
+
+	t.Run("should inject extensions of the hook into every message", func(t *testing.T) {
+		c, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		fakeStream := createFakeStream(func(counter int) (message string, done bool) {
+			return fmt.Sprintf(`{"data":{"counter":%d}}`, counter), counter == 1
+		}, 0, func(input []byte) {
+			assert.Equal(t, `{"method":"POST","url":"http://localhost:4000","body":{"query":"subscription { counter }"}}`, string(input))
+		})
+
+		_, plan, recorder, id := setup(c, fakeStream)
+		resolver := New(c, ResolverOptions{
+			MaxConcurrency: 1024,
+			ResponseExtensionsHook: ResponseExtensionsHookFunc(func(ctx *Context) ([]byte, error) {
+				return []byte(`{"servedBy":"eu-west-1"}`), nil
+			}),
+		})
+
+		ctx := &Context{}
+
+		err := resolver.AsyncResolveGraphQLSubscription(ctx, plan, recorder, id)
+		assert.NoError(t, err)
+		recorder.AwaitComplete(t, defaultTimeout)
+		assert.Equal(t, 2, len(recorder.Messages()))
+		assert.ElementsMatch(t, []string{
+			`{"data":{"counter":0},"extensions":{"servedBy":"eu-west-1"}}`,
+			`{"data":{"counter":1},"extensions":{"servedBy":"eu-west-1"}}`,
+		}, recorder.Messages())
+	})
+
 	t.Run("should propagate extensions to stream", func(t *testing.T) {
 		c, cancel := context.WithCancel(context.Background())
 		defer cancel()
@@ -4895,26 +5129,43 @@ func Test_NestedBatching_WithStats(t *testing.T) {
 	assert.Equal(t, int32(4), ctx.Stats.NumberOfFetches.Load(), "number of fetches")
 }
 
-func Benchmark_NestedBatching(b *testing.B) {
+func TestResolver_EnableArena(t *testing.T) {
 	rCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	resolver := newResolver(rCtx)
+	resolver := New(rCtx, ResolverOptions{
+		MaxConcurrency:               1,
+		PropagateSubgraphErrors:      true,
+		PropagateSubgraphStatusCodes: true,
+		EnableArena:                  true,
+	})
+	plan, expected := nestedBatchingPlan(t)
 
-	productsService := fakeDataSourceWithInputCheck(b,
+	// the single tools instance reuses the chunks of the arena for every request
+	for i := 0; i < 3; i++ {
+		buf := &bytes.Buffer{}
+		err := resolver.ResolveGraphQLResponse(NewContext(context.Background()), plan, nil, buf)
+		require.NoError(t, err)
+		assert.Equal(t, string(expected), buf.String())
+	}
+}
+
+// nestedBatchingPlan returns a federated plan with nested batched entity fetches and its expected response
+func nestedBatchingPlan(tb TestingTB) (plan *GraphQLResponse, expected []byte) {
+	productsService := fakeDataSourceWithInputCheck(tb,
 		[]byte(`{"method":"POST","url":"http://products","body":{"query":"query{topProducts{name __typename upc}}"}}`),
 		[]byte(`{"data":{"topProducts":[{"name":"Table","__typename":"Product","upc":"1"},{"name":"Couch","__typename":"Product","upc":"2"},{"name":"Chair","__typename":"Product","upc":"3"}]}}`))
-	stockService := fakeDataSourceWithInputCheck(b,
+	stockService := fakeDataSourceWithInputCheck(tb,
 		[]byte(`{"method":"POST","url":"http://stock","body":{"query":"query($representations: [_Any!]!){_entities(representations: $representations){__typename ... on Product {stock}}}","variables":{"representations":[{"__typename":"Product","upc":"1"},{"__typename":"Product","upc":"2"},{"__typename":"Product","upc":"3"}]}}}`),
 		[]byte(`{"data":{"_entities":[{"stock":8},{"stock":2},{"stock":5}]}}`))
-	reviewsService := fakeDataSourceWithInputCheck(b,
+	reviewsService := fakeDataSourceWithInputCheck(tb,
 		[]byte(`{"method":"POST","url":"http://reviews","body":{"query":"query($representations: [_Any!]!){_entities(representations: $representations){__typename ... on Product {reviews {body author {__typename id}}}}}","variables":{"representations":[{"__typename":"Product","upc":"1"},{"__typename":"Product","upc":"2"},{"__typename":"Product","upc":"3"}]}}}`),
 		[]byte(`{"data":{"_entities":[{"__typename":"Product","reviews":[{"body":"Love Table!","author":{"__typename":"User","id":"1"}},{"body":"Prefer other Table.","author":{"__typename":"User","id":"2"}}]},{"__typename":"Product","reviews":[{"body":"Couch Too expensive.","author":{"__typename":"User","id":"1"}}]},{"__typename":"Product","reviews":[{"body":"Chair Could be better.","author":{"__typename":"User","id":"2"}}]}]}}`))
-	usersService := fakeDataSourceWithInputCheck(b,
+	usersService := fakeDataSourceWithInputCheck(tb,
 		[]byte(`{"method":"POST","url":"http://users","body":{"query":"query($representations: [_Any!]!){_entities(representations: $representations){__typename ... on User {name}}}","variables":{"representations":[{"__typename":"User","id":"1"},{"__typename":"User","id":"2"}]}}}`),
 		[]byte(`{"data":{"_entities":[{"name":"user-1"},{"name":"user-2"}]}}`))
 
-	plan := &GraphQLResponse{
+	plan = &GraphQLResponse{
 		Data: &Object{
 			Fetch: &SingleFetch{
 				InputTemplate: InputTemplate{
@@ -5167,7 +5418,30 @@ func Benchmark_NestedBatching(b *testing.B) {
 		},
 	}
 
-	expected := []byte(`{"data":{"topProducts":[{"name":"Table","stock":8,"reviews":[{"body":"Love Table!","author":{"name":"user-1"}},{"body":"Prefer other Table.","author":{"name":"user-2"}}]},{"name":"Couch","stock":2,"reviews":[{"body":"Couch Too expensive.","author":{"name":"user-1"}}]},{"name":"Chair","stock":5,"reviews":[{"body":"Chair Could be better.","author":{"name":"user-2"}}]}]}}`)
+	expected = []byte(`{"data":{"topProducts":[{"name":"Table","stock":8,"reviews":[{"body":"Love Table!","author":{"name":"user-1"}},{"body":"Prefer other Table.","author":{"name":"user-2"}}]},{"name":"Couch","stock":2,"reviews":[{"body":"Couch Too expensive.","author":{"name":"user-1"}}]},{"name":"Chair","stock":5,"reviews":[{"body":"Chair Could be better.","author":{"name":"user-2"}}]}]}}`)
+	return plan, expected
+}
+
+func Benchmark_NestedBatching(b *testing.B) {
+	b.Run("heap", func(b *testing.B) {
+		benchmarkNestedBatching(b, false)
+	})
+	b.Run("arena", func(b *testing.B) {
+		benchmarkNestedBatching(b, true)
+	})
+}
+
+func benchmarkNestedBatching(b *testing.B, enableArena bool) {
+	rCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	resolver := New(rCtx, ResolverOptions{
+		MaxConcurrency:               1024,
+		PropagateSubgraphErrors:      true,
+		PropagateSubgraphStatusCodes: true,
+		EnableArena:                  enableArena,
+	})
+	plan, expected := nestedBatchingPlan(b)
 
 	pool := sync.Pool{
 		New: func() interface{} {
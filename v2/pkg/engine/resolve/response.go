@@ -11,6 +11,8 @@ import (
 type GraphQLSubscription struct {
 	Trigger  GraphQLSubscriptionTrigger
 	Response *GraphQLResponse
+	// Throttle is optional and limits the delivery frequency of the subscription field for every subscriber
+	Throttle *SubscriptionThrottle
 }
 
 type GraphQLSubscriptionTrigger struct {
@@ -19,12 +21,23 @@ type GraphQLSubscriptionTrigger struct {
 	Variables      Variables
 	Source         SubscriptionDataSource
 	PostProcessing PostProcessingConfiguration
+	// InitialFetch is optional and loads the current state before the updates of the Source are sent
+	InitialFetch *SubscriptionInitialFetch
 }
 
 type GraphQLResponse struct {
 	Data            *Object
 	RenameTypeNames []RenameTypeName
 	Info            *GraphQLResponseInfo
+	// PIIVariables are the variables of the operation passed to arguments tagged as PII
+	// Their values are redacted from the inputs of fetches in traces
+	PIIVariables []string
+	// DecodeVariables are the variables of the operation passed to arguments with decoded values
+	// They are decoded with the FieldValueTransformer of the Context before the data is loaded
+	DecodeVariables []DecodeVariable
+	// CacheControl is the cache policy of the response, it's nil if the schema doesn't define the @cacheControl directive
+	// The policy is contributed as Cache-Control header to the ResponseHeaders of the Context
+	CacheControl *CacheControl
 }
 
 type GraphQLResponseInfo struct {
@@ -0,0 +1,51 @@
+package resolve
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// ResponseExtensionsHook is called right before a response is rendered to inject computed extensions,
+// e.g. the cache status, the region which served the request or a trace ID
+// It's called for every query and mutation response and for every message of a subscription
+type ResponseExtensionsHook interface {
+	// ResponseExtensions returns a json object whose fields are added to the extensions of the response
+	// The keys must not collide with the extensions rendered by the engine, e.g. "trace" or "authorization"
+	// Returning nil or an empty object adds no extensions
+	ResponseExtensions(ctx *Context) ([]byte, error)
+}
+
+// ResponseExtensionsHookFunc allows to use a function as a ResponseExtensionsHook
+type ResponseExtensionsHookFunc func(ctx *Context) ([]byte, error)
+
+func (f ResponseExtensionsHookFunc) ResponseExtensions(ctx *Context) ([]byte, error) {
+	return f(ctx)
+}
+
+var errInvalidResponseExtensions = errors.New("response extensions hook must return a json object")
+
+// loadHookExtensions calls the ResponseExtensionsHook and keeps the fields of the returned object for printing
+func (r *Resolvable) loadHookExtensions() error {
+	r.hookExtensions = nil
+	if r.extensionsHook == nil {
+		return nil
+	}
+	extensions, err := r.extensionsHook.ResponseExtensions(r.ctx)
+	if err != nil {
+		return err
+	}
+	extensions = bytes.TrimSpace(extensions)
+	if len(extensions) == 0 {
+		return nil
+	}
+	if extensions[0] != '{' || !json.Valid(extensions) {
+		return errInvalidResponseExtensions
+	}
+	r.hookExtensions = bytes.TrimSpace(extensions[1 : len(extensions)-1])
+	return nil
+}
+
+func (r *Resolvable) hasHookExtensions() bool {
+	return len(r.hookExtensions) != 0
+}
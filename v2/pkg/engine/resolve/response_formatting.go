@@ -0,0 +1,47 @@
+package resolve
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astjson"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/pool"
+)
+
+// ResponseFormattingOptions configure how the Resolver renders responses
+// Fields are always rendered in the order of the selection set of the operation,
+// independent of the order in which the upstream returned them, as required by the GraphQL spec
+type ResponseFormattingOptions struct {
+	// Indent renders the response as indented json using the given indentation, e.g. for debugging
+	// if empty, the response is rendered without any whitespace
+	Indent string
+	// StripNulls omits object fields with a null value from the data of the response to save bandwidth
+	// null values in lists are kept, as are null values outside of data, e.g. "data":null
+	// Clients have to treat missing fields as null when this option is enabled
+	StripNulls bool
+}
+
+// fieldValueIsNull returns true if the field value resolves to null after the first walk of the Resolvable
+func (r *Resolvable) fieldValueIsNull(ref int, value Node) bool {
+	if _, ok := value.(*Null); ok {
+		return true
+	}
+	ref = r.storage.Get(ref, value.NodePath())
+	if !r.storage.NodeIsDefined(ref) {
+		return true
+	}
+	return r.storage.Nodes[ref].Kind == astjson.NodeKindNull
+}
+
+// writeIndented writes the compact json response with the configured indentation
+// if the response is not valid json, it's written as is
+func (r *Resolvable) writeIndented(response []byte, out io.Writer) error {
+	buf := pool.BytesBuffer.Get()
+	defer pool.BytesBuffer.Put(buf)
+	if err := json.Indent(buf, response, "", r.formatting.Indent); err != nil {
+		buf.Reset()
+		buf.Write(response)
+	}
+	_, err := out.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,178 @@
+package resolve
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/httpclient"
+)
+
+// HeaderConflictPolicy decides how the values of a response header contributed multiple times are merged
+type HeaderConflictPolicy int
+
+const (
+	// HeaderConflictPolicyLastWins replaces the values of the header with the latest contribution
+	HeaderConflictPolicyLastWins HeaderConflictPolicy = iota
+	// HeaderConflictPolicyFirstWins keeps the values of the first contribution
+	HeaderConflictPolicyFirstWins
+	// HeaderConflictPolicyAppend keeps the values of all contributions, e.g. for Set-Cookie
+	HeaderConflictPolicyAppend
+)
+
+// ResponseHeaderOptions controls which response headers datasources and hooks can contribute
+type ResponseHeaderOptions struct {
+	// AllowedHeaders restricts the headers which can be contributed, all headers are allowed if empty
+	AllowedHeaders []string
+	// DefaultConflictPolicy is used for headers without a policy in ConflictPolicies
+	DefaultConflictPolicy HeaderConflictPolicy
+	// ConflictPolicies sets the conflict policy per header name
+	ConflictPolicies map[string]HeaderConflictPolicy
+	// UpstreamHeaders are the headers of the responses of upstream requests which are contributed, e.g. Set-Cookie,
+	// they are collected from the datasources sending requests with the httpclient package, e.g. the graphql and the rest datasource
+	UpstreamHeaders []string
+}
+
+// ResponseHeaders collects the response headers contributed by datasources and hooks during the execution of an operation
+// Fetches are executed concurrently, so ResponseHeaders is safe for concurrent use
+// All methods can be called on a nil ResponseHeaders, contributions are discarded in this case
+type ResponseHeaders struct {
+	allowed               map[string]struct{}
+	upstream              []string
+	policies              map[string]HeaderConflictPolicy
+	defaultConflictPolicy HeaderConflictPolicy
+
+	mux    sync.Mutex
+	header http.Header
+}
+
+func NewResponseHeaders(options ResponseHeaderOptions) *ResponseHeaders {
+	h := &ResponseHeaders{
+		policies:              make(map[string]HeaderConflictPolicy, len(options.ConflictPolicies)),
+		defaultConflictPolicy: options.DefaultConflictPolicy,
+		header:                make(http.Header),
+	}
+	if len(options.AllowedHeaders) != 0 {
+		h.allowed = make(map[string]struct{}, len(options.AllowedHeaders))
+		for _, name := range options.AllowedHeaders {
+			h.allowed[http.CanonicalHeaderKey(name)] = struct{}{}
+		}
+	}
+	for _, name := range options.UpstreamHeaders {
+		h.upstream = append(h.upstream, http.CanonicalHeaderKey(name))
+	}
+	for name, policy := range options.ConflictPolicies {
+		h.policies[http.CanonicalHeaderKey(name)] = policy
+	}
+	return h
+}
+
+// Add contributes the values of a response header, the values are merged with earlier contributions
+// according to the conflict policy of the header
+// It returns false if the header is not allowed
+func (h *ResponseHeaders) Add(name string, values ...string) bool {
+	if h == nil {
+		return false
+	}
+	key := http.CanonicalHeaderKey(name)
+	if h.allowed != nil {
+		if _, ok := h.allowed[key]; !ok {
+			return false
+		}
+	}
+	if len(values) == 0 {
+		return true
+	}
+
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	existing, exists := h.header[key]
+	switch h.conflictPolicy(key) {
+	case HeaderConflictPolicyFirstWins:
+		if exists {
+			return true
+		}
+		h.header[key] = append([]string(nil), values...)
+	case HeaderConflictPolicyAppend:
+		h.header[key] = append(existing, values...)
+	default:
+		h.header[key] = append([]string(nil), values...)
+	}
+	return true
+}
+
+// CollectResponseHeaders contributes the UpstreamHeaders of the response of an upstream request
+func (h *ResponseHeaders) CollectResponseHeaders(header http.Header) {
+	if h == nil {
+		return
+	}
+	for _, name := range h.upstream {
+		if values := header.Values(name); len(values) != 0 {
+			h.Add(name, values...)
+		}
+	}
+}
+
+func (h *ResponseHeaders) conflictPolicy(key string) HeaderConflictPolicy {
+	if policy, ok := h.policies[key]; ok {
+		return policy
+	}
+	return h.defaultConflictPolicy
+}
+
+// Header returns a copy of the contributed headers
+func (h *ResponseHeaders) Header() http.Header {
+	if h == nil {
+		return http.Header{}
+	}
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	return h.header.Clone()
+}
+
+// Apply sets the contributed headers on the header of a response, e.g. of a http.ResponseWriter
+func (h *ResponseHeaders) Apply(header http.Header) {
+	if h == nil {
+		return
+	}
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	for key, values := range h.header {
+		header[key] = append([]string(nil), values...)
+	}
+}
+
+type responseHeadersKey struct{}
+
+// ResponseHeadersFromContext returns the ResponseHeaders of the operation within the Load of a datasource
+// It returns nil if the operation doesn't collect response headers, which is safe to use
+func ResponseHeadersFromContext(ctx context.Context) *ResponseHeaders {
+	headers, _ := ctx.Value(responseHeadersKey{}).(*ResponseHeaders)
+	return headers
+}
+
+func withResponseHeaders(ctx context.Context, headers *ResponseHeaders) context.Context {
+	if headers == nil {
+		return ctx
+	}
+	ctx = context.WithValue(ctx, responseHeadersKey{}, headers)
+	if len(headers.upstream) != 0 {
+		ctx = httpclient.WithResponseHeaderCollector(ctx, headers)
+	}
+	return ctx
+}
+
+// SetResponseHeaders enables datasources and hooks to contribute response headers to the operation
+func (c *Context) SetResponseHeaders(headers *ResponseHeaders) {
+	c.responseHeaders = headers
+}
+
+// ResponseHeaders returns the response headers of the operation, e.g. to contribute headers from a hook
+// It returns nil if the operation doesn't collect response headers, which is safe to use
+func (c *Context) ResponseHeaders() *ResponseHeaders {
+	return c.responseHeaders
+}
+
+// Interface Guards
+var _ httpclient.ResponseHeaderCollector = (*ResponseHeaders)(nil)
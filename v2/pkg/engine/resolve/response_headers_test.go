@@ -0,0 +1,111 @@
+package resolve
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseHeaders(t *testing.T) {
+	t.Run("should merge the contributions according to the conflict policy", func(t *testing.T) {
+		headers := NewResponseHeaders(ResponseHeaderOptions{
+			ConflictPolicies: map[string]HeaderConflictPolicy{
+				"set-cookie": HeaderConflictPolicyAppend,
+				"X-Region":   HeaderConflictPolicyFirstWins,
+			},
+		})
+		assert.True(t, headers.Add("Set-Cookie", "a=1"))
+		assert.True(t, headers.Add("set-cookie", "b=2"))
+		assert.True(t, headers.Add("X-Region", "eu"))
+		assert.True(t, headers.Add("X-Region", "us"))
+		assert.True(t, headers.Add("Cache-Control", "max-age=60"))
+		assert.True(t, headers.Add("Cache-Control", "no-store"))
+
+		assert.Equal(t, http.Header{
+			"Set-Cookie":    {"a=1", "b=2"},
+			"X-Region":      {"eu"},
+			"Cache-Control": {"no-store"},
+		}, headers.Header())
+	})
+
+	t.Run("should reject headers which are not allowed", func(t *testing.T) {
+		headers := NewResponseHeaders(ResponseHeaderOptions{
+			AllowedHeaders: []string{"cache-control"},
+		})
+		assert.True(t, headers.Add("Cache-Control", "max-age=60"))
+		assert.False(t, headers.Add("Content-Type", "text/html"))
+		assert.Equal(t, http.Header{"Cache-Control": {"max-age=60"}}, headers.Header())
+	})
+
+	t.Run("should apply the headers to a response", func(t *testing.T) {
+		headers := NewResponseHeaders(ResponseHeaderOptions{})
+		headers.Add("Cache-Control", "max-age=60")
+
+		header := http.Header{"Cache-Control": {"no-cache"}, "Content-Type": {"application/json"}}
+		headers.Apply(header)
+		assert.Equal(t, http.Header{"Cache-Control": {"max-age=60"}, "Content-Type": {"application/json"}}, header)
+	})
+
+	t.Run("should collect the upstream headers of upstream responses", func(t *testing.T) {
+		headers := NewResponseHeaders(ResponseHeaderOptions{
+			UpstreamHeaders:  []string{"set-cookie"},
+			ConflictPolicies: map[string]HeaderConflictPolicy{"Set-Cookie": HeaderConflictPolicyAppend},
+		})
+		headers.CollectResponseHeaders(http.Header{"Set-Cookie": {"a=1"}, "Content-Length": {"10"}})
+		headers.CollectResponseHeaders(http.Header{"Set-Cookie": {"b=2"}})
+		assert.Equal(t, http.Header{"Set-Cookie": {"a=1", "b=2"}}, headers.Header())
+	})
+
+	t.Run("should discard contributions without response headers", func(t *testing.T) {
+		var headers *ResponseHeaders
+		assert.False(t, headers.Add("Cache-Control", "max-age=60"))
+		headers.Apply(http.Header{})
+		assert.Equal(t, http.Header{}, headers.Header())
+		assert.Nil(t, ResponseHeadersFromContext(context.Background()))
+	})
+}
+
+func TestResolver_ResponseHeaders(t *testing.T) {
+	t.Run("datasources contribute response headers", testFnWithPostEvaluation(func(t *testing.T, ctrl *gomock.Controller) (node *GraphQLResponse, ctx Context, expectedOutput string, postEvaluation func(t *testing.T)) {
+		mockDataSource := NewMockDataSource(ctrl)
+		mockDataSource.EXPECT().
+			Load(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&bytes.Buffer{})).
+			DoAndReturn(func(ctx context.Context, input []byte, w io.Writer) (err error) {
+				ResponseHeadersFromContext(ctx).Add("Set-Cookie", "session=1")
+				_, err = w.Write([]byte(`{"data":{"name":"Jens"}}`))
+				return
+			})
+
+		headers := NewResponseHeaders(ResponseHeaderOptions{})
+		ctx = Context{ctx: context.Background()}
+		ctx.SetResponseHeaders(headers)
+
+		return &GraphQLResponse{
+			Data: &Object{
+				Fetch: &SingleFetch{
+					FetchConfiguration: FetchConfiguration{
+						DataSource: mockDataSource,
+						PostProcessing: PostProcessingConfiguration{
+							SelectResponseDataPath: []string{"data"},
+						},
+					},
+				},
+				Fields: []*Field{
+					{
+						Name: []byte("name"),
+						Value: &String{
+							Path: []string{"name"},
+						},
+					},
+				},
+			},
+		}, ctx, `{"data":{"name":"Jens"}}`, func(t *testing.T) {
+			assert.Equal(t, http.Header{"Set-Cookie": {"session=1"}}, headers.Header())
+		}
+	}))
+}
@@ -0,0 +1,90 @@
+package resolve
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// InvalidUTF8Policy defines how the Loader handles upstream responses containing invalid UTF-8
+// or unescaped control characters in strings, which would otherwise produce invalid json for the client
+type InvalidUTF8Policy int
+
+const (
+	// InvalidUTF8PolicyPassThrough merges the response as is
+	InvalidUTF8PolicyPassThrough InvalidUTF8Policy = iota
+	// InvalidUTF8PolicyReplace replaces invalid UTF-8 sequences with the unicode replacement character
+	// and escapes control characters in strings
+	InvalidUTF8PolicyReplace
+	// InvalidUTF8PolicyError rejects the response with a fetch error
+	InvalidUTF8PolicyError
+)
+
+const (
+	failedToFetchInvalidUTF8 = ", invalid UTF-8 or control characters in response"
+)
+
+// responseNeedsSanitization returns true if the response contains invalid UTF-8
+// or control characters within strings
+func responseNeedsSanitization(data []byte) bool {
+	if !utf8.Valid(data) {
+		return true
+	}
+	inString, escaped := false, false
+	for _, b := range data {
+		if !inString {
+			if b == '"' {
+				inString = true
+			}
+			continue
+		}
+		switch {
+		case escaped:
+			escaped = false
+		case b == '\\':
+			escaped = true
+		case b == '"':
+			inString = false
+		case b < 0x20:
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeResponse replaces invalid UTF-8 with the replacement character and escapes control characters in strings
+func sanitizeResponse(data []byte, out *bytes.Buffer) {
+	const hex = "0123456789abcdef"
+	inString, escaped := false, false
+	for len(data) != 0 {
+		r, size := utf8.DecodeRune(data)
+		if r == utf8.RuneError && size == 1 {
+			out.WriteRune(utf8.RuneError)
+			data = data[1:]
+			escaped = false
+			continue
+		}
+		b := data[0]
+		data = data[size:]
+		if size > 1 {
+			out.WriteRune(r)
+			escaped = false
+			continue
+		}
+		switch {
+		case !inString:
+			inString = b == '"'
+		case escaped:
+			escaped = false
+		case b == '\\':
+			escaped = true
+		case b == '"':
+			inString = false
+		case b < 0x20:
+			out.WriteString(`\u00`)
+			out.WriteByte(hex[b>>4])
+			out.WriteByte(hex[b&0xF])
+			continue
+		}
+		out.WriteByte(b)
+	}
+}
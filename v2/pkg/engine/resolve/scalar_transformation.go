@@ -0,0 +1,144 @@
+package resolve
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/buger/jsonparser"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astjson"
+)
+
+// ScalarTransformer converts a scalar value of an upstream response into the representation promised by the schema
+// value is the raw json value, the returned value has to be valid json
+type ScalarTransformer interface {
+	Transform(value []byte) ([]byte, error)
+}
+
+// ScalarTransformerFunc allows to use a function as a ScalarTransformer
+type ScalarTransformerFunc func(value []byte) ([]byte, error)
+
+func (f ScalarTransformerFunc) Transform(value []byte) ([]byte, error) {
+	return f(value)
+}
+
+// ScalarTransformation applies a ScalarTransformer to all values at Path in the response of a fetch
+// before it's merged into the response data
+type ScalarTransformation struct {
+	// Path is relative to the object the fetch is merged into, arrays on the path are traversed implicitly
+	Path        []string
+	Transformer ScalarTransformer
+}
+
+// EpochToISO8601Transformer converts a unix timestamp number into a RFC3339 string in UTC
+type EpochToISO8601Transformer struct {
+	// Milliseconds is set when the timestamp is in milliseconds instead of seconds
+	Milliseconds bool
+}
+
+func (t EpochToISO8601Transformer) Transform(value []byte) ([]byte, error) {
+	epoch, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid epoch timestamp: %s", value)
+	}
+	var timestamp time.Time
+	if t.Milliseconds {
+		timestamp = time.UnixMilli(epoch)
+	} else {
+		timestamp = time.Unix(epoch, 0)
+	}
+	return strconv.AppendQuote(nil, timestamp.UTC().Format(time.RFC3339Nano)), nil
+}
+
+// ISO8601ToEpochTransformer converts a RFC3339 string into a unix timestamp number
+type ISO8601ToEpochTransformer struct {
+	// Milliseconds is set when the timestamp should be in milliseconds instead of seconds
+	Milliseconds bool
+}
+
+func (t ISO8601ToEpochTransformer) Transform(value []byte) ([]byte, error) {
+	if len(value) < 2 || value[0] != '"' {
+		return nil, fmt.Errorf("invalid RFC3339 timestamp: %s", value)
+	}
+	str, err := jsonparser.ParseString(value[1 : len(value)-1])
+	if err != nil {
+		return nil, err
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, str)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RFC3339 timestamp: %s", str)
+	}
+	if t.Milliseconds {
+		return strconv.AppendInt(nil, timestamp.UnixMilli(), 10), nil
+	}
+	return strconv.AppendInt(nil, timestamp.Unix(), 10), nil
+}
+
+// transformScalars applies the scalar transformations of the fetch to the response node
+// the path of each transformation is relative to the merge target, so the MergePath is removed
+// values which can't be transformed are kept as is and rejected by the Resolvable if they don't match the schema
+func (l *Loader) transformScalars(res *result, node int) {
+	for i := range res.postProcessing.ScalarTransformations {
+		transformation := &res.postProcessing.ScalarTransformations[i]
+		path, ok := trimScalarTransformationPath(transformation.Path, res.postProcessing.MergePath)
+		if !ok {
+			continue
+		}
+		l.transformScalar(node, path, transformation.Transformer)
+	}
+}
+
+func trimScalarTransformationPath(path, mergePath []string) ([]string, bool) {
+	if len(mergePath) > len(path) {
+		return nil, false
+	}
+	for i := range mergePath {
+		if path[i] != mergePath[i] {
+			return nil, false
+		}
+	}
+	return path[len(mergePath):], true
+}
+
+func (l *Loader) transformScalar(node int, path []string, transformer ScalarTransformer) {
+	if !l.data.NodeIsDefined(node) {
+		return
+	}
+	switch l.data.Nodes[node].Kind {
+	case astjson.NodeKindArray:
+		for _, item := range l.data.Nodes[node].ArrayValues {
+			l.transformScalar(item, path, transformer)
+		}
+		return
+	case astjson.NodeKindObject:
+		if len(path) == 0 {
+			return
+		}
+		l.transformScalar(l.data.GetObjectField(node, path[0]), path[1:], transformer)
+		return
+	}
+	if len(path) != 0 {
+		return
+	}
+
+	value := l.data.Nodes[node].ValueBytes(l.data)
+	if l.data.Nodes[node].Kind == astjson.NodeKindString {
+		// string values are stored without quotes
+		value = append(append(append(make([]byte, 0, len(value)+2), quote...), value...), quote...)
+	}
+	transformed, err := transformer.Transform(value)
+	if err != nil {
+		return
+	}
+	if len(transformed) >= 2 && transformed[0] == '"' {
+		ref := l.data.AppendStringBytes(transformed[1 : len(transformed)-1])
+		l.data.Nodes[node] = l.data.Nodes[ref]
+		return
+	}
+	ref, err := l.data.AppendAnyJSONBytes(transformed)
+	if err != nil {
+		return
+	}
+	l.data.Nodes[node] = l.data.Nodes[ref]
+}
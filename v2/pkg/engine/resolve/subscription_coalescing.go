@@ -0,0 +1,172 @@
+package resolve
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/pool"
+)
+
+type SubscriptionCoalescingMode int
+
+const (
+	// SubscriptionCoalescingModeLatest delivers only the latest event of a burst
+	SubscriptionCoalescingModeLatest SubscriptionCoalescingMode = iota
+	// SubscriptionCoalescingModeArray delivers all events of a burst as a json array of responses in a single message
+	SubscriptionCoalescingModeArray
+)
+
+// SubscriptionCoalescing merges bursts of upstream events of a subscription into a single delivered message,
+// e.g. to reduce the rendering of dashboards subscribed to high frequency topics
+type SubscriptionCoalescing struct {
+	// Window is the time events are collected after the first event of a burst, 0 disables coalescing
+	Window time.Duration
+	Mode   SubscriptionCoalescingMode
+}
+
+// SetSubscriptionCoalescing enables the coalescing of the events of the subscription
+func (c *Context) SetSubscriptionCoalescing(coalescing SubscriptionCoalescing) {
+	c.subscriptionCoalescing = coalescing
+}
+
+// subscriptionCoalescer holds the events of a subscription collected within the coalescing window
+type subscriptionCoalescer struct {
+	config SubscriptionCoalescing
+	// flushMux serializes flushes, so the subscription is completed after the pending events were delivered
+	flushMux sync.Mutex
+	// pending and timer are guarded by the mux of the subscription
+	pending [][]byte
+	timer   *time.Timer
+}
+
+func (r *Resolver) coalesceSubscriptionUpdate(ctx *Context, sub *sub, data []byte) {
+	input := make([]byte, len(data))
+	copy(input, data)
+
+	sub.mux.Lock()
+	defer sub.mux.Unlock()
+	if sub.writer == nil {
+		return // subscription was already closed by the client
+	}
+	coalescer := sub.coalescer
+	if coalescer.config.Mode == SubscriptionCoalescingModeLatest {
+		coalescer.pending = append(coalescer.pending[:0], input)
+	} else {
+		coalescer.pending = append(coalescer.pending, input)
+	}
+	if coalescer.timer == nil {
+		coalescer.timer = time.AfterFunc(coalescer.config.Window, func() {
+			r.flushCoalescedSubscriptionUpdates(ctx, sub)
+		})
+	}
+}
+
+// flushCoalescedSubscriptionUpdates delivers the events collected within the coalescing window
+func (r *Resolver) flushCoalescedSubscriptionUpdates(ctx *Context, sub *sub) {
+	coalescer := sub.coalescer
+	coalescer.flushMux.Lock()
+	defer coalescer.flushMux.Unlock()
+
+	sub.mux.Lock()
+	inputs := coalescer.pending
+	coalescer.pending = nil
+	if coalescer.timer != nil {
+		coalescer.timer.Stop()
+		coalescer.timer = nil
+	}
+	closed := sub.writer == nil
+	sub.mux.Unlock()
+
+	if len(inputs) == 0 || closed {
+		return
+	}
+	if sub.initialized != nil {
+		<-sub.initialized
+	}
+	if coalescer.config.Mode == SubscriptionCoalescingModeLatest {
+		r.resolveSubscriptionUpdate(ctx, sub, inputs[len(inputs)-1])
+		return
+	}
+	r.resolveSubscriptionUpdates(ctx, sub, inputs)
+}
+
+// resolveSubscriptionUpdates resolves the events and delivers the responses as json array in a single message
+func (r *Resolver) resolveSubscriptionUpdates(ctx *Context, sub *sub, inputs [][]byte) {
+	sub.mux.Lock()
+	sub.pendingUpdates++
+	sub.mux.Unlock()
+	if r.options.Debug {
+		fmt.Printf("resolver:trigger:subscription:update:coalesced:%d:%d\n", sub.id.SubscriptionID, len(inputs))
+	}
+
+	buf := pool.BytesBuffer.Get()
+	defer pool.BytesBuffer.Put(buf)
+
+	wroteErrorsWithoutData := false
+	buf.WriteByte('[')
+	for i := range inputs {
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+		withoutData, err := r.resolveSubscriptionUpdateInto(ctx, sub, inputs[i], buf)
+		if err != nil {
+			sub.mux.Lock()
+			sub.pendingUpdates--
+			if sub.writer != nil {
+				errBuf := pool.BytesBuffer.Get()
+				r.asyncErrorWriter.WriteError(ctx, err, sub.resolve.Response, sub.writer, errBuf)
+				pool.BytesBuffer.Put(errBuf)
+			}
+			sub.mux.Unlock()
+			_ = r.AsyncUnsubscribeSubscription(sub.id)
+			return
+		}
+		wroteErrorsWithoutData = wroteErrorsWithoutData || withoutData
+	}
+	buf.WriteByte(']')
+
+	sub.mux.Lock()
+	sub.pendingUpdates--
+	defer sub.mux.Unlock()
+	if sub.writer == nil {
+		return // subscription was already closed by the client
+	}
+	if _, err := sub.writer.Write(buf.Bytes()); err != nil {
+		_ = r.AsyncUnsubscribeSubscription(sub.id)
+		return
+	}
+	if err := sub.writer.Flush(); err != nil {
+		// client disconnected
+		_ = r.AsyncUnsubscribeSubscription(sub.id)
+		return
+	}
+	if r.reporter != nil {
+		r.reporter.SubscriptionUpdateSent()
+	}
+	if wroteErrorsWithoutData {
+		_ = r.AsyncUnsubscribeSubscription(sub.id)
+	}
+}
+
+func (r *Resolver) resolveSubscriptionUpdateInto(ctx *Context, sub *sub, input []byte, buf *bytes.Buffer) (wroteErrorsWithoutData bool, err error) {
+	if timeout := r.subscriptionUpdateTimeout(ctx); timeout > 0 {
+		updateCtx, cancel := context.WithTimeout(ctx.ctx, timeout)
+		defer cancel()
+		ctx = ctx.WithContext(updateCtx)
+	}
+	t := r.getTools()
+	defer r.putTools(t)
+	if err = t.resolvable.InitSubscription(ctx, input, sub.resolve.Trigger.PostProcessing); err != nil {
+		return false, err
+	}
+	if err = t.loader.LoadGraphQLResponseData(ctx, sub.resolve.Response, t.resolvable); err != nil {
+		return false, err
+	}
+	if err = t.resolvable.Resolve(ctx.ctx, sub.resolve.Response.Data, buf); err != nil {
+		return false, err
+	}
+	return t.resolvable.WroteErrorsWithoutData(), nil
+}
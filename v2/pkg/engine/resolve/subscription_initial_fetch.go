@@ -0,0 +1,71 @@
+package resolve
+
+import (
+	"fmt"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/pool"
+)
+
+// SubscriptionInitialFetch loads the current state for every new subscriber of a subscription
+// The response is processed with the PostProcessing of the trigger, so it must have the same shape as the updates
+// It's sent as the first message of the subscription, updates of the trigger are held back until it's sent
+type SubscriptionInitialFetch struct {
+	Input         []byte
+	InputTemplate InputTemplate
+	Variables     Variables
+	DataSource    DataSource
+}
+
+func (r *Resolver) subscriptionInitialFetchInput(ctx *Context, subscription *GraphQLSubscription) ([]byte, error) {
+	if subscription.Trigger.InitialFetch == nil {
+		return nil, nil
+	}
+	buf := pool.BytesBuffer.Get()
+	defer pool.BytesBuffer.Put(buf)
+	err := subscription.Trigger.InitialFetch.InputTemplate.Render(ctx, nil, buf)
+	if err != nil {
+		return nil, err
+	}
+	input := make([]byte, buf.Len())
+	copy(input, buf.Bytes())
+	return input, nil
+}
+
+// startSubscriptionInitialFetch loads the initial state of the subscription asynchronously
+// updates of the trigger wait until the initial state is written
+func (r *Resolver) startSubscriptionInitialFetch(ctx *Context, s *sub, input []byte) {
+	if s.resolve.Trigger.InitialFetch == nil {
+		return
+	}
+	s.initialized = make(chan struct{})
+	done := r.goroutines.Track(ctx.Context(), "subscription initial fetch")
+	r.triggerUpdatePool.Submit(func() {
+		defer done()
+		defer close(s.initialized)
+		r.executeSubscriptionInitialFetch(ctx, s, input)
+	})
+}
+
+func (r *Resolver) executeSubscriptionInitialFetch(ctx *Context, s *sub, input []byte) {
+	if r.options.Debug {
+		fmt.Printf("resolver:trigger:subscription:initial_fetch:%d\n", s.id.SubscriptionID)
+	}
+	buf := pool.BytesBuffer.Get()
+	defer pool.BytesBuffer.Put(buf)
+	err := s.resolve.Trigger.InitialFetch.DataSource.Load(ctx.Context(), input, buf)
+	if err != nil {
+		s.mux.Lock()
+		if s.writer != nil {
+			errBuf := pool.BytesBuffer.Get()
+			defer pool.BytesBuffer.Put(errBuf)
+			r.asyncErrorWriter.WriteError(ctx, err, s.resolve.Response, s.writer, errBuf)
+		}
+		s.mux.Unlock()
+		_ = r.AsyncUnsubscribeSubscription(s.id)
+		if r.options.Debug {
+			fmt.Printf("resolver:trigger:subscription:initial_fetch:failed:%d\n", s.id.SubscriptionID)
+		}
+		return
+	}
+	r.resolveSubscriptionUpdate(ctx, s, buf.Bytes())
+}
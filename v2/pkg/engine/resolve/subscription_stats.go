@@ -0,0 +1,62 @@
+package resolve
+
+import (
+	"sync/atomic"
+)
+
+// SubscriptionStats are the numbers of active subscriptions and of the triggers delivering their events
+type SubscriptionStats struct {
+	Subscriptions int64
+	// Triggers are the upstream subscriptions, subscriptions with the same input share a trigger
+	Triggers int64
+}
+
+// subscriptionCounter is the Reporter of the Resolver, it counts the active subscriptions and triggers
+// and forwards all reports to the Reporter of the ResolverOptions
+type subscriptionCounter struct {
+	subscriptions atomic.Int64
+	triggers      atomic.Int64
+	next          Reporter
+}
+
+func (c *subscriptionCounter) SubscriptionUpdateSent() {
+	if c.next != nil {
+		c.next.SubscriptionUpdateSent()
+	}
+}
+
+func (c *subscriptionCounter) SubscriptionCountInc(count int) {
+	c.subscriptions.Add(int64(count))
+	if c.next != nil {
+		c.next.SubscriptionCountInc(count)
+	}
+}
+
+func (c *subscriptionCounter) SubscriptionCountDec(count int) {
+	c.subscriptions.Add(-int64(count))
+	if c.next != nil {
+		c.next.SubscriptionCountDec(count)
+	}
+}
+
+func (c *subscriptionCounter) TriggerCountInc(count int) {
+	c.triggers.Add(int64(count))
+	if c.next != nil {
+		c.next.TriggerCountInc(count)
+	}
+}
+
+func (c *subscriptionCounter) TriggerCountDec(count int) {
+	c.triggers.Add(-int64(count))
+	if c.next != nil {
+		c.next.TriggerCountDec(count)
+	}
+}
+
+// SubscriptionStats returns the numbers of active subscriptions and triggers of the resolver
+func (r *Resolver) SubscriptionStats() SubscriptionStats {
+	return SubscriptionStats{
+		Subscriptions: r.subscriptionCounter.subscriptions.Load(),
+		Triggers:      r.subscriptionCounter.triggers.Load(),
+	}
+}
@@ -0,0 +1,96 @@
+package resolve
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type SubscriptionThrottlePolicy int
+
+const (
+	// SubscriptionThrottlePolicyDrop drops the events received before the interval since the last delivered event elapsed
+	SubscriptionThrottlePolicyDrop SubscriptionThrottlePolicy = iota
+	// SubscriptionThrottlePolicyConflate delivers the latest event received within the interval once the interval elapsed
+	SubscriptionThrottlePolicyConflate
+)
+
+// SubscriptionThrottle limits the delivery of the events of a subscription field to one event per interval and subscriber
+type SubscriptionThrottle struct {
+	Interval time.Duration
+	Policy   SubscriptionThrottlePolicy
+}
+
+// subscriptionThrottler tracks the delivered events of a subscriber of a throttled subscription field
+type subscriptionThrottler struct {
+	config SubscriptionThrottle
+	// flushMux serializes flushes, so the subscription is completed after the conflated event was delivered
+	flushMux sync.Mutex
+	// nextUpdate, pending and timer are guarded by the mux of the subscription
+	nextUpdate time.Time
+	pending    []byte
+	timer      *time.Timer
+}
+
+// throttleSubscriptionUpdate returns true if the event can be delivered immediately
+// otherwise the event is dropped or conflated according to the policy of the throttle
+func (r *Resolver) throttleSubscriptionUpdate(ctx *Context, sub *sub, data []byte) bool {
+	sub.mux.Lock()
+	defer sub.mux.Unlock()
+	if sub.writer == nil {
+		return false // subscription was already closed by the client
+	}
+	throttler := sub.throttler
+	now := time.Now()
+	if throttler.timer == nil && !now.Before(throttler.nextUpdate) {
+		throttler.nextUpdate = now.Add(throttler.config.Interval)
+		return true
+	}
+	if throttler.config.Policy == SubscriptionThrottlePolicyDrop {
+		if r.options.Debug {
+			fmt.Printf("resolver:trigger:subscription:update:throttled:%d\n", sub.id.SubscriptionID)
+		}
+		return false
+	}
+	throttler.pending = append(throttler.pending[:0], data...)
+	if throttler.timer == nil {
+		throttler.timer = time.AfterFunc(throttler.nextUpdate.Sub(now), func() {
+			r.flushThrottledSubscriptionUpdate(ctx, sub)
+		})
+	}
+	return false
+}
+
+// flushThrottledSubscriptionUpdate delivers the conflated event of the subscriber
+func (r *Resolver) flushThrottledSubscriptionUpdate(ctx *Context, sub *sub) {
+	throttler := sub.throttler
+	throttler.flushMux.Lock()
+	defer throttler.flushMux.Unlock()
+
+	sub.mux.Lock()
+	input := throttler.pending
+	throttler.pending = nil
+	if throttler.timer != nil {
+		throttler.timer.Stop()
+		throttler.timer = nil
+	}
+	if input != nil {
+		throttler.nextUpdate = time.Now().Add(throttler.config.Interval)
+	}
+	closed := sub.writer == nil
+	sub.mux.Unlock()
+
+	if input == nil || closed {
+		return
+	}
+	r.deliverSubscriptionUpdate(ctx, sub, input)
+}
+
+// deliverSubscriptionUpdate resolves the event unless it has to be coalesced with other events of the subscription
+func (r *Resolver) deliverSubscriptionUpdate(ctx *Context, sub *sub, input []byte) {
+	if sub.coalescer != nil {
+		r.coalesceSubscriptionUpdate(ctx, sub, input)
+		return
+	}
+	r.executeSubscriptionUpdate(ctx, sub, input)
+}
@@ -1,10 +1,14 @@
 package resolve
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 
 	"github.com/google/uuid"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
 )
 
 type TraceOptions struct {
@@ -32,6 +36,9 @@ type TraceOptions struct {
 	IncludeTraceOutputInResponseExtensions bool
 	// Debug makes trace IDs of fetches predictable for debugging purposes
 	Debug bool
+	// RedactValues replaces the literals of the upstream operation and the values of its variables
+	// in the rendered input of a load operation with placeholders
+	RedactValues bool
 }
 
 func (r *TraceOptions) EnableAll() {
@@ -60,6 +67,7 @@ func (r *TraceOptions) DisableAll() {
 	r.ExcludeLoadStats = true
 	r.EnablePredictableDebugTimings = false
 	r.IncludeTraceOutputInResponseExtensions = false
+	r.RedactValues = false
 }
 
 type TraceFetchType string
@@ -181,6 +189,8 @@ func parseFetch(fetch Fetch, options *getTraceOptions) *TraceFetch {
 	traceFetch := &TraceFetch{}
 	if options.debug {
 		traceFetch.Id = "00000000-0000-0000-0000-000000000000"
+	} else if options.ids != nil {
+		traceFetch.Id = options.ids.TraceFetchID()
 	} else {
 		traceFetch.Id = uuid.New().String()
 	}
@@ -290,6 +300,7 @@ func parseNode(n Node, options *getTraceOptions) *TraceNode {
 
 type getTraceOptions struct {
 	debug bool
+	ids   IDGenerator
 }
 
 type GetTraceOption func(*getTraceOptions)
@@ -300,6 +311,13 @@ func GetTraceDebug() GetTraceOption {
 	}
 }
 
+// GetTraceIDGenerator generates the IDs of the fetches with the generator instead of random UUIDs
+func GetTraceIDGenerator(ids IDGenerator) GetTraceOption {
+	return func(o *getTraceOptions) {
+		o.ids = ids
+	}
+}
+
 func GetTrace(ctx context.Context, root *Object, opts ...GetTraceOption) *TraceNode {
 	options := &getTraceOptions{}
 	for i := range opts {
@@ -309,3 +327,61 @@ func GetTrace(ctx context.Context, root *Object, opts ...GetTraceOption) *TraceN
 	node.Info = GetTraceInfo(ctx)
 	return node
 }
+
+const redactedTraceValue = "****"
+
+// redactInputValues replaces all scalar values of body.variables and all literals of body.query of a rendered input
+func redactInputValues(input json.RawMessage) json.RawMessage {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(input, &obj); err != nil {
+		return input
+	}
+	body, ok := obj["body"].(map[string]interface{})
+	if !ok {
+		return input
+	}
+	if variables, ok := body["variables"]; ok {
+		body["variables"] = redactJSONScalars(variables)
+	}
+	if query, ok := body["query"].(string); ok {
+		body["query"] = redactOperationLiterals(query)
+	}
+	redacted, err := json.Marshal(obj)
+	if err != nil {
+		return input
+	}
+	return redacted
+}
+
+func redactJSONScalars(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = redactJSONScalars(v[key])
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = redactJSONScalars(v[i])
+		}
+		return v
+	case nil:
+		return nil
+	default:
+		return redactedTraceValue
+	}
+}
+
+// redactOperationLiterals prints the operation with redacted literals
+// operations which can't be parsed are replaced completely as they might contain literals
+func redactOperationLiterals(query string) string {
+	operation, report := astparser.ParseGraphqlDocumentString(query)
+	if report.HasErrors() {
+		return redactedTraceValue
+	}
+	out := &bytes.Buffer{}
+	if err := astprinter.PrintRedacted(&operation, nil, nil, out); err != nil {
+		return redactedTraceValue
+	}
+	return out.String()
+}
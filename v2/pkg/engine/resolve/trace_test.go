@@ -0,0 +1,26 @@
+package resolve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactInputValues(t *testing.T) {
+	t.Run("should redact variables and literals of the upstream operation", func(t *testing.T) {
+		input := []byte(`{"method":"POST","url":"http://localhost","body":{"query":"query($a: ID!){user(id: $a){friends(first: 10, name: \"Leia\"){name}}}","variables":{"a":"1","b":{"c":[1,true,null]}}}}`)
+		assert.Equal(t,
+			`{"body":{"query":"query($a: ID!){user(id: $a){friends(first: \"****\", name: \"****\"){name}}}","variables":{"a":"****","b":{"c":["****","****",null]}}},"method":"POST","url":"http://localhost"}`,
+			string(redactInputValues(input)))
+	})
+
+	t.Run("should replace operations which can't be parsed", func(t *testing.T) {
+		input := []byte(`{"body":{"query":"query { user(id: \"1\""}}`)
+		assert.Equal(t, `{"body":{"query":"****"}}`, string(redactInputValues(input)))
+	})
+
+	t.Run("should keep inputs without body", func(t *testing.T) {
+		input := []byte(`{"url":"http://localhost"}`)
+		assert.Equal(t, string(input), string(redactInputValues(input)))
+	})
+}
@@ -0,0 +1,416 @@
+package federation
+
+import (
+	"errors"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+)
+
+const (
+	InaccessibleDirectiveName = "inaccessible"
+	TagDirectiveName          = "tag"
+)
+
+// Contract filters the public schema of a supergraph by the @tag directives of its elements,
+// so that variants of the schema can be served to different clients from one supergraph
+type Contract struct {
+	// IncludeTags keeps only the fields of object and interface types which are tagged with one of the tags
+	// or are defined on a type tagged with one of the tags, if not empty
+	IncludeTags []string
+	// ExcludeTags removes the types, fields, arguments and enum values tagged with one of the tags,
+	// it takes precedence over IncludeTags
+	ExcludeTags []string
+	// RemoveUnreachableTypes removes the types which are not reachable from the root operation types after filtering
+	RemoveUnreachableTypes bool
+}
+
+// BuildPublicSchema returns the public schema of a supergraph.
+// Elements marked with @inaccessible or filtered by the contract are removed, as well as fields and arguments referencing removed types
+// and types without remaining fields. The @inaccessible and @tag directives are not part of the public schema.
+// The public schema is used to plan the operations, so fields which are selected by @key or @requires have to stay accessible.
+func BuildPublicSchema(schema string, contract Contract) (string, error) {
+	doc, report := astparser.ParseGraphqlDocumentString(schema)
+	if report.HasErrors() {
+		return "", report
+	}
+
+	builder := &publicSchemaBuilder{
+		doc:          &doc,
+		includeTags:  toSet(contract.IncludeTags),
+		excludeTags:  toSet(contract.ExcludeTags),
+		removedTypes: map[string]struct{}{},
+	}
+	builder.filterElements()
+	builder.removeDanglingReferences()
+	if contract.RemoveUnreachableTypes {
+		builder.removeUnreachableTypes()
+	}
+	if err := builder.removeTypes(); err != nil {
+		return "", err
+	}
+	builder.removeDirectives()
+
+	return astprinter.PrintString(&doc, nil)
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		set[value] = struct{}{}
+	}
+	return set
+}
+
+type publicSchemaBuilder struct {
+	doc          *ast.Document
+	includeTags  map[string]struct{}
+	excludeTags  map[string]struct{}
+	removedTypes map[string]struct{}
+}
+
+// hidden returns true if the directives mark the element as inaccessible or tag it with an excluded tag
+func (b *publicSchemaBuilder) hidden(directiveRefs []int) bool {
+	for _, ref := range directiveRefs {
+		switch b.doc.DirectiveNameString(ref) {
+		case InaccessibleDirectiveName:
+			return true
+		case TagDirectiveName:
+			if _, ok := b.excludeTags[b.tagName(ref)]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// included returns true if no include tags are configured or the directives tag the element with an included tag
+func (b *publicSchemaBuilder) included(directiveRefs []int) bool {
+	if len(b.includeTags) == 0 {
+		return true
+	}
+	for _, ref := range directiveRefs {
+		if b.doc.DirectiveNameString(ref) != TagDirectiveName {
+			continue
+		}
+		if _, ok := b.includeTags[b.tagName(ref)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *publicSchemaBuilder) tagName(directiveRef int) string {
+	value, ok := b.doc.DirectiveArgumentValueByName(directiveRef, []byte("name"))
+	if !ok || value.Kind != ast.ValueKindString {
+		return ""
+	}
+	return b.doc.StringValueContentString(value.Ref)
+}
+
+func (b *publicSchemaBuilder) isRemoved(typeRef int) bool {
+	_, ok := b.removedTypes[b.doc.ResolveTypeNameString(typeRef)]
+	return ok
+}
+
+// filterElements removes the hidden types and elements and the elements which are not included
+func (b *publicSchemaBuilder) filterElements() {
+	doc := b.doc
+	for _, node := range doc.RootNodes {
+		if b.hidden(doc.NodeDirectives(node)) {
+			b.removedTypes[doc.NodeNameString(node)] = struct{}{}
+		}
+	}
+
+	for i := range doc.ObjectTypeDefinitions {
+		definition := &doc.ObjectTypeDefinitions[i]
+		definition.FieldsDefinition.Refs = b.filterFields(definition.FieldsDefinition.Refs, b.included(definition.Directives.Refs))
+	}
+	for i := range doc.InterfaceTypeDefinitions {
+		definition := &doc.InterfaceTypeDefinitions[i]
+		definition.FieldsDefinition.Refs = b.filterFields(definition.FieldsDefinition.Refs, b.included(definition.Directives.Refs))
+	}
+	for i := range doc.InputObjectTypeDefinitions {
+		definition := &doc.InputObjectTypeDefinitions[i]
+		definition.InputFieldsDefinition.Refs = b.filterInputValues(definition.InputFieldsDefinition.Refs)
+	}
+	for i := range doc.EnumTypeDefinitions {
+		definition := &doc.EnumTypeDefinitions[i]
+		values := definition.EnumValuesDefinition.Refs[:0]
+		for _, ref := range definition.EnumValuesDefinition.Refs {
+			if !b.hidden(doc.EnumValueDefinitions[ref].Directives.Refs) {
+				values = append(values, ref)
+			}
+		}
+		definition.EnumValuesDefinition.Refs = values
+	}
+}
+
+func (b *publicSchemaBuilder) filterFields(fieldRefs []int, typeIncluded bool) []int {
+	fields := fieldRefs[:0]
+	for _, ref := range fieldRefs {
+		definition := &b.doc.FieldDefinitions[ref]
+		if b.hidden(definition.Directives.Refs) || !(typeIncluded || b.included(definition.Directives.Refs)) {
+			continue
+		}
+		definition.ArgumentsDefinition.Refs = b.filterInputValues(definition.ArgumentsDefinition.Refs)
+		fields = append(fields, ref)
+	}
+	return fields
+}
+
+func (b *publicSchemaBuilder) filterInputValues(inputValueRefs []int) []int {
+	inputValues := inputValueRefs[:0]
+	for _, ref := range inputValueRefs {
+		if !b.hidden(b.doc.InputValueDefinitions[ref].Directives.Refs) {
+			inputValues = append(inputValues, ref)
+		}
+	}
+	return inputValues
+}
+
+// removeDanglingReferences removes the elements referencing removed types and the types without remaining elements
+// until no more types are removed
+func (b *publicSchemaBuilder) removeDanglingReferences() {
+	doc := b.doc
+	for removed := true; removed; {
+		count := len(b.removedTypes)
+
+		for i := range doc.ObjectTypeDefinitions {
+			definition := &doc.ObjectTypeDefinitions[i]
+			definition.FieldsDefinition.Refs = b.removeDanglingFields(definition.FieldsDefinition.Refs)
+			definition.ImplementsInterfaces.Refs = b.removeDanglingTypes(definition.ImplementsInterfaces.Refs)
+			b.removeTypeIfEmpty(doc.ObjectTypeDefinitionNameString(i), len(definition.FieldsDefinition.Refs))
+		}
+		for i := range doc.InterfaceTypeDefinitions {
+			definition := &doc.InterfaceTypeDefinitions[i]
+			definition.FieldsDefinition.Refs = b.removeDanglingFields(definition.FieldsDefinition.Refs)
+			definition.ImplementsInterfaces.Refs = b.removeDanglingTypes(definition.ImplementsInterfaces.Refs)
+			b.removeTypeIfEmpty(doc.InterfaceTypeDefinitionNameString(i), len(definition.FieldsDefinition.Refs))
+		}
+		for i := range doc.InputObjectTypeDefinitions {
+			definition := &doc.InputObjectTypeDefinitions[i]
+			inputFields := definition.InputFieldsDefinition.Refs[:0]
+			for _, ref := range definition.InputFieldsDefinition.Refs {
+				if !b.isRemoved(doc.InputValueDefinitions[ref].Type) {
+					inputFields = append(inputFields, ref)
+				}
+			}
+			definition.InputFieldsDefinition.Refs = inputFields
+			b.removeTypeIfEmpty(doc.InputObjectTypeDefinitionNameString(i), len(inputFields))
+		}
+		for i := range doc.EnumTypeDefinitions {
+			b.removeTypeIfEmpty(doc.EnumTypeDefinitionNameString(i), len(doc.EnumTypeDefinitions[i].EnumValuesDefinition.Refs))
+		}
+		for i := range doc.UnionTypeDefinitions {
+			definition := &doc.UnionTypeDefinitions[i]
+			definition.UnionMemberTypes.Refs = b.removeDanglingTypes(definition.UnionMemberTypes.Refs)
+			b.removeTypeIfEmpty(doc.UnionTypeDefinitionNameString(i), len(definition.UnionMemberTypes.Refs))
+		}
+
+		removed = len(b.removedTypes) != count
+	}
+}
+
+// removeDanglingFields removes the fields returning removed types and the fields with a required argument of a removed type
+func (b *publicSchemaBuilder) removeDanglingFields(fieldRefs []int) []int {
+	fields := fieldRefs[:0]
+	for _, ref := range fieldRefs {
+		definition := &b.doc.FieldDefinitions[ref]
+		if b.isRemoved(definition.Type) {
+			continue
+		}
+		arguments := definition.ArgumentsDefinition.Refs[:0]
+		required := false
+		for _, argumentRef := range definition.ArgumentsDefinition.Refs {
+			argument := b.doc.InputValueDefinitions[argumentRef]
+			if !b.isRemoved(argument.Type) {
+				arguments = append(arguments, argumentRef)
+				continue
+			}
+			if b.doc.TypeIsNonNull(argument.Type) && !argument.DefaultValue.IsDefined {
+				required = true
+			}
+		}
+		if required {
+			continue
+		}
+		definition.ArgumentsDefinition.Refs = arguments
+		fields = append(fields, ref)
+	}
+	return fields
+}
+
+func (b *publicSchemaBuilder) removeDanglingTypes(typeRefs []int) []int {
+	types := typeRefs[:0]
+	for _, ref := range typeRefs {
+		if !b.isRemoved(ref) {
+			types = append(types, ref)
+		}
+	}
+	return types
+}
+
+func (b *publicSchemaBuilder) removeTypeIfEmpty(name string, elements int) {
+	if elements == 0 {
+		b.removedTypes[name] = struct{}{}
+	}
+}
+
+func (b *publicSchemaBuilder) rootTypeNames() []string {
+	names := []string{"Query", "Mutation", "Subscription"}
+	for i, name := range []ast.ByteSlice{b.doc.Index.QueryTypeName, b.doc.Index.MutationTypeName, b.doc.Index.SubscriptionTypeName} {
+		if len(name) > 0 {
+			names[i] = string(name)
+		}
+	}
+	return names
+}
+
+// removeUnreachableTypes removes the types which are neither reachable from the root operation types
+// nor implement a reachable interface
+func (b *publicSchemaBuilder) removeUnreachableTypes() {
+	doc := b.doc
+	reachable := map[string]struct{}{}
+	queue := b.rootTypeNames()
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if _, ok := reachable[name]; ok {
+			continue
+		}
+		if _, ok := b.removedTypes[name]; ok {
+			continue
+		}
+		node, ok := doc.Index.FirstNodeByNameStr(name)
+		if !ok {
+			continue
+		}
+		reachable[name] = struct{}{}
+
+		var fieldRefs, inputValueRefs, typeRefs []int
+		switch node.Kind {
+		case ast.NodeKindObjectTypeDefinition:
+			fieldRefs = doc.ObjectTypeDefinitions[node.Ref].FieldsDefinition.Refs
+			typeRefs = doc.ObjectTypeDefinitions[node.Ref].ImplementsInterfaces.Refs
+		case ast.NodeKindInterfaceTypeDefinition:
+			fieldRefs = doc.InterfaceTypeDefinitions[node.Ref].FieldsDefinition.Refs
+			typeRefs = doc.InterfaceTypeDefinitions[node.Ref].ImplementsInterfaces.Refs
+			for i := range doc.ObjectTypeDefinitions {
+				if doc.ObjectTypeDefinitionImplementsInterface(i, []byte(name)) {
+					queue = append(queue, doc.ObjectTypeDefinitionNameString(i))
+				}
+			}
+		case ast.NodeKindInputObjectTypeDefinition:
+			inputValueRefs = doc.InputObjectTypeDefinitions[node.Ref].InputFieldsDefinition.Refs
+		case ast.NodeKindUnionTypeDefinition:
+			typeRefs = doc.UnionTypeDefinitions[node.Ref].UnionMemberTypes.Refs
+		}
+		for _, ref := range fieldRefs {
+			queue = append(queue, doc.ResolveTypeNameString(doc.FieldDefinitions[ref].Type))
+			inputValueRefs = append(inputValueRefs, doc.FieldDefinitions[ref].ArgumentsDefinition.Refs...)
+		}
+		for _, ref := range inputValueRefs {
+			queue = append(queue, doc.ResolveTypeNameString(doc.InputValueDefinitions[ref].Type))
+		}
+		for _, ref := range typeRefs {
+			queue = append(queue, doc.ResolveTypeNameString(ref))
+		}
+	}
+
+	for _, node := range doc.RootNodes {
+		switch node.Kind {
+		case ast.NodeKindObjectTypeDefinition, ast.NodeKindInterfaceTypeDefinition, ast.NodeKindInputObjectTypeDefinition,
+			ast.NodeKindEnumTypeDefinition, ast.NodeKindUnionTypeDefinition, ast.NodeKindScalarTypeDefinition:
+			name := doc.NodeNameString(node)
+			if _, ok := reachable[name]; !ok {
+				b.removedTypes[name] = struct{}{}
+			}
+		}
+	}
+}
+
+func (b *publicSchemaBuilder) removeTypes() error {
+	doc := b.doc
+	if _, ok := b.removedTypes[b.rootTypeNames()[0]]; ok {
+		return errors.New("the public schema has no query fields")
+	}
+
+	rootNodes := doc.RootNodes[:0]
+	for _, node := range doc.RootNodes {
+		name := doc.NodeNameString(node)
+		switch node.Kind {
+		case ast.NodeKindDirectiveDefinition:
+			if name == InaccessibleDirectiveName || name == TagDirectiveName {
+				continue
+			}
+		case ast.NodeKindSchemaDefinition, ast.NodeKindSchemaExtension:
+		default:
+			if _, ok := b.removedTypes[name]; ok {
+				continue
+			}
+		}
+		rootNodes = append(rootNodes, node)
+	}
+	doc.RootNodes = rootNodes
+	return nil
+}
+
+// removeDirectives removes the @inaccessible and @tag directives of the remaining elements
+// and updates the flags of the filtered lists
+func (b *publicSchemaBuilder) removeDirectives() {
+	doc := b.doc
+	for i := range doc.ObjectTypeDefinitions {
+		definition := &doc.ObjectTypeDefinitions[i]
+		definition.HasDirectives = b.removeDirectiveRefs(&definition.Directives)
+		definition.HasFieldDefinitions = len(definition.FieldsDefinition.Refs) > 0
+	}
+	for i := range doc.InterfaceTypeDefinitions {
+		definition := &doc.InterfaceTypeDefinitions[i]
+		definition.HasDirectives = b.removeDirectiveRefs(&definition.Directives)
+		definition.HasFieldDefinitions = len(definition.FieldsDefinition.Refs) > 0
+	}
+	for i := range doc.InputObjectTypeDefinitions {
+		definition := &doc.InputObjectTypeDefinitions[i]
+		definition.HasDirectives = b.removeDirectiveRefs(&definition.Directives)
+		definition.HasInputFieldsDefinition = len(definition.InputFieldsDefinition.Refs) > 0
+	}
+	for i := range doc.EnumTypeDefinitions {
+		definition := &doc.EnumTypeDefinitions[i]
+		definition.HasDirectives = b.removeDirectiveRefs(&definition.Directives)
+		definition.HasEnumValuesDefinition = len(definition.EnumValuesDefinition.Refs) > 0
+	}
+	for i := range doc.UnionTypeDefinitions {
+		definition := &doc.UnionTypeDefinitions[i]
+		definition.HasDirectives = b.removeDirectiveRefs(&definition.Directives)
+		definition.HasUnionMemberTypes = len(definition.UnionMemberTypes.Refs) > 0
+	}
+	for i := range doc.ScalarTypeDefinitions {
+		doc.ScalarTypeDefinitions[i].HasDirectives = b.removeDirectiveRefs(&doc.ScalarTypeDefinitions[i].Directives)
+	}
+	for i := range doc.FieldDefinitions {
+		definition := &doc.FieldDefinitions[i]
+		definition.HasDirectives = b.removeDirectiveRefs(&definition.Directives)
+		definition.HasArgumentsDefinitions = len(definition.ArgumentsDefinition.Refs) > 0
+	}
+	for i := range doc.InputValueDefinitions {
+		doc.InputValueDefinitions[i].HasDirectives = b.removeDirectiveRefs(&doc.InputValueDefinitions[i].Directives)
+	}
+	for i := range doc.EnumValueDefinitions {
+		doc.EnumValueDefinitions[i].HasDirectives = b.removeDirectiveRefs(&doc.EnumValueDefinitions[i].Directives)
+	}
+}
+
+func (b *publicSchemaBuilder) removeDirectiveRefs(directives *ast.DirectiveList) (hasDirectives bool) {
+	refs := directives.Refs[:0]
+	for _, ref := range directives.Refs {
+		switch b.doc.DirectiveNameString(ref) {
+		case InaccessibleDirectiveName, TagDirectiveName:
+		default:
+			refs = append(refs, ref)
+		}
+	}
+	directives.Refs = refs
+	return len(refs) > 0
+}
@@ -0,0 +1,159 @@
+package federation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+)
+
+const supergraphSchema = `
+	directive @inaccessible on FIELD_DEFINITION | OBJECT | INTERFACE | UNION | ARGUMENT_DEFINITION | SCALAR | ENUM | ENUM_VALUE | INPUT_OBJECT | INPUT_FIELD_DEFINITION
+	directive @tag(name: String!) repeatable on FIELD_DEFINITION | OBJECT | INTERFACE | UNION | ARGUMENT_DEFINITION | SCALAR | ENUM | ENUM_VALUE | INPUT_OBJECT | INPUT_FIELD_DEFINITION
+
+	type Query {
+		me: User @tag(name: "public")
+		products(filter: ProductFilter): [Product] @tag(name: "public")
+		audit: AuditLog @tag(name: "internal")
+		debug: String @inaccessible
+	}
+
+	type User @tag(name: "public") {
+		id: ID!
+		name: String!
+		role: Role
+		passwordHash: String @inaccessible
+		reviews: [Review] @tag(name: "internal")
+	}
+
+	type Product {
+		upc: String! @tag(name: "public")
+		name: String @tag(name: "public")
+		cost: Int @tag(name: "internal")
+	}
+
+	type Review {
+		body: String
+	}
+
+	type AuditLog @tag(name: "internal") {
+		entries: [String]
+	}
+
+	enum Role {
+		ADMIN @tag(name: "internal")
+		CUSTOMER
+	}
+
+	input ProductFilter {
+		name: String
+		supplier: String @inaccessible
+	}
+`
+
+func TestBuildPublicSchema(t *testing.T) {
+	build := func(t *testing.T, schema string, contract Contract) string {
+		actual, err := BuildPublicSchema(schema, contract)
+		require.NoError(t, err)
+		doc, report := astparser.ParseGraphqlDocumentString(actual)
+		require.False(t, report.HasErrors(), report.Error())
+		out, err := astprinter.PrintStringIndent(&doc, nil, "  ")
+		require.NoError(t, err)
+		return out
+	}
+
+	t.Run("should remove inaccessible elements", func(t *testing.T) {
+		actual := build(t, supergraphSchema, Contract{})
+		assert.Equal(t, `type Query {
+    me: User
+    products(filter: ProductFilter): [Product]
+    audit: AuditLog
+}
+
+type User {
+    id: ID!
+    name: String!
+    role: Role
+    reviews: [Review]
+}
+
+type Product {
+    upc: String!
+    name: String
+    cost: Int
+}
+
+type Review {
+    body: String
+}
+
+type AuditLog {
+    entries: [String]
+}
+
+enum Role {
+    ADMIN
+    CUSTOMER
+}
+
+input ProductFilter {
+    name: String
+}`, actual)
+	})
+
+	t.Run("should remove excluded elements and unreachable types", func(t *testing.T) {
+		actual := build(t, supergraphSchema, Contract{ExcludeTags: []string{"internal"}, RemoveUnreachableTypes: true})
+		assert.Equal(t, `type Query {
+    me: User
+    products(filter: ProductFilter): [Product]
+}
+
+type User {
+    id: ID!
+    name: String!
+    role: Role
+}
+
+type Product {
+    upc: String!
+    name: String
+}
+
+enum Role {
+    CUSTOMER
+}
+
+input ProductFilter {
+    name: String
+}`, actual)
+	})
+
+	t.Run("should keep only included elements", func(t *testing.T) {
+		actual := build(t, supergraphSchema, Contract{IncludeTags: []string{"internal"}, RemoveUnreachableTypes: true})
+		assert.Equal(t, `type Query {
+    audit: AuditLog
+}
+
+type AuditLog {
+    entries: [String]
+}`, actual)
+	})
+
+	t.Run("should remove fields referencing removed types", func(t *testing.T) {
+		actual := build(t, `
+			type Query { me: User search(input: SearchInput!): [String] products(filter: SearchInput): [String] }
+			type User @inaccessible { id: ID! }
+			input SearchInput @inaccessible { term: String }`, Contract{})
+		assert.Equal(t, `type Query {
+    products: [String]
+}`, actual)
+	})
+
+	t.Run("should reject schemas without query fields", func(t *testing.T) {
+		_, err := BuildPublicSchema(`type Query { me: User } type User { id: ID! @tag(name: "internal") }`, Contract{ExcludeTags: []string{"internal"}})
+		assert.EqualError(t, err, "the public schema has no query fields")
+	})
+}
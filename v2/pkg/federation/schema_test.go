@@ -14,6 +14,16 @@ func TestSchemaBuilder_BuildFederationSchema(t *testing.T) {
 	goldie.Assert(t, "federated_schema", []byte(actual))
 }
 
+func TestSchemaBuilder_BuildFederationSchema_SpecifiedBy(t *testing.T) {
+	sdl := `scalar DateTime @specifiedBy(url: "https://scalars.graphql.org/andimarek/date-time") type Query { now: DateTime }`
+
+	baseSchema, err := BuildBaseSchemaDocument(sdl)
+	assert.NoError(t, err)
+	actual, err := BuildFederationSchema(baseSchema, sdl)
+	assert.NoError(t, err)
+	assert.Contains(t, actual, `scalar DateTime @specifiedBy(url: "https://scalars.graphql.org/andimarek/date-time")`)
+}
+
 const serviceSDL = `extend type Query {topProducts(first: Int = 5): [Product]}type Product @key(fields: "upc") {upc: String!name: String! price: Int!} extend type Query {me: User} type User @key(fields: "id"){ id: ID! username: String!} type Review { body: String! author: User! @provides(fields: "username") product: Product! } extend type User @key(fields: "id") { id: ID! @external reviews: [Review] } extend type Product @key(fields: "upc") { upc: String! @external reviews: [Review] }`
 
 const baseSchema = `
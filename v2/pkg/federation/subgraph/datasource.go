@@ -0,0 +1,245 @@
+package subgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/buger/jsonparser"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+var null = []byte("null")
+
+type serviceConfiguration struct {
+	SDL string `json:"sdl"`
+}
+
+// entitiesConfiguration identifies the _entities datasource, the resolvers are held by the factory
+type entitiesConfiguration struct {
+	TypeNames []string `json:"type_names"`
+}
+
+type serviceFactory struct{}
+
+func (f *serviceFactory) Planner(_ context.Context) plan.DataSourcePlanner {
+	return &servicePlanner{}
+}
+
+// servicePlanner plans the _service field, the sdl is written by the source,
+// so it's not processed as input template
+type servicePlanner struct {
+	config serviceConfiguration
+}
+
+func (p *servicePlanner) UpstreamSchema(_ plan.DataSourceConfiguration) *ast.Document {
+	return nil
+}
+
+func (p *servicePlanner) Register(_ *plan.Visitor, configuration plan.DataSourceConfiguration, _ plan.DataSourcePlannerConfiguration) error {
+	return json.Unmarshal(configuration.Custom, &p.config)
+}
+
+func (p *servicePlanner) DownstreamResponseFieldAlias(_ int) (alias string, exists bool) {
+	// skip, not required
+	return
+}
+
+func (p *servicePlanner) DataSourcePlanningBehavior() plan.DataSourcePlanningBehavior {
+	return plan.DataSourcePlanningBehavior{
+		MergeAliasedRootNodes:      false,
+		OverrideFieldPathFromAlias: false,
+	}
+}
+
+func (p *servicePlanner) ConfigureFetch() resolve.FetchConfiguration {
+	return resolve.FetchConfiguration{
+		Input:      "{}",
+		DataSource: &serviceSource{sdl: p.config.SDL},
+	}
+}
+
+func (p *servicePlanner) ConfigureSubscription() plan.SubscriptionConfiguration {
+	// the _service DataSourcePlanner doesn't have subscriptions
+	return plan.SubscriptionConfiguration{}
+}
+
+type serviceSource struct {
+	sdl string
+}
+
+func (s *serviceSource) Load(_ context.Context, _ []byte, w io.Writer) error {
+	sdl, err := json.Marshal(s.sdl)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(append([]byte(`{"`+ServiceFieldName+`":{"sdl":`), sdl...), "}}"...))
+	return err
+}
+
+type entitiesFactory struct {
+	definition *ast.Document
+	resolvers  map[string]EntityResolver
+}
+
+func (f *entitiesFactory) Planner(_ context.Context) plan.DataSourcePlanner {
+	return &entitiesPlanner{
+		definition: f.definition,
+		resolvers:  f.resolvers,
+	}
+}
+
+// entitiesPlanner plans the _entities field, the representations argument is rendered into the input by the visitor
+type entitiesPlanner struct {
+	definition *ast.Document
+	resolvers  map[string]EntityResolver
+}
+
+// UpstreamSchema returns the schema of the subgraph, so selections on the _Entity union are not rewritten
+func (p *entitiesPlanner) UpstreamSchema(_ plan.DataSourceConfiguration) *ast.Document {
+	return p.definition
+}
+
+func (p *entitiesPlanner) Register(_ *plan.Visitor, _ plan.DataSourceConfiguration, _ plan.DataSourcePlannerConfiguration) error {
+	return nil
+}
+
+func (p *entitiesPlanner) DownstreamResponseFieldAlias(_ int) (alias string, exists bool) {
+	// skip, not required
+	return
+}
+
+func (p *entitiesPlanner) DataSourcePlanningBehavior() plan.DataSourcePlanningBehavior {
+	return plan.DataSourcePlanningBehavior{
+		MergeAliasedRootNodes:      false,
+		OverrideFieldPathFromAlias: false,
+	}
+}
+
+func (p *entitiesPlanner) ConfigureFetch() resolve.FetchConfiguration {
+	return resolve.FetchConfiguration{
+		Input:      `{"` + representationsArgumentName + `":{{ .arguments.` + representationsArgumentName + ` }}}`,
+		DataSource: &entitiesSource{resolvers: p.resolvers},
+		PostProcessing: resolve.PostProcessingConfiguration{
+			SelectResponseDataPath:   []string{"data"},
+			SelectResponseErrorsPath: []string{"errors"},
+		},
+	}
+}
+
+func (p *entitiesPlanner) ConfigureSubscription() plan.SubscriptionConfiguration {
+	// the _entities DataSourcePlanner doesn't have subscriptions
+	return plan.SubscriptionConfiguration{}
+}
+
+// entitiesSource resolves the representations of the input with the entity resolvers of their type names
+// the response contains an entity or null for each representation and an error for each failed representation
+type entitiesSource struct {
+	resolvers map[string]EntityResolver
+}
+
+type entityError struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path"`
+}
+
+func (s *entitiesSource) Load(ctx context.Context, input []byte, w io.Writer) error {
+	representations, dataType, _, err := jsonparser.Get(input, representationsArgumentName)
+	if err != nil || dataType != jsonparser.Array {
+		return errors.New("subgraph: the representations of _entities have to be a list")
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(`{"data":{"` + EntitiesFieldName + `":[`)
+	var entityErrors []entityError
+	index := 0
+	_, err = jsonparser.ArrayEach(representations, func(value []byte, _ jsonparser.ValueType, _ int, _ error) {
+		if index > 0 {
+			buf.WriteByte(',')
+		}
+		entity, err := s.resolve(ctx, value)
+		if err != nil {
+			entityErrors = append(entityErrors, entityError{
+				Message: err.Error(),
+				Path:    []any{EntitiesFieldName, index},
+			})
+			entity = null
+		}
+		buf.Write(entity)
+		index++
+	})
+	if err != nil {
+		return err
+	}
+	buf.WriteString(`]}`)
+
+	if len(entityErrors) > 0 {
+		errorsJson, err := json.Marshal(entityErrors)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(`,"errors":`)
+		buf.Write(errorsJson)
+	}
+	buf.WriteByte('}')
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// resolve returns the json object of the entity of the representation
+func (s *entitiesSource) resolve(ctx context.Context, representation []byte) ([]byte, error) {
+	typeName, err := jsonparser.GetString(representation, "__typename")
+	if err != nil {
+		return nil, errors.New("the representation has no __typename")
+	}
+	resolver, ok := s.resolvers[typeName]
+	if !ok {
+		return nil, fmt.Errorf("no entity resolver for type %s", typeName)
+	}
+
+	entity, err := resolver(ctx, Representation{TypeName: typeName, Data: representation})
+	if err != nil {
+		return nil, err
+	}
+	if entity == nil {
+		return null, nil
+	}
+	entityJson, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(entityJson, null) {
+		return null, nil
+	}
+	if entityJson[0] != '{' {
+		return nil, fmt.Errorf("the entity of type %s is not an object", typeName)
+	}
+
+	// the __typename selects the member of the _Entity union
+	if _, dataType, _, _ := jsonparser.Get(entityJson, "__typename"); dataType != jsonparser.NotExist {
+		return entityJson, nil
+	}
+	typeNameJson, _ := json.Marshal(typeName)
+	withTypeName := append([]byte(`{"__typename":`), typeNameJson...)
+	if len(entityJson) > 2 {
+		withTypeName = append(withTypeName, ',')
+	}
+	return append(withTypeName, entityJson[1:]...), nil
+}
+
+// Interface Guards
+var (
+	_ plan.PlannerFactory    = (*serviceFactory)(nil)
+	_ plan.DataSourcePlanner = (*servicePlanner)(nil)
+	_ resolve.DataSource     = (*serviceSource)(nil)
+	_ plan.PlannerFactory    = (*entitiesFactory)(nil)
+	_ plan.DataSourcePlanner = (*entitiesPlanner)(nil)
+	_ resolve.DataSource     = (*entitiesSource)(nil)
+)
@@ -0,0 +1,240 @@
+// Package subgraph serves the fields which are added to a subgraph by the federation specification,
+// so the ExecutionEngineV2 can be used to build subgraphs and not only gateways.
+//
+//	type Query {
+//		_service: _Service!
+//		_entities(representations: [_Any!]!): [_Entity]!
+//	}
+//
+// The _service field returns the SDL of the subgraph. The _entities field dispatches each representation
+// by its __typename to the EntityResolver of the entity, the results are returned in the order of the representations.
+// All other fields of the schema are resolved by the datasources added to the engine configuration.
+package subgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astnormalization"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/federation"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+const (
+	ServiceFieldName  = "_service"
+	EntitiesFieldName = "_entities"
+
+	entityUnionTypeName         = "_Entity"
+	serviceTypeName             = "_Service"
+	representationsArgumentName = "representations"
+)
+
+// EntityResolver resolves an entity of the subgraph by its representation.
+// The entity is marshalled to a json object, the __typename of the representation is added if it's missing.
+// A nil entity resolves to null, an error resolves to null with an error at the path of the entity.
+type EntityResolver func(ctx context.Context, representation Representation) (entity any, err error)
+
+// Representation is sent by the gateway to load an entity, it contains the __typename and the fields of a @key
+// as well as the fields required by @requires
+type Representation struct {
+	TypeName string
+	// Data is the json object of the representation
+	Data json.RawMessage
+}
+
+// Unmarshal decodes the representation into v, e.g. a struct with the key fields of the entity
+func (r Representation) Unmarshal(v any) error {
+	return json.Unmarshal(r.Data, v)
+}
+
+type Configuration struct {
+	// SDL of the subgraph, it's returned by _service.sdl
+	SDL string
+	// EntityResolvers resolve the entities of the subgraph by their type name
+	EntityResolvers map[string]EntityResolver
+}
+
+// Subgraph builds the schema and the datasources of a subgraph
+type Subgraph struct {
+	config     Configuration
+	schema     *graphql.Schema
+	definition *ast.Document
+}
+
+// New creates a Subgraph for the SDL, each entity resolver has to resolve a type with a @key directive
+func New(config Configuration) (*Subgraph, error) {
+	federationSchema, err := buildFederationSchema(config.SDL)
+	if err != nil {
+		return nil, fmt.Errorf("build federation schema: %w", err)
+	}
+
+	definition, report := astparser.ParseGraphqlDocumentString(federationSchema)
+	if report.HasErrors() {
+		return nil, fmt.Errorf("parse federation schema: %w", report)
+	}
+
+	entities := entityTypeNames(&definition)
+	for typeName := range config.EntityResolvers {
+		if _, ok := entities[typeName]; !ok {
+			return nil, fmt.Errorf("entity resolver for %s: the type is not an entity of the subgraph", typeName)
+		}
+	}
+
+	schema, err := graphql.NewSchemaFromString(federationSchema)
+	if err != nil {
+		return nil, fmt.Errorf("parse schema from string: %w", err)
+	}
+
+	return &Subgraph{
+		config:     config,
+		schema:     schema,
+		definition: &definition,
+	}, nil
+}
+
+// buildFederationSchema adds the federation fields and types to the SDL,
+// type extensions without a definition in the subgraph, e.g. of entities owned by other subgraphs, are turned into definitions
+func buildFederationSchema(sdl string) (string, error) {
+	doc, report := astparser.ParseGraphqlDocumentString(sdl)
+	if report.HasErrors() {
+		return "", report
+	}
+	astnormalization.NormalizeDefinition(&doc, &report)
+	if report.HasErrors() {
+		return "", report
+	}
+	baseSchema, err := astprinter.PrintString(&doc, nil)
+	if err != nil {
+		return "", err
+	}
+	return federation.BuildFederationSchema(baseSchema, sdl)
+}
+
+func entityTypeNames(definition *ast.Document) map[string]struct{} {
+	entities := map[string]struct{}{}
+	node, ok := definition.Index.FirstNodeByNameStr(entityUnionTypeName)
+	if !ok || node.Kind != ast.NodeKindUnionTypeDefinition {
+		return entities
+	}
+	for _, ref := range definition.UnionTypeDefinitions[node.Ref].UnionMemberTypes.Refs {
+		entities[definition.TypeNameString(ref)] = struct{}{}
+	}
+	return entities
+}
+
+// Schema returns the schema of the subgraph including the federation fields and types
+func (s *Subgraph) Schema() *graphql.Schema {
+	return s.schema
+}
+
+// DataSourceConfigurations returns the datasources resolving _service and _entities,
+// the _entities datasource resolves all fields of the entities from the results of the entity resolvers
+func (s *Subgraph) DataSourceConfigurations() []plan.DataSourceConfiguration {
+	queryTypeName := s.schema.QueryTypeName()
+	dataSources := []plan.DataSourceConfiguration{
+		{
+			ID: ServiceFieldName,
+			RootNodes: plan.TypeFields{
+				{TypeName: queryTypeName, FieldNames: []string{ServiceFieldName}},
+			},
+			ChildNodes: plan.TypeFields{
+				{TypeName: serviceTypeName, FieldNames: []string{"sdl"}},
+			},
+			Custom:  configJSON(serviceConfiguration{SDL: s.config.SDL}),
+			Factory: &serviceFactory{},
+		},
+	}
+	entities := entityTypeNames(s.definition)
+	if len(entities) == 0 {
+		return dataSources
+	}
+	typeNames := make([]string, 0, len(entities))
+	for typeName := range entities {
+		typeNames = append(typeNames, typeName)
+	}
+	slices.Sort(typeNames)
+	return append(dataSources, plan.DataSourceConfiguration{
+		ID: EntitiesFieldName,
+		RootNodes: plan.TypeFields{
+			{TypeName: queryTypeName, FieldNames: []string{EntitiesFieldName}},
+		},
+		ChildNodes: s.entityChildNodes(queryTypeName),
+		Custom:     configJSON(entitiesConfiguration{TypeNames: typeNames}),
+		Factory: &entitiesFactory{
+			definition: s.definition,
+			resolvers:  s.config.EntityResolvers,
+		},
+	})
+}
+
+func configJSON(config any) json.RawMessage {
+	out, _ := json.Marshal(config)
+	return out
+}
+
+// entityChildNodes returns the fields of all object and interface types except the root operation types,
+// the entity resolvers return whole entities, so nested objects are resolved from the same result
+func (s *Subgraph) entityChildNodes(queryTypeName string) plan.TypeFields {
+	rootTypeNames := map[string]struct{}{
+		queryTypeName:                   {},
+		s.schema.MutationTypeName():     {},
+		s.schema.SubscriptionTypeName(): {},
+		serviceTypeName:                 {},
+	}
+
+	childNodes := plan.TypeFields{
+		{TypeName: entityUnionTypeName, FieldNames: []string{"__typename"}},
+	}
+	addFields := func(typeName string, fieldRefs []int) {
+		if _, ok := rootTypeNames[typeName]; ok || strings.HasPrefix(typeName, "__") {
+			return
+		}
+		fieldNames := make([]string, 0, len(fieldRefs))
+		for _, ref := range fieldRefs {
+			fieldNames = append(fieldNames, s.definition.FieldDefinitionNameString(ref))
+		}
+		childNodes = append(childNodes, plan.TypeField{TypeName: typeName, FieldNames: fieldNames})
+	}
+	for i := range s.definition.ObjectTypeDefinitions {
+		addFields(s.definition.ObjectTypeDefinitionNameString(i), s.definition.ObjectTypeDefinitions[i].FieldsDefinition.Refs)
+	}
+	for i := range s.definition.InterfaceTypeDefinitions {
+		addFields(s.definition.InterfaceTypeDefinitionNameString(i), s.definition.InterfaceTypeDefinitions[i].FieldsDefinition.Refs)
+	}
+	return childNodes
+}
+
+// FieldConfigurations returns the configuration of the representations argument of _entities
+func (s *Subgraph) FieldConfigurations() plan.FieldConfigurations {
+	return plan.FieldConfigurations{
+		{
+			TypeName:  s.schema.QueryTypeName(),
+			FieldName: EntitiesFieldName,
+			Arguments: plan.ArgumentsConfigurations{
+				{
+					Name:         representationsArgumentName,
+					SourceType:   plan.FieldArgumentSource,
+					RenderConfig: plan.RenderArgumentAsJSONValue,
+				},
+			},
+		},
+	}
+}
+
+// EngineV2Configuration returns an engine configuration for the schema of the subgraph with the federation datasources,
+// the datasources resolving the fields of the subgraph have to be added with AddDataSource.
+// The errors of the entity resolvers are propagated, so the gateway receives them with the path of the entity.
+func (s *Subgraph) EngineV2Configuration() graphql.EngineV2Configuration {
+	conf := graphql.NewEngineV2Configuration(s.schema)
+	conf.SetDataSources(s.DataSourceConfigurations())
+	conf.SetFieldConfigurations(s.FieldConfigurations())
+	conf.SetPropagateSubgraphErrors(true)
+	return conf
+}
@@ -0,0 +1,159 @@
+package subgraph
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/staticdatasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+const reviewsSDL = `extend type Query { latestReview: Review }
+type Review @key(fields: "id") { id: ID! body: String! author: User }
+extend type User @key(fields: "id") { id: ID! @external reviews: [Review] }
+`
+
+type review struct {
+	ID   string `json:"id"`
+	Body string `json:"body"`
+}
+
+func TestSubgraph(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reviews := map[string]review{"r1": {ID: "r1", Body: "A highly effective form of birth control."}}
+	sg, err := New(Configuration{
+		SDL: reviewsSDL,
+		EntityResolvers: map[string]EntityResolver{
+			"Review": func(ctx context.Context, representation Representation) (any, error) {
+				var key struct {
+					ID string `json:"id"`
+				}
+				if err := representation.Unmarshal(&key); err != nil {
+					return nil, err
+				}
+				if r, ok := reviews[key.ID]; ok {
+					return r, nil
+				}
+				return nil, nil
+			},
+			"User": func(ctx context.Context, representation Representation) (any, error) {
+				return nil, errors.New("user reviews are unavailable")
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	engineConf := sg.EngineV2Configuration()
+	engineConf.AddDataSource(plan.DataSourceConfiguration{
+		ID:        "latestReview",
+		RootNodes: plan.TypeFields{{TypeName: "Query", FieldNames: []string{"latestReview"}}},
+		ChildNodes: plan.TypeFields{
+			{TypeName: "Review", FieldNames: []string{"id", "body"}},
+		},
+		Factory: &staticdatasource.Factory{},
+		Custom: staticdatasource.ConfigJSON(staticdatasource.Configuration{
+			Data: `{"latestReview":{"id":"r1","body":"A highly effective form of birth control."}}`,
+		}),
+	})
+	engine, err := graphql.NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+	require.NoError(t, err)
+
+	execute := func(t *testing.T, request graphql.Request) string {
+		t.Helper()
+		resultWriter := graphql.NewEngineResultWriter()
+		require.NoError(t, engine.Execute(ctx, &request, &resultWriter))
+		return resultWriter.String()
+	}
+
+	t.Run("should serve the sdl of the subgraph", func(t *testing.T) {
+		response := execute(t, graphql.Request{Query: `{ _service { sdl } }`})
+		assert.Equal(t, `{"data":{"_service":{"sdl":"extend type Query { latestReview: Review }\ntype Review @key(fields: \"id\") { id: ID! body: String! author: User }\nextend type User @key(fields: \"id\") { id: ID! @external reviews: [Review] }\n"}}}`, response)
+	})
+
+	t.Run("should resolve the entities in the order of the representations", func(t *testing.T) {
+		response := execute(t, graphql.Request{
+			Query:     `query ($representations: [_Any!]!) { _entities(representations: $representations) { __typename ... on Review { id body } } }`,
+			Variables: []byte(`{"representations":[{"__typename":"Review","id":"r1"},{"__typename":"Review","id":"unknown"}]}`),
+		})
+		assert.Equal(t, `{"data":{"_entities":[{"__typename":"Review","id":"r1","body":"A highly effective form of birth control."},null]}}`, response)
+	})
+
+	t.Run("should resolve representations inlined in the query", func(t *testing.T) {
+		response := execute(t, graphql.Request{
+			Query: `{ _entities(representations: [{__typename: "Review", id: "r1"}]) { ... on Review { body } } }`,
+		})
+		assert.Equal(t, `{"data":{"_entities":[{"body":"A highly effective form of birth control."}]}}`, response)
+	})
+
+	t.Run("should return the errors at the path of the entities", func(t *testing.T) {
+		response := execute(t, graphql.Request{
+			Query:     `query ($representations: [_Any!]!) { _entities(representations: $representations) { ... on Review { id } ... on User { reviews { id } } } }`,
+			Variables: []byte(`{"representations":[{"__typename":"Review","id":"r1"},{"__typename":"User","id":"u1"},{"__typename":"Product","upc":"1"}]}`),
+		})
+		assert.Equal(t, `{"errors":[{"message":"Failed to fetch from Subgraph at path 'query'.","extensions":{"errors":[{"message":"user reviews are unavailable","path":["_entities",1]},{"message":"no entity resolver for type Product","path":["_entities",2]}]}}],"data":{"_entities":[{"id":"r1"},null,null]}}`, response)
+	})
+
+	t.Run("should resolve the other fields with the datasources of the engine", func(t *testing.T) {
+		response := execute(t, graphql.Request{Query: `{ latestReview { id body } }`})
+		assert.Equal(t, `{"data":{"latestReview":{"id":"r1","body":"A highly effective form of birth control."}}}`, response)
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("should reject a resolver of a type without @key", func(t *testing.T) {
+		_, err := New(Configuration{
+			SDL: `type Query { hello: String } type Greeting { text: String }`,
+			EntityResolvers: map[string]EntityResolver{
+				"Greeting": func(ctx context.Context, representation Representation) (any, error) {
+					return nil, nil
+				},
+			},
+		})
+		assert.EqualError(t, err, "entity resolver for Greeting: the type is not an entity of the subgraph")
+	})
+
+	t.Run("should serve _service without entities", func(t *testing.T) {
+		sg, err := New(Configuration{SDL: `type Query { hello: String }`})
+		require.NoError(t, err)
+
+		dataSources := sg.DataSourceConfigurations()
+		require.Len(t, dataSources, 1)
+		assert.Equal(t, ServiceFieldName, dataSources[0].ID)
+	})
+}
+
+func TestEntitiesSource_resolve(t *testing.T) {
+	source := &entitiesSource{resolvers: map[string]EntityResolver{
+		"User": func(ctx context.Context, representation Representation) (any, error) {
+			return map[string]any{"id": "1"}, nil
+		},
+		"Empty": func(ctx context.Context, representation Representation) (any, error) {
+			return struct{}{}, nil
+		},
+		"Scalar": func(ctx context.Context, representation Representation) (any, error) {
+			return "1", nil
+		},
+	}}
+
+	entity, err := source.resolve(context.Background(), []byte(`{"__typename":"User","id":"1"}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"__typename":"User","id":"1"}`, string(entity))
+
+	entity, err = source.resolve(context.Background(), []byte(`{"__typename":"Empty"}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"__typename":"Empty"}`, string(entity))
+
+	_, err = source.resolve(context.Background(), []byte(`{"__typename":"Scalar"}`))
+	assert.EqualError(t, err, "the entity of type Scalar is not an object")
+
+	_, err = source.resolve(context.Background(), []byte(`{"id":"1"}`))
+	assert.EqualError(t, err, "the representation has no __typename")
+}
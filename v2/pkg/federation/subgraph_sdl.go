@@ -0,0 +1,175 @@
+package federation
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+)
+
+// DefaultFederationVersion is the version of the federation specification linked by PrintSubgraphSDL
+const DefaultFederationVersion = "v2.3"
+
+// federationV2Directives are the directives of the federation specification, in the order they are imported
+var federationV2Directives = []string{
+	"key", "requires", "provides", "external", "extends", "shareable",
+	"inaccessible", "tag", "override", "interfaceObject", "composeDirective",
+}
+
+// federationDefinitions are the definitions which are added to a subgraph by the federation specification,
+// they are not part of the SDL of the subgraph
+var federationDefinitions = map[string]struct{}{
+	"_Any": {}, "_FieldSet": {}, "FieldSet": {}, "federation__FieldSet": {}, "_Service": {}, "_Entity": {},
+	"link": {}, "link__Import": {}, "link__Purpose": {},
+}
+
+// federationFields are the root fields which are added to a subgraph by the federation specification
+var federationFields = map[string]struct{}{
+	"_service": {}, "_entities": {},
+}
+
+// SubgraphSDLOptions configures PrintSubgraphSDL
+type SubgraphSDLOptions struct {
+	// FederationVersion is the linked version of the federation specification, defaults to DefaultFederationVersion
+	FederationVersion string
+	// ComposeDirectives are the custom directives which are kept in the supergraph by @composeDirective
+	ComposeDirectives []ComposeDirective
+}
+
+// ComposeDirective is a custom directive of a subgraph which is composed into the supergraph
+type ComposeDirective struct {
+	// Name of the directive without @
+	Name string
+	// SpecURL is the url of the specification defining the directive, e.g. "https://example.com/cache/v1.0",
+	// composition requires the directive to be imported by a @link to its specification
+	SpecURL string
+}
+
+// PrintSubgraphSDL prints the SDL of a subgraph for Federation v2 composition.
+// The federation directives used in the schema are imported by @link and the definitions added by the federation specification,
+// e.g. _Service and _entities, are removed, so a federation v1 schema of a subgraph can be served as v2 SDL.
+func PrintSubgraphSDL(schema string, options SubgraphSDLOptions) (string, error) {
+	doc, report := astparser.ParseGraphqlDocumentString(schema)
+	if report.HasErrors() {
+		return "", report
+	}
+
+	for i := range doc.SchemaExtensions {
+		for _, directiveRef := range doc.SchemaExtensions[i].Directives.Refs {
+			if doc.DirectiveNameString(directiveRef) == "link" {
+				return "", errors.New("the schema already links specifications")
+			}
+		}
+	}
+
+	removeFederationDefinitions(&doc)
+
+	sdl, err := astprinter.PrintStringIndent(&doc, nil, "  ")
+	if err != nil {
+		return "", err
+	}
+	return subgraphSchemaExtension(&doc, options) + "\n\n" + sdl, nil
+}
+
+func removeFederationDefinitions(doc *ast.Document) {
+	rootNodes := doc.RootNodes[:0]
+	for _, node := range doc.RootNodes {
+		name := doc.NodeNameString(node)
+		switch node.Kind {
+		case ast.NodeKindDirectiveDefinition:
+			if _, ok := federationDefinitions[name]; ok || isFederationDirective(name) {
+				continue
+			}
+		case ast.NodeKindScalarTypeDefinition, ast.NodeKindObjectTypeDefinition, ast.NodeKindUnionTypeDefinition, ast.NodeKindEnumTypeDefinition:
+			if _, ok := federationDefinitions[name]; ok {
+				continue
+			}
+		}
+		rootNodes = append(rootNodes, node)
+	}
+	doc.RootNodes = rootNodes
+
+	for i := range doc.ObjectTypeDefinitions {
+		doc.RemoveFieldDefinitionsFromObjectTypeDefinition(federationFieldRefs(doc, doc.ObjectTypeDefinitions[i].FieldsDefinition.Refs), i)
+	}
+	for i := range doc.ObjectTypeExtensions {
+		remove := federationFieldRefs(doc, doc.ObjectTypeExtensions[i].FieldsDefinition.Refs)
+		if len(remove) == 0 {
+			continue
+		}
+		refs := doc.ObjectTypeExtensions[i].FieldsDefinition.Refs[:0]
+		for _, ref := range doc.ObjectTypeExtensions[i].FieldsDefinition.Refs {
+			if !containsRef(remove, ref) {
+				refs = append(refs, ref)
+			}
+		}
+		doc.ObjectTypeExtensions[i].FieldsDefinition.Refs = refs
+		doc.ObjectTypeExtensions[i].HasFieldDefinitions = len(refs) > 0
+	}
+}
+
+func federationFieldRefs(doc *ast.Document, fieldRefs []int) (remove []int) {
+	for _, ref := range fieldRefs {
+		if _, ok := federationFields[doc.FieldDefinitionNameString(ref)]; ok {
+			remove = append(remove, ref)
+		}
+	}
+	return remove
+}
+
+func containsRef(refs []int, ref int) bool {
+	for _, r := range refs {
+		if r == ref {
+			return true
+		}
+	}
+	return false
+}
+
+func isFederationDirective(name string) bool {
+	for _, directive := range federationV2Directives {
+		if directive == name {
+			return true
+		}
+	}
+	return false
+}
+
+// subgraphSchemaExtension prints the schema extension which links the federation specification
+// with the used federation directives and the specifications of the composed directives
+func subgraphSchemaExtension(doc *ast.Document, options SubgraphSDLOptions) string {
+	used := make(map[string]struct{}, len(doc.Directives))
+	for i := range doc.Directives {
+		used[doc.DirectiveNameString(i)] = struct{}{}
+	}
+	if len(options.ComposeDirectives) > 0 {
+		used["composeDirective"] = struct{}{}
+	}
+
+	imports := make([]string, 0, len(federationV2Directives))
+	for _, directive := range federationV2Directives {
+		if _, ok := used[directive]; ok {
+			imports = append(imports, strconv.Quote("@"+directive))
+		}
+	}
+
+	version := options.FederationVersion
+	if version == "" {
+		version = DefaultFederationVersion
+	}
+
+	out := &strings.Builder{}
+	out.WriteString("extend schema\n")
+	fmt.Fprintf(out, "    @link(url: %q, import: [%s])", "https://specs.apollo.dev/federation/"+version, strings.Join(imports, ", "))
+	for _, directive := range options.ComposeDirectives {
+		fmt.Fprintf(out, "\n    @link(url: %q, import: [%q])", directive.SpecURL, "@"+directive.Name)
+	}
+	for _, directive := range options.ComposeDirectives {
+		fmt.Fprintf(out, "\n    @composeDirective(name: %q)", "@"+directive.Name)
+	}
+	return out.String()
+}
@@ -0,0 +1,77 @@
+package federation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintSubgraphSDL(t *testing.T) {
+	t.Run("should link the used federation directives", func(t *testing.T) {
+		schema := `
+			scalar _Any
+			scalar _FieldSet
+			type _Service { sdl: String }
+			union _Entity = Product
+			directive @key(fields: _FieldSet!) on OBJECT | INTERFACE
+			directive @shareable on OBJECT | FIELD_DEFINITION
+			directive @cache(maxAge: Int!) on FIELD_DEFINITION
+
+			type Query {
+				topProducts: [Product] @cache(maxAge: 60)
+				_service: _Service!
+				_entities(representations: [_Any!]!): [_Entity]!
+			}
+
+			type Product @key(fields: "upc") {
+				upc: String!
+				name: String! @shareable
+				internalCode: String @inaccessible @tag(name: "internal")
+			}
+		`
+
+		actual, err := PrintSubgraphSDL(schema, SubgraphSDLOptions{
+			ComposeDirectives: []ComposeDirective{{Name: "cache", SpecURL: "https://example.com/cache/v1.0"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, `extend schema
+    @link(url: "https://specs.apollo.dev/federation/v2.3", import: ["@key", "@shareable", "@inaccessible", "@tag", "@composeDirective"])
+    @link(url: "https://example.com/cache/v1.0", import: ["@cache"])
+    @composeDirective(name: "@cache")
+
+directive @cache(
+    maxAge: Int!
+) on FIELD_DEFINITION
+
+type Query {
+    topProducts: [Product] @cache(maxAge: 60)
+}
+
+type Product @key(fields: "upc") {
+    upc: String!
+    name: String! @shareable
+    internalCode: String @inaccessible @tag(name: "internal")
+}`, actual)
+	})
+
+	t.Run("should print the linked version", func(t *testing.T) {
+		actual, err := PrintSubgraphSDL(`extend type Query { me: User } type User @key(fields: "id") { id: ID! }`, SubgraphSDLOptions{FederationVersion: "v2.0"})
+		require.NoError(t, err)
+		assert.Equal(t, `extend schema
+    @link(url: "https://specs.apollo.dev/federation/v2.0", import: ["@key"])
+
+extend type Query {
+    me: User
+}
+
+type User @key(fields: "id") {
+    id: ID!
+}`, actual)
+	})
+
+	t.Run("should reject schemas which already link specifications", func(t *testing.T) {
+		_, err := PrintSubgraphSDL(`extend schema @link(url: "https://specs.apollo.dev/federation/v2.0") type Query { a: String }`, SubgraphSDLOptions{})
+		assert.EqualError(t, err, "the schema already links specifications")
+	})
+}
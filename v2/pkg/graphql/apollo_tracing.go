@@ -0,0 +1,45 @@
+package graphql
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+// HeaderApolloFederationIncludeTrace is the request header asking for tracing data in the response extensions,
+// Apollo gateways send it with the value ApolloTracingHeaderValueFTV1 to collect the federated traces of the subgraphs
+const HeaderApolloFederationIncludeTrace = "Apollo-Federation-Include-Trace"
+
+const (
+	// ApolloTracingHeaderValueFTV1 asks for the federated trace in the "ftv1" extension
+	ApolloTracingHeaderValueFTV1 = "ftv1"
+	// ApolloTracingHeaderValueLegacy asks for the Apollo Tracing format in the "tracing" extension
+	ApolloTracingHeaderValueLegacy = "tracing"
+)
+
+// ApolloTracingFormatFromHeader returns the format of the tracing data asked for with the HeaderApolloFederationIncludeTrace,
+// it returns resolve.ApolloTracingFormatNone if the header is missing or has an unknown value
+func ApolloTracingFormatFromHeader(header http.Header) resolve.ApolloTracingFormat {
+	switch strings.ToLower(strings.TrimSpace(header.Get(HeaderApolloFederationIncludeTrace))) {
+	case ApolloTracingHeaderValueFTV1:
+		return resolve.ApolloTracingFormatFTV1
+	case ApolloTracingHeaderValueLegacy:
+		return resolve.ApolloTracingFormatLegacy
+	default:
+		return resolve.ApolloTracingFormatNone
+	}
+}
+
+// WithApolloTracing renders the timings of the fetches of the operation in the response extensions in the format,
+// it has no effect unless Apollo tracing is enabled with EngineV2Configuration.EnableApolloTracing
+func WithApolloTracing(format resolve.ApolloTracingFormat) ExecutionOptionsV2 {
+	return func(ctx *internalExecutionContext) {
+		ctx.apolloTracing = format
+	}
+}
+
+// WithApolloTracingFromHeader renders the tracing data in the format asked for with the HeaderApolloFederationIncludeTrace of the request
+func WithApolloTracingFromHeader(header http.Header) ExecutionOptionsV2 {
+	return WithApolloTracing(ApolloTracingFormatFromHeader(header))
+}
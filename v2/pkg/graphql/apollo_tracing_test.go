@@ -0,0 +1,107 @@
+package graphql
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/buger/jsonparser"
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/staticdatasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+func TestApolloTracingFormatFromHeader(t *testing.T) {
+	assert.Equal(t, resolve.ApolloTracingFormatFTV1, ApolloTracingFormatFromHeader(http.Header{"Apollo-Federation-Include-Trace": {"ftv1"}}))
+	assert.Equal(t, resolve.ApolloTracingFormatLegacy, ApolloTracingFormatFromHeader(http.Header{"Apollo-Federation-Include-Trace": {"Tracing"}}))
+	assert.Equal(t, resolve.ApolloTracingFormatNone, ApolloTracingFormatFromHeader(http.Header{"Apollo-Federation-Include-Trace": {"ftv2"}}))
+	assert.Equal(t, resolve.ApolloTracingFormatNone, ApolloTracingFormatFromHeader(http.Header{}))
+}
+
+func TestExecutionEngineV2_ApolloTracing(t *testing.T) {
+	schema, err := NewSchemaFromString(`type Query { hello: String }`)
+	require.NoError(t, err)
+
+	newEngine := func(t *testing.T, enable bool, encoder resolve.FederatedTraceEncoder) *ExecutionEngineV2 {
+		engineConfig := NewEngineV2Configuration(schema)
+		engineConfig.EnableApolloTracing(enable)
+		engineConfig.SetFederatedTraceEncoder(encoder)
+		engineConfig.SetDataSources([]plan.DataSourceConfiguration{
+			{
+				ID: "hello",
+				RootNodes: []plan.TypeField{
+					{TypeName: "Query", FieldNames: []string{"hello"}},
+				},
+				Factory: &staticdatasource.Factory{},
+				Custom: staticdatasource.ConfigJSON(staticdatasource.Configuration{
+					Data: `{"hello":"world"}`,
+				}),
+			},
+		})
+		engine, err := NewExecutionEngineV2(context.Background(), abstractlogger.NoopLogger, engineConfig)
+		require.NoError(t, err)
+		return engine
+	}
+
+	execute := func(t *testing.T, engine *ExecutionEngineV2, header http.Header) []byte {
+		resultWriter := NewEngineResultWriter()
+		err := engine.Execute(context.Background(), &Request{Query: `{ hello }`}, &resultWriter, WithApolloTracingFromHeader(header))
+		require.NoError(t, err)
+		return resultWriter.Bytes()
+	}
+
+	engine := newEngine(t, true, federatedTraceEncoderFunc(func(trace resolve.ApolloTrace) ([]byte, error) {
+		return []byte(trace.Resolvers[0].ParentType + "." + trace.Resolvers[0].FieldName), nil
+	}))
+
+	t.Run("should render the tracing extension", func(t *testing.T) {
+		out := execute(t, engine, http.Header{HeaderApolloFederationIncludeTrace: {ApolloTracingHeaderValueLegacy}})
+		data, _, _, err := jsonparser.Get(out, "data")
+		require.NoError(t, err)
+		assert.Equal(t, `{"hello":"world"}`, string(data))
+
+		version, err := jsonparser.GetInt(out, "extensions", "tracing", "version")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), version)
+		path, _, _, err := jsonparser.Get(out, "extensions", "tracing", "execution", "resolvers", "[0]", "path")
+		require.NoError(t, err)
+		assert.Equal(t, `["hello"]`, string(path))
+		parentType, err := jsonparser.GetString(out, "extensions", "tracing", "execution", "resolvers", "[0]", "parentType")
+		require.NoError(t, err)
+		assert.Equal(t, "Query", parentType)
+	})
+
+	t.Run("should render the ftv1 extension", func(t *testing.T) {
+		out := execute(t, engine, http.Header{HeaderApolloFederationIncludeTrace: {ApolloTracingHeaderValueFTV1}})
+		trace, err := jsonparser.GetString(out, "extensions", "ftv1")
+		require.NoError(t, err)
+		decoded, err := base64.StdEncoding.DecodeString(trace)
+		require.NoError(t, err)
+		assert.Equal(t, "Query.hello", string(decoded))
+	})
+
+	t.Run("should not render the ftv1 extension without an encoder", func(t *testing.T) {
+		out := execute(t, newEngine(t, true, nil), http.Header{HeaderApolloFederationIncludeTrace: {ApolloTracingHeaderValueFTV1}})
+		assert.Equal(t, `{"data":{"hello":"world"}}`, string(out))
+	})
+
+	t.Run("should not render tracing data without the header", func(t *testing.T) {
+		assert.Equal(t, `{"data":{"hello":"world"}}`, string(execute(t, engine, http.Header{})))
+	})
+
+	t.Run("should not render tracing data if it isn't enabled", func(t *testing.T) {
+		out := execute(t, newEngine(t, false, nil), http.Header{HeaderApolloFederationIncludeTrace: {ApolloTracingHeaderValueFTV1}})
+		assert.Equal(t, `{"data":{"hello":"world"}}`, string(out))
+	})
+}
+
+type federatedTraceEncoderFunc func(trace resolve.ApolloTrace) ([]byte, error)
+
+func (f federatedTraceEncoderFunc) EncodeFederatedTrace(trace resolve.ApolloTrace) ([]byte, error) {
+	return f(trace)
+}
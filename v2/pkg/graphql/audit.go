@@ -0,0 +1,88 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+)
+
+// AuditRecord describes the data accessed by an executed operation
+type AuditRecord struct {
+	Timestamp     time.Time `json:"timestamp"`
+	OperationName string    `json:"operationName,omitempty"`
+	OperationType string    `json:"operationType"`
+	// SchemaCoordinates are the accessed fields up to the configured depth, e.g. Query.user, User.email
+	SchemaCoordinates []string `json:"schemaCoordinates"`
+	// DataSources are the IDs of the data sources resolving the accessed fields
+	DataSources []string `json:"dataSources"`
+	// Identity is the auth identity set with WithAuditIdentity
+	Identity string `json:"identity,omitempty"`
+	// Error is set if the execution failed after planning
+	Error string `json:"error,omitempty"`
+}
+
+// AuditSink receives an audit record for every executed operation
+type AuditSink interface {
+	WriteAuditRecord(ctx context.Context, record AuditRecord)
+}
+
+type AuditSinkFunc func(ctx context.Context, record AuditRecord)
+
+func (f AuditSinkFunc) WriteAuditRecord(ctx context.Context, record AuditRecord) {
+	f(ctx, record)
+}
+
+// AuditConfiguration enables audit records for executed operations.
+// Operations which fail normalization, validation or planning don't access any data and are not recorded.
+type AuditConfiguration struct {
+	Sink AuditSink
+	// MaxDepth limits the depth of the fields recorded as schema coordinates, root fields have a depth of 1.
+	// All fields are recorded if MaxDepth is 0.
+	MaxDepth int
+}
+
+// WithAuditIdentity sets the auth identity of the operation, e.g. the subject of a token, for its audit record
+func WithAuditIdentity(identity string) ExecutionOptionsV2 {
+	return func(ctx *internalExecutionContext) {
+		ctx.auditIdentity = identity
+	}
+}
+
+// audit writes the audit record of an operation executed with the plan
+func (e *ExecutionEngineV2) audit(ctx context.Context, execContext *internalExecutionContext, operation *Request, executionPlan plan.Plan, err error) {
+	accessInfo := plan.GetAccessInfo(executionPlan, e.config.audit.MaxDepth)
+	record := AuditRecord{
+		Timestamp:         time.Now(),
+		OperationName:     operation.OperationName,
+		OperationType:     accessInfo.OperationType.Name(),
+		SchemaCoordinates: accessInfo.SchemaCoordinates,
+		DataSources:       accessInfo.DataSourceIDs,
+		Identity:          execContext.auditIdentity,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	e.config.audit.Sink.WriteAuditRecord(ctx, record)
+}
+
+// JSONAuditSink writes every audit record as a line of json
+type JSONAuditSink struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+func NewJSONAuditSink(w io.Writer) *JSONAuditSink {
+	return &JSONAuditSink{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+func (j *JSONAuditSink) WriteAuditRecord(_ context.Context, record AuditRecord) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_ = j.encoder.Encode(record)
+}
@@ -0,0 +1,107 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/staticdatasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+)
+
+func TestExecutionEngineV2_Audit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	schema, err := NewSchemaFromString(`
+		type Query { user: User }
+		type User { name: String address: Address }
+		type Address { street: String }
+	`)
+	require.NoError(t, err)
+
+	var records []AuditRecord
+	engineConf := NewEngineV2Configuration(schema)
+	engineConf.SetDataSources([]plan.DataSourceConfiguration{
+		{
+			ID: "users",
+			RootNodes: []plan.TypeField{
+				{TypeName: "Query", FieldNames: []string{"user"}},
+			},
+			ChildNodes: []plan.TypeField{
+				{TypeName: "User", FieldNames: []string{"name", "address"}},
+				{TypeName: "Address", FieldNames: []string{"street"}},
+			},
+			Factory: &staticdatasource.Factory{},
+			Custom: staticdatasource.ConfigJSON(staticdatasource.Configuration{
+				Data: `{"user":{"name":"Jens","address":{"street":"Main Street"}}}`,
+			}),
+		},
+	})
+	engineConf.SetAuditConfiguration(AuditConfiguration{
+		Sink: AuditSinkFunc(func(ctx context.Context, record AuditRecord) {
+			records = append(records, record)
+		}),
+		MaxDepth: 2,
+	})
+
+	engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+	require.NoError(t, err)
+
+	t.Run("should write a record for executed operations", func(t *testing.T) {
+		records = nil
+		operation := Request{OperationName: "User", Query: `query User { user { name address { street } } }`}
+		resultWriter := NewEngineResultWriter()
+		err := engine.Execute(ctx, &operation, &resultWriter, WithAuditIdentity("user:1"))
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"user":{"name":"Jens","address":{"street":"Main Street"}}}}`, resultWriter.String())
+
+		require.Len(t, records, 1)
+		assert.False(t, records[0].Timestamp.IsZero())
+		records[0].Timestamp = time.Time{}
+		assert.Equal(t, AuditRecord{
+			OperationName:     "User",
+			OperationType:     "query",
+			SchemaCoordinates: []string{"Query.user", "User.name", "User.address"},
+			DataSources:       []string{"users"},
+			Identity:          "user:1",
+		}, records[0])
+	})
+
+	t.Run("should not write a record for invalid operations", func(t *testing.T) {
+		records = nil
+		operation := Request{Query: `{ unknown }`}
+		resultWriter := NewEngineResultWriter()
+		err := engine.Execute(ctx, &operation, &resultWriter)
+		assert.Error(t, err)
+		assert.Len(t, records, 0)
+	})
+
+	t.Run("should not reuse the identity of previous executions", func(t *testing.T) {
+		records = nil
+		operation := Request{Query: `{ user { name } }`}
+		resultWriter := NewEngineResultWriter()
+		err := engine.Execute(ctx, &operation, &resultWriter)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Equal(t, "", records[0].Identity)
+	})
+}
+
+func TestJSONAuditSink(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sink := NewJSONAuditSink(buf)
+	sink.WriteAuditRecord(context.Background(), AuditRecord{
+		Timestamp:         time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		OperationType:     "query",
+		SchemaCoordinates: []string{"Query.user"},
+		DataSources:       []string{"users"},
+		Identity:          "user:1",
+	})
+	assert.Equal(t, `{"timestamp":"2024-01-01T00:00:00Z","operationType":"query","schemaCoordinates":["Query.user"],"dataSources":["users"],"identity":"user:1"}`+"\n", buf.String())
+}
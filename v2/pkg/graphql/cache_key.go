@@ -0,0 +1,133 @@
+package graphql
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/buger/jsonparser"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+)
+
+// CacheKeyOptions selects the parts of a request which are part of its cache key besides the operation
+type CacheKeyOptions struct {
+	// IgnoredVariables are the names of variables which don't change the response, e.g. a client tracking id
+	// All other variables are part of the key, including the variables extracted from literal arguments by the normalization
+	IgnoredVariables []string
+	// VaryByHeaders are the names of the request headers which change the response, e.g. Authorization or Accept-Language
+	VaryByHeaders []string
+	// ExtensionKeys are the names of the extensions which change the response
+	ExtensionKeys []string
+}
+
+// CacheKey derives a stable key of a request for CDN and edge caches
+// The key is built from the hash of the schema, the normalized operation as it is used for the plan cache,
+// the values of the variables and the values of the vary-by headers.
+// The request is normalized, so semantically equal operations result in the same key.
+func (e *ExecutionEngineV2) CacheKey(operation *Request, header http.Header, options CacheKeyOptions) (string, error) {
+	if err := e.normalizeForKey(operation); err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	var schemaHash [8]byte
+	binary.LittleEndian.PutUint64(schemaHash[:], e.config.schema.Hash())
+	_, _ = hash.Write(schemaHash[:])
+
+	if err := writeRequestKey(hash, &operation.document, &e.config.schema.document, operation.OperationName, operation.Extensions, options.ExtensionKeys); err != nil {
+		return "", err
+	}
+	if err := writeVariablesKey(hash, operation.Variables, options.IgnoredVariables); err != nil {
+		return "", err
+	}
+	writeHeadersKey(hash, header, options.VaryByHeaders)
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// OperationShape returns the shape of a request for logs and analytics, i.e. the operation without any values.
+// The request is normalized like for the plan cache, so semantically equal operations have the same shape,
+// the literals remaining after the normalization are replaced with a placeholder of their type and the values of the variables are dropped.
+func (e *ExecutionEngineV2) OperationShape(operation *Request) (string, error) {
+	if err := e.normalizeForKey(operation); err != nil {
+		return "", err
+	}
+	return astprinter.PrintStringShape(&operation.document, &e.config.schema.document)
+}
+
+// normalizeForKey normalizes the request with the options of the engine, if it isn't normalized yet
+func (e *ExecutionEngineV2) normalizeForKey(operation *Request) error {
+	if operation.IsNormalized() {
+		return nil
+	}
+	result, err := operation.normalize(e.config.schema, e.config.normalizationFlags, e.normalizationOptions()...)
+	if err != nil {
+		return err
+	}
+	if !result.Successful {
+		return result.Errors
+	}
+	return nil
+}
+
+// writeVariablesKey writes the canonical values of the variables sorted by name, the order of the variables doesn't change the key
+func writeVariablesKey(out io.Writer, variables []byte, ignoredVariables []string) error {
+	var names []string
+	if len(variables) != 0 {
+		err := jsonparser.ObjectEach(variables, func(key []byte, _ []byte, _ jsonparser.ValueType, _ int) error {
+			if !slices.Contains(ignoredVariables, string(key)) {
+				names = append(names, string(key))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value, err := canonicalExtensionValue(variables, name)
+		if err != nil {
+			return err
+		}
+		if _, err = io.WriteString(out, name); err != nil {
+			return err
+		}
+		if _, err = out.Write([]byte{0}); err != nil {
+			return err
+		}
+		if _, err = out.Write(value); err != nil {
+			return err
+		}
+		if _, err = out.Write([]byte{0}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeHeadersKey writes the values of the vary-by headers, header names are case-insensitive
+func writeHeadersKey(out io.Writer, header http.Header, varyByHeaders []string) {
+	names := make([]string, len(varyByHeaders))
+	for i := range varyByHeaders {
+		names[i] = http.CanonicalHeaderKey(varyByHeaders[i])
+	}
+	sort.Strings(names)
+	names = slices.Compact(names)
+
+	for _, name := range names {
+		_, _ = io.WriteString(out, name)
+		_, _ = out.Write([]byte{0})
+		for _, value := range header.Values(name) {
+			_, _ = io.WriteString(out, value)
+			_, _ = out.Write([]byte{0})
+		}
+		_, _ = out.Write([]byte{0})
+	}
+}
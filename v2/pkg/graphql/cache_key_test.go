@@ -0,0 +1,156 @@
+package graphql
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/staticdatasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+)
+
+func TestExecutionEngineV2_CacheKey(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	newEngine := func(t *testing.T, sdl string) *ExecutionEngineV2 {
+		schema, err := NewSchemaFromString(sdl)
+		require.NoError(t, err)
+		engineConf := NewEngineV2Configuration(schema)
+		engineConf.SetDataSources([]plan.DataSourceConfiguration{
+			{
+				RootNodes: []plan.TypeField{
+					{TypeName: "Query", FieldNames: []string{"product"}},
+				},
+				ChildNodes: []plan.TypeField{
+					{TypeName: "Product", FieldNames: []string{"name", "price"}},
+				},
+				Factory: &staticdatasource.Factory{},
+				Custom: staticdatasource.ConfigJSON(staticdatasource.Configuration{
+					Data: `{"product":{"name":"Table","price":100}}`,
+				}),
+			},
+		})
+		engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+		require.NoError(t, err)
+		return engine
+	}
+
+	engine := newEngine(t, `
+		type Query { product(id: ID!, locale: String): Product }
+		type Product { name: String price: Int }
+	`)
+
+	cacheKey := func(t *testing.T, operation Request, header http.Header, options CacheKeyOptions) string {
+		t.Helper()
+		key, err := engine.CacheKey(&operation, header, options)
+		require.NoError(t, err)
+		return key
+	}
+
+	options := CacheKeyOptions{
+		IgnoredVariables: []string{"traceId"},
+		VaryByHeaders:    []string{"accept-language"},
+	}
+	header := http.Header{"Accept-Language": {"de"}, "X-Request-Id": {"1"}}
+	key := cacheKey(t, Request{
+		Query:     `query ($id: ID!) { product(id: $id) { name price } }`,
+		Variables: []byte(`{"id":"1","traceId":"a"}`),
+	}, header, options)
+
+	t.Run("semantically equal requests have the same key", func(t *testing.T) {
+		assert.Equal(t, key, cacheKey(t, Request{
+			Query:     `query ($id: ID!) { ...ProductFields } fragment ProductFields on Query { product(id: $id) { name price } }`,
+			Variables: []byte(`{"traceId":"b", "id":"1"}`),
+		}, http.Header{"Accept-Language": {"de"}, "X-Request-Id": {"2"}}, options))
+	})
+
+	t.Run("variables change the key", func(t *testing.T) {
+		assert.NotEqual(t, key, cacheKey(t, Request{
+			Query:     `query ($id: ID!) { product(id: $id) { name price } }`,
+			Variables: []byte(`{"id":"2","traceId":"a"}`),
+		}, header, options))
+	})
+
+	t.Run("literal arguments change the key", func(t *testing.T) {
+		assert.NotEqual(t,
+			cacheKey(t, Request{Query: `{ product(id: "1") { name } }`}, header, options),
+			cacheKey(t, Request{Query: `{ product(id: "2") { name } }`}, header, options),
+		)
+	})
+
+	t.Run("vary-by headers change the key", func(t *testing.T) {
+		assert.NotEqual(t, key, cacheKey(t, Request{
+			Query:     `query ($id: ID!) { product(id: $id) { name price } }`,
+			Variables: []byte(`{"id":"1","traceId":"a"}`),
+		}, http.Header{"Accept-Language": {"en"}}, options))
+	})
+
+	t.Run("the schema changes the key", func(t *testing.T) {
+		other := newEngine(t, `
+			type Query { product(id: ID!, locale: String, currency: String): Product }
+			type Product { name: String price: Int }
+		`)
+		otherKey, err := other.CacheKey(&Request{
+			Query:     `query ($id: ID!) { product(id: $id) { name price } }`,
+			Variables: []byte(`{"id":"1","traceId":"a"}`),
+		}, header, options)
+		require.NoError(t, err)
+		assert.NotEqual(t, key, otherKey)
+	})
+
+	t.Run("invalid requests are rejected", func(t *testing.T) {
+		_, err := engine.CacheKey(&Request{Query: `{ product(id: "1") { name `}, header, options)
+		assert.Error(t, err)
+	})
+}
+
+func TestExecutionEngineV2_OperationShape(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	schema, err := NewSchemaFromString(`
+		type Query { product(id: ID!, locale: String): Product }
+		type Product { name: String price: Int }
+	`)
+	require.NoError(t, err)
+	engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, NewEngineV2Configuration(schema))
+	require.NoError(t, err)
+
+	operationShape := func(t *testing.T, operation Request) string {
+		t.Helper()
+		shape, err := engine.OperationShape(&operation)
+		require.NoError(t, err)
+		return shape
+	}
+
+	shape := operationShape(t, Request{
+		OperationName: "Product",
+		Query:         `query Product { product(id: "1", locale: "de") { name price } }`,
+	})
+	assert.Equal(t, `query Product($a: ID!, $b: String){product(id: $a, locale: $b){name price}}`, shape)
+
+	t.Run("operations which differ in their values have the same shape", func(t *testing.T) {
+		assert.Equal(t, shape, operationShape(t, Request{
+			OperationName: "Product",
+			Query:         `query Product { ...ProductFields } fragment ProductFields on Query { product(id: "2", locale: "en") { name price } }`,
+		}))
+	})
+
+	t.Run("values of variables are dropped", func(t *testing.T) {
+		assert.Equal(t, `query Product($id: ID!){product(id: $id){name}}`, operationShape(t, Request{
+			OperationName: "Product",
+			Query:         `query Product($id: ID!) { product(id: $id) { name } }`,
+			Variables:     []byte(`{"id":"secret"}`),
+		}))
+	})
+
+	t.Run("invalid requests are rejected", func(t *testing.T) {
+		_, err := engine.OperationShape(&Request{Query: `{ product(id: "1") { name `})
+		assert.Error(t, err)
+	})
+}
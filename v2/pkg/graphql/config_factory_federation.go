@@ -19,6 +19,7 @@ type federationEngineConfigFactoryOptions struct {
 	subscriptionClientFactory graphqlDataSource.GraphQLSubscriptionClientFactory
 	subscriptionType          SubscriptionType
 	customResolveMap          map[string]resolve.CustomResolve
+	contract                  federation.Contract
 }
 
 type FederationEngineConfigFactoryOption func(options *federationEngineConfigFactoryOptions)
@@ -29,6 +30,13 @@ func WithCustomResolveMap(customResolveMap map[string]resolve.CustomResolve) Fed
 	}
 }
 
+// WithFederationContract filters the merged schema by the @tag directives of its elements, see federation.Contract
+func WithFederationContract(contract federation.Contract) FederationEngineConfigFactoryOption {
+	return func(options *federationEngineConfigFactoryOptions) {
+		options.contract = contract
+	}
+}
+
 func WithFederationHttpClient(client *http.Client) FederationEngineConfigFactoryOption {
 	return func(options *federationEngineConfigFactoryOptions) {
 		options.httpClient = client
@@ -80,6 +88,7 @@ func NewFederationEngineConfigFactory(dataSourceConfigs []graphqlDataSource.Conf
 		subscriptionClientFactory: options.subscriptionClientFactory,
 		subscriptionType:          options.subscriptionType,
 		customResolveMap:          options.customResolveMap,
+		contract:                  options.contract,
 	}
 }
 
@@ -92,6 +101,7 @@ type FederationEngineConfigFactory struct {
 	subscriptionClientFactory graphqlDataSource.GraphQLSubscriptionClientFactory
 	subscriptionType          SubscriptionType
 	customResolveMap          map[string]resolve.CustomResolve
+	contract                  federation.Contract
 }
 
 func (f *FederationEngineConfigFactory) SetMergedSchemaFromString(mergedSchema string) (err error) {
@@ -107,6 +117,23 @@ func (f *FederationEngineConfigFactory) MergedSchema() (*Schema, error) {
 		return f.schema, nil
 	}
 
+	schema, err := f.publicSchema(f.contract)
+	if err != nil {
+		return nil, err
+	}
+	f.schema = schema
+
+	return f.schema, nil
+}
+
+// ContractSchema builds the public schema of the subgraphs filtered by the contract, e.g. to register it with
+// EngineV2Configuration.AddSchemaContract. The contract of the factory is not applied,
+// so the contract must not expose elements which are removed from the merged schema.
+func (f *FederationEngineConfigFactory) ContractSchema(contract federation.Contract) (*Schema, error) {
+	return f.publicSchema(contract)
+}
+
+func (f *FederationEngineConfigFactory) publicSchema(contract federation.Contract) (*Schema, error) {
 	SDLs := make([]string, len(f.dataSourceConfigs))
 	for i := range f.dataSourceConfigs {
 		SDLs[i] = f.dataSourceConfigs[i].Federation.ServiceSDL
@@ -117,11 +144,17 @@ func (f *FederationEngineConfigFactory) MergedSchema() (*Schema, error) {
 		return nil, fmt.Errorf("build base schema: %w", err)
 	}
 
-	if f.schema, err = NewSchemaFromString(rawBaseSchema); err != nil {
+	// the merged schema is the public schema, so elements marked with @inaccessible or filtered by the contract are removed
+	if rawBaseSchema, err = federation.BuildPublicSchema(rawBaseSchema, contract); err != nil {
+		return nil, fmt.Errorf("build public schema: %w", err)
+	}
+
+	schema, err := NewSchemaFromString(rawBaseSchema)
+	if err != nil {
 		return nil, fmt.Errorf("parse schema from string: %v", err)
 	}
 
-	return f.schema, nil
+	return schema, nil
 }
 
 func (f *FederationEngineConfigFactory) EngineV2Configuration() (conf EngineV2Configuration, err error) {
@@ -11,6 +11,7 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
 	graphqlDataSource "github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/graphql_datasource"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/federation"
 )
 
 func TestEngineConfigV2Factory_EngineV2Configuration(t *testing.T) {
@@ -289,3 +290,60 @@ const (
 	}
 `
 )
+
+func TestFederationEngineConfigFactory_Contract(t *testing.T) {
+	dataSourceConfigs := []graphqlDataSource.Configuration{
+		{
+			Fetch: graphqlDataSource.FetchConfiguration{URL: "http://user.service"},
+			Federation: graphqlDataSource.FederationConfiguration{
+				Enabled: true,
+				ServiceSDL: `
+					directive @tag(name: String!) repeatable on FIELD_DEFINITION | OBJECT
+					directive @inaccessible on FIELD_DEFINITION | OBJECT
+					extend type Query { me: User users: [User] @tag(name: "admin") }
+					type User @key(fields: "id") { id: ID! username: String! passwordHash: String @inaccessible }`,
+			},
+		},
+	}
+
+	publicFactory := NewFederationEngineConfigFactory(dataSourceConfigs)
+	publicSchema, err := publicFactory.MergedSchema()
+	require.NoError(t, err)
+	assert.Contains(t, string(publicSchema.Input()), "users: [User]")
+	assert.NotContains(t, string(publicSchema.Input()), "passwordHash")
+	assert.NotContains(t, string(publicSchema.Input()), "@tag")
+
+	contractFactory := NewFederationEngineConfigFactory(dataSourceConfigs, WithFederationContract(federation.Contract{ExcludeTags: []string{"admin"}}))
+	contractSchema, err := contractFactory.MergedSchema()
+	require.NoError(t, err)
+	assert.Contains(t, string(contractSchema.Input()), "me: User")
+	assert.NotContains(t, string(contractSchema.Input()), "users")
+}
+
+func TestFederationEngineConfigFactory_ContractSchema(t *testing.T) {
+	factory := NewFederationEngineConfigFactory([]graphqlDataSource.Configuration{
+		{
+			Fetch: graphqlDataSource.FetchConfiguration{URL: "http://user.service"},
+			Federation: graphqlDataSource.FederationConfiguration{
+				Enabled: true,
+				ServiceSDL: `
+					directive @tag(name: String!) repeatable on FIELD_DEFINITION | OBJECT
+					extend type Query { me: User users: [User] @tag(name: "admin") }
+					type User @key(fields: "id") { id: ID! username: String! }`,
+			},
+		},
+	})
+
+	mergedSchema, err := factory.MergedSchema()
+	require.NoError(t, err)
+	contractSchema, err := factory.ContractSchema(federation.Contract{ExcludeTags: []string{"admin"}})
+	require.NoError(t, err)
+
+	assert.Contains(t, string(mergedSchema.Input()), "users: [User]")
+	assert.NotContains(t, string(contractSchema.Input()), "users")
+	assert.NotEqual(t, mergedSchema.Hash(), contractSchema.Hash())
+
+	again, err := factory.MergedSchema()
+	require.NoError(t, err)
+	assert.Same(t, mergedSchema, again)
+}
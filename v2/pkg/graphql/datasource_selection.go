@@ -0,0 +1,39 @@
+package graphql
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// ExplainDataSourceSelection returns for each field of the operation the datasources able to resolve it,
+// the selected datasource and the reasons of the selection, e.g. to show why a field is resolved by a subgraph
+// The operation is planned without resolving it, the plan is not cached.
+func (e *ExecutionEngineV2) ExplainDataSourceSelection(operation *Request) ([]plan.FieldDataSourceSelection, error) {
+	if !operation.IsNormalized() {
+		result, err := operation.normalize(e.config.schema, e.config.normalizationFlags, e.normalizationOptions()...)
+		if err != nil {
+			return nil, err
+		}
+		if !result.Successful {
+			return nil, result.Errors
+		}
+	}
+
+	result, err := operation.ValidateForSchema(e.config.schema)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Valid {
+		return nil, result.Errors
+	}
+
+	var report operationreport.Report
+
+	e.plannerMu.Lock()
+	defer e.plannerMu.Unlock()
+	selections := e.planner.ExplainDataSourceSelection(&operation.document, &e.config.schema.document, operation.OperationName, &report)
+	if report.HasErrors() {
+		return nil, report
+	}
+	return selections, nil
+}
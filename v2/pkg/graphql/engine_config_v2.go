@@ -20,6 +20,29 @@ type EngineV2Configuration struct {
 	plannerConfig            plan.Configuration
 	websocketBeforeStartHook WebsocketBeforeStartHook
 	dataLoaderConfig         dataLoaderConfig
+	introspectionFilter      IntrospectionFilter
+	introspectionCache       bool
+	audit                    AuditConfiguration
+	piiMaskedRoles           []string
+	arena                    bool
+	maxExpandedSelections    int
+	variablesLimits          VariablesLimits
+	fieldValueTransformer    resolve.FieldValueTransformer
+	shareableConflictPolicy  ShareableConflictPolicy
+	dataSourceMetrics        resolve.DataSourceMetrics
+	schemaContracts          map[string]*Schema
+	propagateSubgraphErrors  bool
+	planCache                PlanCache
+	persistedOperationStore  PersistedOperationStore
+	slowOperationLog         *SlowOperationLog
+	profilerLabels           bool
+	idGenerator              resolve.IDGenerator
+	goroutineTracker         *resolve.GoroutineTracker
+	normalizationFlags       NormalizationFlags
+	operationTracer          OperationTracer
+	metrics                  *EngineMetrics
+	apolloTracing            bool
+	federatedTraceEncoder    resolve.FederatedTraceEncoder
 }
 
 func NewEngineV2Configuration(schema *Schema) EngineV2Configuration {
@@ -72,11 +95,194 @@ func (e *EngineV2Configuration) EnableSingleFlight(enable bool) {
 	e.dataLoaderConfig.EnableSingleFlightLoader = enable
 }
 
+// EnableArena - allocates the results of fetches from an arena which is freed at the end of each request
+func (e *EngineV2Configuration) EnableArena(enable bool) {
+	e.arena = enable
+}
+
+// SetMaxExpandedSelections - limits the number of selections inlined from fragments during normalization,
+// operations exceeding the limit are rejected. 0 disables the limit.
+func (e *EngineV2Configuration) SetMaxExpandedSelections(maxExpandedSelections int) {
+	e.maxExpandedSelections = maxExpandedSelections
+}
+
+// SetVariablesLimits - rejects requests with variables exceeding the limits or defining a variable more than once
+// before the variables are normalized and coerced, the http handler checks the limits before it unmarshals the request
+func (e *EngineV2Configuration) SetVariablesLimits(limits VariablesLimits) {
+	e.variablesLimits = limits
+}
+
+// SetFieldValueTransformer - encodes the values of fields configured with EncodeValue
+// and decodes the values of arguments configured with DecodeValue
+func (e *EngineV2Configuration) SetFieldValueTransformer(transformer resolve.FieldValueTransformer) {
+	e.fieldValueTransformer = transformer
+}
+
+// SetPlanCache - replaces the LRU cache of the engine storing the plans of operations,
+// operations with a cached plan skip validation and planning.
+// Plans depend on the datasources of the engine, so a cache must not be shared by engines with different datasources.
+func (e *EngineV2Configuration) SetPlanCache(cache PlanCache) {
+	e.planCache = cache
+}
+
+// SetPersistedOperationStore - executes only the operations registered in the store and rejects all other requests,
+// requests send the document id of the operation instead of the query, see ExecutionEngineV2.Execute
+func (e *EngineV2Configuration) SetPersistedOperationStore(store PersistedOperationStore) {
+	e.persistedOperationStore = store
+}
+
+// SetSlowOperationLog - records queries and mutations taking longer than the threshold of the log
+// with their redacted query, the fetch tree of their plan and the stats of their fetches, plans include the info about the datasources of the fetches.
+// Subscriptions are not recorded.
+func (e *EngineV2Configuration) SetSlowOperationLog(log *SlowOperationLog) {
+	e.slowOperationLog = log
+	if log != nil {
+		e.plannerConfig.IncludeInfo = true
+	}
+}
+
+// SetProfilerLabels - labels the goroutines planning and resolving an operation with pprof labels,
+// so CPU profiles are attributed to operations and datasources, see resolve.ProfilerLabelOperationName and resolve.ProfilerLabelDataSourceID.
+// Fetches are labeled with the ID of their datasource, so the plans include the info about the datasources of the fetches.
+func (e *EngineV2Configuration) SetProfilerLabels(enabled bool) {
+	e.profilerLabels = enabled
+	if enabled {
+		e.plannerConfig.IncludeInfo = true
+	}
+}
+
+// SetIDGenerator - generates the IDs of the fetches in traces and the connection IDs of subscriptions,
+// e.g. resolve.NewSeededIDGenerator makes the traces deterministic for golden file tests
+func (e *EngineV2Configuration) SetIDGenerator(ids resolve.IDGenerator) {
+	e.idGenerator = ids
+}
+
+// SetGoroutineTracker - tracks the goroutines of operations and subscriptions to report the goroutines
+// which keep running after their operation or subscription is canceled, it's meant for debugging
+func (e *EngineV2Configuration) SetGoroutineTracker(tracker *resolve.GoroutineTracker) {
+	e.goroutineTracker = tracker
+}
+
+// SetNormalizationFlags - disables stages of the normalization of operations,
+// requests override the flags of the engine with WithNormalizationFlags
+func (e *EngineV2Configuration) SetNormalizationFlags(flags NormalizationFlags) {
+	e.normalizationFlags = flags
+}
+
 // SetWebsocketBeforeStartHook - sets before start hook which will be called before processing any operation sent over websockets
 func (e *EngineV2Configuration) SetWebsocketBeforeStartHook(hook WebsocketBeforeStartHook) {
 	e.websocketBeforeStartHook = hook
 }
 
+// SetIntrospectionFilter - sets a filter which is applied once to the introspection data of the schema, before any introspection query is resolved
+func (e *EngineV2Configuration) SetIntrospectionFilter(filter IntrospectionFilter) {
+	e.introspectionFilter = filter
+}
+
+// EnableIntrospectionCache - enables caching the introspection data resolved by the introspection data sources,
+// introspection queries are still executed like any other operation, only the data of the schema is served from the cache
+func (e *EngineV2Configuration) EnableIntrospectionCache(enable bool) {
+	e.introspectionCache = enable
+}
+
+// SetAuditConfiguration - enables audit records for executed operations, plans include the info about the accessed fields
+func (e *EngineV2Configuration) SetAuditConfiguration(config AuditConfiguration) {
+	e.audit = config
+	if config.Sink != nil {
+		e.plannerConfig.IncludeInfo = true
+	}
+}
+
+// SetDataSourceMetrics - records the sizes and the durations of the fetches per datasource, e.g. with resolve.NewDataSourceTelemetry,
+// plans include the info about the datasources of the fetches
+func (e *EngineV2Configuration) SetDataSourceMetrics(metrics resolve.DataSourceMetrics) {
+	e.dataSourceMetrics = metrics
+	if metrics != nil {
+		e.plannerConfig.IncludeInfo = true
+	}
+}
+
+// SetOperationTracer - traces the parsing, normalization, validation, planning and resolving of operations and their fetches,
+// e.g. with otel.NewTracer, plans include the info about the datasources of the fetches
+func (e *EngineV2Configuration) SetOperationTracer(tracer OperationTracer) {
+	e.operationTracer = tracer
+	if tracer != nil {
+		e.plannerConfig.IncludeInfo = true
+	}
+}
+
+// SetMetrics - records the operations, the lookups of the plan cache, the durations of the fetches and the active subscriptions
+// to a metrics backend, e.g. with prometheus.NewMetrics, plans include the info about the datasources of the fetches
+func (e *EngineV2Configuration) SetMetrics(metrics EngineMetrics) {
+	e.metrics = &metrics
+	e.plannerConfig.IncludeInfo = true
+}
+
+// EnableApolloTracing - allows requests to ask for Apollo compatible tracing data in the response extensions with WithApolloTracing,
+// e.g. subgraphs of an Apollo gateway, plans include the info about the datasources of the fetches
+func (e *EngineV2Configuration) EnableApolloTracing(enable bool) {
+	e.apolloTracing = enable
+	if enable {
+		e.plannerConfig.IncludeInfo = true
+	}
+}
+
+// SetFederatedTraceEncoder - sets the encoder of the federated traces rendered for resolve.ApolloTracingFormatFTV1, e.g. ftv1.Encoder of the otel module,
+// without an encoder requests asking for federated traces get no tracing data
+func (e *EngineV2Configuration) SetFederatedTraceEncoder(encoder resolve.FederatedTraceEncoder) {
+	e.federatedTraceEncoder = encoder
+}
+
+// AddSchemaContract - adds a variant of the schema, e.g. built with federation.BuildPublicSchema, which is selected per request with ExecuteWithContract.
+// Operations of the contract are validated and introspected with the schema of the contract and planned with the schema of the engine,
+// so the schema of the contract has to be a subset of the schema of the engine.
+func (e *EngineV2Configuration) AddSchemaContract(name string, schema *Schema) {
+	if e.schemaContracts == nil {
+		e.schemaContracts = map[string]*Schema{}
+	}
+	e.schemaContracts[name] = schema
+}
+
+// SetPropagateSubgraphErrors - adds the errors returned by the datasources to the extensions of the errors of the response,
+// by default the response only contains an error per failed fetch
+func (e *EngineV2Configuration) SetPropagateSubgraphErrors(propagate bool) {
+	e.propagateSubgraphErrors = propagate
+}
+
+// SetPIIMaskedRoles - sets the roles for which the values of fields tagged as PII are masked in responses, see WithRoles
+func (e *EngineV2Configuration) SetPIIMaskedRoles(roles ...string) {
+	e.piiMaskedRoles = roles
+}
+
+// SetNodeSuggestionScorer - sets the scorer which selects the datasource of fields resolvable by multiple datasources,
+// e.g. a plan.WeightedNodeSuggestionScorer to bias planning toward cheaper datasources
+func (e *EngineV2Configuration) SetNodeSuggestionScorer(scorer plan.NodeSuggestionScorer) {
+	e.plannerConfig.NodeSuggestionScorer = scorer
+}
+
+// ShareableConflictPolicy decides how fields which are resolved by multiple datasources without being shareable
+// are handled when the engine is created
+type ShareableConflictPolicy int
+
+const (
+	// ShareableConflictPolicyWarn logs a warning with the conflicting datasources for each field
+	ShareableConflictPolicyWarn ShareableConflictPolicy = iota
+	// ShareableConflictPolicyError rejects the configuration with a plan.ShareableConflicts error
+	ShareableConflictPolicyError
+)
+
+// SetShareableConflictPolicy - sets how fields which are root nodes of multiple datasources without being shareable are reported,
+// see plan.FederationMetaData.Shareable
+func (e *EngineV2Configuration) SetShareableConflictPolicy(policy ShareableConflictPolicy) {
+	e.shareableConflictPolicy = policy
+}
+
+// SetDataSourceDirective - enables the directive which pins fields of an operation to a datasource by its ID,
+// see plan.DataSourceDirectiveConfiguration
+func (e *EngineV2Configuration) SetDataSourceDirective(config plan.DataSourceDirectiveConfiguration) {
+	e.plannerConfig.DataSourceDirective = config
+}
+
 type dataSourceV2GeneratorOptions struct {
 	streamingClient           *http.Client
 	subscriptionType          SubscriptionType
@@ -202,7 +408,7 @@ func (g *graphqlFieldConfigurationsV2Generator) engineConfigArguments(fieldConfs
 			continue
 		}
 
-		(*fieldConfs)[i].Arguments = g.createArgumentConfigurationsForArgumentNames(currentArgs.ArgumentNames)
+		(*fieldConfs)[i].Arguments = g.mergeArgumentConfigurations((*fieldConfs)[i].Arguments, currentArgs.ArgumentNames)
 		delete(generatedArgs, lookupKey)
 	}
 
@@ -215,6 +421,21 @@ func (g *graphqlFieldConfigurationsV2Generator) engineConfigArguments(fieldConfs
 	}
 }
 
+// mergeArgumentConfigurations keeps predefined argument configurations, e.g. gateway level default values,
+// and adds configurations for all other arguments defined in the schema
+func (g *graphqlFieldConfigurationsV2Generator) mergeArgumentConfigurations(predefined plan.ArgumentsConfigurations, argumentNames []string) plan.ArgumentsConfigurations {
+	argConfs := g.createArgumentConfigurationsForArgumentNames(argumentNames)
+	for _, predefinedArgConf := range predefined {
+		if existing := argConfs.ForName(predefinedArgConf.Name); existing != nil {
+			*existing = predefinedArgConf
+			continue
+		}
+		argConfs = append(argConfs, predefinedArgConf)
+	}
+
+	return argConfs
+}
+
 func (g *graphqlFieldConfigurationsV2Generator) createArgumentConfigurationsForArgumentNames(argumentNames []string) plan.ArgumentsConfigurations {
 	argConfs := plan.ArgumentsConfigurations{}
 	for _, argName := range argumentNames {
@@ -286,6 +286,17 @@ func TestEngineV2Configuration_EnableSingleFlight(t *testing.T) {
 	assert.False(t, conf.dataLoaderConfig.EnableSingleFlightLoader)
 }
 
+func TestEngineV2Configuration_SetMaxExpandedSelections(t *testing.T) {
+	schema, err := NewSchemaFromString(graphqlGeneratorSchema)
+	require.NoError(t, err)
+
+	conf := NewEngineV2Configuration(schema)
+	require.Zerof(t, conf.maxExpandedSelections, "expanded selections are not limited by default")
+
+	conf.SetMaxExpandedSelections(100)
+	assert.Equal(t, 100, conf.maxExpandedSelections)
+}
+
 var mockSubscriptionClient = &graphqlDataSource.SubscriptionClient{}
 
 type MockSubscriptionClientFactory struct{}
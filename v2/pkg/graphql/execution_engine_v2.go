@@ -6,23 +6,26 @@ import (
 	"compress/gzip"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
-	lru "github.com/hashicorp/golang-lru"
 	"github.com/jensneuse/abstractlogger"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
-	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astnormalization"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/httpclient"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/introspection_datasource"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/postprocess"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/introspection"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
-	"github.com/wundergraph/graphql-go-tools/v2/pkg/pool"
 )
 
 type EngineResultWriter struct {
@@ -112,6 +115,16 @@ func (e *EngineResultWriter) AsHTTPResponse(status int, headers http.Header) *ht
 type internalExecutionContext struct {
 	resolveContext *resolve.Context
 	postProcessor  *postprocess.Processor
+	auditIdentity  string
+	roles          []string
+	// fetchTimings are collected for the slow operation log
+	fetchTimings *resolve.FetchTimings
+	// normalizationFlags override the normalization flags of the engine
+	normalizationFlags *NormalizationFlags
+	// apolloTracing is the format of the tracing data requested with WithApolloTracing
+	apolloTracing resolve.ApolloTracingFormat
+	// introspectionTypesPage pages the types of __schema, set with WithIntrospectionTypesPage
+	introspectionTypesPage *introspection_datasource.TypesPage
 }
 
 func newInternalExecutionContext() *internalExecutionContext {
@@ -141,6 +154,12 @@ func (e *internalExecutionContext) setVariables(variables []byte) {
 
 func (e *internalExecutionContext) reset() {
 	e.resolveContext.Free()
+	e.auditIdentity = ""
+	e.roles = nil
+	e.fetchTimings = nil
+	e.normalizationFlags = nil
+	e.apolloTracing = resolve.ApolloTracingFormatNone
+	e.introspectionTypesPage = nil
 }
 
 type ExecutionEngineV2 struct {
@@ -150,7 +169,17 @@ type ExecutionEngineV2 struct {
 	plannerMu                    sync.Mutex
 	resolver                     *resolve.Resolver
 	internalExecutionContextPool sync.Pool
-	executionPlanCache           *lru.Cache
+	executionPlanCache           PlanCache
+	planCacheHits                atomic.Int64
+	planCacheMisses              atomic.Int64
+	configHash                   uint64
+	contracts                    map[string]*schemaContract
+}
+
+// schemaContract is a variant of the schema of the engine, see EngineV2Configuration.AddSchemaContract
+type schemaContract struct {
+	schema            *Schema
+	introspectionData *introspection.Data
 }
 
 type WebsocketBeforeStartHook interface {
@@ -186,10 +215,106 @@ func WithAdditionalHttpHeaders(headers http.Header, excludeByKeys ...string) Exe
 	}
 }
 
+// WithRoles sets the roles of the client executing the operation
+// Fields tagged as PII are masked if one of the roles is configured with SetPIIMaskedRoles
+func WithRoles(roles ...string) ExecutionOptionsV2 {
+	return func(ctx *internalExecutionContext) {
+		ctx.roles = roles
+	}
+}
+
+// WithMutationRollbackHook sets a hook which is called with the successful top level mutations
+// after all top level mutations of the operation were executed, if one of them failed
+func WithMutationRollbackHook(hook resolve.MutationRollbackHook) ExecutionOptionsV2 {
+	return func(ctx *internalExecutionContext) {
+		ctx.resolveContext.SetMutationRollbackHook(hook)
+	}
+}
+
+// WithResponseHeaders collects the response headers contributed by datasources and hooks into headers,
+// including the resolve.ResponseHeaderOptions.UpstreamHeaders of the responses of upstream requests, e.g. Set-Cookie,
+// the caller applies them to the response after the execution
+func WithResponseHeaders(headers *resolve.ResponseHeaders) ExecutionOptionsV2 {
+	return func(ctx *internalExecutionContext) {
+		ctx.resolveContext.SetResponseHeaders(headers)
+	}
+}
+
+// WithSubscriptionUpdateTimeout sets the deadline for resolving a single event of a subscription,
+// so a slow fetch triggered by an event can't stall the subscription
+func WithSubscriptionUpdateTimeout(timeout time.Duration) ExecutionOptionsV2 {
+	return func(ctx *internalExecutionContext) {
+		ctx.resolveContext.SetSubscriptionUpdateTimeout(timeout)
+	}
+}
+
+// WithSubscriptionCoalescing merges the events of a subscription received within the coalescing window into a single message
+func WithSubscriptionCoalescing(coalescing resolve.SubscriptionCoalescing) ExecutionOptionsV2 {
+	return func(ctx *internalExecutionContext) {
+		ctx.resolveContext.SetSubscriptionCoalescing(coalescing)
+	}
+}
+
+// WithNormalizationFlags normalizes the operation with the flags instead of the normalization flags of the engine,
+// it has no effect on requests which are already normalized
+func WithNormalizationFlags(flags NormalizationFlags) ExecutionOptionsV2 {
+	return func(ctx *internalExecutionContext) {
+		ctx.normalizationFlags = &flags
+	}
+}
+
+// WithIntrospectionTypesPage pages and filters the types returned by __schema { types },
+// so clients of large schemas can fetch the types with multiple introspection queries.
+// The introspection schema is unchanged, the page is configured per request.
+func WithIntrospectionTypesPage(page introspection_datasource.TypesPage) ExecutionOptionsV2 {
+	return func(ctx *internalExecutionContext) {
+		ctx.introspectionTypesPage = &page
+	}
+}
+
 func NewExecutionEngineV2(ctx context.Context, logger abstractlogger.Logger, engineConfig EngineV2Configuration) (*ExecutionEngineV2, error) {
-	executionPlanCache, err := lru.New(1024)
-	if err != nil {
-		return nil, err
+	return newExecutionEngineV2(ctx, logger, engineConfig, resolve.New(ctx, engineConfig.resolverOptions()))
+}
+
+// resolverOptions returns the options of a resolver configured with the settings of the engine configuration
+func (e *EngineV2Configuration) resolverOptions() resolve.ResolverOptions {
+	return resolve.ResolverOptions{
+		MaxConcurrency:          1024,
+		EnableArena:             e.arena,
+		DataSourceMetrics:       e.dataSourceMetrics,
+		FetchTracer:             e.operationTracer,
+		Metrics:                 e.resolverMetrics(),
+		PropagateSubgraphErrors: e.propagateSubgraphErrors,
+		IDGenerator:             e.idGenerator,
+		GoroutineTracker:        e.goroutineTracker,
+	}
+}
+
+// newExecutionEngineV2 creates an engine using the given resolver, so that engines of multiple schemas are able to share it
+func newExecutionEngineV2(ctx context.Context, logger abstractlogger.Logger, engineConfig EngineV2Configuration, resolver *resolve.Resolver) (*ExecutionEngineV2, error) {
+	executionPlanCache := engineConfig.planCache
+	if executionPlanCache == nil {
+		var err error
+		if executionPlanCache, err = NewLRUPlanCache(defaultPlanCacheSize); err != nil {
+			return nil, err
+		}
+	}
+
+	if conflicts := plan.FindShareableConflicts(engineConfig.plannerConfig.DataSources); len(conflicts) != 0 {
+		if engineConfig.shareableConflictPolicy == ShareableConflictPolicyError {
+			return nil, conflicts
+		}
+		for _, conflict := range conflicts {
+			logger.Warn("field is resolved by multiple datasources without being shareable",
+				abstractlogger.String("field", conflict.TypeName+"."+conflict.FieldName),
+				abstractlogger.Strings("dataSources", conflict.DataSourceIDs),
+			)
+		}
+	}
+
+	// fields depending on each other through @requires could never be planned, so the configuration is rejected
+	if cycles := plan.FindRequiresCycles(engineConfig.plannerConfig.DataSources); len(cycles) != 0 {
+		return nil, cycles
 	}
 
 	introspectionCfg, err := introspection_datasource.NewIntrospectionConfigFactory(&engineConfig.schema.document)
@@ -197,6 +322,34 @@ func NewExecutionEngineV2(ctx context.Context, logger abstractlogger.Logger, eng
 		return nil, err
 	}
 
+	if engineConfig.introspectionFilter != nil {
+		introspectionCfg.ApplyFilter(engineConfig.introspectionFilter)
+	}
+
+	if engineConfig.introspectionCache {
+		if err = introspectionCfg.EnableCache(introspectionCacheSize); err != nil {
+			return nil, err
+		}
+	}
+
+	contracts := make(map[string]*schemaContract, len(engineConfig.schemaContracts))
+	for name, schema := range engineConfig.schemaContracts {
+		contractIntrospectionCfg, err := introspection_datasource.NewIntrospectionConfigFactory(&schema.document)
+		if err != nil {
+			return nil, fmt.Errorf("schema contract %q: %w", name, err)
+		}
+		if engineConfig.introspectionFilter != nil {
+			contractIntrospectionCfg.ApplyFilter(engineConfig.introspectionFilter)
+		}
+		contracts[name] = &schemaContract{
+			schema:            schema,
+			introspectionData: contractIntrospectionCfg.IntrospectionData(),
+		}
+	}
+
+	// the introspection datasources are derived from the schema, so they are not part of the hash
+	hash := configHash(&engineConfig)
+
 	for _, dataSource := range introspectionCfg.BuildDataSourceConfigurations() {
 		engineConfig.AddDataSource(dataSource)
 	}
@@ -206,87 +359,233 @@ func NewExecutionEngineV2(ctx context.Context, logger abstractlogger.Logger, eng
 	}
 
 	return &ExecutionEngineV2{
-		logger:  logger,
-		config:  engineConfig,
-		planner: plan.NewPlanner(ctx, engineConfig.plannerConfig),
-		resolver: resolve.New(ctx, resolve.ResolverOptions{
-			MaxConcurrency: 1024,
-		}),
+		logger:   logger,
+		config:   engineConfig,
+		planner:  plan.NewPlanner(ctx, engineConfig.plannerConfig),
+		resolver: resolver,
 		internalExecutionContextPool: sync.Pool{
 			New: func() interface{} {
 				return newInternalExecutionContext()
 			},
 		},
 		executionPlanCache: executionPlanCache,
+		configHash:         hash,
+		contracts:          contracts,
 	}, nil
 }
 
+// Execute executes the operation against the schema of the engine.
+// With a PersistedOperationStore, the query of a request with a document id is loaded from the store
+// and requests of operations which are not registered are rejected.
 func (e *ExecutionEngineV2) Execute(ctx context.Context, operation *Request, writer resolve.SubscriptionResponseWriter, options ...ExecutionOptionsV2) error {
+	return e.ExecuteWithContract(ctx, "", operation, writer, options...)
+}
+
+// ExecuteWithContract executes the operation against the schema contract, see EngineV2Configuration.AddSchemaContract.
+// An empty contract executes the operation against the schema of the engine.
+// All contracts share the planner, the plan cache and the datasources of the engine.
+func (e *ExecutionEngineV2) ExecuteWithContract(ctx context.Context, contract string, operation *Request, writer resolve.SubscriptionResponseWriter, options ...ExecutionOptionsV2) error {
+	var schemaContract *schemaContract
+	if contract != "" {
+		var ok bool
+		if schemaContract, ok = e.contracts[contract]; !ok {
+			return fmt.Errorf("unknown schema contract %q", contract)
+		}
+	}
+	if err := e.ResolvePersistedOperation(ctx, operation); err != nil {
+		return err
+	}
+	return e.execute(ctx, schemaContract, operation, writer, options...)
+}
+
+func (e *ExecutionEngineV2) normalizationOptions() (options []astnormalization.Option) {
+	if e.config.maxExpandedSelections > 0 {
+		options = append(options, astnormalization.WithMaxExpandedSelections(e.config.maxExpandedSelections))
+	}
+	return options
+}
+
+func (e *ExecutionEngineV2) execute(ctx context.Context, contract *schemaContract, operation *Request, writer resolve.SubscriptionResponseWriter, options ...ExecutionOptionsV2) error {
+	start := time.Now()
+
+	if e.config.profilerLabels {
+		var restoreLabels func()
+		ctx, restoreLabels = setProfilerLabels(ctx, operation)
+		defer restoreLabels()
+	}
+
+	if e.config.variablesLimits.enabled() {
+		if err := operation.ValidateVariablesLimits(e.config.variablesLimits); err != nil {
+			return err
+		}
+	}
+
+	// operations of a contract are normalized and validated with the schema of the contract
+	// and planned with the schema of the engine
+	schema := e.config.schema
+	if contract != nil {
+		schema = contract.schema
+		ctx = introspection_datasource.WithIntrospectionData(ctx, contract.introspectionData)
+	}
+
+	// the options are applied before the normalization, they might set the normalization flags
+	execContext := e.getExecutionCtx()
+	defer e.putExecutionCtx(execContext)
+
+	execContext.prepare(ctx, operation.Variables, operation.request)
+
+	for i := range options {
+		options[i](execContext)
+	}
+
+	if execContext.introspectionTypesPage != nil {
+		ctx = introspection_datasource.WithTypesPage(ctx, *execContext.introspectionTypesPage)
+		execContext.setContext(ctx)
+	}
+
 	if !operation.IsNormalized() {
-		result, err := operation.Normalize(e.config.schema)
+		err := e.tracePhase(ctx, ExecutionPhaseParse, operation, func(_ context.Context) error {
+			if report := operation.parseQueryOnce(); report.HasErrors() {
+				result, err := normalizationResultFromReport(report)
+				if err != nil {
+					return err
+				}
+				return result.Errors
+			}
+			return nil
+		})
 		if err != nil {
 			return err
 		}
 
-		if !result.Successful {
-			return result.Errors
+		normalizationFlags := e.config.normalizationFlags
+		if execContext.normalizationFlags != nil {
+			normalizationFlags = *execContext.normalizationFlags
+		}
+		err = e.tracePhase(ctx, ExecutionPhaseNormalize, operation, func(_ context.Context) error {
+			result, err := operation.normalize(schema, normalizationFlags, e.normalizationOptions()...)
+			if err != nil {
+				return err
+			}
+			if !result.Successful {
+				return result.Errors
+			}
+			return nil
+		})
+		if err != nil {
+			return err
 		}
 	}
 
-	result, err := operation.ValidateForSchema(e.config.schema)
+	// normalization is required on a cache hit as well, it extracts the variables of the operation
+	cacheKey, err := planCacheKey(&operation.document, &e.config.schema.document, operation.OperationName, schema)
 	if err != nil {
 		return err
 	}
-	if !result.Valid {
-		return result.Errors
+	// only plans of valid operations are cached, so operations with a cached plan skip validation
+	cachedPlan, cached := e.executionPlanCache.Get(cacheKey)
+	if cached {
+		e.planCacheHits.Add(1)
+	} else {
+		e.planCacheMisses.Add(1)
+	}
+	e.recordOperation(operation, cached)
+	if !cached {
+		err = e.tracePhase(ctx, ExecutionPhaseValidate, operation, func(_ context.Context) error {
+			result, err := operation.ValidateForSchema(schema)
+			if err != nil {
+				return err
+			}
+			if !result.Valid {
+				return result.Errors
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
 	}
 
-	execContext := e.getExecutionCtx()
-	defer e.putExecutionCtx(execContext)
+	// normalization extracts the variables of the operation
+	execContext.setVariables(operation.Variables)
 
-	execContext.prepare(ctx, operation.Variables, operation.request)
+	if e.masksPII(execContext.roles) {
+		execContext.resolveContext.SetMaskPII(true)
+	}
 
-	for i := range options {
-		options[i](execContext)
+	if e.config.fieldValueTransformer != nil {
+		execContext.resolveContext.SetFieldValueTransformer(e.config.fieldValueTransformer)
+	}
+
+	if e.config.profilerLabels {
+		execContext.resolveContext.SetProfilerLabels(true)
+	}
+
+	if e.config.slowOperationLog != nil {
+		execContext.fetchTimings = &resolve.FetchTimings{}
+		execContext.resolveContext.SetFetchTimings(execContext.fetchTimings)
+	}
+
+	if e.config.apolloTracing && execContext.apolloTracing != resolve.ApolloTracingFormatNone {
+		execContext.resolveContext.SetApolloTracing(execContext.apolloTracing, start)
+		execContext.resolveContext.SetFederatedTraceEncoder(e.config.federatedTraceEncoder)
 	}
 
 	var report operationreport.Report
-	cachedPlan := e.getCachedPlan(execContext, &operation.document, &e.config.schema.document, operation.OperationName, &report)
+	plan.ValidateArgumentConstraints(&operation.document, &e.config.schema.document, operation.Variables, e.config.plannerConfig.Fields, &report)
 	if report.HasErrors() {
 		return report
 	}
 
+	if !cached {
+		err = e.tracePhase(ctx, ExecutionPhasePlan, operation, func(_ context.Context) error {
+			cachedPlan = e.planOperation(execContext, cacheKey, &operation.document, &e.config.schema.document, operation.OperationName, &report)
+			if report.HasErrors() {
+				return report
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	switch p := cachedPlan.(type) {
 	case *plan.SynchronousResponsePlan:
-		err = e.resolver.ResolveGraphQLResponse(execContext.resolveContext, p.Response, nil, writer)
+		err = e.tracePhase(ctx, ExecutionPhaseResolve, operation, func(ctx context.Context) error {
+			// the fetches are started with the context of the phase
+			execContext.setContext(ctx)
+			return e.resolver.ResolveGraphQLResponse(execContext.resolveContext, p.Response, nil, writer)
+		})
+		if e.config.slowOperationLog != nil {
+			e.recordSlowOperation(ctx, execContext, operation, p, start, err)
+		}
 	case *plan.SubscriptionResponsePlan:
-		err = e.resolver.AsyncResolveGraphQLSubscription(execContext.resolveContext, p.Response, writer, resolve.SubscriptionIdentifier{})
+		// the subscription outlives the execution, so it can't use the pooled resolve context
+		// otherwise the fetches of the events, e.g. entity fetches of other subgraphs, run with a freed context
+		err = e.resolver.AsyncResolveGraphQLSubscription(execContext.resolveContext.WithContext(ctx), p.Response, writer, resolve.SubscriptionIdentifier{})
 	default:
 		return errors.New("execution of operation is not possible")
 	}
 
-	return err
-}
-
-func (e *ExecutionEngineV2) getCachedPlan(ctx *internalExecutionContext, operation, definition *ast.Document, operationName string, report *operationreport.Report) plan.Plan {
-
-	hash := pool.Hash64.Get()
-	hash.Reset()
-	defer pool.Hash64.Put(hash)
-	err := astprinter.Print(operation, definition, hash)
-	if err != nil {
-		report.AddInternalError(err)
-		return nil
+	if e.config.audit.Sink != nil {
+		e.audit(ctx, execContext, operation, cachedPlan, err)
 	}
 
-	cacheKey := hash.Sum64()
+	return err
+}
 
-	if cached, ok := e.executionPlanCache.Get(cacheKey); ok {
-		if p, ok := cached.(plan.Plan); ok {
-			return p
+func (e *ExecutionEngineV2) masksPII(roles []string) bool {
+	for _, role := range roles {
+		if slices.Contains(e.config.piiMaskedRoles, role) {
+			return true
 		}
 	}
+	return false
+}
 
+// planOperation plans a validated operation and adds the plan to the plan cache
+func (e *ExecutionEngineV2) planOperation(ctx *internalExecutionContext, cacheKey uint64, operation, definition *ast.Document, operationName string, report *operationreport.Report) plan.Plan {
 	e.plannerMu.Lock()
 	defer e.plannerMu.Unlock()
 	planResult := e.planner.Plan(operation, definition, operationName, report)
@@ -9,8 +9,10 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/jensneuse/abstractlogger"
 	"github.com/stretchr/testify/assert"
@@ -18,9 +20,12 @@ import (
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/graphql_datasource"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/httpclient"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/introspection_datasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/node_datasource"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/staticdatasource"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/introspection"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/starwars"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/testing/federationtesting"
@@ -303,13 +308,42 @@ func TestExecutionEngineV2_Execute(t *testing.T) {
 			},
 		))
 
+		t.Run("execute type introspection query for scalar with specifiedByURL", runWithoutError(
+			ExecutionEngineV2TestCase{
+				schema: func() *Schema {
+					schema, err := NewSchemaFromString(`
+						scalar DateTime @specifiedBy(url: "https://scalars.graphql.org/andimarek/date-time")
+						type Query { now: DateTime }
+					`)
+					require.NoError(t, err)
+					return schema
+				}(),
+				operation: func(t *testing.T) Request {
+					return Request{
+						OperationName: "myIntrospection",
+						Query: `query myIntrospection(){
+							dateTime: __type(name: "DateTime") {
+								name
+								specifiedByURL
+							}
+							string: __type(name: "String") {
+								name
+								specifiedByURL
+							}
+						}`,
+					}
+				},
+				expectedResponse: `{"data":{"dateTime":{"name":"DateTime","specifiedByURL":"https://scalars.graphql.org/andimarek/date-time"},"string":{"name":"String","specifiedByURL":null}}}`,
+			},
+		))
+
 		t.Run("execute full introspection query", runWithoutError(
 			ExecutionEngineV2TestCase{
 				schema: schema,
 				operation: func(t *testing.T) Request {
 					return requestForQuery(t, starwars.FileIntrospectionQuery)
 				},
-				expectedResponse: `{"data":{"__schema":{"queryType":{"name":"Query"},"mutationType":{"name":"Mutation"},"subscriptionType":{"name":"Subscription"},"types":[{"kind":"UNION","name":"SearchResult","description":"","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[{"kind":"OBJECT","name":"Human","ofType":null},{"kind":"OBJECT","name":"Droid","ofType":null},{"kind":"OBJECT","name":"Starship","ofType":null}]},{"kind":"OBJECT","name":"Query","description":"","fields":[{"name":"hero","description":"","args":[],"type":{"kind":"INTERFACE","name":"Character","ofType":null},"isDeprecated":true,"deprecationReason":"No longer supported"},{"name":"droid","description":"","args":[{"name":"id","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"ID","ofType":null}},"defaultValue":null}],"type":{"kind":"OBJECT","name":"Droid","ofType":null},"isDeprecated":false,"deprecationReason":null},{"name":"search","description":"","args":[{"name":"name","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"defaultValue":null}],"type":{"kind":"UNION","name":"SearchResult","ofType":null},"isDeprecated":false,"deprecationReason":null},{"name":"searchResults","description":"","args":[],"type":{"kind":"LIST","name":null,"ofType":{"kind":"UNION","name":"SearchResult","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"OBJECT","name":"Mutation","description":"","fields":[{"name":"createReview","description":"","args":[{"name":"episode","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"ENUM","name":"Episode","ofType":null}},"defaultValue":null},{"name":"review","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"INPUT_OBJECT","name":"ReviewInput","ofType":null}},"defaultValue":null}],"type":{"kind":"OBJECT","name":"Review","ofType":null},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"OBJECT","name":"Subscription","description":"","fields":[{"name":"remainingJedis","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Int","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"INPUT_OBJECT","name":"ReviewInput","description":"","fields":null,"inputFields":[{"name":"stars","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Int","ofType":null}},"defaultValue":null},{"name":"commentary","description":"","type":{"kind":"SCALAR","name":"String","ofType":null},"defaultValue":null}],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"OBJECT","name":"Review","description":"","fields":[{"name":"id","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"ID","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"stars","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Int","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"commentary","description":"","args":[],"type":{"kind":"SCALAR","name":"String","ofType":null},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"ENUM","name":"Episode","description":"","fields":null,"inputFields":[],"interfaces":[],"enumValues":[{"name":"NEWHOPE","description":"","isDeprecated":false,"deprecationReason":null},{"name":"EMPIRE","description":"","isDeprecated":false,"deprecationReason":null},{"name":"JEDI","description":"","isDeprecated":true,"deprecationReason":"No longer supported"}],"possibleTypes":[]},{"kind":"INTERFACE","name":"Character","description":"","fields":[{"name":"name","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"friends","description":"","args":[],"type":{"kind":"LIST","name":null,"ofType":{"kind":"INTERFACE","name":"Character","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[{"kind":"OBJECT","name":"Human","ofType":null},{"kind":"OBJECT","name":"Droid","ofType":null}]},{"kind":"OBJECT","name":"Human","description":"","fields":[{"name":"name","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"height","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":true,"deprecationReason":"No longer supported"},{"name":"friends","description":"","args":[],"type":{"kind":"LIST","name":null,"ofType":{"kind":"INTERFACE","name":"Character","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[{"kind":"INTERFACE","name":"Character","ofType":null}],"enumValues":null,"possibleTypes":[]},{"kind":"OBJECT","name":"Droid","description":"","fields":[{"name":"name","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"primaryFunction","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"friends","description":"","args":[],"type":{"kind":"LIST","name":null,"ofType":{"kind":"INTERFACE","name":"Character","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[{"kind":"INTERFACE","name":"Character","ofType":null}],"enumValues":null,"possibleTypes":[]},{"kind":"INTERFACE","name":"Vehicle","description":"","fields":[{"name":"length","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Float","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[{"kind":"OBJECT","name":"Starship","ofType":null}]},{"kind":"OBJECT","name":"Starship","description":"","fields":[{"name":"name","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"length","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Float","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[{"kind":"INTERFACE","name":"Vehicle","ofType":null}],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"Int","description":"The 'Int' scalar type represents non-fractional signed whole numeric values. Int can represent values between -(2^31) and 2^31 - 1.","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"Float","description":"The 'Float' scalar type represents signed double-precision fractional values as specified by [IEEE 754](http://en.wikipedia.org/wiki/IEEE_floating_point).","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"String","description":"The 'String' scalar type represents textual data, represented as UTF-8 character sequences. The String type is most often used by GraphQL to represent free-form human-readable text.","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"Boolean","description":"The 'Boolean' scalar type represents 'true' or 'false' .","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"ID","description":"The 'ID' scalar type represents a unique identifier, often used to refetch an object or as key for a cache. The ID type appears in a JSON response as a String; however, it is not intended to be human-readable. When expected as an input type, any string (such as '4') or integer (such as 4) input value will be accepted as an ID.","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]}],"directives":[{"name":"include","description":"Directs the executor to include this field or fragment only when the argument is true.","locations":["FIELD","FRAGMENT_SPREAD","INLINE_FRAGMENT"],"args":[{"name":"if","description":"Included when true.","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Boolean","ofType":null}},"defaultValue":null}]},{"name":"skip","description":"Directs the executor to skip this field or fragment when the argument is true.","locations":["FIELD","FRAGMENT_SPREAD","INLINE_FRAGMENT"],"args":[{"name":"if","description":"Skipped when true.","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Boolean","ofType":null}},"defaultValue":null}]},{"name":"deprecated","description":"Marks an element of a GraphQL schema as no longer supported.","locations":["FIELD_DEFINITION","ENUM_VALUE"],"args":[{"name":"reason","description":"Explains why this element was deprecated, usually also including a suggestion\n    for how to access supported similar data. Formatted in\n    [Markdown](https://daringfireball.net/projects/markdown/).","type":{"kind":"SCALAR","name":"String","ofType":null},"defaultValue":"\"No longer supported\""}]}]}}}`,
+				expectedResponse: `{"data":{"__schema":{"queryType":{"name":"Query"},"mutationType":{"name":"Mutation"},"subscriptionType":{"name":"Subscription"},"types":[{"kind":"UNION","name":"SearchResult","description":"","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[{"kind":"OBJECT","name":"Human","ofType":null},{"kind":"OBJECT","name":"Droid","ofType":null},{"kind":"OBJECT","name":"Starship","ofType":null}]},{"kind":"OBJECT","name":"Query","description":"","fields":[{"name":"hero","description":"","args":[],"type":{"kind":"INTERFACE","name":"Character","ofType":null},"isDeprecated":true,"deprecationReason":"No longer supported"},{"name":"droid","description":"","args":[{"name":"id","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"ID","ofType":null}},"defaultValue":null}],"type":{"kind":"OBJECT","name":"Droid","ofType":null},"isDeprecated":false,"deprecationReason":null},{"name":"search","description":"","args":[{"name":"name","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"defaultValue":null}],"type":{"kind":"UNION","name":"SearchResult","ofType":null},"isDeprecated":false,"deprecationReason":null},{"name":"searchResults","description":"","args":[],"type":{"kind":"LIST","name":null,"ofType":{"kind":"UNION","name":"SearchResult","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"OBJECT","name":"Mutation","description":"","fields":[{"name":"createReview","description":"","args":[{"name":"episode","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"ENUM","name":"Episode","ofType":null}},"defaultValue":null},{"name":"review","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"INPUT_OBJECT","name":"ReviewInput","ofType":null}},"defaultValue":null}],"type":{"kind":"OBJECT","name":"Review","ofType":null},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"OBJECT","name":"Subscription","description":"","fields":[{"name":"remainingJedis","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Int","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"INPUT_OBJECT","name":"ReviewInput","description":"","fields":null,"inputFields":[{"name":"stars","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Int","ofType":null}},"defaultValue":null},{"name":"commentary","description":"","type":{"kind":"SCALAR","name":"String","ofType":null},"defaultValue":null}],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"OBJECT","name":"Review","description":"","fields":[{"name":"id","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"ID","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"stars","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Int","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"commentary","description":"","args":[],"type":{"kind":"SCALAR","name":"String","ofType":null},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"ENUM","name":"Episode","description":"","fields":null,"inputFields":[],"interfaces":[],"enumValues":[{"name":"NEWHOPE","description":"","isDeprecated":false,"deprecationReason":null},{"name":"EMPIRE","description":"","isDeprecated":false,"deprecationReason":null},{"name":"JEDI","description":"","isDeprecated":true,"deprecationReason":"No longer supported"}],"possibleTypes":[]},{"kind":"INTERFACE","name":"Character","description":"","fields":[{"name":"name","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"friends","description":"","args":[],"type":{"kind":"LIST","name":null,"ofType":{"kind":"INTERFACE","name":"Character","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[{"kind":"OBJECT","name":"Human","ofType":null},{"kind":"OBJECT","name":"Droid","ofType":null}]},{"kind":"OBJECT","name":"Human","description":"","fields":[{"name":"name","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"height","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":true,"deprecationReason":"No longer supported"},{"name":"friends","description":"","args":[],"type":{"kind":"LIST","name":null,"ofType":{"kind":"INTERFACE","name":"Character","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[{"kind":"INTERFACE","name":"Character","ofType":null}],"enumValues":null,"possibleTypes":[]},{"kind":"OBJECT","name":"Droid","description":"","fields":[{"name":"name","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"primaryFunction","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"friends","description":"","args":[],"type":{"kind":"LIST","name":null,"ofType":{"kind":"INTERFACE","name":"Character","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[{"kind":"INTERFACE","name":"Character","ofType":null}],"enumValues":null,"possibleTypes":[]},{"kind":"INTERFACE","name":"Vehicle","description":"","fields":[{"name":"length","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Float","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[{"kind":"OBJECT","name":"Starship","ofType":null}]},{"kind":"OBJECT","name":"Starship","description":"","fields":[{"name":"name","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"length","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Float","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[{"kind":"INTERFACE","name":"Vehicle","ofType":null}],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"Int","description":"The 'Int' scalar type represents non-fractional signed whole numeric values. Int can represent values between -(2^31) and 2^31 - 1.","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"Float","description":"The 'Float' scalar type represents signed double-precision fractional values as specified by [IEEE 754](http://en.wikipedia.org/wiki/IEEE_floating_point).","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"String","description":"The 'String' scalar type represents textual data, represented as UTF-8 character sequences. The String type is most often used by GraphQL to represent free-form human-readable text.","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"Boolean","description":"The 'Boolean' scalar type represents 'true' or 'false' .","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"ID","description":"The 'ID' scalar type represents a unique identifier, often used to refetch an object or as key for a cache. The ID type appears in a JSON response as a String; however, it is not intended to be human-readable. When expected as an input type, any string (such as '4') or integer (such as 4) input value will be accepted as an ID.","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]}],"directives":[{"name":"include","description":"Directs the executor to include this field or fragment only when the argument is true.","locations":["FIELD","FRAGMENT_SPREAD","INLINE_FRAGMENT"],"args":[{"name":"if","description":"Included when true.","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Boolean","ofType":null}},"defaultValue":null}]},{"name":"skip","description":"Directs the executor to skip this field or fragment when the argument is true.","locations":["FIELD","FRAGMENT_SPREAD","INLINE_FRAGMENT"],"args":[{"name":"if","description":"Skipped when true.","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Boolean","ofType":null}},"defaultValue":null}]},{"name":"deprecated","description":"Marks an element of a GraphQL schema as no longer supported.","locations":["FIELD_DEFINITION","ENUM_VALUE"],"args":[{"name":"reason","description":"Explains why this element was deprecated, usually also including a suggestion\n    for how to access supported similar data. Formatted in\n    [Markdown](https://daringfireball.net/projects/markdown/).","type":{"kind":"SCALAR","name":"String","ofType":null},"defaultValue":"\"No longer supported\""}]},{"name":"specifiedBy","description":"Exposes a URL that specifies the behavior of this scalar.","locations":["SCALAR"],"args":[{"name":"url","description":"The URL that specifies the behavior of this scalar.","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"defaultValue":null}]}]}}}`,
 			},
 		))
 	})
@@ -1233,7 +1267,7 @@ func testNetHttpClient(t *testing.T, testCase roundTripperTestCase) *http.Client
 	}
 }
 
-func TestExecutionEngineV2_GetCachedPlan(t *testing.T) {
+func TestExecutionEngineV2_PlanOperation(t *testing.T) {
 	schema, err := NewSchemaFromString(testSubscriptionDefinition)
 	require.NoError(t, err)
 
@@ -1292,6 +1326,19 @@ func TestExecutionEngineV2_GetCachedPlan(t *testing.T) {
 	engine, err := NewExecutionEngineV2(context.Background(), abstractlogger.NoopLogger, engineConfig)
 	require.NoError(t, err)
 
+	planAndCache := func(t *testing.T, execCtx *internalExecutionContext, request *Request) (uint64, plan.Plan) {
+		t.Helper()
+		cacheKey, err := planCacheKey(&request.document, &schema.document, request.OperationName, schema)
+		require.NoError(t, err)
+		if cachedPlan, ok := engine.executionPlanCache.Get(cacheKey); ok {
+			return cacheKey, cachedPlan
+		}
+		report := operationreport.Report{}
+		cachedPlan := engine.planOperation(execCtx, cacheKey, &request.document, &schema.document, request.OperationName, &report)
+		require.False(t, report.HasErrors())
+		return cacheKey, cachedPlan
+	}
+
 	t.Run("should reuse cached plan", func(t *testing.T) {
 		t.Cleanup(engine.executionPlanCache.Purge)
 		require.Equal(t, 0, engine.executionPlanCache.Len())
@@ -1301,23 +1348,19 @@ func TestExecutionEngineV2_GetCachedPlan(t *testing.T) {
 			http.CanonicalHeaderKey("Authorization"): []string{"123abc"},
 		}
 
-		report := operationreport.Report{}
-		cachedPlan := engine.getCachedPlan(firstInternalExecCtx, &gqlRequest.document, &schema.document, gqlRequest.OperationName, &report)
-		_, oldestCachedPlan, _ := engine.executionPlanCache.GetOldest()
-		assert.False(t, report.HasErrors())
+		firstCacheKey, firstPlan := planAndCache(t, firstInternalExecCtx, &gqlRequest)
 		assert.Equal(t, 1, engine.executionPlanCache.Len())
-		assert.Equal(t, cachedPlan, oldestCachedPlan.(*plan.SubscriptionResponsePlan))
+		assert.IsType(t, &plan.SubscriptionResponsePlan{}, firstPlan)
 
 		secondInternalExecCtx := newInternalExecutionContext()
 		secondInternalExecCtx.resolveContext.Request.Header = http.Header{
 			http.CanonicalHeaderKey("Authorization"): []string{"123abc"},
 		}
 
-		cachedPlan = engine.getCachedPlan(secondInternalExecCtx, &gqlRequest.document, &schema.document, gqlRequest.OperationName, &report)
-		_, oldestCachedPlan, _ = engine.executionPlanCache.GetOldest()
-		assert.False(t, report.HasErrors())
+		secondCacheKey, secondPlan := planAndCache(t, secondInternalExecCtx, &gqlRequest)
 		assert.Equal(t, 1, engine.executionPlanCache.Len())
-		assert.Equal(t, cachedPlan, oldestCachedPlan.(*plan.SubscriptionResponsePlan))
+		assert.Equal(t, firstCacheKey, secondCacheKey)
+		assert.Same(t, firstPlan, secondPlan)
 	})
 
 	t.Run("should create new plan and cache it", func(t *testing.T) {
@@ -1329,23 +1372,171 @@ func TestExecutionEngineV2_GetCachedPlan(t *testing.T) {
 			http.CanonicalHeaderKey("Authorization"): []string{"123abc"},
 		}
 
-		report := operationreport.Report{}
-		cachedPlan := engine.getCachedPlan(firstInternalExecCtx, &gqlRequest.document, &schema.document, gqlRequest.OperationName, &report)
-		_, oldestCachedPlan, _ := engine.executionPlanCache.GetOldest()
-		assert.False(t, report.HasErrors())
+		firstCacheKey, firstPlan := planAndCache(t, firstInternalExecCtx, &gqlRequest)
 		assert.Equal(t, 1, engine.executionPlanCache.Len())
-		assert.Equal(t, cachedPlan, oldestCachedPlan.(*plan.SubscriptionResponsePlan))
 
 		secondInternalExecCtx := newInternalExecutionContext()
 		secondInternalExecCtx.resolveContext.Request.Header = http.Header{
 			http.CanonicalHeaderKey("Authorization"): []string{"xyz098"},
 		}
 
-		cachedPlan = engine.getCachedPlan(secondInternalExecCtx, &differentGqlRequest.document, &schema.document, differentGqlRequest.OperationName, &report)
-		_, oldestCachedPlan, _ = engine.executionPlanCache.GetOldest()
-		assert.False(t, report.HasErrors())
+		secondCacheKey, secondPlan := planAndCache(t, secondInternalExecCtx, &differentGqlRequest)
 		assert.Equal(t, 2, engine.executionPlanCache.Len())
-		assert.NotEqual(t, cachedPlan, oldestCachedPlan.(*plan.SubscriptionResponsePlan))
+		assert.NotEqual(t, firstCacheKey, secondCacheKey)
+		assert.NotEqual(t, firstPlan, secondPlan)
+	})
+
+	t.Run("should include the schema in the cache key", func(t *testing.T) {
+		otherSchema, err := NewSchemaFromString(testSubscriptionDefinition + " type Other { id: ID }")
+		require.NoError(t, err)
+
+		cacheKey, err := planCacheKey(&gqlRequest.document, &schema.document, gqlRequest.OperationName, schema)
+		require.NoError(t, err)
+		otherCacheKey, err := planCacheKey(&gqlRequest.document, &schema.document, gqlRequest.OperationName, otherSchema)
+		require.NoError(t, err)
+		assert.NotEqual(t, cacheKey, otherCacheKey)
+	})
+}
+
+type countingPlanCache struct {
+	PlanCache
+	hits, misses int
+}
+
+func (c *countingPlanCache) Get(key uint64) (plan.Plan, bool) {
+	p, ok := c.PlanCache.Get(key)
+	if ok {
+		c.hits++
+	} else {
+		c.misses++
+	}
+	return p, ok
+}
+
+func TestExecutionEngineV2_PlanCache(t *testing.T) {
+	schema, err := NewSchemaFromString(`type Query { hello(name: String): String }`)
+	require.NoError(t, err)
+
+	lruCache, err := NewLRUPlanCache(2)
+	require.NoError(t, err)
+	cache := &countingPlanCache{PlanCache: lruCache}
+
+	engineConfig := NewEngineV2Configuration(schema)
+	engineConfig.SetPlanCache(cache)
+	engineConfig.SetDataSources([]plan.DataSourceConfiguration{
+		{
+			RootNodes: []plan.TypeField{
+				{TypeName: "Query", FieldNames: []string{"hello"}},
+			},
+			Factory: &staticdatasource.Factory{},
+			Custom: staticdatasource.ConfigJSON(staticdatasource.Configuration{
+				Data: `{"hello":"world"}`,
+			}),
+		},
+	})
+
+	engine, err := NewExecutionEngineV2(context.Background(), abstractlogger.NoopLogger, engineConfig)
+	require.NoError(t, err)
+
+	execute := func(t *testing.T, query string) (string, error) {
+		t.Helper()
+		request := Request{Query: query}
+		resultWriter := NewEngineResultWriter()
+		err := engine.Execute(context.Background(), &request, &resultWriter)
+		return resultWriter.String(), err
+	}
+
+	response, err := execute(t, `{ hello(name: "a") }`)
+	require.NoError(t, err)
+	assert.Equal(t, `{"data":{"hello":"world"}}`, response)
+	assert.Equal(t, 0, cache.hits)
+	assert.Equal(t, 1, cache.misses)
+
+	// the argument is extracted into a variable, so both operations share the plan
+	response, err = execute(t, `query { hello(name: "b") }`)
+	require.NoError(t, err)
+	assert.Equal(t, `{"data":{"hello":"world"}}`, response)
+	assert.Equal(t, 1, cache.hits)
+	assert.Equal(t, 1, cache.misses)
+	assert.Equal(t, 1, cache.Len())
+
+	// plans of invalid operations are not cached
+	_, err = execute(t, `{ hello unknown }`)
+	assert.Error(t, err)
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestExecutionEngineV2_IntrospectionCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	engineConf := NewEngineV2Configuration(starwarsSchema(t))
+	engineConf.EnableIntrospectionCache(true)
+	engineConf.SetIntrospectionFilter(func(data *introspection.Data) {
+		types := data.Schema.Types[:0]
+		for _, fullType := range data.Schema.Types {
+			if fullType.Kind != introspection.SCALAR {
+				types = append(types, fullType)
+			}
+		}
+		data.Schema.Types = types
+	})
+
+	var audited int
+	engineConf.SetAuditConfiguration(AuditConfiguration{
+		Sink: AuditSinkFunc(func(ctx context.Context, record AuditRecord) {
+			audited++
+		}),
+	})
+	engineConf.EnableApolloTracing(true)
+
+	engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+	require.NoError(t, err)
+
+	newRequest := func() Request {
+		return Request{
+			OperationName: "IntrospectionQuery",
+			Query:         `query IntrospectionQuery { __schema { types { name } } }`,
+		}
+	}
+	expectedResponse := `{"data":{"__schema":{"types":[{"name":"SearchResult"},{"name":"Query"},{"name":"Mutation"},{"name":"Subscription"},{"name":"ReviewInput"},{"name":"Review"},{"name":"Episode"},{"name":"Character"},{"name":"Human"},{"name":"Droid"},{"name":"Vehicle"},{"name":"Starship"}]}}}`
+
+	t.Run("should apply the filter to the cached introspection data", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			operation := newRequest()
+			resultWriter := NewEngineResultWriter()
+			require.NoError(t, engine.Execute(ctx, &operation, &resultWriter))
+			assert.Equal(t, expectedResponse, resultWriter.String())
+		}
+	})
+
+	t.Run("should execute cached introspection queries like any other operation", func(t *testing.T) {
+		audited = 0
+		for i := 0; i < 2; i++ {
+			operation := newRequest()
+			resultWriter := NewEngineResultWriter()
+			require.NoError(t, engine.Execute(ctx, &operation, &resultWriter, WithApolloTracing(resolve.ApolloTracingFormatLegacy)))
+			assert.Contains(t, resultWriter.String(), `"extensions":{"tracing":`)
+			assert.True(t, operation.IsNormalized())
+		}
+		assert.Equal(t, 2, audited)
+	})
+
+	t.Run("should page the types of the cached introspection data", func(t *testing.T) {
+		operation := newRequest()
+		resultWriter := NewEngineResultWriter()
+		require.NoError(t, engine.Execute(ctx, &operation, &resultWriter, WithIntrospectionTypesPage(introspection_datasource.TypesPage{First: 2, After: "Query"})))
+		assert.Equal(t, `{"data":{"__schema":{"types":[{"name":"Mutation"},{"name":"Subscription"}]}}}`, resultWriter.String())
+
+		operation = newRequest()
+		resultWriter = NewEngineResultWriter()
+		require.NoError(t, engine.Execute(ctx, &operation, &resultWriter, WithIntrospectionTypesPage(introspection_datasource.TypesPage{NamePrefix: "D"})))
+		assert.Equal(t, `{"data":{"__schema":{"types":[{"name":"Droid"}]}}}`, resultWriter.String())
+
+		operation = newRequest()
+		resultWriter = NewEngineResultWriter()
+		require.NoError(t, engine.Execute(ctx, &operation, &resultWriter))
+		assert.Equal(t, expectedResponse, resultWriter.String())
 	})
 }
 
@@ -1353,7 +1544,7 @@ func BenchmarkIntrospection(b *testing.B) {
 	schema := starwarsSchema(b)
 	engineConf := NewEngineV2Configuration(schema)
 
-	expectedResponse := []byte(`{"data":{"__schema":{"queryType":{"name":"Query"},"mutationType":{"name":"Mutation"},"subscriptionType":{"name":"Subscription"},"types":[{"kind":"UNION","name":"SearchResult","description":"","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[{"kind":"OBJECT","name":"Human","ofType":null},{"kind":"OBJECT","name":"Droid","ofType":null},{"kind":"OBJECT","name":"Starship","ofType":null}]},{"kind":"OBJECT","name":"Query","description":"","fields":[{"name":"hero","description":"","args":[],"type":{"kind":"INTERFACE","name":"Character","ofType":null},"isDeprecated":true,"deprecationReason":"No longer supported"},{"name":"droid","description":"","args":[{"name":"id","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"ID","ofType":null}},"defaultValue":null}],"type":{"kind":"OBJECT","name":"Droid","ofType":null},"isDeprecated":false,"deprecationReason":null},{"name":"search","description":"","args":[{"name":"name","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"defaultValue":null}],"type":{"kind":"UNION","name":"SearchResult","ofType":null},"isDeprecated":false,"deprecationReason":null},{"name":"searchResults","description":"","args":[],"type":{"kind":"LIST","name":null,"ofType":{"kind":"UNION","name":"SearchResult","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"OBJECT","name":"Mutation","description":"","fields":[{"name":"createReview","description":"","args":[{"name":"episode","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"ENUM","name":"Episode","ofType":null}},"defaultValue":null},{"name":"review","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"INPUT_OBJECT","name":"ReviewInput","ofType":null}},"defaultValue":null}],"type":{"kind":"OBJECT","name":"Review","ofType":null},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"OBJECT","name":"Subscription","description":"","fields":[{"name":"remainingJedis","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Int","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"INPUT_OBJECT","name":"ReviewInput","description":"","fields":null,"inputFields":[{"name":"stars","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Int","ofType":null}},"defaultValue":null},{"name":"commentary","description":"","type":{"kind":"SCALAR","name":"String","ofType":null},"defaultValue":null}],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"OBJECT","name":"Review","description":"","fields":[{"name":"id","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"ID","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"stars","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Int","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"commentary","description":"","args":[],"type":{"kind":"SCALAR","name":"String","ofType":null},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"ENUM","name":"Episode","description":"","fields":null,"inputFields":[],"interfaces":[],"enumValues":[{"name":"NEWHOPE","description":"","isDeprecated":false,"deprecationReason":null},{"name":"EMPIRE","description":"","isDeprecated":false,"deprecationReason":null},{"name":"JEDI","description":"","isDeprecated":true,"deprecationReason":"No longer supported"}],"possibleTypes":[]},{"kind":"INTERFACE","name":"Character","description":"","fields":[{"name":"name","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"friends","description":"","args":[],"type":{"kind":"LIST","name":null,"ofType":{"kind":"INTERFACE","name":"Character","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[{"kind":"OBJECT","name":"Human","ofType":null},{"kind":"OBJECT","name":"Droid","ofType":null}]},{"kind":"OBJECT","name":"Human","description":"","fields":[{"name":"name","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"height","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":true,"deprecationReason":"No longer supported"},{"name":"friends","description":"","args":[],"type":{"kind":"LIST","name":null,"ofType":{"kind":"INTERFACE","name":"Character","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[{"kind":"INTERFACE","name":"Character","ofType":null}],"enumValues":null,"possibleTypes":[]},{"kind":"OBJECT","name":"Droid","description":"","fields":[{"name":"name","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"primaryFunction","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"friends","description":"","args":[],"type":{"kind":"LIST","name":null,"ofType":{"kind":"INTERFACE","name":"Character","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[{"kind":"INTERFACE","name":"Character","ofType":null}],"enumValues":null,"possibleTypes":[]},{"kind":"INTERFACE","name":"Vehicle","description":"","fields":[{"name":"length","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Float","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[{"kind":"OBJECT","name":"Starship","ofType":null}]},{"kind":"OBJECT","name":"Starship","description":"","fields":[{"name":"name","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"length","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Float","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[{"kind":"INTERFACE","name":"Vehicle","ofType":null}],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"Int","description":"The 'Int' scalar type represents non-fractional signed whole numeric values. Int can represent values between -(2^31) and 2^31 - 1.","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"Float","description":"The 'Float' scalar type represents signed double-precision fractional values as specified by [IEEE 754](http://en.wikipedia.org/wiki/IEEE_floating_point).","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"String","description":"The 'String' scalar type represents textual data, represented as UTF-8 character sequences. The String type is most often used by GraphQL to represent free-form human-readable text.","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"Boolean","description":"The 'Boolean' scalar type represents 'true' or 'false' .","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"ID","description":"The 'ID' scalar type represents a unique identifier, often used to refetch an object or as key for a cache. The ID type appears in a JSON response as a String; however, it is not intended to be human-readable. When expected as an input type, any string (such as '4') or integer (such as 4) input value will be accepted as an ID.","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]}],"directives":[{"name":"include","description":"Directs the executor to include this field or fragment only when the argument is true.","locations":["FIELD","FRAGMENT_SPREAD","INLINE_FRAGMENT"],"args":[{"name":"if","description":"Included when true.","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Boolean","ofType":null}},"defaultValue":null}]},{"name":"skip","description":"Directs the executor to skip this field or fragment when the argument is true.","locations":["FIELD","FRAGMENT_SPREAD","INLINE_FRAGMENT"],"args":[{"name":"if","description":"Skipped when true.","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Boolean","ofType":null}},"defaultValue":null}]},{"name":"deprecated","description":"Marks an element of a GraphQL schema as no longer supported.","locations":["FIELD_DEFINITION","ENUM_VALUE"],"args":[{"name":"reason","description":"Explains why this element was deprecated, usually also including a suggestion\n    for how to access supported similar data. Formatted in\n    [Markdown](https://daringfireball.net/projects/markdown/).","type":{"kind":"SCALAR","name":"String","ofType":null},"defaultValue":"\"No longer supported\""}]}]}}}`)
+	expectedResponse := []byte(`{"data":{"__schema":{"queryType":{"name":"Query"},"mutationType":{"name":"Mutation"},"subscriptionType":{"name":"Subscription"},"types":[{"kind":"UNION","name":"SearchResult","description":"","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[{"kind":"OBJECT","name":"Human","ofType":null},{"kind":"OBJECT","name":"Droid","ofType":null},{"kind":"OBJECT","name":"Starship","ofType":null}]},{"kind":"OBJECT","name":"Query","description":"","fields":[{"name":"hero","description":"","args":[],"type":{"kind":"INTERFACE","name":"Character","ofType":null},"isDeprecated":true,"deprecationReason":"No longer supported"},{"name":"droid","description":"","args":[{"name":"id","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"ID","ofType":null}},"defaultValue":null}],"type":{"kind":"OBJECT","name":"Droid","ofType":null},"isDeprecated":false,"deprecationReason":null},{"name":"search","description":"","args":[{"name":"name","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"defaultValue":null}],"type":{"kind":"UNION","name":"SearchResult","ofType":null},"isDeprecated":false,"deprecationReason":null},{"name":"searchResults","description":"","args":[],"type":{"kind":"LIST","name":null,"ofType":{"kind":"UNION","name":"SearchResult","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"OBJECT","name":"Mutation","description":"","fields":[{"name":"createReview","description":"","args":[{"name":"episode","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"ENUM","name":"Episode","ofType":null}},"defaultValue":null},{"name":"review","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"INPUT_OBJECT","name":"ReviewInput","ofType":null}},"defaultValue":null}],"type":{"kind":"OBJECT","name":"Review","ofType":null},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"OBJECT","name":"Subscription","description":"","fields":[{"name":"remainingJedis","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Int","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"INPUT_OBJECT","name":"ReviewInput","description":"","fields":null,"inputFields":[{"name":"stars","description":"","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Int","ofType":null}},"defaultValue":null},{"name":"commentary","description":"","type":{"kind":"SCALAR","name":"String","ofType":null},"defaultValue":null}],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"OBJECT","name":"Review","description":"","fields":[{"name":"id","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"ID","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"stars","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Int","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"commentary","description":"","args":[],"type":{"kind":"SCALAR","name":"String","ofType":null},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"ENUM","name":"Episode","description":"","fields":null,"inputFields":[],"interfaces":[],"enumValues":[{"name":"NEWHOPE","description":"","isDeprecated":false,"deprecationReason":null},{"name":"EMPIRE","description":"","isDeprecated":false,"deprecationReason":null},{"name":"JEDI","description":"","isDeprecated":true,"deprecationReason":"No longer supported"}],"possibleTypes":[]},{"kind":"INTERFACE","name":"Character","description":"","fields":[{"name":"name","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"friends","description":"","args":[],"type":{"kind":"LIST","name":null,"ofType":{"kind":"INTERFACE","name":"Character","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[{"kind":"OBJECT","name":"Human","ofType":null},{"kind":"OBJECT","name":"Droid","ofType":null}]},{"kind":"OBJECT","name":"Human","description":"","fields":[{"name":"name","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"height","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":true,"deprecationReason":"No longer supported"},{"name":"friends","description":"","args":[],"type":{"kind":"LIST","name":null,"ofType":{"kind":"INTERFACE","name":"Character","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[{"kind":"INTERFACE","name":"Character","ofType":null}],"enumValues":null,"possibleTypes":[]},{"kind":"OBJECT","name":"Droid","description":"","fields":[{"name":"name","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"primaryFunction","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"friends","description":"","args":[],"type":{"kind":"LIST","name":null,"ofType":{"kind":"INTERFACE","name":"Character","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[{"kind":"INTERFACE","name":"Character","ofType":null}],"enumValues":null,"possibleTypes":[]},{"kind":"INTERFACE","name":"Vehicle","description":"","fields":[{"name":"length","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Float","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[{"kind":"OBJECT","name":"Starship","ofType":null}]},{"kind":"OBJECT","name":"Starship","description":"","fields":[{"name":"name","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"isDeprecated":false,"deprecationReason":null},{"name":"length","description":"","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Float","ofType":null}},"isDeprecated":false,"deprecationReason":null}],"inputFields":[],"interfaces":[{"kind":"INTERFACE","name":"Vehicle","ofType":null}],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"Int","description":"The 'Int' scalar type represents non-fractional signed whole numeric values. Int can represent values between -(2^31) and 2^31 - 1.","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"Float","description":"The 'Float' scalar type represents signed double-precision fractional values as specified by [IEEE 754](http://en.wikipedia.org/wiki/IEEE_floating_point).","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"String","description":"The 'String' scalar type represents textual data, represented as UTF-8 character sequences. The String type is most often used by GraphQL to represent free-form human-readable text.","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"Boolean","description":"The 'Boolean' scalar type represents 'true' or 'false' .","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]},{"kind":"SCALAR","name":"ID","description":"The 'ID' scalar type represents a unique identifier, often used to refetch an object or as key for a cache. The ID type appears in a JSON response as a String; however, it is not intended to be human-readable. When expected as an input type, any string (such as '4') or integer (such as 4) input value will be accepted as an ID.","fields":null,"inputFields":[],"interfaces":[],"enumValues":null,"possibleTypes":[]}],"directives":[{"name":"include","description":"Directs the executor to include this field or fragment only when the argument is true.","locations":["FIELD","FRAGMENT_SPREAD","INLINE_FRAGMENT"],"args":[{"name":"if","description":"Included when true.","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Boolean","ofType":null}},"defaultValue":null}]},{"name":"skip","description":"Directs the executor to skip this field or fragment when the argument is true.","locations":["FIELD","FRAGMENT_SPREAD","INLINE_FRAGMENT"],"args":[{"name":"if","description":"Skipped when true.","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Boolean","ofType":null}},"defaultValue":null}]},{"name":"deprecated","description":"Marks an element of a GraphQL schema as no longer supported.","locations":["FIELD_DEFINITION","ENUM_VALUE"],"args":[{"name":"reason","description":"Explains why this element was deprecated, usually also including a suggestion\n    for how to access supported similar data. Formatted in\n    [Markdown](https://daringfireball.net/projects/markdown/).","type":{"kind":"SCALAR","name":"String","ofType":null},"defaultValue":"\"No longer supported\""}]},{"name":"specifiedBy","description":"Exposes a URL that specifies the behavior of this scalar.","locations":["SCALAR"],"args":[{"name":"url","description":"The URL that specifies the behavior of this scalar.","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}},"defaultValue":null}]}]}}}`)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -1506,6 +1697,38 @@ func newFederationSetup() *federationSetup {
 }
 
 func newFederationEngine(ctx context.Context, setup *federationSetup) (engine *ExecutionEngineV2, schema *Schema, err error) {
+	dataSources, fieldConfigs, err := federationDataSources(setup)
+	if err != nil {
+		return
+	}
+
+	schema, err = federationSchema()
+	if err != nil {
+		return
+	}
+
+	engineConfig := NewEngineV2Configuration(schema)
+	engineConfig.SetDataSources(dataSources)
+	engineConfig.SetFieldConfigurations(fieldConfigs)
+
+	engineConfig.plannerConfig.Debug = plan.DebugConfiguration{
+		PrintOperationTransformations: false,
+		PrintPlanningPaths:            false,
+		PrintQueryPlans:               false,
+		ConfigurationVisitor:          false,
+		PlanningVisitor:               false,
+		DatasourceVisitor:             false,
+	}
+
+	engine, err = NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConfig)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func federationDataSources(setup *federationSetup) (dataSources []plan.DataSourceConfiguration, fieldConfigs plan.FieldConfigurations, err error) {
 	accountsSDL, err := federationtesting.LoadTestingSubgraphSDL(federationtesting.UpstreamAccounts)
 	if err != nil {
 		return
@@ -1725,7 +1948,7 @@ func newFederationEngine(ctx context.Context, setup *federationSetup) (engine *E
 		},
 	}
 
-	fieldConfigs := plan.FieldConfigurations{
+	fieldConfigs = plan.FieldConfigurations{
 		{
 			TypeName:  "Query",
 			FieldName: "topProducts",
@@ -1780,37 +2003,16 @@ func newFederationEngine(ctx context.Context, setup *federationSetup) (engine *E
 		},
 	}
 
-	schema, err = federationSchema()
-	if err != nil {
-		return
-	}
-
-	engineConfig := NewEngineV2Configuration(schema)
-	engineConfig.AddDataSource(accountsDataSource)
-	engineConfig.AddDataSource(productsDataSource)
-	engineConfig.AddDataSource(reviewsDataSource)
-	engineConfig.SetFieldConfigurations(fieldConfigs)
-
-	engineConfig.plannerConfig.Debug = plan.DebugConfiguration{
-		PrintOperationTransformations: false,
-		PrintPlanningPaths:            false,
-		PrintQueryPlans:               false,
-		ConfigurationVisitor:          false,
-		PlanningVisitor:               false,
-		DatasourceVisitor:             false,
-	}
-
-	engine, err = NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConfig)
-	if err != nil {
-		return
-	}
-
-	return
+	dataSources = []plan.DataSourceConfiguration{accountsDataSource, productsDataSource, reviewsDataSource}
+	return dataSources, fieldConfigs, nil
 }
 
 // nolint
 func federationSchema() (*Schema, error) {
-	rawSchema := `
+	return NewSchemaFromString(federationRawSchema)
+}
+
+const federationRawSchema = `
 type Query {
 	me: User
 	topProducts(first: Int = 5): [Product]
@@ -1848,7 +2050,352 @@ type Review {
 }
 `
 
-	return NewSchemaFromString(rawSchema)
+func TestExecutionEngineV2_NodeDataSource(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	setup := newFederationSetup()
+	defer setup.accountsUpstreamServer.Close()
+	defer setup.productsUpstreamServer.Close()
+	defer setup.reviewsUpstreamServer.Close()
+	defer setup.pollingUpstreamServer.Close()
+
+	dataSources, fieldConfigs, err := federationDataSources(setup)
+	require.NoError(t, err)
+
+	schema, err := NewSchemaFromString(`
+		interface Node { id: ID! }
+		type Query {
+			me: User
+			node(id: ID!): Node
+		}
+		type User implements Node {
+			id: ID!
+			username: String
+		}
+		type Product implements Node {
+			id: ID!
+			upc: String!
+			name: String
+			price: Int
+		}
+	`)
+	require.NoError(t, err)
+
+	engineConf := NewEngineV2Configuration(schema)
+	engineConf.SetDataSources(append(dataSources, node_datasource.NewDataSourceConfiguration("node", "Query", node_datasource.Configuration{
+		Types: []node_datasource.NodeType{
+			{TypeName: "User", KeyFields: []string{"id"}},
+			{TypeName: "Product", KeyFields: []string{"upc"}},
+		},
+	})))
+	engineConf.SetFieldConfigurations(fieldConfigs)
+
+	engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+	require.NoError(t, err)
+
+	execute := func(t *testing.T, query string, typeName string, key string) string {
+		t.Helper()
+
+		id, err := node_datasource.EncodeGlobalID(typeName, []byte(key))
+		require.NoError(t, err)
+
+		operation := Request{Query: query, Variables: []byte(`{"id":"` + id + `"}`)}
+		resultWriter := NewEngineResultWriter()
+		require.NoError(t, engine.Execute(ctx, &operation, &resultWriter))
+		return resultWriter.String()
+	}
+
+	const query = `query($id: ID!) { node(id: $id) { __typename ... on User { id username } ... on Product { upc name price } } }`
+
+	t.Run("should load users from the accounts subgraph", func(t *testing.T) {
+		assert.Equal(t, `{"data":{"node":{"__typename":"User","id":"1234","username":"Me"}}}`, execute(t, query, "User", `{"id":"1234"}`))
+	})
+
+	t.Run("should load products from the products subgraph", func(t *testing.T) {
+		assert.Equal(t, `{"data":{"node":{"__typename":"Product","upc":"top-2","name":"Fedora","price":22}}}`, execute(t, query, "Product", `{"upc":"top-2"}`))
+	})
+
+	t.Run("should resolve unknown types to null", func(t *testing.T) {
+		assert.Equal(t, `{"data":{"node":null}}`, execute(t, query, "Review", `{"id":"1"}`))
+	})
+}
+
+func TestExecutionEngineV2_FederatedSubscription(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	setup := newFederationSetup()
+	defer setup.accountsUpstreamServer.Close()
+	defer setup.productsUpstreamServer.Close()
+	defer setup.reviewsUpstreamServer.Close()
+	defer setup.pollingUpstreamServer.Close()
+
+	engine, _, err := newFederationEngine(ctx, setup)
+	require.NoError(t, err)
+
+	messages := make(chan string, 16)
+	resultWriter := NewEngineResultWriter()
+	resultWriter.SetFlushCallback(func(data []byte) {
+		select {
+		case messages <- string(data):
+		default:
+		}
+	})
+
+	subscriptionCtx, cancelSubscription := context.WithCancel(ctx)
+	defer cancelSubscription()
+
+	// updatedPrice is resolved by the products subgraph, the reviews of the product are fetched from the reviews subgraph for each event
+	operation := Request{Query: `subscription { updatedPrice { upc name price reviews { body } } }`}
+	require.NoError(t, engine.Execute(subscriptionCtx, &operation, &resultWriter))
+
+	for i := 0; i < 2; i++ {
+		select {
+		case message := <-messages:
+			assert.Regexp(t, `^{"data":{"updatedPrice":{"upc":"top-1","name":"Trilby","price":\d+,"reviews":\[{"body":"A highly effective form of birth control."}\]}}}$`, message)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the subscription event")
+		}
+	}
+}
+
+func TestExecutionEngineV2_ExplainDataSourceSelection(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	setup := newFederationSetup()
+	defer setup.accountsUpstreamServer.Close()
+	defer setup.productsUpstreamServer.Close()
+	defer setup.reviewsUpstreamServer.Close()
+	defer setup.pollingUpstreamServer.Close()
+
+	engine, _, err := newFederationEngine(ctx, setup)
+	require.NoError(t, err)
+
+	t.Run("should explain the datasources of the fields", func(t *testing.T) {
+		selections, err := engine.ExplainDataSourceSelection(&Request{Query: `{ topProducts { name reviews { body } } }`})
+		require.NoError(t, err)
+
+		// the datasources are identified by their index: accounts #0, products #1, reviews #2
+		assert.Equal(t, []plan.FieldDataSourceSelection{
+			{TypeName: "Query", FieldName: "topProducts", Path: "query.topProducts", Candidates: []plan.DataSourceCandidate{
+				{DataSourceID: "#1", IsRootNode: true, Selected: true, SelectionReasons: []string{plan.ReasonStage1Unique}},
+			}},
+			{TypeName: "Product", FieldName: "name", Path: "query.topProducts.name", Candidates: []plan.DataSourceCandidate{
+				{DataSourceID: "#1", IsRootNode: true, Selected: true, SelectionReasons: []string{plan.ReasonStage1SameSourceLeafChild}},
+			}},
+			{TypeName: "Product", FieldName: "reviews", Path: "query.topProducts.reviews", Candidates: []plan.DataSourceCandidate{
+				{DataSourceID: "#2", IsRootNode: true, Selected: true, SelectionReasons: []string{plan.ReasonStage1Unique}},
+			}},
+			{TypeName: "Review", FieldName: "body", Path: "query.topProducts.reviews.body", Candidates: []plan.DataSourceCandidate{
+				{DataSourceID: "#2", IsRootNode: false, Selected: true, SelectionReasons: []string{plan.ReasonStage1SameSourceLeafChild}},
+			}},
+			{TypeName: "Product", FieldName: "__typename", Path: "query.topProducts.__typename", Candidates: []plan.DataSourceCandidate{
+				{DataSourceID: "#0", IsRootNode: true},
+				{DataSourceID: "#1", IsRootNode: true, Selected: true, SelectionReasons: []string{plan.ReasonStage2SameSourceNodeOfSelectedParent}},
+				{DataSourceID: "#2", IsRootNode: true},
+			}},
+			{TypeName: "Product", FieldName: "upc", Path: "query.topProducts.upc", Candidates: []plan.DataSourceCandidate{
+				{DataSourceID: "#0", IsRootNode: true},
+				{DataSourceID: "#1", IsRootNode: true, Selected: true, SelectionReasons: []string{plan.ReasonKeyRequirementProvidedByPlanner}},
+				{DataSourceID: "#2", IsRootNode: true},
+			}},
+		}, selections)
+	})
+
+	t.Run("should reject invalid operations", func(t *testing.T) {
+		_, err := engine.ExplainDataSourceSelection(&Request{Query: `{ topProducts { unknown } }`})
+		assert.Error(t, err)
+	})
+}
+
+func TestExecutionEngineV2_DataSourceDirective(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	setup := newFederationSetup()
+	defer setup.accountsUpstreamServer.Close()
+	defer setup.productsUpstreamServer.Close()
+	defer setup.reviewsUpstreamServer.Close()
+	defer setup.pollingUpstreamServer.Close()
+
+	dataSources, fieldConfigs, err := federationDataSources(setup)
+	require.NoError(t, err)
+	for i, id := range []string{"accounts", "products", "reviews"} {
+		dataSources[i].ID = id
+	}
+
+	schema, err := NewSchemaFromString(federationRawSchema + `directive @datasource(id: String!) on FIELD`)
+	require.NoError(t, err)
+
+	engineConfig := NewEngineV2Configuration(schema)
+	engineConfig.SetDataSources(dataSources)
+	engineConfig.SetFieldConfigurations(fieldConfigs)
+	engineConfig.SetDataSourceDirective(plan.DataSourceDirectiveConfiguration{Enabled: true})
+
+	engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConfig)
+	require.NoError(t, err)
+
+	t.Run("should resolve the field with the pinned datasource", func(t *testing.T) {
+		request := Request{Query: `{ me @datasource(id: "reviews") { id } }`}
+
+		selections, err := engine.ExplainDataSourceSelection(&request)
+		require.NoError(t, err)
+		require.NotEmpty(t, selections)
+		assert.Equal(t, plan.FieldDataSourceSelection{TypeName: "Query", FieldName: "me", Path: "query.me", Candidates: []plan.DataSourceCandidate{
+			{DataSourceID: "accounts", IsRootNode: true},
+			{DataSourceID: "reviews", IsRootNode: true, Selected: true, SelectionReasons: []string{plan.ReasonDataSourceDirective}},
+		}}, selections[0])
+
+		// the upstream rejects unknown directives, so the response proves that the directive is not sent
+		resultWriter := NewEngineResultWriter()
+		require.NoError(t, engine.Execute(ctx, &request, &resultWriter))
+		assert.Equal(t, `{"data":{"me":{"id":"1234"}}}`, resultWriter.String())
+	})
+
+	t.Run("should reject invalid directives", func(t *testing.T) {
+		for _, testCase := range []struct {
+			name, query, expectedErr string
+		}{
+			{
+				name:        "unknown datasource",
+				query:       `{ me @datasource(id: "inventory") { id } }`,
+				expectedErr: `Directive "@datasource" on field "me" is invalid: unknown datasource "inventory".`,
+			},
+			{
+				name:        "datasource can't resolve the field",
+				query:       `{ me @datasource(id: "products") { id } }`,
+				expectedErr: `Directive "@datasource" on field "me" is invalid: the datasource "products" can't resolve the field Query.me.`,
+			},
+			{
+				name:        "variable id",
+				query:       `query($id: String!) { me @datasource(id: $id) { id } }`,
+				expectedErr: `Directive "@datasource" on field "me" is invalid: the id has to be a string literal.`,
+			},
+		} {
+			t.Run(testCase.name, func(t *testing.T) {
+				request := Request{Query: testCase.query, Variables: []byte(`{"id":"reviews"}`)}
+				resultWriter := NewEngineResultWriter()
+				err := engine.Execute(ctx, &request, &resultWriter)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), testCase.expectedErr)
+			})
+		}
+	})
+}
+
+func TestExecutionEngineV2_DataSourceMetrics(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	setup := newFederationSetup()
+	defer setup.accountsUpstreamServer.Close()
+	defer setup.productsUpstreamServer.Close()
+	defer setup.reviewsUpstreamServer.Close()
+	defer setup.pollingUpstreamServer.Close()
+
+	dataSources, fieldConfigs, err := federationDataSources(setup)
+	require.NoError(t, err)
+	for i, id := range []string{"accounts", "products", "reviews"} {
+		dataSources[i].ID = id
+	}
+
+	schema, err := federationSchema()
+	require.NoError(t, err)
+
+	telemetry := resolve.NewDataSourceTelemetry()
+	engineConfig := NewEngineV2Configuration(schema)
+	engineConfig.SetDataSources(dataSources)
+	engineConfig.SetFieldConfigurations(fieldConfigs)
+	engineConfig.SetDataSourceMetrics(telemetry)
+
+	engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConfig)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		resultWriter := NewEngineResultWriter()
+		require.NoError(t, engine.Execute(ctx, &Request{Query: `{ topProducts { upc reviews { body } } }`}, &resultWriter))
+	}
+
+	snapshot := telemetry.Snapshot()
+	require.Len(t, snapshot, 2)
+	for _, id := range []string{"products", "reviews"} {
+		dataSource := snapshot[id]
+		assert.Equal(t, uint64(2), dataSource.Requests, id)
+		assert.Equal(t, uint64(0), dataSource.Errors, id)
+		assert.Greater(t, dataSource.RequestBytes, uint64(0), id)
+		assert.Greater(t, dataSource.ResponseBytes, uint64(0), id)
+	}
+}
+
+func TestExecutionEngineV2_SchemaContracts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	setup := newFederationSetup()
+	defer setup.accountsUpstreamServer.Close()
+	defer setup.productsUpstreamServer.Close()
+	defer setup.reviewsUpstreamServer.Close()
+	defer setup.pollingUpstreamServer.Close()
+
+	dataSources, fieldConfigs, err := federationDataSources(setup)
+	require.NoError(t, err)
+
+	schema, err := federationSchema()
+	require.NoError(t, err)
+	publicSchema, err := NewSchemaFromString(`
+		type Query { topProducts(first: Int = 5): [Product] }
+		type Product { upc: String! name: String }`)
+	require.NoError(t, err)
+
+	engineConfig := NewEngineV2Configuration(schema)
+	engineConfig.SetDataSources(dataSources)
+	engineConfig.SetFieldConfigurations(fieldConfigs)
+	engineConfig.EnableIntrospectionCache(true)
+	engineConfig.AddSchemaContract("public", publicSchema)
+
+	engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConfig)
+	require.NoError(t, err)
+
+	execute := func(contract, query string) (string, error) {
+		resultWriter := NewEngineResultWriter()
+		err := engine.ExecuteWithContract(ctx, contract, &Request{Query: query}, &resultWriter)
+		return resultWriter.String(), err
+	}
+
+	t.Run("should resolve the fields of the contract", func(t *testing.T) {
+		response, err := execute("public", `{ topProducts { upc name } }`)
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"topProducts":[{"upc":"top-1","name":"Trilby"},{"upc":"top-2","name":"Fedora"},{"upc":"top-3","name":"Boater"}]}}`, response)
+	})
+
+	t.Run("should validate the operation with the schema of the contract", func(t *testing.T) {
+		_, err := execute("public", `{ topProducts { upc price } }`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `field: price not defined on type: Product`)
+
+		response, err := execute("", `{ topProducts { upc price } }`)
+		require.NoError(t, err)
+		assert.Regexp(t, `^{"data":{"topProducts":\[{"upc":"top-1","price":\d+},{"upc":"top-2","price":\d+},{"upc":"top-3","price":\d+}\]}}$`, response)
+	})
+
+	t.Run("should introspect the schema of the contract", func(t *testing.T) {
+		query := `{ __type(name: "Product") { fields { name } } }`
+
+		response, err := execute("public", query)
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"__type":{"fields":[{"name":"upc"},{"name":"name"}]}}}`, response)
+
+		response, err = execute("", query)
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"__type":{"fields":[{"name":"upc"},{"name":"name"},{"name":"price"},{"name":"weight"},{"name":"reviews"}]}}}`, response)
+	})
+
+	t.Run("should return an error for an unknown contract", func(t *testing.T) {
+		_, err := execute("partner", `{ topProducts { upc } }`)
+		assert.EqualError(t, err, `unknown schema contract "partner"`)
+	})
 }
 
 func newPollingUpstreamHandler() http.Handler {
@@ -1865,3 +2412,459 @@ func createCountriesSchema(t *testing.T) *Schema {
 	require.NoError(t, err)
 	return schema
 }
+
+func TestExecutionEngineV2_PIIMasking(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	schema, err := NewSchemaFromString(`
+		directive @pii on FIELD_DEFINITION | ARGUMENT_DEFINITION
+		type Query { user: User }
+		type User { name: String email: String @pii }
+	`)
+	require.NoError(t, err)
+
+	engineConf := NewEngineV2Configuration(schema)
+	engineConf.SetDataSources([]plan.DataSourceConfiguration{
+		{
+			RootNodes: []plan.TypeField{
+				{TypeName: "Query", FieldNames: []string{"user"}},
+			},
+			ChildNodes: []plan.TypeField{
+				{TypeName: "User", FieldNames: []string{"name", "email"}},
+			},
+			Factory: &staticdatasource.Factory{},
+			Custom: staticdatasource.ConfigJSON(staticdatasource.Configuration{
+				Data: `{"user":{"name":"Jens","email":"jens@example.com"}}`,
+			}),
+		},
+	})
+	engineConf.SetPIIMaskedRoles("support")
+
+	engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+	require.NoError(t, err)
+
+	execute := func(t *testing.T, options ...ExecutionOptionsV2) string {
+		operation := Request{Query: `{ user { name email } }`}
+		resultWriter := NewEngineResultWriter()
+		require.NoError(t, engine.Execute(ctx, &operation, &resultWriter, options...))
+		return resultWriter.String()
+	}
+
+	assert.Equal(t, `{"data":{"user":{"name":"Jens","email":"jens@example.com"}}}`, execute(t, WithRoles("admin")))
+	assert.Equal(t, `{"data":{"user":{"name":"Jens","email":"****"}}}`, execute(t, WithRoles("admin", "support")))
+	assert.Equal(t, `{"data":{"user":{"name":"Jens","email":"jens@example.com"}}}`, execute(t))
+}
+
+type prefixFieldValueTransformer struct{}
+
+func (prefixFieldValueTransformer) Encode(ctx *resolve.Context, coordinate resolve.GraphCoordinate, value []byte) ([]byte, error) {
+	return []byte(`"` + coordinate.TypeName + `:` + string(value[1:])), nil
+}
+
+func (prefixFieldValueTransformer) Decode(ctx *resolve.Context, coordinate resolve.ArgumentCoordinate, value []byte) ([]byte, error) {
+	return value, nil
+}
+
+func TestExecutionEngineV2_FieldValueTransformer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	schema, err := NewSchemaFromString(`
+		type Query { user: User }
+		type User { id: ID name: String }
+	`)
+	require.NoError(t, err)
+
+	engineConf := NewEngineV2Configuration(schema)
+	engineConf.SetDataSources([]plan.DataSourceConfiguration{
+		{
+			RootNodes: []plan.TypeField{
+				{TypeName: "Query", FieldNames: []string{"user"}},
+			},
+			ChildNodes: []plan.TypeField{
+				{TypeName: "User", FieldNames: []string{"id", "name"}},
+			},
+			Factory: &staticdatasource.Factory{},
+			Custom: staticdatasource.ConfigJSON(staticdatasource.Configuration{
+				Data: `{"user":{"id":"1","name":"Jens"}}`,
+			}),
+		},
+	})
+	engineConf.SetFieldConfigurations(plan.FieldConfigurations{
+		{TypeName: "User", FieldName: "id", EncodeValue: true},
+	})
+	engineConf.SetFieldValueTransformer(prefixFieldValueTransformer{})
+
+	engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+	require.NoError(t, err)
+
+	operation := Request{Query: `{ user { id name } }`}
+	resultWriter := NewEngineResultWriter()
+	require.NoError(t, engine.Execute(ctx, &operation, &resultWriter))
+	assert.Equal(t, `{"data":{"user":{"id":"User:1","name":"Jens"}}}`, resultWriter.String())
+}
+
+func TestExecutionEngineV2_VariablesLimits(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	schema, err := NewSchemaFromString(`type Query { hello(name: String): String }`)
+	require.NoError(t, err)
+
+	engineConf := NewEngineV2Configuration(schema)
+	engineConf.SetDataSources([]plan.DataSourceConfiguration{
+		{
+			RootNodes: []plan.TypeField{
+				{TypeName: "Query", FieldNames: []string{"hello"}},
+			},
+			Factory: &staticdatasource.Factory{},
+			Custom: staticdatasource.ConfigJSON(staticdatasource.Configuration{
+				Data: `{"hello":"world"}`,
+			}),
+		},
+	})
+	engineConf.SetVariablesLimits(VariablesLimits{MaxSize: 32})
+
+	engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+	require.NoError(t, err)
+
+	execute := func(variables string) (string, error) {
+		operation := Request{Query: `query($name: String) { hello(name: $name) }`, Variables: []byte(variables)}
+		resultWriter := NewEngineResultWriter()
+		err := engine.Execute(ctx, &operation, &resultWriter)
+		return resultWriter.String(), err
+	}
+
+	result, err := execute(`{"name":"Jens"}`)
+	require.NoError(t, err)
+	assert.Equal(t, `{"data":{"hello":"world"}}`, result)
+
+	_, err = execute(`{"name":"` + strings.Repeat("a", 32) + `"}`)
+	require.IsType(t, RequestErrors{}, err)
+	assert.Equal(t, "variables exceed the maximum size of 32 bytes", err.(RequestErrors)[0].Message)
+}
+
+func TestExecutionEngineV2_ShareableConflicts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	schema, err := NewSchemaFromString(`type Query { hello: String }`)
+	require.NoError(t, err)
+
+	dataSource := func(id string) plan.DataSourceConfiguration {
+		return plan.DataSourceConfiguration{
+			ID: id,
+			RootNodes: []plan.TypeField{
+				{TypeName: "Query", FieldNames: []string{"hello"}},
+			},
+			Factory: &staticdatasource.Factory{},
+			Custom: staticdatasource.ConfigJSON(staticdatasource.Configuration{
+				Data: `{"hello":"world"}`,
+			}),
+		}
+	}
+
+	engineConf := NewEngineV2Configuration(schema)
+	engineConf.SetDataSources([]plan.DataSourceConfiguration{dataSource("first"), dataSource("second")})
+
+	t.Run("warn", func(t *testing.T) {
+		_, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+		assert.NoError(t, err)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		engineConf.SetShareableConflictPolicy(ShareableConflictPolicyError)
+		_, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+		assert.EqualError(t, err, "field Query.hello is resolved by the datasources first, second, but it is not shareable")
+	})
+}
+
+func TestExecutionEngineV2_RequiresCycles(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	schema, err := NewSchemaFromString(`
+		type Query { product: Product }
+		type Product { upc: String! weight: Int! shippingEstimate: Int! }`)
+	require.NoError(t, err)
+
+	dataSource := func(id string, fieldName string, requires string) plan.DataSourceConfiguration {
+		return plan.DataSourceConfiguration{
+			ID: id,
+			RootNodes: []plan.TypeField{
+				{TypeName: "Product", FieldNames: []string{"upc", fieldName}},
+			},
+			Factory: &staticdatasource.Factory{},
+			Custom: staticdatasource.ConfigJSON(staticdatasource.Configuration{
+				Data: `{"id":"` + id + `"}`,
+			}),
+			FederationMetaData: plan.FederationMetaData{
+				Keys: plan.FederationFieldConfigurations{
+					{TypeName: "Product", SelectionSet: "upc"},
+				},
+				Requires: plan.FederationFieldConfigurations{
+					{TypeName: "Product", FieldName: fieldName, SelectionSet: requires},
+				},
+			},
+		}
+	}
+
+	engineConf := NewEngineV2Configuration(schema)
+	engineConf.SetDataSources([]plan.DataSourceConfiguration{
+		dataSource("products", "weight", "shippingEstimate"),
+		dataSource("inventory", "shippingEstimate", "weight"),
+	})
+
+	_, err = NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+	assert.EqualError(t, err, "dependency cycle between datasources: Product.shippingEstimate (inventory) -> Product.weight (products) -> Product.shippingEstimate (inventory)")
+}
+
+func TestExecutionEngineV2_NormalizationFlags(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var upstreamQuery string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		upstreamQuery = string(body)
+		_, _ = w.Write([]byte(`{"data":{"user":{"__typename":"User","id":"1","name":"Jens"}}}`))
+	}))
+	defer upstream.Close()
+
+	const sdl = `
+		type Query { user(id: ID!): User }
+		type User { id: ID! name: String }
+	`
+	schema, err := NewSchemaFromString(sdl)
+	require.NoError(t, err)
+
+	newEngine := func(t *testing.T, flags NormalizationFlags) *ExecutionEngineV2 {
+		t.Helper()
+		engineConf := NewEngineV2Configuration(schema)
+		engineConf.SetDataSources([]plan.DataSourceConfiguration{
+			{
+				RootNodes: []plan.TypeField{
+					{TypeName: "Query", FieldNames: []string{"user"}},
+				},
+				ChildNodes: []plan.TypeField{
+					{TypeName: "User", FieldNames: []string{"id", "name"}},
+				},
+				Custom: graphql_datasource.ConfigJson(graphql_datasource.Configuration{
+					Fetch: graphql_datasource.FetchConfiguration{
+						URL: upstream.URL,
+					},
+					UpstreamSchema: sdl,
+				}),
+				Factory: &graphql_datasource.Factory{
+					HTTPClient: upstream.Client(),
+				},
+			},
+		})
+		engineConf.SetFieldConfigurations(plan.FieldConfigurations{
+			{
+				TypeName:  "Query",
+				FieldName: "user",
+				Arguments: []plan.ArgumentConfiguration{
+					{Name: "id", SourceType: plan.FieldArgumentSource},
+				},
+			},
+		})
+		engineConf.SetNormalizationFlags(flags)
+		engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+		require.NoError(t, err)
+		return engine
+	}
+
+	const query = `query User { user(id: "1") { ... on User { id } name @include(if: true) } }`
+
+	execute := func(t *testing.T, engine *ExecutionEngineV2, options ...ExecutionOptionsV2) string {
+		t.Helper()
+		operation := Request{Query: query}
+		resultWriter := NewEngineResultWriter()
+		require.NoError(t, engine.Execute(ctx, &operation, &resultWriter, options...))
+		assert.Equal(t, `{"data":{"user":{"id":"1","name":"Jens"}}}`, resultWriter.String())
+		return upstreamQuery
+	}
+
+	allFlags := NormalizationFlags{
+		DisableRemoveUnusedVariables: true,
+		DisableInlineFragments:       true,
+		DisableExtractVariables:      true,
+		DisableRemoveSkippedFields:   true,
+	}
+
+	t.Run("should normalize the operation by default", func(t *testing.T) {
+		assert.Equal(t, `{"query":"query($a: ID!){user(id: $a){id name}}","variables":{"a":"1"}}`, execute(t, newEngine(t, NormalizationFlags{})))
+	})
+
+	t.Run("should keep the shape of the operation with the flags of the engine", func(t *testing.T) {
+		assert.Equal(t, `{"query":"{user(id: \"1\"){__typename id name}}"}`, execute(t, newEngine(t, allFlags)))
+	})
+
+	t.Run("should override the flags of the engine per request", func(t *testing.T) {
+		engine := newEngine(t, NormalizationFlags{})
+		assert.Equal(t, `{"query":"{user(id: \"1\"){__typename id name}}"}`, execute(t, engine, WithNormalizationFlags(allFlags)))
+		assert.Equal(t, `{"query":"query($a: ID!){user(id: $a){id name}}","variables":{"a":"1"}}`, execute(t, engine))
+	})
+}
+
+func TestExecutionEngineV2_RepeatableDirectives(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var upstreamQuery string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		upstreamQuery = string(body)
+		_, _ = w.Write([]byte(`{"data":{"user":{"id":"1","name":"Jens"}}}`))
+	}))
+	defer upstream.Close()
+
+	const sdl = `
+		directive @tag(name: String!) repeatable on QUERY | FIELD
+		directive @once on FIELD
+		type Query { user(id: ID!): User }
+		type User { id: ID! name: String }
+	`
+	schema, err := NewSchemaFromString(sdl)
+	require.NoError(t, err)
+
+	engineConf := NewEngineV2Configuration(schema)
+	engineConf.SetDataSources([]plan.DataSourceConfiguration{
+		{
+			RootNodes: []plan.TypeField{
+				{TypeName: "Query", FieldNames: []string{"user"}},
+			},
+			ChildNodes: []plan.TypeField{
+				{TypeName: "User", FieldNames: []string{"id", "name"}},
+			},
+			Custom: graphql_datasource.ConfigJson(graphql_datasource.Configuration{
+				Fetch: graphql_datasource.FetchConfiguration{
+					URL: upstream.URL,
+				},
+				UpstreamSchema: sdl,
+			}),
+			Factory: &graphql_datasource.Factory{
+				HTTPClient: upstream.Client(),
+			},
+		},
+	})
+	engineConf.SetFieldConfigurations(plan.FieldConfigurations{
+		{
+			TypeName:  "Query",
+			FieldName: "user",
+			Arguments: []plan.ArgumentConfiguration{
+				{Name: "id", SourceType: plan.FieldArgumentSource},
+			},
+		},
+	})
+	engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+	require.NoError(t, err)
+
+	execute := func(t *testing.T, query, variables string) (string, error) {
+		t.Helper()
+		upstreamQuery = ""
+		operation := Request{Query: query, Variables: []byte(variables)}
+		resultWriter := NewEngineResultWriter()
+		err := engine.Execute(ctx, &operation, &resultWriter)
+		return resultWriter.String(), err
+	}
+
+	t.Run("should forward every application of a repeatable directive", func(t *testing.T) {
+		out, err := execute(t, `query User @tag(name: "q1") @tag(name: "q2") { user(id: "1") @tag(name: "a") @tag(name: "a") { id @tag(name: "b") @tag(name: "c") name } }`, "")
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"user":{"id":"1","name":"Jens"}}}`, out)
+		assert.Equal(t, `{"query":"query($a: ID!)@tag(name: \"q1\") @tag(name: \"q2\") {user(id: $a)@tag(name: \"a\") @tag(name: \"a\") {id @tag(name: \"b\") @tag(name: \"c\") name}}","variables":{"a":"1"}}`, upstreamQuery)
+	})
+
+	t.Run("should merge fields differing only in repeatable directives into a single response field", func(t *testing.T) {
+		out, err := execute(t, `query User($skip: Boolean!) { user(id: "1") { id @tag(name: "a") id @tag(name: "b") name @skip(if: $skip) } }`, `{"skip":false}`)
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"user":{"id":"1","name":"Jens"}}}`, out)
+		assert.Equal(t, `{"query":"query($a: ID!, $skip: Boolean!){user(id: $a){id @tag(name: \"a\") id @tag(name: \"b\") name @skip(if: $skip)}}","variables":{"skip":false,"a":"1"}}`, upstreamQuery)
+	})
+
+	t.Run("should reject a repeated non repeatable directive", func(t *testing.T) {
+		_, err := execute(t, `query User { user(id: "1") { id @once @once } }`, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `The directive "@once" can only be used once at this location.`)
+		assert.Empty(t, upstreamQuery)
+	})
+}
+
+func TestExecutionEngineV2_UpstreamResponseHeaders(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Set-Cookie", "session=1")
+		w.Header().Set("X-Upstream", "users")
+		_, _ = w.Write([]byte(`{"data":{"user":{"id":"1","name":"Jens"}}}`))
+	}))
+	defer upstream.Close()
+
+	const sdl = `
+		type Query { user(id: ID!): User }
+		type User { id: ID! name: String }
+	`
+	schema, err := NewSchemaFromString(sdl)
+	require.NoError(t, err)
+
+	engineConf := NewEngineV2Configuration(schema)
+	engineConf.SetDataSources([]plan.DataSourceConfiguration{
+		{
+			RootNodes: []plan.TypeField{
+				{TypeName: "Query", FieldNames: []string{"user"}},
+			},
+			ChildNodes: []plan.TypeField{
+				{TypeName: "User", FieldNames: []string{"id", "name"}},
+			},
+			Custom: graphql_datasource.ConfigJson(graphql_datasource.Configuration{
+				Fetch: graphql_datasource.FetchConfiguration{
+					URL: upstream.URL,
+				},
+				UpstreamSchema: sdl,
+			}),
+			Factory: &graphql_datasource.Factory{
+				HTTPClient: upstream.Client(),
+			},
+		},
+	})
+	engineConf.SetFieldConfigurations(plan.FieldConfigurations{
+		{
+			TypeName:  "Query",
+			FieldName: "user",
+			Arguments: []plan.ArgumentConfiguration{
+				{Name: "id", SourceType: plan.FieldArgumentSource},
+			},
+		},
+	})
+	engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+	require.NoError(t, err)
+
+	execute := func(t *testing.T, headers *resolve.ResponseHeaders) {
+		t.Helper()
+		operation := Request{Query: `{ user(id: "1") { id name } }`}
+		resultWriter := NewEngineResultWriter()
+		require.NoError(t, engine.Execute(ctx, &operation, &resultWriter, WithResponseHeaders(headers)))
+		assert.Equal(t, `{"data":{"user":{"id":"1","name":"Jens"}}}`, resultWriter.String())
+	}
+
+	t.Run("should forward the configured headers of the upstream responses", func(t *testing.T) {
+		headers := resolve.NewResponseHeaders(resolve.ResponseHeaderOptions{
+			UpstreamHeaders: []string{"Set-Cookie"},
+		})
+		execute(t, headers)
+
+		response := httptest.NewRecorder()
+		headers.Apply(response.Header())
+		assert.Equal(t, http.Header{"Set-Cookie": {"session=1"}}, response.Header())
+	})
+
+	t.Run("should not forward headers of upstream responses by default", func(t *testing.T) {
+		headers := resolve.NewResponseHeaders(resolve.ResponseHeaderOptions{})
+		execute(t, headers)
+		assert.Equal(t, http.Header{}, headers.Header())
+	})
+}
@@ -0,0 +1,10 @@
+package graphql
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/introspection"
+)
+
+const introspectionCacheSize = 64
+
+// IntrospectionFilter modifies the introspection data of the schema, e.g. to hide types or fields from introspection
+type IntrospectionFilter func(data *introspection.Data)
@@ -0,0 +1,50 @@
+package graphql
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+const (
+	PlanCacheResultHit  = "hit"
+	PlanCacheResultMiss = "miss"
+)
+
+// EngineMetrics are the instruments recording the metrics of the engine to a metrics backend, e.g. with the prometheus package,
+// instruments which are nil aren't recorded
+type EngineMetrics struct {
+	// Operations counts the operations passing the normalization, labeled with the operation type: query, mutation or subscription
+	Operations resolve.Counter
+	// PlanCacheLookups counts the lookups of the plans of operations, labeled with the result: PlanCacheResultHit or PlanCacheResultMiss
+	PlanCacheLookups resolve.Counter
+	// Resolver records the fetches and the subscriptions of the resolver
+	Resolver resolve.Metrics
+}
+
+// resolverMetrics returns the instruments of the resolver, if metrics are configured
+func (e *EngineV2Configuration) resolverMetrics() *resolve.Metrics {
+	if e.metrics == nil {
+		return nil
+	}
+	return &e.metrics.Resolver
+}
+
+// recordOperation records an operation which passed the normalization and the result of the lookup of its plan
+func (e *ExecutionEngineV2) recordOperation(operation *Request, cached bool) {
+	metrics := e.config.metrics
+	if metrics == nil {
+		return
+	}
+	if metrics.Operations != nil {
+		if operationType, err := operation.OperationType(); err == nil {
+			metrics.Operations.Add(1, ast.OperationType(operationType).Name())
+		}
+	}
+	if metrics.PlanCacheLookups != nil {
+		result := PlanCacheResultMiss
+		if cached {
+			result = PlanCacheResultHit
+		}
+		metrics.PlanCacheLookups.Add(1, result)
+	}
+}
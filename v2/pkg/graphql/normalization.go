@@ -10,7 +10,58 @@ type NormalizationResult struct {
 	Errors     Errors
 }
 
+// NormalizationFlags disable stages of the normalization, the zero value runs all stages.
+// Clients relying on the exact shape of their operations, e.g. to match the persisted queries of their subgraphs,
+// keep the parts of their operations which are forwarded to the subgraphs.
+type NormalizationFlags struct {
+	// DisableRemoveUnusedVariables keeps the definitions of variables which are not used by the operation,
+	// the validation rejects operations with unused variables as required by the specification
+	DisableRemoveUnusedVariables bool
+	// DisableInlineFragments keeps inline fragments on the enclosing type and without type condition
+	// instead of merging their selections into the parent selection set.
+	// Fragment spreads are inlined regardless, the planner only resolves the selections of inline fragments.
+	DisableInlineFragments bool
+	// DisableExtractVariables keeps literal arguments instead of extracting them into variables,
+	// the default values of variables are still injected
+	DisableExtractVariables bool
+	// DisableRemoveSkippedFields keeps fields and fragments with constant @skip and @include arguments,
+	// they are planned and forwarded to the subgraphs together with their directives
+	DisableRemoveSkippedFields bool
+}
+
+func (f NormalizationFlags) options() []astnormalization.Option {
+	options := []astnormalization.Option{
+		astnormalization.WithRemoveFragmentDefinitions(),
+		astnormalization.WithInlineFragmentSpreads(),
+	}
+	if f.DisableExtractVariables {
+		options = append(options, astnormalization.WithProcessVariables())
+	} else {
+		options = append(options, astnormalization.WithExtractVariables())
+	}
+	if !f.DisableRemoveUnusedVariables {
+		options = append(options, astnormalization.WithRemoveUnusedVariables())
+	}
+	if f.DisableInlineFragments {
+		options = append(options, astnormalization.WithKeepInlineFragments())
+	}
+	if f.DisableRemoveSkippedFields {
+		options = append(options, astnormalization.WithKeepSkippedFields())
+	}
+	return options
+}
+
 func (r *Request) Normalize(schema *Schema) (result NormalizationResult, err error) {
+	return r.normalize(schema, NormalizationFlags{})
+}
+
+// NormalizeWithFlags normalizes the request without the stages disabled by the flags
+func (r *Request) NormalizeWithFlags(schema *Schema, flags NormalizationFlags) (result NormalizationResult, err error) {
+	return r.normalize(schema, flags)
+}
+
+// normalize normalizes the request with the stages enabled by the flags and the additional options
+func (r *Request) normalize(schema *Schema, flags NormalizationFlags, options ...astnormalization.Option) (result NormalizationResult, err error) {
 	if schema == nil {
 		return NormalizationResult{Successful: false, Errors: nil}, ErrNilSchema
 	}
@@ -22,12 +73,7 @@ func (r *Request) Normalize(schema *Schema) (result NormalizationResult, err err
 
 	r.document.Input.Variables = r.Variables
 
-	normalizer := astnormalization.NewWithOpts(
-		astnormalization.WithExtractVariables(),
-		astnormalization.WithRemoveFragmentDefinitions(),
-		astnormalization.WithRemoveUnusedVariables(),
-		astnormalization.WithInlineFragmentSpreads(),
-	)
+	normalizer := astnormalization.NewWithOpts(append(flags.options(), options...)...)
 
 	if r.OperationName != "" {
 		normalizer.NormalizeNamedOperation(&r.document, &schema.document, []byte(r.OperationName), &report)
@@ -0,0 +1,59 @@
+package graphql
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/middleware/operation_complexity"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// OperationStats describe the size of an operation
+type OperationStats struct {
+	// Depth is the depth of the deepest field selection
+	Depth int
+	// FieldCount is the number of field selections, fields of fragment definitions are counted once
+	FieldCount int
+	// AliasCount is the number of aliased field selections
+	AliasCount int
+	// NodeCount is the estimated number of nodes of the response, see operation_complexity
+	NodeCount int
+	// Complexity is the complexity score of the operation, see operation_complexity
+	Complexity int
+}
+
+func (r *Request) operationStats(schema *Schema) (*OperationStats, error) {
+	report := operationreport.Report{}
+	complexity, _ := operation_complexity.CalculateOperationComplexity(&r.document, &schema.document, &report)
+	if report.HasErrors() {
+		return nil, report
+	}
+
+	walker := astvisitor.NewWalker(8)
+	counter := &fieldCounter{operation: &r.document}
+	walker.RegisterEnterFieldVisitor(counter)
+	walker.Walk(&r.document, &schema.document, &report)
+	if report.HasErrors() {
+		return nil, report
+	}
+
+	return &OperationStats{
+		Depth:      complexity.Depth,
+		FieldCount: counter.fields,
+		AliasCount: counter.aliases,
+		NodeCount:  complexity.NodeCount,
+		Complexity: complexity.Complexity,
+	}, nil
+}
+
+type fieldCounter struct {
+	operation *ast.Document
+	fields    int
+	aliases   int
+}
+
+func (f *fieldCounter) EnterField(ref int) {
+	f.fields++
+	if f.operation.FieldAliasIsDefined(ref) {
+		f.aliases++
+	}
+}
@@ -0,0 +1,162 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/buger/jsonparser"
+)
+
+const persistedOperationNotFoundMessage = "PersistedOperationNotFound"
+
+// PersistedOperationStore returns the registered operations by their document id,
+// e.g. the sha256 hash of the query or the id of an operation of a trusted documents manifest
+type PersistedOperationStore interface {
+	PersistedOperation(ctx context.Context, documentID string) (query string, ok bool, err error)
+}
+
+// InMemoryPersistedOperationStore is a PersistedOperationStore keeping all registered operations in memory
+type InMemoryPersistedOperationStore struct {
+	mu         sync.RWMutex
+	operations map[string]string
+}
+
+// NewInMemoryPersistedOperationStore creates a store with the operations by their document id
+func NewInMemoryPersistedOperationStore(operations map[string]string) *InMemoryPersistedOperationStore {
+	store := &InMemoryPersistedOperationStore{}
+	store.Set(operations)
+	return store
+}
+
+func (s *InMemoryPersistedOperationStore) PersistedOperation(_ context.Context, documentID string) (query string, ok bool, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	query, ok = s.operations[documentID]
+	return query, ok, nil
+}
+
+// Add registers the operation with the document id
+func (s *InMemoryPersistedOperationStore) Add(documentID, query string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.operations[documentID] = query
+}
+
+// Set replaces all registered operations
+func (s *InMemoryPersistedOperationStore) Set(operations map[string]string) {
+	copied := make(map[string]string, len(operations))
+	for documentID, query := range operations {
+		copied[documentID] = query
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.operations = copied
+}
+
+// FilePersistedOperationStore is a PersistedOperationStore loading the operations from a json manifest.
+// The manifest is either an object of queries by their document id, e.g. a relay persisted query map,
+// or an apollo persisted query manifest with the operations in the format {"operations":[{"id":"...","body":"..."}]}
+type FilePersistedOperationStore struct {
+	*InMemoryPersistedOperationStore
+	path string
+}
+
+// NewFilePersistedOperationStore creates a store with the operations of the manifest at path
+func NewFilePersistedOperationStore(path string) (*FilePersistedOperationStore, error) {
+	store := &FilePersistedOperationStore{
+		InMemoryPersistedOperationStore: NewInMemoryPersistedOperationStore(nil),
+		path:                            path,
+	}
+	if err := store.Reload(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Reload replaces the operations with the operations of the manifest, e.g. after a deployment of new clients.
+// The operations are kept if the manifest is invalid.
+func (s *FilePersistedOperationStore) Reload() error {
+	manifest, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	operations, err := parsePersistedOperationManifest(manifest)
+	if err != nil {
+		return fmt.Errorf("persisted operation manifest %s: %w", s.path, err)
+	}
+	s.Set(operations)
+	return nil
+}
+
+type apolloPersistedQueryManifest struct {
+	Operations []struct {
+		ID   string `json:"id"`
+		Body string `json:"body"`
+	} `json:"operations"`
+}
+
+func parsePersistedOperationManifest(manifest []byte) (map[string]string, error) {
+	if _, dataType, _, err := jsonparser.Get(manifest, "operations"); err == nil && dataType == jsonparser.Array {
+		var apolloManifest apolloPersistedQueryManifest
+		if err = json.Unmarshal(manifest, &apolloManifest); err != nil {
+			return nil, err
+		}
+		operations := make(map[string]string, len(apolloManifest.Operations))
+		for _, operation := range apolloManifest.Operations {
+			if operation.ID == "" || operation.Body == "" {
+				return nil, errors.New("operation without id or body")
+			}
+			operations[operation.ID] = operation.Body
+		}
+		return operations, nil
+	}
+
+	var operations map[string]string
+	if err := json.Unmarshal(manifest, &operations); err != nil {
+		return nil, err
+	}
+	return operations, nil
+}
+
+// ResolvePersistedOperation sets the query of a request with a document id to the registered operation
+// and rejects requests which are not registered, see EngineV2Configuration.SetPersistedOperationStore.
+// The document id is either the documentId of the request or the sha256Hash of the persistedQuery extension,
+// requests sending only the query are accepted if the sha256 hash of the query is a registered document id.
+// Execute resolves the operation, transports call it before to inspect the operation, e.g. its type.
+// Without a store the request is not modified.
+func (e *ExecutionEngineV2) ResolvePersistedOperation(ctx context.Context, operation *Request) error {
+	if e.config.persistedOperationStore == nil {
+		return nil
+	}
+
+	documentID := operation.DocumentID
+	if documentID == "" && len(operation.Extensions) != 0 {
+		documentID, _ = jsonparser.GetString(operation.Extensions, "persistedQuery", "sha256Hash")
+	}
+	if documentID == "" {
+		if operation.Query == "" {
+			return ErrEmptyRequest
+		}
+		documentID = QuerySha256Hash(operation.Query)
+	}
+
+	query, ok, err := e.config.persistedOperationStore.PersistedOperation(ctx, documentID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if operation.DocumentID == "" && operation.Query != "" {
+			return RequestErrors{{Message: "operation is not a registered persisted operation"}}
+		}
+		return RequestErrors{{Message: persistedOperationNotFoundMessage}}
+	}
+	if operation.Query != "" && operation.Query != query {
+		return RequestErrors{{Message: fmt.Sprintf("query does not match the persisted operation %s", documentID)}}
+	}
+	operation.Query = query
+	return nil
+}
@@ -0,0 +1,145 @@
+package graphql
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/staticdatasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+)
+
+func TestFilePersistedOperationStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "operations.json")
+
+	t.Run("should load a map of operations", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte(`{"a":"{ a }","b":"{ b }"}`), 0o600))
+		store, err := NewFilePersistedOperationStore(path)
+		require.NoError(t, err)
+
+		query, ok, err := store.PersistedOperation(ctx, "b")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "{ b }", query)
+
+		_, ok, err = store.PersistedOperation(ctx, "c")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("should load an apollo persisted query manifest", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte(`{"format":"apollo-persisted-query-manifest","version":1,"operations":[{"id":"a","name":"A","type":"query","body":"query A { a }"}]}`), 0o600))
+		store, err := NewFilePersistedOperationStore(path)
+		require.NoError(t, err)
+
+		query, ok, err := store.PersistedOperation(ctx, "a")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "query A { a }", query)
+	})
+
+	t.Run("should keep the operations if the manifest is invalid", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte(`{"a":"{ a }"}`), 0o600))
+		store, err := NewFilePersistedOperationStore(path)
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(path, []byte(`{"operations":[{"id":"b"}]}`), 0o600))
+		assert.Error(t, store.Reload())
+		_, ok, _ := store.PersistedOperation(ctx, "a")
+		assert.True(t, ok)
+
+		require.NoError(t, os.WriteFile(path, []byte(`{"b":"{ b }"}`), 0o600))
+		require.NoError(t, store.Reload())
+		_, ok, _ = store.PersistedOperation(ctx, "a")
+		assert.False(t, ok)
+		_, ok, _ = store.PersistedOperation(ctx, "b")
+		assert.True(t, ok)
+	})
+
+	t.Run("should fail for a missing file", func(t *testing.T) {
+		_, err := NewFilePersistedOperationStore(filepath.Join(t.TempDir(), "missing.json"))
+		assert.Error(t, err)
+	})
+}
+
+func TestExecutionEngineV2_PersistedOperations(t *testing.T) {
+	schema, err := NewSchemaFromString(`type Query { hello: String }`)
+	require.NoError(t, err)
+
+	store := NewInMemoryPersistedOperationStore(map[string]string{
+		"hello": `query Hello { hello }`,
+	})
+	store.Add(QuerySha256Hash(`{ hello }`), `{ hello }`)
+
+	engineConfig := NewEngineV2Configuration(schema)
+	engineConfig.SetPersistedOperationStore(store)
+	engineConfig.SetDataSources([]plan.DataSourceConfiguration{
+		{
+			RootNodes: []plan.TypeField{
+				{TypeName: "Query", FieldNames: []string{"hello"}},
+			},
+			Factory: &staticdatasource.Factory{},
+			Custom: staticdatasource.ConfigJSON(staticdatasource.Configuration{
+				Data: `{"hello":"world"}`,
+			}),
+		},
+	})
+	engine, err := NewExecutionEngineV2(context.Background(), abstractlogger.NoopLogger, engineConfig)
+	require.NoError(t, err)
+
+	execute := func(request Request) (string, error) {
+		resultWriter := NewEngineResultWriter()
+		err := engine.Execute(context.Background(), &request, &resultWriter)
+		return resultWriter.String(), err
+	}
+
+	t.Run("should execute the operation of the document id", func(t *testing.T) {
+		response, err := execute(Request{DocumentID: "hello"})
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"hello":"world"}}`, response)
+	})
+
+	t.Run("should execute the operation of the persisted query extension", func(t *testing.T) {
+		response, err := execute(Request{Extensions: []byte(`{"persistedQuery":{"version":1,"sha256Hash":"` + QuerySha256Hash(`{ hello }`) + `"}}`)})
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"hello":"world"}}`, response)
+	})
+
+	t.Run("should execute registered queries", func(t *testing.T) {
+		response, err := execute(Request{Query: `{ hello }`})
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"hello":"world"}}`, response)
+	})
+
+	t.Run("should reject unknown document ids", func(t *testing.T) {
+		_, err := execute(Request{DocumentID: "unknown"})
+		assert.Equal(t, RequestErrors{{Message: "PersistedOperationNotFound"}}, err)
+	})
+
+	t.Run("should reject queries which are not registered", func(t *testing.T) {
+		_, err := execute(Request{Query: `{ __typename }`})
+		assert.Equal(t, RequestErrors{{Message: "operation is not a registered persisted operation"}}, err)
+	})
+
+	t.Run("should reject queries not matching the document id", func(t *testing.T) {
+		_, err := execute(Request{DocumentID: "hello", Query: `{ __typename }`})
+		assert.Equal(t, RequestErrors{{Message: "query does not match the persisted operation hello"}}, err)
+	})
+
+	t.Run("should reject empty requests", func(t *testing.T) {
+		_, err := execute(Request{})
+		assert.ErrorIs(t, err, ErrEmptyRequest)
+	})
+
+	t.Run("should unmarshal the document id", func(t *testing.T) {
+		var request Request
+		require.NoError(t, UnmarshalRequestBytes([]byte(`{"documentId":"hello","variables":{}}`), &request))
+		assert.Equal(t, "hello", request.DocumentID)
+	})
+}
@@ -0,0 +1,76 @@
+package graphql
+
+import (
+	"encoding/binary"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/pool"
+)
+
+const defaultPlanCacheSize = 1024
+
+// PlanCache stores the plans of operations, see EngineV2Configuration.SetPlanCache
+// the keys are computed from the normalized operation, the operation name and the hash of the schema
+// the operation is validated with, so a cached plan belongs to a valid operation.
+// Implementations must be safe for concurrent use.
+type PlanCache interface {
+	Get(key uint64) (plan.Plan, bool)
+	Add(key uint64, p plan.Plan)
+	// Len returns the number of cached plans
+	Len() int
+	// Purge removes all cached plans
+	Purge()
+}
+
+type lruPlanCache struct {
+	cache *lru.Cache
+}
+
+// NewLRUPlanCache returns a PlanCache which evicts the least recently used plan when size plans are cached
+// it is the plan cache of engines without a configured cache, with a size of 1024
+func NewLRUPlanCache(size int) (PlanCache, error) {
+	cache, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+	return &lruPlanCache{cache: cache}, nil
+}
+
+func (c *lruPlanCache) Get(key uint64) (plan.Plan, bool) {
+	cached, ok := c.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	p, ok := cached.(plan.Plan)
+	return p, ok
+}
+
+func (c *lruPlanCache) Add(key uint64, p plan.Plan) {
+	c.cache.Add(key, p)
+}
+
+func (c *lruPlanCache) Len() int {
+	return c.cache.Len()
+}
+
+func (c *lruPlanCache) Purge() {
+	c.cache.Purge()
+}
+
+// planCacheKey returns the key of the plan of a normalized operation, it is the hash of the printed operation,
+// the operation name and the hash of the schema the operation is validated with
+func planCacheKey(operation, definition *ast.Document, operationName string, schema *Schema) (uint64, error) {
+	hash := pool.Hash64.Get()
+	defer pool.Hash64.Put(hash)
+	if err := writeRequestKey(hash, operation, definition, operationName, nil, nil); err != nil {
+		return 0, err
+	}
+
+	var schemaHash [8]byte
+	binary.LittleEndian.PutUint64(schemaHash[:], schema.Hash())
+	_, _ = hash.Write(schemaHash[:])
+	return hash.Sum64(), nil
+}
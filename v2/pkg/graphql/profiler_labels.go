@@ -0,0 +1,19 @@
+package graphql
+
+import (
+	"context"
+	"runtime/pprof"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+// setProfilerLabels labels the goroutine with the name of the operation, see resolve.ProfilerLabelOperationName.
+// Goroutines started by the goroutine, e.g. the fetches of the resolver, inherit the labels.
+// The returned function restores the labels of ctx.
+func setProfilerLabels(ctx context.Context, operation *Request) (context.Context, func()) {
+	labeled := pprof.WithLabels(ctx, pprof.Labels(resolve.ProfilerLabelOperationName, operation.OperationName))
+	pprof.SetGoroutineLabels(labeled)
+	return labeled, func() {
+		pprof.SetGoroutineLabels(ctx)
+	}
+}
@@ -6,9 +6,12 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/buger/jsonparser"
+
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/jsoncodec"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/middleware/operation_complexity"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
 )
@@ -36,6 +39,9 @@ type Request struct {
 	OperationName string          `json:"operationName"`
 	Variables     json.RawMessage `json:"variables,omitempty"`
 	Query         string          `json:"query"`
+	Extensions    json.RawMessage `json:"extensions,omitempty"`
+	// DocumentID identifies a persisted operation, see EngineV2Configuration.SetPersistedOperationStore
+	DocumentID string `json:"documentId,omitempty"`
 
 	document     ast.Document
 	isParsed     bool
@@ -51,11 +57,55 @@ func UnmarshalRequest(reader io.Reader, request *Request) error {
 		return err
 	}
 
+	return UnmarshalRequestBytes(requestBytes, request)
+}
+
+// requestWithoutVariables is used to unmarshal all fields of a Request except the variables
+type requestWithoutVariables struct {
+	OperationName string          `json:"operationName"`
+	Query         string          `json:"query"`
+	Extensions    json.RawMessage `json:"extensions,omitempty"`
+	DocumentID    string          `json:"documentId,omitempty"`
+}
+
+// UnmarshalRequestBytes unmarshals the request without copying the variables.
+// The variables of the request reference the variables in requestBytes, so requestBytes must not be modified
+// or reused while the request is in use. The capacity of the variables is limited to their length, so
+// normalization copies the variables when it has to modify them instead of writing into requestBytes.
+func UnmarshalRequestBytes(requestBytes []byte, request *Request) error {
 	if len(requestBytes) == 0 {
 		return ErrEmptyRequest
 	}
 
-	return json.Unmarshal(requestBytes, &request)
+	fields := requestWithoutVariables{
+		OperationName: request.OperationName,
+		Query:         request.Query,
+		Extensions:    request.Extensions,
+		DocumentID:    request.DocumentID,
+	}
+	if err := jsoncodec.Unmarshal(requestBytes, &fields); err != nil {
+		return err
+	}
+	request.OperationName = fields.OperationName
+	request.Query = fields.Query
+	request.Extensions = fields.Extensions
+	request.DocumentID = fields.DocumentID
+
+	variables, dataType, offset, err := jsonparser.Get(requestBytes, "variables")
+	switch {
+	case errors.Is(err, jsonparser.KeyPathNotFoundError):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	start := offset - len(variables)
+	if dataType == jsonparser.String {
+		// strings are returned without quotes, but the raw variables keep them like json.RawMessage does
+		start -= 2
+	}
+	request.Variables = requestBytes[start:offset:offset]
+	return nil
 }
 
 func UnmarshalHttpRequest(r *http.Request, request *Request) error {
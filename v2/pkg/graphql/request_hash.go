@@ -0,0 +1,143 @@
+package graphql
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/buger/jsonparser"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/pool"
+)
+
+// QuerySha256Hash returns the hex encoded sha256 hash of the raw query
+// this is the hash clients send for automatic persisted queries and the key of persisted operation allow-lists
+func QuerySha256Hash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// Hash returns a stable hash of the request built from the printed query, the operation name
+// and the values of the given extensions, e.g. to use requests as cache keys.
+// Variables are not part of the hash.
+// The query is printed without whitespace and comments; normalize the request first
+// to get the same hash for semantically equal queries, e.g. with inlined fragments.
+// Extensions which are not listed in extensionKeys don't change the hash.
+func (r *Request) Hash(extensionKeys ...string) (uint64, error) {
+	report := r.parseQueryOnce()
+	if report.HasErrors() {
+		return 0, report
+	}
+
+	hash := pool.Hash64.Get()
+	defer pool.Hash64.Put(hash)
+	if err := writeRequestKey(hash, &r.document, nil, r.OperationName, r.Extensions, extensionKeys); err != nil {
+		return 0, err
+	}
+	return hash.Sum64(), nil
+}
+
+// Equals reports if both requests have the same printed query, operation name and values of the given extensions
+// it compares the same parts of the requests as Hash, but without the risk of hash collisions
+func (r *Request) Equals(other *Request, extensionKeys ...string) (bool, error) {
+	report := r.parseQueryOnce()
+	if report.HasErrors() {
+		return false, report
+	}
+	report = other.parseQueryOnce()
+	if report.HasErrors() {
+		return false, report
+	}
+
+	left, right := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := writeRequestKey(left, &r.document, nil, r.OperationName, r.Extensions, extensionKeys); err != nil {
+		return false, err
+	}
+	if err := writeRequestKey(right, &other.document, nil, other.OperationName, other.Extensions, extensionKeys); err != nil {
+		return false, err
+	}
+	return bytes.Equal(left.Bytes(), right.Bytes()), nil
+}
+
+// operationHash returns the hash of a parsed operation, it is equal to the Hash of a request with the same operation
+func operationHash(operation, definition *ast.Document, operationName string) (uint64, error) {
+	hash := pool.Hash64.Get()
+	defer pool.Hash64.Put(hash)
+	if err := writeRequestKey(hash, operation, definition, operationName, nil, nil); err != nil {
+		return 0, err
+	}
+	return hash.Sum64(), nil
+}
+
+// writeRequestKey writes the parts of a request which identify it to out
+// each part is terminated with a zero byte, so moving bytes between parts results in a different key
+func writeRequestKey(out io.Writer, operation, definition *ast.Document, operationName string, extensions json.RawMessage, extensionKeys []string) error {
+	if err := astprinter.Print(operation, definition, out); err != nil {
+		return err
+	}
+	if _, err := out.Write([]byte{0}); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(out, operationName); err != nil {
+		return err
+	}
+	if _, err := out.Write([]byte{0}); err != nil {
+		return err
+	}
+	if len(extensionKeys) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(extensionKeys))
+	copy(keys, extensionKeys)
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, err := canonicalExtensionValue(extensions, key)
+		if err != nil {
+			return err
+		}
+		if _, err = io.WriteString(out, key); err != nil {
+			return err
+		}
+		if _, err = out.Write([]byte{0}); err != nil {
+			return err
+		}
+		if _, err = out.Write(value); err != nil {
+			return err
+		}
+		if _, err = out.Write([]byte{0}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// canonicalExtensionValue returns the value of the extension re-encoded with sorted object keys and without whitespace
+// a missing extension and an explicit null result in the same value
+func canonicalExtensionValue(extensions json.RawMessage, key string) ([]byte, error) {
+	if len(extensions) == 0 {
+		return []byte("null"), nil
+	}
+	raw, dataType, _, err := jsonparser.Get(extensions, key)
+	if dataType == jsonparser.NotExist {
+		return []byte("null"), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if dataType == jsonparser.String {
+		// jsonparser returns strings without quotes
+		raw = append(append([]byte{'"'}, raw...), '"')
+	}
+
+	var value interface{}
+	if err = json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
@@ -0,0 +1,91 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+)
+
+func TestQuerySha256Hash(t *testing.T) {
+	assert.Equal(t, "ecf4edb46db40b5132295c0291d62fb65d6759a9eedfa4d5d612dd5ec54a6b38", QuerySha256Hash("{__typename}"))
+}
+
+func TestRequest_Hash(t *testing.T) {
+	hash := func(t *testing.T, request *Request, extensionKeys ...string) uint64 {
+		t.Helper()
+		h, err := request.Hash(extensionKeys...)
+		require.NoError(t, err)
+		return h
+	}
+
+	t.Run("should ignore whitespace and comments", func(t *testing.T) {
+		left := &Request{Query: "query Hello { hello }"}
+		right := &Request{Query: "query Hello {\n  # comment\n  hello\n}"}
+		assert.Equal(t, hash(t, left), hash(t, right))
+	})
+
+	t.Run("should include operation name", func(t *testing.T) {
+		query := "query A { a } query B { b }"
+		left := &Request{Query: query, OperationName: "A"}
+		right := &Request{Query: query, OperationName: "B"}
+		assert.NotEqual(t, hash(t, left), hash(t, right))
+	})
+
+	t.Run("should not include variables", func(t *testing.T) {
+		left := &Request{Query: "query Hello($a: String) { hello(a: $a) }", Variables: []byte(`{"a":"a"}`)}
+		right := &Request{Query: "query Hello($a: String) { hello(a: $a) }", Variables: []byte(`{"a":"b"}`)}
+		assert.Equal(t, hash(t, left), hash(t, right))
+	})
+
+	t.Run("should only include listed extensions", func(t *testing.T) {
+		left := &Request{Query: "{ hello }", Extensions: []byte(`{"persistedQuery":{"version":1},"client":{"name":"a"}}`)}
+		right := &Request{Query: "{ hello }", Extensions: []byte(`{"persistedQuery":{"version":1},"client":{"name":"b"}}`)}
+		assert.Equal(t, hash(t, left), hash(t, right))
+		assert.Equal(t, hash(t, left, "persistedQuery"), hash(t, right, "persistedQuery"))
+		assert.NotEqual(t, hash(t, left, "client"), hash(t, right, "client"))
+	})
+
+	t.Run("should not depend on formatting and key order of extensions", func(t *testing.T) {
+		left := &Request{Query: "{ hello }", Extensions: []byte(`{"client":{"name":"a","version":"1"}}`)}
+		right := &Request{Query: "{ hello }", Extensions: []byte(`{ "client": { "version": "1", "name": "a" } }`)}
+		assert.Equal(t, hash(t, left, "client"), hash(t, right, "client"))
+	})
+
+	t.Run("should treat missing extensions as null", func(t *testing.T) {
+		left := &Request{Query: "{ hello }"}
+		right := &Request{Query: "{ hello }", Extensions: []byte(`{"client":null}`)}
+		assert.Equal(t, hash(t, left, "client"), hash(t, right, "client"))
+	})
+
+	t.Run("should be equal to the hash of the parsed operation", func(t *testing.T) {
+		request := &Request{Query: "query Hello { hello }", OperationName: "Hello"}
+		operation, report := astparser.ParseGraphqlDocumentString(request.Query)
+		require.False(t, report.HasErrors())
+
+		operationHash, err := operationHash(&operation, nil, "Hello")
+		require.NoError(t, err)
+		assert.Equal(t, hash(t, request), operationHash)
+	})
+
+	t.Run("should return error for invalid query", func(t *testing.T) {
+		request := &Request{Query: "query Hello { hello"}
+		_, err := request.Hash()
+		assert.Error(t, err)
+	})
+}
+
+func TestRequest_Equals(t *testing.T) {
+	left := &Request{Query: "{ hello }", Extensions: []byte(`{"client":{"name":"a"}}`)}
+	right := &Request{Query: "{\n  hello\n}", Extensions: []byte(`{"client":{"name":"b"}}`)}
+
+	equal, err := left.Equals(right)
+	require.NoError(t, err)
+	assert.True(t, equal)
+
+	equal, err = left.Equals(right, "client")
+	require.NoError(t, err)
+	assert.False(t, equal)
+}
@@ -2,11 +2,14 @@ package graphql
 
 import (
 	"bytes"
+	"encoding/json"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/jsoncodec"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/starwars"
 )
 
@@ -33,6 +36,71 @@ func TestUnmarshalRequest(t *testing.T) {
 		assert.Equal(t, "Hello", request.OperationName)
 		assert.Equal(t, "query Hello { hello }", request.Query)
 	})
+
+	t.Run("should keep raw variables", func(t *testing.T) {
+		for _, variables := range []string{`{"a":"b"}`, `""`, `null`, `1`} {
+			var request Request
+			err := UnmarshalRequest(strings.NewReader(`{"query":"{ hello }","variables":`+variables+`}`), &request)
+			require.NoError(t, err)
+			assert.Equal(t, variables, string(request.Variables))
+		}
+	})
+}
+
+func TestUnmarshalRequestBytes(t *testing.T) {
+	t.Run("should reference variables of the request bytes", func(t *testing.T) {
+		requestBytes := []byte(`{"query":"query Hello($id: ID!) { hello(id: $id) }","variables":{"id":"1"},"operationName":"Hello"}`)
+
+		var request Request
+		err := UnmarshalRequestBytes(requestBytes, &request)
+		require.NoError(t, err)
+		assert.Equal(t, "Hello", request.OperationName)
+		assert.Equal(t, `{"id":"1"}`, string(request.Variables))
+
+		offset := bytes.Index(requestBytes, []byte(`{"id"`))
+		assert.Same(t, &requestBytes[offset], &request.Variables[0])
+		assert.Equal(t, len(request.Variables), cap(request.Variables))
+	})
+
+	t.Run("should not modify the request bytes on normalization", func(t *testing.T) {
+		schema, err := NewSchemaFromString(`
+			schema { query: Query }
+			input Filter { id: ID! name: String = "world" }
+			type Query { hello(filter: Filter!): String }`)
+		require.NoError(t, err)
+
+		requestBytes := []byte(`{"query":"query Hello($filter: Filter!, $unused: String) { hello(filter: $filter) }","variables":{"filter":{"id":"1"},"unused":"x"},"extensions":{}}`)
+		original := string(requestBytes)
+
+		var request Request
+		require.NoError(t, UnmarshalRequestBytes(requestBytes, &request))
+		result, err := request.Normalize(schema)
+		require.NoError(t, err)
+		assert.True(t, result.Successful)
+
+		assert.Equal(t, original, string(requestBytes))
+		assert.Equal(t, `{"filter":{"id":"1","name":"world"}}`, string(request.Variables))
+	})
+}
+
+func TestUnmarshalRequestBytes_Codec(t *testing.T) {
+	t.Cleanup(func() {
+		jsoncodec.SetDefault(nil)
+	})
+
+	unmarshaled := 0
+	jsoncodec.SetDefault(jsoncodec.Funcs{
+		MarshalFunc: json.Marshal,
+		UnmarshalFunc: func(data []byte, v interface{}) error {
+			unmarshaled++
+			return json.Unmarshal(data, v)
+		},
+	})
+
+	var request Request
+	require.NoError(t, UnmarshalRequestBytes([]byte(`{"query":"{ hello }"}`), &request))
+	assert.Equal(t, "{ hello }", request.Query)
+	assert.Equal(t, 1, unmarshaled)
 }
 
 func TestRequest_Print(t *testing.T) {
@@ -1,7 +1,7 @@
 package graphql
 
 import (
-	"encoding/json"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/jsoncodec"
 )
 
 type Response struct {
@@ -10,5 +10,5 @@ type Response struct {
 }
 
 func (r Response) Marshal() ([]byte, error) {
-	return json.Marshal(r)
+	return jsoncodec.Marshal(r)
 }
@@ -0,0 +1,69 @@
+package graphql
+
+import (
+	"encoding/binary"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/pool"
+)
+
+// PlanCacheStats are the size of the plan cache and the number of lookups served by it since the creation of the engine
+type PlanCacheStats struct {
+	Size   int
+	Hits   int64
+	Misses int64
+}
+
+// PlanCacheStats returns the stats of the plan cache of the engine
+func (e *ExecutionEngineV2) PlanCacheStats() PlanCacheStats {
+	return PlanCacheStats{
+		Size:   e.executionPlanCache.Len(),
+		Hits:   e.planCacheHits.Load(),
+		Misses: e.planCacheMisses.Load(),
+	}
+}
+
+// SubscriptionStats returns the active subscriptions of the resolver of the engine,
+// engines sharing a resolver, e.g. the engines of a TenantEngineManager, report the subscriptions of all engines
+func (e *ExecutionEngineV2) SubscriptionStats() resolve.SubscriptionStats {
+	return e.resolver.SubscriptionStats()
+}
+
+// ConfigHash returns the hash of the configuration the engine was created with,
+// e.g. to compare the configurations loaded by the instances of a gateway
+func (e *ExecutionEngineV2) ConfigHash() uint64 {
+	return e.configHash
+}
+
+// configHash hashes the schema and the datasources of the configuration
+func configHash(config *EngineV2Configuration) uint64 {
+	hash := pool.Hash64.Get()
+	hash.Reset()
+	defer pool.Hash64.Put(hash)
+
+	var schemaHash [8]byte
+	binary.LittleEndian.PutUint64(schemaHash[:], config.schema.Hash())
+	_, _ = hash.Write(schemaHash[:])
+
+	for i := range config.plannerConfig.DataSources {
+		dataSource := &config.plannerConfig.DataSources[i]
+		_, _ = hash.WriteString(dataSource.ID)
+		_, _ = hash.Write([]byte{0})
+		for _, nodes := range []plan.TypeFields{dataSource.RootNodes, dataSource.ChildNodes} {
+			for _, node := range nodes {
+				_, _ = hash.WriteString(node.TypeName)
+				_, _ = hash.Write([]byte{0})
+				for _, fieldName := range node.FieldNames {
+					_, _ = hash.WriteString(fieldName)
+					_, _ = hash.Write([]byte{0})
+				}
+			}
+			_, _ = hash.Write([]byte{0})
+		}
+		var customHash [8]byte
+		binary.LittleEndian.PutUint64(customHash[:], uint64(dataSource.Hash()))
+		_, _ = hash.Write(customHash[:])
+	}
+	return hash.Sum64()
+}
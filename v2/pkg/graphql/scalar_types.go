@@ -0,0 +1,78 @@
+package graphql
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/introspection"
+)
+
+var builtInScalarTypeNames = map[string]struct{}{
+	"Int":     {},
+	"Float":   {},
+	"String":  {},
+	"Boolean": {},
+	"ID":      {},
+}
+
+// ScalarType describes a scalar type of the schema
+type ScalarType struct {
+	Name        string
+	Description string
+	// SpecifiedByURL is the url of the @specifiedBy directive, it is empty if the scalar has no @specifiedBy directive
+	SpecifiedByURL string
+	// IsBuiltIn is true for the scalars defined by the GraphQL specification: Int, Float, String, Boolean and ID
+	IsBuiltIn bool
+}
+
+// ScalarTypes returns all scalar types of the schema in the order of their definition
+// directives of scalar type extensions are taken into account, so the schema doesn't have to be normalized
+func (s *Schema) ScalarTypes() []ScalarType {
+	scalarTypes := make([]ScalarType, 0, len(s.document.ScalarTypeDefinitions))
+	for ref := range s.document.ScalarTypeDefinitions {
+		scalarTypes = append(scalarTypes, s.scalarType(ref))
+	}
+	return scalarTypes
+}
+
+// ScalarType returns the scalar type with the given name, ok is false if the schema has no such scalar
+func (s *Schema) ScalarType(name string) (scalarType ScalarType, ok bool) {
+	nodes, _ := s.document.Index.NodesByNameStr(name)
+	for _, node := range nodes {
+		if node.Kind == ast.NodeKindScalarTypeDefinition {
+			return s.scalarType(node.Ref), true
+		}
+	}
+	return ScalarType{}, false
+}
+
+func (s *Schema) scalarType(ref int) ScalarType {
+	name := s.document.ScalarTypeDefinitionNameString(ref)
+	_, isBuiltIn := builtInScalarTypeNames[name]
+
+	return ScalarType{
+		Name:           name,
+		Description:    s.document.ScalarTypeDefinitionDescriptionString(ref),
+		SpecifiedByURL: s.scalarTypeSpecifiedByURL(ref, name),
+		IsBuiltIn:      isBuiltIn,
+	}
+}
+
+func (s *Schema) scalarTypeSpecifiedByURL(ref int, name string) string {
+	directiveRefs := s.document.ScalarTypeDefinitions[ref].Directives.Refs
+	for i := range s.document.ScalarTypeExtensions {
+		if s.document.ScalarTypeExtensionNameString(i) == name {
+			directiveRefs = append(directiveRefs[:len(directiveRefs):len(directiveRefs)], s.document.ScalarTypeExtensions[i].Directives.Refs...)
+		}
+	}
+
+	for _, directiveRef := range directiveRefs {
+		if s.document.DirectiveNameString(directiveRef) != introspection.SpecifiedByDirectiveName {
+			continue
+		}
+		value, exists := s.document.DirectiveArgumentValueByName(directiveRef, []byte(introspection.SpecifiedByURLArgName))
+		if !exists {
+			continue
+		}
+		return s.document.ValueContentString(value)
+	}
+	return ""
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvalidation"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/federation"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/pool"
 )
@@ -131,6 +132,11 @@ func (s *Schema) Document() []byte {
 	return s.rawSchema
 }
 
+// FederationSDL prints the schema as SDL of a Federation v2 subgraph, see federation.PrintSubgraphSDL
+func (s *Schema) FederationSDL(options federation.SubgraphSDLOptions) (string, error) {
+	return federation.PrintSubgraphSDL(string(s.rawInput), options)
+}
+
 // HasQueryType TODO: should be deprecated?
 func (s *Schema) HasQueryType() bool {
 	return len(s.document.Index.QueryTypeName) > 0
@@ -11,6 +11,7 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/federation"
 )
 
 func TestNewSchemaFromReader(t *testing.T) {
@@ -142,6 +143,53 @@ func TestSchema_QueryTypeName(t *testing.T) {
 	)
 }
 
+func TestSchema_ScalarTypes(t *testing.T) {
+	schema, err := NewSchemaFromString(`
+		"An RFC 3339 date time"
+		scalar DateTime @specifiedBy(url: "https://scalars.graphql.org/andimarek/date-time")
+		scalar JSON
+		extend scalar JSON @specifiedBy(url: "https://www.rfc-editor.org/rfc/rfc8259")
+		scalar Upload
+		type Query {
+			now: DateTime
+			json: JSON
+		}`)
+	require.NoError(t, err)
+
+	t.Run("should return all scalar types", func(t *testing.T) {
+		customScalarTypes := make([]ScalarType, 0)
+		for _, scalarType := range schema.ScalarTypes() {
+			if !scalarType.IsBuiltIn {
+				customScalarTypes = append(customScalarTypes, scalarType)
+			}
+		}
+		assert.Equal(t, []ScalarType{
+			{Name: "DateTime", Description: "An RFC 3339 date time", SpecifiedByURL: "https://scalars.graphql.org/andimarek/date-time"},
+			{Name: "JSON", SpecifiedByURL: "https://www.rfc-editor.org/rfc/rfc8259"},
+			{Name: "Upload"},
+		}, customScalarTypes)
+	})
+
+	t.Run("should return scalar type by name", func(t *testing.T) {
+		scalarType, ok := schema.ScalarType("DateTime")
+		assert.True(t, ok)
+		assert.Equal(t, "https://scalars.graphql.org/andimarek/date-time", scalarType.SpecifiedByURL)
+
+		scalarType, ok = schema.ScalarType("String")
+		assert.True(t, ok)
+		assert.True(t, scalarType.IsBuiltIn)
+		assert.Empty(t, scalarType.SpecifiedByURL)
+	})
+
+	t.Run("should return false for types which are not scalars", func(t *testing.T) {
+		_, ok := schema.ScalarType("Query")
+		assert.False(t, ok)
+
+		_, ok = schema.ScalarType("NotExisting")
+		assert.False(t, ok)
+	})
+}
+
 func TestSchema_HasMutationType(t *testing.T) {
 	run := func(schema string, expectation bool) func(t *testing.T) {
 		return func(t *testing.T) {
@@ -1899,3 +1947,33 @@ type VehiclesEdge {
   """A cursor for use in pagination"""
   cursor: String!
 }`
+
+func TestSchema_FederationSDL(t *testing.T) {
+	schema, err := NewSchemaFromString(`
+		directive @key(fields: String!) on OBJECT
+		directive @shareable on FIELD_DEFINITION
+
+		type Query {
+			me: User
+		}
+
+		type User @key(fields: "id") {
+			id: ID!
+			name: String! @shareable
+		}`)
+	require.NoError(t, err)
+
+	sdl, err := schema.FederationSDL(federation.SubgraphSDLOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, `extend schema
+    @link(url: "https://specs.apollo.dev/federation/v2.3", import: ["@key", "@shareable"])
+
+type Query {
+    me: User
+}
+
+type User @key(fields: "id") {
+    id: ID!
+    name: String! @shareable
+}`, sdl)
+}
@@ -0,0 +1,131 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/jensneuse/abstractlogger"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+const defaultSlowOperationLogSize = 100
+
+// SlowOperation is the record of an operation which took longer than the threshold of the SlowOperationLog
+type SlowOperation struct {
+	Timestamp     time.Time `json:"timestamp"`
+	OperationName string    `json:"operationName,omitempty"`
+	OperationType string    `json:"operationType"`
+	// Query is the normalized operation, literals are replaced with placeholders
+	Query string `json:"query"`
+	// Duration of the execution, including normalization, validation and planning
+	Duration time.Duration `json:"durationNanos"`
+	// Plan is the fetch tree of the plan as json
+	Plan json.RawMessage `json:"plan,omitempty"`
+	// Fetches are the stats of the fetches in the order of their completion
+	Fetches []resolve.FetchTiming `json:"fetches"`
+	Error   string                `json:"error,omitempty"`
+}
+
+// SlowOperationLog keeps the most recent operations which took longer than the threshold in a ring buffer,
+// see EngineV2Configuration.SetSlowOperationLog
+type SlowOperationLog struct {
+	threshold time.Duration
+
+	mu         sync.Mutex
+	operations []SlowOperation
+	next       int
+	full       bool
+}
+
+// NewSlowOperationLog creates a log of operations taking longer than threshold, keeping the most recent size operations.
+// A size of 0 keeps 100 operations.
+func NewSlowOperationLog(threshold time.Duration, size int) *SlowOperationLog {
+	if size <= 0 {
+		size = defaultSlowOperationLogSize
+	}
+	return &SlowOperationLog{
+		threshold:  threshold,
+		operations: make([]SlowOperation, size),
+	}
+}
+
+// Threshold returns the duration above which operations are recorded
+func (l *SlowOperationLog) Threshold() time.Duration {
+	return l.threshold
+}
+
+// Add records the operation, the oldest operation is dropped if the log is full
+func (l *SlowOperationLog) Add(operation SlowOperation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.operations[l.next] = operation
+	l.next = (l.next + 1) % len(l.operations)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Operations returns the recorded operations, the most recent operation first
+func (l *SlowOperationLog) Operations() []SlowOperation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	count := l.next
+	if l.full {
+		count = len(l.operations)
+	}
+	operations := make([]SlowOperation, 0, count)
+	for i := 1; i <= count; i++ {
+		operations = append(operations, l.operations[(l.next-i+len(l.operations))%len(l.operations)])
+	}
+	return operations
+}
+
+// SlowOperations returns the operations of the slow operation log, the most recent operation first,
+// it returns nil if the engine has no slow operation log
+func (e *ExecutionEngineV2) SlowOperations() []SlowOperation {
+	if e.config.slowOperationLog == nil {
+		return nil
+	}
+	return e.config.slowOperationLog.Operations()
+}
+
+// recordSlowOperation adds the operation to the slow operation log and logs it, if it took longer than the threshold
+func (e *ExecutionEngineV2) recordSlowOperation(ctx context.Context, execContext *internalExecutionContext, operation *Request, executionPlan *plan.SynchronousResponsePlan, start time.Time, err error) {
+	duration := time.Since(start)
+	if duration <= e.config.slowOperationLog.threshold {
+		return
+	}
+
+	record := SlowOperation{
+		Timestamp:     start,
+		OperationName: operation.OperationName,
+		Duration:      duration,
+		Fetches:       execContext.fetchTimings.Timings(),
+	}
+	if operationType, typeErr := operation.OperationType(); typeErr == nil {
+		record.OperationType = ast.OperationType(operationType).Name()
+	}
+	query := &bytes.Buffer{}
+	if printErr := astprinter.PrintRedacted(&operation.document, &e.config.schema.document, nil, query); printErr == nil {
+		record.Query = query.String()
+	}
+	record.Plan, _ = json.Marshal(resolve.GetTrace(ctx, executionPlan.Response.Data, resolve.GetTraceDebug()))
+	if err != nil {
+		record.Error = err.Error()
+	}
+	e.config.slowOperationLog.Add(record)
+
+	e.logger.Warn("slow operation",
+		abstractlogger.String("operationName", record.OperationName),
+		abstractlogger.String("query", record.Query),
+		abstractlogger.String("duration", duration.String()),
+		abstractlogger.Int("fetches", len(record.Fetches)),
+	)
+}
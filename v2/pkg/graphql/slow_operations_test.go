@@ -0,0 +1,89 @@
+package graphql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/staticdatasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+)
+
+func TestSlowOperationLog(t *testing.T) {
+	log := NewSlowOperationLog(time.Second, 2)
+	assert.Empty(t, log.Operations())
+
+	log.Add(SlowOperation{OperationName: "a"})
+	assert.Equal(t, []SlowOperation{{OperationName: "a"}}, log.Operations())
+
+	log.Add(SlowOperation{OperationName: "b"})
+	log.Add(SlowOperation{OperationName: "c"})
+	assert.Equal(t, []SlowOperation{{OperationName: "c"}, {OperationName: "b"}}, log.Operations())
+}
+
+func TestExecutionEngineV2_SlowOperationLog(t *testing.T) {
+	schema, err := NewSchemaFromString(`type Query { hello(name: String): String }`)
+	require.NoError(t, err)
+
+	newEngine := func(t *testing.T, log *SlowOperationLog) *ExecutionEngineV2 {
+		engineConfig := NewEngineV2Configuration(schema)
+		engineConfig.SetSlowOperationLog(log)
+		engineConfig.SetDataSources([]plan.DataSourceConfiguration{
+			{
+				ID: "hello",
+				RootNodes: []plan.TypeField{
+					{TypeName: "Query", FieldNames: []string{"hello"}},
+				},
+				Factory: &staticdatasource.Factory{},
+				Custom: staticdatasource.ConfigJSON(staticdatasource.Configuration{
+					Data: `{"hello":"world"}`,
+				}),
+			},
+		})
+		engine, err := NewExecutionEngineV2(context.Background(), abstractlogger.NoopLogger, engineConfig)
+		require.NoError(t, err)
+		return engine
+	}
+
+	execute := func(t *testing.T, engine *ExecutionEngineV2) {
+		resultWriter := NewEngineResultWriter()
+		err := engine.Execute(context.Background(), &Request{OperationName: "Hello", Query: `query Hello { hello(name: "secret") }`}, &resultWriter)
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"hello":"world"}}`, resultWriter.String())
+	}
+
+	t.Run("should record operations exceeding the threshold", func(t *testing.T) {
+		engine := newEngine(t, NewSlowOperationLog(0, 10))
+		execute(t, engine)
+
+		operations := engine.SlowOperations()
+		require.Len(t, operations, 1)
+		operation := operations[0]
+		assert.Equal(t, "Hello", operation.OperationName)
+		assert.Equal(t, "query", operation.OperationType)
+		assert.Equal(t, "query Hello($a: String){hello(name: $a)}", operation.Query)
+		assert.NotContains(t, operation.Query, "secret")
+		assert.Greater(t, operation.Duration, time.Duration(0))
+		assert.Contains(t, string(operation.Plan), `"data_source_id":"hello"`)
+		require.Len(t, operation.Fetches, 1)
+		assert.Equal(t, "hello", operation.Fetches[0].DataSourceID)
+		assert.False(t, operation.Fetches[0].Failed)
+		assert.Empty(t, operation.Error)
+	})
+
+	t.Run("should not record fast operations", func(t *testing.T) {
+		engine := newEngine(t, NewSlowOperationLog(time.Hour, 10))
+		execute(t, engine)
+		assert.Empty(t, engine.SlowOperations())
+	})
+
+	t.Run("should return nil without a log", func(t *testing.T) {
+		engine := newEngine(t, nil)
+		execute(t, engine)
+		assert.Nil(t, engine.SlowOperations())
+	})
+}
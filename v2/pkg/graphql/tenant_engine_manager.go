@@ -0,0 +1,181 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jensneuse/abstractlogger"
+
+	graphqlDataSource "github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/graphql_datasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+type tenantEngineManagerOptions struct {
+	httpClient                *http.Client
+	streamingClient           *http.Client
+	subscriptionClientOptions []graphqlDataSource.Options
+	resolverOptions           resolve.ResolverOptions
+}
+
+type TenantEngineManagerOption func(options *tenantEngineManagerOptions)
+
+func WithTenantHttpClient(client *http.Client) TenantEngineManagerOption {
+	return func(options *tenantEngineManagerOptions) {
+		options.httpClient = client
+	}
+}
+
+func WithTenantStreamingClient(client *http.Client) TenantEngineManagerOption {
+	return func(options *tenantEngineManagerOptions) {
+		options.streamingClient = client
+	}
+}
+
+func WithTenantSubscriptionClientOptions(subscriptionClientOptions ...graphqlDataSource.Options) TenantEngineManagerOption {
+	return func(options *tenantEngineManagerOptions) {
+		options.subscriptionClientOptions = subscriptionClientOptions
+	}
+}
+
+// WithTenantResolverConfiguration configures the resolver shared by the tenants with the resolver settings of the configuration,
+// e.g. EnableArena, SetDataSourceMetrics or SetMetrics
+func WithTenantResolverConfiguration(engineConfig EngineV2Configuration) TenantEngineManagerOption {
+	return func(options *tenantEngineManagerOptions) {
+		options.resolverOptions = engineConfig.resolverOptions()
+	}
+}
+
+// TenantEngineManager maps tenant ids to engines with independent schemas, plan configurations and plan caches.
+// The engines of all tenants share the resolver and the clients of their graphql data sources:
+// every graphql data source of a tenant uses the http client, the streaming client and the subscription client of the manager,
+// so connections to the same upstream are pooled across tenants.
+// Data sources with their own subscription settings, e.g. an OnWsConnectionInitCallback or an EgressPolicy,
+// create their own subscription client with the shared http clients.
+type TenantEngineManager struct {
+	ctx      context.Context
+	logger   abstractlogger.Logger
+	resolver *resolve.Resolver
+
+	httpClient         *http.Client
+	streamingClient    *http.Client
+	subscriptionClient *graphqlDataSource.SubscriptionClient
+
+	mu      sync.RWMutex
+	engines map[string]*ExecutionEngineV2
+}
+
+func NewTenantEngineManager(ctx context.Context, logger abstractlogger.Logger, opts ...TenantEngineManagerOption) *TenantEngineManager {
+	var defaultConfig EngineV2Configuration
+	options := tenantEngineManagerOptions{
+		httpClient: &http.Client{
+			Timeout: time.Second * 10,
+			Transport: &http.Transport{
+				MaxIdleConnsPerHost: 1024,
+				TLSHandshakeTimeout: 0 * time.Second,
+			},
+		},
+		streamingClient: &http.Client{
+			Timeout: 0,
+		},
+		resolverOptions: defaultConfig.resolverOptions(),
+	}
+
+	for _, optFunc := range opts {
+		optFunc(&options)
+	}
+
+	subscriptionClientOptions := append([]graphqlDataSource.Options{graphqlDataSource.WithLogger(logger)}, options.subscriptionClientOptions...)
+
+	return &TenantEngineManager{
+		ctx:                ctx,
+		logger:             logger,
+		resolver:           resolve.New(ctx, options.resolverOptions),
+		httpClient:         options.httpClient,
+		streamingClient:    options.streamingClient,
+		subscriptionClient: graphqlDataSource.NewGraphQLSubscriptionClient(options.httpClient, options.streamingClient, ctx, subscriptionClientOptions...),
+		engines:            map[string]*ExecutionEngineV2{},
+	}
+}
+
+// SetTenant creates the engine of the tenant and replaces its previous engine including its plan cache.
+// Operations which are already executing on the previous engine are not affected.
+func (m *TenantEngineManager) SetTenant(tenantID string, engineConfig EngineV2Configuration) error {
+	engineConfig.SetDataSources(m.shareClients(engineConfig.DataSources()))
+
+	engine, err := newExecutionEngineV2(m.ctx, m.logger, engineConfig, m.resolver)
+	if err != nil {
+		return fmt.Errorf("tenant %q: %w", tenantID, err)
+	}
+
+	m.mu.Lock()
+	m.engines[tenantID] = engine
+	m.mu.Unlock()
+	return nil
+}
+
+// shareClients returns a copy of the data sources in which the graphql data sources use the clients of the manager
+func (m *TenantEngineManager) shareClients(dataSources []plan.DataSourceConfiguration) []plan.DataSourceConfiguration {
+	shared := make([]plan.DataSourceConfiguration, len(dataSources))
+	for i := range dataSources {
+		shared[i] = dataSources[i]
+		factory, ok := dataSources[i].Factory.(*graphqlDataSource.Factory)
+		if !ok {
+			continue
+		}
+		// the subscription client of the manager doesn't know the subscription settings of the factory
+		subscriptionClient := m.subscriptionClient
+		if factory.OnWsConnectionInitCallback != nil || factory.SubscriptionCallbackHandler != nil || factory.EgressPolicy != nil {
+			subscriptionClient = nil
+		}
+		sharedFactory := factory.WithClients(m.httpClient, m.streamingClient, subscriptionClient)
+		if sharedFactory.Logger == nil {
+			sharedFactory.Logger = m.logger
+		}
+		shared[i].Factory = sharedFactory
+	}
+	return shared
+}
+
+// RemoveTenant removes the engine of the tenant.
+func (m *TenantEngineManager) RemoveTenant(tenantID string) {
+	m.mu.Lock()
+	delete(m.engines, tenantID)
+	m.mu.Unlock()
+}
+
+// Engine returns the engine of the tenant.
+func (m *TenantEngineManager) Engine(tenantID string) (*ExecutionEngineV2, error) {
+	m.mu.RLock()
+	engine, ok := m.engines[tenantID]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown tenant %q", tenantID)
+	}
+	return engine, nil
+}
+
+// Execute executes the operation with the engine of the tenant.
+func (m *TenantEngineManager) Execute(ctx context.Context, tenantID string, operation *Request, writer resolve.SubscriptionResponseWriter, options ...ExecutionOptionsV2) error {
+	engine, err := m.Engine(tenantID)
+	if err != nil {
+		return err
+	}
+	return engine.Execute(ctx, operation, writer, options...)
+}
+
+// Tenants returns the sorted ids of the tenants.
+func (m *TenantEngineManager) Tenants() []string {
+	m.mu.RLock()
+	tenants := make([]string, 0, len(m.engines))
+	for tenantID := range m.engines {
+		tenants = append(tenants, tenantID)
+	}
+	m.mu.RUnlock()
+	sort.Strings(tenants)
+	return tenants
+}
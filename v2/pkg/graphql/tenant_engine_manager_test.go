@@ -0,0 +1,177 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	graphqlDataSource "github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/graphql_datasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/httpclient"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/staticdatasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+func TestTenantEngineManager(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	engineConfig := func(t *testing.T, schemaString string, data string) EngineV2Configuration {
+		schema, err := NewSchemaFromString(schemaString)
+		require.NoError(t, err)
+		engineConf := NewEngineV2Configuration(schema)
+		engineConf.SetDataSources([]plan.DataSourceConfiguration{
+			{
+				RootNodes: []plan.TypeField{
+					{TypeName: "Query", FieldNames: []string{"hello"}},
+				},
+				Factory: &staticdatasource.Factory{},
+				Custom: staticdatasource.ConfigJSON(staticdatasource.Configuration{
+					Data: data,
+				}),
+			},
+		})
+		return engineConf
+	}
+
+	execute := func(t *testing.T, manager *TenantEngineManager, tenantID string) (string, error) {
+		operation := Request{Query: `{ hello }`}
+		resultWriter := NewEngineResultWriter()
+		err := manager.Execute(ctx, tenantID, &operation, &resultWriter)
+		return resultWriter.String(), err
+	}
+
+	t.Run("should execute with the engine of the tenant", func(t *testing.T) {
+		manager := NewTenantEngineManager(ctx, abstractlogger.Noop{})
+		require.NoError(t, manager.SetTenant("acme", engineConfig(t, `type Query { hello: String }`, `{"hello":"acme"}`)))
+		require.NoError(t, manager.SetTenant("globex", engineConfig(t, `type Query { hello: String! }`, `{"hello":"globex"}`)))
+		assert.Equal(t, []string{"acme", "globex"}, manager.Tenants())
+
+		response, err := execute(t, manager, "acme")
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"hello":"acme"}}`, response)
+
+		response, err = execute(t, manager, "globex")
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"hello":"globex"}}`, response)
+
+		acme, err := manager.Engine("acme")
+		require.NoError(t, err)
+		globex, err := manager.Engine("globex")
+		require.NoError(t, err)
+		assert.Same(t, acme.resolver, globex.resolver)
+		assert.NotSame(t, acme.executionPlanCache, globex.executionPlanCache)
+	})
+
+	t.Run("should replace and remove tenants", func(t *testing.T) {
+		manager := NewTenantEngineManager(ctx, abstractlogger.Noop{})
+		require.NoError(t, manager.SetTenant("acme", engineConfig(t, `type Query { hello: String }`, `{"hello":"v1"}`)))
+		require.NoError(t, manager.SetTenant("acme", engineConfig(t, `type Query { hello: String }`, `{"hello":"v2"}`)))
+
+		response, err := execute(t, manager, "acme")
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"hello":"v2"}}`, response)
+
+		manager.RemoveTenant("acme")
+		_, err = execute(t, manager, "acme")
+		assert.EqualError(t, err, `unknown tenant "acme"`)
+		assert.Empty(t, manager.Tenants())
+	})
+
+	t.Run("should share the clients of graphql data sources", func(t *testing.T) {
+		httpClient := &http.Client{}
+		manager := NewTenantEngineManager(ctx, abstractlogger.Noop{}, WithTenantHttpClient(httpClient))
+
+		tenantFactory := &graphqlDataSource.Factory{HTTPClient: http.DefaultClient}
+		graphqlConfig := func(t *testing.T) EngineV2Configuration {
+			engineConf := engineConfig(t, `type Query { hello: String }`, `{}`)
+			engineConf.SetDataSources([]plan.DataSourceConfiguration{
+				{
+					RootNodes: []plan.TypeField{
+						{TypeName: "Query", FieldNames: []string{"hello"}},
+					},
+					Factory: tenantFactory,
+					Custom: graphqlDataSource.ConfigJson(graphqlDataSource.Configuration{
+						Fetch: graphqlDataSource.FetchConfiguration{URL: "http://localhost/graphql"},
+					}),
+				},
+			})
+			return engineConf
+		}
+		require.NoError(t, manager.SetTenant("acme", graphqlConfig(t)))
+		require.NoError(t, manager.SetTenant("globex", graphqlConfig(t)))
+
+		for _, tenantID := range []string{"acme", "globex"} {
+			engine, err := manager.Engine(tenantID)
+			require.NoError(t, err)
+			factory, ok := engine.config.DataSources()[0].Factory.(*graphqlDataSource.Factory)
+			require.True(t, ok)
+			assert.Same(t, httpClient, factory.HTTPClient)
+			assert.Same(t, manager.streamingClient, factory.StreamingClient)
+			assert.Same(t, manager.subscriptionClient, factory.SubscriptionClient)
+		}
+		assert.Same(t, http.DefaultClient, tenantFactory.HTTPClient)
+	})
+
+	t.Run("should keep the settings of graphql data sources", func(t *testing.T) {
+		manager := NewTenantEngineManager(ctx, abstractlogger.Noop{})
+
+		onWsConnectionInit := graphqlDataSource.OnWsConnectionInitCallback(func(ctx context.Context, url string, header http.Header) (json.RawMessage, error) {
+			return nil, nil
+		})
+		tenantFactory := &graphqlDataSource.Factory{
+			HTTPClient:                 http.DefaultClient,
+			OnWsConnectionInitCallback: &onWsConnectionInit,
+			RequestSigner:              &httpclient.HMACSigner{Secret: []byte("secret")},
+			EgressPolicy:               &httpclient.EgressPolicy{AllowedHosts: []string{"*.example.com"}},
+		}
+		engineConf := engineConfig(t, `type Query { hello: String }`, `{}`)
+		engineConf.SetDataSources([]plan.DataSourceConfiguration{
+			{
+				RootNodes: []plan.TypeField{
+					{TypeName: "Query", FieldNames: []string{"hello"}},
+				},
+				Factory: tenantFactory,
+				Custom: graphqlDataSource.ConfigJson(graphqlDataSource.Configuration{
+					Fetch: graphqlDataSource.FetchConfiguration{URL: "http://localhost/graphql"},
+				}),
+			},
+		})
+		require.NoError(t, manager.SetTenant("acme", engineConf))
+
+		engine, err := manager.Engine("acme")
+		require.NoError(t, err)
+		factory, ok := engine.config.DataSources()[0].Factory.(*graphqlDataSource.Factory)
+		require.True(t, ok)
+		assert.Same(t, manager.httpClient, factory.HTTPClient)
+		assert.Same(t, tenantFactory.OnWsConnectionInitCallback, factory.OnWsConnectionInitCallback)
+		assert.Same(t, tenantFactory.RequestSigner, factory.RequestSigner)
+		assert.Same(t, tenantFactory.EgressPolicy, factory.EgressPolicy)
+		// the subscription client is created with the settings of the data source
+		assert.Nil(t, factory.SubscriptionClient)
+	})
+
+	t.Run("should configure the shared resolver", func(t *testing.T) {
+		telemetry := resolve.NewDataSourceTelemetry()
+		resolverConfig := engineConfig(t, `type Query { hello: String }`, `{}`)
+		resolverConfig.SetDataSourceMetrics(telemetry)
+		manager := NewTenantEngineManager(ctx, abstractlogger.Noop{}, WithTenantResolverConfiguration(resolverConfig))
+
+		tenantConfig := engineConfig(t, `type Query { hello: String }`, `{"hello":"acme"}`)
+		tenantConfig.SetDataSourceMetrics(telemetry)
+		dataSources := tenantConfig.DataSources()
+		dataSources[0].ID = "hello"
+		tenantConfig.SetDataSources(dataSources)
+		require.NoError(t, manager.SetTenant("acme", tenantConfig))
+
+		response, err := execute(t, manager, "acme")
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"hello":"acme"}}`, response)
+		assert.Equal(t, uint64(1), telemetry.Snapshot()["hello"].Requests)
+	})
+}
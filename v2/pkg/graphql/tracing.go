@@ -0,0 +1,39 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+// ExecutionPhase is a phase of the execution of an operation, see OperationTracer
+type ExecutionPhase string
+
+const (
+	ExecutionPhaseParse     ExecutionPhase = "parse"
+	ExecutionPhaseNormalize ExecutionPhase = "normalize"
+	ExecutionPhaseValidate  ExecutionPhase = "validate"
+	ExecutionPhasePlan      ExecutionPhase = "plan"
+	ExecutionPhaseResolve   ExecutionPhase = "resolve"
+)
+
+// OperationTracer traces the phases of the execution of operations and the fetches of the resolver,
+// e.g. with OpenTelemetry spans, see package otel.
+// Phases which are skipped aren't traced, e.g. the validation and the planning of operations with a cached plan.
+type OperationTracer interface {
+	resolve.FetchTracer
+	// StartPhase is called before each phase of the execution of the operation, the fetches are started with the context of ExecutionPhaseResolve.
+	// end is called with the error of the phase
+	StartPhase(ctx context.Context, phase ExecutionPhase, operation *Request) (phaseCtx context.Context, end func(err error))
+}
+
+// tracePhase runs the phase with the context returned by the OperationTracer of the engine
+func (e *ExecutionEngineV2) tracePhase(ctx context.Context, phase ExecutionPhase, operation *Request, run func(ctx context.Context) error) error {
+	if e.config.operationTracer == nil {
+		return run(ctx)
+	}
+	ctx, end := e.config.operationTracer.StartPhase(ctx, phase, operation)
+	err := run(ctx)
+	end(err)
+	return err
+}
@@ -8,13 +8,34 @@ import (
 type ValidationResult struct {
 	Valid  bool
 	Errors Errors
+	// Stats are only computed for valid operations if requested with WithOperationStats
+	Stats *OperationStats
 }
 
-func (r *Request) ValidateForSchema(schema *Schema) (result ValidationResult, err error) {
+type validationOptions struct {
+	operationStats bool
+}
+
+type ValidationOption func(options *validationOptions)
+
+// WithOperationStats computes the OperationStats of valid operations, e.g. to log or enforce limits
+// without walking the document again
+func WithOperationStats() ValidationOption {
+	return func(options *validationOptions) {
+		options.operationStats = true
+	}
+}
+
+func (r *Request) ValidateForSchema(schema *Schema, opts ...ValidationOption) (result ValidationResult, err error) {
 	if schema == nil {
 		return ValidationResult{Valid: false, Errors: nil}, ErrNilSchema
 	}
 
+	var options validationOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	schemaHash := schema.Hash()
 
 	if r.validForSchema == nil {
@@ -22,6 +43,12 @@ func (r *Request) ValidateForSchema(schema *Schema) (result ValidationResult, er
 	}
 
 	if result, ok := r.validForSchema[schemaHash]; ok {
+		if options.operationStats && result.Stats == nil {
+			if result.Stats, err = r.operationStats(schema); err != nil {
+				return result, err
+			}
+			r.validForSchema[schemaHash] = result
+		}
 		return result, nil
 	}
 
@@ -36,6 +63,11 @@ func (r *Request) ValidateForSchema(schema *Schema) (result ValidationResult, er
 	if err != nil {
 		return result, err
 	}
+	if options.operationStats && result.Valid {
+		if result.Stats, err = r.operationStats(schema); err != nil {
+			return result, err
+		}
+	}
 	r.validForSchema[schemaHash] = result
 	return result, err
 }
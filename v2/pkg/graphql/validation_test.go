@@ -93,6 +93,52 @@ func TestRequest_ValidateForSchema(t *testing.T) {
 		assert.NoError(t, err)
 		assert.True(t, result.Valid)
 		assert.Nil(t, result.Errors)
+		assert.Nil(t, result.Stats)
+	})
+
+	t.Run("should return operation stats when requested", func(t *testing.T) {
+		schema := starwarsSchema(t)
+		request := Request{
+			Query: `query { first: hero { name friends { name } } second: hero { ...names } } fragment names on Character { name }`,
+		}
+		normalizationResult, err := request.Normalize(schema)
+		require.NoError(t, err)
+		require.True(t, normalizationResult.Successful)
+
+		result, err := request.ValidateForSchema(schema, WithOperationStats())
+		assert.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+		assert.Equal(t, &OperationStats{
+			Depth:      3,
+			FieldCount: 6,
+			AliasCount: 2,
+			NodeCount:  3,
+			Complexity: 3,
+		}, result.Stats)
+	})
+
+	t.Run("should add operation stats to a cached result", func(t *testing.T) {
+		schema := starwarsSchema(t)
+		request := requestForQuery(t, starwars.FileSimpleHeroQuery)
+
+		result, err := request.ValidateForSchema(schema)
+		assert.NoError(t, err)
+		assert.Nil(t, result.Stats)
+
+		result, err = request.ValidateForSchema(schema, WithOperationStats())
+		assert.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, &OperationStats{Depth: 2, FieldCount: 2, NodeCount: 1, Complexity: 1}, result.Stats)
+	})
+
+	t.Run("should not return operation stats for invalid operations", func(t *testing.T) {
+		schema := starwarsSchema(t)
+		request := Request{Query: `query { unknown }`}
+
+		result, err := request.ValidateForSchema(schema, WithOperationStats())
+		assert.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Nil(t, result.Stats)
 	})
 }
 
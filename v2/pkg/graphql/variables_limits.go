@@ -0,0 +1,114 @@
+package graphql
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/buger/jsonparser"
+)
+
+// VariablesLimits limits the variables of a request before they are parsed and coerced,
+// so large or deeply nested variables are rejected without allocating memory for them.
+// A limit of 0 disables the limit.
+type VariablesLimits struct {
+	// MaxSize is the maximum size of the variables json in bytes
+	MaxSize int
+	// MaxDepth is the maximum nesting depth of objects and lists, the variables object has a depth of 1
+	MaxDepth int
+	// MaxKeys is the maximum number of object keys of all objects in the variables
+	MaxKeys int
+}
+
+func (l VariablesLimits) enabled() bool {
+	return l.MaxSize > 0 || l.MaxDepth > 0 || l.MaxKeys > 0
+}
+
+// ValidateVariablesLimits checks the variables of the request against the limits
+// and rejects variables defined more than once, because json decoders disagree on which value wins.
+// Malformed json is not reported, it is rejected when the variables are parsed.
+func (r *Request) ValidateVariablesLimits(limits VariablesLimits) error {
+	return validateVariablesLimits(r.Variables, limits)
+}
+
+// ValidateVariablesLimits checks the raw variables json against the limits configured with SetVariablesLimits,
+// so transports can reject requests before they unmarshal the variables. It returns nil if no limits are configured.
+func (e *ExecutionEngineV2) ValidateVariablesLimits(variables []byte) error {
+	if !e.config.variablesLimits.enabled() {
+		return nil
+	}
+	return validateVariablesLimits(variables, e.config.variablesLimits)
+}
+
+func validateVariablesLimits(variables []byte, limits VariablesLimits) error {
+	if limits.MaxSize > 0 && len(variables) > limits.MaxSize {
+		return RequestErrors{{Message: fmt.Sprintf("variables exceed the maximum size of %d bytes", limits.MaxSize)}}
+	}
+
+	var (
+		depth, keys int
+		inString    bool
+		escaped     bool
+		keyStart    int
+		topLevel    map[string]struct{}
+	)
+
+	for i := 0; i < len(variables); i++ {
+		c := variables[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+			keyStart = i
+		case '{', '[':
+			depth++
+			if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+				return RequestErrors{{Message: fmt.Sprintf("variables exceed the maximum depth of %d", limits.MaxDepth)}}
+			}
+		case '}', ']':
+			depth--
+		case ':':
+			// outside of strings colons only follow object keys
+			keys++
+			if limits.MaxKeys > 0 && keys > limits.MaxKeys {
+				return RequestErrors{{Message: fmt.Sprintf("variables exceed the maximum of %d keys", limits.MaxKeys)}}
+			}
+			if depth != 1 {
+				continue
+			}
+			name := variableName(bytes.TrimRight(variables[keyStart:i], " \t\r\n"))
+			if topLevel == nil {
+				topLevel = make(map[string]struct{})
+			}
+			if _, exists := topLevel[name]; exists {
+				return RequestErrors{{Message: fmt.Sprintf("variable %q is defined more than once", name)}}
+			}
+			topLevel[name] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// variableName returns the unescaped name of a quoted object key,
+// so the same name written with different escapes, e.g. "a" and "\u0061", is detected as duplicate
+func variableName(key []byte) string {
+	if len(key) < 2 {
+		return string(key)
+	}
+	name, err := jsonparser.Unescape(key[1:len(key)-1], nil)
+	if err != nil {
+		return string(key)
+	}
+	return string(name)
+}
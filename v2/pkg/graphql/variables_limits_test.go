@@ -0,0 +1,83 @@
+package graphql
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequest_ValidateVariablesLimits(t *testing.T) {
+	run := func(t *testing.T, variables string, limits VariablesLimits, expectedError string) {
+		t.Helper()
+
+		request := Request{Variables: []byte(variables)}
+		err := request.ValidateVariablesLimits(limits)
+		if expectedError == "" {
+			assert.NoError(t, err)
+			return
+		}
+		require.IsType(t, RequestErrors{}, err)
+		assert.Equal(t, expectedError, err.(RequestErrors)[0].Message)
+	}
+
+	t.Run("should accept variables within the limits", func(t *testing.T) {
+		run(t, `{"a":{"b":[1,{"c":"d"}]},"e":"f"}`, VariablesLimits{MaxSize: 64, MaxDepth: 4, MaxKeys: 4}, "")
+	})
+	t.Run("should accept empty variables", func(t *testing.T) {
+		run(t, ``, VariablesLimits{MaxSize: 1, MaxDepth: 1, MaxKeys: 1}, "")
+	})
+	t.Run("should reject variables exceeding the size", func(t *testing.T) {
+		run(t, `{"a":"bcdefg"}`, VariablesLimits{MaxSize: 8}, "variables exceed the maximum size of 8 bytes")
+	})
+	t.Run("should reject variables exceeding the depth", func(t *testing.T) {
+		run(t, `{"a":[[{"b":1}]]}`, VariablesLimits{MaxDepth: 3}, "variables exceed the maximum depth of 3")
+	})
+	t.Run("should reject variables exceeding the number of keys", func(t *testing.T) {
+		run(t, `{"a":1,"b":{"c":2,"d":3}}`, VariablesLimits{MaxKeys: 3}, "variables exceed the maximum of 3 keys")
+	})
+	t.Run("should ignore brackets and colons in strings", func(t *testing.T) {
+		run(t, `{"a:[{":"}]:{\":[","b":"c"}`, VariablesLimits{MaxDepth: 1, MaxKeys: 2}, "")
+	})
+	t.Run("should reject variables defined more than once", func(t *testing.T) {
+		run(t, `{"a":1, "a" : {"a":2}}`, VariablesLimits{}, `variable "a" is defined more than once`)
+	})
+	t.Run("should reject variables defined more than once with different escapes", func(t *testing.T) {
+		run(t, `{"a":1,"\u0061":2}`, VariablesLimits{}, `variable "a" is defined more than once`)
+	})
+	t.Run("should accept equal keys of nested objects", func(t *testing.T) {
+		run(t, `{"a":{"c":1},"b":{"c":2}}`, VariablesLimits{}, "")
+	})
+	t.Run("should reject variables defined more than once among many variables", func(t *testing.T) {
+		variables := manyVariables(100_000)
+		run(t, string(variables), VariablesLimits{}, "")
+		run(t, string(variables[:len(variables)-1])+`,"v0":1}`, VariablesLimits{}, `variable "v0" is defined more than once`)
+	})
+}
+
+func BenchmarkRequest_ValidateVariablesLimits(b *testing.B) {
+	request := Request{Variables: manyVariables(10_000)}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := request.ValidateVariablesLimits(VariablesLimits{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// manyVariables returns a variables object with the variables v0 to vn-1
+func manyVariables(n int) []byte {
+	variables := []byte{'{'}
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			variables = append(variables, ',')
+		}
+		variables = append(variables, '"', 'v')
+		variables = strconv.AppendInt(variables, int64(i), 10)
+		variables = append(variables, `":1`...)
+	}
+	return append(variables, '}')
+}
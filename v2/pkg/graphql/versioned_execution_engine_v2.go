@@ -0,0 +1,77 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/jensneuse/abstractlogger"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+// VersionedExecutionEngineV2 executes operations against multiple named schema versions, e.g. v1, v2 and canary
+// during a blue/green rollout of a schema.
+// Every version has its own planner and plan cache. All versions share the resolver, datasource connections are shared
+// if the versions are configured with the same datasource factories.
+type VersionedExecutionEngineV2 struct {
+	engines        map[string]*ExecutionEngineV2
+	defaultVersion string
+}
+
+// NewVersionedExecutionEngineV2 creates an engine for each of the versions.
+// The default version is used for operations which don't select a version and must be one of the versions.
+// The resolver shared by the versions is configured with the resolver settings of the default version, e.g. EnableArena or SetMetrics.
+func NewVersionedExecutionEngineV2(ctx context.Context, logger abstractlogger.Logger, defaultVersion string, versions map[string]EngineV2Configuration) (*VersionedExecutionEngineV2, error) {
+	defaultConfig, ok := versions[defaultVersion]
+	if !ok {
+		return nil, fmt.Errorf("default schema version %q is not configured", defaultVersion)
+	}
+
+	resolver := resolve.New(ctx, defaultConfig.resolverOptions())
+
+	engines := make(map[string]*ExecutionEngineV2, len(versions))
+	for version, engineConfig := range versions {
+		engine, err := newExecutionEngineV2(ctx, logger, engineConfig, resolver)
+		if err != nil {
+			return nil, fmt.Errorf("schema version %q: %w", version, err)
+		}
+		engines[version] = engine
+	}
+
+	return &VersionedExecutionEngineV2{
+		engines:        engines,
+		defaultVersion: defaultVersion,
+	}, nil
+}
+
+// Engine returns the engine of the version or the engine of the default version if version is empty.
+func (v *VersionedExecutionEngineV2) Engine(version string) (*ExecutionEngineV2, error) {
+	if version == "" {
+		version = v.defaultVersion
+	}
+	engine, ok := v.engines[version]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema version %q", version)
+	}
+	return engine, nil
+}
+
+// Execute executes the operation against the schema of the version.
+func (v *VersionedExecutionEngineV2) Execute(ctx context.Context, version string, operation *Request, writer resolve.SubscriptionResponseWriter, options ...ExecutionOptionsV2) error {
+	engine, err := v.Engine(version)
+	if err != nil {
+		return err
+	}
+	return engine.Execute(ctx, operation, writer, options...)
+}
+
+// Versions returns the sorted names of the versions.
+func (v *VersionedExecutionEngineV2) Versions() []string {
+	versions := make([]string, 0, len(v.engines))
+	for version := range v.engines {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
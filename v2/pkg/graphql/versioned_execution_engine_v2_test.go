@@ -0,0 +1,102 @@
+package graphql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/staticdatasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+func TestVersionedExecutionEngineV2(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	engineConfig := func(t *testing.T, schemaString string, data string) EngineV2Configuration {
+		schema, err := NewSchemaFromString(schemaString)
+		require.NoError(t, err)
+		engineConf := NewEngineV2Configuration(schema)
+		engineConf.SetDataSources([]plan.DataSourceConfiguration{
+			{
+				ID: "hello",
+				RootNodes: []plan.TypeField{
+					{TypeName: "Query", FieldNames: []string{"hello"}},
+				},
+				Factory: &staticdatasource.Factory{},
+				Custom: staticdatasource.ConfigJSON(staticdatasource.Configuration{
+					Data: data,
+				}),
+			},
+		})
+		return engineConf
+	}
+
+	engine, err := NewVersionedExecutionEngineV2(ctx, abstractlogger.Noop{}, "v1", map[string]EngineV2Configuration{
+		"v1": engineConfig(t, `type Query { hello: String }`, `{"hello":"v1"}`),
+		"v2": engineConfig(t, `type Query { hello: String! }`, `{"hello":"v2"}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1", "v2"}, engine.Versions())
+
+	execute := func(t *testing.T, version string) (string, error) {
+		operation := Request{Query: `{ hello }`}
+		resultWriter := NewEngineResultWriter()
+		err := engine.Execute(ctx, version, &operation, &resultWriter)
+		return resultWriter.String(), err
+	}
+
+	t.Run("should execute against the selected version", func(t *testing.T) {
+		response, err := execute(t, "v2")
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"hello":"v2"}}`, response)
+	})
+
+	t.Run("should execute against the default version", func(t *testing.T) {
+		response, err := execute(t, "")
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"hello":"v1"}}`, response)
+	})
+
+	t.Run("should return an error for unknown versions", func(t *testing.T) {
+		_, err := execute(t, "v3")
+		assert.EqualError(t, err, `unknown schema version "v3"`)
+	})
+
+	t.Run("should share the resolver", func(t *testing.T) {
+		v1, err := engine.Engine("v1")
+		require.NoError(t, err)
+		v2, err := engine.Engine("v2")
+		require.NoError(t, err)
+		assert.Same(t, v1.resolver, v2.resolver)
+	})
+
+	t.Run("should configure the resolver with the settings of the default version", func(t *testing.T) {
+		telemetry := resolve.NewDataSourceTelemetry()
+		v1 := engineConfig(t, `type Query { hello: String }`, `{"hello":"v1"}`)
+		v1.SetDataSourceMetrics(telemetry)
+
+		engine, err := NewVersionedExecutionEngineV2(ctx, abstractlogger.Noop{}, "v1", map[string]EngineV2Configuration{
+			"v1": v1,
+			"v2": engineConfig(t, `type Query { hello: String! }`, `{"hello":"v2"}`),
+		})
+		require.NoError(t, err)
+
+		operation := Request{Query: `{ hello }`}
+		resultWriter := NewEngineResultWriter()
+		require.NoError(t, engine.Execute(ctx, "", &operation, &resultWriter))
+		assert.Equal(t, `{"data":{"hello":"v1"}}`, resultWriter.String())
+		assert.Equal(t, uint64(1), telemetry.Snapshot()["hello"].Requests)
+	})
+
+	t.Run("should require a configured default version", func(t *testing.T) {
+		_, err := NewVersionedExecutionEngineV2(ctx, abstractlogger.Noop{}, "canary", map[string]EngineV2Configuration{
+			"v1": engineConfig(t, `type Query { hello: String }`, `{"hello":"v1"}`),
+		})
+		assert.EqualError(t, err, `default schema version "canary" is not configured`)
+	})
+}
@@ -0,0 +1,14 @@
+// Package chiadapter serves the GraphQL http.Handler with chi routers.
+package chiadapter
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	graphqlhttp "github.com/wundergraph/graphql-go-tools/v2/pkg/http"
+)
+
+// Register serves GET and POST requests to the pattern with the handler, including websocket upgrades,
+// the handler rejects methods which are not enabled in its options
+func Register(router chi.Router, pattern string, handler *graphqlhttp.Handler) {
+	router.Handle(pattern, handler)
+}
@@ -0,0 +1,20 @@
+package chiadapter
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/http/adapters/internal/adapterstest"
+)
+
+func TestRegister(t *testing.T) {
+	router := chi.NewRouter()
+	Register(router, "/graphql", adapterstest.NewHandler(t))
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	adapterstest.AssertServes(t, server.URL+"/graphql")
+}
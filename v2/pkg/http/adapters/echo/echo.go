@@ -0,0 +1,25 @@
+// Package echoadapter serves the GraphQL http.Handler with echo.
+package echoadapter
+
+import (
+	"github.com/labstack/echo/v4"
+
+	graphqlhttp "github.com/wundergraph/graphql-go-tools/v2/pkg/http"
+)
+
+// Router is implemented by *echo.Echo and *echo.Group
+type Router interface {
+	Any(path string, handler echo.HandlerFunc, middleware ...echo.MiddlewareFunc) []*echo.Route
+}
+
+// Handler returns an echo.HandlerFunc serving the requests with the handler,
+// websocket upgrades hijack the connection of the echo response
+func Handler(handler *graphqlhttp.Handler) echo.HandlerFunc {
+	return echo.WrapHandler(handler)
+}
+
+// Register serves all requests to the path with the handler, including websocket upgrades,
+// the handler rejects methods which are not enabled in its options
+func Register(router Router, path string, handler *graphqlhttp.Handler, middleware ...echo.MiddlewareFunc) {
+	router.Any(path, Handler(handler), middleware...)
+}
@@ -0,0 +1,20 @@
+package echoadapter
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/http/adapters/internal/adapterstest"
+)
+
+func TestRegister(t *testing.T) {
+	e := echo.New()
+	Register(e.Group("/api"), "/graphql", adapterstest.NewHandler(t))
+
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+
+	adapterstest.AssertServes(t, server.URL+"/api/graphql")
+}
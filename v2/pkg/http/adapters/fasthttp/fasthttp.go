@@ -0,0 +1,138 @@
+// Package fasthttpadapter serves the GraphQL http.Handler with fasthttp.
+package fasthttpadapter
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+	graphqlhttp "github.com/wundergraph/graphql-go-tools/v2/pkg/http"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription/websocket"
+)
+
+// websocketGUID is appended to the key of the client to compute the accept key, see RFC 6455 section 1.3
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// NewRequestHandler returns a fasthttp.RequestHandler serving the requests with the handler.
+// POST and GET requests are served through fasthttpadaptor, which does not support hijacking,
+// so websocket upgrades are answered by the adapter and the hijacked connection is served with Handler.ServeWebsocketConn.
+func NewRequestHandler(handler *graphqlhttp.Handler) fasthttp.RequestHandler {
+	serveHTTP := fasthttpadaptor.NewFastHTTPHandler(handler)
+	return func(ctx *fasthttp.RequestCtx) {
+		if !ctx.IsGet() || !ctx.Request.Header.ConnectionUpgrade() {
+			serveHTTP(ctx)
+			return
+		}
+
+		r, err := newRequest(ctx)
+		if err != nil {
+			ctx.Error(err.Error(), fasthttp.StatusBadRequest)
+			return
+		}
+		if !handler.AcceptsWebsocket(r) {
+			serveHTTP(ctx)
+			return
+		}
+		upgradeWebsocket(ctx, handler, r)
+	}
+}
+
+// NewExecuteBodyHandler returns a fasthttp.RequestHandler executing the bodies of POST requests with Handler.ExecuteBody,
+// the requests and responses are not converted to net/http. Other methods are answered with 405 Method Not Allowed.
+// The EngineSelector and ExecutionOptions of the handler are not used, the options are passed to the engine instead.
+func NewExecuteBodyHandler(handler *graphqlhttp.Handler, engine *graphql.ExecutionEngineV2, options ...graphql.ExecutionOptionsV2) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		if !ctx.IsPost() {
+			ctx.Error(fasthttp.StatusMessage(fasthttp.StatusMethodNotAllowed), fasthttp.StatusMethodNotAllowed)
+			ctx.Response.Header.Set("Allow", fasthttp.MethodPost)
+			return
+		}
+
+		ctx.SetContentType("application/json")
+		ctx.SetStatusCode(handler.ExecuteBody(ctx, engine, &ctx.Request.Header, ctx.PostBody(), ctx, options...))
+	}
+}
+
+// newRequest copies the upgrade request, fasthttpadaptor.ConvertRequest can't be used
+// because the converted request references the buffers of the fasthttp request, which are reused after the hijack
+func newRequest(ctx *fasthttp.RequestCtx) (*http.Request, error) {
+	requestURI := string(ctx.RequestURI())
+	requestURL, err := url.ParseRequestURI(requestURI)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &http.Request{
+		Method:     string(ctx.Method()),
+		URL:        requestURL,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Body:       http.NoBody,
+		Host:       string(ctx.Host()),
+		RemoteAddr: ctx.RemoteAddr().String(),
+		RequestURI: requestURI,
+	}
+	ctx.Request.Header.VisitAll(func(key, value []byte) {
+		r.Header.Add(string(key), string(value))
+	})
+	return r, nil
+}
+
+// upgradeWebsocket answers the websocket handshake and serves the hijacked connection,
+// the protocol is negotiated from the Sec-WebSocket-Protocol header of the request
+func upgradeWebsocket(ctx *fasthttp.RequestCtx, handler *graphqlhttp.Handler, r *http.Request) {
+	key := ctx.Request.Header.Peek("Sec-WebSocket-Key")
+	if len(key) == 0 || string(ctx.Request.Header.Peek("Sec-WebSocket-Version")) != "13" {
+		ctx.Error("invalid websocket handshake", fasthttp.StatusBadRequest)
+		return
+	}
+
+	ctx.Response.Header.Set("Upgrade", "websocket")
+	ctx.Response.Header.Set("Connection", "Upgrade")
+	ctx.Response.Header.Set("Sec-WebSocket-Accept", acceptKey(key))
+	if protocol := websocket.ProtocolFromRequestHeaders(r.Header); protocol != websocket.ProtocolUndefined {
+		// the negotiated protocol has to be confirmed in the response, otherwise clients close the connection
+		ctx.Response.Header.Set(websocket.HeaderSecWebSocketProtocol, string(protocol))
+	}
+	ctx.SetStatusCode(fasthttp.StatusSwitchingProtocols)
+
+	ctx.Hijack(func(conn net.Conn) {
+		// fasthttp closes the hijacked connection when the hijack handler returns,
+		// so the handler waits until the websocket connection is closed
+		closingConn := &closeNotifyConn{Conn: conn, closed: make(chan struct{})}
+		if err := handler.ServeWebsocketConn(closingConn, r); err != nil {
+			return
+		}
+		<-closingConn.closed
+	})
+}
+
+// closeNotifyConn notifies when the connection is closed by the websocket handler
+type closeNotifyConn struct {
+	net.Conn
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (c *closeNotifyConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+	return c.Conn.Close()
+}
+
+func acceptKey(key []byte) string {
+	hash := sha1.New()
+	_, _ = hash.Write(key)
+	_, _ = hash.Write([]byte(websocketGUID))
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil))
+}
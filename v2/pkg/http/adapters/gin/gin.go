@@ -0,0 +1,20 @@
+// Package ginadapter serves the GraphQL http.Handler with gin.
+package ginadapter
+
+import (
+	"github.com/gin-gonic/gin"
+
+	graphqlhttp "github.com/wundergraph/graphql-go-tools/v2/pkg/http"
+)
+
+// Handler returns a gin.HandlerFunc serving the requests with the handler,
+// websocket upgrades hijack the connection of the gin response writer
+func Handler(handler *graphqlhttp.Handler) gin.HandlerFunc {
+	return gin.WrapH(handler)
+}
+
+// Register serves all requests to the path with the handler, including websocket upgrades,
+// the handler rejects methods which are not enabled in its options
+func Register(router gin.IRoutes, path string, handler *graphqlhttp.Handler) {
+	router.Any(path, Handler(handler))
+}
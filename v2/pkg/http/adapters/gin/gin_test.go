@@ -0,0 +1,21 @@
+package ginadapter
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/http/adapters/internal/adapterstest"
+)
+
+func TestRegister(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	Register(router, "/graphql", adapterstest.NewHandler(t))
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	adapterstest.AssertServes(t, server.URL+"/graphql")
+}
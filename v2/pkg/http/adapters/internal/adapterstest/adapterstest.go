@@ -0,0 +1,98 @@
+// Package adapterstest provides the handler and the assertions shared by the tests of the adapters.
+package adapterstest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/staticdatasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+	graphqlhttp "github.com/wundergraph/graphql-go-tools/v2/pkg/http"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription/websocket"
+)
+
+// NewHandler returns a handler resolving { hello } with "world", serving GET requests and websockets
+func NewHandler(t testing.TB) *graphqlhttp.Handler {
+	t.Helper()
+
+	return graphqlhttp.NewHandler(NewEngine(t), graphqlhttp.HandlerOptions{
+		EnableGET: true,
+		Websocket: graphqlhttp.WebsocketOptions{Upgrader: &ws.HTTPUpgrader{}},
+	})
+}
+
+// NewEngine returns an engine resolving { hello } with "world"
+func NewEngine(t testing.TB) *graphql.ExecutionEngineV2 {
+	t.Helper()
+
+	schema, err := graphql.NewSchemaFromString(`type Query { hello: String }`)
+	require.NoError(t, err)
+
+	engineConf := graphql.NewEngineV2Configuration(schema)
+	engineConf.SetDataSources([]plan.DataSourceConfiguration{
+		{
+			RootNodes: []plan.TypeField{
+				{TypeName: "Query", FieldNames: []string{"hello"}},
+			},
+			Factory: &staticdatasource.Factory{},
+			Custom: staticdatasource.ConfigJSON(staticdatasource.Configuration{
+				Data: `{"hello":"world"}`,
+			}),
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	engine, err := graphql.NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+	require.NoError(t, err)
+	return engine
+}
+
+// AssertServes asserts that the url serves operations sent with POST and GET requests and over websockets
+func AssertServes(t *testing.T, url string) {
+	t.Helper()
+
+	response, err := http.Post(url, "application/json", strings.NewReader(`{"query":"{ hello }"}`))
+	require.NoError(t, err)
+	assertResponse(t, response)
+
+	response, err = http.Get(url + "?query=%7B+hello+%7D")
+	require.NoError(t, err)
+	assertResponse(t, response)
+
+	dialer := ws.Dialer{Protocols: []string{string(websocket.ProtocolGraphQLTransportWS)}}
+	conn, _, handshake, err := dialer.Dial(context.Background(), "ws"+strings.TrimPrefix(url, "http"))
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, string(websocket.ProtocolGraphQLTransportWS), handshake.Protocol)
+
+	require.NoError(t, wsutil.WriteClientMessage(conn, ws.OpText, []byte(`{"type":"connection_init"}`)))
+	message, err := wsutil.ReadServerText(conn)
+	require.NoError(t, err)
+	assert.Equal(t, `{"type":"connection_ack"}`, string(message))
+
+	require.NoError(t, wsutil.WriteClientMessage(conn, ws.OpText, []byte(`{"id":"1","type":"subscribe","payload":{"query":"{ hello }"}}`)))
+	message, err = wsutil.ReadServerText(conn)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"1","type":"next","payload":{"data":{"hello":"world"}}}`, string(message))
+}
+
+func assertResponse(t *testing.T, response *http.Response) {
+	t.Helper()
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, `{"data":{"hello":"world"}}`, string(body))
+}
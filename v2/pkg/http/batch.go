@@ -0,0 +1,53 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/jensneuse/abstractlogger"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+// serveBatch executes the operations of a batch concurrently and writes the responses as json array in the order of the batch
+func (h *Handler) serveBatch(w http.ResponseWriter, r *http.Request, engine *graphql.ExecutionEngineV2, requests []*request) {
+	buf := h.executeBatch(r.Context(), r.Method, r.Header, engine, requests, h.executionOptions(r))
+
+	w.Header().Set(httpHeaderContentType, httpContentTypeApplicationJson)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		h.options.Logger.Error("http.Handler.serveBatch",
+			abstractlogger.String("message", "could not write response"),
+			abstractlogger.Error(err),
+		)
+	}
+}
+
+// executeBatch executes the operations of a batch concurrently and returns the json array of the responses
+func (h *Handler) executeBatch(ctx context.Context, method string, header http.Header, engine *graphql.ExecutionEngineV2, requests []*request, options []graphql.ExecutionOptionsV2) *bytes.Buffer {
+	responses := make([]*bytes.Buffer, len(requests))
+	wg := &sync.WaitGroup{}
+	wg.Add(len(requests))
+	for i := range requests {
+		go func(i int) {
+			defer wg.Done()
+			responses[i] = &bytes.Buffer{}
+			// the status of single operations is not applicable to the batch, errors are part of the responses
+			_ = h.executeRequest(ctx, method, header, engine, requests[i], options, responses[i])
+		}(i)
+	}
+	wg.Wait()
+
+	buf := &bytes.Buffer{}
+	buf.WriteByte('[')
+	for i := range responses {
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(responses[i].Bytes())
+	}
+	buf.WriteByte(']')
+	return buf
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+// RequestHeader provides the headers of a request independent of the http server.
+// *fasthttp.RequestHeader implements it, net/http headers are adapted with Header.
+type RequestHeader interface {
+	VisitAll(f func(key, value []byte))
+}
+
+// Header adapts a net/http header to RequestHeader
+type Header http.Header
+
+func (h Header) VisitAll(f func(key, value []byte)) {
+	for key, values := range h {
+		for _, value := range values {
+			f([]byte(key), []byte(value))
+		}
+	}
+}
+
+// httpHeader converts the header for the operation, net/http headers are forwarded without copying them
+func httpHeader(header RequestHeader) http.Header {
+	switch header := header.(type) {
+	case nil:
+		return nil
+	case Header:
+		return http.Header(header)
+	}
+
+	// the keys and values are copied into one string and the values share one slice,
+	// so the conversion allocates independent of the number of headers
+	var (
+		fields strings.Builder
+		count  int
+	)
+	header.VisitAll(func(key, value []byte) {
+		fields.Grow(len(key) + len(value))
+		count++
+	})
+	header.VisitAll(func(key, value []byte) {
+		fields.Write(key)
+		fields.Write(value)
+	})
+
+	converted := make(http.Header, count)
+	values := make([]string, count)
+	data, offset, i := fields.String(), 0, 0
+	header.VisitAll(func(key, value []byte) {
+		name := textproto.CanonicalMIMEHeaderKey(data[offset : offset+len(key)])
+		offset += len(key)
+		values[i] = data[offset : offset+len(value)]
+		offset += len(value)
+
+		if existing, ok := converted[name]; ok {
+			converted[name] = append(existing, values[i])
+		} else {
+			converted[name] = values[i : i+1 : i+1]
+		}
+		i++
+	})
+	return converted
+}
+
+// ExecuteBody executes the body of a POST request and writes the json response to w.
+// The returned status is the http status of the response, the content type is always application/json.
+//
+// ExecuteBody does not depend on a net/http request, so it can serve requests of http servers like fasthttp
+// without converting them:
+//
+//	func(ctx *fasthttp.RequestCtx) {
+//		ctx.SetContentType("application/json")
+//		ctx.SetStatusCode(handler.ExecuteBody(ctx, engine, &ctx.Request.Header, ctx.PostBody(), ctx))
+//	}
+//
+// The header is forwarded to the operation and can be nil, net/http headers are passed as Header. Batching and persisted queries are applied
+// as configured in the HandlerOptions, the EngineSelector and ExecutionOptions of the Handler are not used
+// because they depend on the net/http request, the options are passed to the engine instead.
+func (h *Handler) ExecuteBody(ctx context.Context, engine *graphql.ExecutionEngineV2, header RequestHeader, body []byte, w io.Writer, options ...graphql.ExecutionOptionsV2) (status int) {
+	requests, isBatch, err := h.requestsFromBytes(engine, body)
+	if err != nil {
+		_, _ = graphql.RequestErrorsFromError(err).WriteResponse(w)
+		return http.StatusBadRequest
+	}
+
+	operationHeader := httpHeader(header)
+	var buf *bytes.Buffer
+	if isBatch {
+		buf = h.executeBatch(ctx, http.MethodPost, operationHeader, engine, requests, options)
+		status = http.StatusOK
+	} else {
+		buf = &bytes.Buffer{}
+		status = h.executeRequest(ctx, http.MethodPost, operationHeader, engine, requests[0], options, buf)
+	}
+
+	_, _ = w.Write(buf.Bytes())
+	return status
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_ExecuteBody(t *testing.T) {
+	engine := newTestEngine(t)
+
+	execute := func(handler *Handler, body string) (int, string) {
+		buf := &bytes.Buffer{}
+		status := handler.ExecuteBody(context.Background(), engine, nil, []byte(body), buf)
+		return status, buf.String()
+	}
+
+	t.Run("should execute the operation", func(t *testing.T) {
+		status, response := execute(NewHandler(engine, HandlerOptions{}), `{"query":"{ hello }"}`)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, `{"data":{"hello":"world"}}`, response)
+	})
+
+	t.Run("should reject invalid requests", func(t *testing.T) {
+		status, response := execute(NewHandler(engine, HandlerOptions{}), `{"query":`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Contains(t, response, `"errors"`)
+	})
+
+	t.Run("should reject subscriptions", func(t *testing.T) {
+		status, response := execute(NewHandler(engine, HandlerOptions{}), `{"query":"subscription { hello }"}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Contains(t, response, errSubscriptionTransport.Error())
+	})
+
+	t.Run("should execute batches", func(t *testing.T) {
+		handler := NewHandler(engine, HandlerOptions{Batching: BatchingOptions{Enabled: true}})
+		status, response := execute(handler, `[{"query":"{ hello }"},{"query":"mutation { hello }"}]`)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, `[{"data":{"hello":"world"}},{"data":{"hello":"world"}}]`, response)
+	})
+}
+
+type visitedHeader [][2]string
+
+func (h visitedHeader) VisitAll(f func(key, value []byte)) {
+	for _, field := range h {
+		f([]byte(field[0]), []byte(field[1]))
+	}
+}
+
+func TestHttpHeader(t *testing.T) {
+	t.Run("should keep nil headers", func(t *testing.T) {
+		assert.Nil(t, httpHeader(nil))
+	})
+
+	t.Run("should forward net/http headers", func(t *testing.T) {
+		header := http.Header{"Authorization": []string{"token"}}
+		assert.Equal(t, header, httpHeader(Header(header)))
+	})
+
+	t.Run("should convert visited headers", func(t *testing.T) {
+		header := visitedHeader{{"authorization", "token"}, {"X-Tenant", "a"}, {"X-Tenant", "b"}}
+		assert.Equal(t, http.Header{
+			"Authorization": []string{"token"},
+			"X-Tenant":      []string{"a", "b"},
+		}, httpHeader(header))
+	})
+}
+
+func BenchmarkHandler_ExecuteBody(b *testing.B) {
+	engine := newTestEngine(b)
+	handler := NewHandler(engine, HandlerOptions{})
+	body := []byte(`{"query":"{ hello }"}`)
+
+	b.Run("ServeHTTP", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			r := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+		}
+	})
+
+	b.Run("ExecuteBody", func(b *testing.B) {
+		b.ReportAllocs()
+		buf := &bytes.Buffer{}
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			_ = handler.ExecuteBody(context.Background(), engine, nil, body, buf)
+		}
+	})
+}
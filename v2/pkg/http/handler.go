@@ -0,0 +1,269 @@
+// Package http provides a http.Handler serving GraphQL operations with the ExecutionEngineV2
+// over POST and GET requests, batched requests, server-sent events and websockets.
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gobwas/ws"
+	"github.com/jensneuse/abstractlogger"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription/websocket"
+)
+
+const (
+	httpHeaderUpgrade      = "Upgrade"
+	httpHeaderContentType  = "Content-Type"
+	httpHeaderAccept       = "Accept"
+	httpHeaderCacheControl = "Cache-Control"
+	httpHeaderConnection   = "Connection"
+	httpHeaderAllow        = "Allow"
+
+	httpContentTypeApplicationJson = "application/json"
+	httpContentTypeEventStream     = "text/event-stream"
+
+	DefaultMaxBatchSize = 32
+)
+
+// HandlerOptions configures the transports of the Handler
+// Transports are disabled by their zero value, so only POST requests are served by default
+type HandlerOptions struct {
+	Logger abstractlogger.Logger
+	// EnableGET allows to execute queries with GET requests, mutations and subscriptions are rejected
+	EnableGET bool
+	// EnableSSE allows to execute operations with server-sent events if the client accepts text/event-stream
+	EnableSSE bool
+	// Batching allows to send multiple operations in a single POST request as json array
+	Batching BatchingOptions
+	// MaxRequestBodySize limits the size of the body of POST requests in bytes,
+	// larger requests are rejected with 413 Request Entity Too Large, 0 means no limit
+	MaxRequestBodySize int64
+	// PersistedQueries enables automatic persisted queries if a cache is set
+	PersistedQueries PersistedQueryOptions
+	// Websocket enables subscriptions over websockets if an upgrader is set
+	Websocket WebsocketOptions
+	// ExecutionOptions is called for every operation to set per request execution options, e.g. to forward headers
+	ExecutionOptions func(r *http.Request) []graphql.ExecutionOptionsV2
+	// ResponseHeaders allows datasources and hooks to set headers on the response of single POST and GET requests,
+	// it also enables the Cache-Control header computed from the @cacheControl hints of the schema
+	ResponseHeaders *resolve.ResponseHeaderOptions
+}
+
+// BatchingOptions configures the execution of batched requests
+type BatchingOptions struct {
+	Enabled bool
+	// MaxBatchSize limits the number of operations of a batch, defaults to DefaultMaxBatchSize
+	MaxBatchSize int
+}
+
+// WebsocketOptions configures the upgrade of requests to websocket connections
+type WebsocketOptions struct {
+	Upgrader *ws.HTTPUpgrader
+	// HandleOptions are passed to websocket.Handle, the protocol is negotiated from the request headers
+	HandleOptions []websocket.HandleOptionFunc
+}
+
+// EngineSelector returns the engine executing the operations of a request,
+// e.g. the engine of the schema version or of the tenant of the request
+type EngineSelector func(r *http.Request) (*graphql.ExecutionEngineV2, error)
+
+// Handler serves GraphQL operations with the ExecutionEngineV2
+type Handler struct {
+	selectEngine EngineSelector
+	options      HandlerOptions
+}
+
+// NewHandler creates a Handler for the engine with the given options
+func NewHandler(engine *graphql.ExecutionEngineV2, options HandlerOptions) *Handler {
+	return NewHandlerWithEngineSelector(func(r *http.Request) (*graphql.ExecutionEngineV2, error) {
+		return engine, nil
+	}, options)
+}
+
+// NewHandlerWithEngineSelector creates a Handler which selects the engine per request
+// Requests are rejected if no engine can be selected
+func NewHandlerWithEngineSelector(selectEngine EngineSelector, options HandlerOptions) *Handler {
+	if options.Logger == nil {
+		options.Logger = abstractlogger.Noop{}
+	}
+	if options.Batching.MaxBatchSize <= 0 {
+		options.Batching.MaxBatchSize = DefaultMaxBatchSize
+	}
+	return &Handler{
+		selectEngine: selectEngine,
+		options:      options,
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	engine, err := h.selectEngine(r)
+	if err != nil {
+		h.writeRequestErrors(w, http.StatusBadRequest, graphql.RequestErrorsFromError(err))
+		return
+	}
+
+	if h.AcceptsWebsocket(r) {
+		if err := h.upgradeWebsocket(w, r, engine); err != nil {
+			h.options.Logger.Error("http.Handler.ServeHTTP",
+				abstractlogger.String("message", "could not upgrade to websocket"),
+				abstractlogger.Error(err),
+			)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		return
+	}
+
+	var (
+		requests []*request
+		isBatch  bool
+	)
+	switch r.Method {
+	case http.MethodPost:
+		requests, isBatch, err = h.requestsFromBody(w, r, engine)
+	case http.MethodGet:
+		if !h.options.EnableGET {
+			h.writeMethodNotAllowed(w)
+			return
+		}
+		var req *request
+		req, err = requestFromQueryParameters(r, engine)
+		requests = []*request{req}
+	default:
+		h.writeMethodNotAllowed(w)
+		return
+	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		h.writeRequestErrors(w, http.StatusRequestEntityTooLarge, graphql.RequestErrorsFromError(err))
+		return
+	}
+	if err != nil {
+		h.writeRequestErrors(w, http.StatusBadRequest, graphql.RequestErrorsFromError(err))
+		return
+	}
+
+	if h.options.EnableSSE && !isBatch && h.acceptsEventStream(r) {
+		h.serveSSE(w, r, engine, requests[0])
+		return
+	}
+
+	if isBatch {
+		h.serveBatch(w, r, engine, requests)
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	options := h.executionOptions(r)
+	var responseHeaders *resolve.ResponseHeaders
+	if h.options.ResponseHeaders != nil {
+		responseHeaders = resolve.NewResponseHeaders(*h.options.ResponseHeaders)
+		options = append(options[:len(options):len(options)], graphql.WithResponseHeaders(responseHeaders))
+	}
+	status := h.executeRequest(r.Context(), r.Method, r.Header, engine, requests[0], options, buf)
+	responseHeaders.Apply(w.Header())
+	w.Header().Set(httpHeaderContentType, httpContentTypeApplicationJson)
+	w.WriteHeader(status)
+	if _, err = w.Write(buf.Bytes()); err != nil {
+		h.options.Logger.Error("http.Handler.ServeHTTP",
+			abstractlogger.String("message", "could not write response"),
+			abstractlogger.Error(err),
+		)
+	}
+}
+
+// executeRequest executes a single operation independent of net/http, the header is forwarded to the operation
+// errors of the request are written as GraphQL errors, the returned status is the http status of the response
+func (h *Handler) executeRequest(ctx context.Context, method string, header http.Header, engine *graphql.ExecutionEngineV2, req *request, options []graphql.ExecutionOptionsV2, buf *bytes.Buffer) (status int) {
+	operation, errs := h.resolveOperation(ctx, header, engine, req)
+	if errs != nil {
+		_, _ = errs.WriteResponse(buf)
+		return http.StatusOK
+	}
+
+	return h.executeOperation(ctx, method, engine, operation, options, buf)
+}
+
+// executeOperation executes an operation with resolved persisted query and writes the response to buf
+func (h *Handler) executeOperation(ctx context.Context, method string, engine *graphql.ExecutionEngineV2, operation *graphql.Request, options []graphql.ExecutionOptionsV2, buf *bytes.Buffer) (status int) {
+	operationType, err := operation.OperationType()
+	if err != nil {
+		_, _ = graphql.RequestErrorsFromError(err).WriteResponse(buf)
+		return http.StatusOK
+	}
+	switch {
+	case operationType == graphql.OperationTypeSubscription:
+		_, _ = graphql.RequestErrorsFromError(errSubscriptionTransport).WriteResponse(buf)
+		return http.StatusBadRequest
+	case method == http.MethodGet && operationType != graphql.OperationTypeQuery:
+		_, _ = graphql.RequestErrorsFromError(errGETOnlyQueries).WriteResponse(buf)
+		return http.StatusMethodNotAllowed
+	}
+
+	resultWriter := graphql.NewEngineResultWriterFromBuffer(buf)
+	if err = engine.Execute(ctx, operation, &resultWriter, options...); err != nil {
+		buf.Reset()
+		_, _ = graphql.RequestErrorsFromError(err).WriteResponse(buf)
+	}
+	return http.StatusOK
+}
+
+func (h *Handler) executionOptions(r *http.Request) []graphql.ExecutionOptionsV2 {
+	if h.options.ExecutionOptions == nil {
+		return nil
+	}
+	return h.options.ExecutionOptions(r)
+}
+
+// AcceptsWebsocket reports if the request asks for a websocket upgrade and websockets are enabled in the options,
+// e.g. for routers not based on net/http which upgrade the connection themselves and serve it with ServeWebsocketConn
+func (h *Handler) AcceptsWebsocket(r *http.Request) bool {
+	return h.options.Websocket.Upgrader != nil && h.isWebsocketUpgrade(r)
+}
+
+// isWebsocketUpgrade reports if the request asks for a websocket upgrade,
+// the protocols of the Upgrade header are case-insensitive and can be listed comma-separated
+func (h *Handler) isWebsocketUpgrade(r *http.Request) bool {
+	for _, header := range r.Header.Values(httpHeaderUpgrade) {
+		for _, protocol := range strings.Split(header, ",") {
+			if strings.EqualFold(strings.TrimSpace(protocol), "websocket") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (h *Handler) acceptsEventStream(r *http.Request) bool {
+	for _, accept := range r.Header.Values(httpHeaderAccept) {
+		if bytes.Contains([]byte(accept), []byte(httpContentTypeEventStream)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Handler) writeMethodNotAllowed(w http.ResponseWriter) {
+	allowed := http.MethodPost
+	if h.options.EnableGET {
+		allowed = http.MethodGet + ", " + http.MethodPost
+	}
+	w.Header().Set(httpHeaderAllow, allowed)
+	w.WriteHeader(http.StatusMethodNotAllowed)
+}
+
+func (h *Handler) writeRequestErrors(w http.ResponseWriter, status int, errs graphql.RequestErrors) {
+	w.Header().Set(httpHeaderContentType, httpContentTypeApplicationJson)
+	w.WriteHeader(status)
+	if _, err := errs.WriteResponse(w); err != nil {
+		h.options.Logger.Error("http.Handler.writeRequestErrors",
+			abstractlogger.String("message", "could not write response"),
+			abstractlogger.Error(err),
+		)
+	}
+}
@@ -0,0 +1,391 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/staticdatasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+const testSchema = `
+	type Query {
+		hello: String
+	}
+
+	type Mutation {
+		hello: String
+	}
+`
+
+func newTestEngineConfiguration(t testing.TB, data string) graphql.EngineV2Configuration {
+	t.Helper()
+
+	schema, err := graphql.NewSchemaFromString(testSchema)
+	require.NoError(t, err)
+
+	engineConf := graphql.NewEngineV2Configuration(schema)
+	engineConf.SetDataSources([]plan.DataSourceConfiguration{
+		{
+			RootNodes: []plan.TypeField{
+				{TypeName: "Query", FieldNames: []string{"hello"}},
+				{TypeName: "Mutation", FieldNames: []string{"hello"}},
+			},
+			Factory: &staticdatasource.Factory{},
+			Custom: staticdatasource.ConfigJSON(staticdatasource.Configuration{
+				Data: data,
+			}),
+		},
+	})
+	return engineConf
+}
+
+func newTestEngine(t testing.TB) *graphql.ExecutionEngineV2 {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	engine, err := graphql.NewExecutionEngineV2(ctx, abstractlogger.Noop{}, newTestEngineConfiguration(t, `{"hello":"world"}`))
+	require.NoError(t, err)
+	return engine
+}
+
+func TestHandler_isWebsocketUpgrade(t *testing.T) {
+	handler := NewHandler(nil, HandlerOptions{})
+
+	for _, tc := range []struct {
+		upgrade  []string
+		expected bool
+	}{
+		{upgrade: []string{"websocket"}, expected: true},
+		{upgrade: []string{"WebSocket"}, expected: true},
+		{upgrade: []string{"h2c, websocket"}, expected: true},
+		{upgrade: []string{"h2c", "WEBSOCKET"}, expected: true},
+		{upgrade: []string{"h2c"}, expected: false},
+		{upgrade: nil, expected: false},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+		r.Header[httpHeaderUpgrade] = tc.upgrade
+		assert.Equal(t, tc.expected, handler.isWebsocketUpgrade(r), "Upgrade: %v", tc.upgrade)
+	}
+}
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	engine := newTestEngine(t)
+
+	serve := func(handler http.Handler, r *http.Request) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, r)
+		return recorder
+	}
+
+	post := func(body string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	}
+
+	get := func(query string) *http.Request {
+		return httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape(query), nil)
+	}
+
+	t.Run("POST", func(t *testing.T) {
+		handler := NewHandler(engine, HandlerOptions{})
+
+		t.Run("should execute the operation", func(t *testing.T) {
+			recorder := serve(handler, post(`{"query":"{ hello }"}`))
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, httpContentTypeApplicationJson, recorder.Header().Get(httpHeaderContentType))
+			assert.Equal(t, `{"data":{"hello":"world"}}`, recorder.Body.String())
+		})
+
+		t.Run("should respond with validation errors", func(t *testing.T) {
+			recorder := serve(handler, post(`{"query":"{ goodbye }"}`))
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), `"errors":[{"message":"field: goodbye not defined on type: Query"`)
+		})
+
+		t.Run("should reject invalid requests", func(t *testing.T) {
+			recorder := serve(handler, post(`{"query":`))
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+
+		t.Run("should reject bodies exceeding the max request body size", func(t *testing.T) {
+			handler := NewHandler(engine, HandlerOptions{MaxRequestBodySize: 24})
+
+			recorder := serve(handler, post(`{"query":"{ hello }"}`))
+			assert.Equal(t, http.StatusOK, recorder.Code)
+
+			recorder = serve(handler, post(`{"query":"{ hello hello }"}`))
+			assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), `"errors":[{"message":"http: request body too large"`)
+		})
+	})
+
+	t.Run("GET", func(t *testing.T) {
+		t.Run("should be rejected if disabled", func(t *testing.T) {
+			recorder := serve(NewHandler(engine, HandlerOptions{}), get(`{ hello }`))
+			assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+			assert.Equal(t, http.MethodPost, recorder.Header().Get(httpHeaderAllow))
+		})
+
+		handler := NewHandler(engine, HandlerOptions{EnableGET: true})
+
+		t.Run("should execute queries", func(t *testing.T) {
+			recorder := serve(handler, get(`{ hello }`))
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, `{"data":{"hello":"world"}}`, recorder.Body.String())
+		})
+
+		t.Run("should reject mutations", func(t *testing.T) {
+			recorder := serve(handler, get(`mutation { hello }`))
+			assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+			assert.Equal(t, `{"errors":[{"message":"only queries can be executed with GET requests"}],"data":null}`, recorder.Body.String())
+		})
+	})
+
+	t.Run("batching", func(t *testing.T) {
+		t.Run("should be rejected if disabled", func(t *testing.T) {
+			recorder := serve(NewHandler(engine, HandlerOptions{}), post(`[{"query":"{ hello }"}]`))
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			assert.Equal(t, `{"errors":[{"message":"batched requests are not supported"}],"data":null}`, recorder.Body.String())
+		})
+
+		handler := NewHandler(engine, HandlerOptions{Batching: BatchingOptions{Enabled: true, MaxBatchSize: 2}})
+
+		t.Run("should execute all operations of the batch", func(t *testing.T) {
+			recorder := serve(handler, post(`[{"query":"{ hello }"},{"query":"{ goodbye }"}]`))
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, `[{"data":{"hello":"world"}},{"errors":[{"message":"field: goodbye not defined on type: Query","path":["query","goodbye"]}],"data":null}]`, recorder.Body.String())
+		})
+
+		t.Run("should reject batches exceeding the max batch size", func(t *testing.T) {
+			recorder := serve(handler, post(`[{"query":"{ hello }"},{"query":"{ hello }"},{"query":"{ hello }"}]`))
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			assert.Equal(t, `{"errors":[{"message":"the batch contains more than 2 operations"}],"data":null}`, recorder.Body.String())
+		})
+	})
+
+	t.Run("variables limits", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		engineConf := newTestEngineConfiguration(t, `{"hello":"world"}`)
+		engineConf.SetVariablesLimits(graphql.VariablesLimits{MaxDepth: 2})
+		limitedEngine, err := graphql.NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+		require.NoError(t, err)
+		handler := NewHandler(limitedEngine, HandlerOptions{EnableGET: true, Batching: BatchingOptions{Enabled: true}})
+
+		t.Run("should reject variables before the body is unmarshalled", func(t *testing.T) {
+			recorder := serve(handler, post(`{"variables":{"a":[[1]]},"query":`))
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			assert.Equal(t, `{"errors":[{"message":"variables exceed the maximum depth of 2"}],"data":null}`, recorder.Body.String())
+		})
+
+		t.Run("should reject variables defined more than once with different escapes", func(t *testing.T) {
+			recorder := serve(handler, post(`{"query":"{ hello }","variables":{"a":1,"\u0061":2}}`))
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			assert.Equal(t, `{"errors":[{"message":"variable \"a\" is defined more than once"}],"data":null}`, recorder.Body.String())
+		})
+
+		t.Run("should reject variables of batched operations", func(t *testing.T) {
+			recorder := serve(handler, post(`[{"query":"{ hello }"},{"query":"{ hello }","variables":{"a":[[1]]}}]`))
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			assert.Equal(t, `{"errors":[{"message":"variables exceed the maximum depth of 2"}],"data":null}`, recorder.Body.String())
+		})
+
+		t.Run("should reject variables of GET requests", func(t *testing.T) {
+			recorder := serve(handler, httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape(`{ hello }`)+"&variables="+url.QueryEscape(`{"a":[[1]]}`), nil))
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			assert.Equal(t, `{"errors":[{"message":"variables exceed the maximum depth of 2"}],"data":null}`, recorder.Body.String())
+		})
+
+		t.Run("should execute operations with variables within the limits", func(t *testing.T) {
+			recorder := serve(handler, post(`{"query":"{ hello }","variables":{"a":[1]}}`))
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, `{"data":{"hello":"world"}}`, recorder.Body.String())
+		})
+	})
+
+	t.Run("persisted queries", func(t *testing.T) {
+		cache, err := NewInMemoryPersistedQueryCache(8)
+		require.NoError(t, err)
+		handler := NewHandler(engine, HandlerOptions{PersistedQueries: PersistedQueryOptions{Cache: cache}})
+
+		sum := sha256.Sum256([]byte(`{ hello }`))
+		extensions := `{"persistedQuery":{"version":1,"sha256Hash":"` + hex.EncodeToString(sum[:]) + `"}}`
+
+		recorder := serve(handler, post(`{"extensions":`+extensions+`}`))
+		assert.Equal(t, `{"errors":[{"message":"PersistedQueryNotFound"}],"data":null}`, recorder.Body.String())
+
+		recorder = serve(handler, post(`{"query":"{ hello }","extensions":`+extensions+`}`))
+		assert.Equal(t, `{"data":{"hello":"world"}}`, recorder.Body.String())
+
+		recorder = serve(handler, post(`{"extensions":`+extensions+`}`))
+		assert.Equal(t, `{"data":{"hello":"world"}}`, recorder.Body.String())
+
+		recorder = serve(handler, post(`{"query":"{ __typename }","extensions":`+extensions+`}`))
+		assert.Equal(t, `{"errors":[{"message":"provided sha256Hash does not match query"}],"data":null}`, recorder.Body.String())
+	})
+
+	t.Run("persisted operations", func(t *testing.T) {
+		engineConf := newTestEngineConfiguration(t, `{"hello":"world"}`)
+		engineConf.SetPersistedOperationStore(graphql.NewInMemoryPersistedOperationStore(map[string]string{
+			"hello": `{ hello }`,
+		}))
+		persistedEngine, err := graphql.NewExecutionEngineV2(context.Background(), abstractlogger.Noop{}, engineConf)
+		require.NoError(t, err)
+		handler := NewHandler(persistedEngine, HandlerOptions{EnableGET: true})
+
+		recorder := serve(handler, post(`{"documentId":"hello"}`))
+		assert.Equal(t, `{"data":{"hello":"world"}}`, recorder.Body.String())
+
+		recorder = serve(handler, httptest.NewRequest(http.MethodGet, "/graphql?documentId=hello", nil))
+		assert.Equal(t, `{"data":{"hello":"world"}}`, recorder.Body.String())
+
+		recorder = serve(handler, post(`{"documentId":"unknown"}`))
+		assert.Equal(t, `{"errors":[{"message":"PersistedOperationNotFound"}],"data":null}`, recorder.Body.String())
+
+		recorder = serve(handler, post(`{"query":"{ __typename }"}`))
+		assert.Equal(t, `{"errors":[{"message":"operation is not a registered persisted operation"}],"data":null}`, recorder.Body.String())
+	})
+
+	t.Run("server-sent events", func(t *testing.T) {
+		handler := NewHandler(engine, HandlerOptions{EnableSSE: true})
+
+		r := post(`{"query":"{ hello }"}`)
+		r.Header.Set(httpHeaderAccept, httpContentTypeEventStream)
+		recorder := serve(handler, r)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, httpContentTypeEventStream, recorder.Header().Get(httpHeaderContentType))
+		assert.Equal(t, "event: next\ndata: {\"data\":{\"hello\":\"world\"}}\n\nevent: complete\ndata: \n\n", recorder.Body.String())
+	})
+
+	t.Run("schema versions", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		versionedEngine, err := graphql.NewVersionedExecutionEngineV2(ctx, abstractlogger.Noop{}, "v1", map[string]graphql.EngineV2Configuration{
+			"v1": newTestEngineConfiguration(t, `{"hello":"v1"}`),
+			"v2": newTestEngineConfiguration(t, `{"hello":"v2"}`),
+		})
+		require.NoError(t, err)
+		handler := NewHandlerWithEngineSelector(SchemaVersionSelector(versionedEngine, SchemaVersionFromHeader("X-Schema-Version"), SchemaVersionFromPath()), HandlerOptions{})
+
+		t.Run("should use the default version", func(t *testing.T) {
+			recorder := serve(handler, post(`{"query":"{ hello }"}`))
+			assert.Equal(t, `{"data":{"hello":"v1"}}`, recorder.Body.String())
+		})
+
+		t.Run("should select the version by header", func(t *testing.T) {
+			r := post(`{"query":"{ hello }"}`)
+			r.Header.Set("X-Schema-Version", "v2")
+			recorder := serve(handler, r)
+			assert.Equal(t, `{"data":{"hello":"v2"}}`, recorder.Body.String())
+		})
+
+		t.Run("should select the version by path", func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/v2/graphql", strings.NewReader(`{"query":"{ hello }"}`))
+			recorder := serve(handler, r)
+			assert.Equal(t, `{"data":{"hello":"v2"}}`, recorder.Body.String())
+		})
+
+		t.Run("should reject unknown versions", func(t *testing.T) {
+			r := post(`{"query":"{ hello }"}`)
+			r.Header.Set("X-Schema-Version", "v3")
+			recorder := serve(handler, r)
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			assert.Equal(t, `{"errors":[{"message":"unknown schema version \"v3\""}],"data":null}`, recorder.Body.String())
+		})
+	})
+
+	t.Run("tenants", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		manager := graphql.NewTenantEngineManager(ctx, abstractlogger.Noop{})
+		require.NoError(t, manager.SetTenant("acme", newTestEngineConfiguration(t, `{"hello":"acme"}`)))
+		handler := NewHandlerWithEngineSelector(TenantSelector(manager, TenantIDFromHeader("X-Tenant-ID")), HandlerOptions{})
+
+		t.Run("should use the engine of the tenant", func(t *testing.T) {
+			r := post(`{"query":"{ hello }"}`)
+			r.Header.Set("X-Tenant-ID", "acme")
+			recorder := serve(handler, r)
+			assert.Equal(t, `{"data":{"hello":"acme"}}`, recorder.Body.String())
+		})
+
+		t.Run("should reject unknown tenants", func(t *testing.T) {
+			r := post(`{"query":"{ hello }"}`)
+			r.Header.Set("X-Tenant-ID", "globex")
+			recorder := serve(handler, r)
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			assert.Equal(t, `{"errors":[{"message":"unknown tenant \"globex\""}],"data":null}`, recorder.Body.String())
+		})
+	})
+	t.Run("response headers", func(t *testing.T) {
+		engineConf := newTestEngineConfiguration(t, `{"hello":"world"}`)
+		dataSources := engineConf.DataSources()
+		dataSources[0].Factory = &responseHeaderFactory{PlannerFactory: dataSources[0].Factory}
+		engineConf.SetDataSources(dataSources)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		headerEngine, err := graphql.NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+		require.NoError(t, err)
+
+		t.Run("should not set headers if disabled", func(t *testing.T) {
+			recorder := serve(NewHandler(headerEngine, HandlerOptions{}), post(`{"query":"{ hello }"}`))
+			assert.Empty(t, recorder.Header().Values("Set-Cookie"))
+		})
+
+		t.Run("should set the headers contributed by datasources", func(t *testing.T) {
+			handler := NewHandler(headerEngine, HandlerOptions{
+				ResponseHeaders: &resolve.ResponseHeaderOptions{
+					AllowedHeaders: []string{"Set-Cookie", "Content-Type"},
+				},
+			})
+			recorder := serve(handler, post(`{"query":"{ hello }"}`))
+			assert.Equal(t, `{"data":{"hello":"world"}}`, recorder.Body.String())
+			assert.Equal(t, []string{"session=1"}, recorder.Header().Values("Set-Cookie"))
+			assert.Equal(t, httpContentTypeApplicationJson, recorder.Header().Get(httpHeaderContentType))
+		})
+	})
+}
+
+// responseHeaderFactory wraps the datasources of a factory to contribute response headers
+type responseHeaderFactory struct {
+	plan.PlannerFactory
+}
+
+func (f *responseHeaderFactory) Planner(ctx context.Context) plan.DataSourcePlanner {
+	return &responseHeaderPlanner{DataSourcePlanner: f.PlannerFactory.Planner(ctx)}
+}
+
+type responseHeaderPlanner struct {
+	plan.DataSourcePlanner
+}
+
+func (p *responseHeaderPlanner) ConfigureFetch() resolve.FetchConfiguration {
+	config := p.DataSourcePlanner.ConfigureFetch()
+	config.DataSource = &responseHeaderSource{DataSource: config.DataSource}
+	return config
+}
+
+type responseHeaderSource struct {
+	resolve.DataSource
+}
+
+func (s *responseHeaderSource) Load(ctx context.Context, input []byte, w io.Writer) error {
+	headers := resolve.ResponseHeadersFromContext(ctx)
+	headers.Add("Set-Cookie", "session=1")
+	headers.Add("Content-Type", "text/html")
+	return s.DataSource.Load(ctx, input, w)
+}
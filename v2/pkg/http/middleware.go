@@ -0,0 +1,27 @@
+package http
+
+import (
+	"net/http"
+)
+
+// Middleware serves the requests to path with the Handler and passes all other requests to the next handler.
+// An empty path serves all requests, so the Handler can be used as the final handler of a middleware chain.
+//
+// The Handler is a http.Handler and can be used with routers built on net/http, e.g. chi:
+//
+//	router.Handle("/graphql", handler)
+//	router.Use(handler.Middleware("/graphql"))
+//
+// Adapters for chi, echo, gin and fasthttp, including websocket upgrades, are provided by the
+// github.com/wundergraph/graphql-go-tools/v2/pkg/http/adapters module.
+func (h *Handler) Middleware(path string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if path != "" && r.URL.Path != path {
+				next.ServeHTTP(w, r)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
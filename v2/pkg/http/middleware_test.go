@@ -0,0 +1,70 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription/websocket"
+)
+
+func TestHandler_Middleware(t *testing.T) {
+	handler := NewHandler(newTestEngine(t), HandlerOptions{})
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	serve := func(path string, r *http.Request) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.Middleware(path)(next).ServeHTTP(recorder, r)
+		return recorder
+	}
+
+	post := func(target string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, target, strings.NewReader(`{"query":"{ hello }"}`))
+	}
+
+	t.Run("should serve requests to the path", func(t *testing.T) {
+		recorder := serve("/graphql", post("/graphql"))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `{"data":{"hello":"world"}}`, recorder.Body.String())
+	})
+
+	t.Run("should pass other requests to the next handler", func(t *testing.T) {
+		recorder := serve("/graphql", post("/other"))
+		assert.Equal(t, http.StatusTeapot, recorder.Code)
+	})
+
+	t.Run("should serve all requests without path", func(t *testing.T) {
+		recorder := serve("", post("/other"))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+}
+
+func TestHandler_ServeWebsocketConn(t *testing.T) {
+	handler := NewHandler(newTestEngine(t), HandlerOptions{})
+
+	serverConn, clientConn := net.Pipe()
+	r := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+	r.Header.Set(websocket.HeaderSecWebSocketProtocol, string(websocket.ProtocolGraphQLTransportWS))
+
+	served := make(chan error)
+	go func() {
+		served <- handler.ServeWebsocketConn(serverConn, r)
+	}()
+
+	require.NoError(t, wsutil.WriteClientMessage(clientConn, ws.OpText, []byte(`{"type":"connection_init"}`)))
+	message, err := wsutil.ReadServerText(clientConn)
+	require.NoError(t, err)
+	assert.Equal(t, `{"type":"connection_ack"}`, string(message))
+
+	require.NoError(t, clientConn.Close())
+	assert.NoError(t, <-served)
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"encoding/json"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+// persistedQueryNotFoundMessage is the error message clients expect to send the full query
+const persistedQueryNotFoundMessage = "PersistedQueryNotFound"
+
+// PersistedQueryCache stores the queries of automatic persisted queries by their sha256 hash
+type PersistedQueryCache interface {
+	Get(sha256Hash string) (query string, ok bool)
+	Set(sha256Hash string, query string)
+}
+
+// PersistedQueryOptions configures automatic persisted queries
+// Clients send the sha256 hash of the query in extensions.persistedQuery.sha256Hash
+// and send the full query only if the hash is unknown to the server
+type PersistedQueryOptions struct {
+	Cache PersistedQueryCache
+}
+
+// InMemoryPersistedQueryCache is a PersistedQueryCache keeping the most recently used queries in memory
+type InMemoryPersistedQueryCache struct {
+	cache *lru.Cache
+}
+
+// NewInMemoryPersistedQueryCache creates a cache for at most size queries
+func NewInMemoryPersistedQueryCache(size int) (*InMemoryPersistedQueryCache, error) {
+	cache, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+	return &InMemoryPersistedQueryCache{cache: cache}, nil
+}
+
+func (c *InMemoryPersistedQueryCache) Get(sha256Hash string) (query string, ok bool) {
+	value, ok := c.cache.Get(sha256Hash)
+	if !ok {
+		return "", false
+	}
+	return value.(string), true
+}
+
+func (c *InMemoryPersistedQueryCache) Set(sha256Hash string, query string) {
+	c.cache.Add(sha256Hash, query)
+}
+
+type persistedQueryExtensions struct {
+	PersistedQuery *struct {
+		Version    int    `json:"version"`
+		Sha256Hash string `json:"sha256Hash"`
+	} `json:"persistedQuery"`
+}
+
+// persistedQuery returns the query of the request
+// if the request is a persisted query, the query is loaded from the cache or stored if the request contains it
+func (h *Handler) persistedQuery(req *request) (string, graphql.RequestErrors) {
+	cache := h.options.PersistedQueries.Cache
+	if cache == nil || len(req.Extensions) == 0 {
+		return req.Query, nil
+	}
+
+	var extensions persistedQueryExtensions
+	if err := json.Unmarshal(req.Extensions, &extensions); err != nil {
+		return "", graphql.RequestErrorsFromError(err)
+	}
+	if extensions.PersistedQuery == nil {
+		return req.Query, nil
+	}
+	if extensions.PersistedQuery.Version != 1 {
+		return "", graphql.RequestErrors{{Message: "unsupported persisted query version"}}
+	}
+
+	hash := extensions.PersistedQuery.Sha256Hash
+	if req.Query == "" {
+		query, ok := cache.Get(hash)
+		if !ok {
+			return "", graphql.RequestErrors{{Message: persistedQueryNotFoundMessage}}
+		}
+		return query, nil
+	}
+
+	if graphql.QuerySha256Hash(req.Query) != hash {
+		return "", graphql.RequestErrors{{Message: "provided sha256Hash does not match query"}}
+	}
+	cache.Set(hash, req.Query)
+	return req.Query, nil
+}
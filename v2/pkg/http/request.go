@@ -0,0 +1,156 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/buger/jsonparser"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/jsoncodec"
+)
+
+var (
+	errSubscriptionTransport = errors.New("subscriptions have to be executed over websockets or server-sent events")
+	errGETOnlyQueries        = errors.New("only queries can be executed with GET requests")
+	errBatchingDisabled      = errors.New("batched requests are not supported")
+	errEmptyBatch            = errors.New("the batch contains no operations")
+)
+
+// request is the payload of a GraphQL request
+type request struct {
+	OperationName string          `json:"operationName"`
+	Variables     json.RawMessage `json:"variables,omitempty"`
+	Query         string          `json:"query"`
+	Extensions    json.RawMessage `json:"extensions,omitempty"`
+	// DocumentID identifies a persisted operation, see graphql.EngineV2Configuration.SetPersistedOperationStore
+	DocumentID string `json:"documentId,omitempty"`
+}
+
+// requestsFromBody reads a single request or a batch of requests from the body of a POST request
+// bodies exceeding HandlerOptions.MaxRequestBodySize return a *http.MaxBytesError
+func (h *Handler) requestsFromBody(w http.ResponseWriter, r *http.Request, engine *graphql.ExecutionEngineV2) (requests []*request, isBatch bool, err error) {
+	if h.options.MaxRequestBodySize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.options.MaxRequestBodySize)
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, false, err
+	}
+	return h.requestsFromBytes(engine, body)
+}
+
+// requestsFromBytes parses a single request or a batch of requests from a request body
+// the variables are checked against the limits of the engine before the body is unmarshalled
+func (h *Handler) requestsFromBytes(engine *graphql.ExecutionEngineV2, body []byte) (requests []*request, isBatch bool, err error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, false, graphql.ErrEmptyRequest
+	}
+
+	if body[0] != '[' {
+		if err = validateVariablesLimits(engine, body); err != nil {
+			return nil, false, err
+		}
+		req := &request{}
+		if err = jsoncodec.Unmarshal(body, req); err != nil {
+			return nil, false, err
+		}
+		return []*request{req}, false, nil
+	}
+
+	if !h.options.Batching.Enabled {
+		return nil, true, errBatchingDisabled
+	}
+	// malformed batches are ignored here and rejected by the unmarshalling
+	_, _ = jsonparser.ArrayEach(body, func(value []byte, dataType jsonparser.ValueType, _ int, _ error) {
+		if err == nil && dataType == jsonparser.Object {
+			err = validateVariablesLimits(engine, value)
+		}
+	})
+	if err != nil {
+		return nil, true, err
+	}
+	if err = jsoncodec.Unmarshal(body, &requests); err != nil {
+		return nil, true, err
+	}
+	switch {
+	case len(requests) == 0:
+		return nil, true, errEmptyBatch
+	case len(requests) > h.options.Batching.MaxBatchSize:
+		return nil, true, fmt.Errorf("the batch contains more than %d operations", h.options.Batching.MaxBatchSize)
+	}
+	return requests, true, nil
+}
+
+// validateVariablesLimits checks the raw variables of a request object against the limits of the engine
+func validateVariablesLimits(engine *graphql.ExecutionEngineV2, body []byte) error {
+	variables, dataType, _, err := jsonparser.Get(body, "variables")
+	if err != nil || dataType != jsonparser.Object {
+		return nil
+	}
+	return engine.ValidateVariablesLimits(variables)
+}
+
+// requestFromQueryParameters reads the request from the query parameters of a GET request
+// variables and extensions are json encoded
+func requestFromQueryParameters(r *http.Request, engine *graphql.ExecutionEngineV2) (*request, error) {
+	query := r.URL.Query()
+	req := &request{
+		OperationName: query.Get("operationName"),
+		Query:         query.Get("query"),
+		DocumentID:    query.Get("documentId"),
+	}
+	if variables := query.Get("variables"); variables != "" {
+		if err := engine.ValidateVariablesLimits([]byte(variables)); err != nil {
+			return nil, err
+		}
+		if !json.Valid([]byte(variables)) {
+			return nil, errors.New("variables are not valid json")
+		}
+		req.Variables = json.RawMessage(variables)
+	}
+	if extensions := query.Get("extensions"); extensions != "" {
+		if !json.Valid([]byte(extensions)) {
+			return nil, errors.New("extensions are not valid json")
+		}
+		req.Extensions = json.RawMessage(extensions)
+	}
+	if req.Query == "" && len(req.Extensions) == 0 && req.DocumentID == "" {
+		return nil, graphql.ErrEmptyRequest
+	}
+	return req, nil
+}
+
+// resolveOperation creates the operation of the request, resolving persisted queries
+// and the persisted operations of the engine
+func (h *Handler) resolveOperation(ctx context.Context, header http.Header, engine *graphql.ExecutionEngineV2, req *request) (*graphql.Request, graphql.RequestErrors) {
+	query, errs := h.persistedQuery(req)
+	if errs != nil {
+		return nil, errs
+	}
+	if query == "" && req.DocumentID == "" {
+		return nil, graphql.RequestErrorsFromError(graphql.ErrEmptyRequest)
+	}
+
+	operation := &graphql.Request{
+		OperationName: req.OperationName,
+		Variables:     req.Variables,
+		Query:         query,
+		Extensions:    req.Extensions,
+		DocumentID:    req.DocumentID,
+	}
+	if err := engine.ResolvePersistedOperation(ctx, operation); err != nil {
+		return nil, graphql.RequestErrorsFromError(err)
+	}
+	if operation.Query == "" {
+		return nil, graphql.RequestErrorsFromError(graphql.ErrEmptyRequest)
+	}
+	operation.SetHeader(header)
+	return operation, nil
+}
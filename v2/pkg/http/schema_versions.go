@@ -0,0 +1,43 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+// SchemaVersionSource returns the schema version requested by a request or an empty string if the request doesn't select one
+type SchemaVersionSource func(r *http.Request) string
+
+// SchemaVersionFromHeader reads the schema version from the header with the given name
+func SchemaVersionFromHeader(name string) SchemaVersionSource {
+	return func(r *http.Request) string {
+		return r.Header.Get(name)
+	}
+}
+
+// SchemaVersionFromPath reads the schema version from the first segment of the request path, e.g. v2 for /v2/graphql
+// Paths with a single segment don't select a version
+func SchemaVersionFromPath() SchemaVersionSource {
+	return func(r *http.Request) string {
+		segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if len(segments) < 2 {
+			return ""
+		}
+		return segments[0]
+	}
+}
+
+// SchemaVersionSelector selects the engine of the schema version of a request
+// The sources are asked in order, the default version is used if none of them returns a version
+func SchemaVersionSelector(engine *graphql.VersionedExecutionEngineV2, sources ...SchemaVersionSource) EngineSelector {
+	return func(r *http.Request) (*graphql.ExecutionEngineV2, error) {
+		for _, source := range sources {
+			if version := source(r); version != "" {
+				return engine.Engine(version)
+			}
+		}
+		return engine.Engine("")
+	}
+}
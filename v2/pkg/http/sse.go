@@ -0,0 +1,153 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/jensneuse/abstractlogger"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+var errSSEClientDisconnected = errors.New("client disconnected")
+
+var (
+	sseEventNext     = []byte("event: next\ndata: ")
+	sseEventComplete = []byte("event: complete\ndata: \n\n")
+	sseEventEnd      = []byte("\n\n")
+)
+
+// sseResponseWriter writes every flushed response as next event and the completion as complete event
+// the resolver flushes subscription updates concurrently to the request goroutine
+type sseResponseWriter struct {
+	mu        sync.Mutex
+	w         http.ResponseWriter
+	flusher   http.Flusher
+	buf       *bytes.Buffer
+	done      chan struct{}
+	completed bool
+	aborted   bool
+}
+
+func newSSEResponseWriter(w http.ResponseWriter, flusher http.Flusher) *sseResponseWriter {
+	return &sseResponseWriter{
+		w:       w,
+		flusher: flusher,
+		buf:     &bytes.Buffer{},
+		done:    make(chan struct{}),
+	}
+}
+
+func (s *sseResponseWriter) Write(p []byte) (n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *sseResponseWriter) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.aborted {
+		// the resolver unsubscribes on flush errors
+		return errSSEClientDisconnected
+	}
+	if s.completed || s.buf.Len() == 0 {
+		s.buf.Reset()
+		return nil
+	}
+	defer s.buf.Reset()
+	if _, err := s.w.Write(sseEventNext); err != nil {
+		return err
+	}
+	if _, err := s.w.Write(s.buf.Bytes()); err != nil {
+		return err
+	}
+	if _, err := s.w.Write(sseEventEnd); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+// abort stops writing to the response after the client disconnected
+func (s *sseResponseWriter) abort() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.aborted = true
+}
+
+func (s *sseResponseWriter) Complete() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.completed || s.aborted {
+		return
+	}
+	s.completed = true
+	_, _ = s.w.Write(sseEventComplete)
+	s.flusher.Flush()
+	close(s.done)
+}
+
+// serveSSE executes the operation and streams the responses as server-sent events
+// queries and mutations send a single next event, subscriptions send an event per update until they complete
+// or the client disconnects
+func (h *Handler) serveSSE(w http.ResponseWriter, r *http.Request, engine *graphql.ExecutionEngineV2, req *request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.writeRequestErrors(w, http.StatusInternalServerError, graphql.RequestErrors{{Message: "streaming is not supported"}})
+		return
+	}
+
+	operation, errs := h.resolveOperation(r.Context(), r.Header, engine, req)
+	if errs != nil {
+		h.writeRequestErrors(w, http.StatusOK, errs)
+		return
+	}
+	operationType, err := operation.OperationType()
+	if err != nil {
+		h.writeRequestErrors(w, http.StatusOK, graphql.RequestErrorsFromError(err))
+		return
+	}
+	if r.Method == http.MethodGet && operationType == graphql.OperationTypeMutation {
+		h.writeRequestErrors(w, http.StatusMethodNotAllowed, graphql.RequestErrorsFromError(errGETOnlyQueries))
+		return
+	}
+
+	w.Header().Set(httpHeaderContentType, httpContentTypeEventStream)
+	w.Header().Set(httpHeaderCacheControl, "no-cache")
+	w.Header().Set(httpHeaderConnection, "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	writer := newSSEResponseWriter(w, flusher)
+	err = engine.Execute(r.Context(), operation, writer, h.executionOptions(r)...)
+	if err != nil {
+		_, _ = graphql.RequestErrorsFromError(err).WriteResponse(writer)
+		h.flushSSE(writer)
+		writer.Complete()
+		return
+	}
+
+	if operationType != graphql.OperationTypeSubscription {
+		h.flushSSE(writer)
+		writer.Complete()
+		return
+	}
+
+	select {
+	case <-writer.done:
+	case <-r.Context().Done():
+		writer.abort()
+	}
+}
+
+func (h *Handler) flushSSE(writer *sseResponseWriter) {
+	if err := writer.Flush(); err != nil {
+		h.options.Logger.Error("http.Handler.serveSSE",
+			abstractlogger.String("message", "could not write event"),
+			abstractlogger.Error(err),
+		)
+	}
+}
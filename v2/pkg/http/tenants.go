@@ -0,0 +1,24 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+// TenantIDSource returns the tenant id of a request
+type TenantIDSource func(r *http.Request) string
+
+// TenantIDFromHeader reads the tenant id from the header with the given name
+func TenantIDFromHeader(name string) TenantIDSource {
+	return func(r *http.Request) string {
+		return r.Header.Get(name)
+	}
+}
+
+// TenantSelector selects the engine of the tenant of a request
+func TenantSelector(manager *graphql.TenantEngineManager, tenantID TenantIDSource) EngineSelector {
+	return func(r *http.Request) (*graphql.ExecutionEngineV2, error) {
+		return manager.Engine(tenantID(r))
+	}
+}
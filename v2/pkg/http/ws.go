@@ -0,0 +1,216 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/jensneuse/abstractlogger"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription/websocket"
+)
+
+// upgradeWebsocket upgrades the request and handles the websocket connection in a new goroutine
+// the protocol is negotiated from the Sec-WebSocket-Protocol header of the request
+func (h *Handler) upgradeWebsocket(w http.ResponseWriter, r *http.Request, engine *graphql.ExecutionEngineV2) error {
+	upgrader := *h.options.Websocket.Upgrader
+	if upgrader.Protocol == nil {
+		// the negotiated protocol has to be confirmed in the response, otherwise clients close the connection
+		upgrader.Protocol = websocket.IsSupportedProtocol
+	}
+	conn, _, _, err := upgrader.Upgrade(r, w)
+	if err != nil {
+		return err
+	}
+
+	h.handleWebsocketConn(conn, r, engine)
+	return nil
+}
+
+// ServeWebsocketConn handles a connection which was upgraded to a websocket outside of ServeHTTP,
+// e.g. by a hijack handler of a router not based on net/http.
+// The request provides the headers for the protocol negotiation and the engine selection, as well as
+// the initial request context of the subscriptions. ServeWebsocketConn returns once the connection is served
+// in a new goroutine, the connection is closed by the Handler when the client disconnects.
+func (h *Handler) ServeWebsocketConn(conn net.Conn, r *http.Request) error {
+	engine, err := h.selectEngine(r)
+	if err != nil {
+		return err
+	}
+
+	h.handleWebsocketConn(conn, r, engine)
+	return nil
+}
+
+// handleWebsocketConn handles the websocket connection in a new goroutine and waits until it is done
+func (h *Handler) handleWebsocketConn(conn net.Conn, r *http.Request, engine *graphql.ExecutionEngineV2) {
+	done := make(chan bool)
+	errChan := make(chan error)
+
+	executorPool := h.newWebsocketExecutorPool(r, engine)
+	options := append([]websocket.HandleOptionFunc{
+		websocket.WithLogger(h.options.Logger),
+		websocket.WithProtocolFromRequestHeaders(r),
+	}, h.options.Websocket.HandleOptions...)
+	go websocket.Handle(done, errChan, conn, executorPool, options...)
+
+	select {
+	case err := <-errChan:
+		h.options.Logger.Error("http.Handler.handleWebsocketConn",
+			abstractlogger.Error(err),
+		)
+	case <-done:
+	}
+}
+
+// websocketExecutorPool executes queries and mutations sent over a websocket like operations of http requests,
+// with persisted queries, batching and the execution options of the Handler.
+// Subscriptions are executed by the executors of the subscription package.
+type websocketExecutorPool struct {
+	handler       *Handler
+	engine        *graphql.ExecutionEngineV2
+	header        http.Header
+	reqCtx        context.Context
+	options       []graphql.ExecutionOptionsV2
+	subscriptions *subscription.ExecutorV2Pool
+}
+
+func (h *Handler) newWebsocketExecutorPool(r *http.Request, engine *graphql.ExecutionEngineV2) *websocketExecutorPool {
+	reqCtx := subscription.NewInitialHttpRequestContext(r)
+	options := h.executionOptions(r)
+	return &websocketExecutorPool{
+		handler:       h,
+		engine:        engine,
+		header:        r.Header,
+		reqCtx:        reqCtx,
+		options:       options,
+		subscriptions: subscription.NewExecutorV2PoolWithExecutionOptions(engine, reqCtx, options...),
+	}
+}
+
+// Get returns the executor of the payload, request errors are sent to the client as the result of the operation
+func (p *websocketExecutorPool) Get(payload []byte) (subscription.Executor, error) {
+	requests, isBatch, err := p.handler.requestsFromBytes(p.engine, payload)
+	if err != nil {
+		return p.newExecutor(nil, nil, graphql.RequestErrorsFromError(err)), nil
+	}
+	if isBatch {
+		return p.newExecutor(requests, nil, nil), nil
+	}
+
+	operation, errs := p.handler.resolveOperation(p.reqCtx, p.header, p.engine, requests[0])
+	if errs != nil {
+		return p.newExecutor(nil, nil, errs), nil
+	}
+	if operationType, err := operation.OperationType(); err == nil && operationType == graphql.OperationTypeSubscription {
+		return p.subscriptions.GetForOperation(operation)
+	}
+	return p.newExecutor(nil, operation, nil), nil
+}
+
+func (p *websocketExecutorPool) Put(executor subscription.Executor) error {
+	if _, ok := executor.(*websocketOperationExecutor); ok {
+		return nil
+	}
+	return p.subscriptions.Put(executor)
+}
+
+// onBeforeStart calls the websocket before start hook of the engine for every operation of the executor,
+// subscriptions are checked by the subscription engine
+func (p *websocketExecutorPool) onBeforeStart(batch []*request, operation *graphql.Request) error {
+	hook := p.engine.GetWebsocketBeforeStartHook()
+	if hook == nil {
+		return nil
+	}
+	if operation != nil {
+		return hook.OnBeforeStart(p.reqCtx, operation)
+	}
+	for _, req := range batch {
+		batchOperation, errs := p.handler.resolveOperation(p.reqCtx, p.header, p.engine, req)
+		if errs != nil {
+			// request errors are part of the response of the batch
+			continue
+		}
+		if err := hook.OnBeforeStart(p.reqCtx, batchOperation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *websocketExecutorPool) newExecutor(batch []*request, operation *graphql.Request, errs graphql.RequestErrors) *websocketOperationExecutor {
+	return &websocketOperationExecutor{
+		pool:      p,
+		ctx:       context.Background(),
+		batch:     batch,
+		operation: operation,
+		errs:      errs,
+	}
+}
+
+// websocketOperationExecutor executes a batch, a single query or mutation, or writes the errors of the request
+type websocketOperationExecutor struct {
+	pool      *websocketExecutorPool
+	ctx       context.Context
+	batch     []*request
+	operation *graphql.Request
+	errs      graphql.RequestErrors
+}
+
+func (e *websocketOperationExecutor) Execute(writer resolve.SubscriptionResponseWriter) error {
+	if e.errs == nil {
+		if err := e.pool.onBeforeStart(e.batch, e.operation); err != nil {
+			return err
+		}
+	}
+
+	var buf *bytes.Buffer
+	switch {
+	case e.errs != nil:
+		buf = &bytes.Buffer{}
+		_, _ = e.errs.WriteResponse(buf)
+	case e.batch != nil:
+		buf = e.pool.handler.executeBatch(e.ctx, http.MethodPost, e.pool.header, e.pool.engine, e.batch, e.pool.options)
+	default:
+		buf = &bytes.Buffer{}
+		// the status is not applicable to websockets, errors are part of the response
+		_ = e.pool.handler.executeOperation(e.ctx, http.MethodPost, e.pool.engine, e.operation, e.pool.options, buf)
+	}
+
+	_, err := writer.Write(buf.Bytes())
+	return err
+}
+
+// OperationType returns the type of the operation, batches and request errors are treated as queries
+func (e *websocketOperationExecutor) OperationType() ast.OperationType {
+	if e.operation == nil {
+		return ast.OperationTypeQuery
+	}
+	operationType, err := e.operation.OperationType()
+	if err != nil {
+		return ast.OperationTypeUnknown
+	}
+	return ast.OperationType(operationType)
+}
+
+func (e *websocketOperationExecutor) SetContext(ctx context.Context) {
+	e.ctx = ctx
+}
+
+func (e *websocketOperationExecutor) Reset() {
+	e.ctx = context.Background()
+	e.batch = nil
+	e.operation = nil
+	e.errs = nil
+}
+
+// Interface Guards
+var (
+	_ subscription.ExecutorPool = (*websocketExecutorPool)(nil)
+	_ subscription.Executor     = (*websocketOperationExecutor)(nil)
+)
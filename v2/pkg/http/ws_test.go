@@ -0,0 +1,183 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription/websocket"
+)
+
+type testBeforeStartHook struct{}
+
+func (testBeforeStartHook) OnBeforeStart(reqCtx context.Context, operation *graphql.Request) error {
+	if operation.OperationName == "forbidden" {
+		return errors.New("forbidden")
+	}
+	return nil
+}
+
+func TestHandler_WebsocketOperations(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	engineConf := newTestEngineConfiguration(t, `{"hello":"world"}`)
+	engineConf.SetWebsocketBeforeStartHook(testBeforeStartHook{})
+	engine, err := graphql.NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+	require.NoError(t, err)
+
+	cache, err := NewInMemoryPersistedQueryCache(8)
+	require.NoError(t, err)
+	handler := NewHandler(engine, HandlerOptions{
+		Batching:         BatchingOptions{Enabled: true},
+		PersistedQueries: PersistedQueryOptions{Cache: cache},
+	})
+
+	connect := func(t *testing.T) net.Conn {
+		t.Helper()
+
+		serverConn, clientConn := net.Pipe()
+		r := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+		r.Header.Set(websocket.HeaderSecWebSocketProtocol, string(websocket.ProtocolGraphQLTransportWS))
+
+		served := make(chan error)
+		go func() {
+			served <- handler.ServeWebsocketConn(serverConn, r)
+		}()
+		t.Cleanup(func() {
+			_ = clientConn.Close()
+			<-served
+		})
+
+		require.NoError(t, wsutil.WriteClientMessage(clientConn, ws.OpText, []byte(`{"type":"connection_init"}`)))
+		message, err := wsutil.ReadServerText(clientConn)
+		require.NoError(t, err)
+		require.Equal(t, `{"type":"connection_ack"}`, string(message))
+		return clientConn
+	}
+
+	subscribe := func(t *testing.T, conn net.Conn, payload string) (messages []string) {
+		t.Helper()
+
+		require.NoError(t, wsutil.WriteClientMessage(conn, ws.OpText, []byte(`{"id":"1","type":"subscribe","payload":`+payload+`}`)))
+		for {
+			message, err := wsutil.ReadServerText(conn)
+			require.NoError(t, err)
+			messages = append(messages, string(message))
+			if !strings.Contains(string(message), `"type":"next"`) {
+				// complete and error end the operation
+				return messages
+			}
+		}
+	}
+
+	t.Run("should execute queries", func(t *testing.T) {
+		conn := connect(t)
+		assert.Equal(t, []string{
+			`{"id":"1","type":"next","payload":{"data":{"hello":"world"}}}`,
+			`{"id":"1","type":"complete"}`,
+		}, subscribe(t, conn, `{"query":"{ hello }"}`))
+	})
+
+	t.Run("should execute mutations", func(t *testing.T) {
+		conn := connect(t)
+		assert.Equal(t, []string{
+			`{"id":"1","type":"next","payload":{"data":{"hello":"world"}}}`,
+			`{"id":"1","type":"complete"}`,
+		}, subscribe(t, conn, `{"query":"mutation { hello }"}`))
+	})
+
+	t.Run("should execute batches", func(t *testing.T) {
+		conn := connect(t)
+		assert.Equal(t, []string{
+			`{"id":"1","type":"next","payload":[{"data":{"hello":"world"}},{"data":{"hello":"world"}}]}`,
+			`{"id":"1","type":"complete"}`,
+		}, subscribe(t, conn, `[{"query":"{ hello }"},{"query":"mutation { hello }"}]`))
+	})
+
+	t.Run("should resolve persisted queries", func(t *testing.T) {
+		conn := connect(t)
+		sum := sha256.Sum256([]byte(`{ hello }`))
+		extensions := `{"persistedQuery":{"version":1,"sha256Hash":"` + hex.EncodeToString(sum[:]) + `"}}`
+
+		assert.Equal(t, []string{
+			`{"id":"1","type":"next","payload":{"errors":[{"message":"PersistedQueryNotFound"}],"data":null}}`,
+			`{"id":"1","type":"complete"}`,
+		}, subscribe(t, conn, `{"extensions":`+extensions+`}`))
+
+		conn = connect(t)
+		subscribe(t, conn, `{"query":"{ hello }","extensions":`+extensions+`}`)
+
+		conn = connect(t)
+		assert.Equal(t, []string{
+			`{"id":"1","type":"next","payload":{"data":{"hello":"world"}}}`,
+			`{"id":"1","type":"complete"}`,
+		}, subscribe(t, conn, `{"extensions":`+extensions+`}`))
+	})
+
+	t.Run("should call the before start hook for every operation of a batch", func(t *testing.T) {
+		conn := connect(t)
+		messages := subscribe(t, conn, `[{"query":"{ hello }"},{"operationName":"forbidden","query":"query forbidden { hello }"}]`)
+		require.NotEmpty(t, messages)
+		assert.Contains(t, messages[0], `"type":"error"`)
+		assert.Contains(t, messages[0], `forbidden`)
+	})
+}
+
+func TestHandler_WebsocketProtocolNegotiation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	engine, err := graphql.NewExecutionEngineV2(ctx, abstractlogger.Noop{}, newTestEngineConfiguration(t, `{"hello":"world"}`))
+	require.NoError(t, err)
+
+	server := httptest.NewServer(NewHandler(engine, HandlerOptions{
+		Websocket: WebsocketOptions{Upgrader: &ws.HTTPUpgrader{}},
+	}))
+	t.Cleanup(server.Close)
+
+	// execute sends an operation with the message type of the protocol and returns the first response
+	execute := func(t *testing.T, messageType string, protocols ...string) (ws.Handshake, string) {
+		t.Helper()
+
+		dialer := ws.Dialer{Protocols: protocols}
+		conn, _, handshake, err := dialer.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http"))
+		require.NoError(t, err)
+		defer conn.Close()
+
+		require.NoError(t, wsutil.WriteClientMessage(conn, ws.OpText, []byte(`{"type":"connection_init"}`)))
+		message, err := wsutil.ReadServerText(conn)
+		require.NoError(t, err)
+		require.Equal(t, `{"type":"connection_ack"}`, string(message))
+
+		require.NoError(t, wsutil.WriteClientMessage(conn, ws.OpText, []byte(`{"id":"1","type":"`+messageType+`","payload":{"query":"{ hello }"}}`)))
+		message, err = wsutil.ReadServerText(conn)
+		require.NoError(t, err)
+		return handshake, string(message)
+	}
+
+	t.Run("should confirm and serve the first supported protocol", func(t *testing.T) {
+		handshake, message := execute(t, "start", "something-else", string(websocket.ProtocolGraphQLWS), string(websocket.ProtocolGraphQLTransportWS))
+		assert.Equal(t, string(websocket.ProtocolGraphQLWS), handshake.Protocol)
+		assert.Equal(t, `{"id":"1","type":"data","payload":{"data":{"hello":"world"}}}`, message)
+	})
+
+	t.Run("should confirm and serve graphql-transport-ws", func(t *testing.T) {
+		handshake, message := execute(t, "subscribe", string(websocket.ProtocolGraphQLTransportWS))
+		assert.Equal(t, string(websocket.ProtocolGraphQLTransportWS), handshake.Protocol)
+		assert.Equal(t, `{"id":"1","type":"next","payload":{"data":{"hello":"world"}}}`, message)
+	})
+}
@@ -0,0 +1,94 @@
+// Package arena provides a chunked bump allocator for short-lived values which are freed all at once,
+// e.g. at the end of a request.
+package arena
+
+import (
+	"sync"
+)
+
+// DefaultChunkSize is the number of values of a chunk if no chunk size is given
+const DefaultChunkSize = 64
+
+// Arena allocates values of type T from chunks.
+// Values are not freed individually, Reset makes all chunks available for new allocations.
+// Values must not be used after Reset, the chunks are kept to be reused by the next user of the Arena.
+// It is safe to allocate from multiple goroutines.
+type Arena[T any] struct {
+	mu        sync.Mutex
+	chunkSize int
+	chunks    [][]T
+	// chunk is the index of the chunk values are allocated from
+	chunk int
+	// offset is the index of the next free value in the current chunk
+	offset int
+}
+
+// New creates an Arena with chunks of chunkSize values, DefaultChunkSize is used if chunkSize is not positive
+func New[T any](chunkSize int) *Arena[T] {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	return &Arena[T]{
+		chunkSize: chunkSize,
+	}
+}
+
+// New returns a pointer to a zero value
+func (a *Arena[T]) New() *T {
+	return &a.Slice(1)[0]
+}
+
+// Slice returns a slice of n zero values, the capacity of the slice is n so appending never overwrites other values
+// Slices larger than the chunk size are allocated on the heap
+func (a *Arena[T]) Slice(n int) []T {
+	if n > a.chunkSize {
+		return make([]T, n)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.chunks) == 0 {
+		a.chunks = append(a.chunks, make([]T, a.chunkSize))
+	}
+	if a.offset+n > a.chunkSize {
+		a.chunk++
+		a.offset = 0
+		if a.chunk == len(a.chunks) {
+			a.chunks = append(a.chunks, make([]T, a.chunkSize))
+		}
+	}
+
+	start := a.offset
+	a.offset += n
+	return a.chunks[a.chunk][start:a.offset:a.offset]
+}
+
+// Copy returns a copy of values allocated from the Arena
+func (a *Arena[T]) Copy(values []T) []T {
+	out := a.Slice(len(values))
+	copy(out, values)
+	return out
+}
+
+// Reset zeroes all allocated values, so that the Arena does not keep references, and reuses the chunks
+func (a *Arena[T]) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i := 0; i < a.chunk && i < len(a.chunks); i++ {
+		clear(a.chunks[i])
+	}
+	if a.chunk < len(a.chunks) {
+		clear(a.chunks[a.chunk][:a.offset])
+	}
+	a.chunk = 0
+	a.offset = 0
+}
+
+// Chunks returns the number of chunks allocated by the Arena
+func (a *Arena[T]) Chunks() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.chunks)
+}
@@ -0,0 +1,86 @@
+package arena
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type value struct {
+	id   int
+	data []byte
+}
+
+func TestArena(t *testing.T) {
+	t.Run("should allocate zero values from chunks", func(t *testing.T) {
+		a := New[value](2)
+		first, second, third := a.New(), a.New(), a.New()
+		assert.Equal(t, value{}, *first)
+		assert.Equal(t, 2, a.Chunks())
+
+		first.id, second.id, third.id = 1, 2, 3
+		assert.Equal(t, 1, first.id)
+		assert.Equal(t, 2, second.id)
+		assert.Equal(t, 3, third.id)
+	})
+
+	t.Run("should limit the capacity of slices", func(t *testing.T) {
+		a := New[int](8)
+		first := a.Copy([]int{1, 2})
+		second := a.Slice(2)
+		assert.Equal(t, 2, cap(first))
+
+		first = append(first, 3)
+		assert.Equal(t, []int{0, 0}, second)
+		assert.Equal(t, []int{1, 2, 3}, first)
+	})
+
+	t.Run("should allocate slices larger than a chunk on the heap", func(t *testing.T) {
+		a := New[int](2)
+		assert.Len(t, a.Slice(3), 3)
+		assert.Equal(t, 0, a.Chunks())
+	})
+
+	t.Run("should reuse zeroed chunks after reset", func(t *testing.T) {
+		a := New[value](2)
+		for i := 0; i < 3; i++ {
+			v := a.New()
+			v.id = i
+			v.data = []byte("data")
+		}
+		assert.Equal(t, 2, a.Chunks())
+
+		a.Reset()
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, value{}, *a.New())
+		}
+		assert.Equal(t, 2, a.Chunks())
+	})
+}
+
+func BenchmarkArena(b *testing.B) {
+	var sink *value
+
+	b.Run("heap", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < 64; j++ {
+				sink = &value{id: j}
+			}
+		}
+	})
+
+	b.Run("arena", func(b *testing.B) {
+		b.ReportAllocs()
+		a := New[value](64)
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < 64; j++ {
+				sink = a.New()
+				sink.id = j
+			}
+			a.Reset()
+		}
+	})
+
+	_ = sink
+}
@@ -56,7 +56,7 @@ func (j *JsonConverter) importSchema() error {
 func (j *JsonConverter) importFullType(fullType FullType) (err error) {
 	switch fullType.Kind {
 	case SCALAR:
-		j.doc.ImportScalarTypeDefinition(fullType.Name, fullType.Description)
+		j.importScalar(fullType)
 	case OBJECT:
 		err = j.importObject(fullType)
 	case ENUM:
@@ -71,6 +71,16 @@ func (j *JsonConverter) importFullType(fullType FullType) (err error) {
 	return
 }
 
+func (j *JsonConverter) importScalar(fullType FullType) {
+	if fullType.SpecifiedByURL == nil {
+		j.doc.ImportScalarTypeDefinition(fullType.Name, fullType.Description)
+		return
+	}
+
+	directiveRef := j.importSpecifiedByDirective(*fullType.SpecifiedByURL)
+	j.doc.ImportScalarTypeDefinitionWithDirectives(fullType.Name, fullType.Description, []int{directiveRef})
+}
+
 func (j *JsonConverter) importObject(fullType FullType) error {
 	fieldRefs, err := j.importFields(fullType.Fields)
 	if err != nil {
@@ -273,3 +283,15 @@ func (j *JsonConverter) importDeprecatedDirective(reason *string) (ref int) {
 
 	return j.doc.ImportDirective(DeprecatedDirectiveName, args)
 }
+
+func (j *JsonConverter) importSpecifiedByDirective(url string) (ref int) {
+	valueRef := j.doc.ImportStringValue([]byte(url), false)
+	value := ast.Value{
+		Kind: ast.ValueKindString,
+		Ref:  valueRef,
+	}
+	j.doc.AddValue(value)
+	args := []int{j.doc.ImportArgument(SpecifiedByURLArgName, value)}
+
+	return j.doc.ImportDirective(SpecifiedByDirectiveName, args)
+}
@@ -63,6 +63,42 @@ func TestJSONConverter_GraphQLDocument(t *testing.T) {
 	}
 }
 
+func TestJSONConverter_SpecifiedByURL(t *testing.T) {
+	definition, report := astparser.ParseGraphqlDocumentString(`
+		scalar DateTime @specifiedBy(url: "https://scalars.graphql.org/andimarek/date-time")
+		scalar JSON
+		type Query { now: DateTime json: JSON }
+	`)
+	require.False(t, report.HasErrors(), report.Error())
+
+	gen := NewGenerator()
+	var data Data
+	gen.Generate(&definition, &report, &data)
+	require.False(t, report.HasErrors(), report.Error())
+
+	specifiedByURLs := make(map[string]*string)
+	for _, fullType := range data.Schema.Types {
+		if fullType.Kind == SCALAR {
+			specifiedByURLs[fullType.Name] = fullType.SpecifiedByURL
+		}
+	}
+	require.NotNil(t, specifiedByURLs["DateTime"])
+	assert.Equal(t, "https://scalars.graphql.org/andimarek/date-time", *specifiedByURLs["DateTime"])
+	assert.Nil(t, specifiedByURLs["JSON"])
+
+	introspectionJSON, err := json.Marshal(data)
+	require.NoError(t, err)
+
+	converter := JsonConverter{}
+	doc, err := converter.GraphQLDocument(bytes.NewReader(introspectionJSON))
+	require.NoError(t, err)
+
+	sdl, err := astprinter.PrintString(doc, nil)
+	require.NoError(t, err)
+	assert.Contains(t, sdl, `scalar DateTime @specifiedBy(url: "https://scalars.graphql.org/andimarek/date-time")`)
+	assert.Contains(t, sdl, `scalar JSON `)
+}
+
 func BenchmarkJsonConverter_GraphQLDocument(b *testing.B) {
 	introspectedBytes, err := os.ReadFile("./testdata/swapi_introspection_response.json")
 	require.NoError(b, err)
@@ -12,6 +12,8 @@ import (
 const (
 	DeprecatedDirectiveName  = "deprecated"
 	DeprecationReasonArgName = "reason"
+	SpecifiedByDirectiveName = "specifiedBy"
+	SpecifiedByURLArgName    = "url"
 )
 
 type Generator struct {
@@ -192,6 +194,7 @@ func (i *introspectionVisitor) EnterScalarTypeDefinition(ref int) {
 	typeDefinition.Kind = SCALAR
 	typeDefinition.Name = i.definition.ScalarTypeDefinitionNameString(ref)
 	typeDefinition.Description = i.definition.ScalarTypeDefinitionDescriptionString(ref)
+	typeDefinition.SpecifiedByURL = i.specifiedByURL(ref)
 	i.data.Schema.Types = append(i.data.Schema.Types, typeDefinition)
 }
 
@@ -350,3 +353,16 @@ func (i *introspectionVisitor) deprecationReason(directiveRef int) (reason *stri
 
 	return
 }
+
+func (i *introspectionVisitor) specifiedByURL(scalarTypeDefinitionRef int) (url *string) {
+	directiveRef, exists := i.definition.ScalarTypeDefinitionDirectiveByName(scalarTypeDefinitionRef, []byte(SpecifiedByDirectiveName))
+	if !exists {
+		return nil
+	}
+	argValue, exists := i.definition.DirectiveArgumentValueByName(directiveRef, []byte(SpecifiedByURLArgName))
+	if !exists {
+		return nil
+	}
+	urlContent := i.definition.ValueContentString(argValue)
+	return &urlContent
+}
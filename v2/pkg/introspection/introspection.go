@@ -57,6 +57,8 @@ type FullType struct {
 	EnumValues []EnumValue `json:"enumValues,omitempty"`
 	// not empty for __TypeKind INTERFACE and UNION only
 	PossibleTypes []TypeRef `json:"possibleTypes"`
+	// not nil for __TypeKind SCALAR with the @specifiedBy directive only
+	SpecifiedByURL *string `json:"specifiedByURL,omitempty"`
 }
 
 func NewFullType() FullType {
@@ -0,0 +1,87 @@
+// Package jsoncodec abstracts the json encoding and decoding of GraphQL requests and responses,
+// so that encoding/json can be replaced with a faster implementation.
+//
+// The default codec is based on encoding/json. Implementations with the same signatures can be used directly,
+// e.g. bytedance/sonic:
+//
+//	jsoncodec.SetDefault(sonic.ConfigStd)
+//
+// Packages exposing functions instead of an api type, e.g. goccy/go-json, can be used with Funcs:
+//
+//	jsoncodec.SetDefault(jsoncodec.Funcs{MarshalFunc: gojson.Marshal, UnmarshalFunc: gojson.Unmarshal})
+//
+// To select the codec at build time, call SetDefault from an init function in a file with a build constraint.
+package jsoncodec
+
+import (
+	"encoding/json"
+	"sync/atomic"
+)
+
+// Codec encodes and decodes json
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// Std is the Codec based on encoding/json
+var Std Codec = stdCodec{}
+
+type stdCodec struct{}
+
+func (stdCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// Funcs is a Codec calling the given functions
+type Funcs struct {
+	MarshalFunc   func(v interface{}) ([]byte, error)
+	UnmarshalFunc func(data []byte, v interface{}) error
+}
+
+func (f Funcs) Marshal(v interface{}) ([]byte, error) {
+	return f.MarshalFunc(v)
+}
+
+func (f Funcs) Unmarshal(data []byte, v interface{}) error {
+	return f.UnmarshalFunc(data, v)
+}
+
+// codecHolder allows to store codecs of different types in the atomic.Value
+type codecHolder struct {
+	codec Codec
+}
+
+var defaultCodec atomic.Value
+
+func init() {
+	defaultCodec.Store(codecHolder{codec: Std})
+}
+
+// Default returns the Codec used for requests and responses
+func Default() Codec {
+	return defaultCodec.Load().(codecHolder).codec
+}
+
+// SetDefault replaces the Codec used for requests and responses, a nil codec restores Std
+// It is safe to call SetDefault concurrently, but the codec should be set before serving requests
+func SetDefault(codec Codec) {
+	if codec == nil {
+		codec = Std
+	}
+	defaultCodec.Store(codecHolder{codec: codec})
+}
+
+// Marshal encodes v with the default Codec
+func Marshal(v interface{}) ([]byte, error) {
+	return Default().Marshal(v)
+}
+
+// Unmarshal decodes data into v with the default Codec
+func Unmarshal(data []byte, v interface{}) error {
+	return Default().Unmarshal(data, v)
+}
@@ -0,0 +1,43 @@
+package jsoncodec
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetDefault(t *testing.T) {
+	t.Cleanup(func() {
+		SetDefault(nil)
+	})
+
+	assert.Equal(t, Std, Default())
+
+	var marshaled, unmarshaled int
+	SetDefault(Funcs{
+		MarshalFunc: func(v interface{}) ([]byte, error) {
+			marshaled++
+			return json.Marshal(v)
+		},
+		UnmarshalFunc: func(data []byte, v interface{}) error {
+			unmarshaled++
+			return json.Unmarshal(data, v)
+		},
+	})
+
+	data, err := Marshal(map[string]string{"hello": "world"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"hello":"world"}`, string(data))
+
+	var value map[string]string
+	require.NoError(t, Unmarshal(data, &value))
+	assert.Equal(t, map[string]string{"hello": "world"}, value)
+
+	assert.Equal(t, 1, marshaled)
+	assert.Equal(t, 1, unmarshaled)
+
+	SetDefault(nil)
+	assert.Equal(t, Std, Default())
+}
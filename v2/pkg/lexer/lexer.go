@@ -130,6 +130,8 @@ func (l *Lexer) readDotOrSpread(tok *token.Token) {
 func (l *Lexer) readComment(tok *token.Token) {
 
 	tok.Keyword = keyword.COMMENT
+	// an empty comment ends right after the hashtag
+	tok.SetEnd(l.input.InputPosition, l.input.TextPosition)
 
 	for {
 		next := l.readRune()
@@ -351,6 +353,8 @@ func (l *Lexer) readBlockString(tok *token.Token) {
 			quoteCount = 0
 			whitespaceCount++
 		case runes.EOF:
+			// unterminated block string, the literal ends at the end of the input
+			tok.SetEnd(l.input.InputPosition, l.input.TextPosition)
 			return
 		case runes.QUOTE:
 			if escaped {
@@ -218,6 +218,9 @@ func TestLexer_Peek_Read(t *testing.T) {
 	t.Run("complex multi line string", func(t *testing.T) {
 		run("\"\"\"block string uses \\\"\"\"\n\"\"\"", mustRead(keyword.BLOCKSTRING, "block string uses \\\"\"\""))
 	})
+	t.Run("unterminated multi line string", func(t *testing.T) {
+		run("\"\"\"foo", mustRead(keyword.BLOCKSTRING, "foo"), mustRead(keyword.EOF, ""))
+	})
 	t.Run("complex multi line string with carriage return", func(t *testing.T) {
 		run("\"\"\"block string uses \\\"\"\"\r\n\"\"\"", mustRead(keyword.BLOCKSTRING, "block string uses \\\"\"\""))
 	})
@@ -655,6 +658,27 @@ func TestLexerRegressions(t *testing.T) {
 	}
 }
 
+func FuzzLexer(f *testing.F) {
+	f.Add([]byte(introspectionQuery))
+	f.Add([]byte(`"\u{1F600} \uD83D\uDE00" """block \""" string""" 1.5e-3 ...`))
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		in := &ast.Input{}
+		in.ResetInputBytes(input)
+		lexer := &Lexer{}
+		lexer.SetInput(in)
+
+		for i := 0; i <= len(input); i++ {
+			tok := lexer.Read()
+			if tok.Keyword == keyword.EOF {
+				return
+			}
+			_ = in.ByteSlice(tok.Literal)
+		}
+		t.Fatalf("lexer did not reach EOF")
+	})
+}
+
 func BenchmarkLexer(b *testing.B) {
 
 	in := &ast.Input{}
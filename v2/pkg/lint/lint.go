@@ -0,0 +1,138 @@
+// Package lint checks client operations against a schema for patterns which are valid GraphQL but undesirable,
+// e.g. the usage of deprecated fields or lists which are fetched without a limit.
+//
+// In contrast to astvalidation, the linter doesn't stop at the first finding, it returns all findings of all rules,
+// so CI pipelines are able to lint the operations of clients against the live schema:
+//
+//	findings, err := lint.DefaultLinter().Lint(&operation, &definition)
+//
+// The definition has to be merged with the base schema, see asttransform.MergeDefinitionWithBaseSchema.
+// The operation must not be normalized, otherwise the fragments of the operation are inlined.
+package lint
+
+import (
+	"sort"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqlerrors"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/position"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+type Severity int
+
+const (
+	SeverityInfo Severity = iota + 1
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Finding is a violation of a rule in the operation
+type Finding struct {
+	// Rule is the name of the rule, e.g. RuleDeprecatedUsage
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	// Path is the path of the violation within the operation or the fragment, e.g. "query.user.friends"
+	Path      string                   `json:"path"`
+	Locations []graphqlerrors.Location `json:"locations"`
+}
+
+// Findings collects the findings of the rules
+type Findings struct {
+	findings []Finding
+}
+
+// Add adds the finding of the rule at the position of the operation
+func (f *Findings) Add(rule string, severity Severity, path ast.Path, message string, positions ...position.Position) {
+	f.findings = append(f.findings, Finding{
+		Rule:      rule,
+		Severity:  severity,
+		Message:   message,
+		Path:      path.DotDelimitedString(),
+		Locations: operationreport.LocationsFromPosition(positions...),
+	})
+}
+
+// Rule registers the visitors of a rule on the walker of the linter, the visitors add their violations to findings
+type Rule func(walker *astvisitor.Walker, findings *Findings)
+
+// Linter lints operations with the registered rules, it must not be used concurrently
+type Linter struct {
+	walker   astvisitor.Walker
+	findings Findings
+}
+
+// DefaultLinter returns a Linter with all rules of the package registered
+func DefaultLinter() *Linter {
+	return NewLinter(
+		LeafSelections(),
+		DuplicateFieldSets(DefaultMinDuplicateFields),
+		DeprecatedUsage(),
+		UnboundedLists(DefaultPaginationArguments...),
+	)
+}
+
+func NewLinter(rules ...Rule) *Linter {
+	linter := &Linter{
+		walker: astvisitor.NewWalker(48),
+	}
+	for _, rule := range rules {
+		linter.RegisterRule(rule)
+	}
+	return linter
+}
+
+// RegisterRule registers a rule to the Linter
+func (l *Linter) RegisterRule(rule Rule) {
+	rule(&l.walker, &l.findings)
+}
+
+// Lint returns the findings of the operation in the order of their first location,
+// err is the report of the walker if the operation can't be walked
+func (l *Linter) Lint(operation, definition *ast.Document) (findings []Finding, err error) {
+	l.findings.findings = nil
+	defer func() {
+		l.findings.findings = nil
+	}()
+
+	var report operationreport.Report
+	l.walker.Walk(operation, definition, &report)
+	if report.HasErrors() {
+		return nil, report
+	}
+
+	findings = l.findings.findings
+	sort.SliceStable(findings, func(i, j int) bool {
+		return locationLess(findings[i].Locations, findings[j].Locations)
+	})
+	return findings, nil
+}
+
+func locationLess(left, right []graphqlerrors.Location) bool {
+	if len(left) == 0 || len(right) == 0 {
+		return len(left) > len(right)
+	}
+	if left[0].Line != right[0].Line {
+		return left[0].Line < right[0].Line
+	}
+	return left[0].Column < right[0].Column
+}
@@ -0,0 +1,175 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqlerrors"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+)
+
+const testSchema = `
+	schema { query: Query }
+
+	type Query {
+		user(id: ID!): User
+		users(first: Int, after: String): [User!]!
+		search(term: String, limit: Int = 10): [User!]
+		tags: [String!]!
+		friends(first: Int, last: Int): UserConnection
+		legacyUser(id: ID!, legacyId: ID @deprecated(reason: "use id")): User @deprecated(reason: "use user")
+		usersByRole(roles: [Role!]): [User!]!
+	}
+
+	enum Role {
+		ADMIN
+		SUPERUSER @deprecated
+		USER
+	}
+
+	type UserConnection {
+		edges: [UserEdge]
+	}
+
+	type UserEdge {
+		node: User
+	}
+
+	type User {
+		id: ID!
+		name: String
+		address: Address
+	}
+
+	type Address {
+		street: String
+		city: String
+	}
+`
+
+func TestLinter(t *testing.T) {
+	run := func(t *testing.T, linter *Linter, operation string) []Finding {
+		t.Helper()
+		definitionDocument := unsafeparser.ParseGraphqlDocumentStringWithBaseSchema(testSchema)
+		operationDocument := unsafeparser.ParseGraphqlDocumentString(operation)
+		findings, err := linter.Lint(&operationDocument, &definitionDocument)
+		require.NoError(t, err)
+		return findings
+	}
+
+	t.Run("leaf selections", func(t *testing.T) {
+		findings := run(t, NewLinter(LeafSelections()), `
+			query {
+				user(id: 1) { name address }
+				users { id address { city } }
+			}`)
+		assert.Equal(t, []Finding{
+			{
+				Rule:      RuleLeafSelections,
+				Severity:  SeverityError,
+				Message:   `field "address" of type "Address" must have a selection of subfields`,
+				Path:      "query.user",
+				Locations: []graphqlerrors.Location{{Line: 3, Column: 24}},
+			},
+		}, findings)
+	})
+
+	t.Run("duplicate field sets", func(t *testing.T) {
+		t.Run("selection sets with the same fields in any order", func(t *testing.T) {
+			findings := run(t, NewLinter(DuplicateFieldSets(2)), `
+				query {
+					user(id: 1) { id name address { city } }
+					users { name id address { city } }
+					search { id name address { street } }
+				}`)
+			assert.Equal(t, []Finding{
+				{
+					Rule:      RuleDuplicateFieldSets,
+					Severity:  SeverityInfo,
+					Message:   `selection set on type "User" duplicates the selection set at 3:18, extract a fragment`,
+					Path:      "query.users",
+					Locations: []graphqlerrors.Location{{Line: 4, Column: 12}, {Line: 3, Column: 18}},
+				},
+			}, findings)
+		})
+
+		t.Run("selection sets duplicating a fragment", func(t *testing.T) {
+			findings := run(t, NewLinter(DuplicateFieldSets(2)), `
+				fragment UserFields on User { id name }
+				query {
+					user(id: 1) { ...UserFields }
+					users { name id }
+				}`)
+			require.Len(t, findings, 1)
+			assert.Equal(t, `selection set on type "User" duplicates the selection set of fragment "UserFields", spread the fragment instead`, findings[0].Message)
+		})
+
+		t.Run("selection sets with different arguments or less fields than the minimum", func(t *testing.T) {
+			findings := run(t, NewLinter(DuplicateFieldSets(3)), `
+				query {
+					a: user(id: 1) { id name }
+					b: user(id: 2) { id name }
+					users { id address(id: 1) { city street } }
+					search { id address(id: 2) { city street } }
+				}`)
+			assert.Empty(t, findings)
+		})
+	})
+
+	t.Run("deprecated usage", func(t *testing.T) {
+		findings := run(t, NewLinter(DeprecatedUsage()), `
+			query {
+				legacyUser(id: 1, legacyId: 2) { id }
+				usersByRole(roles: [ADMIN, SUPERUSER]) { id }
+			}`)
+		assert.Equal(t, []string{
+			`field "Query.legacyUser" is deprecated: use user`,
+			`argument "legacyId" is deprecated: use id`,
+			`enum value "Role.SUPERUSER" of argument "roles" is deprecated: No longer supported`,
+		}, messages(findings))
+		assert.Equal(t, SeverityWarning, findings[0].Severity)
+	})
+
+	t.Run("unbounded lists", func(t *testing.T) {
+		findings := run(t, NewLinter(UnboundedLists(DefaultPaginationArguments...)), `
+			query {
+				users { id }
+				bounded: users(first: 10) { id }
+				search { id }
+				tags
+				friends { edges { node { id } } }
+				usersByRole { id }
+			}`)
+		assert.Equal(t, []string{
+			`field "users" returns an unbounded list, set one of the arguments first`,
+			`field "friends" returns an unbounded list, set one of the arguments first, last`,
+		}, messages(findings))
+	})
+
+	t.Run("default linter reports the findings of all rules in the order of their location", func(t *testing.T) {
+		findings := run(t, DefaultLinter(), `
+			query {
+				legacyUser(id: 1) { id name }
+				users { address }
+				user(id: 2) { name id }
+			}`)
+		rules := make([]string, 0, len(findings))
+		for _, finding := range findings {
+			rules = append(rules, finding.Rule)
+		}
+		assert.Equal(t, []string{RuleDeprecatedUsage, RuleUnboundedLists, RuleLeafSelections, RuleDuplicateFieldSets}, rules)
+
+		// the findings of a previous operation are not reported again
+		assert.Empty(t, run(t, DefaultLinter(), `query { user(id: 1) { id } }`))
+	})
+}
+
+func messages(findings []Finding) []string {
+	out := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		out = append(out, finding.Message)
+	}
+	return out
+}
@@ -0,0 +1,121 @@
+package lint
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/position"
+)
+
+const (
+	RuleDeprecatedUsage = "deprecated-usage"
+
+	deprecatedDirectiveName  = "deprecated"
+	deprecationReasonArgName = "reason"
+	// defaultDeprecationReason is the default value of the reason argument of @deprecated
+	defaultDeprecationReason = "No longer supported"
+)
+
+// DeprecatedUsage reports the usage of deprecated fields, arguments and enum values,
+// the enum values are checked in argument values and lists of argument values
+func DeprecatedUsage() Rule {
+	return func(walker *astvisitor.Walker, findings *Findings) {
+		visitor := &deprecatedUsageVisitor{
+			Walker:   walker,
+			findings: findings,
+		}
+		walker.RegisterEnterDocumentVisitor(visitor)
+		walker.RegisterEnterFieldVisitor(visitor)
+		walker.RegisterEnterArgumentVisitor(visitor)
+	}
+}
+
+type deprecatedUsageVisitor struct {
+	*astvisitor.Walker
+	findings              *Findings
+	operation, definition *ast.Document
+}
+
+func (d *deprecatedUsageVisitor) EnterDocument(operation, definition *ast.Document) {
+	d.operation = operation
+	d.definition = definition
+}
+
+func (d *deprecatedUsageVisitor) EnterField(ref int) {
+	fieldDefinition, exists := d.FieldDefinition(ref)
+	if !exists {
+		return
+	}
+	directive, deprecated := d.definition.FieldDefinitionDirectiveByName(fieldDefinition, []byte(deprecatedDirectiveName))
+	if !deprecated {
+		return
+	}
+	d.findings.Add(RuleDeprecatedUsage, SeverityWarning, d.Path,
+		fmt.Sprintf("field \"%s.%s\" is deprecated: %s", d.EnclosingTypeDefinition.NameString(d.definition), d.operation.FieldNameString(ref), d.reason(directive)),
+		d.operation.Fields[ref].Position,
+	)
+}
+
+func (d *deprecatedUsageVisitor) EnterArgument(ref int) {
+	inputValueDefinition, exists := d.ArgumentInputValueDefinition(ref)
+	if !exists {
+		return
+	}
+	argumentName := d.operation.ArgumentNameString(ref)
+	if directive, deprecated := d.inputValueDefinitionDeprecation(inputValueDefinition); deprecated {
+		d.findings.Add(RuleDeprecatedUsage, SeverityWarning, d.Path,
+			fmt.Sprintf("argument %q is deprecated: %s", argumentName, d.reason(directive)),
+			d.operation.Arguments[ref].Position,
+		)
+	}
+
+	enumTypeName := d.definition.ResolveTypeNameBytes(d.definition.InputValueDefinitionType(inputValueDefinition))
+	enumType, exists := d.definition.Index.FirstNodeByNameBytes(enumTypeName)
+	if !exists || enumType.Kind != ast.NodeKindEnumTypeDefinition {
+		return
+	}
+	d.checkEnumValue(enumType.Ref, argumentName, d.operation.ArgumentValue(ref), d.operation.Arguments[ref].Position)
+}
+
+func (d *deprecatedUsageVisitor) checkEnumValue(enumType int, argumentName string, value ast.Value, position position.Position) {
+	switch value.Kind {
+	case ast.ValueKindList:
+		for _, item := range d.operation.ListValues[value.Ref].Refs {
+			d.checkEnumValue(enumType, argumentName, d.operation.Value(item), position)
+		}
+	case ast.ValueKindEnum:
+		valueName := d.operation.EnumValueNameBytes(value.Ref)
+		for _, enumValueDefinition := range d.definition.EnumTypeDefinitions[enumType].EnumValuesDefinition.Refs {
+			if !bytes.Equal(valueName, d.definition.EnumValueDefinitionNameBytes(enumValueDefinition)) {
+				continue
+			}
+			directive, deprecated := d.definition.EnumValueDefinitionDirectiveByName(enumValueDefinition, []byte(deprecatedDirectiveName))
+			if deprecated {
+				d.findings.Add(RuleDeprecatedUsage, SeverityWarning, d.Path,
+					fmt.Sprintf("enum value \"%s.%s\" of argument %q is deprecated: %s", d.definition.EnumTypeDefinitionNameString(enumType), valueName, argumentName, d.reason(directive)),
+					position,
+				)
+			}
+			return
+		}
+	}
+}
+
+func (d *deprecatedUsageVisitor) inputValueDefinitionDeprecation(inputValueDefinition int) (directive int, deprecated bool) {
+	for _, directive = range d.definition.InputValueDefinitions[inputValueDefinition].Directives.Refs {
+		if d.definition.DirectiveNameString(directive) == deprecatedDirectiveName {
+			return directive, true
+		}
+	}
+	return ast.InvalidRef, false
+}
+
+func (d *deprecatedUsageVisitor) reason(directive int) string {
+	value, exists := d.definition.DirectiveArgumentValueByName(directive, []byte(deprecationReasonArgName))
+	if !exists || value.Kind != ast.ValueKindString {
+		return defaultDeprecationReason
+	}
+	return d.definition.StringValueContentString(value.Ref)
+}
@@ -0,0 +1,150 @@
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/position"
+)
+
+const RuleDuplicateFieldSets = "duplicate-field-sets"
+
+// DefaultMinDuplicateFields is the minimum of fields of a duplicated selection set used by DefaultLinter
+const DefaultMinDuplicateFields = 2
+
+// DuplicateFieldSets reports selection sets which select the same fields on the same type as a previous selection set,
+// so a fragment is preferable. Selection sets with less than minFields fields are ignored.
+// The fields are compared with their aliases, arguments and subselections, independent of their order.
+// If the previous selection set is the selection set of a fragment, the fragment is suggested instead.
+// Selection sets of operations are ignored.
+func DuplicateFieldSets(minFields int) Rule {
+	return func(walker *astvisitor.Walker, findings *Findings) {
+		visitor := &duplicateFieldSetsVisitor{
+			Walker:    walker,
+			findings:  findings,
+			minFields: minFields,
+		}
+		walker.RegisterEnterDocumentVisitor(visitor)
+		walker.RegisterEnterSelectionSetVisitor(visitor)
+	}
+}
+
+type duplicateFieldSetsVisitor struct {
+	*astvisitor.Walker
+	findings              *Findings
+	operation, definition *ast.Document
+	minFields             int
+	// selectionSets are the first occurrences of the selection sets by type name and canonical selection
+	selectionSets map[string]fieldSetOccurrence
+}
+
+type fieldSetOccurrence struct {
+	position position.Position
+	// fragmentName is the name of the fragment of the selection set, if the selection set is the selection set of a fragment
+	fragmentName string
+}
+
+func (d *duplicateFieldSetsVisitor) EnterDocument(operation, definition *ast.Document) {
+	d.operation = operation
+	d.definition = definition
+	d.selectionSets = map[string]fieldSetOccurrence{}
+}
+
+func (d *duplicateFieldSetsVisitor) EnterSelectionSet(ref int) {
+	ancestor := d.Ancestor()
+	if ancestor.Kind == ast.NodeKindOperationDefinition {
+		return
+	}
+	if fields := d.operation.SelectionSetFieldSelections(ref); len(fields) < d.minFields {
+		return
+	}
+
+	selectionSet, err := d.canonicalSelectionSet(ref)
+	if err != nil {
+		d.StopWithInternalErr(err)
+		return
+	}
+	key := d.EnclosingTypeDefinition.NameString(d.definition) + " " + selectionSet
+
+	current := fieldSetOccurrence{
+		position: d.operation.SelectionSets[ref].LBrace,
+	}
+	if ancestor.Kind == ast.NodeKindFragmentDefinition {
+		current.fragmentName = d.operation.FragmentDefinitionNameString(ancestor.Ref)
+	}
+
+	previous, duplicate := d.selectionSets[key]
+	if !duplicate {
+		d.selectionSets[key] = current
+		return
+	}
+	message := fmt.Sprintf("selection set on type %q duplicates the selection set at %d:%d, extract a fragment",
+		d.EnclosingTypeDefinition.NameString(d.definition), previous.position.LineStart, previous.position.CharStart)
+	if previous.fragmentName != "" {
+		message = fmt.Sprintf("selection set on type %q duplicates the selection set of fragment %q, spread the fragment instead",
+			d.EnclosingTypeDefinition.NameString(d.definition), previous.fragmentName)
+	}
+	d.findings.Add(RuleDuplicateFieldSets, SeverityInfo, d.Path, message, current.position, previous.position)
+}
+
+// canonicalSelectionSet prints the selections of the selection set in a canonical order
+func (d *duplicateFieldSetsVisitor) canonicalSelectionSet(ref int) (string, error) {
+	selections := make([]string, 0, len(d.operation.SelectionSets[ref].SelectionRefs))
+	for _, selectionRef := range d.operation.SelectionSets[ref].SelectionRefs {
+		selection, err := d.canonicalSelection(selectionRef)
+		if err != nil {
+			return "", err
+		}
+		selections = append(selections, selection)
+	}
+	sort.Strings(selections)
+	return "{" + strings.Join(selections, " ") + "}", nil
+}
+
+func (d *duplicateFieldSetsVisitor) canonicalSelection(selectionRef int) (string, error) {
+	var (
+		out          bytes.Buffer
+		selectionSet = ast.InvalidRef
+	)
+	selection := d.operation.Selections[selectionRef]
+	switch selection.Kind {
+	case ast.SelectionKindField:
+		field := selection.Ref
+		out.WriteString(d.operation.FieldAliasOrNameString(field))
+		out.WriteByte(':')
+		out.WriteString(d.operation.FieldNameString(field))
+		if d.operation.FieldHasArguments(field) {
+			if err := d.operation.PrintArguments(d.operation.FieldArguments(field), &out); err != nil {
+				return "", err
+			}
+		}
+		if ref, ok := d.operation.FieldSelectionSet(field); ok {
+			selectionSet = ref
+		}
+	case ast.SelectionKindInlineFragment:
+		inlineFragment := selection.Ref
+		out.WriteString("...")
+		if d.operation.InlineFragmentHasTypeCondition(inlineFragment) {
+			out.WriteString(" on ")
+			out.Write(d.operation.InlineFragmentTypeConditionName(inlineFragment))
+		}
+		if ref, ok := d.operation.InlineFragmentSelectionSet(inlineFragment); ok {
+			selectionSet = ref
+		}
+	case ast.SelectionKindFragmentSpread:
+		out.WriteString("...")
+		out.Write(d.operation.FragmentSpreadNameBytes(selection.Ref))
+	}
+	if selectionSet != ast.InvalidRef {
+		nested, err := d.canonicalSelectionSet(selectionSet)
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(nested)
+	}
+	return out.String(), nil
+}
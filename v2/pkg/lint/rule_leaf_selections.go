@@ -0,0 +1,53 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+)
+
+const RuleLeafSelections = "leaf-selections"
+
+// LeafSelections reports selections which don't end in leaf fields,
+// i.e. fields of an object, interface or union type without a selection of subfields
+func LeafSelections() Rule {
+	return func(walker *astvisitor.Walker, findings *Findings) {
+		visitor := &leafSelectionsVisitor{
+			Walker:   walker,
+			findings: findings,
+		}
+		walker.RegisterEnterDocumentVisitor(visitor)
+		walker.RegisterEnterFieldVisitor(visitor)
+	}
+}
+
+type leafSelectionsVisitor struct {
+	*astvisitor.Walker
+	findings              *Findings
+	operation, definition *ast.Document
+}
+
+func (l *leafSelectionsVisitor) EnterDocument(operation, definition *ast.Document) {
+	l.operation = operation
+	l.definition = definition
+}
+
+func (l *leafSelectionsVisitor) EnterField(ref int) {
+	fieldDefinition, exists := l.FieldDefinition(ref)
+	if !exists {
+		return
+	}
+	switch l.definition.FieldDefinitionTypeNode(fieldDefinition).Kind {
+	case ast.NodeKindObjectTypeDefinition, ast.NodeKindInterfaceTypeDefinition, ast.NodeKindUnionTypeDefinition:
+	default:
+		return
+	}
+	if l.operation.FieldHasSelections(ref) {
+		return
+	}
+	l.findings.Add(RuleLeafSelections, SeverityError, l.Path,
+		fmt.Sprintf("field %q of type %q must have a selection of subfields", l.operation.FieldAliasOrNameString(ref), l.definition.FieldDefinitionTypeNameString(fieldDefinition)),
+		l.operation.Fields[ref].Position,
+	)
+}
@@ -0,0 +1,78 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+)
+
+const (
+	RuleUnboundedLists = "unbounded-lists"
+
+	connectionTypeSuffix = "Connection"
+)
+
+// DefaultPaginationArguments are the arguments limiting the size of lists used by DefaultLinter
+var DefaultPaginationArguments = []string{"first", "last", "limit"}
+
+// UnboundedLists reports fields returning a list or a connection, i.e. a type with a name ending in Connection,
+// which define one of the pagination arguments but are selected without setting any of them.
+// Pagination arguments with a default value bound the list.
+// Fields without pagination arguments aren't reported, as the client can't limit their size.
+func UnboundedLists(paginationArguments ...string) Rule {
+	return func(walker *astvisitor.Walker, findings *Findings) {
+		visitor := &unboundedListsVisitor{
+			Walker:              walker,
+			findings:            findings,
+			paginationArguments: paginationArguments,
+		}
+		walker.RegisterEnterDocumentVisitor(visitor)
+		walker.RegisterEnterFieldVisitor(visitor)
+	}
+}
+
+type unboundedListsVisitor struct {
+	*astvisitor.Walker
+	findings              *Findings
+	operation, definition *ast.Document
+	paginationArguments   []string
+}
+
+func (u *unboundedListsVisitor) EnterDocument(operation, definition *ast.Document) {
+	u.operation = operation
+	u.definition = definition
+}
+
+func (u *unboundedListsVisitor) EnterField(ref int) {
+	fieldDefinition, exists := u.FieldDefinition(ref)
+	if !exists {
+		return
+	}
+	fieldType := u.definition.FieldDefinitionType(fieldDefinition)
+	typeName := u.definition.ResolveTypeNameString(fieldType)
+	if !u.definition.TypeIsList(fieldType) && !(strings.HasSuffix(typeName, connectionTypeSuffix) && typeName != connectionTypeSuffix) {
+		return
+	}
+
+	var defined []string
+	for _, argument := range u.definition.FieldDefinitionArgumentsDefinitions(fieldDefinition) {
+		argumentName := u.definition.InputValueDefinitionNameString(argument)
+		if !slices.Contains(u.paginationArguments, argumentName) {
+			continue
+		}
+		if _, set := u.operation.FieldArgument(ref, []byte(argumentName)); set || u.definition.InputValueDefinitionHasDefaultValue(argument) {
+			return
+		}
+		defined = append(defined, argumentName)
+	}
+	if len(defined) == 0 {
+		return
+	}
+	u.findings.Add(RuleUnboundedLists, SeverityWarning, u.Path,
+		fmt.Sprintf("field %q returns an unbounded list, set one of the arguments %s", u.operation.FieldAliasOrNameString(ref), strings.Join(defined, ", ")),
+		u.operation.Fields[ref].Position,
+	)
+}
@@ -27,6 +27,9 @@ const (
 	UnknownFieldOfInputObjectErrMsg         = `Field "%s" is not defined by type "%s".`
 	DuplicatedFieldInputObjectErrMsg        = `There can be only one input field named "%s".`
 	ValueIsNotAnInputObjectTypeErrMsg       = `Expected value of type "%s", found %s.`
+	ArgumentConstraintViolatedErrMsg        = `Argument "%s" on field "%s.%s" %s.`
+	InvalidListPostProcessingArgumentErrMsg = `Directive "@%s" on field "%s" is invalid: %s.`
+	InvalidDataSourceDirectiveErrMsg        = `Directive "@%s" on field "%s" is invalid: %s.`
 )
 
 type ExternalError struct {
@@ -217,6 +220,27 @@ func ErrArgumentNotDefinedOnField(argName, typeName, fieldName ast.ByteSlice, po
 	return err
 }
 
+func ErrArgumentConstraintViolated(argName, typeName, fieldName ast.ByteSlice, reason string, position position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf(ArgumentConstraintViolatedErrMsg, argName, typeName, fieldName, reason)
+	err.Locations = LocationsFromPosition(position)
+
+	return err
+}
+
+func ErrInvalidListPostProcessingArgument(directiveName, fieldName ast.ByteSlice, reason string, position position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf(InvalidListPostProcessingArgumentErrMsg, directiveName, fieldName, reason)
+	err.Locations = LocationsFromPosition(position)
+
+	return err
+}
+
+func ErrInvalidDataSourceDirective(directiveName, fieldName ast.ByteSlice, reason string, position position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf(InvalidDataSourceDirectiveErrMsg, directiveName, fieldName, reason)
+	err.Locations = LocationsFromPosition(position)
+
+	return err
+}
+
 func ErrNullValueDoesntSatisfyInputValueDefinition(inputType ast.ByteSlice, position position.Position) (err ExternalError) {
 	err.Message = fmt.Sprintf(NullValueErrMsg, inputType)
 	err.Locations = LocationsFromPosition(position)
@@ -337,6 +361,12 @@ func ErrArgumentRequiredOnField(argName, fieldName ast.ByteSlice) (err ExternalE
 	return err
 }
 
+func ErrArgumentRequiredOnDirective(argName, directiveName ast.ByteSlice, position position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf("argument: %s is required on directive: @%s but missing", argName, directiveName)
+	err.Locations = LocationsFromPosition(position)
+	return err
+}
+
 func ErrArgumentOnFieldMustNotBeNull(argName, fieldName ast.ByteSlice) (err ExternalError) {
 	err.Message = fmt.Sprintf("argument: %s on field: %s must not be null", argName, fieldName)
 	return err
@@ -365,6 +395,11 @@ func ErrFragmentUndefined(fragmentName ast.ByteSlice) (err ExternalError) {
 	return err
 }
 
+func ErrExpandedSelectionsLimitExceeded(maxExpandedSelections int) (err ExternalError) {
+	err.Message = fmt.Sprintf("fragments expand to more than %d selections", maxExpandedSelections)
+	return err
+}
+
 func ErrInlineFragmentOnTypeDisallowed(onTypeName ast.ByteSlice) (err ExternalError) {
 	err.Message = fmt.Sprintf("inline fragment on type: %s disallowed", onTypeName)
 	return err
@@ -385,6 +420,12 @@ func ErrFragmentDefinitionMustBeUnique(fragmentName ast.ByteSlice) (err External
 	return err
 }
 
+func ErrFragmentDefinitionConflict(fragmentName ast.ByteSlice, first, conflicting position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf("fragment: %s is defined more than once with different type conditions, directives or selections", fragmentName)
+	err.Locations = LocationsFromPosition(first, conflicting)
+	return err
+}
+
 func ErrDirectiveUndefined(directiveName ast.ByteSlice) (err ExternalError) {
 	err.Message = fmt.Sprintf("directive: %s undefined", directiveName)
 	return err
@@ -492,3 +533,12 @@ func ErrDuplicateFieldsMustBeIdentical(fieldName, parentName, typeOne, typeTwo s
 		"first subgraph: type '%s'\n second subgraph: type '%s'", fieldName, parentName, typeOne, typeTwo)
 	return err
 }
+
+func ErrConnectionSpecViolation(typeName, fieldName, reason string) (err ExternalError) {
+	if fieldName == "" {
+		err.Message = fmt.Sprintf("the type named '%s' violates the connection spec: %s", typeName, reason)
+		return err
+	}
+	err.Message = fmt.Sprintf("the field '%s.%s' violates the connection spec: %s", typeName, fieldName, reason)
+	return err
+}
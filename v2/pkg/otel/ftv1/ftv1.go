@@ -0,0 +1,119 @@
+// Package ftv1 encodes the traces of operations as federated traces, the "ftv1" extension collected from subgraphs by Apollo gateways.
+//
+//	engineConfig.EnableApolloTracing(true)
+//	engineConfig.SetFederatedTraceEncoder(ftv1.Encoder{})
+package ftv1
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+// Field numbers of the Trace message of the Apollo usage reporting protocol, see reports.proto
+const (
+	traceEndTime    protowire.Number = 3
+	traceStartTime  protowire.Number = 4
+	traceDurationNs protowire.Number = 11
+	traceRoot       protowire.Number = 14
+
+	nodeResponseName      protowire.Number = 1
+	nodeStartTime         protowire.Number = 8
+	nodeEndTime           protowire.Number = 9
+	nodeChild             protowire.Number = 12
+	nodeParentType        protowire.Number = 13
+	nodeOriginalFieldName protowire.Number = 14
+
+	timestampSeconds protowire.Number = 1
+	timestampNanos   protowire.Number = 2
+)
+
+// Encoder is a resolve.FederatedTraceEncoder encoding the trace as a Trace message of the Apollo usage reporting protocol
+type Encoder struct{}
+
+func (Encoder) EncodeFederatedTrace(trace resolve.ApolloTrace) ([]byte, error) {
+	root := &node{}
+	for _, resolver := range trace.Resolvers {
+		if len(resolver.Path) == 0 {
+			continue
+		}
+		parent := root
+		for _, element := range resolver.Path[:len(resolver.Path)-1] {
+			parent = parent.child(element)
+		}
+		n := parent.child(resolver.Path[len(resolver.Path)-1])
+		n.fieldName = resolver.FieldName
+		n.parentType = resolver.ParentType
+		n.start = uint64(resolver.StartOffset.Nanoseconds())
+		n.end = uint64((resolver.StartOffset + resolver.Duration).Nanoseconds())
+	}
+
+	var out []byte
+	out = protowire.AppendTag(out, traceEndTime, protowire.BytesType)
+	out = protowire.AppendBytes(out, encodeTimestamp(trace.End))
+	out = protowire.AppendTag(out, traceStartTime, protowire.BytesType)
+	out = protowire.AppendBytes(out, encodeTimestamp(trace.Start))
+	out = protowire.AppendTag(out, traceDurationNs, protowire.VarintType)
+	out = protowire.AppendVarint(out, uint64(trace.End.Sub(trace.Start).Nanoseconds()))
+	out = protowire.AppendTag(out, traceRoot, protowire.BytesType)
+	out = protowire.AppendBytes(out, root.encode(nil))
+	return out, nil
+}
+
+// node is a node of the tree of the response fields of the trace,
+// the nodes of the root fields of fetches carry the offsets of the fetch in nanoseconds
+type node struct {
+	responseName string
+	parentType   string
+	fieldName    string
+	start, end   uint64
+	children     []*node
+}
+
+func (n *node) child(responseName string) *node {
+	for _, child := range n.children {
+		if child.responseName == responseName {
+			return child
+		}
+	}
+	child := &node{responseName: responseName}
+	n.children = append(n.children, child)
+	return child
+}
+
+func (n *node) encode(out []byte) []byte {
+	if n.responseName != "" {
+		out = protowire.AppendTag(out, nodeResponseName, protowire.BytesType)
+		out = protowire.AppendString(out, n.responseName)
+	}
+	if n.fieldName != "" {
+		out = protowire.AppendTag(out, nodeOriginalFieldName, protowire.BytesType)
+		out = protowire.AppendString(out, n.fieldName)
+		out = protowire.AppendTag(out, nodeParentType, protowire.BytesType)
+		out = protowire.AppendString(out, n.parentType)
+		out = protowire.AppendTag(out, nodeStartTime, protowire.VarintType)
+		out = protowire.AppendVarint(out, n.start)
+		out = protowire.AppendTag(out, nodeEndTime, protowire.VarintType)
+		out = protowire.AppendVarint(out, n.end)
+	}
+	for _, child := range n.children {
+		out = protowire.AppendTag(out, nodeChild, protowire.BytesType)
+		out = protowire.AppendBytes(out, child.encode(nil))
+	}
+	return out
+}
+
+// encodeTimestamp encodes the time as a google.protobuf.Timestamp message
+func encodeTimestamp(t time.Time) []byte {
+	var out []byte
+	out = protowire.AppendTag(out, timestampSeconds, protowire.VarintType)
+	out = protowire.AppendVarint(out, uint64(t.Unix()))
+	out = protowire.AppendTag(out, timestampNanos, protowire.VarintType)
+	out = protowire.AppendVarint(out, uint64(t.Nanosecond()))
+	return out
+}
+
+// Interface Guards
+var _ resolve.FederatedTraceEncoder = Encoder{}
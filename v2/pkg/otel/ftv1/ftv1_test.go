@@ -0,0 +1,74 @@
+package ftv1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+// decodeProtobuf decodes a protobuf message into its fields by number, nested messages have to be decoded separately
+func decodeProtobuf(t *testing.T, message []byte) map[protowire.Number][]any {
+	t.Helper()
+	fields := map[protowire.Number][]any{}
+	for len(message) > 0 {
+		number, wireType, n := protowire.ConsumeTag(message)
+		require.GreaterOrEqual(t, n, 0)
+		message = message[n:]
+		switch wireType {
+		case protowire.VarintType:
+			value, n := protowire.ConsumeVarint(message)
+			require.GreaterOrEqual(t, n, 0)
+			fields[number] = append(fields[number], value)
+			message = message[n:]
+		case protowire.BytesType:
+			value, n := protowire.ConsumeBytes(message)
+			require.GreaterOrEqual(t, n, 0)
+			fields[number] = append(fields[number], value)
+			message = message[n:]
+		default:
+			require.Fail(t, "unexpected wire type", wireType)
+		}
+	}
+	return fields
+}
+
+func TestEncoder_EncodeFederatedTrace(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	encoded, err := Encoder{}.EncodeFederatedTrace(resolve.ApolloTrace{
+		Start: start,
+		End:   start.Add(10 * time.Millisecond),
+		Resolvers: []resolve.ApolloTraceResolver{
+			{Path: []string{"users"}, ParentType: "Query", FieldName: "users", StartOffset: time.Millisecond, Duration: 2 * time.Millisecond},
+			{Path: []string{"users", "reviews"}, ParentType: "User", FieldName: "reviews", StartOffset: 4 * time.Millisecond, Duration: 3 * time.Millisecond},
+		},
+	})
+	require.NoError(t, err)
+
+	trace := decodeProtobuf(t, encoded)
+	assert.Equal(t, []any{uint64(10 * time.Millisecond)}, trace[traceDurationNs])
+
+	startTime := decodeProtobuf(t, trace[traceStartTime][0].([]byte))
+	assert.Equal(t, []any{uint64(start.Unix())}, startTime[timestampSeconds])
+	endTime := decodeProtobuf(t, trace[traceEndTime][0].([]byte))
+	assert.Equal(t, []any{uint64(10 * time.Millisecond)}, endTime[timestampNanos])
+
+	root := decodeProtobuf(t, trace[traceRoot][0].([]byte))
+	require.Len(t, root[nodeChild], 1)
+	users := decodeProtobuf(t, root[nodeChild][0].([]byte))
+	assert.Equal(t, []any{[]byte("users")}, users[nodeResponseName])
+	assert.Equal(t, []any{[]byte("Query")}, users[nodeParentType])
+	assert.Equal(t, []any{uint64(time.Millisecond)}, users[nodeStartTime])
+	assert.Equal(t, []any{uint64(3 * time.Millisecond)}, users[nodeEndTime])
+
+	require.Len(t, users[nodeChild], 1)
+	reviews := decodeProtobuf(t, users[nodeChild][0].([]byte))
+	assert.Equal(t, []any{[]byte("reviews")}, reviews[nodeResponseName])
+	assert.Equal(t, []any{[]byte("User")}, reviews[nodeParentType])
+	assert.Equal(t, []any{uint64(4 * time.Millisecond)}, reviews[nodeStartTime])
+	assert.Equal(t, []any{uint64(7 * time.Millisecond)}, reviews[nodeEndTime])
+}
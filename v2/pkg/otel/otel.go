@@ -0,0 +1,147 @@
+// Package otel traces the execution of operations with OpenTelemetry.
+//
+// The Tracer records a span for each phase of the execution of an operation, see graphql.ExecutionPhase,
+// and a client span for each fetch of the resolver with the name of the subgraph, the type of the fetch and the size of the response.
+// The trace context of the fetches is propagated to the upstream requests of datasources using the httpclient package,
+// e.g. the graphql and the rest datasource.
+//
+//	engineConfig.SetOperationTracer(otel.NewTracer(otel.WithTracerProvider(provider)))
+//
+// The package is a separate module, so the engine only depends on OpenTelemetry if the tracer is used.
+package otel
+
+import (
+	"context"
+	"net/http"
+
+	globalotel "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/httpclient"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+// TracerName is the name of the tracer of the spans, which is the instrumentation scope of the spans
+const TracerName = "github.com/wundergraph/graphql-go-tools/v2/pkg/otel"
+
+const (
+	// SpanNameFetch is the name of the spans of fetches, the spans of the phases are named "graphql." followed by the phase, e.g. "graphql.parse"
+	SpanNameFetch = "graphql.fetch"
+
+	AttributeOperationName = attribute.Key("graphql.operation.name")
+	AttributeSubgraphName  = attribute.Key("graphql.subgraph.name")
+	// AttributeFetchType is "single", "entity" or "batch_entity"
+	AttributeFetchType           = attribute.Key("graphql.fetch.type")
+	AttributeFetchRequestSize    = attribute.Key("graphql.fetch.request_size")
+	AttributeFetchResponseSize   = attribute.Key("graphql.fetch.response_size")
+	AttributeFetchRootFieldNames = attribute.Key("graphql.fetch.root_fields")
+)
+
+type Option func(t *Tracer)
+
+// WithTracerProvider creates the spans with the provider instead of the global TracerProvider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(t *Tracer) {
+		t.provider = provider
+	}
+}
+
+// WithPropagator propagates the trace context of the fetches with the propagator instead of the global TextMapPropagator,
+// a nil propagator disables the propagation
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(t *Tracer) {
+		t.propagator = propagator
+	}
+}
+
+// Tracer is a graphql.OperationTracer recording OpenTelemetry spans
+type Tracer struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+	tracer     trace.Tracer
+}
+
+func NewTracer(options ...Option) *Tracer {
+	t := &Tracer{
+		provider:   globalotel.GetTracerProvider(),
+		propagator: globalotel.GetTextMapPropagator(),
+	}
+	for _, option := range options {
+		option(t)
+	}
+	t.tracer = t.provider.Tracer(TracerName)
+	return t
+}
+
+func (t *Tracer) StartPhase(ctx context.Context, phase graphql.ExecutionPhase, operation *graphql.Request) (context.Context, func(err error)) {
+	ctx, span := t.tracer.Start(ctx, "graphql."+string(phase),
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(AttributeOperationName.String(operation.OperationName)),
+	)
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+func (t *Tracer) StartFetch(ctx context.Context, info resolve.FetchTraceInfo) (context.Context, func(stats resolve.FetchStats)) {
+	attributes := []attribute.KeyValue{
+		AttributeFetchType.String(fetchType(info.Kind)),
+	}
+	if info.DataSourceID != "" {
+		attributes = append(attributes, AttributeSubgraphName.String(info.DataSourceID))
+	}
+	if len(info.RootFields) != 0 {
+		rootFields := make([]string, 0, len(info.RootFields))
+		for _, rootField := range info.RootFields {
+			rootFields = append(rootFields, rootField.TypeName+"."+rootField.FieldName)
+		}
+		attributes = append(attributes, AttributeFetchRootFieldNames.StringSlice(rootFields))
+	}
+	ctx, span := t.tracer.Start(ctx, SpanNameFetch,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributes...),
+	)
+
+	if t.propagator != nil {
+		header := make(http.Header)
+		t.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+		if len(header) != 0 {
+			ctx = httpclient.WithRequestHeaders(ctx, header)
+		}
+	}
+
+	return ctx, func(stats resolve.FetchStats) {
+		span.SetAttributes(
+			AttributeFetchRequestSize.Int(stats.RequestBytes),
+			AttributeFetchResponseSize.Int(stats.ResponseBytes),
+		)
+		if stats.Failed {
+			span.SetStatus(codes.Error, "fetch failed")
+		}
+		span.End()
+	}
+}
+
+func fetchType(kind resolve.FetchKind) string {
+	switch kind {
+	case resolve.FetchKindEntity:
+		return "entity"
+	case resolve.FetchKindEntityBatch:
+		return "batch_entity"
+	default:
+		return "single"
+	}
+}
+
+// Interface Guards
+var (
+	_ graphql.OperationTracer = (*Tracer)(nil)
+)
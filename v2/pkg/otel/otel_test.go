@@ -0,0 +1,139 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/rest_datasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+func TestTracer(t *testing.T) {
+	schema, err := graphql.NewSchemaFromString(`
+		type Query { user: User }
+		type User { name: String }
+	`)
+	require.NoError(t, err)
+
+	newEngine := func(t *testing.T, url string, options ...Option) (*graphql.ExecutionEngineV2, *tracetest.SpanRecorder) {
+		recorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		engineConfig := graphql.NewEngineV2Configuration(schema)
+		engineConfig.SetOperationTracer(NewTracer(append([]Option{WithTracerProvider(provider)}, options...)...))
+		engineConfig.SetDataSources([]plan.DataSourceConfiguration{
+			{
+				ID: "users",
+				RootNodes: []plan.TypeField{
+					{TypeName: "Query", FieldNames: []string{"user"}},
+				},
+				ChildNodes: []plan.TypeField{
+					{TypeName: "User", FieldNames: []string{"name"}},
+				},
+				Factory: &rest_datasource.Factory{Client: http.DefaultClient},
+				Custom: rest_datasource.ConfigJSON(rest_datasource.Configuration{
+					Fields: []rest_datasource.FieldConfiguration{
+						{TypeName: "Query", FieldName: "user", URL: url},
+					},
+				}),
+			},
+		})
+		engine, err := graphql.NewExecutionEngineV2(context.Background(), abstractlogger.NoopLogger, engineConfig)
+		require.NoError(t, err)
+		return engine, recorder
+	}
+
+	execute := func(t *testing.T, engine *graphql.ExecutionEngineV2, query string) (string, error) {
+		resultWriter := graphql.NewEngineResultWriter()
+		err := engine.Execute(context.Background(), &graphql.Request{OperationName: "User", Query: query}, &resultWriter)
+		return resultWriter.String(), err
+	}
+
+	spanNames := func(spans []sdktrace.ReadOnlySpan) []string {
+		names := make([]string, 0, len(spans))
+		for _, span := range spans {
+			names = append(names, span.Name())
+		}
+		return names
+	}
+
+	t.Run("should trace the phases and the fetches and propagate the trace context", func(t *testing.T) {
+		var traceparent string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceparent = r.Header.Get("traceparent")
+			_, _ = w.Write([]byte(`{"name":"Jens"}`))
+		}))
+		defer server.Close()
+
+		engine, recorder := newEngine(t, server.URL, WithPropagator(propagation.TraceContext{}))
+		out, err := execute(t, engine, `query User { user { name } }`)
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"user":{"name":"Jens"}}}`, out)
+
+		spans := recorder.Ended()
+		assert.Equal(t, []string{"graphql.parse", "graphql.normalize", "graphql.validate", "graphql.plan", "graphql.fetch", "graphql.resolve"}, spanNames(spans))
+
+		fetch, resolve := spans[4], spans[5]
+		assert.Equal(t, trace.SpanKindClient, fetch.SpanKind())
+		assert.Equal(t, resolve.SpanContext().SpanID(), fetch.Parent().SpanID())
+		attributes := attribute.NewSet(fetch.Attributes()...)
+		requestSize, ok := attributes.Value(AttributeFetchRequestSize)
+		require.True(t, ok)
+		assert.Greater(t, requestSize.AsInt64(), int64(0))
+		assert.Equal(t, attribute.NewSet(
+			AttributeFetchType.String("single"),
+			AttributeSubgraphName.String("users"),
+			AttributeFetchRootFieldNames.StringSlice([]string{"Query.user"}),
+			AttributeFetchRequestSize.Int64(requestSize.AsInt64()),
+			AttributeFetchResponseSize.Int(len(`{"name":"Jens"}`)),
+		), attributes)
+		assert.Equal(t, AttributeOperationName.String("User"), resolve.Attributes()[0])
+
+		// the upstream request is a child of the fetch span
+		assert.Equal(t, "00-"+fetch.SpanContext().TraceID().String()+"-"+fetch.SpanContext().SpanID().String()+"-01", traceparent)
+
+		t.Run("operations with a cached plan skip the validation and the planning", func(t *testing.T) {
+			_, err := execute(t, engine, `query User { user { name } }`)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"graphql.parse", "graphql.normalize", "graphql.fetch", "graphql.resolve"}, spanNames(recorder.Ended()[len(spans):]))
+		})
+	})
+
+	t.Run("should record the error of a phase", func(t *testing.T) {
+		engine, recorder := newEngine(t, "http://localhost")
+		_, err := execute(t, engine, `query User { user { age } }`)
+		require.Error(t, err)
+
+		spans := recorder.Ended()
+		require.Equal(t, []string{"graphql.parse", "graphql.normalize"}, spanNames(spans))
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+		assert.Equal(t, codes.Error, spans[1].Status().Code)
+		assert.Equal(t, err.Error(), spans[1].Status().Description)
+	})
+
+	t.Run("should record failed fetches", func(t *testing.T) {
+		// the requests to a closed server fail
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		engine, recorder := newEngine(t, server.URL, WithPropagator(nil))
+		_, err := execute(t, engine, `query User { user { name } }`)
+		require.NoError(t, err)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 6)
+		assert.Equal(t, codes.Error, spans[4].Status().Code)
+	})
+}
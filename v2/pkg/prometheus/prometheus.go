@@ -0,0 +1,186 @@
+// Package prometheus records the metrics of the execution engine and of the websocket connections with Prometheus.
+//
+// The Metrics register the collectors of the operations by type, the lookups of the plan cache, the durations of the fetches per datasource,
+// the active subscriptions and the messages of the websocket connections.
+//
+//	metrics, err := prometheus.NewMetrics(prometheus.WithRegisterer(registry))
+//	engineConfig.SetMetrics(metrics.EngineMetrics())
+//	websocket.Handle(done, errChan, conn, executorPool, websocket.WithMetrics(metrics.WebSocketMetrics()))
+//
+// The package is a separate module, so the engine only depends on the Prometheus client if the metrics are recorded with it.
+package prometheus
+
+import (
+	promclient "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription/websocket"
+)
+
+// DefaultNamespace is the prefix of the names of the metrics
+const DefaultNamespace = "graphql"
+
+const (
+	LabelOperationType = "operation_type"
+	LabelResult        = "result"
+	LabelDataSourceID  = "datasource_id"
+	LabelFailed        = "failed"
+	LabelDirection     = "direction"
+)
+
+type Option func(m *Metrics)
+
+// WithRegisterer registers the collectors with the registerer instead of the prometheus.DefaultRegisterer
+func WithRegisterer(registerer promclient.Registerer) Option {
+	return func(m *Metrics) {
+		m.registerer = registerer
+	}
+}
+
+// WithNamespace prefixes the names of the metrics with the namespace instead of the DefaultNamespace
+func WithNamespace(namespace string) Option {
+	return func(m *Metrics) {
+		m.namespace = namespace
+	}
+}
+
+// WithFetchDurationBuckets sets the buckets of the histogram of the fetch durations in seconds instead of the prometheus.DefBuckets
+func WithFetchDurationBuckets(buckets []float64) Option {
+	return func(m *Metrics) {
+		m.fetchDurationBuckets = buckets
+	}
+}
+
+// Metrics are the collectors of the metrics of the engine and the websocket connections
+type Metrics struct {
+	registerer           promclient.Registerer
+	namespace            string
+	fetchDurationBuckets []float64
+
+	operations          *promclient.CounterVec
+	planCacheLookups    *promclient.CounterVec
+	fetchDuration       *promclient.HistogramVec
+	activeSubscriptions *promclient.GaugeVec
+	activeTriggers      *promclient.GaugeVec
+	subscriptionUpdates *promclient.CounterVec
+	websocketMessages   *promclient.CounterVec
+	websocketBytes      *promclient.CounterVec
+}
+
+// NewMetrics creates the collectors and registers them, it fails if a collector with the same name is already registered
+func NewMetrics(options ...Option) (*Metrics, error) {
+	m := &Metrics{
+		registerer:           promclient.DefaultRegisterer,
+		namespace:            DefaultNamespace,
+		fetchDurationBuckets: promclient.DefBuckets,
+	}
+	for _, option := range options {
+		option(m)
+	}
+
+	m.operations = promclient.NewCounterVec(promclient.CounterOpts{
+		Namespace: m.namespace,
+		Name:      "operations_total",
+		Help:      "Number of operations by operation type.",
+	}, []string{LabelOperationType})
+	m.planCacheLookups = promclient.NewCounterVec(promclient.CounterOpts{
+		Namespace: m.namespace,
+		Name:      "plan_cache_lookups_total",
+		Help:      "Number of lookups of the plan cache by result, hit or miss.",
+	}, []string{LabelResult})
+	m.fetchDuration = promclient.NewHistogramVec(promclient.HistogramOpts{
+		Namespace: m.namespace,
+		Name:      "fetch_duration_seconds",
+		Help:      "Duration of the fetches by datasource.",
+		Buckets:   m.fetchDurationBuckets,
+	}, []string{LabelDataSourceID, LabelFailed})
+	m.activeSubscriptions = promclient.NewGaugeVec(promclient.GaugeOpts{
+		Namespace: m.namespace,
+		Name:      "active_subscriptions",
+		Help:      "Number of active subscriptions.",
+	}, nil)
+	m.activeTriggers = promclient.NewGaugeVec(promclient.GaugeOpts{
+		Namespace: m.namespace,
+		Name:      "active_subscription_triggers",
+		Help:      "Number of active upstream subscriptions shared by the subscriptions with the same input.",
+	}, nil)
+	m.subscriptionUpdates = promclient.NewCounterVec(promclient.CounterOpts{
+		Namespace: m.namespace,
+		Name:      "subscription_updates_total",
+		Help:      "Number of messages sent to subscriptions.",
+	}, nil)
+	m.websocketMessages = promclient.NewCounterVec(promclient.CounterOpts{
+		Namespace: m.namespace,
+		Name:      "websocket_messages_total",
+		Help:      "Number of websocket messages by direction, received or sent.",
+	}, []string{LabelDirection})
+	m.websocketBytes = promclient.NewCounterVec(promclient.CounterOpts{
+		Namespace: m.namespace,
+		Name:      "websocket_message_bytes_total",
+		Help:      "Size of the websocket messages in bytes by direction, received or sent.",
+	}, []string{LabelDirection})
+
+	for _, collector := range []promclient.Collector{
+		m.operations, m.planCacheLookups, m.fetchDuration, m.activeSubscriptions,
+		m.activeTriggers, m.subscriptionUpdates, m.websocketMessages, m.websocketBytes,
+	} {
+		if err := m.registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// EngineMetrics returns the instruments of the engine, see graphql.EngineV2Configuration.SetMetrics
+func (m *Metrics) EngineMetrics() graphql.EngineMetrics {
+	return graphql.EngineMetrics{
+		Operations:       counter{m.operations},
+		PlanCacheLookups: counter{m.planCacheLookups},
+		Resolver: resolve.Metrics{
+			FetchDuration:       histogram{m.fetchDuration},
+			ActiveSubscriptions: gauge{m.activeSubscriptions},
+			ActiveTriggers:      gauge{m.activeTriggers},
+			SubscriptionUpdates: counter{m.subscriptionUpdates},
+		},
+	}
+}
+
+// WebSocketMetrics returns the instruments of the websocket connections, see websocket.WithMetrics
+func (m *Metrics) WebSocketMetrics() websocket.Metrics {
+	return websocket.Metrics{
+		Messages:     counter{m.websocketMessages},
+		MessageBytes: counter{m.websocketBytes},
+	}
+}
+
+type counter struct {
+	vec *promclient.CounterVec
+}
+
+func (c counter) Add(value float64, labelValues ...string) {
+	c.vec.WithLabelValues(labelValues...).Add(value)
+}
+
+type gauge struct {
+	vec *promclient.GaugeVec
+}
+
+func (g gauge) Add(value float64, labelValues ...string) {
+	g.vec.WithLabelValues(labelValues...).Add(value)
+}
+
+type histogram struct {
+	vec *promclient.HistogramVec
+}
+
+func (h histogram) Observe(value float64, labelValues ...string) {
+	h.vec.WithLabelValues(labelValues...).Observe(value)
+}
+
+// Interface Guards
+var (
+	_ resolve.Counter   = counter{}
+	_ resolve.Gauge     = gauge{}
+	_ resolve.Histogram = histogram{}
+)
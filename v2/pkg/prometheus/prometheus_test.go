@@ -0,0 +1,102 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	promclient "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/rest_datasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription/websocket"
+)
+
+func TestMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"name":"Jens"}`))
+	}))
+	defer server.Close()
+
+	registry := promclient.NewRegistry()
+	metrics, err := NewMetrics(WithRegisterer(registry), WithFetchDurationBuckets([]float64{1}))
+	require.NoError(t, err)
+
+	schema, err := graphql.NewSchemaFromString(`
+		type Query { user: User }
+		type User { name: String }
+	`)
+	require.NoError(t, err)
+	engineConfig := graphql.NewEngineV2Configuration(schema)
+	engineConfig.SetMetrics(metrics.EngineMetrics())
+	engineConfig.SetDataSources([]plan.DataSourceConfiguration{
+		{
+			ID: "users",
+			RootNodes: []plan.TypeField{
+				{TypeName: "Query", FieldNames: []string{"user"}},
+			},
+			ChildNodes: []plan.TypeField{
+				{TypeName: "User", FieldNames: []string{"name"}},
+			},
+			Factory: &rest_datasource.Factory{Client: http.DefaultClient},
+			Custom: rest_datasource.ConfigJSON(rest_datasource.Configuration{
+				Fields: []rest_datasource.FieldConfiguration{
+					{TypeName: "Query", FieldName: "user", URL: server.URL},
+				},
+			}),
+		},
+	})
+	engine, err := graphql.NewExecutionEngineV2(context.Background(), abstractlogger.NoopLogger, engineConfig)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		resultWriter := graphql.NewEngineResultWriter()
+		require.NoError(t, engine.Execute(context.Background(), &graphql.Request{Query: `{ user { name } }`}, &resultWriter))
+	}
+
+	t.Run("should record the operations and the lookups of the plan cache", func(t *testing.T) {
+		assert.Equal(t, float64(2), testutil.ToFloat64(metrics.operations.WithLabelValues("query")))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.planCacheLookups.WithLabelValues(graphql.PlanCacheResultHit)))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.planCacheLookups.WithLabelValues(graphql.PlanCacheResultMiss)))
+	})
+
+	t.Run("should record the durations of the fetches per datasource", func(t *testing.T) {
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		var found bool
+		for _, family := range families {
+			if family.GetName() != "graphql_fetch_duration_seconds" {
+				continue
+			}
+			found = true
+			require.Len(t, family.GetMetric(), 1)
+			metric := family.GetMetric()[0]
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			assert.Equal(t, map[string]string{LabelDataSourceID: "users", LabelFailed: "false"}, labels)
+			assert.Equal(t, uint64(2), metric.GetHistogram().GetSampleCount())
+		}
+		assert.True(t, found)
+	})
+
+	t.Run("should record the websocket messages", func(t *testing.T) {
+		webSocketMetrics := metrics.WebSocketMetrics()
+		webSocketMetrics.Messages.Add(1, websocket.MessageDirectionSent)
+		webSocketMetrics.MessageBytes.Add(25, websocket.MessageDirectionSent)
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.websocketMessages.WithLabelValues(websocket.MessageDirectionSent)))
+		assert.Equal(t, float64(25), testutil.ToFloat64(metrics.websocketBytes.WithLabelValues(websocket.MessageDirectionSent)))
+	})
+
+	t.Run("should fail to register the metrics twice", func(t *testing.T) {
+		_, err := NewMetrics(WithRegisterer(registry))
+		assert.Error(t, err)
+	})
+}
@@ -46,21 +46,28 @@ type ExecutorEngine struct {
 	bufferPool *sync.Pool
 	// subscriptionUpdateInterval is the actual interval on which the server sends subscription updates to the client.
 	subscriptionUpdateInterval time.Duration
+	// subscriptionUpdateTimeout is the deadline for resolving a single event of a subscription.
+	subscriptionUpdateTimeout time.Duration
 }
 
 // StartOperation will start any operation.
 func (e *ExecutorEngine) StartOperation(ctx context.Context, id string, payload []byte, eventHandler EventHandler) error {
 	executor, err := e.executorPool.Get(payload)
 	if err != nil {
+		if errors.Is(err, ErrExecutorV2PoolExhausted) {
+			eventHandler.Emit(EventTypeOnError, id, nil, err)
+		}
 		return err
 	}
 
 	if err = e.handleOnBeforeStart(executor); err != nil {
+		e.putExecutor(executor)
 		eventHandler.Emit(EventTypeOnError, id, nil, err)
 		return &errOnBeforeStartHookFailure{wrappedErr: err}
 	}
 
 	if ctx, err = e.checkForDuplicateSubscriberID(ctx, id, eventHandler); err != nil {
+		e.putExecutor(executor)
 		return err
 	}
 
@@ -94,6 +101,15 @@ func (e *ExecutorEngine) TerminateAllSubscriptions(eventHandler EventHandler) er
 	return nil
 }
 
+// putExecutor returns an executor which was not started to the pool, so pools with a limited size don't run out of executors
+func (e *ExecutorEngine) putExecutor(executor Executor) {
+	if err := e.executorPool.Put(executor); err != nil {
+		e.logger.Error("subscription.Handle.putExecutor()",
+			abstractlogger.Error(err),
+		)
+	}
+}
+
 func (e *ExecutorEngine) handleOnBeforeStart(executor Executor) error {
 	switch e := executor.(type) {
 	case *ExecutorV2:
@@ -131,6 +147,9 @@ func (e *ExecutorEngine) startSubscription(ctx context.Context, id string, execu
 	}()
 
 	executor.SetContext(ctx)
+	if executorV2, ok := executor.(*ExecutorV2); ok {
+		executorV2.subscriptionUpdateTimeout = e.subscriptionUpdateTimeout
+	}
 	buf := e.bufferPool.Get().(*graphql.EngineResultWriter)
 	buf.Reset()
 
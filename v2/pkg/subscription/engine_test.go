@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"net/http/httptest"
 	"runtime"
 	"sync"
 	"testing"
@@ -12,9 +13,11 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/jensneuse/abstractlogger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/testing/subscriptiontesting"
 )
 
 func TestExecutorEngine_StartOperation(t *testing.T) {
@@ -333,6 +336,10 @@ func TestExecutorEngine_StartOperation(t *testing.T) {
 				wg.Done()
 			}).
 			Times(1)
+		// the executor of the rejected operation is put back without being started
+		executorPoolMock.EXPECT().Put(gomock.Eq(executorMockQuery)).
+			Return(nil).
+			Times(1)
 
 		eventHandlerMock := NewMockEventHandler(ctrl)
 		eventHandlerMock.EXPECT().Emit(gomock.Eq(EventTypeOnDuplicatedSubscriberID), gomock.Eq(id), gomock.Nil(), gomock.Any()).
@@ -367,6 +374,61 @@ func TestExecutorEngine_StartOperation(t *testing.T) {
 	})
 }
 
+func TestExecutorEngine_StartOperationWithExhaustedExecutorPool(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	chatServer := httptest.NewServer(subscriptiontesting.ChatGraphQLEndpointHandler())
+	defer chatServer.Close()
+
+	enginePool, _ := setupEngineV2(t, ctx, chatServer.URL)
+	executorPool := NewExecutorV2PoolWithOptions(enginePool.engine, context.Background(), ExecutorV2PoolOptions{MaxSize: 2})
+
+	payload, err := subscriptiontesting.GraphQLRequestForOperation(subscriptiontesting.SubscriptionLiveMessages)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventHandlerMock := NewMockEventHandler(ctrl)
+	eventHandlerMock.EXPECT().Emit(gomock.Eq(EventTypeOnError), gomock.Eq("3"), gomock.Nil(), gomock.Eq(ErrExecutorV2PoolExhausted)).
+		Times(1)
+	eventHandlerMock.EXPECT().Emit(gomock.Eq(EventTypeOnSubscriptionCompleted), gomock.Eq("1"), gomock.Nil(), gomock.Nil()).
+		Times(1)
+	eventHandlerMock.EXPECT().Emit(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		AnyTimes()
+
+	engine := ExecutorEngine{
+		logger:           abstractlogger.Noop{},
+		subCancellations: subscriptionCancellations{},
+		executorPool:     executorPool,
+		bufferPool: &sync.Pool{
+			New: func() interface{} {
+				writer := graphql.NewEngineResultWriterFromBuffer(bytes.NewBuffer(make([]byte, 0, 1024)))
+				return &writer
+			},
+		},
+		subscriptionUpdateInterval: time.Minute,
+	}
+
+	require.NoError(t, engine.StartOperation(ctx, "1", payload, eventHandlerMock))
+	require.NoError(t, engine.StartOperation(ctx, "2", payload, eventHandlerMock))
+
+	// the pool is exhausted, the operation is rejected instead of blocking the read loop of the connection
+	err = engine.StartOperation(ctx, "3", payload, eventHandlerMock)
+	assert.ErrorIs(t, err, ErrExecutorV2PoolExhausted)
+	assert.Equal(t, 2, engine.subCancellations.Len())
+
+	require.NoError(t, engine.StopSubscription("1", eventHandlerMock))
+	assert.Eventually(t, func() bool {
+		return executorPool.Metrics().InUse == 1
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, engine.StartOperation(ctx, "4", payload, eventHandlerMock))
+	assert.Equal(t, 2, executorPool.Metrics().InUse)
+	assert.Equal(t, 2, engine.subCancellations.Len())
+}
+
 func TestExecutorEngine_StopSubscription(t *testing.T) {
 	wg := &sync.WaitGroup{}
 	wg.Add(1)
@@ -0,0 +1,53 @@
+package subscription
+
+import (
+	"errors"
+)
+
+// ErrorCategory classifies errors that end a connection or an operation so that
+// protocols are able to report them with their own close codes and messages.
+type ErrorCategory int
+
+const (
+	ErrorCategoryUnknown ErrorCategory = iota
+	ErrorCategoryAuthFailed
+	ErrorCategoryValidationFailed
+	ErrorCategoryRateLimited
+	ErrorCategoryOriginUnavailable
+)
+
+// CategorizedError is an error with an ErrorCategory attached.
+type CategorizedError struct {
+	Category ErrorCategory
+	Err      error
+}
+
+// NewCategorizedError wraps err with the provided category.
+// It can be returned e.g. from a websocket init func to reject a connection with a protocol specific reason.
+func NewCategorizedError(category ErrorCategory, err error) error {
+	return &CategorizedError{
+		Category: category,
+		Err:      err,
+	}
+}
+
+func (c *CategorizedError) Error() string {
+	if c.Err == nil {
+		return ""
+	}
+	return c.Err.Error()
+}
+
+func (c *CategorizedError) Unwrap() error {
+	return c.Err
+}
+
+// ErrorCategoryOf returns the category of the first CategorizedError in the chain of err.
+// It returns ErrorCategoryUnknown if err is not categorized.
+func ErrorCategoryOf(err error) ErrorCategory {
+	var categorizedErr *CategorizedError
+	if errors.As(err, &categorizedErr) {
+		return categorizedErr.Category
+	}
+	return ErrorCategoryUnknown
+}
@@ -0,0 +1,22 @@
+package subscription
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCategoryOf(t *testing.T) {
+	t.Run("should return unknown for uncategorized errors", func(t *testing.T) {
+		assert.Equal(t, ErrorCategoryUnknown, ErrorCategoryOf(errors.New("error")))
+		assert.Equal(t, ErrorCategoryUnknown, ErrorCategoryOf(nil))
+	})
+
+	t.Run("should return the category of wrapped errors", func(t *testing.T) {
+		err := fmt.Errorf("init failed: %w", NewCategorizedError(ErrorCategoryAuthFailed, errors.New("invalid token")))
+		assert.Equal(t, ErrorCategoryAuthFailed, ErrorCategoryOf(err))
+		assert.Equal(t, "init failed: invalid token", err.Error())
+	})
+}
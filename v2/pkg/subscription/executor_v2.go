@@ -3,30 +3,126 @@ package subscription
 import (
 	"bytes"
 	"context"
+	"errors"
 	"sync"
+	"time"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
 )
 
+// DefaultExecutorV2PoolMaxIdle is the number of idle executors kept for reuse when ExecutorV2PoolOptions.MaxIdle is not set
+const DefaultExecutorV2PoolMaxIdle = 8
+
+var errExecutorNotFromPool = errors.New("executor was not taken from this pool or was already put back")
+
+// ErrExecutorV2PoolExhausted is returned by Get when all executors of the pool are in use
+// and none was put back within ExecutorV2PoolOptions.AcquireTimeout
+var ErrExecutorV2PoolExhausted = errors.New("executor pool exhausted: too many operations in use")
+
+// ExecutorV2PoolOptions controls the sizing of an ExecutorV2Pool
+type ExecutorV2PoolOptions struct {
+	// MaxSize is the maximum number of executors in use at the same time,
+	// Get fails with ErrExecutorV2PoolExhausted when the pool is exhausted. 0 means unlimited
+	MaxSize int
+	// AcquireTimeout is how long Get waits for an executor to be put back when the pool is exhausted.
+	// Get is called by the read loop of the connection, so the wait should be short.
+	// 0 means Get fails right away
+	AcquireTimeout time.Duration
+	// MaxIdle is the maximum number of idle executors kept for reuse, defaults to DefaultExecutorV2PoolMaxIdle
+	MaxIdle int
+	// IdleTimeout trims idle executors which were not reused within the timeout. 0 keeps idle executors
+	IdleTimeout time.Duration
+	// ExecutionOptions are passed to every operation
+	ExecutionOptions []graphql.ExecutionOptionsV2
+	// LifecycleHook is called when executors are acquired from and released to the pool
+	LifecycleHook ExecutorV2LifecycleHook
+}
+
+// ExecutorV2LifecycleHook attaches request scoped resources, e.g. database sessions or loaders, to the operations of an ExecutorV2Pool
+type ExecutorV2LifecycleHook interface {
+	// OnAcquire is called when an executor is acquired for an operation.
+	// The values of the returned context are available in the resolve.Context of the operation,
+	// an error rejects the operation.
+	OnAcquire(reqCtx context.Context, operation *graphql.Request) (context.Context, error)
+	// OnRelease is called with the context returned by OnAcquire when the operation is done and the executor is released
+	OnRelease(ctx context.Context, operation *graphql.Request)
+}
+
+// ExecutorV2PoolMetrics is a snapshot of the state and the counters of an ExecutorV2Pool
+type ExecutorV2PoolMetrics struct {
+	// InUse is the number of executors taken from the pool and not yet put back
+	InUse int
+	// Idle is the number of executors kept for reuse
+	Idle int
+	// Waiting is the number of Get calls waiting for an executor
+	Waiting int
+	// Created is the number of executors created by the pool
+	Created uint64
+	// Reused is the number of Get calls served by an idle executor
+	Reused uint64
+	// Trimmed is the number of idle executors dropped because of MaxIdle or IdleTimeout
+	Trimmed uint64
+	// WaitCount is the number of Get calls which had to wait for an executor
+	WaitCount uint64
+	// WaitDuration is the total time Get calls waited for an executor
+	WaitDuration time.Duration
+}
+
 // ExecutorV2Pool - provides reusable executors
 type ExecutorV2Pool struct {
 	engine               *graphql.ExecutionEngineV2
-	executorPool         *sync.Pool
 	connectionInitReqCtx context.Context // connectionInitReqCtx - holds original request context used to establish websocket connection
+	executionOptions     []graphql.ExecutionOptionsV2
+	lifecycleHook        ExecutorV2LifecycleHook
+	maxIdle              int
+	idleTimeout          time.Duration
+	acquireTimeout       time.Duration
+	// slots limits the executors in use, nil when the size is unlimited
+	slots chan struct{}
+	now   func() time.Time
+
+	mu      sync.Mutex
+	idle    []idleExecutorV2 // idle executors, the most recently used executor is last
+	metrics ExecutorV2PoolMetrics
+}
+
+type idleExecutorV2 struct {
+	executor *ExecutorV2
+	since    time.Time
 }
 
 func NewExecutorV2Pool(engine *graphql.ExecutionEngineV2, connectionInitReqCtx context.Context) *ExecutorV2Pool {
-	return &ExecutorV2Pool{
-		engine: engine,
-		executorPool: &sync.Pool{
-			New: func() interface{} {
-				return &ExecutorV2{}
-			},
-		},
+	return NewExecutorV2PoolWithOptions(engine, connectionInitReqCtx, ExecutorV2PoolOptions{})
+}
+
+// NewExecutorV2PoolWithExecutionOptions creates an ExecutorV2Pool which passes the execution options to every operation
+func NewExecutorV2PoolWithExecutionOptions(engine *graphql.ExecutionEngineV2, connectionInitReqCtx context.Context, options ...graphql.ExecutionOptionsV2) *ExecutorV2Pool {
+	return NewExecutorV2PoolWithOptions(engine, connectionInitReqCtx, ExecutorV2PoolOptions{
+		ExecutionOptions: options,
+	})
+}
+
+// NewExecutorV2PoolWithOptions creates an ExecutorV2Pool sized by the options
+func NewExecutorV2PoolWithOptions(engine *graphql.ExecutionEngineV2, connectionInitReqCtx context.Context, options ExecutorV2PoolOptions) *ExecutorV2Pool {
+	pool := &ExecutorV2Pool{
+		engine:               engine,
 		connectionInitReqCtx: connectionInitReqCtx,
+		executionOptions:     options.ExecutionOptions,
+		lifecycleHook:        options.LifecycleHook,
+		maxIdle:              options.MaxIdle,
+		idleTimeout:          options.IdleTimeout,
+		acquireTimeout:       options.AcquireTimeout,
+		now:                  time.Now,
+	}
+	if pool.maxIdle <= 0 {
+		pool.maxIdle = DefaultExecutorV2PoolMaxIdle
+	}
+	if options.MaxSize > 0 {
+		pool.slots = make(chan struct{}, options.MaxSize)
 	}
+	return pool
 }
 
 func (e *ExecutorV2Pool) Get(payload []byte) (Executor, error) {
@@ -36,35 +132,214 @@ func (e *ExecutorV2Pool) Get(payload []byte) (Executor, error) {
 		return nil, err
 	}
 
-	return &ExecutorV2{
-		engine:    e.engine,
-		operation: &operation,
-		context:   context.Background(),
-		reqCtx:    e.connectionInitReqCtx,
-	}, nil
+	return e.GetForOperation(&operation)
 }
 
+// GetForOperation returns an executor for an operation which was already unmarshalled, e.g. a resolved persisted query
+// when the pool is exhausted it waits up to the acquire timeout for an executor to be put back
+func (e *ExecutorV2Pool) GetForOperation(operation *graphql.Request) (Executor, error) {
+	if err := e.acquire(); err != nil {
+		return nil, err
+	}
+
+	executor := e.take()
+	executor.engine = e.engine
+	executor.operation = operation
+	executor.context = context.Background()
+	executor.reqCtx = e.connectionInitReqCtx
+	executor.executionOptions = e.executionOptions
+
+	if e.lifecycleHook != nil {
+		valuesCtx, err := e.lifecycleHook.OnAcquire(e.connectionInitReqCtx, operation)
+		if err != nil {
+			e.release(executor)
+			return nil, err
+		}
+		executor.valuesCtx = valuesCtx
+	}
+	return executor, nil
+}
+
+// Put resets the executor and keeps it for reuse
+// executors which were not taken from the pool are rejected, so an executor can't be put back twice
 func (e *ExecutorV2Pool) Put(executor Executor) error {
-	executor.Reset()
-	e.executorPool.Put(executor)
+	executorV2, ok := executor.(*ExecutorV2)
+	if !ok || executorV2.pool != e {
+		return errExecutorNotFromPool
+	}
+	if e.lifecycleHook != nil && executorV2.valuesCtx != nil {
+		e.lifecycleHook.OnRelease(executorV2.valuesCtx, executorV2.operation)
+	}
+	e.release(executorV2)
 	return nil
 }
 
+func (e *ExecutorV2Pool) release(executor *ExecutorV2) {
+	executor.pool = nil
+	executor.Reset()
+
+	e.mu.Lock()
+	e.metrics.InUse--
+	now := e.now()
+	e.trimLocked(now)
+	if len(e.idle) < e.maxIdle {
+		e.idle = append(e.idle, idleExecutorV2{executor: executor, since: now})
+	} else {
+		e.metrics.Trimmed++
+	}
+	e.mu.Unlock()
+
+	if e.slots != nil {
+		<-e.slots
+	}
+}
+
+// Trim drops the idle executors which were not reused within the idle timeout
+// idle executors are also trimmed on Get and Put, Trim can be called periodically to release memory of quiet pools
+func (e *ExecutorV2Pool) Trim() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.trimLocked(e.now())
+}
+
+// Metrics returns a snapshot of the metrics of the pool
+func (e *ExecutorV2Pool) Metrics() ExecutorV2PoolMetrics {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	metrics := e.metrics
+	metrics.Idle = len(e.idle)
+	return metrics
+}
+
+func (e *ExecutorV2Pool) acquire() error {
+	if e.slots == nil {
+		return nil
+	}
+	select {
+	case e.slots <- struct{}{}:
+		return nil
+	default:
+	}
+	if e.acquireTimeout <= 0 {
+		return ErrExecutorV2PoolExhausted
+	}
+
+	e.mu.Lock()
+	e.metrics.Waiting++
+	e.metrics.WaitCount++
+	e.mu.Unlock()
+
+	start := e.now()
+	defer func() {
+		e.mu.Lock()
+		e.metrics.Waiting--
+		e.metrics.WaitDuration += e.now().Sub(start)
+		e.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(e.acquireTimeout)
+	defer timer.Stop()
+
+	var done <-chan struct{}
+	if e.connectionInitReqCtx != nil {
+		done = e.connectionInitReqCtx.Done()
+	}
+	select {
+	case e.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return ErrExecutorV2PoolExhausted
+	case <-done:
+		return e.connectionInitReqCtx.Err()
+	}
+}
+
+func (e *ExecutorV2Pool) take() *ExecutorV2 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.metrics.InUse++
+	e.trimLocked(e.now())
+
+	var executor *ExecutorV2
+	if last := len(e.idle) - 1; last >= 0 {
+		executor = e.idle[last].executor
+		e.idle[last] = idleExecutorV2{}
+		e.idle = e.idle[:last]
+		e.metrics.Reused++
+	} else {
+		executor = &ExecutorV2{}
+		e.metrics.Created++
+	}
+	executor.pool = e
+	executor.uses++
+	return executor
+}
+
+// trimLocked drops the idle executors exceeding the idle timeout, the oldest executors are first
+func (e *ExecutorV2Pool) trimLocked(now time.Time) {
+	if e.idleTimeout <= 0 {
+		return
+	}
+	expired := 0
+	for expired < len(e.idle) && now.Sub(e.idle[expired].since) >= e.idleTimeout {
+		expired++
+	}
+	if expired == 0 {
+		return
+	}
+	e.metrics.Trimmed += uint64(expired)
+	remaining := copy(e.idle, e.idle[expired:])
+	for i := remaining; i < len(e.idle); i++ {
+		e.idle[i] = idleExecutorV2{}
+	}
+	e.idle = e.idle[:remaining]
+}
+
 type ExecutorV2 struct {
-	engine    *graphql.ExecutionEngineV2
-	operation *graphql.Request
-	context   context.Context
-	reqCtx    context.Context
+	engine           *graphql.ExecutionEngineV2
+	operation        *graphql.Request
+	context          context.Context
+	reqCtx           context.Context
+	executionOptions []graphql.ExecutionOptionsV2
+	// subscriptionUpdateTimeout is the deadline for resolving a single event of a subscription
+	subscriptionUpdateTimeout time.Duration
+	// valuesCtx is the context returned by the lifecycle hook of the pool, its values are added to the context of the operation
+	valuesCtx context.Context
+	// pool is the pool the executor was taken from, nil while the executor is idle
+	pool *ExecutorV2Pool
+	uses int
 }
 
 func (e *ExecutorV2) Execute(writer resolve.SubscriptionResponseWriter) error {
-	options := make([]graphql.ExecutionOptionsV2, 0)
+	options := make([]graphql.ExecutionOptionsV2, 0, len(e.executionOptions)+2)
 	switch ctx := e.reqCtx.(type) {
 	case *InitialHttpRequestContext:
 		options = append(options, graphql.WithAdditionalHttpHeaders(ctx.Request.Header))
 	}
+	options = append(options, e.executionOptions...)
+	if e.subscriptionUpdateTimeout > 0 {
+		options = append(options, graphql.WithSubscriptionUpdateTimeout(e.subscriptionUpdateTimeout))
+	}
 
-	return e.engine.Execute(e.context, e.operation, writer, options...)
+	ctx := e.context
+	if e.valuesCtx != nil {
+		ctx = &valuesContext{Context: ctx, values: e.valuesCtx}
+	}
+	return e.engine.Execute(ctx, e.operation, writer, options...)
+}
+
+// valuesContext is canceled with the context of the operation but prefers the values of the lifecycle hook context
+type valuesContext struct {
+	context.Context
+	values context.Context
+}
+
+func (v *valuesContext) Value(key any) any {
+	if value := v.values.Value(key); value != nil {
+		return value
+	}
+	return v.Context.Value(key)
 }
 
 func (e *ExecutorV2) OperationType() ast.OperationType {
@@ -80,9 +355,17 @@ func (e *ExecutorV2) SetContext(context context.Context) {
 	e.context = context
 }
 
+// Uses returns how many operations the executor was taken from its pool for
+func (e *ExecutorV2) Uses() int {
+	return e.uses
+}
+
 func (e *ExecutorV2) Reset() {
 	e.engine = nil
 	e.operation = nil
 	e.context = context.Background()
 	e.reqCtx = context.TODO()
+	e.executionOptions = nil
+	e.valuesCtx = nil
+	e.subscriptionUpdateTimeout = 0
 }
@@ -0,0 +1,243 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+func TestExecutorV2Pool(t *testing.T) {
+	operation := &graphql.Request{Query: "{ hello }"}
+
+	t.Run("reuses executors which were put back", func(t *testing.T) {
+		pool := NewExecutorV2Pool(nil, context.Background())
+
+		first, err := pool.GetForOperation(operation)
+		require.NoError(t, err)
+		require.NoError(t, pool.Put(first))
+
+		second, err := pool.GetForOperation(operation)
+		require.NoError(t, err)
+		assert.Same(t, first, second)
+		assert.Equal(t, 2, second.(*ExecutorV2).Uses())
+		assert.Equal(t, operation, second.(*ExecutorV2).operation)
+
+		assert.Equal(t, ExecutorV2PoolMetrics{
+			InUse:   1,
+			Created: 1,
+			Reused:  1,
+		}, pool.Metrics())
+	})
+
+	t.Run("rejects executors which were not taken from the pool", func(t *testing.T) {
+		pool := NewExecutorV2Pool(nil, context.Background())
+		other := NewExecutorV2Pool(nil, context.Background())
+
+		executor, err := other.GetForOperation(operation)
+		require.NoError(t, err)
+		assert.Error(t, pool.Put(executor))
+		assert.Error(t, pool.Put(NewMockExecutor(gomock.NewController(t))))
+
+		require.NoError(t, other.Put(executor))
+		assert.Error(t, other.Put(executor), "executors must not be put back twice")
+		assert.Equal(t, 0, other.Metrics().InUse)
+	})
+
+	t.Run("keeps at most max idle executors", func(t *testing.T) {
+		pool := NewExecutorV2PoolWithOptions(nil, context.Background(), ExecutorV2PoolOptions{MaxIdle: 1})
+
+		first, err := pool.GetForOperation(operation)
+		require.NoError(t, err)
+		second, err := pool.GetForOperation(operation)
+		require.NoError(t, err)
+
+		require.NoError(t, pool.Put(first))
+		require.NoError(t, pool.Put(second))
+
+		metrics := pool.Metrics()
+		assert.Equal(t, 1, metrics.Idle)
+		assert.Equal(t, uint64(1), metrics.Trimmed)
+	})
+
+	t.Run("trims executors exceeding the idle timeout", func(t *testing.T) {
+		now := time.Unix(0, 0)
+		pool := NewExecutorV2PoolWithOptions(nil, context.Background(), ExecutorV2PoolOptions{IdleTimeout: time.Minute})
+		pool.now = func() time.Time { return now }
+
+		executor, err := pool.GetForOperation(operation)
+		require.NoError(t, err)
+		require.NoError(t, pool.Put(executor))
+
+		now = now.Add(30 * time.Second)
+		pool.Trim()
+		assert.Equal(t, 1, pool.Metrics().Idle)
+
+		now = now.Add(30 * time.Second)
+		pool.Trim()
+		metrics := pool.Metrics()
+		assert.Equal(t, 0, metrics.Idle)
+		assert.Equal(t, uint64(1), metrics.Trimmed)
+
+		_, err = pool.GetForOperation(operation)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), pool.Metrics().Created)
+	})
+
+	t.Run("fails when the pool is exhausted", func(t *testing.T) {
+		pool := NewExecutorV2PoolWithOptions(nil, context.Background(), ExecutorV2PoolOptions{MaxSize: 1})
+
+		executor, err := pool.GetForOperation(operation)
+		require.NoError(t, err)
+
+		_, err = pool.GetForOperation(operation)
+		assert.ErrorIs(t, err, ErrExecutorV2PoolExhausted)
+		assert.Equal(t, 1, pool.Metrics().InUse)
+
+		require.NoError(t, pool.Put(executor))
+		_, err = pool.GetForOperation(operation)
+		assert.NoError(t, err)
+	})
+
+	t.Run("fails after the acquire timeout", func(t *testing.T) {
+		pool := NewExecutorV2PoolWithOptions(nil, context.Background(), ExecutorV2PoolOptions{MaxSize: 1, AcquireTimeout: 10 * time.Millisecond})
+
+		_, err := pool.GetForOperation(operation)
+		require.NoError(t, err)
+
+		_, err = pool.GetForOperation(operation)
+		assert.ErrorIs(t, err, ErrExecutorV2PoolExhausted)
+
+		metrics := pool.Metrics()
+		assert.Equal(t, 1, metrics.InUse)
+		assert.Equal(t, 0, metrics.Waiting)
+		assert.Equal(t, uint64(1), metrics.WaitCount)
+	})
+
+	t.Run("waits for executors within the acquire timeout", func(t *testing.T) {
+		pool := NewExecutorV2PoolWithOptions(nil, context.Background(), ExecutorV2PoolOptions{MaxSize: 1, AcquireTimeout: time.Minute})
+
+		executor, err := pool.GetForOperation(operation)
+		require.NoError(t, err)
+
+		var waited Executor
+		wg := sync.WaitGroup{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			waited, err = pool.GetForOperation(operation)
+		}()
+
+		assert.Eventually(t, func() bool {
+			return pool.Metrics().Waiting == 1
+		}, time.Second, time.Millisecond)
+
+		require.NoError(t, pool.Put(executor))
+		wg.Wait()
+		require.NoError(t, err)
+		assert.Same(t, executor, waited)
+
+		metrics := pool.Metrics()
+		assert.Equal(t, 1, metrics.InUse)
+		assert.Equal(t, 0, metrics.Waiting)
+		assert.Equal(t, uint64(1), metrics.WaitCount)
+	})
+
+	t.Run("stops waiting when the connection is closed", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		pool := NewExecutorV2PoolWithOptions(nil, ctx, ExecutorV2PoolOptions{MaxSize: 1, AcquireTimeout: time.Minute})
+
+		_, err := pool.GetForOperation(operation)
+		require.NoError(t, err)
+
+		cancel()
+		_, err = pool.GetForOperation(operation)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, pool.Metrics().InUse)
+	})
+
+	t.Run("calls the lifecycle hook on acquire and release", func(t *testing.T) {
+		hook := &testLifecycleHook{}
+		pool := NewExecutorV2PoolWithOptions(nil, context.Background(), ExecutorV2PoolOptions{LifecycleHook: hook})
+
+		executor, err := pool.GetForOperation(operation)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"acquire"}, hook.calls)
+
+		executorV2 := executor.(*ExecutorV2)
+		operationCtx := context.WithValue(context.Background(), testContextKey("operation"), "operation")
+		ctx := &valuesContext{Context: operationCtx, values: executorV2.valuesCtx}
+		assert.Equal(t, "session", ctx.Value(testContextKey("session")))
+		assert.Equal(t, "operation", ctx.Value(testContextKey("operation")))
+
+		require.NoError(t, pool.Put(executor))
+		assert.Equal(t, []string{"acquire", "release session"}, hook.calls)
+		assert.Nil(t, executorV2.valuesCtx)
+	})
+
+	t.Run("releases the executor when the lifecycle hook rejects the operation", func(t *testing.T) {
+		hook := &testLifecycleHook{err: errors.New("no session available")}
+		pool := NewExecutorV2PoolWithOptions(nil, context.Background(), ExecutorV2PoolOptions{MaxSize: 1, LifecycleHook: hook})
+
+		_, err := pool.GetForOperation(operation)
+		assert.EqualError(t, err, "no session available")
+		_, err = pool.GetForOperation(operation)
+		assert.EqualError(t, err, "no session available")
+
+		metrics := pool.Metrics()
+		assert.Equal(t, 0, metrics.InUse)
+		assert.Equal(t, 0, metrics.Waiting)
+		assert.Equal(t, []string{"acquire", "acquire"}, hook.calls)
+	})
+
+	t.Run("is safe for concurrent use", func(t *testing.T) {
+		pool := NewExecutorV2PoolWithOptions(nil, context.Background(), ExecutorV2PoolOptions{MaxSize: 4, MaxIdle: 2, AcquireTimeout: time.Minute})
+
+		wg := sync.WaitGroup{}
+		for i := 0; i < 16; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					executor, err := pool.GetForOperation(operation)
+					if !assert.NoError(t, err) {
+						return
+					}
+					assert.NoError(t, pool.Put(executor))
+				}
+			}()
+		}
+		wg.Wait()
+
+		metrics := pool.Metrics()
+		assert.Equal(t, 0, metrics.InUse)
+		assert.Equal(t, uint64(1600), metrics.Created+metrics.Reused)
+		assert.LessOrEqual(t, metrics.Idle, 2)
+	})
+}
+
+type testContextKey string
+
+type testLifecycleHook struct {
+	calls []string
+	err   error
+}
+
+func (h *testLifecycleHook) OnAcquire(reqCtx context.Context, _ *graphql.Request) (context.Context, error) {
+	h.calls = append(h.calls, "acquire")
+	if h.err != nil {
+		return nil, h.err
+	}
+	return context.WithValue(reqCtx, testContextKey("session"), "session"), nil
+}
+
+func (h *testLifecycleHook) OnRelease(ctx context.Context, _ *graphql.Request) {
+	h.calls = append(h.calls, "release "+ctx.Value(testContextKey("session")).(string))
+}
@@ -48,6 +48,9 @@ type UniversalProtocolHandlerOptions struct {
 	CustomSubscriptionUpdateInterval time.Duration
 	CustomReadErrorTimeOut           time.Duration
 	CustomEngine                     Engine
+	// SubscriptionUpdateTimeout is the deadline for resolving a single event of a subscription,
+	// 0 resolves events within the lifetime of the subscription
+	SubscriptionUpdateTimeout time.Duration
 }
 
 // UniversalProtocolHandler can handle any protocol by using the Protocol interface.
@@ -116,6 +119,7 @@ func NewUniversalProtocolHandlerWithOptions(client TransportClient, protocol Pro
 			}
 			engine.subscriptionUpdateInterval = subscriptionUpdateInterval
 		}
+		engine.subscriptionUpdateTimeout = options.SubscriptionUpdateTimeout
 		handler.engine = &engine
 	}
 
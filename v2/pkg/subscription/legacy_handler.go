@@ -50,6 +50,8 @@ type Client interface {
 }
 
 // Handler is the actual subscription handler which will keep track on how to handle messages coming from the client.
+// It only speaks the legacy graphql-ws (subscriptions-transport-ws) protocol, use websocket.Handle to negotiate
+// between graphql-ws and graphql-transport-ws by the Sec-WebSocket-Protocol header.
 type Handler struct {
 	logger abstractlogger.Logger
 	// client will hold the subscription client implementation.
@@ -206,6 +208,7 @@ func (h *Handler) handleStart(ctx context.Context, id string, payload []byte) {
 	}
 
 	if err = h.handleOnBeforeStart(executor); err != nil {
+		h.putExecutor(executor)
 		h.handleError(id, graphql.RequestErrorsFromError(err))
 		return
 	}
@@ -213,6 +216,7 @@ func (h *Handler) handleStart(ctx context.Context, id string, payload []byte) {
 	if executor.OperationType() == ast.OperationTypeSubscription {
 		ctx, subsErr := h.subCancellations.AddWithParent(id, ctx)
 		if subsErr != nil {
+			h.putExecutor(executor)
 			h.handleError(id, graphql.RequestErrorsFromError(subsErr))
 			return
 		}
@@ -223,6 +227,15 @@ func (h *Handler) handleStart(ctx context.Context, id string, payload []byte) {
 	go h.handleNonSubscriptionOperation(ctx, id, executor)
 }
 
+// putExecutor returns an executor which was not started to the pool, so pools with a limited size don't run out of executors
+func (h *Handler) putExecutor(executor Executor) {
+	if err := h.executorPool.Put(executor); err != nil {
+		h.logger.Error("subscription.Handler.putExecutor()",
+			abstractlogger.Error(err),
+		)
+	}
+}
+
 func (h *Handler) handleOnBeforeStart(executor Executor) error {
 	switch e := executor.(type) {
 	case *ExecutorV2:
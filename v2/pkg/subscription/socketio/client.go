@@ -0,0 +1,463 @@
+// Package socketio provides a subscription.TransportClient for clients using GraphQL subscriptions over Socket.IO.
+//
+// The client speaks Engine.IO v4 and Socket.IO v5 over an already upgraded websocket connection
+// (/socket.io/?EIO=4&transport=websocket). Long-polling is not supported.
+// GraphQL messages are exchanged as payload of a single Socket.IO event, by default "graphql",
+// e.g. socket.emit("graphql", {"type": "subscribe", "id": "1", "payload": {"query": "subscription { counter }"}}).
+// The messages inside the event are the messages of the used websocket subprotocol,
+// so the client can be used with the protocol handlers of the websocket package:
+//
+//	client, err := socketio.NewClient(logger, conn)
+//	websocket.Handle(done, errChan, conn, executorPool,
+//		websocket.WithCustomClient(client),
+//		websocket.WithProtocol(websocket.ProtocolGraphQLTransportWS),
+//	)
+package socketio
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/jensneuse/abstractlogger"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription/websocket"
+)
+
+const (
+	DefaultEventName    = "graphql"
+	DefaultPingInterval = 25 * time.Second
+	DefaultPingTimeout  = 20 * time.Second
+	// DefaultMaxPayload is the max payload announced to the client, it is not enforced by the server
+	DefaultMaxPayload = 1000000
+
+	// EngineIOVersion is the only supported version of the Engine.IO protocol
+	EngineIOVersion = "4"
+)
+
+// engine.io packet types
+const (
+	engineIOPacketOpen    byte = '0'
+	engineIOPacketClose   byte = '1'
+	engineIOPacketPing    byte = '2'
+	engineIOPacketPong    byte = '3'
+	engineIOPacketMessage byte = '4'
+)
+
+// socket.io packet types, sent inside engine.io message packets
+const (
+	socketIOPacketConnect      byte = '0'
+	socketIOPacketDisconnect   byte = '1'
+	socketIOPacketEvent        byte = '2'
+	socketIOPacketAck          byte = '3'
+	socketIOPacketConnectError byte = '4'
+)
+
+// IsSocketIORequest reports if the request is a Socket.IO websocket upgrade request supported by the Client.
+func IsSocketIORequest(r *http.Request) bool {
+	query := r.URL.Query()
+	return query.Get("EIO") == EngineIOVersion && query.Get("transport") == "websocket"
+}
+
+// ClientOptions can be used to customize the Socket.IO client.
+type ClientOptions struct {
+	// EventName is the name of the event carrying the GraphQL messages, it defaults to DefaultEventName.
+	EventName string
+	// PingInterval is the interval of the engine.io pings sent to the client, it defaults to DefaultPingInterval.
+	PingInterval time.Duration
+	// PingTimeout is the time the client has to answer a ping before the connection is closed, it defaults to DefaultPingTimeout.
+	PingTimeout time.Duration
+}
+
+func (o *ClientOptions) applyDefaults() {
+	if o.EventName == "" {
+		o.EventName = DefaultEventName
+	}
+	if o.PingInterval <= 0 {
+		o.PingInterval = DefaultPingInterval
+	}
+	if o.PingTimeout <= 0 {
+		o.PingTimeout = DefaultPingTimeout
+	}
+}
+
+// Client is an implementation of the subscription client interface for Socket.IO clients.
+type Client struct {
+	logger abstractlogger.Logger
+	// clientConn holds the actual websocket connection to the client.
+	clientConn net.Conn
+	options    ClientOptions
+	sessionID  string
+	// isClosedConnection indicates if the websocket connection is closed.
+	isClosedConnection bool
+	lastPong           time.Time
+	mu                 *sync.RWMutex
+	writeMu            *sync.Mutex
+	done               chan struct{}
+	closeOnce          *sync.Once
+}
+
+// NewClient will create a new Socket.IO subscription client with default options.
+// It sends the engine.io handshake to the client and starts sending pings.
+func NewClient(logger abstractlogger.Logger, clientConn net.Conn) (*Client, error) {
+	return NewClientWithOptions(logger, clientConn, ClientOptions{})
+}
+
+// NewClientWithOptions will create a new Socket.IO subscription client.
+// It sends the engine.io handshake to the client and starts sending pings.
+func NewClientWithOptions(logger abstractlogger.Logger, clientConn net.Conn, options ClientOptions) (*Client, error) {
+	options.applyDefaults()
+
+	sessionID, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		logger:     logger,
+		clientConn: clientConn,
+		options:    options,
+		sessionID:  sessionID,
+		lastPong:   time.Now(),
+		mu:         &sync.RWMutex{},
+		writeMu:    &sync.Mutex{},
+		done:       make(chan struct{}),
+		closeOnce:  &sync.Once{},
+	}
+
+	handshake, err := json.Marshal(struct {
+		SID          string   `json:"sid"`
+		Upgrades     []string `json:"upgrades"`
+		PingInterval int64    `json:"pingInterval"`
+		PingTimeout  int64    `json:"pingTimeout"`
+		MaxPayload   int      `json:"maxPayload"`
+	}{
+		SID:          sessionID,
+		Upgrades:     []string{},
+		PingInterval: options.PingInterval.Milliseconds(),
+		PingTimeout:  options.PingTimeout.Milliseconds(),
+		MaxPayload:   DefaultMaxPayload,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.writePacket(append([]byte{engineIOPacketOpen}, handshake...)); err != nil {
+		return nil, err
+	}
+
+	go c.keepAlive()
+	return c, nil
+}
+
+// ReadBytesFromClient will read the next GraphQL message from the Socket.IO client.
+// Engine.IO and Socket.IO control packets are handled internally, events with other names are ignored.
+func (c *Client) ReadBytesFromClient() ([]byte, error) {
+	for {
+		if !c.IsConnected() {
+			return nil, subscription.ErrTransportClientClosedConnection
+		}
+
+		data, opCode, err := wsutil.ReadClientData(c.clientConn)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrClosedPipe) || errors.Is(err, io.ErrUnexpectedEOF) {
+			c.changeConnectionStateToClosed()
+			return nil, subscription.ErrTransportClientClosedConnection
+		} else if err != nil {
+			if c.isClosedConnectionError(err) {
+				return nil, subscription.ErrTransportClientClosedConnection
+			}
+
+			c.logger.Error("socketio.Client.ReadBytesFromClient: after reading from client",
+				abstractlogger.Error(err),
+				abstractlogger.ByteString("data", data),
+				abstractlogger.Any("opCode", opCode),
+			)
+
+			return nil, err
+		}
+
+		message, ok, err := c.handleEngineIOPacket(data)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return message, nil
+		}
+	}
+}
+
+// WriteBytesToClient will emit the GraphQL message as event to the Socket.IO client.
+func (c *Client) WriteBytesToClient(message []byte) error {
+	if !c.IsConnected() {
+		return subscription.ErrTransportClientClosedConnection
+	}
+
+	eventName, err := json.Marshal(c.options.EventName)
+	if err != nil {
+		return err
+	}
+
+	packet := make([]byte, 0, len(message)+len(eventName)+5)
+	packet = append(packet, engineIOPacketMessage, socketIOPacketEvent, '[')
+	packet = append(packet, eventName...)
+	packet = append(packet, ',')
+	packet = append(packet, message...)
+	packet = append(packet, ']')
+
+	err = c.writePacket(packet)
+	if errors.Is(err, io.ErrClosedPipe) {
+		c.changeConnectionStateToClosed()
+		return subscription.ErrTransportClientClosedConnection
+	} else if err != nil {
+		c.logger.Error("socketio.Client.WriteBytesToClient: after writing to client",
+			abstractlogger.Error(err),
+			abstractlogger.ByteString("message", message),
+		)
+
+		return err
+	}
+
+	return nil
+}
+
+// IsConnected will indicate if the websocket connection is still established.
+func (c *Client) IsConnected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return !c.isClosedConnection
+}
+
+// Disconnect will close the websocket connection.
+func (c *Client) Disconnect() error {
+	c.logger.Debug("socketio.Client.Disconnect: before disconnect",
+		abstractlogger.String("message", "disconnecting client"),
+	)
+	c.changeConnectionStateToClosed()
+	return c.clientConn.Close()
+}
+
+// DisconnectWithReason will send a Socket.IO disconnect packet and close the websocket with the close code and reason.
+// It can only consume websocket.CloseReason or websocket.CompiledCloseReason.
+func (c *Client) DisconnectWithReason(reason interface{}) error {
+	err := c.writePacket([]byte{engineIOPacketMessage, socketIOPacketDisconnect})
+	if err == nil {
+		switch reason := reason.(type) {
+		case websocket.CloseReason:
+			err = c.writeFrame(ws.Frame(reason))
+		case websocket.CompiledCloseReason:
+			err = c.writeCompiledFrame(reason)
+		default:
+			c.logger.Error("socketio.Client.DisconnectWithReason: on reason/frame parsing",
+				abstractlogger.String("message", "unknown reason provided"),
+			)
+			err = c.writeFrame(ws.Frame(websocket.NewCloseReason(4400, "unknown reason")))
+		}
+	}
+
+	if err != nil {
+		c.logger.Error("socketio.Client.DisconnectWithReason: after writing close reason",
+			abstractlogger.Error(err),
+		)
+		return err
+	}
+
+	return c.Disconnect()
+}
+
+// handleEngineIOPacket handles a packet read from the client and returns the GraphQL message if the packet contains one
+func (c *Client) handleEngineIOPacket(packet []byte) (message []byte, ok bool, err error) {
+	if len(packet) == 0 {
+		return nil, false, nil
+	}
+
+	switch packet[0] {
+	case engineIOPacketClose:
+		c.changeConnectionStateToClosed()
+		return nil, false, subscription.ErrTransportClientClosedConnection
+	case engineIOPacketPing:
+		// clients of older engine.io versions send pings, they are answered with the same payload
+		return nil, false, c.writePacket(append([]byte{engineIOPacketPong}, packet[1:]...))
+	case engineIOPacketPong:
+		c.mu.Lock()
+		c.lastPong = time.Now()
+		c.mu.Unlock()
+		return nil, false, nil
+	case engineIOPacketMessage:
+		return c.handleSocketIOPacket(packet[1:])
+	default:
+		return nil, false, nil
+	}
+}
+
+// handleSocketIOPacket handles a Socket.IO packet and returns the GraphQL message if the packet contains one
+// only the main namespace is supported, connections to other namespaces are rejected and their events are ignored
+func (c *Client) handleSocketIOPacket(packet []byte) (message []byte, ok bool, err error) {
+	if len(packet) == 0 {
+		return nil, false, nil
+	}
+
+	packetType := packet[0]
+	namespace, rest := splitNamespace(packet[1:])
+
+	switch packetType {
+	case socketIOPacketConnect:
+		if namespace != "/" {
+			return nil, false, c.writePacket([]byte(fmt.Sprintf(`%c%c%s,{"message":"Invalid namespace"}`, engineIOPacketMessage, socketIOPacketConnectError, namespace)))
+		}
+		return nil, false, c.writePacket([]byte(fmt.Sprintf(`%c%c{"sid":%q}`, engineIOPacketMessage, socketIOPacketConnect, c.sessionID)))
+	case socketIOPacketDisconnect:
+		if namespace != "/" {
+			return nil, false, nil
+		}
+		c.changeConnectionStateToClosed()
+		return nil, false, subscription.ErrTransportClientClosedConnection
+	case socketIOPacketEvent:
+		if namespace != "/" {
+			return nil, false, nil
+		}
+		return c.handleEvent(rest)
+	default:
+		return nil, false, nil
+	}
+}
+
+// handleEvent parses an event with an optional ack id, e.g. 12["graphql",{...}], and acknowledges it
+func (c *Client) handleEvent(event []byte) (message []byte, ok bool, err error) {
+	ackIDLength := 0
+	for ackIDLength < len(event) && event[ackIDLength] >= '0' && event[ackIDLength] <= '9' {
+		ackIDLength++
+	}
+	ackID, event := event[:ackIDLength], event[ackIDLength:]
+
+	var args []json.RawMessage
+	if err = json.Unmarshal(event, &args); err != nil || len(args) < 2 {
+		c.logger.Debug("socketio.Client.handleEvent: on parsing event",
+			abstractlogger.String("message", "ignoring malformed event"),
+			abstractlogger.ByteString("event", event),
+		)
+		return nil, false, nil
+	}
+
+	var eventName string
+	if err = json.Unmarshal(args[0], &eventName); err != nil || eventName != c.options.EventName {
+		return nil, false, nil
+	}
+
+	if len(ackID) > 0 {
+		ack := append([]byte{engineIOPacketMessage, socketIOPacketAck}, ackID...)
+		if err = c.writePacket(append(ack, '[', ']')); err != nil {
+			return nil, false, err
+		}
+	}
+
+	message = args[1]
+	if len(message) > 0 && message[0] == '"' {
+		// some clients emit the messages as JSON encoded strings
+		var encoded string
+		if err = json.Unmarshal(message, &encoded); err != nil {
+			return nil, false, err
+		}
+		message = []byte(encoded)
+	}
+	return message, true, nil
+}
+
+// keepAlive sends engine.io pings to the client and closes the connection if the client stops answering them
+func (c *Client) keepAlive() {
+	ticker := time.NewTicker(c.options.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			c.mu.RLock()
+			lastPong := c.lastPong
+			c.mu.RUnlock()
+
+			if time.Since(lastPong) > c.options.PingInterval+c.options.PingTimeout {
+				c.logger.Debug("socketio.Client.keepAlive: on ping timeout",
+					abstractlogger.String("message", "client did not answer pings"),
+				)
+				_ = c.Disconnect()
+				return
+			}
+
+			if err := c.writePacket([]byte{engineIOPacketPing}); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) writePacket(packet []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return wsutil.WriteServerMessage(c.clientConn, ws.OpText, packet)
+}
+
+func (c *Client) writeFrame(frame ws.Frame) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return ws.WriteFrame(c.clientConn, frame)
+}
+
+func (c *Client) writeCompiledFrame(compiledFrame []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := c.clientConn.Write(compiledFrame)
+	return err
+}
+
+// isClosedConnectionError will indicate if the given error is a connection closed error.
+func (c *Client) isClosedConnectionError(err error) bool {
+	var closedErr wsutil.ClosedError
+	if errors.As(err, &closedErr) {
+		c.changeConnectionStateToClosed()
+	}
+	return !c.IsConnected()
+}
+
+func (c *Client) changeConnectionStateToClosed() {
+	c.mu.Lock()
+	c.isClosedConnection = true
+	c.mu.Unlock()
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+// splitNamespace splits the namespace from a Socket.IO packet, the main namespace "/" is not sent by clients
+func splitNamespace(packet []byte) (namespace string, rest []byte) {
+	if len(packet) == 0 || packet[0] != '/' {
+		return "/", packet
+	}
+	for i := range packet {
+		if packet[i] == ',' {
+			return string(packet[:i]), packet[i+1:]
+		}
+	}
+	return string(packet), nil
+}
+
+func newSessionID() (string, error) {
+	id := make([]byte, 15)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(id), nil
+}
+
+// Interface Guard
+var _ subscription.TransportClient = (*Client)(nil)
@@ -0,0 +1,230 @@
+package socketio
+
+import (
+	"encoding/json"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription/websocket"
+)
+
+// newTestClient creates a client on one end of a pipe and returns the handshake read on the other end
+func newTestClient(t *testing.T, options ClientOptions) (client *Client, connToServer net.Conn, handshake []byte) {
+	t.Helper()
+	connToServer, connToClient := net.Pipe()
+
+	handshakeChan := make(chan []byte)
+	go func() {
+		data, _, err := wsutil.ReadServerData(connToServer)
+		assert.NoError(t, err)
+		handshakeChan <- data
+	}()
+
+	client, err := NewClientWithOptions(abstractlogger.NoopLogger, connToClient, options)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = client.Disconnect()
+	})
+	return client, connToServer, <-handshakeChan
+}
+
+func writeToServer(t *testing.T, conn net.Conn, packet string) {
+	t.Helper()
+	go func() {
+		_ = wsutil.WriteClientMessage(conn, ws.OpText, []byte(packet))
+	}()
+}
+
+func readFromServer(t *testing.T, conn net.Conn) string {
+	t.Helper()
+	data, _, err := wsutil.ReadServerData(conn)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestClient_Handshake(t *testing.T) {
+	client, _, handshake := newTestClient(t, ClientOptions{PingInterval: time.Second, PingTimeout: 2 * time.Second})
+
+	require.Equal(t, byte('0'), handshake[0])
+	var open struct {
+		SID          string   `json:"sid"`
+		Upgrades     []string `json:"upgrades"`
+		PingInterval int      `json:"pingInterval"`
+		PingTimeout  int      `json:"pingTimeout"`
+	}
+	require.NoError(t, json.Unmarshal(handshake[1:], &open))
+	assert.Equal(t, client.sessionID, open.SID)
+	assert.Equal(t, []string{}, open.Upgrades)
+	assert.Equal(t, 1000, open.PingInterval)
+	assert.Equal(t, 2000, open.PingTimeout)
+}
+
+func TestClient_ReadBytesFromClient(t *testing.T) {
+	t.Run("should answer connect and return graphql events", func(t *testing.T) {
+		client, connToServer, _ := newTestClient(t, ClientOptions{})
+
+		messages := make(chan []byte)
+		go func() {
+			message, err := client.ReadBytesFromClient()
+			assert.NoError(t, err)
+			messages <- message
+		}()
+
+		writeToServer(t, connToServer, `40`)
+		assert.Equal(t, `40{"sid":"`+client.sessionID+`"}`, readFromServer(t, connToServer))
+
+		writeToServer(t, connToServer, `42["graphql",{"type":"connection_init"}]`)
+		assert.Equal(t, `{"type":"connection_init"}`, string(<-messages))
+	})
+
+	t.Run("should acknowledge events with ack id", func(t *testing.T) {
+		client, connToServer, _ := newTestClient(t, ClientOptions{})
+
+		messages := make(chan []byte)
+		go func() {
+			message, err := client.ReadBytesFromClient()
+			assert.NoError(t, err)
+			messages <- message
+		}()
+
+		writeToServer(t, connToServer, `4212["graphql",{"type":"connection_init"}]`)
+		assert.Equal(t, `4312[]`, readFromServer(t, connToServer))
+		assert.Equal(t, `{"type":"connection_init"}`, string(<-messages))
+	})
+
+	t.Run("should decode messages sent as json strings", func(t *testing.T) {
+		client, connToServer, _ := newTestClient(t, ClientOptions{})
+
+		messages := make(chan []byte)
+		go func() {
+			message, err := client.ReadBytesFromClient()
+			assert.NoError(t, err)
+			messages <- message
+		}()
+
+		writeToServer(t, connToServer, `42["graphql","{\"type\":\"connection_init\"}"]`)
+		assert.Equal(t, `{"type":"connection_init"}`, string(<-messages))
+	})
+
+	t.Run("should ignore other events and answer pings", func(t *testing.T) {
+		client, connToServer, _ := newTestClient(t, ClientOptions{EventName: "subscriptions"})
+
+		messages := make(chan []byte)
+		go func() {
+			message, err := client.ReadBytesFromClient()
+			assert.NoError(t, err)
+			messages <- message
+		}()
+
+		writeToServer(t, connToServer, `42["graphql",{"type":"ignored"}]`)
+		writeToServer(t, connToServer, `2probe`)
+		assert.Equal(t, `3probe`, readFromServer(t, connToServer))
+
+		writeToServer(t, connToServer, `42["subscriptions",{"type":"connection_init"}]`)
+		assert.Equal(t, `{"type":"connection_init"}`, string(<-messages))
+	})
+
+	t.Run("should reject other namespaces", func(t *testing.T) {
+		client, connToServer, _ := newTestClient(t, ClientOptions{})
+
+		go func() {
+			_, _ = client.ReadBytesFromClient()
+		}()
+
+		writeToServer(t, connToServer, `40/admin,`)
+		assert.Equal(t, `44/admin,{"message":"Invalid namespace"}`, readFromServer(t, connToServer))
+	})
+
+	t.Run("should return closed connection error on disconnect", func(t *testing.T) {
+		client, connToServer, _ := newTestClient(t, ClientOptions{})
+
+		writeToServer(t, connToServer, `41`)
+		_, err := client.ReadBytesFromClient()
+		assert.Equal(t, subscription.ErrTransportClientClosedConnection, err)
+		assert.False(t, client.IsConnected())
+	})
+
+	t.Run("should return closed connection error on engine.io close", func(t *testing.T) {
+		client, connToServer, _ := newTestClient(t, ClientOptions{})
+
+		writeToServer(t, connToServer, `1`)
+		_, err := client.ReadBytesFromClient()
+		assert.Equal(t, subscription.ErrTransportClientClosedConnection, err)
+		assert.False(t, client.IsConnected())
+	})
+}
+
+func TestClient_WriteBytesToClient(t *testing.T) {
+	t.Run("should emit message as event", func(t *testing.T) {
+		client, connToServer, _ := newTestClient(t, ClientOptions{})
+
+		go func() {
+			err := client.WriteBytesToClient([]byte(`{"type":"connection_ack"}`))
+			assert.NoError(t, err)
+		}()
+
+		assert.Equal(t, `42["graphql",{"type":"connection_ack"}]`, readFromServer(t, connToServer))
+	})
+
+	t.Run("should not write to client when connection is closed", func(t *testing.T) {
+		client, _, _ := newTestClient(t, ClientOptions{})
+		require.NoError(t, client.Disconnect())
+
+		err := client.WriteBytesToClient([]byte(`{"type":"connection_ack"}`))
+		assert.Equal(t, subscription.ErrTransportClientClosedConnection, err)
+	})
+}
+
+func TestClient_KeepAlive(t *testing.T) {
+	t.Run("should send pings", func(t *testing.T) {
+		_, connToServer, _ := newTestClient(t, ClientOptions{PingInterval: 10 * time.Millisecond, PingTimeout: time.Second})
+		assert.Equal(t, `2`, readFromServer(t, connToServer))
+	})
+
+	t.Run("should disconnect when pings are not answered", func(t *testing.T) {
+		client, connToServer, _ := newTestClient(t, ClientOptions{PingInterval: 10 * time.Millisecond, PingTimeout: 10 * time.Millisecond})
+		go func() {
+			for {
+				if _, _, err := wsutil.ReadServerData(connToServer); err != nil {
+					return
+				}
+			}
+		}()
+
+		assert.Eventually(t, func() bool {
+			return !client.IsConnected()
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestClient_DisconnectWithReason(t *testing.T) {
+	client, connToServer, _ := newTestClient(t, ClientOptions{})
+
+	go func() {
+		err := client.DisconnectWithReason(websocket.NewCloseReason(4400, "bad request"))
+		assert.NoError(t, err)
+	}()
+
+	assert.Equal(t, `41`, readFromServer(t, connToServer))
+	frame, err := ws.ReadFrame(connToServer)
+	require.NoError(t, err)
+	require.Equal(t, ws.OpClose, frame.Header.OpCode)
+	statusCode, closeReason := ws.ParseCloseFrameData(frame.Payload)
+	assert.Equal(t, ws.StatusCode(4400), statusCode)
+	assert.Equal(t, "bad request", closeReason)
+}
+
+func TestIsSocketIORequest(t *testing.T) {
+	assert.True(t, IsSocketIORequest(httptest.NewRequest("GET", "/socket.io/?EIO=4&transport=websocket", nil)))
+	assert.False(t, IsSocketIORequest(httptest.NewRequest("GET", "/socket.io/?EIO=3&transport=websocket", nil)))
+	assert.False(t, IsSocketIORequest(httptest.NewRequest("GET", "/socket.io/?EIO=4&transport=polling", nil)))
+}
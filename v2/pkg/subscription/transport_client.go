@@ -11,6 +11,19 @@ var ErrTransportClientClosedConnection = errors.New("transport client has a clos
 
 // TransportClient provides an interface that can be implemented by any possible subscription client like websockets, mqtt, etc.
 // It operates with raw byte slices.
+//
+// A TransportClient is used by the UniversalProtocolHandler and a Protocol as follows:
+//   - ReadBytesFromClient is only called from a single goroutine, the read loop of the UniversalProtocolHandler.
+//     It blocks until a complete message was received. Transport specific control messages (e.g. pings) are handled
+//     by the implementation and are never returned.
+//   - WriteBytesToClient is called by the protocol from the read loop and from the goroutines of running subscriptions.
+//     The protocol serializes its writes, but implementations must allow writes to happen concurrently with reads
+//     and with DisconnectWithReason.
+//   - IsConnected must return false once the connection was closed by either side, it is checked before every read.
+//   - Disconnect and DisconnectWithReason may be called more than once and concurrently with reads and writes.
+//
+// The websocket package contains the default implementation based on gobwas/ws. Adapters for other websocket libraries
+// live in the subpackages of the websocket package, the socketio package adapts Socket.IO clients.
 type TransportClient interface {
 	// ReadBytesFromClient will invoke a read operation from the client connection and return a byte slice.
 	// This function should return ErrTransportClientClosedConnection when reading on a closed connection.
@@ -23,5 +36,7 @@ type TransportClient interface {
 	// Disconnect will close the connection between server and client.
 	Disconnect() error
 	// DisconnectWithReason will close the connection but is also able to process a reason for closure.
+	// The type of the reason depends on the protocol, the websocket protocols use websocket.CloseReason
+	// and websocket.CompiledCloseReason.
 	DisconnectWithReason(reason interface{}) error
 }
@@ -1,6 +1,7 @@
 package websocket
 
 import (
+	"bytes"
 	"errors"
 	"io"
 	"net"
@@ -40,6 +41,31 @@ func NewCloseReason(code uint16, reason string) CloseReason {
 	return CloseReason(wsCloseFrame)
 }
 
+// ParseCloseReason returns the close code and message of a CloseReason or CompiledCloseReason.
+// It can be used by transport clients based on other websocket libraries to handle the reasons passed to DisconnectWithReason.
+// ok is false for other reason types and invalid compiled frames.
+func ParseCloseReason(reason interface{}) (code uint16, message string, ok bool) {
+	var frame ws.Frame
+	switch reason := reason.(type) {
+	case CloseReason:
+		frame = ws.Frame(reason)
+	case CompiledCloseReason:
+		var err error
+		frame, err = ws.ReadFrame(bytes.NewReader(reason))
+		if err != nil {
+			return 0, "", false
+		}
+	default:
+		return 0, "", false
+	}
+
+	if frame.Header.OpCode != ws.OpClose {
+		return 0, "", false
+	}
+	statusCode, message := ws.ParseCloseFrameData(frame.Payload)
+	return uint16(statusCode), message, true
+}
+
 // Client is an actual implementation of the subscription client interface.
 type Client struct {
 	logger abstractlogger.Logger
@@ -337,6 +337,7 @@ type TestClient struct {
 	messageToClient   chan []byte
 	isConnected       bool
 	shouldFail        bool
+	closeReason       interface{}
 }
 
 func NewTestClient(shouldFail bool) *TestClient {
@@ -381,9 +382,16 @@ func (t *TestClient) DisconnectWithReason(reason interface{}) error {
 	t.connectionMutex.Lock()
 	defer t.connectionMutex.Unlock()
 	t.isConnected = false
+	t.closeReason = reason
 	return nil
 }
 
+func (t *TestClient) getCloseReason() interface{} {
+	t.connectionMutex.RLock()
+	defer t.connectionMutex.RUnlock()
+	return t.closeReason
+}
+
 func (t *TestClient) readMessageToClient() []byte {
 	return <-t.messageToClient
 }
@@ -459,3 +467,24 @@ func readServerResponse(responseChan chan testServerWebsocketResponse, connToSer
 
 	responseChan <- response
 }
+
+func TestParseCloseReason(t *testing.T) {
+	t.Run("should parse close reason", func(t *testing.T) {
+		code, message, ok := ParseCloseReason(NewCloseReason(4400, "error occurred"))
+		assert.True(t, ok)
+		assert.Equal(t, uint16(4400), code)
+		assert.Equal(t, "error occurred", message)
+	})
+
+	t.Run("should parse compiled close reason", func(t *testing.T) {
+		code, message, ok := ParseCloseReason(CompiledCloseReasonNormal)
+		assert.True(t, ok)
+		assert.Equal(t, uint16(ws.StatusNormalClosure), code)
+		assert.Equal(t, "Normal Closure", message)
+	})
+
+	t.Run("should not parse unknown reason", func(t *testing.T) {
+		_, _, ok := ParseCloseReason("invalid reason")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,39 @@
+package websocket
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription"
+)
+
+// graphQLTransportWSCloseReasons maps error categories to the close reasons of the graphql-transport-ws protocol.
+var graphQLTransportWSCloseReasons = map[subscription.ErrorCategory]CloseReason{
+	subscription.ErrorCategoryAuthFailed:        NewCloseReason(4403, "Forbidden"),
+	subscription.ErrorCategoryValidationFailed:  NewCloseReason(4400, "Bad Request"),
+	subscription.ErrorCategoryRateLimited:       NewCloseReason(4429, "Too Many Requests"),
+	subscription.ErrorCategoryOriginUnavailable: NewCloseReason(1014, "Bad Gateway"),
+}
+
+// graphQLWSConnectionErrorMessages maps error categories to the connection_error messages of the legacy graphql-ws protocol.
+var graphQLWSConnectionErrorMessages = map[subscription.ErrorCategory]string{
+	subscription.ErrorCategoryAuthFailed:        "unauthorized",
+	subscription.ErrorCategoryValidationFailed:  "invalid request",
+	subscription.ErrorCategoryRateLimited:       "too many requests",
+	subscription.ErrorCategoryOriginUnavailable: "origin unavailable",
+}
+
+// graphQLTransportWSCloseReason returns the close reason for err.
+// Uncategorized errors are reported as internal server error.
+func graphQLTransportWSCloseReason(err error) interface{} {
+	if reason, ok := graphQLTransportWSCloseReasons[subscription.ErrorCategoryOf(err)]; ok {
+		return reason
+	}
+	return CompiledCloseReasonInternalServerError
+}
+
+// graphQLWSConnectionErrorMessage returns the connection_error message for err.
+// Uncategorized errors fall back to a generic message so internal details are not leaked to the client.
+func graphQLWSConnectionErrorMessage(err error) string {
+	if message, ok := graphQLWSConnectionErrorMessages[subscription.ErrorCategoryOf(err)]; ok {
+		return message
+	}
+	return "failed to accept the websocket connection"
+}
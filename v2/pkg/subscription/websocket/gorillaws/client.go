@@ -0,0 +1,193 @@
+// Package gorillaws provides a subscription.TransportClient for websocket connections upgraded with gorilla/websocket.
+//
+// The client can be passed to the websocket handler as custom client:
+//
+//	conn, err := upgrader.Upgrade(w, r, nil)
+//	client := gorillaws.NewClient(logger, conn)
+//	websocket.Handle(done, errChan, conn.UnderlyingConn(), executorPool,
+//		websocket.WithCustomClient(client),
+//		websocket.WithProtocol(websocket.Protocol(conn.Subprotocol())),
+//	)
+package gorillaws
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	gorilla "github.com/gorilla/websocket"
+	"github.com/jensneuse/abstractlogger"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription/websocket"
+)
+
+// DefaultCloseWriteTimeout is the time to write the close frame in DisconnectWithReason.
+const DefaultCloseWriteTimeout = time.Second
+
+// ClientOptions can be used to tune the gorilla/websocket connection.
+type ClientOptions struct {
+	// ReadLimit is the maximum size in bytes of a message read from the client, zero means no limit.
+	ReadLimit int64
+	// WriteTimeout is the deadline for writing a message to the client, zero means no deadline.
+	WriteTimeout time.Duration
+	// CloseWriteTimeout is the deadline for writing the close frame, it defaults to DefaultCloseWriteTimeout.
+	CloseWriteTimeout time.Duration
+}
+
+// Client is an implementation of the subscription client interface based on gorilla/websocket.
+type Client struct {
+	logger abstractlogger.Logger
+	// clientConn holds the actual connection to the client.
+	clientConn *gorilla.Conn
+	options    ClientOptions
+	// isClosedConnection indicates if the websocket connection is closed.
+	isClosedConnection bool
+	mu                 *sync.RWMutex
+	// writeMu serializes writes, gorilla/websocket supports only one concurrent writer.
+	writeMu *sync.Mutex
+}
+
+// NewClient will create a new gorilla/websocket subscription client with default options.
+func NewClient(logger abstractlogger.Logger, clientConn *gorilla.Conn) *Client {
+	return NewClientWithOptions(logger, clientConn, ClientOptions{})
+}
+
+// NewClientWithOptions will create a new gorilla/websocket subscription client.
+func NewClientWithOptions(logger abstractlogger.Logger, clientConn *gorilla.Conn, options ClientOptions) *Client {
+	if options.CloseWriteTimeout <= 0 {
+		options.CloseWriteTimeout = DefaultCloseWriteTimeout
+	}
+	if options.ReadLimit > 0 {
+		clientConn.SetReadLimit(options.ReadLimit)
+	}
+
+	return &Client{
+		logger:     logger,
+		clientConn: clientConn,
+		options:    options,
+		mu:         &sync.RWMutex{},
+		writeMu:    &sync.Mutex{},
+	}
+}
+
+// ReadBytesFromClient will read a subscription message from the websocket client.
+func (c *Client) ReadBytesFromClient() ([]byte, error) {
+	if !c.IsConnected() {
+		return nil, subscription.ErrTransportClientClosedConnection
+	}
+
+	messageType, data, err := c.clientConn.ReadMessage()
+	if err != nil {
+		if c.isClosedConnectionError(err) {
+			c.changeConnectionStateToClosed()
+			return nil, subscription.ErrTransportClientClosedConnection
+		}
+
+		c.logger.Error("gorillaws.Client.ReadBytesFromClient: after reading from client",
+			abstractlogger.Error(err),
+			abstractlogger.ByteString("data", data),
+			abstractlogger.Any("messageType", messageType),
+		)
+
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// WriteBytesToClient will write a subscription message to the websocket client.
+func (c *Client) WriteBytesToClient(message []byte) error {
+	if !c.IsConnected() {
+		return subscription.ErrTransportClientClosedConnection
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.options.WriteTimeout > 0 {
+		_ = c.clientConn.SetWriteDeadline(time.Now().Add(c.options.WriteTimeout))
+	}
+	err := c.clientConn.WriteMessage(gorilla.TextMessage, message)
+	if err != nil {
+		if c.isClosedConnectionError(err) {
+			c.changeConnectionStateToClosed()
+			return subscription.ErrTransportClientClosedConnection
+		}
+
+		c.logger.Error("gorillaws.Client.WriteBytesToClient: after writing to client",
+			abstractlogger.Error(err),
+			abstractlogger.ByteString("message", message),
+		)
+
+		return err
+	}
+
+	return nil
+}
+
+// IsConnected will indicate if the websocket connection is still established.
+func (c *Client) IsConnected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return !c.isClosedConnection
+}
+
+// Disconnect will close the websocket connection.
+func (c *Client) Disconnect() error {
+	c.logger.Debug("gorillaws.Client.Disconnect: before disconnect",
+		abstractlogger.String("message", "disconnecting client"),
+	)
+	c.changeConnectionStateToClosed()
+	return c.clientConn.Close()
+}
+
+// DisconnectWithReason will close the websocket and provide the close code and reason.
+// It can only consume websocket.CloseReason or websocket.CompiledCloseReason.
+func (c *Client) DisconnectWithReason(reason interface{}) error {
+	code, message, ok := websocket.ParseCloseReason(reason)
+	if !ok {
+		c.logger.Error("gorillaws.Client.DisconnectWithReason: on reason parsing",
+			abstractlogger.String("message", "unknown reason provided"),
+		)
+		code, message = 4400, "unknown reason"
+	}
+
+	c.logger.Debug("gorillaws.Client.DisconnectWithReason: before sending close frame",
+		abstractlogger.String("message", "disconnecting client"),
+	)
+
+	c.writeMu.Lock()
+	err := c.clientConn.WriteControl(gorilla.CloseMessage, gorilla.FormatCloseMessage(int(code), message), time.Now().Add(c.options.CloseWriteTimeout))
+	c.writeMu.Unlock()
+	if err != nil && !c.isClosedConnectionError(err) {
+		c.logger.Error("gorillaws.Client.DisconnectWithReason: after writing close reason",
+			abstractlogger.Error(err),
+		)
+		return err
+	}
+
+	return c.Disconnect()
+}
+
+// isClosedConnectionError will indicate if the given error is a connection closed error.
+func (c *Client) isClosedConnectionError(err error) bool {
+	var closeErr *gorilla.CloseError
+	return errors.As(err, &closeErr) ||
+		errors.Is(err, gorilla.ErrCloseSent) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.ErrClosedPipe)
+}
+
+func (c *Client) changeConnectionStateToClosed() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.isClosedConnection = true
+}
+
+// Interface Guard
+var _ subscription.TransportClient = (*Client)(nil)
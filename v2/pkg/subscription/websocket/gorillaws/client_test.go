@@ -0,0 +1,97 @@
+package gorillaws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gorilla "github.com/gorilla/websocket"
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription/websocket"
+)
+
+// connectTestClient returns the server side client and the connection of the dialing websocket client
+func connectTestClient(t *testing.T) (client *Client, connToServer *gorilla.Conn) {
+	t.Helper()
+
+	clients := make(chan *Client, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := gorilla.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		clients <- NewClient(abstractlogger.NoopLogger, conn)
+	}))
+	t.Cleanup(server.Close)
+
+	connToServer, _, err := gorilla.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = connToServer.Close()
+	})
+
+	return <-clients, connToServer
+}
+
+func TestClient_ReadBytesFromClient(t *testing.T) {
+	t.Run("should read successfully from client", func(t *testing.T) {
+		client, connToServer := connectTestClient(t)
+
+		require.NoError(t, connToServer.WriteMessage(gorilla.TextMessage, []byte(`{"type":"connection_init"}`)))
+		message, err := client.ReadBytesFromClient()
+		require.NoError(t, err)
+		assert.Equal(t, `{"type":"connection_init"}`, string(message))
+	})
+
+	t.Run("should detect closed connection", func(t *testing.T) {
+		client, connToServer := connectTestClient(t)
+
+		require.NoError(t, connToServer.WriteMessage(gorilla.CloseMessage, gorilla.FormatCloseMessage(gorilla.CloseNormalClosure, "")))
+		_, err := client.ReadBytesFromClient()
+		assert.Equal(t, subscription.ErrTransportClientClosedConnection, err)
+		assert.False(t, client.IsConnected())
+	})
+}
+
+func TestClient_WriteBytesToClient(t *testing.T) {
+	t.Run("should write successfully to client", func(t *testing.T) {
+		client, connToServer := connectTestClient(t)
+
+		require.NoError(t, client.WriteBytesToClient([]byte(`{"type":"connection_ack"}`)))
+		_, message, err := connToServer.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, `{"type":"connection_ack"}`, string(message))
+	})
+
+	t.Run("should not write to client when connection is closed", func(t *testing.T) {
+		client, _ := connectTestClient(t)
+		require.NoError(t, client.Disconnect())
+
+		err := client.WriteBytesToClient([]byte(`{"type":"connection_ack"}`))
+		assert.Equal(t, subscription.ErrTransportClientClosedConnection, err)
+	})
+}
+
+func TestClient_DisconnectWithReason(t *testing.T) {
+	t.Run("should close with close reason", func(t *testing.T) {
+		client, connToServer := connectTestClient(t)
+
+		require.NoError(t, client.DisconnectWithReason(websocket.NewCloseReason(4409, "Subscriber for 1 already exists")))
+		_, _, err := connToServer.ReadMessage()
+		assert.True(t, gorilla.IsCloseError(err, 4409))
+		assert.Contains(t, err.Error(), "Subscriber for 1 already exists")
+		assert.False(t, client.IsConnected())
+	})
+
+	t.Run("should close with compiled close reason", func(t *testing.T) {
+		client, connToServer := connectTestClient(t)
+
+		require.NoError(t, client.DisconnectWithReason(websocket.CompiledCloseReasonNormal))
+		_, _, err := connToServer.ReadMessage()
+		assert.True(t, gorilla.IsCloseError(err, gorilla.CloseNormalClosure))
+	})
+}
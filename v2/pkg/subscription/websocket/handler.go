@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/jensneuse/abstractlogger"
@@ -13,6 +14,7 @@ import (
 
 const (
 	DefaultConnectionInitTimeOut = "15s"
+	DefaultRefreshInterval       = "1m"
 
 	HeaderSecWebSocketProtocol = "Sec-WebSocket-Protocol"
 )
@@ -33,12 +35,19 @@ type HandleOptions struct {
 	Logger                           abstractlogger.Logger
 	Protocol                         Protocol
 	WebSocketInitFunc                InitFunc
+	WebSocketRefreshFunc             RefreshFunc
 	CustomClient                     subscription.TransportClient
 	CustomKeepAliveInterval          time.Duration
 	CustomSubscriptionUpdateInterval time.Duration
 	CustomConnectionInitTimeOut      time.Duration
+	CustomRefreshInterval            time.Duration
 	CustomReadErrorTimeOut           time.Duration
 	CustomSubscriptionEngine         subscription.Engine
+	// SubscriptionUpdateTimeout is the deadline for resolving a single event of a subscription,
+	// 0 resolves events within the lifetime of the subscription
+	SubscriptionUpdateTimeout time.Duration
+	// Metrics records the messages of the connection, if set
+	Metrics *Metrics
 }
 
 // HandleOptionFunc can be used to define option functions.
@@ -58,6 +67,16 @@ func WithInitFunc(initFunc InitFunc) HandleOptionFunc {
 	}
 }
 
+// WithRefreshFunc is a function that sets the refresh function for the websocket handler.
+// The refresh function is called every interval after the connection was initialized. A zero interval uses
+// the DefaultRefreshInterval.
+func WithRefreshFunc(refreshFunc RefreshFunc, interval time.Duration) HandleOptionFunc {
+	return func(opts *HandleOptions) {
+		opts.WebSocketRefreshFunc = refreshFunc
+		opts.CustomRefreshInterval = interval
+	}
+}
+
 // WithCustomClient is a function that set a custom transport client for the websocket handler.
 func WithCustomClient(client subscription.TransportClient) HandleOptionFunc {
 	return func(opts *HandleOptions) {
@@ -102,6 +121,21 @@ func WithCustomSubscriptionEngine(subscriptionEngine subscription.Engine) Handle
 	}
 }
 
+// WithSubscriptionUpdateTimeout is a function that sets the deadline for resolving a single event of a subscription,
+// so a slow fetch triggered by an event can't stall the subscription.
+func WithSubscriptionUpdateTimeout(subscriptionUpdateTimeout time.Duration) HandleOptionFunc {
+	return func(opts *HandleOptions) {
+		opts.SubscriptionUpdateTimeout = subscriptionUpdateTimeout
+	}
+}
+
+// WithMetrics is a function that sets the instruments recording the messages received from and sent to the client.
+func WithMetrics(metrics Metrics) HandleOptionFunc {
+	return func(opts *HandleOptions) {
+		opts.Metrics = &metrics
+	}
+}
+
 // WithProtocol is a function that sets the protocol.
 func WithProtocol(protocol Protocol) HandleOptionFunc {
 	return func(opts *HandleOptions) {
@@ -119,18 +153,38 @@ func WithProtocolFromRequestHeaders(req *http.Request) HandleOptionFunc {
 			return
 		}
 
-		protocolHeaderValue := req.Header.Get(HeaderSecWebSocketProtocol)
-		switch Protocol(protocolHeaderValue) {
-		case ProtocolGraphQLWS:
-			opts.Protocol = ProtocolGraphQLWS
-		case ProtocolGraphQLTransportWS:
-			opts.Protocol = ProtocolGraphQLTransportWS
-		default:
+		opts.Protocol = ProtocolFromRequestHeaders(req.Header)
+		if opts.Protocol == ProtocolUndefined {
 			opts.Protocol = DefaultProtocol
 		}
 	}
 }
 
+// ProtocolFromRequestHeaders returns the first supported protocol offered by the Sec-WebSocket-Protocol headers.
+// Clients can offer multiple protocols in a comma separated list, e.g. "graphql-transport-ws, graphql-ws".
+// It returns ProtocolUndefined if none of the offered protocols is supported.
+func ProtocolFromRequestHeaders(header http.Header) Protocol {
+	for _, value := range header.Values(HeaderSecWebSocketProtocol) {
+		for _, protocol := range strings.Split(value, ",") {
+			if protocol = strings.TrimSpace(protocol); IsSupportedProtocol(protocol) {
+				return Protocol(protocol)
+			}
+		}
+	}
+	return ProtocolUndefined
+}
+
+// IsSupportedProtocol returns true if the protocol is served by Handle.
+// It can be used as ws.HTTPUpgrader.Protocol, so the negotiated protocol is confirmed in the upgrade response.
+func IsSupportedProtocol(protocol string) bool {
+	switch Protocol(protocol) {
+	case ProtocolGraphQLWS, ProtocolGraphQLTransportWS:
+		return true
+	default:
+		return false
+	}
+}
+
 // Handle will handle the websocket subscription. It can take optional option functions to customize the handler.
 // behavior. By default, it uses the 'graphql-transport-ws' protocol.
 func Handle(done chan bool, errChan chan error, conn net.Conn, executorPool subscription.ExecutorPool, options ...HandleOptionFunc) {
@@ -168,6 +222,9 @@ func HandleWithOptions(done chan bool, errChan chan error, conn net.Conn, execut
 	} else {
 		client = NewClient(options.Logger, conn)
 	}
+	if options.Metrics != nil {
+		client = newMeteredClient(client, *options.Metrics)
+	}
 
 	protocolHandler, err := createProtocolHandler(options, client)
 	if err != nil {
@@ -185,6 +242,7 @@ func HandleWithOptions(done chan bool, errChan chan error, conn net.Conn, execut
 		Logger:                           options.Logger,
 		CustomSubscriptionUpdateInterval: options.CustomSubscriptionUpdateInterval,
 		CustomReadErrorTimeOut:           options.CustomReadErrorTimeOut,
+		SubscriptionUpdateTimeout:        options.SubscriptionUpdateTimeout,
 		CustomEngine:                     options.CustomSubscriptionEngine,
 	})
 	if err != nil {
@@ -211,16 +269,21 @@ func createProtocolHandler(handleOptions HandleOptions, client subscription.Tran
 	switch protocol {
 	case ProtocolGraphQLWS:
 		protocolHandler, err = NewProtocolGraphQLWSHandlerWithOptions(client, ProtocolGraphQLWSHandlerOptions{
-			Logger:                  handleOptions.Logger,
-			WebSocketInitFunc:       handleOptions.WebSocketInitFunc,
-			CustomKeepAliveInterval: handleOptions.CustomKeepAliveInterval,
+			Logger:                    handleOptions.Logger,
+			WebSocketInitFunc:         handleOptions.WebSocketInitFunc,
+			WebSocketRefreshFunc:      handleOptions.WebSocketRefreshFunc,
+			CustomKeepAliveInterval:   handleOptions.CustomKeepAliveInterval,
+			CustomInitTimeOutDuration: handleOptions.CustomConnectionInitTimeOut,
+			CustomRefreshInterval:     handleOptions.CustomRefreshInterval,
 		})
 	default:
 		protocolHandler, err = NewProtocolGraphQLTransportWSHandlerWithOptions(client, ProtocolGraphQLTransportWSHandlerOptions{
 			Logger:                    handleOptions.Logger,
 			WebSocketInitFunc:         handleOptions.WebSocketInitFunc,
+			WebSocketRefreshFunc:      handleOptions.WebSocketRefreshFunc,
 			CustomKeepAliveInterval:   handleOptions.CustomKeepAliveInterval,
 			CustomInitTimeOutDuration: handleOptions.CustomConnectionInitTimeOut,
+			CustomRefreshInterval:     handleOptions.CustomRefreshInterval,
 		})
 	}
 
@@ -242,6 +242,7 @@ func TestWithProtocolFromRequestHeaders(t *testing.T) {
 
 	t.Run("should detect graphql-ws", runTest(HeaderSecWebSocketProtocol, "graphql-ws", ProtocolGraphQLWS))
 	t.Run("should detect graphql-transport-ws", runTest(HeaderSecWebSocketProtocol, "graphql-transport-ws", ProtocolGraphQLTransportWS))
+	t.Run("should detect the first supported protocol of a list", runTest(HeaderSecWebSocketProtocol, "something-else, graphql-ws, graphql-transport-ws", ProtocolGraphQLWS))
 	t.Run("should fallback to default protocol", runTest(HeaderSecWebSocketProtocol, "something-else", DefaultProtocol))
 	t.Run("should fallback to default protocol when header is missing", runTest("Different-Header-Key", "missing-header", DefaultProtocol))
 	t.Run("should fallback to default protocol when request is nil", func(t *testing.T) {
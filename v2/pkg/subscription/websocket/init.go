@@ -3,12 +3,20 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"time"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription"
 )
 
 // InitFunc is called when the server receives connection init message from the client.
 // This can be used to check initial payload to see whether to accept the websocket connection.
 type InitFunc func(ctx context.Context, initPayload InitPayload) (context.Context, error)
 
+// RefreshFunc is called periodically on initialized connections with the context returned by the InitFunc
+// and the initial payload. It can be used to re-validate or refresh credentials of long-lived connections, e.g. expiring JWTs.
+// Returning an error closes the connection. Uncategorized errors are treated as subscription.ErrorCategoryAuthFailed.
+type RefreshFunc func(ctx context.Context, initPayload InitPayload) error
+
 // InitPayload is a structure that is parsed from the websocket init message payload.
 type InitPayload json.RawMessage
 
@@ -45,3 +53,27 @@ func (p InitPayload) Authorization() string {
 
 	return ""
 }
+
+// runRefresh calls refreshFunc every interval until ctx is done or refreshFunc returns an error.
+// The error is categorized and passed to onError.
+func runRefresh(ctx context.Context, refreshFunc RefreshFunc, initPayload InitPayload, interval time.Duration, onError func(err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := refreshFunc(ctx, initPayload)
+			if err == nil {
+				continue
+			}
+			if subscription.ErrorCategoryOf(err) == subscription.ErrorCategoryUnknown {
+				err = subscription.NewCategorizedError(subscription.ErrorCategoryAuthFailed, err)
+			}
+			onError(err)
+			return
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package websocket
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription"
+)
+
+const (
+	MessageDirectionReceived = "received"
+	MessageDirectionSent     = "sent"
+)
+
+// Metrics are the instruments recording the throughput of the websocket connections to a metrics backend,
+// instruments which are nil aren't recorded
+type Metrics struct {
+	// Messages counts the messages, labeled with the direction: MessageDirectionReceived or MessageDirectionSent
+	Messages resolve.Counter
+	// MessageBytes counts the bytes of the messages, labeled with the direction
+	MessageBytes resolve.Counter
+}
+
+// meteredClient is a subscription.TransportClient recording the messages read from and written to the client
+type meteredClient struct {
+	subscription.TransportClient
+	metrics Metrics
+}
+
+func newMeteredClient(client subscription.TransportClient, metrics Metrics) *meteredClient {
+	return &meteredClient{
+		TransportClient: client,
+		metrics:         metrics,
+	}
+}
+
+func (m *meteredClient) ReadBytesFromClient() ([]byte, error) {
+	message, err := m.TransportClient.ReadBytesFromClient()
+	if err == nil {
+		m.record(MessageDirectionReceived, message)
+	}
+	return message, err
+}
+
+func (m *meteredClient) WriteBytesToClient(message []byte) error {
+	err := m.TransportClient.WriteBytesToClient(message)
+	if err == nil {
+		m.record(MessageDirectionSent, message)
+	}
+	return err
+}
+
+func (m *meteredClient) record(direction string, message []byte) {
+	if m.metrics.Messages != nil {
+		m.metrics.Messages.Add(1, direction)
+	}
+	if m.metrics.MessageBytes != nil {
+		m.metrics.MessageBytes.Add(float64(len(message)), direction)
+	}
+}
+
+// Interface Guards
+var _ subscription.TransportClient = (*meteredClient)(nil)
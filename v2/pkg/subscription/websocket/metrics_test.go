@@ -0,0 +1,40 @@
+package websocket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCounter map[string]float64
+
+func (c testCounter) Add(value float64, labelValues ...string) {
+	c[labelValues[0]] += value
+}
+
+func TestMeteredClient(t *testing.T) {
+	messages, messageBytes := testCounter{}, testCounter{}
+	testClient := NewTestClient(false)
+	client := newMeteredClient(testClient, Metrics{
+		Messages:     messages,
+		MessageBytes: messageBytes,
+	})
+
+	testClient.writeMessageFromClient([]byte(`{"type":"connection_init"}`))
+	_, err := client.ReadBytesFromClient()
+	require.NoError(t, err)
+	require.NoError(t, client.WriteBytesToClient([]byte(`{"type":"connection_ack"}`)))
+	testClient.readMessageToClient()
+
+	assert.Equal(t, testCounter{MessageDirectionReceived: 1, MessageDirectionSent: 1}, messages)
+	assert.Equal(t, testCounter{MessageDirectionReceived: 26, MessageDirectionSent: 25}, messageBytes)
+
+	t.Run("failed reads and writes are not recorded", func(t *testing.T) {
+		failing := newMeteredClient(NewTestClient(true), Metrics{Messages: messages})
+		_, err := failing.ReadBytesFromClient()
+		assert.Error(t, err)
+		assert.Error(t, failing.WriteBytesToClient([]byte(`{}`)))
+		assert.Equal(t, testCounter{MessageDirectionReceived: 1, MessageDirectionSent: 1}, messages)
+	})
+}
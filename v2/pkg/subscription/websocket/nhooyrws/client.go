@@ -0,0 +1,196 @@
+// Package nhooyrws provides a subscription.TransportClient for websocket connections accepted with nhooyr.io/websocket
+// (continued as github.com/coder/websocket).
+//
+// The client can be passed to the websocket handler as custom client:
+//
+//	conn, err := nhooyr.Accept(w, r, &nhooyr.AcceptOptions{Subprotocols: []string{"graphql-transport-ws", "graphql-ws"}})
+//	client := nhooyrws.NewClient(logger, conn)
+//	websocket.Handle(done, errChan, nhooyr.NetConn(context.Background(), conn, nhooyr.MessageText), executorPool,
+//		websocket.WithCustomClient(client),
+//		websocket.WithProtocol(websocket.Protocol(conn.Subprotocol())),
+//	)
+package nhooyrws
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/jensneuse/abstractlogger"
+	nhooyr "nhooyr.io/websocket"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription/websocket"
+)
+
+// ClientOptions can be used to tune the nhooyr.io/websocket connection.
+type ClientOptions struct {
+	// ReadLimit is the maximum size in bytes of a message read from the client.
+	// Zero keeps the default limit of the library of 32768 bytes.
+	ReadLimit int64
+	// WriteTimeout is the deadline for writing a message to the client, zero means no deadline.
+	WriteTimeout time.Duration
+}
+
+// Client is an implementation of the subscription client interface based on nhooyr.io/websocket.
+type Client struct {
+	logger abstractlogger.Logger
+	// clientConn holds the actual connection to the client.
+	clientConn *nhooyr.Conn
+	options    ClientOptions
+	// ctx is used for all reads and writes, it is canceled on disconnect to abort pending reads.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// isClosedConnection indicates if the websocket connection is closed.
+	isClosedConnection bool
+	mu                 *sync.RWMutex
+	closeOnce          *sync.Once
+}
+
+// NewClient will create a new nhooyr.io/websocket subscription client with default options.
+func NewClient(logger abstractlogger.Logger, clientConn *nhooyr.Conn) *Client {
+	return NewClientWithOptions(logger, clientConn, ClientOptions{})
+}
+
+// NewClientWithOptions will create a new nhooyr.io/websocket subscription client.
+func NewClientWithOptions(logger abstractlogger.Logger, clientConn *nhooyr.Conn, options ClientOptions) *Client {
+	if options.ReadLimit > 0 {
+		clientConn.SetReadLimit(options.ReadLimit)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Client{
+		logger:     logger,
+		clientConn: clientConn,
+		options:    options,
+		ctx:        ctx,
+		cancel:     cancel,
+		mu:         &sync.RWMutex{},
+		closeOnce:  &sync.Once{},
+	}
+}
+
+// ReadBytesFromClient will read a subscription message from the websocket client.
+func (c *Client) ReadBytesFromClient() ([]byte, error) {
+	if !c.IsConnected() {
+		return nil, subscription.ErrTransportClientClosedConnection
+	}
+
+	messageType, data, err := c.clientConn.Read(c.ctx)
+	if err != nil {
+		if c.isClosedConnectionError(err) {
+			c.changeConnectionStateToClosed()
+			return nil, subscription.ErrTransportClientClosedConnection
+		}
+
+		c.logger.Error("nhooyrws.Client.ReadBytesFromClient: after reading from client",
+			abstractlogger.Error(err),
+			abstractlogger.ByteString("data", data),
+			abstractlogger.Any("messageType", messageType),
+		)
+
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// WriteBytesToClient will write a subscription message to the websocket client.
+// nhooyr.io/websocket allows concurrent writers, so writes are not serialized by the client.
+func (c *Client) WriteBytesToClient(message []byte) error {
+	if !c.IsConnected() {
+		return subscription.ErrTransportClientClosedConnection
+	}
+
+	ctx := c.ctx
+	if c.options.WriteTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.options.WriteTimeout)
+		defer cancel()
+	}
+
+	err := c.clientConn.Write(ctx, nhooyr.MessageText, message)
+	if err != nil {
+		if c.isClosedConnectionError(err) {
+			c.changeConnectionStateToClosed()
+			return subscription.ErrTransportClientClosedConnection
+		}
+
+		c.logger.Error("nhooyrws.Client.WriteBytesToClient: after writing to client",
+			abstractlogger.Error(err),
+			abstractlogger.ByteString("message", message),
+		)
+
+		return err
+	}
+
+	return nil
+}
+
+// IsConnected will indicate if the websocket connection is still established.
+func (c *Client) IsConnected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return !c.isClosedConnection
+}
+
+// Disconnect will close the websocket connection with a normal closure.
+func (c *Client) Disconnect() error {
+	c.logger.Debug("nhooyrws.Client.Disconnect: before disconnect",
+		abstractlogger.String("message", "disconnecting client"),
+	)
+	return c.close(nhooyr.StatusNormalClosure, "")
+}
+
+// DisconnectWithReason will close the websocket and provide the close code and reason.
+// It can only consume websocket.CloseReason or websocket.CompiledCloseReason.
+func (c *Client) DisconnectWithReason(reason interface{}) error {
+	code, message, ok := websocket.ParseCloseReason(reason)
+	if !ok {
+		c.logger.Error("nhooyrws.Client.DisconnectWithReason: on reason parsing",
+			abstractlogger.String("message", "unknown reason provided"),
+		)
+		code, message = 4400, "unknown reason"
+	}
+
+	c.logger.Debug("nhooyrws.Client.DisconnectWithReason: before sending close frame",
+		abstractlogger.String("message", "disconnecting client"),
+	)
+	return c.close(nhooyr.StatusCode(code), message)
+}
+
+// close performs the closing handshake once, closing an already closed connection is not an error
+func (c *Client) close(code nhooyr.StatusCode, reason string) (err error) {
+	wasConnected := c.IsConnected()
+	c.changeConnectionStateToClosed()
+	c.closeOnce.Do(func() {
+		err = c.clientConn.Close(code, reason)
+		c.cancel()
+	})
+	if err != nil && wasConnected && !c.isClosedConnectionError(err) {
+		return err
+	}
+	return nil
+}
+
+// isClosedConnectionError will indicate if the given error is a connection closed error.
+func (c *Client) isClosedConnectionError(err error) bool {
+	return nhooyr.CloseStatus(err) != -1 ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.ErrClosedPipe)
+}
+
+func (c *Client) changeConnectionStateToClosed() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.isClosedConnection = true
+}
+
+// Interface Guard
+var _ subscription.TransportClient = (*Client)(nil)
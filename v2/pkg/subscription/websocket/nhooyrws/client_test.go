@@ -0,0 +1,111 @@
+package nhooyrws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	nhooyr "nhooyr.io/websocket"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription/websocket"
+)
+
+// connectTestClient returns the server side client and the connection of the dialing websocket client
+func connectTestClient(t *testing.T) (client *Client, connToServer *nhooyr.Conn) {
+	t.Helper()
+
+	clients := make(chan *Client, 1)
+	handlerDone := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := nhooyr.Accept(w, r, nil)
+		require.NoError(t, err)
+		clients <- NewClient(abstractlogger.NoopLogger, conn)
+		// the connection is hijacked, the handler can return while the test uses the client
+		close(handlerDone)
+	}))
+	t.Cleanup(server.Close)
+
+	connToServer, _, err := nhooyr.Dial(context.Background(), server.URL, nil)
+	require.NoError(t, err)
+
+	<-handlerDone
+	client = <-clients
+	t.Cleanup(func() {
+		// the closing handshake is answered by the pending read of the dialing client
+		go func() {
+			_, _, _ = connToServer.Read(context.Background())
+		}()
+		_ = client.Disconnect()
+	})
+
+	return client, connToServer
+}
+
+func TestClient_ReadBytesFromClient(t *testing.T) {
+	t.Run("should read successfully from client", func(t *testing.T) {
+		client, connToServer := connectTestClient(t)
+
+		require.NoError(t, connToServer.Write(context.Background(), nhooyr.MessageText, []byte(`{"type":"connection_init"}`)))
+		message, err := client.ReadBytesFromClient()
+		require.NoError(t, err)
+		assert.Equal(t, `{"type":"connection_init"}`, string(message))
+	})
+
+	t.Run("should detect closed connection", func(t *testing.T) {
+		client, connToServer := connectTestClient(t)
+
+		go func() {
+			_ = connToServer.Close(nhooyr.StatusNormalClosure, "")
+		}()
+		_, err := client.ReadBytesFromClient()
+		assert.Equal(t, subscription.ErrTransportClientClosedConnection, err)
+		assert.False(t, client.IsConnected())
+	})
+}
+
+func TestClient_WriteBytesToClient(t *testing.T) {
+	t.Run("should write successfully to client", func(t *testing.T) {
+		client, connToServer := connectTestClient(t)
+
+		require.NoError(t, client.WriteBytesToClient([]byte(`{"type":"connection_ack"}`)))
+		_, message, err := connToServer.Read(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, `{"type":"connection_ack"}`, string(message))
+	})
+
+	t.Run("should not write to client when connection is closed", func(t *testing.T) {
+		client, connToServer := connectTestClient(t)
+		go func() {
+			// answer the closing handshake
+			_, _, _ = connToServer.Read(context.Background())
+		}()
+		require.NoError(t, client.Disconnect())
+
+		err := client.WriteBytesToClient([]byte(`{"type":"connection_ack"}`))
+		assert.Equal(t, subscription.ErrTransportClientClosedConnection, err)
+	})
+}
+
+func TestClient_DisconnectWithReason(t *testing.T) {
+	client, connToServer := connectTestClient(t)
+
+	readErr := make(chan error)
+	go func() {
+		_, _, err := connToServer.Read(context.Background())
+		readErr <- err
+	}()
+
+	require.NoError(t, client.DisconnectWithReason(websocket.NewCloseReason(4409, "Subscriber for 1 already exists")))
+	err := <-readErr
+	assert.Equal(t, nhooyr.StatusCode(4409), nhooyr.CloseStatus(err))
+	assert.Contains(t, err.Error(), "Subscriber for 1 already exists")
+	assert.False(t, client.IsConnected())
+
+	// disconnecting again is a no-op
+	assert.NoError(t, client.Disconnect())
+}
@@ -1,6 +1,7 @@
 package websocket
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -190,6 +191,18 @@ func (g *GraphQLTransportWSEventHandler) Emit(eventType subscription.EventType,
 			g.OnConnectionOpened()
 		}
 		return
+	case subscription.EventTypeOnConnectionError:
+		if subscription.ErrorCategoryOf(err) == subscription.ErrorCategoryUnknown {
+			return
+		}
+		if disconnectErr := g.Writer.Client.DisconnectWithReason(graphQLTransportWSCloseReason(err)); disconnectErr != nil {
+			g.logger.Error("websocket.GraphQLTransportWSEventHandler.Emit: on connection error handling",
+				abstractlogger.Error(disconnectErr),
+				abstractlogger.String("id", id),
+				abstractlogger.Error(err),
+			)
+		}
+		return
 	case subscription.EventTypeOnDuplicatedSubscriberID:
 		err = g.Writer.Client.DisconnectWithReason(
 			NewCloseReason(4409, fmt.Sprintf("Subscriber for %s already exists", id)),
@@ -265,8 +278,10 @@ func (g *GraphQLTransportWSEventHandler) HandleWriteEvent(messageType GraphQLTra
 type ProtocolGraphQLTransportWSHandlerOptions struct {
 	Logger                    abstractlogger.Logger
 	WebSocketInitFunc         InitFunc
+	WebSocketRefreshFunc      RefreshFunc
 	CustomKeepAliveInterval   time.Duration
 	CustomInitTimeOutDuration time.Duration
+	CustomRefreshInterval     time.Duration
 }
 
 // ProtocolGraphQLTransportWSHandler is able to handle the graphql-transport-ws protocol.
@@ -282,6 +297,9 @@ type ProtocolGraphQLTransportWSHandler struct {
 	connectionInitTimerStarted    bool
 	connectionInitTimeOutCancel   context.CancelFunc
 	connectionInitTimeOutDuration time.Duration
+	refreshFunc                   RefreshFunc
+	refreshInterval               time.Duration
+	refreshStarted                bool
 }
 
 // NewProtocolGraphQLTransportWSHandler creates a new ProtocolGraphQLTransportWSHandler with default options.
@@ -304,7 +322,8 @@ func NewProtocolGraphQLTransportWSHandlerWithOptions(client subscription.Transpo
 				mu:     &sync.Mutex{},
 			},
 		},
-		initFunc: opts.WebSocketInitFunc,
+		initFunc:    opts.WebSocketInitFunc,
+		refreshFunc: opts.WebSocketRefreshFunc,
 	}
 
 	if opts.Logger != nil {
@@ -334,6 +353,16 @@ func NewProtocolGraphQLTransportWSHandlerWithOptions(client subscription.Transpo
 		protocolHandler.connectionInitTimeOutDuration = timeOutDuration
 	}
 
+	if opts.CustomRefreshInterval != 0 {
+		protocolHandler.refreshInterval = opts.CustomRefreshInterval
+	} else {
+		parsedRefreshInterval, err := time.ParseDuration(DefaultRefreshInterval)
+		if err != nil {
+			return nil, err
+		}
+		protocolHandler.refreshInterval = parsedRefreshInterval
+	}
+
 	// Pass event functions
 	protocolHandler.eventHandler.OnConnectionOpened = protocolHandler.startConnectionInitTimer
 
@@ -367,8 +396,10 @@ func (p *ProtocolGraphQLTransportWSHandler) Handle(ctx context.Context, engine s
 				abstractlogger.Error(err),
 			)
 			p.closeConnectionWithReason(
-				CompiledCloseReasonInternalServerError,
+				graphQLTransportWSCloseReason(err),
 			)
+		} else {
+			p.startRefresh(ctx, message.Payload)
 		}
 		p.startHeartbeat(ctx)
 	case GraphQLTransportWSMessageTypePing:
@@ -471,6 +502,20 @@ func (p *ProtocolGraphQLTransportWSHandler) handleInit(ctx context.Context, payl
 	return initCtx, nil
 }
 
+func (p *ProtocolGraphQLTransportWSHandler) startRefresh(ctx context.Context, initPayload []byte) {
+	if p.refreshFunc == nil || p.refreshStarted {
+		return
+	}
+
+	p.refreshStarted = true
+	go runRefresh(ctx, p.refreshFunc, initPayload, p.refreshInterval, func(err error) {
+		p.logger.Error("websocket.ProtocolGraphQLTransportWSHandler.startRefresh: on refreshing connection",
+			abstractlogger.Error(err),
+		)
+		p.closeConnectionWithReason(graphQLTransportWSCloseReason(err))
+	})
+}
+
 func (p *ProtocolGraphQLTransportWSHandler) handlePing(payload []byte) {
 	// Pong should return the same payload as ping.
 	// https://developer.mozilla.org/en-US/docs/Web/API/WebSockets_API/Writing_WebSocket_servers#pings_and_pongs_the_heartbeat_of_websockets
@@ -485,6 +530,11 @@ func (p *ProtocolGraphQLTransportWSHandler) handleSubscribe(ctx context.Context,
 		return nil
 	}
 
+	if payload := bytes.TrimSpace(message.Payload); len(payload) > 0 && payload[0] == '[' {
+		// batched operations are passed to the engine as they are, the executor pool decides if batches are supported
+		return engine.StartOperation(ctx, message.Id, payload, &p.eventHandler)
+	}
+
 	subscribePayload, err := p.reader.DeserializeSubscribePayload(message)
 	if err != nil {
 		return err
@@ -494,6 +544,7 @@ func (p *ProtocolGraphQLTransportWSHandler) handleSubscribe(ctx context.Context,
 		OperationName: subscribePayload.OperationName,
 		Query:         subscribePayload.Query,
 		Variables:     subscribePayload.Variables,
+		Extensions:    subscribePayload.Extensions,
 	}
 
 	enginePayloadBytes, err := json.Marshal(enginePayload)
@@ -312,6 +312,19 @@ func TestGraphQLTransportWSEventHandler_Emit(t *testing.T) {
 		eventHandler.Emit(subscription.EventTypeOnConnectionOpened, "", nil, nil)
 		assert.Equal(t, counter, 1)
 	})
+	t.Run("should disconnect on categorized connection error", func(t *testing.T) {
+		testClient := NewTestClient(false)
+		eventHandler := NewTestGraphQLTransportWSEventHandler(testClient)
+		eventHandler.Emit(subscription.EventTypeOnConnectionError, "", nil, subscription.NewCategorizedError(subscription.ErrorCategoryRateLimited, errors.New("rate limit exceeded")))
+		assert.False(t, testClient.IsConnected())
+		assert.Equal(t, NewCloseReason(4429, "Too Many Requests"), testClient.getCloseReason())
+	})
+	t.Run("should ignore uncategorized connection error", func(t *testing.T) {
+		testClient := NewTestClient(false)
+		eventHandler := NewTestGraphQLTransportWSEventHandler(testClient)
+		eventHandler.Emit(subscription.EventTypeOnConnectionError, "", nil, subscription.ErrCouldNotReadMessageFromClient)
+		assert.True(t, testClient.IsConnected())
+	})
 	t.Run("should disconnect on duplicated subscriber id", func(t *testing.T) {
 		testClient := NewTestClient(false)
 		eventHandler := NewTestGraphQLTransportWSEventHandler(testClient)
@@ -410,6 +423,68 @@ func TestProtocolGraphQLTransportWSHandler_Handle(t *testing.T) {
 
 		})
 
+		t.Run("should close connection with the close reason of a categorized init error", func(t *testing.T) {
+			testClient := NewTestClient(false)
+			protocol := NewTestProtocolGraphQLTransportWSHandler(testClient)
+			protocol.initFunc = func(ctx context.Context, initPayload InitPayload) (context.Context, error) {
+				return ctx, subscription.NewCategorizedError(subscription.ErrorCategoryAuthFailed, errors.New("invalid token"))
+			}
+
+			ctrl := gomock.NewController(t)
+			mockEngine := NewMockEngine(ctrl)
+
+			ctx, cancelFunc := context.WithCancel(context.Background())
+			defer cancelFunc()
+
+			err := protocol.Handle(ctx, mockEngine, []byte(`{"type":"connection_init","payload":{"Authorization":"token"}}`))
+			assert.NoError(t, err)
+			assert.False(t, testClient.IsConnected())
+			assert.Equal(t, NewCloseReason(4403, "Forbidden"), testClient.getCloseReason())
+		})
+
+		t.Run("should close connection with internal server error on uncategorized init error", func(t *testing.T) {
+			testClient := NewTestClient(false)
+			protocol := NewTestProtocolGraphQLTransportWSHandler(testClient)
+			protocol.initFunc = func(ctx context.Context, initPayload InitPayload) (context.Context, error) {
+				return ctx, errors.New("database password expired")
+			}
+
+			ctrl := gomock.NewController(t)
+			mockEngine := NewMockEngine(ctrl)
+
+			ctx, cancelFunc := context.WithCancel(context.Background())
+			defer cancelFunc()
+
+			err := protocol.Handle(ctx, mockEngine, []byte(`{"type":"connection_init","payload":{"Authorization":"token"}}`))
+			assert.NoError(t, err)
+			assert.False(t, testClient.IsConnected())
+			assert.Equal(t, CompiledCloseReasonInternalServerError, testClient.getCloseReason())
+		})
+
+		t.Run("should close connection when the refresh func fails", func(t *testing.T) {
+			testClient := NewTestClient(false)
+			protocol := NewTestProtocolGraphQLTransportWSHandler(testClient)
+			protocol.refreshInterval = 2 * time.Millisecond
+			protocol.refreshFunc = func(ctx context.Context, initPayload InitPayload) error {
+				assert.Equal(t, InitPayload(`{"Authorization":"token"}`), initPayload)
+				return errors.New("token expired")
+			}
+
+			ctrl := gomock.NewController(t)
+			mockEngine := NewMockEngine(ctrl)
+
+			ctx, cancelFunc := context.WithCancel(context.Background())
+			defer cancelFunc()
+
+			err := protocol.Handle(ctx, mockEngine, []byte(`{"type":"connection_init","payload":{"Authorization":"token"}}`))
+			assert.NoError(t, err)
+			assert.Equal(t, []byte(`{"type":"connection_ack"}`), testClient.readMessageToClient())
+			assert.Eventually(t, func() bool {
+				return !testClient.IsConnected()
+			}, 1*time.Second, 2*time.Millisecond)
+			assert.Equal(t, NewCloseReason(4403, "Forbidden"), testClient.getCloseReason())
+		})
+
 		t.Run("should not time out if connection_init message is sent before time out", func(t *testing.T) {
 			if runtime.GOOS == "windows" {
 				t.Skip("this test fails on Windows due to different timings than unix, consider fixing it at some point")
@@ -161,8 +161,9 @@ func (g *GraphQLWSMessageWriter) write(message *GraphQLWSMessage) error {
 
 // GraphQLWSWriteEventHandler can be used to handle subscription events and forward them to a GraphQLWSMessageWriter.
 type GraphQLWSWriteEventHandler struct {
-	logger abstractlogger.Logger
-	Writer GraphQLWSMessageWriter
+	logger             abstractlogger.Logger
+	Writer             GraphQLWSMessageWriter
+	OnConnectionOpened func()
 }
 
 // Emit is an implementation of subscription.EventHandler. It forwards events to the HandleWriteEvent.
@@ -181,8 +182,16 @@ func (g *GraphQLWSWriteEventHandler) Emit(eventType subscription.EventType, id s
 		messageType = GraphQLWSMessageTypeError
 	case subscription.EventTypeOnDuplicatedSubscriberID:
 		messageType = GraphQLWSMessageTypeError
+	case subscription.EventTypeOnConnectionOpened:
+		if g.OnConnectionOpened != nil {
+			g.OnConnectionOpened()
+		}
+		return
 	case subscription.EventTypeOnConnectionError:
 		messageType = GraphQLWSMessageTypeConnectionError
+		if subscription.ErrorCategoryOf(err) != subscription.ErrorCategoryUnknown {
+			err = errors.New(graphQLWSConnectionErrorMessage(err))
+		}
 	default:
 		return
 	}
@@ -229,9 +238,12 @@ func (g *GraphQLWSWriteEventHandler) HandleWriteEvent(messageType GraphQLWSMessa
 
 // ProtocolGraphQLWSHandlerOptions can be used to provide options to the graphql-ws protocol handler.
 type ProtocolGraphQLWSHandlerOptions struct {
-	Logger                  abstractlogger.Logger
-	WebSocketInitFunc       InitFunc
-	CustomKeepAliveInterval time.Duration
+	Logger                    abstractlogger.Logger
+	WebSocketInitFunc         InitFunc
+	WebSocketRefreshFunc      RefreshFunc
+	CustomKeepAliveInterval   time.Duration
+	CustomInitTimeOutDuration time.Duration
+	CustomRefreshInterval     time.Duration
 }
 
 // ProtocolGraphQLWSHandler is able to handle the graphql-ws protocol.
@@ -241,6 +253,14 @@ type ProtocolGraphQLWSHandler struct {
 	writeEventHandler GraphQLWSWriteEventHandler
 	keepAliveInterval time.Duration
 	initFunc          InitFunc
+	refreshFunc       RefreshFunc
+	refreshInterval   time.Duration
+	refreshStarted    bool
+
+	connectionInitialized         bool
+	connectionInitTimerStarted    bool
+	connectionInitTimeOutCancel   context.CancelFunc
+	connectionInitTimeOutDuration time.Duration
 }
 
 // NewProtocolGraphQLWSHandler creates a new ProtocolGraphQLWSHandler with default options.
@@ -263,7 +283,9 @@ func NewProtocolGraphQLWSHandlerWithOptions(client subscription.TransportClient,
 				mu:     &sync.Mutex{},
 			},
 		},
-		initFunc: opts.WebSocketInitFunc,
+		initFunc:                      opts.WebSocketInitFunc,
+		refreshFunc:                   opts.WebSocketRefreshFunc,
+		connectionInitTimeOutDuration: opts.CustomInitTimeOutDuration,
 	}
 
 	if opts.Logger != nil {
@@ -283,11 +305,30 @@ func NewProtocolGraphQLWSHandlerWithOptions(client subscription.TransportClient,
 		protocolHandler.keepAliveInterval = parsedKeepAliveInterval
 	}
 
+	if opts.CustomRefreshInterval != 0 {
+		protocolHandler.refreshInterval = opts.CustomRefreshInterval
+	} else {
+		parsedRefreshInterval, err := time.ParseDuration(DefaultRefreshInterval)
+		if err != nil {
+			return nil, err
+		}
+		protocolHandler.refreshInterval = parsedRefreshInterval
+	}
+
+	// The connection init time out is opt-in for graphql-ws as the protocol does not define it.
+	if protocolHandler.connectionInitTimeOutDuration != 0 {
+		protocolHandler.writeEventHandler.OnConnectionOpened = protocolHandler.startConnectionInitTimer
+	}
+
 	return protocolHandler, nil
 }
 
 // Handle will handle the actual graphql-ws protocol messages. It's an implementation of subscription.Protocol.
 func (p *ProtocolGraphQLWSHandler) Handle(ctx context.Context, engine subscription.Engine, data []byte) error {
+	if p.connectionInitTimeOutDuration != 0 && !p.connectionInitialized && !p.connectionInitTimerStarted {
+		p.startConnectionInitTimer()
+	}
+
 	message, err := p.reader.Read(data)
 	if err != nil {
 		var jsonSyntaxError *json.SyntaxError
@@ -306,10 +347,11 @@ func (p *ProtocolGraphQLWSHandler) Handle(ctx context.Context, engine subscripti
 	case GraphQLWSMessageTypeConnectionInit:
 		ctx, err = p.handleInit(ctx, message.Payload)
 		if err != nil {
-			p.writeEventHandler.HandleWriteEvent(GraphQLWSMessageTypeConnectionError, "", nil, errors.New("failed to accept the websocket connection"))
+			p.writeEventHandler.HandleWriteEvent(GraphQLWSMessageTypeConnectionError, "", nil, errors.New(graphQLWSConnectionErrorMessage(err)))
 			return engine.TerminateAllSubscriptions(&p.writeEventHandler)
 		}
 
+		p.startRefresh(ctx, engine, message.Payload)
 		go p.handleKeepAlive(ctx)
 	case GraphQLWSMessageTypeStart:
 		return engine.StartOperation(ctx, message.Id, message.Payload, &p.writeEventHandler)
@@ -339,10 +381,69 @@ func (p *ProtocolGraphQLWSHandler) handleInit(ctx context.Context, payload []byt
 		}
 	}
 
+	p.stopConnectionInitTimer()
+	p.connectionInitialized = true
 	p.writeEventHandler.HandleWriteEvent(GraphQLWSMessageTypeConnectionAck, "", nil, nil)
 	return initCtx, nil
 }
 
+func (p *ProtocolGraphQLWSHandler) startConnectionInitTimer() {
+	if p.connectionInitTimerStarted {
+		return
+	}
+
+	timeOutContext, timeOutContextCancel := context.WithCancel(context.Background())
+	p.connectionInitTimeOutCancel = timeOutContextCancel
+	p.connectionInitTimerStarted = true
+	timeOutParams := subscription.TimeOutParams{
+		Name:           "connection init time out",
+		Logger:         p.logger,
+		TimeOutContext: timeOutContext,
+		TimeOutAction: func() {
+			err := p.writeEventHandler.Writer.Client.DisconnectWithReason(
+				NewCloseReason(4408, "Connection initialisation timeout"),
+			)
+			if err != nil {
+				p.logger.Error("websocket.ProtocolGraphQLWSHandler.startConnectionInitTimer: after trying to disconnect on time out",
+					abstractlogger.Error(err),
+				)
+			}
+		},
+		TimeOutDuration: p.connectionInitTimeOutDuration,
+	}
+	go subscription.TimeOutChecker(timeOutParams)
+}
+
+func (p *ProtocolGraphQLWSHandler) stopConnectionInitTimer() {
+	if p.connectionInitTimeOutCancel == nil {
+		return
+	}
+
+	p.connectionInitTimeOutCancel()
+	p.connectionInitTimeOutCancel = nil
+}
+
+// startRefresh periodically calls the refresh func. If it fails, the client receives a connection_error
+// and all subscriptions of the connection are terminated.
+func (p *ProtocolGraphQLWSHandler) startRefresh(ctx context.Context, engine subscription.Engine, initPayload []byte) {
+	if p.refreshFunc == nil || p.refreshStarted {
+		return
+	}
+
+	p.refreshStarted = true
+	go runRefresh(ctx, p.refreshFunc, initPayload, p.refreshInterval, func(err error) {
+		p.logger.Error("websocket.ProtocolGraphQLWSHandler.startRefresh: on refreshing connection",
+			abstractlogger.Error(err),
+		)
+		p.writeEventHandler.HandleWriteEvent(GraphQLWSMessageTypeConnectionError, "", nil, errors.New(graphQLWSConnectionErrorMessage(err)))
+		if err := engine.TerminateAllSubscriptions(&p.writeEventHandler); err != nil {
+			p.logger.Error("websocket.ProtocolGraphQLWSHandler.startRefresh: on terminating subscriptions",
+				abstractlogger.Error(err),
+			)
+		}
+	})
+}
+
 func (p *ProtocolGraphQLWSHandler) handleKeepAlive(ctx context.Context) {
 	for {
 		select {
@@ -369,6 +369,88 @@ func TestProtocolGraphQLWSHandler_Handle(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("should close connection if no connection_init message is sent before time out", func(t *testing.T) {
+		testClient := NewTestClient(false)
+		protocol := NewTestProtocolGraphQLWSHandler(testClient)
+		protocol.connectionInitTimeOutDuration = 2 * time.Millisecond
+		protocol.writeEventHandler.OnConnectionOpened = protocol.startConnectionInitTimer
+
+		protocol.writeEventHandler.Emit(subscription.EventTypeOnConnectionOpened, "", nil, nil)
+		assert.Eventually(t, func() bool {
+			return !testClient.IsConnected()
+		}, 1*time.Second, 2*time.Millisecond)
+		assert.Equal(t, NewCloseReason(4408, "Connection initialisation timeout"), testClient.getCloseReason())
+	})
+
+	t.Run("should send connection_error and terminate subscriptions when the refresh func fails", func(t *testing.T) {
+		testClient := NewTestClient(false)
+		protocol := NewTestProtocolGraphQLWSHandler(testClient)
+		protocol.keepAliveInterval = time.Minute
+		protocol.refreshInterval = 2 * time.Millisecond
+		protocol.refreshFunc = func(ctx context.Context, initPayload InitPayload) error {
+			return errors.New("token expired")
+		}
+
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		defer cancelFunc()
+
+		ctrl := gomock.NewController(t)
+		mockEngine := NewMockEngine(ctrl)
+		terminated := make(chan struct{})
+		mockEngine.EXPECT().TerminateAllSubscriptions(gomock.Eq(protocol.EventHandler())).DoAndReturn(func(eventHandler subscription.EventHandler) error {
+			close(terminated)
+			return nil
+		})
+
+		err := protocol.Handle(ctx, mockEngine, []byte(`{"type":"connection_init","payload":{"Authorization":"token"}}`))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(`{"type":"connection_ack"}`), testClient.readMessageToClient())
+		assert.Equal(t, []byte(`{"type":"connection_error","payload":"unauthorized"}`), testClient.readMessageToClient())
+		<-terminated
+	})
+
+	t.Run("should map categorized init errors to connection_error messages", func(t *testing.T) {
+		testClient := NewTestClient(false)
+		protocol := NewTestProtocolGraphQLWSHandler(testClient)
+		protocol.initFunc = func(ctx context.Context, initPayload InitPayload) (context.Context, error) {
+			return ctx, subscription.NewCategorizedError(subscription.ErrorCategoryAuthFailed, errors.New("invalid token"))
+		}
+
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		defer cancelFunc()
+
+		ctrl := gomock.NewController(t)
+		mockEngine := NewMockEngine(ctrl)
+		mockEngine.EXPECT().TerminateAllSubscriptions(gomock.Eq(protocol.EventHandler()))
+
+		err := protocol.Handle(ctx, mockEngine, []byte(`{"type":"connection_init","payload":{"Authorization":"token"}}`))
+		assert.NoError(t, err)
+
+		expectedMessage := []byte(`{"type":"connection_error","payload":"unauthorized"}`)
+		assert.Equal(t, expectedMessage, testClient.readMessageToClient())
+	})
+
+	t.Run("should not leak uncategorized init errors", func(t *testing.T) {
+		testClient := NewTestClient(false)
+		protocol := NewTestProtocolGraphQLWSHandler(testClient)
+		protocol.initFunc = func(ctx context.Context, initPayload InitPayload) (context.Context, error) {
+			return ctx, errors.New("database password expired")
+		}
+
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		defer cancelFunc()
+
+		ctrl := gomock.NewController(t)
+		mockEngine := NewMockEngine(ctrl)
+		mockEngine.EXPECT().TerminateAllSubscriptions(gomock.Eq(protocol.EventHandler()))
+
+		err := protocol.Handle(ctx, mockEngine, []byte(`{"type":"connection_init","payload":{"Authorization":"token"}}`))
+		assert.NoError(t, err)
+
+		expectedMessage := []byte(`{"type":"connection_error","payload":"failed to accept the websocket connection"}`)
+		assert.Equal(t, expectedMessage, testClient.readMessageToClient())
+	})
+
 	t.Run("should not panic on broken input", func(t *testing.T) {
 		testClient := NewTestClient(false)
 		protocol := NewTestProtocolGraphQLWSHandler(testClient)
@@ -32,7 +32,16 @@ var TestOptions = EndpointOptions{
 
 func GraphQLEndpointHandler(opts EndpointOptions) http.Handler {
 	websocketConnections.Store(0)
-	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: &Resolver{}}))
+	resolver := &Resolver{
+		randomnessEnabled: opts.EnableRandomness,
+		updateInterval:    updateInterval,
+	}
+	if opts.OverrideUpdateInterval > 0 {
+		resolver.updateInterval = opts.OverrideUpdateInterval
+	}
+	resolver.currentPrice.Store(int64(minPrice))
+
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: resolver}))
 
 	srv.AddTransport(transport.POST{})
 	srv.AddTransport(transport.Websocket{
@@ -57,12 +66,6 @@ func GraphQLEndpointHandler(opts EndpointOptions) http.Handler {
 		srv.Use(&debug.Tracer{})
 	}
 
-	randomnessEnabled = opts.EnableRandomness
-
-	if opts.OverrideUpdateInterval > 0 {
-		updateInterval = opts.OverrideUpdateInterval
-	}
-
 	return srv
 }
 
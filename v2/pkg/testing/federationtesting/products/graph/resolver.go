@@ -3,4 +3,15 @@
 // It serves as dependency injection for your app, add any dependencies you require here.
 package graph
 
-type Resolver struct{}
+import (
+	"time"
+
+	"go.uber.org/atomic"
+)
+
+// Resolver holds the state of a single endpoint, so subscriptions of an endpoint don't race with the setup of other endpoints
+type Resolver struct {
+	randomnessEnabled bool
+	updateInterval    time.Duration
+	currentPrice      atomic.Int64
+}
@@ -32,18 +32,18 @@ func (r *subscriptionResolver) UpdatedPrice(ctx context.Context) (<-chan *model.
 			select {
 			case <-ctx.Done():
 				return
-			case <-time.After(updateInterval):
-				product := hats[0]
-				if randomnessEnabled {
-					product = hats[rand.Intn(len(hats)-1)]
+			case <-time.After(r.updateInterval):
+				// the events are copies, so the prices of the hats returned by queries don't change
+				product := *hats[0]
+				if r.randomnessEnabled {
+					product = *hats[rand.Intn(len(hats)-1)]
 					product.Price = rand.Intn(maxPrice-minPrice+1) + minPrice
-					updatedPrice <- product
+					updatedPrice <- &product
 					continue
 				}
 
-				product.Price = currentPrice
-				currentPrice += 1
-				updatedPrice <- product
+				product.Price = int(r.currentPrice.Inc() - 1)
+				updatedPrice <- &product
 			}
 		}
 	}()
@@ -76,8 +76,9 @@ func (r *subscriptionResolver) UpdateProductPrice(ctx context.Context, upc strin
 			case <-ctx.Done():
 				return
 			case <-time.After(100 * time.Millisecond):
-				product.Price = num
-				updatedPrice <- product
+				updated := *product
+				updated.Price = num
+				updatedPrice <- &updated
 			}
 		}
 	}()
@@ -5,9 +5,7 @@ import (
 )
 
 var (
-	randomnessEnabled = true
-	minPrice          = 10
-	maxPrice          = 1499
-	currentPrice      = minPrice
-	updateInterval    = time.Second
+	minPrice       = 10
+	maxPrice       = 1499
+	updateInterval = time.Second
 )
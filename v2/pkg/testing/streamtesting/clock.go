@@ -0,0 +1,89 @@
+package streamtesting
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock schedules the events of a Source
+type Clock interface {
+	Now() time.Time
+	// Until returns a channel which is closed once the clock reached the time
+	Until(t time.Time) <-chan struct{}
+}
+
+// RealClock schedules the events in real time
+type RealClock struct{}
+
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+func (RealClock) Until(t time.Time) <-chan struct{} {
+	reached := make(chan struct{})
+	time.AfterFunc(time.Until(t), func() {
+		close(reached)
+	})
+	return reached
+}
+
+// ManualClock only moves forward when it's advanced,
+// so tests control exactly which events of a timeline are emitted without sleeping
+type ManualClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []manualClockWaiter
+}
+
+type manualClockWaiter struct {
+	at      time.Time
+	reached chan struct{}
+}
+
+func NewManualClock() *ManualClock {
+	return &ManualClock{
+		now: time.Unix(0, 0),
+	}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *ManualClock) Until(t time.Time) <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reached := make(chan struct{})
+	if !t.After(c.now) {
+		close(reached)
+		return reached
+	}
+	c.waiters = append(c.waiters, manualClockWaiter{at: t, reached: reached})
+	return reached
+}
+
+// Advance moves the clock forward and releases all events scheduled up to the new time
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	waiters := c.waiters[:0]
+	for _, waiter := range c.waiters {
+		if waiter.at.After(c.now) {
+			waiters = append(waiters, waiter)
+			continue
+		}
+		close(waiter.reached)
+	}
+	c.waiters = waiters
+}
+
+// Interface Guards
+var (
+	_ Clock = RealClock{}
+	_ Clock = (*ManualClock)(nil)
+)
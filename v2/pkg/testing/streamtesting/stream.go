@@ -0,0 +1,129 @@
+// Package streamtesting provides a subscription datasource emitting a scripted timeline of events,
+// so the subscription handling of the resolver can be tested without real upstreams or sleeps:
+//
+//	clock := streamtesting.NewManualClock()
+//	source := streamtesting.NewSource(clock,
+//		streamtesting.Emit(0, `{"data":{"counter":1}}`),
+//		streamtesting.Emit(50*time.Millisecond, `{"data":{"counter":2}}`),
+//		streamtesting.Error(100*time.Millisecond, "upstream failed"),
+//	)
+//
+// The events are scheduled relative to the start of each subscription.
+// With a ManualClock the events are emitted once the clock is advanced to their time.
+package streamtesting
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+type eventKind int
+
+const (
+	eventKindData eventKind = iota
+	eventKindError
+	eventKindComplete
+)
+
+// Event is an event of the timeline of a Source
+type Event struct {
+	// At is the time of the event relative to the start of the subscription
+	At   time.Duration
+	kind eventKind
+	data []byte
+}
+
+// Emit emits the data as the next message of the subscription
+func Emit(at time.Duration, data string) Event {
+	return Event{At: at, kind: eventKindData, data: []byte(data)}
+}
+
+// Error emits a message with a graphql error and completes the subscription
+func Error(at time.Duration, message string) Event {
+	errorMessage, _ := json.Marshal(message)
+	return Event{At: at, kind: eventKindError, data: []byte(`{"errors":[{"message":` + string(errorMessage) + `}]}`)}
+}
+
+// Complete completes the subscription
+func Complete(at time.Duration) Event {
+	return Event{At: at, kind: eventKindComplete}
+}
+
+// Source is a resolve.SubscriptionDataSource emitting the same timeline for every subscription
+// a timeline without an error or complete event keeps the subscription open until it's canceled
+type Source struct {
+	clock  Clock
+	events []Event
+}
+
+// NewSource creates a Source emitting the events in the order of their time, events of the same time keep their order
+func NewSource(clock Clock, events ...Event) *Source {
+	if clock == nil {
+		clock = RealClock{}
+	}
+	return &Source{
+		clock:  clock,
+		events: sortEvents(events),
+	}
+}
+
+func sortEvents(events []Event) []Event {
+	sorted := make([]Event, len(events))
+	copy(sorted, events)
+	// insertion sort is stable and timelines are short
+	for i := 1; i < len(sorted); i++ {
+		for j := i; j > 0 && sorted[j].At < sorted[j-1].At; j-- {
+			sorted[j], sorted[j-1] = sorted[j-1], sorted[j]
+		}
+	}
+	return sorted
+}
+
+func (s *Source) UniqueRequestID(_ *resolve.Context, input []byte, xxh *xxhash.Digest) (err error) {
+	_, err = xxh.WriteString("streamtesting")
+	if err != nil {
+		return
+	}
+	_, err = xxh.Write(input)
+	return
+}
+
+func (s *Source) Start(ctx *resolve.Context, _ []byte, updater resolve.SubscriptionUpdater) error {
+	start := s.clock.Now()
+	done := ctx.Context().Done()
+
+	go func() {
+		defer updater.Done()
+
+		for _, event := range s.events {
+			select {
+			case <-done:
+				return
+			case <-s.clock.Until(start.Add(event.At)):
+			}
+
+			switch event.kind {
+			case eventKindData:
+				updater.Update(event.data)
+			case eventKindError:
+				updater.Update(event.data)
+				return
+			case eventKindComplete:
+				return
+			}
+		}
+
+		// the timeline is over but the subscription is still open
+		<-done
+	}()
+	return nil
+}
+
+// Interface Guards
+var (
+	_ resolve.SubscriptionDataSource = (*Source)(nil)
+)
@@ -0,0 +1,145 @@
+package streamtesting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+type recordingUpdater struct {
+	updates chan string
+	done    chan struct{}
+}
+
+func newRecordingUpdater() *recordingUpdater {
+	return &recordingUpdater{
+		updates: make(chan string, 16),
+		done:    make(chan struct{}),
+	}
+}
+
+func (r *recordingUpdater) Update(data []byte) {
+	r.updates <- string(data)
+}
+
+func (r *recordingUpdater) Done() {
+	close(r.done)
+}
+
+func (r *recordingUpdater) next(t *testing.T) string {
+	t.Helper()
+	select {
+	case update := <-r.updates:
+		return update
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for an update")
+		return ""
+	}
+}
+
+func (r *recordingUpdater) awaitDone(t *testing.T) {
+	t.Helper()
+	select {
+	case <-r.done:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for done")
+	}
+}
+
+func (r *recordingUpdater) assertNoUpdate(t *testing.T) {
+	t.Helper()
+	select {
+	case update := <-r.updates:
+		t.Fatalf("unexpected update: %s", update)
+	case <-r.done:
+		t.Fatal("unexpected done")
+	default:
+	}
+}
+
+func TestSource(t *testing.T) {
+	start := func(t *testing.T, source *Source) (*recordingUpdater, context.CancelFunc) {
+		t.Helper()
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		updater := newRecordingUpdater()
+		require.NoError(t, source.Start(resolve.NewContext(ctx), nil, updater))
+		return updater, cancel
+	}
+
+	t.Run("emits the events when the clock reaches them", func(t *testing.T) {
+		clock := NewManualClock()
+		source := NewSource(clock,
+			Emit(50*time.Millisecond, `{"data":{"counter":2}}`),
+			Emit(0, `{"data":{"counter":1}}`),
+			Error(100*time.Millisecond, "upstream failed"),
+		)
+		updater, _ := start(t, source)
+
+		assert.Equal(t, `{"data":{"counter":1}}`, updater.next(t))
+		updater.assertNoUpdate(t)
+
+		clock.Advance(50 * time.Millisecond)
+		assert.Equal(t, `{"data":{"counter":2}}`, updater.next(t))
+		updater.assertNoUpdate(t)
+
+		clock.Advance(50 * time.Millisecond)
+		assert.Equal(t, `{"errors":[{"message":"upstream failed"}]}`, updater.next(t))
+		updater.awaitDone(t)
+	})
+
+	t.Run("emits all events scheduled up to the advanced time", func(t *testing.T) {
+		clock := NewManualClock()
+		source := NewSource(clock,
+			Emit(10*time.Millisecond, `1`),
+			Emit(20*time.Millisecond, `2`),
+			Complete(30*time.Millisecond),
+		)
+		updater, _ := start(t, source)
+		updater.assertNoUpdate(t)
+
+		clock.Advance(time.Second)
+		assert.Equal(t, `1`, updater.next(t))
+		assert.Equal(t, `2`, updater.next(t))
+		updater.awaitDone(t)
+	})
+
+	t.Run("schedules the events relative to the start of the subscription", func(t *testing.T) {
+		clock := NewManualClock()
+		source := NewSource(clock, Emit(10*time.Millisecond, `1`))
+
+		clock.Advance(time.Second)
+		updater, _ := start(t, source)
+		updater.assertNoUpdate(t)
+
+		clock.Advance(10 * time.Millisecond)
+		assert.Equal(t, `1`, updater.next(t))
+	})
+
+	t.Run("keeps the subscription open until it's canceled", func(t *testing.T) {
+		clock := NewManualClock()
+		source := NewSource(clock, Emit(0, `1`))
+		updater, cancel := start(t, source)
+
+		assert.Equal(t, `1`, updater.next(t))
+		clock.Advance(time.Hour)
+		updater.assertNoUpdate(t)
+
+		cancel()
+		updater.awaitDone(t)
+	})
+
+	t.Run("real clock", func(t *testing.T) {
+		source := NewSource(nil, Emit(time.Millisecond, `1`), Complete(2*time.Millisecond))
+		updater, _ := start(t, source)
+
+		assert.Equal(t, `1`, updater.next(t))
+		updater.awaitDone(t)
+	})
+}